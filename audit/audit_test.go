@@ -0,0 +1,164 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/audit"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestAuditor(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.13:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.14:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	// the responses have the TEID of the client not in any session.
+	cliConn.DisableValidation()
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		return c.RespondTo(addr, msg, messages.NewCreateSessionResponse(
+			0x11111111, 0,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0x22222222, "127.0.0.14", ""),
+		))
+	})
+
+	path := filepath.Join(t.TempDir(), "audit.json")
+	fileSink, err := audit.NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recCh := make(chan *audit.Record, 10)
+	a := audit.NewAuditor(fileSink, audit.SinkFunc(func(r *audit.Record) error {
+		recCh <- r
+		return nil
+	}))
+	a.Timeout = 200 * time.Millisecond
+	cliConn.SetMessageObserver(a.Observe)
+
+	wait := func(t *testing.T) *audit.Record {
+		t.Helper()
+		select {
+		case r := <-recCh:
+			return r
+		case <-time.After(time.Second):
+			t.Fatal("no record written")
+			return nil
+		}
+	}
+
+	t.Run("echo", func(t *testing.T) {
+		if err := cliConn.EchoRequest(srvAddr); err != nil {
+			t.Fatal(err)
+		}
+		r := wait(t)
+		if r.Request != messages.MessageType(messages.MsgTypeEchoRequest) || r.Response != messages.MessageType(messages.MsgTypeEchoResponse) {
+			t.Errorf("unexpected messages: %s, %s", r.Request, r.Response)
+		}
+		if r.Direction != audit.DirectionOutbound || r.Peer != srvAddr.String() || r.Local != cliAddr.String() {
+			t.Errorf("unexpected addresses: %+v", r)
+		}
+	})
+
+	t.Run("create-session", func(t *testing.T) {
+		csReq := messages.NewCreateSessionRequest(
+			0, 100,
+			ies.NewIMSI("123451234567890"),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.13", ""),
+		)
+		if err := cliConn.SendMessageTo(csReq, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+
+		r := wait(t)
+		if r.Latency <= 0 || r.Timeout {
+			t.Errorf("unexpected latency: %d, timeout: %v", r.Latency, r.Timeout)
+		}
+		r.Time, r.Latency = time.Time{}, 0
+		want := &audit.Record{
+			Local:        cliAddr.String(),
+			Peer:         srvAddr.String(),
+			Direction:    audit.DirectionOutbound,
+			Request:      messages.MessageType(messages.MsgTypeCreateSessionRequest),
+			Response:     messages.MessageType(messages.MsgTypeCreateSessionResponse),
+			Sequence:     100,
+			Cause:        v2.Cause(v2.CauseRequestAccepted),
+			IMSI:         "123451234567890",
+			ResponseTEID: 0x11111111,
+			FTEIDs: []audit.FTEID{
+				{Interface: v2.IFType(v2.IFTypeS11MMEGTPC), TEID: 0x11111111, IP: "127.0.0.13"},
+				{Interface: v2.IFType(v2.IFTypeS11S4SGWGTPC), TEID: 0x22222222, IP: "127.0.0.14"},
+			},
+		}
+		if diff := cmp.Diff(r, want); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		// no handler for Delete Session Request on the server.
+		dsReq := messages.NewDeleteSessionRequest(0x22222222, 101)
+		for range 2 {
+			if err := cliConn.SendMessageTo(dsReq, srvAddr); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		r := wait(t)
+		if r.Request != messages.MessageType(messages.MsgTypeDeleteSessionRequest) || !r.Timeout || r.Retransmissions != 1 {
+			t.Errorf("unexpected record: %+v", r)
+		}
+	})
+
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fileSink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var n int
+	for sc := bufio.NewScanner(f); sc.Scan(); n++ {
+		r := &audit.Record{}
+		if err := json.Unmarshal(sc.Bytes(), r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n != 3 {
+		t.Errorf("got %d records in file, want 3", n)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package audit
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultTimeout is the default Timeout of Auditor.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultQueueSize is the number of the records queued to be written to the Sinks,
+// over which the records are dropped.
+const DefaultQueueSize = 1024
+
+// Auditor records the transactions observed on v2.Conn to the Sinks.
+type Auditor struct {
+	sinks []Sink
+
+	mu      sync.Mutex
+	pending map[txKey]*transaction
+	closed  bool
+	recCh   chan *Record
+	doneCh  chan struct{}
+	dropped atomic.Uint64
+
+	// Timeout is how long to wait for the response to the request before writing the
+	// record with Timeout. This should be set before the Auditor starts observing.
+	Timeout time.Duration
+
+	// ErrorHandler is called with the errors in writing the records to the Sinks,
+	// which are ignored if nil. This should be set before the Auditor starts observing.
+	ErrorHandler func(err error)
+}
+
+// txKey identifies the transaction; the response comes from/to the peer of the
+// request with the same sequence number in the other direction.
+type txKey struct {
+	peer      string
+	seq       uint32
+	direction Direction
+}
+
+type transaction struct {
+	record *Record
+	sentAt time.Time
+	timer  *time.Timer
+}
+
+// NewAuditor creates a new Auditor writing the records to sinks, and starts writing
+// in the background until Close is called.
+func NewAuditor(sinks ...Sink) *Auditor {
+	a := &Auditor{
+		sinks:   sinks,
+		pending: map[txKey]*transaction{},
+		recCh:   make(chan *Record, DefaultQueueSize),
+		doneCh:  make(chan struct{}),
+		Timeout: DefaultTimeout,
+	}
+	go a.write()
+	return a
+}
+
+func (a *Auditor) write() {
+	defer close(a.doneCh)
+	for r := range a.recCh {
+		for _, s := range a.sinks {
+			if err := s.Write(r); err != nil && a.ErrorHandler != nil {
+				a.ErrorHandler(err)
+			}
+		}
+	}
+}
+
+// Observe is the v2.MessageObserverFunc to be set to the Conn with SetMessageObserver.
+func (a *Auditor) Observe(c *v2.Conn, direction uint8, peer net.Addr, msg messages.Message) {
+	now := time.Now()
+	dir := DirectionOutbound
+	if direction == v2.DirectionInbound {
+		dir = DirectionInbound
+	}
+	ct := parse(msg)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+
+	// the response to the request in the other direction.
+	reqDir := DirectionInbound
+	if dir == DirectionInbound {
+		reqDir = DirectionOutbound
+	}
+	if tx, ok := a.pending[txKey{peer.String(), msg.Sequence(), reqDir}]; ok {
+		delete(a.pending, txKey{peer.String(), msg.Sequence(), reqDir})
+		tx.timer.Stop()
+
+		r := tx.record
+		r.Response = messages.MessageType(msg.MessageType())
+		r.ResponseTEID = msg.TEID()
+		r.Cause = ct.cause
+		r.Latency = now.Sub(tx.sentAt).Microseconds()
+		r.FTEIDs = append(r.FTEIDs, ct.fteids...)
+		if r.IMSI == "" {
+			r.IMSI = lookupIMSI(c, ct.imsi, msg.TEID())
+		}
+		a.emit(r)
+
+		// the triggered message may also be the request, e.g., Context Response and
+		// Update Bearer Request triggered by Modify Bearer Command.
+		if !messages.ExpectsResponse(msg.MessageType()) {
+			return
+		}
+	}
+
+	key := txKey{peer.String(), msg.Sequence(), dir}
+	if tx, ok := a.pending[key]; ok && tx.record.Request == messages.MessageType(msg.MessageType()) {
+		tx.record.Retransmissions++
+		return
+	}
+
+	r := &Record{
+		Time:        now,
+		Local:       c.LocalAddr().String(),
+		Peer:        peer.String(),
+		Direction:   dir,
+		Request:     messages.MessageType(msg.MessageType()),
+		Sequence:    msg.Sequence(),
+		IMSI:        lookupIMSI(c, ct.imsi, msg.TEID()),
+		RequestTEID: msg.TEID(),
+		FTEIDs:      ct.fteids,
+	}
+	if !messages.ExpectsResponse(msg.MessageType()) {
+		r.Cause = ct.cause
+		a.emit(r)
+		return
+	}
+
+	tx := &transaction{record: r, sentAt: now}
+	tx.timer = time.AfterFunc(a.Timeout, func() { a.expire(key, tx) })
+	a.pending[key] = tx
+}
+
+func (a *Auditor) expire(key txKey, tx *transaction) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.pending[key] != tx {
+		return
+	}
+	delete(a.pending, key)
+	tx.record.Timeout = true
+	a.emit(tx.record)
+}
+
+// emit queues the record to be written. The caller must hold mu.
+func (a *Auditor) emit(r *Record) {
+	if a.closed {
+		return
+	}
+	select {
+	case a.recCh <- r:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of the records dropped as the queue is full.
+func (a *Auditor) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Close stops observing, and returns after the records queued are written.
+// The transactions waiting for the responses are discarded.
+func (a *Auditor) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	for key, tx := range a.pending {
+		tx.timer.Stop()
+		delete(a.pending, key)
+	}
+	close(a.recCh)
+	a.mu.Unlock()
+
+	<-a.doneCh
+	return nil
+}
+
+// lookupIMSI returns the IMSI in the message, or the one of the session with the TEID.
+func lookupIMSI(c *v2.Conn, imsi string, teid uint32) string {
+	if imsi != "" || teid == 0 {
+		return imsi
+	}
+	if sess, err := c.GetSessionByTEID(teid); err == nil {
+		return sess.IMSI
+	}
+	return ""
+}
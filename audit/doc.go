@@ -0,0 +1,24 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package audit provides the structured log of the GTPv2-C transactions on v2.Conn,
+// for the offline troubleshooting without the packet captures.
+//
+// Auditor observes the messages sent and received on the Conn, matches the requests
+// and the responses by the peer and the sequence number, and writes a Record with the
+// cause, the latency, the IMSI and the TEIDs of each transaction to the Sinks.
+//
+//	f, err := audit.NewFileSink("/var/log/sgw/audit.json")
+//	// ...
+//	a := audit.NewAuditor(f)
+//	defer a.Close()
+//	s11Conn.SetMessageObserver(a.Observe)
+//
+// The records are written in the background not to block the signaling. The Sinks
+// for the message brokers can be built with PublisherSink, e.g., with *nats.Conn of
+// github.com/nats-io/nats.go as it is, or with PublisherFunc wrapping the writer of
+// the Kafka client of your choice.
+//
+//	sink := audit.NewPublisherSink(nc, "gtp.audit")
+package audit
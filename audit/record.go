@@ -0,0 +1,125 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package audit
+
+import (
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Direction is the direction of the request in the transaction.
+type Direction string
+
+// Direction definitions.
+const (
+	// DirectionInbound is the transaction initiated by the peer.
+	DirectionInbound Direction = "inbound"
+	// DirectionOutbound is the transaction initiated by the Conn.
+	DirectionOutbound Direction = "outbound"
+)
+
+// Record is a transaction, which is a request and the response to it.
+type Record struct {
+	// Time is the time when the request is sent or received.
+	Time      time.Time `json:"time"`
+	Local     string    `json:"local"`
+	Peer      string    `json:"peer"`
+	Direction Direction `json:"direction"`
+
+	// Request is the message initiating the transaction, or the message which is not
+	// in any transaction, e.g., the response to the request not observed.
+	Request  messages.MessageType `json:"request"`
+	Response messages.MessageType `json:"response,omitempty"`
+	Sequence uint32               `json:"sequence"`
+
+	// Cause is the Cause in the response, or in the request if no response is expected.
+	Cause v2.Cause `json:"cause,omitempty"`
+	// Latency is the time from the request to the response in microseconds.
+	Latency int64 `json:"latency,omitempty"`
+	// Timeout is true if no response came within the Timeout of the Auditor.
+	Timeout bool `json:"timeout,omitempty"`
+	// Retransmissions is the number of the request retransmitted.
+	Retransmissions int `json:"retransmissions,omitempty"`
+
+	IMSI string `json:"imsi,omitempty"`
+	// RequestTEID and ResponseTEID are the TEIDs in the header of the messages.
+	RequestTEID  uint32 `json:"request_teid"`
+	ResponseTEID uint32 `json:"response_teid,omitempty"`
+	// FTEIDs are the F-TEIDs in the messages, including the ones in the grouped IEs.
+	FTEIDs []FTEID `json:"fteids,omitempty"`
+}
+
+// FTEID is an F-TEID in the messages of the transaction.
+type FTEID struct {
+	Interface v2.IFType `json:"interface"`
+	TEID      uint32    `json:"teid"`
+	IP        string    `json:"ip,omitempty"`
+}
+
+// contents is what is taken from the message for the Record.
+type contents struct {
+	imsi   string
+	cause  v2.Cause
+	fteids []FTEID
+}
+
+// parse takes the IMSI, the Cause and the F-TEIDs in msg.
+//
+// The message is serialized and decoded again to look into the IEs regardless
+// of the message type.
+func parse(msg messages.Message) *contents {
+	ct := &contents{}
+	b, err := messages.Serialize(msg)
+	if err != nil {
+		return ct
+	}
+	h, err := messages.DecodeHeader(b)
+	if err != nil {
+		return ct
+	}
+	decoded, err := ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		return ct
+	}
+
+	for _, ie := range decoded {
+		switch ie.Type {
+		case ies.IMSI:
+			if ct.imsi == "" {
+				ct.imsi = ie.IMSI()
+			}
+		case ies.Cause:
+			if ie.Instance() == 0 && ct.cause == 0 {
+				ct.cause = v2.Cause(ie.Cause())
+			}
+		}
+	}
+	ct.fteids = collectFTEIDs(ct.fteids, decoded)
+	return ct
+}
+
+func collectFTEIDs(fteids []FTEID, ie []*ies.IE) []FTEID {
+	for _, i := range ie {
+		if i.IsGrouped() {
+			if children, err := i.Children(); err == nil {
+				fteids = collectFTEIDs(fteids, children)
+			}
+			continue
+		}
+		if i.Type != ies.FullyQualifiedTEID {
+			continue
+		}
+
+		fteids = append(fteids, FTEID{
+			Interface: v2.IFType(i.InterfaceType()),
+			TEID:      i.TEID(),
+			IP:        i.IPAddress(),
+		})
+	}
+	return fteids
+}
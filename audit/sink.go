@@ -0,0 +1,100 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Sink writes the records of the transactions.
+//
+// The method is called by Auditor in a single goroutine, and is not called
+// concurrently by the same Auditor.
+type Sink interface {
+	Write(r *Record) error
+}
+
+// SinkFunc is an adapter to use the ordinary function as a Sink.
+type SinkFunc func(r *Record) error
+
+// Write calls f(r).
+func (f SinkFunc) Write(r *Record) error {
+	return f(r)
+}
+
+// FileSink is a Sink that appends the records to a file in JSON, one record per line.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileSink creates a new FileSink with the file at path, which is created if it
+// does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Write appends the record to the file.
+func (s *FileSink) Write(r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.f.Write(append(b, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// Publisher publishes the data to the subject(or the topic) of the message broker.
+//
+// *nats.Conn of github.com/nats-io/nats.go satisfies this as it is.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// PublisherFunc is an adapter to use the ordinary function as a Publisher, e.g., to
+// write the records to Kafka with the client of your choice.
+type PublisherFunc func(subject string, data []byte) error
+
+// Publish calls f(subject, data).
+func (f PublisherFunc) Publish(subject string, data []byte) error {
+	return f(subject, data)
+}
+
+// PublisherSink is a Sink that publishes the records in JSON to the subject with
+// the Publisher.
+type PublisherSink struct {
+	pub     Publisher
+	subject string
+}
+
+// NewPublisherSink creates a new PublisherSink publishing to subject with pub.
+func NewPublisherSink(pub Publisher, subject string) *PublisherSink {
+	return &PublisherSink{pub: pub, subject: subject}
+}
+
+// Write publishes the record.
+func (s *PublisherSink) Write(r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.pub.Publish(s.subject, b)
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package charging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+var (
+	// ErrAlreadyTracked indicates that the bearer is already tracked.
+	ErrAlreadyTracked = errors.New("bearer already tracked")
+
+	// ErrNotTracked indicates that the bearer is not tracked.
+	ErrNotTracked = errors.New("bearer not tracked")
+)
+
+// CloseCause is the cause for closing a record.
+//
+// The values are the same as causeForRecClosing in 3GPP TS 32.298.
+type CloseCause uint8
+
+// CloseCause definitions.
+const (
+	CauseNormalRelease   CloseCause = 0
+	CauseAbnormalRelease CloseCause = 4
+	CauseVolumeLimit     CloseCause = 16
+	CauseTimeLimit       CloseCause = 17
+)
+
+// String returns the name of the CloseCause.
+func (c CloseCause) String() string {
+	switch c {
+	case CauseNormalRelease:
+		return "normal-release"
+	case CauseAbnormalRelease:
+		return "abnormal-release"
+	case CauseVolumeLimit:
+		return "volume-limit"
+	case CauseTimeLimit:
+		return "time-limit"
+	default:
+		return fmt.Sprintf("CloseCause(%d)", uint8(c))
+	}
+}
+
+// MarshalText returns the name of the CloseCause, to be readable in the records.
+func (c CloseCause) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Bearer is the bearer to be charged.
+type Bearer struct {
+	IMSI       string
+	MSISDN     string
+	APN        string
+	EBI        uint8
+	ChargingID uint32
+	// ServedAddress is the IP address of the UE.
+	ServedAddress string
+}
+
+// Record is a charging data record of a bearer.
+type Record struct {
+	IMSI          string `json:"imsi"`
+	MSISDN        string `json:"msisdn,omitempty"`
+	APN           string `json:"apn"`
+	EBI           uint8  `json:"ebi"`
+	ChargingID    uint32 `json:"charging_id"`
+	ServedAddress string `json:"served_address,omitempty"`
+
+	// SequenceNumber is the number of the record of the bearer, starting from 1.
+	SequenceNumber int       `json:"sequence_number"`
+	OpeningTime    time.Time `json:"opening_time"`
+	ClosingTime    time.Time `json:"closing_time"`
+	// Duration is the duration of the record in seconds.
+	Duration uint32 `json:"duration"`
+
+	UplinkPackets   uint64 `json:"uplink_packets"`
+	UplinkVolume    uint64 `json:"uplink_volume"`
+	DownlinkPackets uint64 `json:"downlink_packets"`
+	DownlinkVolume  uint64 `json:"downlink_volume"`
+
+	Cause CloseCause `json:"cause"`
+}
+
+// Triggers are the conditions to close the partial records of the bearers,
+// evaluated in Check. Zero disables each trigger.
+type Triggers struct {
+	// TimeLimit closes the record when it has been opened for the duration.
+	TimeLimit time.Duration
+	// VolumeLimit closes the record when the sum of the uplink and downlink volume
+	// in bytes reaches the value.
+	VolumeLimit uint64
+}
+
+type bearerKey struct {
+	imsi string
+	ebi  uint8
+}
+
+// tracked is the bearer being tracked, with the record currently opened.
+type tracked struct {
+	bearer  Bearer
+	counter *v1.Counter
+
+	seq    int
+	opened time.Time
+	// base is the counters when the current record is opened.
+	base v1.CounterStats
+}
+
+// Tracker tracks the volume and the duration of the bearers, and writes the records
+// with the Writer.
+type Tracker struct {
+	w    Writer
+	trig Triggers
+
+	mu      sync.Mutex
+	bearers map[bearerKey]*tracked
+}
+
+// NewTracker creates a new Tracker writing the records with w.
+func NewTracker(w Writer, trig Triggers) *Tracker {
+	return &Tracker{w: w, trig: trig, bearers: map[bearerKey]*tracked{}}
+}
+
+// Start starts tracking the bearer with the Counter, which should be set to the
+// UPlaneConn for the TEIDs of the bearer. The first record is opened now, counting
+// the volume from the current value of the Counter.
+func (t *Tracker) Start(b *Bearer, c *v1.Counter) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := bearerKey{b.IMSI, b.EBI}
+	if _, ok := t.bearers[key]; ok {
+		return ErrAlreadyTracked
+	}
+	t.bearers[key] = &tracked{
+		bearer:  *b,
+		counter: c,
+		opened:  time.Now(),
+		base:    c.Stats(),
+	}
+	return nil
+}
+
+// Stop stops tracking the bearer and writes the last record with CauseNormalRelease.
+func (t *Tracker) Stop(imsi string, ebi uint8) error {
+	return t.StopWithCause(imsi, ebi, CauseNormalRelease)
+}
+
+// StopWithCause stops tracking the bearer and writes the last record with the cause
+// given, e.g., CauseAbnormalRelease for the bearer released by the path failure.
+func (t *Tracker) StopWithCause(imsi string, ebi uint8, cause CloseCause) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := bearerKey{imsi, ebi}
+	tb, ok := t.bearers[key]
+	if !ok {
+		return ErrNotTracked
+	}
+	delete(t.bearers, key)
+	return t.w.Write(tb.close(time.Now(), cause))
+}
+
+// Check evaluates the triggers for all the bearers, and writes the partial records
+// of the bearers hitting any of them. It should be called periodically, at the
+// interval short enough for the TimeLimit.
+//
+// The record is written for all the bearers even if the Writer fails, and the first
+// error is returned.
+func (t *Tracker) Check() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var firstErr error
+	now := time.Now()
+	for _, tb := range t.bearers {
+		cause, ok := tb.check(now, t.trig)
+		if !ok {
+			continue
+		}
+		if err := t.w.Write(tb.close(now, cause)); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Len returns the number of the bearers being tracked.
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.bearers)
+}
+
+// check returns the cause if the current record hits any of the triggers.
+func (tb *tracked) check(now time.Time, trig Triggers) (CloseCause, bool) {
+	if trig.VolumeLimit > 0 {
+		s := tb.counter.Stats()
+		if s.UplinkBytes-tb.base.UplinkBytes+s.DownlinkBytes-tb.base.DownlinkBytes >= trig.VolumeLimit {
+			return CauseVolumeLimit, true
+		}
+	}
+	if trig.TimeLimit > 0 && now.Sub(tb.opened) >= trig.TimeLimit {
+		return CauseTimeLimit, true
+	}
+	return 0, false
+}
+
+// close closes the current record with the cause and opens the next one.
+func (tb *tracked) close(now time.Time, cause CloseCause) *Record {
+	s := tb.counter.Stats()
+	tb.seq++
+	r := &Record{
+		IMSI:            tb.bearer.IMSI,
+		MSISDN:          tb.bearer.MSISDN,
+		APN:             tb.bearer.APN,
+		EBI:             tb.bearer.EBI,
+		ChargingID:      tb.bearer.ChargingID,
+		ServedAddress:   tb.bearer.ServedAddress,
+		SequenceNumber:  tb.seq,
+		OpeningTime:     tb.opened,
+		ClosingTime:     now,
+		Duration:        uint32(now.Sub(tb.opened) / time.Second),
+		UplinkPackets:   s.UplinkPackets - tb.base.UplinkPackets,
+		UplinkVolume:    s.UplinkBytes - tb.base.UplinkBytes,
+		DownlinkPackets: s.DownlinkPackets - tb.base.DownlinkPackets,
+		DownlinkVolume:  s.DownlinkBytes - tb.base.DownlinkBytes,
+		Cause:           cause,
+	}
+
+	tb.opened, tb.base = now, s
+	return r
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package charging_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/wmnsk/go-gtp/charging"
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+var ignoreTimes = cmpopts.IgnoreFields(charging.Record{}, "OpeningTime", "ClosingTime", "Duration")
+
+// recorder is a Writer that keeps the records in memory.
+type recorder struct {
+	records []*charging.Record
+}
+
+func (r *recorder) Write(rec *charging.Record) error {
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func TestTracker(t *testing.T) {
+	w := &recorder{}
+	tr := charging.NewTracker(w, charging.Triggers{VolumeLimit: 1000})
+
+	c := v1.NewCounter()
+	// the traffic before starting is not charged.
+	c.Add(v1.Uplink, 5000)
+
+	b := &charging.Bearer{IMSI: "001010000000001", APN: "internet", EBI: 5, ChargingID: 1}
+	if err := tr.Start(b, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Start(b, c); !errors.Is(err, charging.ErrAlreadyTracked) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c.Add(v1.Uplink, 300)
+	c.Add(v1.Downlink, 500)
+	if err := tr.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.records) != 0 {
+		t.Fatalf("record closed below the limit: %v", w.records)
+	}
+
+	c.Add(v1.Downlink, 500)
+	if err := tr.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Add(v1.Uplink, 100)
+	if err := tr.Stop(b.IMSI, b.EBI); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Stop(b.IMSI, b.EBI); !errors.Is(err, charging.ErrNotTracked) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if tr.Len() != 0 {
+		t.Errorf("bearer still tracked")
+	}
+
+	want := []*charging.Record{
+		{
+			IMSI: b.IMSI, APN: b.APN, EBI: 5, ChargingID: 1, SequenceNumber: 1,
+			UplinkPackets: 1, UplinkVolume: 300, DownlinkPackets: 2, DownlinkVolume: 1000,
+			Cause: charging.CauseVolumeLimit,
+		}, {
+			IMSI: b.IMSI, APN: b.APN, EBI: 5, ChargingID: 1, SequenceNumber: 2,
+			UplinkPackets: 1, UplinkVolume: 100,
+			Cause: charging.CauseNormalRelease,
+		},
+	}
+	if diff := cmp.Diff(w.records, want, ignoreTimes); diff != "" {
+		t.Error(diff)
+	}
+	if !w.records[0].ClosingTime.Equal(w.records[1].OpeningTime) {
+		t.Error("next record is not opened when the previous one is closed")
+	}
+}
+
+func TestTrackerTimeLimit(t *testing.T) {
+	w := &recorder{}
+	tr := charging.NewTracker(w, charging.Triggers{TimeLimit: 50 * time.Millisecond})
+
+	c := v1.NewCounter()
+	if err := tr.Start(&charging.Bearer{IMSI: "001010000000001", EBI: 5}, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tr.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.records) != 0 {
+		t.Fatalf("record closed before the limit: %v", w.records)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := tr.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.records) != 1 {
+		t.Fatalf("unexpected number of records: %d", len(w.records))
+	}
+	if got := w.records[0].Cause; got != charging.CauseTimeLimit {
+		t.Errorf("unexpected cause: %s", got)
+	}
+	if d := w.records[0].ClosingTime.Sub(w.records[0].OpeningTime); d < 50*time.Millisecond {
+		t.Errorf("unexpected duration: %s", d)
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cdr.json")
+	w, err := charging.NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []*charging.Record{
+		{IMSI: "001010000000001", EBI: 5, SequenceNumber: 1, UplinkVolume: 100, Cause: charging.CauseTimeLimit},
+		{IMSI: "001010000000001", EBI: 5, SequenceNumber: 2, DownlinkVolume: 200, Cause: charging.CauseNormalRelease},
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var causes []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		var m map[string]any
+		if err := json.Unmarshal(s.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		causes = append(causes, m["cause"].(string))
+	}
+	if diff := cmp.Diff(causes, []string{"time-limit", "normal-release"}); diff != "" {
+		t.Error(diff)
+	}
+}
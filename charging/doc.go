@@ -0,0 +1,27 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package charging provides the generation of the charging data records(CDRs) of
+// the bearers, fed by the counters of the user-plane, typically by P-GW or S-GW.
+//
+// Each bearer is tracked with the v1.Counter set to the UPlaneConn for its TEIDs,
+// and a record of the volume and the duration is written with the Writer when the
+// bearer is stopped, or when any of the triggers is hit in Check.
+//
+//	w, err := charging.NewFileWriter("/var/log/pgw/cdr.json")
+//	// ...
+//	t := charging.NewTracker(w, charging.Triggers{TimeLimit: time.Hour, VolumeLimit: 1 << 30})
+//
+//	c := v1.NewCounter()
+//	uConn.SetCounter(teidIn, v1.Uplink, c)
+//	uConn.SetSentCounter(teidOut, v1.Downlink, c)
+//	err = t.Start(&charging.Bearer{IMSI: imsi, APN: apn, EBI: 5, ChargingID: id}, c)
+//	// ...
+//	err = t.Check() // periodically
+//	// ...
+//	err = t.Stop(imsi, 5)
+//
+// The records closed by the triggers are the partial records, numbered with the
+// SequenceNumber for each bearer, and the last one is closed with CauseNormalRelease.
+package charging
@@ -0,0 +1,65 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package charging
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Writer writes the records closed by Tracker.
+//
+// The method is called by Tracker with its lock held, and is not called concurrently
+// by the same Tracker. Note that GTP' to send the records to CGF is not implemented
+// in go-gtp; implement Writer with your own transport to do so.
+type Writer interface {
+	Write(r *Record) error
+}
+
+// WriterFunc is an adapter to use the ordinary function as a Writer.
+type WriterFunc func(r *Record) error
+
+// Write calls f(r).
+func (f WriterFunc) Write(r *Record) error {
+	return f(r)
+}
+
+// FileWriter is a Writer that appends the records to a file in JSON, one record
+// per line.
+type FileWriter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileWriter creates a new FileWriter with the file at path, which is created
+// if it does not exist.
+func NewFileWriter(path string) (*FileWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileWriter{f: f}, nil
+}
+
+// Write appends the record to the file.
+func (w *FileWriter) Write(r *Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.f.Write(append(b, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
@@ -0,0 +1,219 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command enbsim attaches UEs through S-GW as MME and eNB, and generates the
+// user-plane traffic over the S1-U tunnels established, to validate the relay
+// of S-GW and P-GW like iperf over GTP.
+//
+// The packets sent are ICMP Echo Requests from the IP address of each UE
+// allocated by the P-GW to -target. With -bidir, the Echo Replies coming back
+// through the tunnels are counted, and the loss and the round-trip time are
+// printed for each UE and in total.
+//
+//	enbsim -s11 127.0.0.111:2123 -sgw 127.0.0.112:2123 -pgw 127.0.0.52 \
+//		-s1u 127.0.0.1:2152 -ues 10 -rate 100 -count 1000 -bidir
+//
+// The replies are given by the host of -target on SGi, or by enbsim.Endpoint
+// with SetReflect enabled in place of the user plane of the P-GW.
+//
+// Interrupting with Ctrl-C stops the traffic and detaches the UEs before
+// printing the results.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/enbsim"
+	"github.com/wmnsk/go-gtp/mmesim"
+)
+
+// command-line flags.
+var (
+	s11      = flag.String("s11", "127.0.0.111:2123", "local IP:Port of MME on S11.")
+	sgw      = flag.String("sgw", "127.0.0.112:2123", "IP:Port of S-GW on S11.")
+	pgw      = flag.String("pgw", "127.0.0.52", "P-GW's IP on S5/S8 told to S-GW.")
+	s1u      = flag.String("s1u", "127.0.0.1:2152", "local IP:Port of eNB on S1-U.")
+	numUEs   = flag.Int("ues", 1, "number of UEs to attach and generate the traffic from.")
+	imsi     = flag.String("imsi", "001010000000001", "IMSI of the first UE. Incremented one by one for the others.")
+	mcc      = flag.String("mcc", "001", "MCC of the serving network.")
+	mnc      = flag.String("mnc", "01", "MNC of the serving network.")
+	apn      = flag.String("apn", "internet", "APN to attach to.")
+	size     = flag.Int("size", 64, "size of the IPv4 packets sent over the tunnels.")
+	rate     = flag.Float64("rate", 10, "number of packets sent per second by each UE.")
+	count    = flag.Int("count", 100, "number of packets sent by each UE. 0 to send for -duration.")
+	duration = flag.Duration("duration", 10*time.Second, "duration to send the packets, used with -count 0.")
+	target   = flag.String("target", "8.8.8.8", "destination IP of the packets sent by the UEs.")
+	bidir    = flag.Bool("bidir", false, "wait for the replies to measure the loss and the round-trip time.")
+	wait     = flag.Duration("wait", enbsim.DefaultWait, "time to wait for the replies after the last packet is sent.")
+)
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[enbsim] ")
+
+	cfg := &enbsim.TrafficConfig{
+		Size:          *size,
+		Rate:          *rate,
+		Count:         *count,
+		Duration:      *duration,
+		Target:        net.ParseIP(*target),
+		Bidirectional: *bidir,
+		Wait:          *wait,
+	}
+
+	imsis, err := sequentialDigits(*imsi, *numUEs)
+	if err != nil {
+		log.Fatalf("invalid IMSI: %s", err)
+	}
+
+	s11Addr, err := net.ResolveUDPAddr("udp", *s11)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sgwAddr, err := net.ResolveUDPAddr("udp", *sgw)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s1uAddr, err := net.ResolveUDPAddr("udp", *s1u)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	errCh := make(chan error, 16)
+	go func() {
+		for err := range errCh {
+			log.Printf("Warning: %s", err)
+		}
+	}()
+
+	enb, err := enbsim.ListenAndServe(s1uAddr, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer enb.Close()
+
+	mme, err := mmesim.Dial(&mmesim.Config{
+		LocalAddr: s11Addr,
+		SGWAddr:   sgwAddr,
+		ENBIP:     s1uAddr.IP.String(),
+		MCC:       *mcc,
+		MNC:       *mnc,
+		SelectPGW: func(string) (string, error) {
+			return *pgw, nil
+		},
+	}, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer mme.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	tunnels := make([]*enbsim.Tunnel, 0, len(imsis))
+	attached := make([]string, 0, len(imsis))
+	for _, imsi := range imsis {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sess, err := mme.AttachUE(imsi, *apn)
+		if err != nil {
+			log.Printf("Failed to attach %s: %s", imsi, err)
+			continue
+		}
+		attached = append(attached, imsi)
+
+		t, err := enbsim.TunnelFromBearer(sess.GetDefaultBearer())
+		if err != nil {
+			log.Printf("Failed to get tunnel of %s: %s", imsi, err)
+			continue
+		}
+		enb.AddTunnel(t)
+		tunnels = append(tunnels, t)
+		log.Printf("Attached %s: UE IP=%s, TEID=%#08x/%#08x", imsi, t.UEIP, t.IncomingTEID, t.OutgoingTEID)
+	}
+
+	reports := make([]*enbsim.Report, len(tunnels))
+	wg := sync.WaitGroup{}
+	for i, t := range tunnels {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := enb.Generate(ctx, t, cfg)
+			if err != nil && err != context.Canceled {
+				log.Printf("Failed to generate traffic from %s: %s", t.UEIP, err)
+			}
+			reports[i] = r
+		}()
+	}
+	wg.Wait()
+	stop()
+
+	for _, imsi := range attached {
+		if err := mme.Detach(imsi); err != nil {
+			log.Printf("Failed to detach %s: %s", imsi, err)
+		}
+	}
+
+	total := &enbsim.Report{}
+	for i, r := range reports {
+		if r == nil {
+			continue
+		}
+		fmt.Printf("%s: %s\n", tunnels[i].UEIP, r)
+		merge(total, r)
+	}
+	fmt.Printf("total: %s\n", total)
+}
+
+// merge adds the counters of r into total. The average RTT is weighted by the
+// number of the replies received.
+func merge(total, r *enbsim.Report) {
+	if r.Received > 0 {
+		sum := total.AvgRTT*time.Duration(total.Received) + r.AvgRTT*time.Duration(r.Received)
+		total.AvgRTT = sum / time.Duration(total.Received+r.Received)
+		if total.MinRTT == 0 || r.MinRTT < total.MinRTT {
+			total.MinRTT = r.MinRTT
+		}
+		if r.MaxRTT > total.MaxRTT {
+			total.MaxRTT = r.MaxRTT
+		}
+	}
+
+	total.Sent += r.Sent
+	total.Received += r.Received
+	total.Duplicated += r.Duplicated
+	total.SentBytes += r.SentBytes
+	if r.Elapsed > total.Elapsed {
+		total.Elapsed = r.Elapsed
+	}
+}
+
+// sequentialDigits returns n digit strings incremented one by one from first,
+// keeping the number of digits.
+func sequentialDigits(first string, n int) ([]string, error) {
+	start, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := make([]string, n)
+	for i := range digits {
+		digits[i] = fmt.Sprintf("%0*d", len(first), start+uint64(i))
+		if len(digits[i]) > len(first) {
+			return nil, fmt.Errorf("%s overflows with %d UEs", first, n)
+		}
+	}
+	return digits, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtpcraft sends a GTPv1/v2 message described in YAML or JSON to a GTP
+// node, and prints the response dissected IE by IE.
+//
+// The message is built as it is described, regardless of whether the IEs are
+// valid for the message type, which is handy for the negative testing of the
+// gateways with unusual IE combinations. The length in the header and the IEs
+// can also be overridden to send the malformed ones.
+//
+//	version: 2
+//	type: 32 # Create Session Request
+//	teid: 0
+//	sequence: 1
+//	ies:
+//	  - type: IMSI
+//	    digits: "001010000000001"
+//	  - type: BearerContext
+//	    ies:
+//	      - type: EPSBearerID
+//	        uint8: 5
+//	  - type: 255 # Private Extension
+//	    length: 100
+//	    hex: "0001deadbeef"
+//
+//	gtpcraft -raddr 127.0.0.112:2123 csreq.yml
+//
+// The IE type is given in number or the name, which is the one in Name() of ies.IE
+// in each version. The value is given with one of hex, string, digits(in TBCD), ip,
+// uint8, uint16 and uint32, or ies for the grouped IE in GTPv2. With raw instead of
+// the others, the message in hex is sent as it is.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// command-line flags.
+var (
+	laddr   = flag.String("laddr", "", "local IP:Port to send the message from.")
+	raddr   = flag.String("raddr", "127.0.0.112:2123", "IP:Port of the node to send the message to.")
+	timeout = flag.Duration("timeout", 3*time.Second, "time to wait for the response. 0 not to wait.")
+	dryRun  = flag.Bool("n", false, "print the message without sending it.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] FILE\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetPrefix("[gtpcraft] ")
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := loadSpec(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := spec.serialize()
+	if err != nil {
+		log.Fatalf("failed to build the message: %s", err)
+	}
+
+	fmt.Printf("Message to %s (%d bytes):\n", *raddr, len(b))
+	dissect(spec.Version, b)
+	if *dryRun {
+		return
+	}
+
+	ra, err := net.ResolveUDPAddr("udp", *raddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn, err := net.ListenPacket("udp", *laddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	sentAt := time.Now()
+	if _, err := conn.WriteTo(b, ra); err != nil {
+		log.Fatal(err)
+	}
+	if *timeout == 0 {
+		return
+	}
+
+	// print everything coming back until the one with the same sequence number,
+	// which is the response in most cases.
+	buf := make([]byte, 65535)
+	if err := conn.SetReadDeadline(sentAt.Add(*timeout)); err != nil {
+		log.Fatal(err)
+	}
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			log.Fatalf("no response within %s", *timeout)
+		}
+
+		fmt.Printf("\nMessage from %s (%d bytes, %s):\n", addr, n, time.Since(sentAt).Round(time.Microsecond))
+		dissect(spec.Version, buf[:n])
+		if sameSequence(spec.Version, b, buf[:n]) {
+			return
+		}
+	}
+}
+
+// loadSpec reads the message from the YAML or JSON file.
+func loadSpec(path string) (*messageSpec, error) {
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is also decoded as YAML.
+	dec := yaml.NewDecoder(bytes.NewReader(f))
+	dec.KnownFields(true)
+	spec := &messageSpec{Version: 2}
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+func dissect(version int, b []byte) {
+	if version == 1 {
+		fmt.Print(v1msg.DissectBytes(b))
+		return
+	}
+	fmt.Print(v2msg.DissectBytes(b))
+}
+
+// sameSequence reports whether the messages sent and received have the same
+// sequence number. It is false if any of them has a malformed header.
+func sameSequence(version int, sent, received []byte) bool {
+	if version == 1 {
+		s, err := v1msg.DecodeHeader(sent)
+		if err != nil {
+			return false
+		}
+		r, err := v1msg.DecodeHeader(received)
+		if err != nil {
+			return false
+		}
+		return s.SequenceNumber == r.SequenceNumber
+	}
+
+	s, err := v2msg.DecodeHeader(sent)
+	if err != nil {
+		return false
+	}
+	r, err := v2msg.DecodeHeader(received)
+	if err != nil {
+		return false
+	}
+	return s.SequenceNumber == r.SequenceNumber
+}
@@ -0,0 +1,259 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/utils"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// messageSpec is the message described in the YAML/JSON file.
+type messageSpec struct {
+	// Version is the GTP version, 1 or 2. 2 is used if omitted.
+	Version int `yaml:"version"`
+
+	// Type is the message type in number.
+	Type uint8 `yaml:"type"`
+
+	// TEID is the TEID in the header. In GTPv2, the header has no TEID if omitted.
+	TEID *uint32 `yaml:"teid"`
+
+	Sequence uint32 `yaml:"sequence"`
+
+	// Length overrides the length in the header, to send the malformed message.
+	Length *uint16 `yaml:"length"`
+
+	IEs []*ieSpec `yaml:"ies"`
+
+	// Raw is the whole message in hex, sent as it is. The other fields are ignored
+	// except Version, which is used to dissect the response.
+	Raw string `yaml:"raw"`
+}
+
+// ieSpec is an IE in messageSpec. The value is given with one of the fields
+// below Length, or with IEs for the grouped IE in GTPv2.
+type ieSpec struct {
+	// Type is the IE type in number, or the name of it, e.g., "IMSI".
+	Type string `yaml:"type"`
+
+	// Instance is the instance of the IE in GTPv2.
+	Instance uint8 `yaml:"instance"`
+
+	// Length overrides the length of the IE, to send the malformed IE.
+	Length *uint16 `yaml:"length"`
+
+	Hex    string  `yaml:"hex"`
+	String string  `yaml:"string"`
+	Digits string  `yaml:"digits"` // in TBCD, e.g., IMSI and MSISDN.
+	IP     string  `yaml:"ip"`
+	Uint8  *uint8  `yaml:"uint8"`
+	Uint16 *uint16 `yaml:"uint16"`
+	Uint32 *uint32 `yaml:"uint32"`
+
+	IEs []*ieSpec `yaml:"ies"`
+}
+
+// errNoValue indicates that the value of the IE is not given.
+var errNoValue = errors.New("no value given")
+
+// serialize returns the message described in the spec.
+func (m *messageSpec) serialize() ([]byte, error) {
+	if m.Raw != "" {
+		return hex.DecodeString(strings.ReplaceAll(m.Raw, " ", ""))
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	switch m.Version {
+	case 1:
+		b, err = m.serializeV1()
+	case 2:
+		b, err = m.serializeV2()
+	default:
+		return nil, fmt.Errorf("unsupported GTP version: %d", m.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Length != nil {
+		binary.BigEndian.PutUint16(b[2:4], *m.Length)
+	}
+	return b, nil
+}
+
+func (m *messageSpec) serializeV1() ([]byte, error) {
+	var ie []*v1ies.IE
+	for _, s := range m.IEs {
+		i, err := s.v1IE()
+		if err != nil {
+			return nil, err
+		}
+		ie = append(ie, i)
+	}
+
+	var teid uint32
+	if m.TEID != nil {
+		teid = *m.TEID
+	}
+	return v1msg.NewGeneric(m.Type, teid, uint16(m.Sequence), ie...).Serialize()
+}
+
+func (m *messageSpec) serializeV2() ([]byte, error) {
+	var ie []*v2ies.IE
+	for _, s := range m.IEs {
+		i, err := s.v2IE()
+		if err != nil {
+			return nil, err
+		}
+		ie = append(ie, i)
+	}
+
+	if m.TEID == nil {
+		msg := v2msg.NewGenericWithoutTEID(m.Type, 0, m.Sequence, ie...)
+		msg.SetLength()
+		return msg.Serialize()
+	}
+	return v2msg.NewGeneric(m.Type, *m.TEID, m.Sequence, ie...).Serialize()
+}
+
+func (s *ieSpec) v1IE() (*v1ies.IE, error) {
+	t, err := ieType(s.Type, v1Names)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.IEs) != 0 {
+		return nil, fmt.Errorf("IE %s: no grouped IE in GTPv1", s.Type)
+	}
+	p, err := s.payload()
+	if err != nil {
+		return nil, fmt.Errorf("IE %s: %w", s.Type, err)
+	}
+
+	i := v1ies.New(t, p)
+	if s.Length != nil {
+		i.Length = *s.Length
+	}
+	return i, nil
+}
+
+func (s *ieSpec) v2IE() (*v2ies.IE, error) {
+	t, err := ieType(s.Type, v2Names)
+	if err != nil {
+		return nil, err
+	}
+
+	var i *v2ies.IE
+	if len(s.IEs) != 0 {
+		// in the same way as the constructors of the grouped IEs in v2/ies.
+		i = v2ies.New(t, s.Instance, nil)
+		for _, c := range s.IEs {
+			child, err := c.v2IE()
+			if err != nil {
+				return nil, err
+			}
+			b, err := child.Serialize()
+			if err != nil {
+				return nil, err
+			}
+			i.ChildIEs = append(i.ChildIEs, child)
+			i.Payload = append(i.Payload, b...)
+		}
+		i.SetLength()
+	} else {
+		p, err := s.payload()
+		if err != nil {
+			return nil, fmt.Errorf("IE %s: %w", s.Type, err)
+		}
+
+		// decode it to keep the payload as it is even if the type is the grouped one.
+		b := make([]byte, 4+len(p))
+		b[0] = t
+		binary.BigEndian.PutUint16(b[1:3], uint16(len(p)))
+		b[3] = s.Instance & 0x0f
+		copy(b[4:], p)
+		i, err = v2ies.Decode(b)
+		if err != nil {
+			return nil, fmt.Errorf("IE %s: %w", s.Type, err)
+		}
+	}
+
+	if s.Length != nil {
+		i.Length = *s.Length
+	}
+	return i, nil
+}
+
+// payload returns the value given in the spec.
+func (s *ieSpec) payload() ([]byte, error) {
+	switch {
+	case s.Hex != "":
+		return hex.DecodeString(strings.ReplaceAll(s.Hex, " ", ""))
+	case s.String != "":
+		return []byte(s.String), nil
+	case s.Digits != "":
+		return utils.StrToSwappedBytes(s.Digits, "f")
+	case s.IP != "":
+		ip := net.ParseIP(s.IP)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP: %s", s.IP)
+		}
+		if v4 := ip.To4(); v4 != nil {
+			return v4, nil
+		}
+		return ip, nil
+	case s.Uint8 != nil:
+		return []byte{*s.Uint8}, nil
+	case s.Uint16 != nil:
+		return binary.BigEndian.AppendUint16(nil, *s.Uint16), nil
+	case s.Uint32 != nil:
+		return binary.BigEndian.AppendUint32(nil, *s.Uint32), nil
+	default:
+		return nil, errNoValue
+	}
+}
+
+// names of the IE types to look up by the name given in the spec, in lower case.
+var (
+	v1Names = ieNames(func(t uint8) string { return (&v1ies.IE{Type: t}).Name() })
+	v2Names = ieNames(func(t uint8) string { return (&v2ies.IE{Type: t}).Name() })
+)
+
+func ieNames(name func(t uint8) string) map[string]uint8 {
+	names := map[string]uint8{}
+	for t := 0; t <= 0xff; t++ {
+		if n := name(uint8(t)); n != "Unknown" {
+			names[strings.ToLower(n)] = uint8(t)
+		}
+	}
+	return names
+}
+
+// ieType returns the IE type given in number or the name.
+func ieType(s string, names map[string]uint8) (uint8, error) {
+	if s == "" {
+		return 0, errors.New("no IE type given")
+	}
+	if t, err := strconv.ParseUint(s, 0, 8); err == nil {
+		return uint8(t), nil
+	}
+	if t, ok := names[strings.ToLower(s)]; ok {
+		return t, nil
+	}
+	return 0, fmt.Errorf("unknown IE type: %s", s)
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	v0ies "github.com/wmnsk/go-gtp/v0/ies"
+	v0msg "github.com/wmnsk/go-gtp/v0/messages"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// msgTypeTPDU is the message type of T-PDU in GTPv0.
+const msgTypeTPDU = 0xff
+
+// dissect writes the GTP message in b to w, IE by IE.
+//
+// The message is decoded with messages.Decode of the corresponding version to
+// see if the library can handle it, and the IEs are printed regardless of the
+// result so that the problematic IE can be found.
+func dissect(w io.Writer, b []byte) {
+	if len(b) < 1 {
+		fmt.Fprintln(w, "  (empty payload)")
+		return
+	}
+
+	switch b[0] >> 5 {
+	case 0:
+		dissectV0(w, b)
+	case 1:
+		fmt.Fprint(w, v1msg.DissectBytes(b))
+	case 2:
+		fmt.Fprint(w, v2msg.DissectBytes(b))
+	default:
+		fmt.Fprintf(w, "  unknown GTP version: %d\n", b[0]>>5)
+	}
+}
+
+func dissectV0(w io.Writer, b []byte) {
+	h, err := v0msg.DecodeHeader(b)
+	if err != nil {
+		fmt.Fprintf(w, "GTPv0 (malformed header: %s)\n", err)
+		return
+	}
+
+	name := fmt.Sprintf("Unknown(%d)", h.Type)
+	msg, decErr := v0msg.Decode(b)
+	if decErr == nil {
+		name = msg.MessageTypeName()
+	}
+	fmt.Fprintf(w, "GTPv0 %s\n", name)
+	fmt.Fprintf(w, "  Header: Flags: %#02x, Type: %d, Length: %d, SequenceNumber: %d, FlowLabel: %#04x, TID: %#016x\n",
+		h.Flags, h.Type, h.Length, h.SequenceNumber, h.FlowLabel, h.TID)
+	if decErr != nil {
+		fmt.Fprintf(w, "  ! failed to decode message: %s\n", decErr)
+	}
+
+	if h.Type == msgTypeTPDU {
+		fmt.Fprintf(w, "  T-PDU: %d bytes\n", len(h.Payload))
+		return
+	}
+
+	ies, err := v0ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		fmt.Fprintf(w, "  ! failed to decode IEs: %s\n", err)
+	}
+	for _, ie := range ies {
+		fmt.Fprintf(w, "  %s(%d) length: %d, value: %s\n",
+			ieName(ieNamesV0, ie.Type), ie.Type, len(ie.Payload), safeValue(ie.Payload, func() string { return valueV0(ie) }))
+	}
+}
+
+func ieName(names map[uint8]string, t uint8) string {
+	if name, ok := names[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// safeValue returns the value given by fn, or the payload in hex if fn gives
+// nothing or panics with the malformed payload.
+func safeValue(payload []byte, fn func() string) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("%x (malformed: %v)", payload, r)
+		}
+	}()
+
+	if s = fn(); s == "" {
+		s = fmt.Sprintf("%x", payload)
+	}
+	return s
+}
+
+func valueV0(ie *v0ies.IE) string {
+	switch ie.Type {
+	case v0ies.Cause:
+		return fmt.Sprintf("%d", ie.Cause())
+	case v0ies.IMSI:
+		return ie.IMSI()
+	case v0ies.RouteingAreaIdentity:
+		return fmt.Sprintf("MCC: %s, MNC: %s, LAC: %#04x, RAC: %#02x", ie.MCC(), ie.MNC(), ie.LAC(), ie.RAC())
+	case v0ies.TemporaryLogicalLinkIdentity:
+		return fmt.Sprintf("%#08x", ie.TemporaryLogicalLinkIdentity())
+	case v0ies.PacketTMSI:
+		return fmt.Sprintf("%#08x", ie.PacketTMSI())
+	case v0ies.ReorderingRequired:
+		return fmt.Sprintf("%v", ie.ReorderingRequired())
+	case v0ies.PTMSISignature:
+		return fmt.Sprintf("%#06x", ie.PTMSISignature())
+	case v0ies.Recovery:
+		return fmt.Sprintf("%d", ie.Recovery())
+	case v0ies.SelectionMode:
+		return fmt.Sprintf("%d", ie.SelectionMode())
+	case v0ies.FlowLabelDataI:
+		return fmt.Sprintf("%#04x", ie.FlowLabelDataI())
+	case v0ies.FlowLabelSignalling:
+		return fmt.Sprintf("%#04x", ie.FlowLabelSignalling())
+	case v0ies.FlowLabelDataII:
+		return fmt.Sprintf("NSAPI: %d, FlowLabel: %#04x", ie.NSAPI(), ie.FlowLabelData())
+	case v0ies.MSNotReachableReason:
+		return fmt.Sprintf("%d", ie.MSNotReachableReason())
+	case v0ies.ChargingID:
+		return fmt.Sprintf("%#08x", ie.ChargingID())
+	case v0ies.EndUserAddress:
+		return fmt.Sprintf("PDPTypeOrganization: %d, PDPTypeNumber: %#02x, Address: %s",
+			ie.PDPTypeOrganization(), ie.PDPTypeNumber(), ie.IPAddress())
+	case v0ies.AccessPointName:
+		return ie.AccessPointName()
+	case v0ies.GSNAddress:
+		return ie.GSNAddress()
+	case v0ies.MSISDN:
+		return ie.MSISDN()
+	case v0ies.ChargingGatewayAddress:
+		return ie.ChargingGatewayAddress()
+	case v0ies.PrivateExtension:
+		return fmt.Sprintf("ExtensionIdentifier: %d, Value: %x", ie.ExtensionIdentifier(), ie.ExtensionValue())
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtpdump prints the GTPv0/v1/v2 messages captured on the network
+// interface or stored in the pcap/pcapng file, IE by IE.
+//
+// The messages are dissected with the decoders in go-gtp, which makes it
+// useful also to check if the library can handle the real traffic. Any error
+// in decoding is printed with the message.
+//
+//	gtpdump -r gtp.pcapng
+//	gtpdump -i eth0 -ports 2123
+//
+// Sniffing the interface is supported only on Linux, and requires the
+// privilege to open the raw socket.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+)
+
+// command-line flags.
+var (
+	file  = flag.String("r", "", "pcap or pcapng file to read packets from.")
+	ifc   = flag.String("i", "", "network interface to sniff packets on.")
+	ports = flag.String("ports", "2123,2152,3386", "comma-separated UDP ports to dissect as GTP.")
+	hex   = flag.Bool("x", false, "print the whole UDP payload in hex as well.")
+)
+
+// source is the source of packets, which is either a file or an interface.
+type source interface {
+	Next() (*gtppcap.Packet, error)
+}
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[gtpdump] ")
+
+	portList, err := parsePorts(*ports)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var src source
+	switch {
+	case *file != "" && *ifc != "":
+		log.Fatal("-r and -i cannot be specified at the same time")
+	case *file != "":
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		r, err := gtppcap.NewReader(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.SetPorts(portList...)
+		src = r
+	case *ifc != "":
+		s, err := openSniffer(*ifc, portList)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer s.Close()
+		src = s
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for {
+		pkt, err := src.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s %s -> %s ", pkt.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"), pkt.Src, pkt.Dst)
+		dissect(os.Stdout, pkt.Payload)
+		if *hex {
+			fmt.Printf("  Raw: %x\n", pkt.Payload)
+		}
+		fmt.Println()
+	}
+}
+
+func parsePorts(s string) ([]int, error) {
+	var ps []int
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 0xffff {
+			return nil, fmt.Errorf("invalid port: %s", p)
+		}
+		ps = append(ps, n)
+	}
+	return ps, nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import v0ies "github.com/wmnsk/go-gtp/v0/ies"
+
+// names of the GTPv0 IE types, which are defined as constants in v0/ies.
+//
+// The names of GTPv1 and GTPv2 IEs are given by Name() of ies.IE in each version.
+var ieNamesV0 = map[uint8]string{
+	v0ies.Cause:                        "Cause",
+	v0ies.IMSI:                         "IMSI",
+	v0ies.RouteingAreaIdentity:         "RouteingAreaIdentity",
+	v0ies.TemporaryLogicalLinkIdentity: "TemporaryLogicalLinkIdentity",
+	v0ies.PacketTMSI:                   "PacketTMSI",
+	v0ies.QualityOfServiceProfile:      "QualityOfServiceProfile",
+	v0ies.ReorderingRequired:           "ReorderingRequired",
+	v0ies.AuthenticationTriplet:        "AuthenticationTriplet",
+	v0ies.MAPCause:                     "MAPCause",
+	v0ies.PTMSISignature:               "PTMSISignature",
+	v0ies.MSValidated:                  "MSValidated",
+	v0ies.Recovery:                     "Recovery",
+	v0ies.SelectionMode:                "SelectionMode",
+	v0ies.FlowLabelDataI:               "FlowLabelDataI",
+	v0ies.FlowLabelSignalling:          "FlowLabelSignalling",
+	v0ies.FlowLabelDataII:              "FlowLabelDataII",
+	v0ies.MSNotReachableReason:         "MSNotReachableReason",
+	v0ies.ChargingID:                   "ChargingID",
+	v0ies.EndUserAddress:               "EndUserAddress",
+	v0ies.MMContext:                    "MMContext",
+	v0ies.PDPContext:                   "PDPContext",
+	v0ies.AccessPointName:              "AccessPointName",
+	v0ies.ProtocolConfigurationOptions: "ProtocolConfigurationOptions",
+	v0ies.GSNAddress:                   "GSNAddress",
+	v0ies.MSISDN:                       "MSISDN",
+	v0ies.ChargingGatewayAddress:       "ChargingGatewayAddress",
+	v0ies.PrivateExtension:             "PrivateExtension",
+}
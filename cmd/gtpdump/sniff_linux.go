@@ -0,0 +1,98 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+)
+
+// packetOutgoing is PACKET_OUTGOING in linux/if_packet.h.
+const packetOutgoing = 4
+
+// sniffer reads the frames on the interface with AF_PACKET socket.
+type sniffer struct {
+	fd       int
+	linkType uint16
+	loopback bool
+	ports    map[int]bool
+	buf      []byte
+}
+
+func openSniffer(name string, ports []int) (*sniffer, error) {
+	ifi, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	proto := htons(syscall.ETH_P_ALL)
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_RAW, int(proto))
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: ifi.Index}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+
+	s := &sniffer{
+		fd:       fd,
+		linkType: gtppcap.LinkTypeEthernet,
+		ports:    map[int]bool{},
+		buf:      make([]byte, 65536),
+	}
+	// interfaces without link-layer address(e.g., tun) give IP packets as they are,
+	// while loopback has dummy Ethernet header.
+	s.loopback = ifi.Flags&net.FlagLoopback != 0
+	if !s.loopback && len(ifi.HardwareAddr) == 0 {
+		s.linkType = gtppcap.LinkTypeRaw
+	}
+	for _, p := range ports {
+		s.ports[p] = true
+	}
+	return s, nil
+}
+
+// Next returns the next UDP packet on the ports to be dissected.
+func (s *sniffer) Next() (*gtppcap.Packet, error) {
+	for {
+		n, from, err := syscall.Recvfrom(s.fd, s.buf, 0)
+		if err != nil {
+			if err == syscall.EINTR {
+				continue
+			}
+			return nil, err
+		}
+		// the packets on loopback are seen twice, as outgoing and incoming.
+		if ll, ok := from.(*syscall.SockaddrLinklayer); ok && s.loopback && ll.Pkttype == packetOutgoing {
+			continue
+		}
+
+		pkt, ok := gtppcap.ParseFrame(s.linkType, s.buf[:n])
+		if !ok {
+			continue
+		}
+		if len(s.ports) != 0 && !s.ports[pkt.Src.Port] && !s.ports[pkt.Dst.Port] {
+			continue
+		}
+		pkt.Timestamp = time.Now()
+		return pkt, nil
+	}
+}
+
+// Close closes the socket.
+func (s *sniffer) Close() error {
+	return syscall.Close(s.fd)
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"errors"
+	"io"
+	"runtime"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+)
+
+// sniffer is not available on the platforms other than Linux.
+type sniffer struct{}
+
+// openSniffer is not supported on the platforms other than Linux.
+func openSniffer(name string, ports []int) (*sniffer, error) {
+	return nil, errors.New("sniffing interface is not supported on " + runtime.GOOS)
+}
+
+// Next always returns io.EOF.
+func (s *sniffer) Next() (*gtppcap.Packet, error) {
+	return nil, io.EOF
+}
+
+// Close does nothing.
+func (s *sniffer) Close() error {
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtpechod responds to GTPv1/v2 Echo Requests, to be the peer of gtping
+// or any other GTP node checking the path in the lab.
+//
+// GTPv1-C and GTPv2-C are served on the same GTP-C address, told apart by the
+// version in the header, and GTPv1-U is served on the GTP-U address. The other
+// messages are ignored silently.
+//
+//	gtpechod -c 127.0.0.112:2123 -u 127.0.0.2:2152 -r 3
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"sync"
+)
+
+// command-line flags.
+var (
+	caddr    = flag.String("c", "0.0.0.0:2123", "IP:Port to serve GTPv1-C and GTPv2-C on. Empty to disable.")
+	uaddr    = flag.String("u", "0.0.0.0:2152", "IP:Port to serve GTPv1-U on. Empty to disable.")
+	recovery = flag.Uint("r", 0, "restart counter set in Recovery IE on GTP-C.")
+	quiet    = flag.Bool("q", false, "do not print the Echo Requests responded.")
+)
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[gtpechod] ")
+
+	if *recovery > 0xff {
+		log.Fatalf("invalid restart counter: %d", *recovery)
+	}
+	if *caddr == "" && *uaddr == "" {
+		log.Fatal("nothing to serve")
+	}
+
+	wg := sync.WaitGroup{}
+	for _, l := range []struct {
+		addr   string
+		uplane bool
+	}{{*caddr, false}, {*uaddr, true}} {
+		if l.addr == "" {
+			continue
+		}
+
+		conn, err := net.ListenPacket("udp", l.addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Started serving on %s", conn.LocalAddr())
+
+		r := &responder{conn: conn, uplane: l.uplane, recovery: uint8(*recovery)}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.serve(); err != nil {
+				log.Printf("Stopped serving on %s: %s", conn.LocalAddr(), err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"net"
+
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// responder responds to the Echo Requests received on conn.
+type responder struct {
+	conn     net.PacketConn
+	uplane   bool
+	recovery uint8
+}
+
+func (r *responder) serve() error {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		res, seq, err := r.respond(buf[:n])
+		if err != nil {
+			log.Printf("Failed to handle the packet from %s: %s", raddr, err)
+			continue
+		}
+		if res == nil {
+			continue
+		}
+
+		if _, err := r.conn.WriteTo(res, raddr); err != nil {
+			log.Printf("Failed to respond to %s: %s", raddr, err)
+			continue
+		}
+		if !*quiet {
+			log.Printf("Responded to Echo Request from %s on %s: seq=%d", raddr, r.conn.LocalAddr(), seq)
+		}
+	}
+}
+
+// respond returns the Echo Response to b, or nil if b is not an Echo Request.
+func (r *responder) respond(b []byte) ([]byte, uint32, error) {
+	if len(b) == 0 {
+		return nil, 0, nil
+	}
+
+	// the version is in the first 3 bits in both GTPv1 and GTPv2.
+	switch b[0] >> 5 {
+	case 1:
+		msg, err := v1msg.Decode(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, ok := msg.(*v1msg.EchoRequest); !ok {
+			return nil, 0, nil
+		}
+
+		// Recovery is always 0 on GTP-U.
+		restarts := r.recovery
+		if r.uplane {
+			restarts = 0
+		}
+		res, err := v1msg.NewEchoResponse(msg.Sequence(), v1ies.NewRecovery(restarts)).Serialize()
+		return res, uint32(msg.Sequence()), err
+	case 2:
+		if r.uplane {
+			return nil, 0, nil
+		}
+		msg, err := v2msg.Decode(b)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, ok := msg.(*v2msg.EchoRequest); !ok {
+			return nil, 0, nil
+		}
+
+		res, err := v2msg.NewEchoResponse(msg.Sequence(), v2ies.NewRecovery(r.recovery)).Serialize()
+		return res, msg.Sequence(), err
+	default:
+		return nil, 0, nil
+	}
+}
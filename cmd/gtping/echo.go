@@ -0,0 +1,93 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// echoer builds Echo Request and parses Echo Response in a version of GTP.
+type echoer interface {
+	// request returns the Echo Request with the sequence number given.
+	request(seq uint32) ([]byte, error)
+
+	// response parses b and returns the sequence number and the Recovery in it.
+	// ok is false if b is not an Echo Response. recovery is -1 if it is not present.
+	response(b []byte) (seq uint32, recovery int, ok bool)
+
+	// maxSeq is the largest sequence number in the header.
+	maxSeq() uint32
+}
+
+func newEchoer(version int) (echoer, error) {
+	switch version {
+	case 1:
+		return v1Echoer{}, nil
+	case 2:
+		return v2Echoer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported GTP version: %d", version)
+	}
+}
+
+type v1Echoer struct{}
+
+func (v1Echoer) request(seq uint32) ([]byte, error) {
+	// Recovery is always 0 in Echo Request on GTPv1.
+	return v1msg.NewEchoRequest(uint16(seq), v1ies.NewRecovery(0)).Serialize()
+}
+
+func (v1Echoer) response(b []byte) (uint32, int, bool) {
+	msg, err := v1msg.Decode(b)
+	if err != nil {
+		return 0, 0, false
+	}
+	res, ok := msg.(*v1msg.EchoResponse)
+	if !ok {
+		return 0, 0, false
+	}
+
+	recovery := -1
+	if res.Recovery != nil {
+		recovery = int(res.Recovery.Recovery())
+	}
+	return uint32(res.Sequence()), recovery, true
+}
+
+func (v1Echoer) maxSeq() uint32 {
+	return 0xffff
+}
+
+type v2Echoer struct{}
+
+func (v2Echoer) request(seq uint32) ([]byte, error) {
+	return v2msg.NewEchoRequest(seq, v2ies.NewRecovery(0)).Serialize()
+}
+
+func (v2Echoer) response(b []byte) (uint32, int, bool) {
+	msg, err := v2msg.Decode(b)
+	if err != nil {
+		return 0, 0, false
+	}
+	res, ok := msg.(*v2msg.EchoResponse)
+	if !ok {
+		return 0, 0, false
+	}
+
+	recovery := -1
+	if res.Recovery != nil {
+		recovery = int(res.Recovery.Recovery())
+	}
+	return res.Sequence(), recovery, true
+}
+
+func (v2Echoer) maxSeq() uint32 {
+	return 0xffffff
+}
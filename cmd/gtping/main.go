@@ -0,0 +1,116 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtping sends GTPv1/v2 Echo Requests to a GTP node and reports the
+// round-trip time and the loss, like ping over GTP.
+//
+// The target is given as IP or IP:Port. The port is 2123 by default, or 2152 with
+// -u to check the path on GTP-U, which is available only with GTPv1.
+//
+//	gtping 127.0.0.112
+//	gtping -v 1 -u -c 10 127.0.0.2
+//
+// A change of the Recovery(restart counter) in the Echo Responses is reported as
+// the restart of the peer. Interrupting with Ctrl-C stops sending and prints the
+// statistics.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// command-line flags.
+var (
+	version  = flag.Int("v", 2, "GTP version; 1 or 2.")
+	uplane   = flag.Bool("u", false, "send Echo Requests on GTP-U(2152) instead of GTP-C(2123). GTPv1 only.")
+	laddr    = flag.String("laddr", "", "local IP:Port to send the Echo Requests from.")
+	count    = flag.Int("c", 0, "number of Echo Requests to send. 0 to send until interrupted.")
+	interval = flag.Duration("i", time.Second, "interval between the Echo Requests.")
+	timeout  = flag.Duration("W", time.Second, "time to wait for each Echo Response.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] IP[:Port]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *uplane && *version != 1 {
+		log.Fatal("GTP-U is available only with -v 1")
+	}
+
+	e, err := newEchoer(*version)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	port := "2123"
+	if *uplane {
+		port = "2152"
+	}
+	target := flag.Arg(0)
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		target = net.JoinHostPort(target, port)
+	}
+	raddr, err := net.ResolveUDPAddr("udp", target)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conn, err := net.ListenPacket("udp", *laddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	p := newPinger(conn, raddr, e)
+	go p.receive()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	fmt.Printf("GTPv%d Echo to %s from %s\n", *version, raddr, conn.LocalAddr())
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	interrupted := false
+	for n := 0; !interrupted && (*count == 0 || n < *count); n++ {
+		if n != 0 {
+			select {
+			case <-sigCh:
+				interrupted = true
+				continue
+			case <-ticker.C:
+			}
+		}
+		if err := p.send(*timeout); err != nil {
+			log.Printf("failed to send Echo Request: %s", err)
+		}
+	}
+
+	// wait for the responses to the last ones unless interrupted.
+	if !interrupted {
+		select {
+		case <-sigCh:
+		case <-p.wait():
+		}
+	}
+
+	p.report(os.Stdout)
+	if p.received() == 0 {
+		os.Exit(1)
+	}
+}
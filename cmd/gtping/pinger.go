@@ -0,0 +1,175 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// pinger sends Echo Requests and matches the Echo Responses by the sequence number.
+type pinger struct {
+	conn  net.PacketConn
+	raddr *net.UDPAddr
+	e     echoer
+
+	mu       sync.Mutex
+	seq      uint32
+	pending  map[uint32]time.Time
+	sent     int
+	rtts     []time.Duration
+	recovery int
+	restarts int
+
+	outstanding sync.WaitGroup
+}
+
+func newPinger(conn net.PacketConn, raddr *net.UDPAddr, e echoer) *pinger {
+	return &pinger{
+		conn:     conn,
+		raddr:    raddr,
+		e:        e,
+		pending:  map[uint32]time.Time{},
+		recovery: -1,
+	}
+}
+
+// send sends an Echo Request, which is counted as lost if no response comes within timeout.
+func (p *pinger) send(timeout time.Duration) error {
+	p.mu.Lock()
+	p.seq++
+	if p.seq > p.e.maxSeq() {
+		p.seq = 1
+	}
+	seq := p.seq
+	b, err := p.e.request(seq)
+	if err != nil {
+		p.mu.Unlock()
+		return err
+	}
+	p.pending[seq] = time.Now()
+	p.sent++
+	p.outstanding.Add(1)
+	p.mu.Unlock()
+
+	if _, err := p.conn.WriteTo(b, p.raddr); err != nil {
+		p.expire(seq, false)
+		return err
+	}
+
+	time.AfterFunc(timeout, func() { p.expire(seq, true) })
+	return nil
+}
+
+// expire removes the request not responded yet.
+func (p *pinger) expire(seq uint32, print bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[seq]; !ok {
+		return
+	}
+	delete(p.pending, seq)
+	p.outstanding.Done()
+	if print {
+		fmt.Printf("Request timeout for seq=%d\n", seq)
+	}
+}
+
+// receive reads the Echo Responses until the conn is closed.
+func (p *pinger) receive() {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := p.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		now := time.Now()
+
+		seq, recovery, ok := p.e.response(buf[:n])
+		if !ok {
+			continue
+		}
+
+		p.mu.Lock()
+		sentAt, ok := p.pending[seq]
+		if !ok {
+			// duplicated, or too late.
+			p.mu.Unlock()
+			continue
+		}
+		delete(p.pending, seq)
+		rtt := now.Sub(sentAt)
+		p.rtts = append(p.rtts, rtt)
+
+		restarted := recovery >= 0 && p.recovery >= 0 && recovery != p.recovery
+		if restarted {
+			p.restarts++
+		}
+		if recovery >= 0 {
+			p.recovery = recovery
+		}
+		p.outstanding.Done()
+		p.mu.Unlock()
+
+		fmt.Printf("Echo Response from %s: seq=%d recovery=%d time=%.3f ms\n",
+			addr, seq, recovery, float64(rtt.Microseconds())/1000,
+		)
+		if restarted {
+			fmt.Printf("Recovery changed, %s has restarted\n", addr)
+		}
+	}
+}
+
+// wait returns the channel closed when all the requests are responded or expired.
+func (p *pinger) wait() <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		p.outstanding.Wait()
+		close(ch)
+	}()
+	return ch
+}
+
+func (p *pinger) received() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.rtts)
+}
+
+// report writes the statistics to w.
+func (p *pinger) report(w io.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the ones still waiting for the response are not counted.
+	sent := p.sent - len(p.pending)
+	var loss float64
+	if sent != 0 {
+		loss = float64(sent-len(p.rtts)) / float64(sent) * 100
+	}
+	fmt.Fprintf(w, "\n--- %s gtping statistics ---\n", p.raddr)
+	fmt.Fprintf(w, "%d requests transmitted, %d responses received, %.1f%% loss", sent, len(p.rtts), loss)
+	if p.restarts != 0 {
+		fmt.Fprintf(w, ", %d restarts", p.restarts)
+	}
+	fmt.Fprintln(w)
+
+	if len(p.rtts) == 0 {
+		return
+	}
+	minRTT, maxRTT, total := p.rtts[0], p.rtts[0], time.Duration(0)
+	for _, rtt := range p.rtts {
+		minRTT, maxRTT = min(minRTT, rtt), max(maxRTT, rtt)
+		total += rtt
+	}
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	fmt.Fprintf(w, "rtt min/avg/max = %.3f/%.3f/%.3f ms\n",
+		ms(minRTT), ms(total/time.Duration(len(p.rtts))), ms(maxRTT),
+	)
+}
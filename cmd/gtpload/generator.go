@@ -0,0 +1,311 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+var errTimeout = errors.New("no response")
+
+// ue is a simulated UE.
+type ue struct {
+	imsi, msisdn, imei string
+	eci                uint32
+
+	// values valid only while the UE is attached.
+	localTEID, peerTEID uint32
+	ebi                 uint8
+}
+
+// generator runs the procedures of the UEs over a Conn.
+//
+// The Conn is used only as a transport. The sessions are not registered to
+// the Conn, as it is not designed for handling this many sessions at once.
+// The responses are matched with the requests by the sequence number instead.
+type generator struct {
+	conn    *v2.Conn
+	raddr   net.Addr
+	localIP string
+
+	seq  uint32
+	teid uint32
+
+	mu       sync.Mutex
+	inflight map[uint32]chan messages.Message
+
+	stopOnce, abortOnce sync.Once
+	stopCh, abortCh     chan struct{}
+
+	start                  time.Time
+	active                 int64
+	attach, modify, detach *procStats
+}
+
+func newGenerator(conn *v2.Conn, raddr net.Addr, localIP string) *generator {
+	return &generator{
+		conn:     conn,
+		raddr:    raddr,
+		localIP:  localIP,
+		inflight: map[uint32]chan messages.Message{},
+		stopCh:   make(chan struct{}),
+		abortCh:  make(chan struct{}),
+		start:    time.Now(),
+		attach:   newProcStats("attach"),
+		modify:   newProcStats("modify"),
+		detach:   newProcStats("detach"),
+	}
+}
+
+// stop stops attaching new UEs. The attached ones are detached after holding.
+func (g *generator) stop() {
+	g.stopOnce.Do(func() { close(g.stopCh) })
+}
+
+// abort stops attaching new UEs and detaches the attached ones immediately.
+func (g *generator) abort() {
+	g.stop()
+	g.abortOnce.Do(func() { close(g.abortCh) })
+}
+
+func (g *generator) stopped() <-chan struct{} {
+	return g.stopCh
+}
+
+// pace returns the channel that lets the receiver start attaching at the rate
+// given. The ticks are dropped if nobody is ready to attach.
+func (g *generator) pace(rate float64) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-g.stopCh:
+				return
+			case <-ticker.C:
+			}
+
+			select {
+			case <-g.stopCh:
+				return
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch
+}
+
+// handleResponse passes the response to the procedure waiting for it.
+//
+// The responses to the requests already given up or retransmitted are just
+// discarded.
+func (g *generator) handleResponse(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+	g.mu.Lock()
+	ch, ok := g.inflight[msg.Sequence()]
+	g.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case ch <- msg:
+	default:
+	}
+	return nil
+}
+
+// transact sends the request and waits for the response, retransmitting the
+// request if the response does not come in time.
+func (g *generator) transact(req messages.Message) (messages.Message, time.Duration, error) {
+	seq := atomic.AddUint32(&g.seq, 1) & 0xffffff
+	req.SetSequenceNumber(seq)
+	b, err := messages.Serialize(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ch := make(chan messages.Message, 1)
+	g.mu.Lock()
+	g.inflight[seq] = ch
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.inflight, seq)
+		g.mu.Unlock()
+	}()
+
+	sentAt := time.Now()
+	for n := 0; n <= *retries; n++ {
+		if _, err := g.conn.WriteTo(b, g.raddr); err != nil {
+			return nil, 0, err
+		}
+
+		timer := time.NewTimer(*timeout)
+		select {
+		case rsp := <-ch:
+			timer.Stop()
+			return rsp, time.Since(sentAt), nil
+		case <-timer.C:
+		}
+	}
+	return nil, 0, errTimeout
+}
+
+// run goes through the procedures once for the UE.
+func (g *generator) run(u *ue) {
+	if !g.doAttach(u) {
+		return
+	}
+	atomic.AddInt64(&g.active, 1)
+	defer atomic.AddInt64(&g.active, -1)
+
+	if g.doModify(u) {
+		timer := time.NewTimer(*hold)
+		select {
+		case <-g.abortCh:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+	g.doDetach(u)
+}
+
+func (g *generator) doAttach(u *ue) bool {
+	u.localTEID = atomic.AddUint32(&g.teid, 1)
+	u.ebi = 5
+
+	senderIFType := v2.IFTypeS11MMEGTPC
+	if *target == "pgw" {
+		senderIFType = v2.IFTypeS5S8SGWGTPC
+	}
+	bearer := ies.NewBearerContext(
+		ies.NewEPSBearerID(u.ebi),
+		ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0),
+	)
+	if *target == "pgw" {
+		bearer.Add(ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, u.localTEID, *userIP, "").WithInstance(2))
+	}
+
+	req := messages.NewCreateSessionRequest(
+		0, 0,
+		ies.NewIMSI(u.imsi),
+		ies.NewMSISDN(u.msisdn),
+		ies.NewMobileEquipmentIdentity(u.imei),
+		ies.NewUserLocationInformation(
+			0, 0, 0, 1, 1, 0, 0, 0,
+			*mcc, *mnc, 0, 0, 0, 0, 1, u.eci, 0, 0,
+		),
+		ies.NewServingNetwork(*mcc, *mnc),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewFullyQualifiedTEID(senderIFType, u.localTEID, g.localIP, ""),
+		ies.NewAccessPointName(*apn),
+		ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+		ies.NewPDNType(v2.PDNTypeIPv4),
+		ies.NewPDNAddressAllocation("0.0.0.0"),
+		ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+		ies.NewAggregateMaximumBitRate(0, 0),
+		bearer,
+		ies.NewUETimeZone(9*time.Hour, 0),
+	)
+	if *target == "sgw" {
+		req.PGWS5S8FTEIDC = ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0, *pgwIP, "").WithInstance(1)
+	}
+
+	rsp, rtt, err := g.transact(req)
+	if err != nil {
+		g.attach.fail(err)
+		return false
+	}
+
+	csRsp, ok := rsp.(*messages.CreateSessionResponse)
+	if !ok || !accepted(csRsp.Cause) {
+		g.attach.reject()
+		return false
+	}
+	if ie := csRsp.SenderFTEIDC; ie != nil {
+		u.peerTEID = ie.TEID()
+	}
+	if ie := csRsp.BearerContextsCreated; ie != nil {
+		for _, child := range ie.BearerContext() {
+			if child.Type == ies.EPSBearerID {
+				u.ebi = child.EPSBearerID()
+			}
+		}
+	}
+
+	g.attach.succeed(rtt)
+	return true
+}
+
+func (g *generator) doModify(u *ue) bool {
+	// the F-TEID on the user plane is the eNB's on S11 and the S-GW's on S5/S8.
+	var fteid *ies.IE
+	if *target == "sgw" {
+		fteid = ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, u.localTEID, *userIP, "")
+	} else {
+		fteid = ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, u.localTEID, *userIP, "").WithInstance(1)
+	}
+
+	req := messages.NewModifyBearerRequest(
+		u.peerTEID, 0,
+		ies.NewUserLocationInformation(
+			0, 0, 0, 1, 1, 0, 0, 0,
+			*mcc, *mnc, 0, 0, 0, 0, 1, u.eci, 0, 0,
+		),
+		ies.NewIndicationFromOctets(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
+		ies.NewBearerContext(ies.NewEPSBearerID(u.ebi), fteid),
+	)
+
+	rsp, rtt, err := g.transact(req)
+	if err != nil {
+		g.modify.fail(err)
+		return false
+	}
+
+	mbRsp, ok := rsp.(*messages.ModifyBearerResponse)
+	if !ok || !accepted(mbRsp.Cause) {
+		g.modify.reject()
+		return false
+	}
+
+	g.modify.succeed(rtt)
+	return true
+}
+
+func (g *generator) doDetach(u *ue) bool {
+	req := messages.NewDeleteSessionRequest(
+		u.peerTEID, 0,
+		ies.NewEPSBearerID(u.ebi),
+	)
+
+	rsp, rtt, err := g.transact(req)
+	if err != nil {
+		g.detach.fail(err)
+		return false
+	}
+
+	dsRsp, ok := rsp.(*messages.DeleteSessionResponse)
+	if !ok || !accepted(dsRsp.Cause) {
+		g.detach.reject()
+		return false
+	}
+
+	g.detach.succeed(rtt)
+	return true
+}
+
+func accepted(cause *ies.IE) bool {
+	return cause != nil && cause.Cause() == v2.CauseRequestAccepted
+}
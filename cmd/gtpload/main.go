@@ -0,0 +1,204 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtpload generates GTPv2-C signaling load toward S-GW or P-GW.
+//
+// Each simulated UE repeats the procedures below, which is started at the
+// rate given with -rate in total.
+//
+// 1. Attach: send Create Session Request and wait for Create Session Response.
+//
+// 2. Modify: send Modify Bearer Request with the eNB(or S-GW on S5/S8-U)
+// F-TEID and wait for Modify Bearer Response.
+//
+// 3. Hold the session for the duration given with -hold.
+//
+// 4. Detach: send Delete Session Request and wait for Delete Session Response.
+//
+// With -duration 0, each UE goes through the procedures only once. Otherwise,
+// the UEs keep attaching again until the duration expires. The requests are
+// retransmitted with the same sequence number if no response comes within
+// -timeout, up to -retries times.
+//
+// The success rate and the latency percentiles of each procedure are printed
+// at the end. The latency is the time from the first transmission of the
+// request to the arrival of the response, including the retransmissions.
+//
+//	gtpload -raddr 127.0.0.112:2123 -ues 1000 -rate 200 -hold 10s
+//	gtpload -target pgw -laddr 127.0.0.51:2123 -raddr 127.0.0.52:2123
+//
+// Interrupting with Ctrl-C stops attaching new UEs and detaches the ones
+// holding sessions before printing the results.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// command-line flags.
+var (
+	laddr    = flag.String("laddr", "127.0.0.111:2123", "local IP:Port to send the requests from.")
+	raddr    = flag.String("raddr", "127.0.0.112:2123", "IP:Port of the S-GW or P-GW under test.")
+	target   = flag.String("target", "sgw", "node under test; sgw(as MME on S11) or pgw(as S-GW on S5/S8).")
+	pgwIP    = flag.String("pgw", "127.0.0.52", "P-GW's IP on S5/S8 told to S-GW. Used only with -target sgw.")
+	userIP   = flag.String("uip", "127.0.0.1", "IP of eNB(or S-GW) on the user plane told in Modify Bearer Request.")
+	numUEs   = flag.Int("ues", 100, "number of UEs to simulate.")
+	rate     = flag.Float64("rate", 10, "number of attaches started per second.")
+	hold     = flag.Duration("hold", 5*time.Second, "duration to keep each session before detaching.")
+	duration = flag.Duration("duration", 0, "duration to keep re-attaching the UEs. 0 to attach each UE only once.")
+	timeout  = flag.Duration("timeout", 3*time.Second, "time to wait for the response before retransmitting.")
+	retries  = flag.Int("retries", 2, "number of retransmissions before giving up.")
+	imsi     = flag.String("imsi", "001010000000001", "IMSI of the first UE. Incremented one by one for the others.")
+	msisdn   = flag.String("msisdn", "819000000001", "MSISDN of the first UE. Incremented one by one for the others.")
+	mcc      = flag.String("mcc", "001", "MCC of the serving network.")
+	mnc      = flag.String("mnc", "01", "MNC of the serving network.")
+	apn      = flag.String("apn", "internet", "APN to attach to.")
+	interval = flag.Duration("interval", time.Second, "interval of printing the progress. 0 to disable.")
+)
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[gtpload] ")
+
+	if *rate <= 0 {
+		log.Fatal("rate should be greater than 0")
+	}
+	if *target != "sgw" && *target != "pgw" {
+		log.Fatalf("unknown target: %s", *target)
+	}
+
+	imsis, err := sequentialDigits(*imsi, *numUEs)
+	if err != nil {
+		log.Fatalf("invalid IMSI: %s", err)
+	}
+	msisdns, err := sequentialDigits(*msisdn, *numUEs)
+	if err != nil {
+		log.Fatalf("invalid MSISDN: %s", err)
+	}
+
+	la, err := net.ResolveUDPAddr("udp", *laddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ra, err := net.ResolveUDPAddr("udp", *raddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	errCh := make(chan error, 16)
+	conn, err := v2.Dial(la, ra, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+	log.Printf("Connection established with %s", ra)
+
+	// the sessions are managed by the generator, and the Conn does not know
+	// the TEIDs used. validation must be disabled not to drop the responses.
+	conn.DisableValidation()
+
+	g := newGenerator(conn, ra, la.IP.String())
+	conn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionResponse: g.handleResponse,
+		messages.MsgTypeModifyBearerResponse:  g.handleResponse,
+		messages.MsgTypeDeleteSessionResponse: g.handleResponse,
+	})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Println("Interrupted, detaching the UEs...")
+		g.abort()
+	}()
+	if *duration > 0 {
+		time.AfterFunc(*duration, g.stop)
+	}
+
+	go func() {
+		for err := range errCh {
+			log.Printf("Warning: %s", err)
+		}
+	}()
+
+	doneCh := make(chan struct{})
+	if *interval > 0 {
+		go func() {
+			ticker := time.NewTicker(*interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-doneCh:
+					return
+				case <-ticker.C:
+					log.Println(g.progress())
+				}
+			}
+		}()
+	}
+
+	tokens := g.pace(*rate)
+	wg := sync.WaitGroup{}
+	for n := 0; n < *numUEs; n++ {
+		u := &ue{
+			imsi:   imsis[n],
+			msisdn: msisdns[n],
+			imei:   fmt.Sprintf("35%013d", n),
+			eci:    uint32(n),
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-g.stopped():
+					return
+				case <-tokens:
+				}
+				g.run(u)
+
+				if *duration == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	g.stop()
+	close(doneCh)
+
+	if err := g.report(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// sequentialDigits returns n digit strings incremented one by one from first,
+// keeping the number of digits.
+func sequentialDigits(first string, n int) ([]string, error) {
+	start, err := strconv.ParseUint(first, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	digits := make([]string, n)
+	for i := range digits {
+		digits[i] = fmt.Sprintf("%0*d", len(first), start+uint64(i))
+		if len(digits[i]) > len(first) {
+			return nil, fmt.Errorf("%s overflows with %d UEs", first, n)
+		}
+	}
+	return digits, nil
+}
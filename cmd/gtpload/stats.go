@@ -0,0 +1,120 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// procStats is the statistics of a procedure.
+type procStats struct {
+	name string
+
+	mu        sync.Mutex
+	accepted  int
+	rejected  int
+	timedOut  int
+	errored   int
+	latencies []time.Duration
+}
+
+func newProcStats(name string) *procStats {
+	return &procStats{name: name}
+}
+
+// succeed counts the procedure accepted by the peer with the latency.
+func (p *procStats) succeed(rtt time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.accepted++
+	p.latencies = append(p.latencies, rtt)
+}
+
+// reject counts the procedure rejected by the peer with some Cause.
+func (p *procStats) reject() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rejected++
+}
+
+// fail counts the procedure failed without response.
+func (p *procStats) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == errTimeout {
+		p.timedOut++
+		return
+	}
+	p.errored++
+}
+
+func (p *procStats) total() int {
+	return p.accepted + p.rejected + p.timedOut + p.errored
+}
+
+// percentile returns the latency at the percentile given with the nearest-rank
+// method. The latencies must be sorted beforehand.
+func (p *procStats) percentile(pct float64) time.Duration {
+	if len(p.latencies) == 0 {
+		return 0
+	}
+
+	rank := int(pct/100*float64(len(p.latencies))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(p.latencies) {
+		rank = len(p.latencies) - 1
+	}
+	return p.latencies[rank]
+}
+
+// progress returns the one-line summary of the current statistics.
+func (g *generator) progress() string {
+	var s []string
+	for _, p := range []*procStats{g.attach, g.modify, g.detach} {
+		p.mu.Lock()
+		s = append(s, fmt.Sprintf("%s: %d/%d", p.name, p.accepted, p.total()))
+		p.mu.Unlock()
+	}
+
+	return fmt.Sprintf(
+		"%s elapsed, %d active, %s",
+		time.Since(g.start).Truncate(time.Second), atomic.LoadInt64(&g.active), strings.Join(s, ", "),
+	)
+}
+
+// report writes the statistics of all the procedures in table format.
+func (g *generator) report(w io.Writer) error {
+	elapsed := time.Since(g.start)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "procedure\ttotal\taccepted\trejected\ttimeout\terror\tsuccess\trate\tp50\tp90\tp99\tmax\t")
+
+	for _, p := range []*procStats{g.attach, g.modify, g.detach} {
+		p.mu.Lock()
+		sort.Slice(p.latencies, func(i, j int) bool { return p.latencies[i] < p.latencies[j] })
+
+		var success float64
+		if total := p.total(); total > 0 {
+			success = float64(p.accepted) / float64(total) * 100
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%.2f%%\t%.1f/s\t%s\t%s\t%s\t%s\t\n",
+			p.name, p.total(), p.accepted, p.rejected, p.timedOut, p.errored,
+			success, float64(p.total())/elapsed.Seconds(),
+			p.percentile(50).Round(time.Microsecond), p.percentile(90).Round(time.Microsecond),
+			p.percentile(99).Round(time.Microsecond), p.percentile(100).Round(time.Microsecond),
+		)
+		p.mu.Unlock()
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package enbsim provides an eNB simulator that generates user-plane traffic
+// over the S1-U tunnels, to validate the relays of S-GW and P-GW with the loss
+// and the latency measured.
+//
+// The traffic consists of ICMP Echo Requests from the UE, with the sequence
+// number and the time of transmission in the payload. They are echoed back by
+// any host beyond the P-GW, or by another Endpoint with SetReflect enabled,
+// which works as the P-GW and the host on SGi in the test environment.
+//
+//	enb, err := enbsim.ListenAndServe(s1uAddr, errCh)
+//	// ...
+//	enb.AddTunnel(tunnel)
+//	report, err := enb.Generate(ctx, tunnel, &enbsim.TrafficConfig{
+//		Size: 512, Rate: 100, Duration: 10 * time.Second, Target: target, Bidirectional: true,
+//	})
+//
+// The tunnels can be established with the MME simulator in mmesim, and the
+// Tunnel for the session is given by TunnelFromBearer.
+package enbsim
@@ -0,0 +1,168 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package enbsim
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// Tunnel is a GTP-U tunnel between the Endpoint and its peer for a UE.
+type Tunnel struct {
+	// UEIP is the IP address of the UE, which is the source of the packets generated.
+	UEIP net.IP
+	// IncomingTEID is the TEID allocated by the Endpoint, which the peer sends with.
+	IncomingTEID uint32
+	// OutgoingTEID is the TEID allocated by the peer, which the Endpoint sends with.
+	OutgoingTEID uint32
+	// PeerAddr is the GTP-U address of the peer.
+	PeerAddr net.Addr
+}
+
+// TunnelFromBearer returns the Tunnel of eNB for the bearer, which is typically
+// the default bearer of the Session established by the MME in mmesim.
+func TunnelFromBearer(br *v2.Bearer) (*Tunnel, error) {
+	raddr := br.RemoteAddress()
+	if raddr == nil {
+		return nil, v2.ErrNoRemoteAddressFound
+	}
+	ip := net.ParseIP(br.SubscriberIP)
+	if ip == nil || ip.To4() == nil {
+		return nil, &net.ParseError{Type: "IPv4 address", Text: br.SubscriberIP}
+	}
+
+	return &Tunnel{
+		UEIP:         ip,
+		IncomingTEID: br.IncomingTEID(),
+		OutgoingTEID: br.OutgoingTEID(),
+		PeerAddr:     raddr,
+	}, nil
+}
+
+// Endpoint is a GTP-U endpoint that generates the traffic over the tunnels.
+//
+// It works as eNB on S1-U, or with SetReflect enabled, as the P-GW and the host
+// on SGi that echo the traffic back.
+type Endpoint struct {
+	// Conn is the GTP-U Conn of the Endpoint.
+	Conn *v1.UPlaneConn
+
+	mu      sync.Mutex
+	tunnels map[uint32]*Tunnel
+	streams map[uint16]*stream
+	lastID  uint16
+
+	reflect   atomic.Bool
+	reflected atomic.Uint64
+}
+
+// ListenAndServe creates a new Endpoint listening on laddr, and starts receiving
+// the packets over the tunnels.
+//
+// The errCh given should be monitored continuously after retrieving *Endpoint.
+// Otherwise the background process may get stuck.
+func ListenAndServe(laddr net.Addr, errCh chan error) (*Endpoint, error) {
+	conn, err := v1.ListenAndServeUPlane(laddr, 0, errCh)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Endpoint{
+		Conn:    conn,
+		tunnels: map[uint32]*Tunnel{},
+		streams: map[uint16]*stream{},
+	}
+	go e.serve(errCh)
+	return e, nil
+}
+
+// Close closes the Conn of the Endpoint.
+func (e *Endpoint) Close() error {
+	return e.Conn.Close()
+}
+
+// AddTunnel adds the tunnel to the Endpoint, to reflect the traffic received
+// with its IncomingTEID. The tunnel with the same IncomingTEID is replaced.
+//
+// The tunnel is not required to generate the traffic with Generate.
+func (e *Endpoint) AddTunnel(t *Tunnel) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tunnels[t.IncomingTEID] = t
+}
+
+// RemoveTunnel removes the tunnel with the IncomingTEID from the Endpoint.
+func (e *Endpoint) RemoveTunnel(teid uint32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.tunnels, teid)
+}
+
+// SetReflect sets whether to echo the traffic generated by the other Endpoint
+// back through the tunnel added for the incoming TEID.
+//
+// The traffic received with unknown TEID is discarded.
+func (e *Endpoint) SetReflect(enable bool) {
+	e.reflect.Store(enable)
+}
+
+// Reflected returns the number of packets echoed back by the Endpoint so far.
+//
+// This can be used to count the packets received in the uplink-only traffic.
+func (e *Endpoint) Reflected() uint64 {
+	return e.reflected.Load()
+}
+
+func (e *Endpoint) serve(errCh chan error) {
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, _, teid, err := e.Conn.ReadFromGTP(buf)
+		if err != nil {
+			if err == v1.ErrConnNotOpened {
+				return
+			}
+			continue
+		}
+
+		p, ok := parseProbe(buf[:n])
+		if !ok {
+			continue
+		}
+		now := time.Now()
+
+		if !p.request {
+			e.mu.Lock()
+			if s, ok := e.streams[p.id]; ok {
+				s.record(p, now)
+			}
+			e.mu.Unlock()
+			continue
+		}
+
+		if !e.reflect.Load() {
+			continue
+		}
+		e.mu.Lock()
+		t, ok := e.tunnels[teid]
+		e.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		reflectProbe(buf[:n])
+		if _, err := e.Conn.WriteToGTP(t.OutgoingTEID, buf[:n], t.PeerAddr); err != nil {
+			go func() {
+				errCh <- err
+			}()
+			continue
+		}
+		e.reflected.Add(1)
+	}
+}
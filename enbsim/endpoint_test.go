@@ -0,0 +1,200 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package enbsim_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/enbsim"
+	"github.com/wmnsk/go-gtp/gtptest"
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+var (
+	ueIP   = net.IPv4(10, 45, 0, 1)
+	target = net.IPv4(192, 0, 2, 1)
+)
+
+func listen(t *testing.T) *enbsim.Endpoint {
+	t.Helper()
+
+	e, err := enbsim.ListenAndServe(gtptest.LoopbackAddr(t), make(chan error, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { e.Close() })
+	return e
+}
+
+// setup returns eNB and the reflector connected with each other directly.
+func setup(t *testing.T) (enb, reflector *enbsim.Endpoint, tunnel *enbsim.Tunnel) {
+	t.Helper()
+
+	enb = listen(t)
+	reflector = listen(t)
+	reflector.SetReflect(true)
+
+	tunnel = &enbsim.Tunnel{
+		UEIP: ueIP, IncomingTEID: 0x11111111, OutgoingTEID: 0x22222222,
+		PeerAddr: reflector.Conn.LocalAddr(),
+	}
+	enb.AddTunnel(tunnel)
+	reflector.AddTunnel(&enbsim.Tunnel{
+		UEIP: ueIP, IncomingTEID: 0x22222222, OutgoingTEID: 0x11111111,
+		PeerAddr: enb.Conn.LocalAddr(),
+	})
+	return enb, reflector, tunnel
+}
+
+func TestGenerateBidirectional(t *testing.T) {
+	enb, reflector, tunnel := setup(t)
+
+	r, err := enb.Generate(context.Background(), tunnel, &enbsim.TrafficConfig{
+		Size: 256, Rate: 1000, Count: 50, Target: target, Bidirectional: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Sent != 50 || r.SentBytes != 50*256 {
+		t.Errorf("unexpected sent: %d packets, %d bytes", r.Sent, r.SentBytes)
+	}
+	if r.Received != r.Sent || r.Loss() != 0 || r.Duplicated != 0 {
+		t.Errorf("unexpected received: %s", r)
+	}
+	if r.MinRTT <= 0 || r.MinRTT > r.AvgRTT || r.AvgRTT > r.MaxRTT {
+		t.Errorf("unexpected RTT: %s", r)
+	}
+	if got := reflector.Reflected(); got != 50 {
+		t.Errorf("unexpected reflected: got %d, want %d", got, 50)
+	}
+}
+
+func TestGenerateThroughRelay(t *testing.T) {
+	enb := listen(t)
+	reflector := listen(t)
+	reflector.SetReflect(true)
+
+	// S-GW relaying between S1-U and S5-U.
+	s1u, err := v1.ListenAndServeUPlane(gtptest.LoopbackAddr(t), 0, make(chan error, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s1u.Close()
+	s5u, err := v1.ListenAndServeUPlane(gtptest.LoopbackAddr(t), 0, make(chan error, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s5u.Close()
+
+	const (
+		enbTEID uint32 = 0x11111111
+		s1uTEID uint32 = 0x22222222
+		s5uTEID uint32 = 0x33333333
+		pgwTEID uint32 = 0x44444444
+	)
+	if err := s1u.RelayTo(s5u, s1uTEID, pgwTEID, reflector.Conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s5u.RelayTo(s1u, s5uTEID, enbTEID, enb.Conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	tunnel := &enbsim.Tunnel{
+		UEIP: ueIP, IncomingTEID: enbTEID, OutgoingTEID: s1uTEID, PeerAddr: s1u.LocalAddr(),
+	}
+	enb.AddTunnel(tunnel)
+	reflector.AddTunnel(&enbsim.Tunnel{
+		UEIP: ueIP, IncomingTEID: pgwTEID, OutgoingTEID: s5uTEID, PeerAddr: s5u.LocalAddr(),
+	})
+
+	r, err := enb.Generate(context.Background(), tunnel, &enbsim.TrafficConfig{
+		Rate: 1000, Count: 50, Target: target, Bidirectional: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Sent != 50 || r.Received != 50 {
+		t.Errorf("unexpected report: %s", r)
+	}
+}
+
+func TestGenerateUplinkOnly(t *testing.T) {
+	enb, reflector, tunnel := setup(t)
+
+	r, err := enb.Generate(context.Background(), tunnel, &enbsim.TrafficConfig{
+		Rate: 1000, Count: 20, Target: target,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Sent != 20 || r.Received != 0 {
+		t.Errorf("unexpected report: %s", r)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for reflector.Reflected() != 20 {
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected reflected: got %d, want %d", reflector.Reflected(), 20)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGenerateLoss(t *testing.T) {
+	enb, reflector, tunnel := setup(t)
+	reflector.RemoveTunnel(tunnel.OutgoingTEID)
+
+	r, err := enb.Generate(context.Background(), tunnel, &enbsim.TrafficConfig{
+		Rate: 1000, Count: 10, Target: target, Bidirectional: true, Wait: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Received != 0 || r.Loss() != 1 {
+		t.Errorf("unexpected report: %s", r)
+	}
+}
+
+func TestGenerateCancel(t *testing.T) {
+	enb, _, tunnel := setup(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	r, err := enb.Generate(ctx, tunnel, &enbsim.TrafficConfig{
+		Rate: 100, Duration: 10 * time.Second, Target: target, Bidirectional: true,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Sent == 0 || r.Sent >= 1000 {
+		t.Errorf("unexpected sent: %d", r.Sent)
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	enb, _, tunnel := setup(t)
+
+	cases := []struct {
+		description string
+		cfg         *enbsim.TrafficConfig
+	}{
+		{"too small", &enbsim.TrafficConfig{Size: enbsim.MinPacketSize - 1, Rate: 1, Count: 1, Target: target}},
+		{"too large", &enbsim.TrafficConfig{Size: enbsim.MaxPacketSize + 1, Rate: 1, Count: 1, Target: target}},
+		{"no rate", &enbsim.TrafficConfig{Count: 1, Target: target}},
+		{"no count nor duration", &enbsim.TrafficConfig{Rate: 1, Target: target}},
+		{"no target", &enbsim.TrafficConfig{Rate: 1, Count: 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if _, err := enb.Generate(context.Background(), tunnel, c.cfg); !errors.Is(err, enbsim.ErrInvalidTraffic) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package enbsim
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+)
+
+const (
+	ipv4HeaderLen = 20
+	icmpHeaderLen = 8
+	// magic(4), sequence number(8) and the time of transmission(8).
+	probeLen = 20
+
+	icmpTypeEchoReply   = 0
+	icmpTypeEchoRequest = 8
+)
+
+// MinPacketSize is the minimum size of the packets generated, which consists of
+// IPv4 header, ICMP header and the probe in the payload.
+const MinPacketSize = ipv4HeaderLen + icmpHeaderLen + probeLen
+
+// MaxPacketSize is the maximum size of the packets generated.
+const MaxPacketSize = 1500
+
+// probeMagic is the first bytes of the ICMP payload to identify the probes
+// generated by Endpoint.
+var probeMagic = []byte{'g', 't', 'p', 's'}
+
+// probe is the information carried by the packet generated by Endpoint.
+type probe struct {
+	request bool
+	id      uint16
+	seq     uint64
+	sent    time.Time
+}
+
+// putProbe serializes an ICMP Echo Request from src to dst into b, which is
+// used as it is as the size of the IPv4 packet.
+func putProbe(b []byte, src, dst net.IP, id uint16, seq uint64, sent time.Time) {
+	for i := range b {
+		b[i] = 0
+	}
+
+	b[0] = 0x45
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	binary.BigEndian.PutUint16(b[4:6], uint16(seq))
+	b[8] = 64 // TTL
+	b[9] = 1  // ICMP
+	copy(b[12:16], src.To4())
+	copy(b[16:20], dst.To4())
+	binary.BigEndian.PutUint16(b[10:12], checksum.Sum(b[:ipv4HeaderLen], 0))
+
+	icmp := b[ipv4HeaderLen:]
+	icmp[0] = icmpTypeEchoRequest
+	binary.BigEndian.PutUint16(icmp[4:6], id)
+	binary.BigEndian.PutUint16(icmp[6:8], uint16(seq))
+
+	payload := icmp[icmpHeaderLen:]
+	copy(payload[0:4], probeMagic)
+	binary.BigEndian.PutUint64(payload[4:12], seq)
+	binary.BigEndian.PutUint64(payload[12:20], uint64(sent.UnixNano()))
+	binary.BigEndian.PutUint16(icmp[2:4], checksum.Sum(icmp, 0))
+}
+
+// parseProbe parses the ICMP Echo Request or Reply in b generated by Endpoint.
+// The second returned value is false if b is not a probe.
+func parseProbe(b []byte) (probe, bool) {
+	if len(b) < MinPacketSize || b[0] != 0x45 || b[9] != 1 {
+		return probe{}, false
+	}
+
+	icmp := b[ipv4HeaderLen:]
+	switch icmp[0] {
+	case icmpTypeEchoRequest, icmpTypeEchoReply:
+	default:
+		return probe{}, false
+	}
+	payload := icmp[icmpHeaderLen:]
+	if string(payload[0:4]) != string(probeMagic) {
+		return probe{}, false
+	}
+
+	return probe{
+		request: icmp[0] == icmpTypeEchoRequest,
+		id:      binary.BigEndian.Uint16(icmp[4:6]),
+		seq:     binary.BigEndian.Uint64(payload[4:12]),
+		sent:    time.Unix(0, int64(binary.BigEndian.Uint64(payload[12:20]))),
+	}, true
+}
+
+// reflectProbe turns the ICMP Echo Request in b into the Echo Reply in place.
+func reflectProbe(b []byte) {
+	var src [4]byte
+	copy(src[:], b[12:16])
+	copy(b[12:16], b[16:20])
+	copy(b[16:20], src[:])
+
+	icmp := b[ipv4HeaderLen:]
+	icmp[0] = icmpTypeEchoReply
+	icmp[2], icmp[3] = 0, 0
+	binary.BigEndian.PutUint16(icmp[2:4], checksum.Sum(icmp, 0))
+}
@@ -0,0 +1,249 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package enbsim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+var (
+	// ErrInvalidTraffic indicates that the TrafficConfig given is not valid.
+	ErrInvalidTraffic = errors.New("invalid traffic config")
+
+	// ErrTooManyStreams indicates that too many traffic are generated at the same
+	// time on an Endpoint to distinguish the replies.
+	ErrTooManyStreams = errors.New("too many streams running")
+)
+
+// DefaultWait is the time to wait for the replies after the last packet is sent,
+// used when Wait is not set in TrafficConfig.
+const DefaultWait = time.Second
+
+// TrafficConfig is the configuration of the traffic generated by Endpoint.
+type TrafficConfig struct {
+	// Size is the size of the IPv4 packets over the tunnel, between MinPacketSize
+	// and MaxPacketSize. If zero, MinPacketSize is used.
+	Size int
+	// Rate is the number of packets sent per second.
+	Rate float64
+	// Count is the number of packets sent. If zero, Duration is used instead.
+	Count int
+	// Duration is the time to keep sending the packets, used if Count is zero.
+	Duration time.Duration
+	// Target is the destination of the packets from the UE.
+	Target net.IP
+
+	// Bidirectional is whether to wait for the replies coming back through the
+	// tunnel, to measure the loss and the round-trip time.
+	Bidirectional bool
+	// Wait is the time to wait for the replies after the last packet is sent.
+	// If zero, DefaultWait is used.
+	Wait time.Duration
+}
+
+func (c *TrafficConfig) validate() error {
+	switch {
+	case c.Size != 0 && (c.Size < MinPacketSize || c.Size > MaxPacketSize):
+		return fmt.Errorf("%w: size should be between %d and %d: %d", ErrInvalidTraffic, MinPacketSize, MaxPacketSize, c.Size)
+	case c.Rate <= 0:
+		return fmt.Errorf("%w: rate should be greater than 0: %v", ErrInvalidTraffic, c.Rate)
+	case c.Count <= 0 && c.Duration <= 0:
+		return fmt.Errorf("%w: either count or duration should be given", ErrInvalidTraffic)
+	case c.Target.To4() == nil:
+		return fmt.Errorf("%w: target should be an IPv4 address: %v", ErrInvalidTraffic, c.Target)
+	}
+	return nil
+}
+
+// Report is the result of the traffic generated.
+//
+// The Received and the round-trip times are measured only for the bidirectional
+// traffic.
+type Report struct {
+	Sent, Received, Duplicated int
+	SentBytes                  int
+	// Elapsed is the time from the first packet sent to the end of waiting for the replies.
+	Elapsed                time.Duration
+	MinRTT, AvgRTT, MaxRTT time.Duration
+}
+
+// Loss returns the ratio of the packets not replied to the packets sent.
+func (r *Report) Loss() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Sent-r.Received) / float64(r.Sent)
+}
+
+// String returns the Report in a human-readable form.
+func (r *Report) String() string {
+	s := fmt.Sprintf("sent=%d(%dbytes) elapsed=%s", r.Sent, r.SentBytes, r.Elapsed.Round(time.Millisecond))
+	if r.Received == 0 && r.MaxRTT == 0 {
+		return s
+	}
+	return fmt.Sprintf(
+		"%s received=%d dup=%d loss=%.2f%% rtt(min/avg/max)=%s/%s/%s",
+		s, r.Received, r.Duplicated, r.Loss()*100, r.MinRTT, r.AvgRTT, r.MaxRTT,
+	)
+}
+
+// stream is the state of the bidirectional traffic, which is updated with the
+// replies received. The fields are protected by mu of Endpoint.
+type stream struct {
+	seen       map[uint64]struct{}
+	duplicated int
+	sum        time.Duration
+	min, max   time.Duration
+	notifyCh   chan struct{}
+}
+
+func (s *stream) record(p probe, now time.Time) {
+	if _, ok := s.seen[p.seq]; ok {
+		s.duplicated++
+		return
+	}
+	s.seen[p.seq] = struct{}{}
+
+	rtt := now.Sub(p.sent)
+	s.sum += rtt
+	if s.min == 0 || rtt < s.min {
+		s.min = rtt
+	}
+	if rtt > s.max {
+		s.max = rtt
+	}
+
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// registerStream allocates the ICMP identifier for the new stream.
+func (e *Endpoint) registerStream() (uint16, *stream, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := 0; i < 1<<16; i++ {
+		e.lastID++
+		if _, ok := e.streams[e.lastID]; ok {
+			continue
+		}
+		s := &stream{seen: map[uint64]struct{}{}, notifyCh: make(chan struct{}, 1)}
+		e.streams[e.lastID] = s
+		return e.lastID, s, nil
+	}
+	return 0, nil, ErrTooManyStreams
+}
+
+// Generate sends the traffic over the tunnel as configured, and returns the Report
+// after all the packets are sent and, for the bidirectional traffic, replied or
+// the Wait has passed.
+//
+// Cancelling the ctx stops sending the packets and waiting for the replies, and
+// the Report of the traffic so far is returned with the error of ctx.
+func (e *Endpoint) Generate(ctx context.Context, t *Tunnel, cfg *TrafficConfig) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	size := cfg.Size
+	if size == 0 {
+		size = MinPacketSize
+	}
+	wait := cfg.Wait
+	if wait == 0 {
+		wait = DefaultWait
+	}
+
+	id, s, err := e.registerStream()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e.mu.Lock()
+		delete(e.streams, id)
+		e.mu.Unlock()
+	}()
+
+	r := &Report{}
+	buf := make([]byte, size)
+	interval := time.Duration(float64(time.Second) / cfg.Rate)
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var ctxErr error
+send:
+	for seq := uint64(0); ; seq++ {
+		if cfg.Count > 0 && r.Sent >= cfg.Count {
+			break
+		}
+		next := start.Add(time.Duration(seq) * interval)
+		if cfg.Count <= 0 && next.Sub(start) >= cfg.Duration {
+			break
+		}
+
+		if d := time.Until(next); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-ctx.Done():
+				ctxErr = ctx.Err()
+				break send
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			ctxErr = err
+			break
+		}
+
+		putProbe(buf, t.UEIP, cfg.Target, id, seq, time.Now())
+		if _, err := e.Conn.WriteToGTP(t.OutgoingTEID, buf, t.PeerAddr); err != nil {
+			r.Elapsed = time.Since(start)
+			return r, err
+		}
+		r.Sent++
+		r.SentBytes += size
+	}
+
+	if cfg.Bidirectional && ctxErr == nil {
+		deadline := time.NewTimer(wait)
+		defer deadline.Stop()
+	wait:
+		for {
+			e.mu.Lock()
+			done := len(s.seen) >= r.Sent
+			e.mu.Unlock()
+			if done {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				ctxErr = ctx.Err()
+				break wait
+			case <-deadline.C:
+				break wait
+			case <-s.notifyCh:
+			}
+		}
+	}
+	r.Elapsed = time.Since(start)
+
+	if cfg.Bidirectional {
+		e.mu.Lock()
+		r.Received = len(s.seen)
+		r.Duplicated = s.duplicated
+		r.MinRTT, r.MaxRTT = s.min, s.max
+		if r.Received > 0 {
+			r.AvgRTT = s.sum / time.Duration(r.Received)
+		}
+		e.mu.Unlock()
+	}
+	return r, ctxErr
+}
@@ -0,0 +1,286 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// getPGWAddr is to get P-GW's S2b address according to APN.
+//
+// DNS should be used in the real case, but here, to keep the example simple,
+// this function just returns IP address configured for the APN.
+func getPGWAddr(apn string) (net.Addr, error) {
+	a, err := cfg.LookupAPN(apn)
+	if err != nil {
+		return nil, err
+	}
+	if a.PGW == "" {
+		return nil, fmt.Errorf("no P-GW configured for APN: %s", apn)
+	}
+	return net.ResolveUDPAddr("udp", net.JoinHostPort(a.PGW, "2123"))
+}
+
+// dispatch sends the subscribers in db to attachCh, which will be handled in handleAttach().
+func dispatch(db *config.SubscriberDB) {
+	for _, sub := range db.Subscribers {
+		time.Sleep(100 * time.Millisecond)
+		attachCh <- sub
+	}
+}
+
+// handleAttach creates the PDN connection of the subscriber with P-GW over S2b, and
+// starts the mocked UE sending the payload to P-GW.
+// in the real case this should be called after the UE is authenticated over SWu and SWm.
+func handleAttach(s2bConn *v2.Conn, sub *config.Subscriber) error {
+	raddr, err := getPGWAddr(sub.APN)
+	if err != nil {
+		return err
+	}
+	cIP, err := cfg.IP("s2bc")
+	if err != nil {
+		return err
+	}
+	uIP, err := cfg.IP("s2bu")
+	if err != nil {
+		return err
+	}
+
+	cFTEID := s2bConn.NewFTEID(v2.IFTypeS2bePDGGTPC, cIP, "")
+	uFTEID := s2bConn.NewFTEID(v2.IFTypeS2bUePDGGTPU, uIP, "")
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextToBeCreated, 5).
+		FTEID(uFTEID).
+		QoS(&v2.QoSProfile{PCI: true, PL: 2, PVI: true, QCI: 0xff}).
+		Build()
+	if err != nil {
+		return err
+	}
+	session, err := s2bConn.CreateSession(
+		raddr,
+		ies.NewIMSI(sub.IMSI),
+		ies.NewMSISDN(sub.MSISDN),
+		ies.NewMobileEquipmentIdentity(sub.IMEI),
+		ies.NewServingNetwork(subscribers.MCC, subscribers.MNC),
+		ies.NewRATType(v2.RATTypeWLAN),
+		cFTEID,
+		ies.NewAccessPointName(sub.APN),
+		ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+		ies.NewPDNType(v2.PDNTypeIPv4),
+		ies.NewPDNAddressAllocation("0.0.0.0"),
+		ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+		ies.NewAggregateMaximumBitRate(0x11111111, 0x22222222),
+		brCtx,
+		// the UE has no way to receive PCO over IKEv2, so ask for the DNS servers in APCO.
+		ies.NewAdditionalProtocolConfigurationOptions(
+			v2.ConfigProtocolPPPWithIP,
+			ies.NewConfigurationProtocolOption(v2.ContIDDNSServerIPv4AddressRequest, nil),
+		),
+	)
+	if err != nil {
+		return err
+	}
+	session.AddTEID(uFTEID.InterfaceType(), uFTEID.TEID())
+	session.GetDefaultBearer().SetIncomingTEID(uFTEID.TEID())
+	session.GetDefaultBearer().APN = sub.APN
+	s2bConn.AddSession(session)
+
+	loggerCh <- fmt.Sprintf("Sent Create Session Request to %s for %s", raddr, session.IMSI)
+
+	// wait for Create Session Response to be handled.
+	if _, err := session.WaitMessage(cfg.Timers.ResponseTimeout); err != nil {
+		s2bConn.RemoveSession(session)
+		return err
+	}
+	if !session.IsActive() {
+		return fmt.Errorf("failed to create session for %s", session.IMSI)
+	}
+
+	go sendEcho(session)
+	return nil
+}
+
+// handleDetach deletes the PDN connection of the subscriber with P-GW.
+func handleDetach(s2bConn *v2.Conn, session *v2.Session) error {
+	teid, err := session.GetTEID(v2.IFTypeS2bPGWGTPC)
+	if err != nil {
+		return err
+	}
+	session.Sequence++
+	dsReq := messages.NewDeleteSessionRequest(
+		teid, session.Sequence,
+		ies.NewEPSBearerID(session.GetDefaultBearer().EBI),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timers.ResponseTimeout)
+	defer cancel()
+	dsRsp, err := v2.Exchange[*messages.DeleteSessionResponse](ctx, s2bConn, session.PeerAddr, dsReq)
+	if dsRsp != nil {
+		loggerCh <- fmt.Sprintf("Received %s from %s", dsRsp.MessageTypeName(), session.PeerAddr)
+	}
+
+	// even the cause indicates failure, session should be removed locally.
+	if err := session.Deactivate(); err != nil {
+		errCh <- err
+	}
+	s2bConn.RemoveSession(session)
+	return err
+}
+
+func handleCreateSessionResponse(s2bConn *v2.Conn, pgwAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), pgwAddr)
+
+	session, err := s2bConn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+
+	// assert type to refer to the struct field specific to the message.
+	// in general, no need to check if it can be type-asserted, as long as the MessageType is
+	// specified correctly in AddHandler().
+	csRspFromPGW := msg.(*messages.CreateSessionResponse)
+
+	// pass the response to handleAttach() anyway to let it go without waiting for the timeout.
+	defer func() {
+		if err := v2.PassMessageTo(session, msg, cfg.Timers.ResponseTimeout); err != nil {
+			errCh <- err
+		}
+	}()
+
+	// check Cause value first.
+	if ie := csRspFromPGW.Cause; ie != nil {
+		if cause := ie.Cause(); cause != v2.CauseRequestAccepted && cause != v2.CauseNewPDNTypeDueToNetworkPreference {
+			s2bConn.RemoveSession(session)
+			return &v2.ErrCauseNotOK{
+				MsgType: csRspFromPGW.MessageTypeName(),
+				Cause:   cause,
+				Msg:     fmt.Sprintf("subscriber: %s", session.IMSI),
+			}
+		}
+	} else {
+		s2bConn.RemoveSession(session)
+		return &v2.ErrRequiredIEMissing{Type: ies.Cause}
+	}
+
+	bearer := session.GetDefaultBearer()
+	if ie := csRspFromPGW.PAA; ie != nil {
+		bearer.SubscriberIP = ie.IPAddress()
+	} else {
+		s2bConn.RemoveSession(session)
+		return &v2.ErrRequiredIEMissing{Type: ies.PDNAddressAllocation}
+	}
+	if ie := csRspFromPGW.PGWS5S8FTEIDC; ie != nil {
+		session.AddTEID(ie.InterfaceType(), ie.TEID())
+	} else {
+		s2bConn.RemoveSession(session)
+		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+	if brCtxIE := csRspFromPGW.BearerContextsCreated; brCtxIE != nil {
+		for _, ie := range brCtxIE.BearerContext() {
+			switch ie.Type {
+			case ies.EPSBearerID:
+				bearer.EBI = ie.EPSBearerID()
+			case ies.FullyQualifiedTEID:
+				if ie.InterfaceType() != v2.IFTypeS2bUPGWGTPU {
+					continue
+				}
+				session.AddTEID(ie.InterfaceType(), ie.TEID())
+				bearer.SetOutgoingTEID(ie.TEID())
+				bearer.SetRemoteAddress(&net.UDPAddr{IP: net.ParseIP(ie.IPAddress()), Port: 2152})
+			case ies.ChargingID:
+				bearer.ChargingID = ie.ChargingID()
+			}
+		}
+	} else {
+		s2bConn.RemoveSession(session)
+		return &v2.ErrRequiredIEMissing{Type: ies.BearerContext}
+	}
+	if bearer.RemoteAddress() == nil {
+		s2bConn.RemoveSession(session)
+		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+
+	// the DNS servers are told to the UE over IKEv2 in the real case.
+	var dns []string
+	if ie := csRspFromPGW.APCO; ie != nil {
+		if apco := ie.AdditionalProtocolConfigurationOptions(); apco != nil {
+			for _, opt := range apco.ConfigurationProtocolOptions {
+				if opt.ProtocolID == v2.ContIDDNSServerIPv4AddressRequest && len(opt.Contents) == 4 {
+					dns = append(dns, net.IP(opt.Contents).String())
+				}
+			}
+		}
+	}
+
+	if err := session.Activate(); err != nil {
+		s2bConn.RemoveSession(session)
+		return err
+	}
+
+	pgwTEID, err := session.GetTEID(v2.IFTypeS2bPGWGTPC)
+	if err != nil {
+		return err
+	}
+	epdgTEID, err := session.GetTEID(v2.IFTypeS2bePDGGTPC)
+	if err != nil {
+		return err
+	}
+	loggerCh <- fmt.Sprintf(
+		"Session created with P-GW for Subscriber: %s;\n\tS2b P-GW: %s, TEID->: %#x, TEID<-: %#x\n\tSubscriber IP: %s, DNS: %s, S2b-U P-GW: %s, TEID->: %#x, TEID<-: %#x",
+		session.IMSI, pgwAddr, pgwTEID, epdgTEID,
+		bearer.SubscriberIP, strings.Join(dns, ","), bearer.RemoteAddress(), bearer.OutgoingTEID(), bearer.IncomingTEID(),
+	)
+	return nil
+}
+
+// payload is ICMP Echo to 8.8.8.8 over IP(src will be replaced), checksum is invalid.
+var payload = []byte{
+	// IP
+	0x45, 0x00, 0x00, 0x54, 0x00, 0x01, 0x40, 0x00, 0x3f, 0x01, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef,
+	0x08, 0x08, 0x08, 0x08,
+	// ICMP
+	0x08, 0x00, 0x93, 0x6a, 0x00, 0x01, 0x00, 0x01, 0xdf, 0xd5, 0x2c, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x99, 0xea, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x11, 0x12, 0x13,
+	0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20, 0x21, 0x22, 0x23,
+	0x24, 0x25, 0x26, 0x27, 0x28, 0x29, 0x2a, 0x2b, 0x2c, 0x2d, 0x2e, 0x2f, 0x30, 0x31, 0x32, 0x33,
+	0x34, 0x35, 0x36, 0x37,
+}
+
+// sendEcho sends the payload from the mocked UE through the S2b-U tunnel every 3
+// seconds, until the session is deleted.
+func sendEcho(session *v2.Session) {
+	bearer := session.GetDefaultBearer()
+	pkt := make([]byte, len(payload))
+	copy(pkt, payload)
+	copy(pkt[12:16], net.ParseIP(bearer.SubscriberIP).To4())
+
+	for session.IsActive() {
+		if _, err := uConn.WriteToGTP(bearer.OutgoingTEID(), pkt, bearer.RemoteAddress()); err != nil {
+			errCh <- err
+			return
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
+
+// receive prints the payload of the packets received from P-GW.
+func receive() {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, _, err := uConn.ReadFromGTP(buf)
+		if err != nil {
+			return
+		}
+		loggerCh <- fmt.Sprintf("Received from %s: %x", raddr, buf[:n])
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// gtpuPort is the well-known port of GTP-U, which is used to send T-PDU to SGSN.
+const gtpuPort = 2152
+
+var (
+	loggerCh = make(chan string)
+	errCh    = make(chan error)
+
+	uConn    *v1.UPlaneConn
+	addrPool *ipPool
+
+	// lastChargingID is incremented to allocate the Charging ID to each PDP context.
+	lastChargingID uint32
+
+	// supervised is the set of SGSNs to which the Echo Request is sent periodically.
+	supervised   = map[string]bool{}
+	supervisedMu sync.Mutex
+)
+
+// ipPool is a dead simple pool of IPv4 end-user addresses.
+//
+// In the real case, GGSN may ask RADIUS or DHCP server for the address, but here, to
+// keep the example simple, the addresses are just picked from the subnet in order.
+type ipPool struct {
+	mu      sync.Mutex
+	network *net.IPNet
+	used    map[string]bool
+}
+
+func newIPPool(cidr string) (*ipPool, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if network.IP.To4() == nil {
+		return nil, fmt.Errorf("pool %s is not IPv4", cidr)
+	}
+	return &ipPool{network: network, used: map[string]bool{}}, nil
+}
+
+// allocate picks an address that is not in use, excluding network and broadcast address.
+func (p *ipPool) allocate() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	base := binary.BigEndian.Uint32(p.network.IP.To4())
+	ones, bits := p.network.Mask.Size()
+	size := uint32(1) << uint(bits-ones)
+	for n := uint32(1); n < size-1; n++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, base+n)
+		if !p.used[ip.String()] {
+			p.used[ip.String()] = true
+			return ip.String(), nil
+		}
+	}
+	return "", errors.New("all addresses in the pool are occupied")
+}
+
+// assign marks the static address requested by MS as used.
+func (p *ipPool) assign(addr string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.used[addr] {
+		return fmt.Errorf("address %s is already in use", addr)
+	}
+	p.used[addr] = true
+	return nil
+}
+
+func (p *ipPool) release(addr string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.used, addr)
+}
+
+func handleCreatePDPContextRequest(c v1.Conn, sgsnAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), sgsnAddr)
+
+	// assert type to refer to the struct field specific to the message.
+	// in general, no need to check if it can be type-asserted, as long as the MessageType is
+	// specified correctly in AddHandler().
+	conn := c.(*v1.CPlaneConn)
+	req := msg.(*messages.CreatePDPContextRequest)
+
+	// the TEID to be used in the response is the one given by SGSN. if it is missing,
+	// the response is sent with TEID=0 as specified in TS 29.060.
+	var sgsnTEIDC uint32
+	if ie := req.TEIDCPlane; ie != nil {
+		sgsnTEIDC = ie.TEID()
+	}
+	reject := func(cause uint8, err error) error {
+		rsp := messages.NewCreatePDPContextResponse(sgsnTEIDC, 0, ies.NewCause(cause))
+		if rerr := conn.RespondTo(sgsnAddr, req, rsp); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	// keep session information retrieved from the message.
+	session := v1.NewSession(sgsnAddr, &v1.Subscriber{Location: &v1.Location{}})
+	pdp := session.GetDefaultPDPContext()
+	if ie := req.IMSI; ie != nil {
+		imsi := ie.IMSI()
+		session.IMSI = imsi
+
+		// remove previous session for the same subscriber if exists.
+		sess, err := conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			if err != v1.ErrUnknownIMSI {
+				return errors.Wrap(err, "got something unexpected")
+			}
+			// whole new session. just ignore.
+		} else {
+			removeSession(conn, sess)
+		}
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.IMSI})
+	}
+	if ie := req.MSISDN; ie != nil {
+		session.MSISDN = ie.MSISDN()
+	}
+	if ie := req.RAI; ie != nil {
+		session.MCC = ie.MCC()
+		session.MNC = ie.MNC()
+		session.LAC = ie.LAC()
+		session.RAC = ie.RAC()
+	}
+	if ie := req.RATType; ie != nil {
+		session.RATType = ie.RATType()
+	}
+	if ie := req.NSAPI; ie != nil {
+		pdp.NSAPI = ie.NSAPI()
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.NSAPI})
+	}
+	if ie := req.APN; ie != nil {
+		pdp.APN = ie.AccessPointName()
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.AccessPointName})
+	}
+	if ie := req.QoSProfile; ie != nil {
+		pdp.QoSProfile = ie.QoSProfile()
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.QoSProfile})
+	}
+	if ie := req.TEIDCPlane; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpSGSNGTPC, ie.TEID())
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.TEIDCPlane})
+	}
+	if ie := req.TEIDDataI; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpSGSNGTPU, ie.TEID())
+		pdp.SetOutgoingTEID(ie.TEID())
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.TEIDDataI})
+	}
+	if ie := req.SGSNAddressForUserTraffic; ie != nil {
+		pdp.SetRemoteAddress(&net.UDPAddr{IP: ie.GSNAddressIP(), Port: gtpuPort})
+	} else {
+		return reject(v1.ResCauseMandatoryIEMissing, &v1.ErrRequiredIEMissing{Type: ies.GSNAddress})
+	}
+
+	// use the static address if MS has one, otherwise allocate from the pool.
+	if ip := staticAddress(req.EndUserAddress); ip != "" {
+		if err := addrPool.assign(ip); err != nil {
+			return reject(v1.ResCauseNoResourcesAvailable, err)
+		}
+		pdp.SubscriberIP = ip
+	} else {
+		ip, err := addrPool.allocate()
+		if err != nil {
+			return reject(v1.ResCauseAllDynamicPDPAddressesAreOccupied, err)
+		}
+		pdp.SubscriberIP = ip
+	}
+	pdp.ChargingID = atomic.AddUint32(&lastChargingID, 1)
+
+	ggsnTEIDC := conn.NewTEID(v1.IFTypeGnGpGGSNGTPC)
+	ggsnTEIDU := conn.NewTEID(v1.IFTypeGnGpGGSNGTPU)
+	session.AddTEID(v1.IFTypeGnGpGGSNGTPC, ggsnTEIDC)
+	session.AddTEID(v1.IFTypeGnGpGGSNGTPU, ggsnTEIDU)
+	pdp.SetIncomingTEID(ggsnTEIDU)
+
+	cIP := strings.Split(conn.LocalAddr().String(), ":")[0]
+	uIP := strings.Split(uConn.LocalAddr().String(), ":")[0]
+	rsp := messages.NewCreatePDPContextResponse(
+		sgsnTEIDC, 0,
+		ies.NewCause(v1.ResCauseRequestAccepted),
+		ies.NewReorderingRequired(false),
+		ies.NewRecovery(conn.Restarts()),
+		ies.NewTEIDDataI(ggsnTEIDU),
+		ies.NewTEIDCPlane(ggsnTEIDC),
+		ies.NewChargingID(pdp.ChargingID),
+		ies.NewEndUserAddress(pdp.SubscriberIP),
+		ies.NewGSNAddress(cIP),
+		ies.NewGSNAddress(uIP),
+		ies.NewQoSProfile(pdp.QoSProfile),
+	)
+	if err := conn.RespondTo(sgsnAddr, req, rsp); err != nil {
+		addrPool.release(pdp.SubscriberIP)
+		return err
+	}
+
+	// don't forget to activate and add session created to the session list
+	if err := session.Activate(); err != nil {
+		return err
+	}
+	conn.AddSession(session)
+	supervise(conn, sgsnAddr)
+
+	loggerCh <- fmt.Sprintf("PDP context created with SGSN for subscriber: %s, address: %s;\n\tGn-C SGSN: %s, TEID->: %#x, TEID<-: %#x",
+		session.IMSI, pdp.SubscriberIP, sgsnAddr, sgsnTEIDC, ggsnTEIDC,
+	)
+	return nil
+}
+
+// staticAddress returns the address in End User Address IE, or empty string if the
+// IE is not present or the address is requested to be allocated dynamically.
+func staticAddress(ie *ies.IE) string {
+	if ie == nil {
+		return ""
+	}
+	ip := net.ParseIP(ie.IPAddress())
+	if ip == nil || ip.IsUnspecified() {
+		return ""
+	}
+	return ip.String()
+}
+
+func handleUpdatePDPContextRequest(c v1.Conn, sgsnAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), sgsnAddr)
+
+	conn := c.(*v1.CPlaneConn)
+	req := msg.(*messages.UpdatePDPContextRequest)
+
+	session, err := conn.GetSessionByTEID(req.TEID())
+	if err != nil {
+		rsp := messages.NewUpdatePDPContextResponse(0, 0, ies.NewCause(v1.ResCauseNonExistent))
+		if err := conn.RespondTo(sgsnAddr, req, rsp); err != nil {
+			return err
+		}
+		return err
+	}
+	pdp := session.GetDefaultPDPContext()
+
+	// the SGSN may have changed by the inter-SGSN Routeing Area Update.
+	session.PeerAddr = sgsnAddr
+	if ie := req.TEIDCPlane; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpSGSNGTPC, ie.TEID())
+	}
+	if ie := req.TEIDDataI; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpSGSNGTPU, ie.TEID())
+		pdp.SetOutgoingTEID(ie.TEID())
+	}
+	if ie := req.SGSNAddressForUserTraffic; ie != nil {
+		pdp.SetRemoteAddress(&net.UDPAddr{IP: ie.GSNAddressIP(), Port: gtpuPort})
+	}
+	if ie := req.QoSProfile; ie != nil {
+		pdp.QoSProfile = ie.QoSProfile()
+	}
+	if ie := req.RAI; ie != nil {
+		session.MCC = ie.MCC()
+		session.MNC = ie.MNC()
+		session.LAC = ie.LAC()
+		session.RAC = ie.RAC()
+	}
+
+	sgsnTEIDC, err := session.GetTEID(v1.IFTypeGnGpSGSNGTPC)
+	if err != nil {
+		return err
+	}
+	ggsnTEIDC, err := session.GetTEID(v1.IFTypeGnGpGGSNGTPC)
+	if err != nil {
+		return err
+	}
+
+	cIP := strings.Split(conn.LocalAddr().String(), ":")[0]
+	uIP := strings.Split(uConn.LocalAddr().String(), ":")[0]
+	rsp := messages.NewUpdatePDPContextResponse(
+		sgsnTEIDC, 0,
+		ies.NewCause(v1.ResCauseRequestAccepted),
+		ies.NewRecovery(conn.Restarts()),
+		ies.NewTEIDDataI(pdp.IncomingTEID()),
+		ies.NewTEIDCPlane(ggsnTEIDC),
+		ies.NewChargingID(pdp.ChargingID),
+		ies.NewGSNAddress(cIP),
+		ies.NewGSNAddress(uIP),
+		ies.NewQoSProfile(pdp.QoSProfile),
+	)
+	if err := conn.RespondTo(sgsnAddr, req, rsp); err != nil {
+		return err
+	}
+	supervise(conn, sgsnAddr)
+
+	loggerCh <- fmt.Sprintf("PDP context updated for subscriber: %s;\n\tGn-U SGSN: %s, TEID->: %#x",
+		session.IMSI, pdp.RemoteAddress(), pdp.OutgoingTEID(),
+	)
+	return nil
+}
+
+func handleDeletePDPContextRequest(c v1.Conn, sgsnAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), sgsnAddr)
+
+	conn := c.(*v1.CPlaneConn)
+	session, err := conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		rsp := messages.NewDeletePDPContextResponse(0, 0, ies.NewCause(v1.ResCauseNonExistent))
+		if err := conn.RespondTo(sgsnAddr, msg, rsp); err != nil {
+			return err
+		}
+		return err
+	}
+
+	// respond to SGSN with DeletePDPContextResponse.
+	teid, err := session.GetTEID(v1.IFTypeGnGpSGSNGTPC)
+	if err != nil {
+		loggerCh <- errors.Wrap(err, "Error").Error()
+		return nil
+	}
+	rsp := messages.NewDeletePDPContextResponse(teid, 0, ies.NewCause(v1.ResCauseRequestAccepted))
+	if err := conn.RespondTo(sgsnAddr, msg, rsp); err != nil {
+		return err
+	}
+
+	// this GGSN has only one PDP context in a session, so the whole session is removed
+	// regardless of the Teardown Indicator.
+	loggerCh <- fmt.Sprintf("PDP context deleted for subscriber: %s", session.IMSI)
+	removeSession(conn, session)
+	return nil
+}
+
+// removeSession removes the session and releases the end-user address allocated to it.
+func removeSession(c *v1.CPlaneConn, session *v1.Session) {
+	if err := session.Deactivate(); err != nil {
+		loggerCh <- errors.Wrap(err, "Error").Error()
+	}
+	addrPool.release(session.GetDefaultPDPContext().SubscriberIP)
+	c.RemoveSession(session)
+}
+
+// removeSessionsWith removes all the sessions established with the SGSN given.
+func removeSessionsWith(c *v1.CPlaneConn, sgsnAddr net.Addr) {
+	var sessions []*v1.Session
+	for _, sess := range c.Sessions {
+		if sess.PeerAddr.String() == sgsnAddr.String() {
+			sessions = append(sessions, sess)
+		}
+	}
+	for _, sess := range sessions {
+		loggerCh <- fmt.Sprintf("PDP context deleted locally for subscriber: %s", sess.IMSI)
+		removeSession(c, sess)
+	}
+}
+
+// supervise starts sending Echo Request to the SGSN if not yet.
+func supervise(c *v1.CPlaneConn, sgsnAddr net.Addr) {
+	if *echo == 0 {
+		return
+	}
+
+	supervisedMu.Lock()
+	defer supervisedMu.Unlock()
+	if supervised[sgsnAddr.String()] {
+		return
+	}
+	supervised[sgsnAddr.String()] = true
+	c.StartEchoKeepalive(sgsnAddr, *echo, 3)
+}
+
+// serveUPlane terminates GTP-U from SGSN. As the PDN side is not implemented, this just
+// prints the packets received, and responds to the ICMP Echo Request in it.
+func serveUPlane(c *v1.CPlaneConn) {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, teid, err := uConn.ReadFromGTP(buf)
+		if err != nil {
+			return
+		}
+
+		session, err := c.GetSessionByTEID(teid)
+		if err != nil {
+			loggerCh <- fmt.Sprintf("Discarded T-PDU with unknown TEID %#x from %s", teid, raddr)
+			continue
+		}
+		pdp := session.GetDefaultPDPContext()
+		loggerCh <- fmt.Sprintf("Received T-PDU from subscriber %s: %x", session.IMSI, buf[:n])
+
+		rsp := icmpEchoReply(buf[:n])
+		if rsp == nil {
+			continue
+		}
+		if _, err := uConn.WriteToGTP(pdp.OutgoingTEID(), rsp, pdp.RemoteAddress()); err != nil {
+			errCh <- err
+			return
+		}
+	}
+}
+
+// icmpEchoReply returns ICMP Echo Reply for the ICMP Echo Request over IPv4 given.
+// It returns nil if the packet is not ICMP Echo Request.
+func icmpEchoReply(req []byte) []byte {
+	if len(req) < 20 || req[0]>>4 != 4 || req[9] != 1 {
+		return nil
+	}
+	hlen := int(req[0]&0x0f) * 4
+	if len(req) < hlen+8 || req[hlen] != 8 {
+		return nil
+	}
+
+	rsp := make([]byte, len(req))
+	copy(rsp, req)
+	// swap IP
+	copy(rsp[12:16], req[16:20])
+	copy(rsp[16:20], req[12:16])
+
+	// update message type and checksum
+	icmp := rsp[hlen:]
+	icmp[0] = 0
+	icmp[2], icmp[3] = 0, 0
+	binary.BigEndian.PutUint16(icmp[2:4], checksum.Sum(icmp, 0))
+	return rsp
+}
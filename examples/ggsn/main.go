@@ -0,0 +1,125 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command ggsn is a dead simple implementation of GGSN only with GTP-related features.
+//
+// GGSN follows the steps below if there's no unexpected events in the middle. Note
+// that the authentication and the interaction with the PDN is just mocked to make it
+// work in standalone manner.
+//
+// 1. Start listening on Gn-C and Gn-U interfaces.
+//
+// 2. Wait for Create PDP Context Request from SGSN.
+//
+// 3. Allocate an end-user address from the pool specified with pool flag, and send
+// Create PDP Context Response to SGSN if the required IEs are not missing.
+// The Echo Request is sent to the SGSN periodically after that, if the interval is
+// specified with echo flag.
+//
+// 4. If Update PDP Context Request comes from SGSN, update the information of SGSN.
+//
+// 5. If T-PDU comes from SGSN, print the payload of encapsulated packets received,
+// and respond to it with payload(ICMP Echo Reply).
+//
+// 6. If Delete PDP Context Request comes from SGSN, release the end-user address and
+// delete the PDP context.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// command-line arguments
+var (
+	gnc  = flag.String("gnc", "127.0.0.62:2123", "IP Address:Port for Gn-C interface.")
+	gnu  = flag.String("gnu", "127.0.0.6:2152", "IP Address:Port for Gn-U interface.")
+	pool = flag.String("pool", "10.20.0.0/24", "Subnet to allocate end-user addresses from.")
+	echo = flag.Duration("echo", 60*time.Second, "Interval to send Echo Request to SGSN. 0 disables it.")
+)
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[GGSN] ")
+
+	var err error
+	addrPool, err = newIPPool(*pool)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	claddr, err := net.ResolveUDPAddr("udp", *gnc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	uladdr, err := net.ResolveUDPAddr("udp", *gnu)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// start listening on the specified IP:Port.
+	cConn, err := v1.ListenAndServeCPlane(claddr, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cConn.Close()
+	log.Printf("Started serving C-Plane on %s", cConn.LocalAddr())
+
+	uConn, err = v1.ListenAndServeUPlane(uladdr, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer uConn.Close()
+	log.Printf("Started serving U-Plane on %s", uConn.LocalAddr())
+
+	// register handlers for ALL the messages you expect remote endpoint to send.
+	cConn.AddHandlers(map[uint8]v1.HandlerFunc{
+		messages.MsgTypeCreatePDPContextRequest: handleCreatePDPContextRequest,
+		messages.MsgTypeUpdatePDPContextRequest: handleUpdatePDPContextRequest,
+		messages.MsgTypeDeletePDPContextRequest: handleDeletePDPContextRequest,
+	})
+
+	// the PDP contexts with the SGSN that has restarted or is unreachable are no longer valid.
+	cConn.SetPeerRestartHandler(func(c *v1.CPlaneConn, sgsnAddr net.Addr, _, _ uint8) {
+		loggerCh <- "Detected restart of SGSN: " + sgsnAddr.String()
+		removeSessionsWith(c, sgsnAddr)
+	})
+	cConn.SetPathFailureHandler(func(c *v1.CPlaneConn, sgsnAddr net.Addr) {
+		loggerCh <- "Detected path failure with SGSN: " + sgsnAddr.String()
+		removeSessionsWith(c, sgsnAddr)
+	})
+
+	go serveUPlane(cConn)
+
+	for {
+		select {
+		case str := <-loggerCh:
+			log.Printf("%s", str)
+		case err := <-errCh:
+			log.Printf("Warning: %s", err)
+		case <-time.After(10 * time.Second):
+			var activeSubs []string
+			for _, sess := range cConn.Sessions {
+				if !sess.IsActive() {
+					continue
+				}
+				activeSubs = append(activeSubs, sess.IMSI+": "+sess.GetDefaultPDPContext().SubscriberIP)
+			}
+			if len(activeSubs) == 0 {
+				continue
+			}
+
+			log.Println("Active Subscribers:")
+			for _, sub := range activeSubs {
+				log.Printf("\t%s", sub)
+			}
+			activeSubs = nil
+		}
+	}
+}
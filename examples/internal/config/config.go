@@ -0,0 +1,348 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package config provides the YAML configuration shared by the example commands.
+//
+// A configuration looks like the following. Each command uses only the parts it
+// needs, e.g., P-GW uses the IP pools to assign the addresses to the subscribers,
+// and MME uses the P-GW address of the APNs to select the P-GW.
+//
+//	interfaces:
+//	  s5c: 127.0.0.52:2123
+//	  s5u: 127.0.0.4:2152
+//	peers:
+//	  sgw: 127.0.0.51:2123
+//	devices:
+//	  s5c: vrf-s5
+//	  s5u: vrf-s5
+//	ip_pools:
+//	  - name: pool-1
+//	    cidr: 10.10.10.0/24
+//	    ipv6_prefix: 2001:db8:10::/48
+//	lease_file: /var/lib/pgw/leases.json
+//	sgi:
+//	  device: pgw-sgi
+//	  egress: eth0
+//	subscriber_file: subscribers.yml
+//	plmn: "12345"
+//	routes:
+//	  - pgw: 127.0.0.52
+//	  - plmn: "310260"
+//	    mode: home-routed
+//	    pgw: 192.0.2.1
+//	apns:
+//	  - name: some-apn-1.example
+//	    ip_pool: pool-1
+//	    pgw: 127.0.0.52
+//	    dns: [8.8.8.8, 8.8.4.4]
+//	timers:
+//	  response_timeout: 5s
+//	  status_interval: 10s
+//	  idle: 10s
+//	log:
+//	  prefix: "[P-GW] "
+//	  file: /var/log/pgw.log
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/wmnsk/go-gtp/ipam"
+	"github.com/wmnsk/go-gtp/pgwsel"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the configuration of an example command.
+type Config struct {
+	// Interfaces are the local IP:Port of each interface, by the name like "s11".
+	Interfaces map[string]string `yaml:"interfaces"`
+	// Peers are the IP:Port of the peers to connect to, by the name like "sgw".
+	Peers map[string]string `yaml:"peers"`
+	// Devices are the network devices or VRFs to bind each interface to, by the
+	// name like "s11". The interfaces not listed here are not bound to any device.
+	Devices map[string]string `yaml:"devices"`
+	IPPools []*IPPoolConfig   `yaml:"ip_pools"`
+	// LeaseFile is the file to persist the addresses assigned from the IP pools.
+	// The leases are not persisted if empty.
+	LeaseFile string `yaml:"lease_file"`
+	// SessionDir is the directory to save the sessions at shutdown, to restore them
+	// at the next startup. The sessions are not saved if empty.
+	SessionDir string       `yaml:"session_dir"`
+	APNs       []*APNConfig `yaml:"apns"`
+	SGi        SGi          `yaml:"sgi"`
+	Charging   Charging     `yaml:"charging"`
+	// SubscriberFile is the YAML file of the subscribers to be attached by MME.
+	// See SubscriberDB for the format.
+	SubscriberFile string `yaml:"subscriber_file"`
+	// PLMN is the PLMN served by the command as MCC and MNC, which tells the home
+	// subscribers from the roaming ones.
+	PLMN string `yaml:"plmn"`
+	// Routes are the rules to select the P-GW by the home PLMN of the subscribers
+	// and the APN. See pgwsel for how the rule is chosen.
+	Routes []*RouteConfig `yaml:"routes"`
+	Timers Timers         `yaml:"timers"`
+	Log    Log            `yaml:"log"`
+}
+
+// IPPoolConfig is a pool of the IP addresses to be assigned to the subscribers.
+type IPPoolConfig struct {
+	Name string `yaml:"name"`
+	// CIDR is the IPv4 prefix to assign the addresses from.
+	CIDR string `yaml:"cidr"`
+	// IPv6Prefix is the IPv6 prefix to delegate the prefixes from.
+	IPv6Prefix string `yaml:"ipv6_prefix"`
+	// IPv6PrefixLen is the length of the IPv6 prefixes delegated, /64 by default.
+	IPv6PrefixLen int `yaml:"ipv6_prefix_len"`
+}
+
+// APNConfig is an APN served by the network.
+type APNConfig struct {
+	Name string `yaml:"name"`
+	// IPPool is the name of the IP pool to assign the subscriber's IP address from.
+	IPPool string `yaml:"ip_pool"`
+	// PGW is the IP address of the P-GW serving the APN.
+	PGW string `yaml:"pgw"`
+	// DNS is the IPv4 addresses of the DNS servers told to the subscribers of the
+	// APN when requested in APCO.
+	DNS []string `yaml:"dns"`
+}
+
+// RouteConfig is a rule to select the P-GW.
+type RouteConfig struct {
+	// PLMN is the home PLMN of the subscribers as MCC and MNC. Empty matches any.
+	PLMN string `yaml:"plmn"`
+	// APN is the APN to match. Empty matches any.
+	APN string `yaml:"apn"`
+	// Mode is "home-routed" or "local-breakout" for the roaming subscribers,
+	// home-routed by default.
+	Mode string `yaml:"mode"`
+	// PGW is the IP address of the P-GW, optionally with the port.
+	PGW string `yaml:"pgw"`
+}
+
+// SGi is the configuration of the SGi interface of P-GW.
+type SGi struct {
+	// Device is the name of the TUN device to route the packets of the subscribers
+	// through to the host network. SGi is disabled if empty.
+	Device string `yaml:"device"`
+	// Egress is the interface of the host to masquerade the packets from the
+	// subscribers out of. The packets are routed without NAT if empty.
+	Egress string `yaml:"egress"`
+}
+
+// Charging is the configuration of the CDRs generated by P-GW.
+type Charging struct {
+	// File is the file to append the CDRs to in JSON. CDRs are not generated if empty.
+	File string `yaml:"file"`
+	// TimeLimit closes the partial CDRs of the bearers after the duration.
+	TimeLimit time.Duration `yaml:"time_limit"`
+	// VolumeLimit closes the partial CDRs of the bearers after the bytes.
+	VolumeLimit uint64 `yaml:"volume_limit"`
+	// IDFile is the file to persist the Charging IDs reserved, to keep them unique
+	// after restarting. The Charging IDs are allocated from 1 again if empty.
+	IDFile string `yaml:"id_file"`
+}
+
+// Timers are the durations used by the commands.
+type Timers struct {
+	// ResponseTimeout is how long to wait for the response from the peer.
+	ResponseTimeout time.Duration `yaml:"response_timeout"`
+	// StatusInterval is the interval to print the active subscribers.
+	StatusInterval time.Duration `yaml:"status_interval"`
+	// Inactivity is how long to keep the sessions before deleting them.
+	Inactivity time.Duration `yaml:"inactivity"`
+	// Idle is how long the UEs stay in ECM-CONNECTED before going to ECM-IDLE,
+	// and then stay in ECM-IDLE before coming back. UEs never go idle if zero.
+	Idle time.Duration `yaml:"idle"`
+}
+
+// Log is the configuration of the logger.
+type Log struct {
+	Prefix string `yaml:"prefix"`
+	// File is the file to append the logs to. Logs are written to stderr if empty.
+	File string `yaml:"file"`
+}
+
+// Load reads the YAML file at path into cfg and validates it.
+//
+// The fields not in the file keep the values in cfg, so cfg should be filled with the
+// defaults beforehand. If path is empty, only the defaults are validated.
+func Load(path string, cfg *Config) error {
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+	}
+
+	return cfg.validate()
+}
+
+func (c *Config) validate() error {
+	for name, addr := range c.Interfaces {
+		if _, err := net.ResolveUDPAddr("udp", addr); err != nil {
+			return fmt.Errorf("invalid address for interface %s: %w", name, err)
+		}
+	}
+	for name, addr := range c.Peers {
+		if _, err := net.ResolveUDPAddr("udp", addr); err != nil {
+			return fmt.Errorf("invalid address for peer %s: %w", name, err)
+		}
+	}
+
+	pools := map[string]bool{}
+	for _, p := range c.IPPools {
+		pools[p.Name] = true
+	}
+
+	names := map[string]bool{}
+	for _, apn := range c.APNs {
+		if names[apn.Name] {
+			return fmt.Errorf("duplicate APN: %s", apn.Name)
+		}
+		names[apn.Name] = true
+
+		if apn.IPPool != "" && !pools[apn.IPPool] {
+			return fmt.Errorf("unknown IP pool for APN %s: %s", apn.Name, apn.IPPool)
+		}
+		if apn.PGW != "" && net.ParseIP(apn.PGW) == nil {
+			return fmt.Errorf("invalid P-GW address for APN %s: %s", apn.Name, apn.PGW)
+		}
+		for _, dns := range apn.DNS {
+			if net.ParseIP(dns).To4() == nil {
+				return fmt.Errorf("invalid DNS server address for APN %s: %s", apn.Name, dns)
+			}
+		}
+	}
+
+	if _, err := ipam.New(c.poolConfigs(), nil); err != nil {
+		return fmt.Errorf("invalid IP pools: %w", err)
+	}
+	if len(c.Routes) != 0 {
+		if _, err := c.NewPGWSelector(); err != nil {
+			return fmt.Errorf("invalid routes: %w", err)
+		}
+	}
+	return nil
+}
+
+// poolConfigs returns the IP pools with the APNs using them, for ipam.
+func (c *Config) poolConfigs() []*ipam.PoolConfig {
+	pools := make([]*ipam.PoolConfig, len(c.IPPools))
+	for i, p := range c.IPPools {
+		pools[i] = &ipam.PoolConfig{
+			Name:          p.Name,
+			IPv4:          p.CIDR,
+			IPv6:          p.IPv6Prefix,
+			IPv6PrefixLen: p.IPv6PrefixLen,
+		}
+		for _, apn := range c.APNs {
+			if apn.IPPool == p.Name {
+				pools[i].APNs = append(pools[i].APNs, apn.Name)
+			}
+		}
+	}
+	return pools
+}
+
+// Interface returns the IP:Port of the interface.
+func (c *Config) Interface(name string) (string, error) {
+	addr, ok := c.Interfaces[name]
+	if !ok || addr == "" {
+		return "", fmt.Errorf("interface not configured: %s", name)
+	}
+	return addr, nil
+}
+
+// UDPAddr returns the IP:Port of the interface as *net.UDPAddr.
+func (c *Config) UDPAddr(name string) (*net.UDPAddr, error) {
+	addr, err := c.Interface(name)
+	if err != nil {
+		return nil, err
+	}
+	return net.ResolveUDPAddr("udp", addr)
+}
+
+// IP returns the IP address of the interface.
+func (c *Config) IP(name string) (string, error) {
+	addr, err := c.Interface(name)
+	if err != nil {
+		return "", err
+	}
+	ip, _, err := net.SplitHostPort(addr)
+	return ip, err
+}
+
+// PeerAddr returns the IP:Port of the peer as *net.UDPAddr.
+func (c *Config) PeerAddr(name string) (*net.UDPAddr, error) {
+	addr, ok := c.Peers[name]
+	if !ok || addr == "" {
+		return nil, fmt.Errorf("peer not configured: %s", name)
+	}
+	return net.ResolveUDPAddr("udp", addr)
+}
+
+// LookupAPN returns the APN configured.
+func (c *Config) LookupAPN(name string) (*APNConfig, error) {
+	for _, apn := range c.APNs {
+		if apn.Name == name {
+			return apn, nil
+		}
+	}
+	return nil, fmt.Errorf("got unknown APN: %s", name)
+}
+
+// NewIPAM creates the IPAM that assigns the addresses in the IP pools to the
+// subscribers of the APNs, with the leases persisted in the LeaseFile if given.
+func (c *Config) NewIPAM() (*ipam.IPAM, error) {
+	var store ipam.Store
+	if c.LeaseFile != "" {
+		store = ipam.NewFileStore(c.LeaseFile)
+	}
+	return ipam.New(c.poolConfigs(), store)
+}
+
+// NewPGWSelector creates the Selector that selects the P-GW by the Routes, for
+// the subscribers in the PLMN.
+func (c *Config) NewPGWSelector() (*pgwsel.Selector, error) {
+	rules := make([]*pgwsel.Rule, len(c.Routes))
+	for i, r := range c.Routes {
+		mode := pgwsel.HomeRouted
+		if r.Mode != "" {
+			var err error
+			if mode, err = pgwsel.ParseMode(r.Mode); err != nil {
+				return nil, err
+			}
+		}
+		rules[i] = &pgwsel.Rule{PLMN: r.PLMN, APN: r.APN, Mode: mode, PGW: r.PGW}
+	}
+	return pgwsel.New(c.PLMN, rules)
+}
+
+// SetupLog sets the prefix and the output of the standard logger.
+func (c *Config) SetupLog() error {
+	log.SetPrefix(c.Log.Prefix)
+	if c.Log.File == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(c.Log.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	log.SetOutput(f)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SubscriberDB is the static subscriber database used by MME in place of HSS.
+//
+// A subscriber file looks like the following. The subscribers without static_ip
+// get the IP address assigned dynamically by the P-GW.
+//
+//	mcc: "123"
+//	mnc: "45"
+//	subscribers:
+//	  - imsi: "123451234567891"
+//	    msisdn: "8130900000001"
+//	    imei: "123456780000011"
+//	    apn: some-apn-1.example
+//	    static_ip: 10.10.10.101
+//	    tai: 1
+//	    eci: 0x101
+type SubscriberDB struct {
+	// MCC and MNC are the PLMN that the subscribers are attached in.
+	MCC         string        `yaml:"mcc"`
+	MNC         string        `yaml:"mnc"`
+	Subscribers []*Subscriber `yaml:"subscribers"`
+}
+
+// Subscriber is a subscriber in the SubscriberDB.
+type Subscriber struct {
+	IMSI   string `yaml:"imsi"`
+	MSISDN string `yaml:"msisdn"`
+	IMEI   string `yaml:"imei"`
+	// APN is the APN that the subscriber connects to on attach.
+	APN string `yaml:"apn"`
+	// StaticIP is the IPv4 address assigned to the subscriber statically.
+	StaticIP string `yaml:"static_ip"`
+	// TAI and ECI are the location of the subscriber.
+	TAI uint16 `yaml:"tai"`
+	ECI uint32 `yaml:"eci"`
+}
+
+// LoadSubscriberDB reads the subscriber file at path and validates it.
+func LoadSubscriberDB(path string) (*SubscriberDB, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &SubscriberDB{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(db); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	if err := db.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid subscribers in %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Validate checks the subscribers have the mandatory values, and no IMSI nor
+// static IP address is used twice.
+func (db *SubscriberDB) Validate() error {
+	if db.MCC == "" || db.MNC == "" {
+		return fmt.Errorf("MCC and MNC are required")
+	}
+
+	imsis := map[string]bool{}
+	ips := map[string]bool{}
+	for _, s := range db.Subscribers {
+		if !isDigits(s.IMSI) || len(s.IMSI) < 6 || len(s.IMSI) > 15 {
+			return fmt.Errorf("invalid IMSI: %q", s.IMSI)
+		}
+		if imsis[s.IMSI] {
+			return fmt.Errorf("duplicate IMSI: %s", s.IMSI)
+		}
+		imsis[s.IMSI] = true
+
+		if s.MSISDN == "" || s.IMEI == "" || s.APN == "" {
+			return fmt.Errorf("MSISDN, IMEI and APN are required for IMSI %s", s.IMSI)
+		}
+
+		if s.StaticIP == "" {
+			continue
+		}
+		if net.ParseIP(s.StaticIP).To4() == nil {
+			return fmt.Errorf("invalid static IP for IMSI %s: %s", s.IMSI, s.StaticIP)
+		}
+		if ips[s.StaticIP] {
+			return fmt.Errorf("duplicate static IP: %s", s.StaticIP)
+		}
+		ips[s.StaticIP] = true
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
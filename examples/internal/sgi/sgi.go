@@ -0,0 +1,133 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sgi provides the SGi interface shared by the example commands, which
+// routes the packets of the subscribers between the GTP-U tunnels and the host
+// network through a TUN device.
+package sgi
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/tun"
+)
+
+// SGi routes the packets of the subscribers between the GTP-U tunnels and the host
+// network through the TUN device. The sessions are added to and removed from the
+// Bridge by the commands.
+//
+// The host is configured with ip, sysctl and iptables commands, which requires
+// the privilege. The configurations are reverted by Close, except for IP forwarding.
+type SGi struct {
+	Bridge *tun.Bridge
+	// commands to revert the configurations, in the reverse order of execution.
+	undo [][]string
+}
+
+// Setup opens the TUN device in cfg and starts bridging it with uConn.
+//
+// The IPv4 and IPv6 prefixes of the IP pools are routed to the device, and
+// masqueraded out of the egress interface if configured. The error occurred while
+// bridging is sent to errCh.
+func Setup(cfg *config.Config, uConn *v1.UPlaneConn, errCh chan error) (*SGi, error) {
+	dev, err := tun.Open(cfg.SGi.Device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cfg.SGi.Device, err)
+	}
+	s := &SGi{Bridge: tun.NewBridge(uConn, dev)}
+
+	name := dev.Name()
+	if err := s.run([]string{"ip", "link", "set", "dev", name, "up"}, nil); err != nil {
+		s.Close()
+		return nil, err
+	}
+	if err := s.run([]string{"sysctl", "-w", "net.ipv4.ip_forward=1"}, nil); err != nil {
+		s.Close()
+		return nil, err
+	}
+	for _, p := range cfg.IPPools {
+		if p.CIDR == "" {
+			continue
+		}
+		if err := s.route(p.CIDR, name, cfg.SGi.Egress, "ip", "iptables"); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	forwarding := false
+	for _, p := range cfg.IPPools {
+		if p.IPv6Prefix == "" {
+			continue
+		}
+		if !forwarding {
+			if err := s.run([]string{"sysctl", "-w", "net.ipv6.conf.all.forwarding=1"}, nil); err != nil {
+				s.Close()
+				return nil, err
+			}
+			forwarding = true
+		}
+		if err := s.route(p.IPv6Prefix, name, cfg.SGi.Egress, "ip -6", "ip6tables"); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	go func() {
+		if err := s.Bridge.Serve(); err != nil {
+			errCh <- fmt.Errorf("SGi stopped: %w", err)
+		}
+	}()
+	log.Printf("Started routing the subscribers' traffic through %s", name)
+	return s, nil
+}
+
+// route routes the prefix to the device, and masquerades it out of the egress
+// interface if not empty, with the ip and iptables commands for the IP version.
+func (s *SGi) route(prefix, dev, egress, ip, iptables string) error {
+	ipCmd := strings.Fields(ip)
+	if err := s.run(
+		slices.Concat(ipCmd, []string{"route", "replace", prefix, "dev", dev}),
+		slices.Concat(ipCmd, []string{"route", "del", prefix, "dev", dev}),
+	); err != nil {
+		return err
+	}
+
+	if egress == "" {
+		return nil
+	}
+	rule := []string{"POSTROUTING", "-s", prefix, "-o", egress, "-j", "MASQUERADE"}
+	return s.run(
+		append([]string{iptables, "-t", "nat", "-A"}, rule...),
+		append([]string{iptables, "-t", "nat", "-D"}, rule...),
+	)
+}
+
+// run executes the command, and keeps undo to be executed by Close if succeeded.
+func (s *SGi) run(cmd, undo []string) error {
+	if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run %q: %w: %s", strings.Join(cmd, " "), err, out)
+	}
+	if undo != nil {
+		s.undo = append([][]string{undo}, s.undo...)
+	}
+	return nil
+}
+
+// Close reverts the configurations of the host and stops bridging.
+func (s *SGi) Close() {
+	for _, cmd := range s.undo {
+		if out, err := exec.Command(cmd[0], cmd[1:]...).CombinedOutput(); err != nil {
+			log.Printf("Warning: failed to run %q: %s: %s", strings.Join(cmd, " "), err, out)
+		}
+	}
+	if err := s.Bridge.Close(); err != nil {
+		log.Printf("Warning: failed to close SGi: %s", err)
+	}
+}
@@ -2,119 +2,179 @@
 // Use of this source code is governed by a MIT-style license that can be
 // found in the LICENSE file.
 
-// Command mme is a reference implementation of MME with go-gtp.
+// Command mme is a reference implementation of MME with go-gtp, built on the
+// MME simulator in mmesim.
 //
 // MME follows the steps below if there's no unexpected events in the middle.
 // Note that the  S1 and DNS procedures is just mocked to make it work in
 // standalone manner.
 //
-// 1. Exchange Echo to S-GW address specified in command-line argument.
+// 1. Exchange Echo to S-GW address specified in the config.
 //
 // 2. Start dispatching subscribers by sending Create Session Request to S-GW.
-// APN is handled with getPGWIP(), which is hard-coded.
+// P-GW of the APN is selected with getPGWIP(), which looks up the config.
 //
 // 3. Wait for Create Session Response coming from S-GW with Cause="request accepted".
 //
-// 4. Create mocked UE and eNB with the required values set as told by S-GW, start
-// listening on the interface specified with s1enb flag,  and send Modify Bearer Request
-// to S-GW.
+// 4. Send Modify Bearer Request with the F-TEID of eNB on the s1enb interface in
+// the config to S-GW.
 //
-// 5. Wait for Modify Bearer Response coming from S-GW with Cause="request accepted".
+// 5. Wait for Modify Bearer Response coming from S-GW with Cause="request accepted",
+// and create mocked UE and eNB with the required values set as told by S-GW.
 //
 // 6. Start sending payload(ICMP Echo Request) encapsulated with GTPv1-U Header, and printing
 // the payload of encapsulated packets received.
+//
+// 7. After the idle timer expires, release the S1-U bearers with Release Access Bearers
+// Request to move the UE to ECM-IDLE, where the mocked UE stops sending. After the timer
+// expires again, bring it back to ECM-CONNECTED by sending Modify Bearer Request with a
+// new F-TEID of eNB as in Service Request, and repeat.
+//
+// 8. Delete all the sessions with Delete Session Request after the inactivity timer expires.
+//
+// The subscribers are read from the subscriber file given with subscriber_file in the
+// config, in place of HSS. Each subscriber is attached to its APN, with the static IP
+// address requested if configured. See subscribers.yml for the example, which is the
+// same as the defaults.
+//
+// The interfaces, the S-GW, the P-GW of each APN, and the timers can be configured with
+// the YAML file given with config flag. See mme.yml for the example.
 package main
 
 import (
 	"flag"
 	"fmt"
 	"log"
-	"net"
-	"strings"
+	"net/http"
 	"sync"
 	"time"
 
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	"github.com/wmnsk/go-gtp/gtpmgmt"
+	"github.com/wmnsk/go-gtp/mmesim"
 	v2 "github.com/wmnsk/go-gtp/v2"
-	"github.com/wmnsk/go-gtp/v2/ies"
-	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
 // command-line flags.
-var (
-	s11mme = flag.String("s11mme", "127.0.0.111:2123", "local IP:Port on S11 interface.")
-	s11sgw = flag.String("s11sgw", "127.0.0.112:2123", "S-GW's IP:Port on S11 interface.")
-	s1enb  = flag.String("s1enb", "127.0.0.1:2152", "local IP:Port on S1-U of pseudo eNB.")
-)
+var configPath = flag.String("config", "", "path to the YAML config file.")
+
+// cfg is the configuration with the defaults, overridden by the config file.
+//
+// The management API is served on the "mgmt" interface only if it is configured.
+var cfg = &config.Config{
+	Interfaces: map[string]string{
+		"s11":   "127.0.0.111:2123",
+		"s1enb": "127.0.0.1:2152",
+	},
+	Peers: map[string]string{
+		"sgw": "127.0.0.112:2123",
+	},
+	APNs: []*config.APNConfig{
+		{Name: "some-apn-1.example", PGW: "127.0.0.52"},
+		{Name: "some-apn-2.example", PGW: "127.0.0.53"},
+	},
+	Timers: config.Timers{Inactivity: 30 * time.Second, Idle: 10 * time.Second},
+	Log:    config.Log{Prefix: "[MME] "},
+}
+
+// subscribers are the subscribers to be attached if no subscriber file is configured.
+var subscribers = &config.SubscriberDB{
+	MCC: "123", MNC: "45",
+	Subscribers: []*config.Subscriber{
+		{
+			IMSI: "123451234567891", MSISDN: "8130900000001", IMEI: "123456780000011",
+			APN: "some-apn-1.example", StaticIP: "10.10.10.101", TAI: 0x0001, ECI: 0x00000101,
+		},
+		{
+			IMSI: "123451234567892", MSISDN: "8130900000002", IMEI: "123456780000012",
+			APN: "some-apn-2.example", TAI: 0x0002, ECI: 0x00000202,
+		},
+		{
+			IMSI: "123451234567893", MSISDN: "8130900000003", IMEI: "123456780000013",
+			APN: "some-apn-1.example", TAI: 0x0003, ECI: 0x00000303,
+		},
+		{
+			IMSI: "123451234567894", MSISDN: "8130900000004", IMEI: "123456780000014",
+			APN: "some-apn-2.example", TAI: 0x0004, ECI: 0x00000404,
+		},
+		{
+			IMSI: "123451234567895", MSISDN: "8130900000005", IMEI: "123456780000015",
+			APN: "some-apn-1.example", TAI: 0x0005, ECI: 0x00000505,
+		},
+	},
+}
 
 // variables globally shared.
 var (
-	attachCh  = make(chan *v2.Subscriber)
-	createdCh = make(chan string)
-	loggerCh  = make(chan string)
-	errCh     = make(chan error)
+	attachCh = make(chan *subscriber)
+	loggerCh = make(chan string)
+	errCh    = make(chan error)
 
-	once  = sync.Once{}
-	delWG = sync.WaitGroup{}
+	once = sync.Once{}
 )
 
 func main() {
 	flag.Parse()
-	log.SetPrefix("[MME] ")
+	if err := config.Load(*configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.SetupLog(); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.SubscriberFile != "" {
+		db, err := config.LoadSubscriberDB(cfg.SubscriberFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		subscribers = db
+	}
 
-	laddr, err := net.ResolveUDPAddr("udp", *s11mme)
+	laddr, err := cfg.UDPAddr("s11")
+	if err != nil {
+		log.Fatal(err)
+	}
+	raddr, err := cfg.PeerAddr("sgw")
 	if err != nil {
 		log.Fatal(err)
 	}
-	raddr, err := net.ResolveUDPAddr("udp", *s11sgw)
+	enbIP, err := cfg.IP("s1enb")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// setup *Conn first to check if the remote endpoint is awaken.
-	s11Conn, err := v2.Dial(laddr, raddr, 0, errCh)
+	// setup MME first to check if the remote endpoint is awaken.
+	// the messages on S11 are handled by the simulator in mmesim.
+	mme, err := mmesim.Dial(&mmesim.Config{
+		LocalAddr: laddr,
+		SGWAddr:   raddr,
+		ENBIP:     enbIP,
+		SelectPGW: getPGWIP,
+		QoS: &v2.QoSProfile{
+			PL: 2, QCI: 255, MBRUL: 0xffffffff, MBRDL: 0xffffffff, GBRUL: 0xffffffff, GBRDL: 0xffffffff,
+		},
+		Timeout: cfg.Timers.ResponseTimeout,
+	}, errCh)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer s11Conn.Close()
+	defer mme.Close()
 	log.Printf("Connection established with %s", raddr.String())
 
-	// register handlers for ALL the messages you expect remote endpoint to send.
-	// by default, Echo and VersionNotsupported is handled without explicit declaration.
-	s11Conn.AddHandlers(map[uint8]v2.HandlerFunc{
-		messages.MsgTypeCreateSessionResponse: handleCreateSessionResponse,
-		messages.MsgTypeModifyBearerResponse:  handleModifyBearerResponse,
-		messages.MsgTypeDeleteSessionResponse: handleDeleteSessionResponse,
-	})
+	// serve management API to list and delete sessions from outside, if configured.
+	if mgmt, err := cfg.Interface("mgmt"); err == nil {
+		go func() {
+			log.Fatal(http.ListenAndServe(mgmt, gtpmgmt.NewServer(mme.Conn, v2.IFTypeS11S4SGWGTPC)))
+		}()
+		log.Printf("Started serving management API on %s", mgmt)
+	}
 
 	// here you should wait for UEs to come attaching to your network.
-	// in this example, the following five subscribers are to be attached.
+	// in this example, the subscribers in the subscriber database are to be attached.
 	// working as worker-dispatcher is preferable in the real case
-	go dispatch([]*v2.Subscriber{
-		&v2.Subscriber{
-			IMSI: "123451234567891", MSISDN: "8130900000001", IMEI: "123456780000011",
-			Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 0x0001, ECI: 0x00000101},
-		},
-		&v2.Subscriber{
-			IMSI: "123451234567892", MSISDN: "8130900000002", IMEI: "123456780000012",
-			Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 0x0002, ECI: 0x00000202},
-		},
-		&v2.Subscriber{
-			IMSI: "123451234567893", MSISDN: "8130900000003", IMEI: "123456780000013",
-			Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 0x0003, ECI: 0x00000303},
-		},
-		&v2.Subscriber{
-			IMSI: "123451234567894", MSISDN: "8130900000004", IMEI: "123456780000014",
-			Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 0x0004, ECI: 0x00000404},
-		},
-		&v2.Subscriber{
-			IMSI: "123451234567895", MSISDN: "8130900000005", IMEI: "123456780000015",
-			Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 0x0005, ECI: 0x00000505},
-		},
-	})
+	go dispatch(subscribers)
 
-	bearer := v2.NewBearer(5, "", &v2.QoSProfile{
-		PL: 2, QCI: 255, MBRUL: 0xffffffff, MBRDL: 0xffffffff, GBRUL: 0xffffffff, GBRDL: 0xffffffff,
-	})
+	// the timer is not reset by the events, as the idle cycles keep logging.
+	inactivity := time.After(cfg.Timers.Inactivity)
 	for {
 		select {
 		// print logs coming from handlers working background
@@ -128,46 +188,23 @@ func main() {
 		case sub := <-attachCh:
 			log.Printf("Started creating session for subscriber: %s", sub.IMSI)
 			go func() {
-				bearer.APN = "some-apn-1.example"
-				if sub.TAI%2 == 0 {
-					bearer.APN = "some-apn-2.example"
-				}
-				if err := handleAttach(raddr, s11Conn, sub, bearer); err != nil {
+				if err := handleAttach(mme, sub); err != nil {
 					errCh <- err
-					return
 				}
 			}()
-		case imsi := <-createdCh:
-			go func() {
-				sess, err := s11Conn.GetSessionByIMSI(imsi)
-				if err != nil {
-					errCh <- err
-					return
-				}
-
-				enbIP := strings.Split(*s1enb, ":")[0]
-				enbFTEID := s11Conn.NewFTEID(v2.IFTypeS1UeNodeBGTPU, enbIP, "")
-				if err := sess.ModifyBearer(
-					s11Conn, v2.IFTypeS11S4SGWGTPC,
-					ies.NewIndicationFromOctets(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
-					ies.NewBearerContext(ies.NewEPSBearerID(sess.GetDefaultBearer().EBI), enbFTEID),
-				); err != nil {
-					errCh <- err
-					return
-				}
-				sess.AddTEID(enbFTEID.InterfaceType(), enbFTEID.TEID())
-
-				loggerCh <- fmt.Sprintf("Sent Modify Bearer Request for %s", imsi)
-				return
-			}()
-		// delete all the sessions after 30 seconds
-		case <-time.After(30 * time.Second):
-			for _, sess := range s11Conn.Sessions {
-				if err := sess.Delete(s11Conn, v2.IFTypeS11S4SGWGTPC); err != nil {
-					log.Printf("Warning: %s", err)
-				}
+		// delete all the sessions after the inactivity timer expires
+		case <-inactivity:
+			delWG := sync.WaitGroup{}
+			for _, sess := range mme.Conn.ListSessions() {
 				delWG.Add(1)
-				log.Printf("Sent Delete Session Request for %s", sess.IMSI)
+				go func(imsi string) {
+					defer delWG.Done()
+					if err := mme.Detach(imsi); err != nil {
+						errCh <- err
+						return
+					}
+					loggerCh <- fmt.Sprintf("Session deleted with S-GW for Subscriber: %s", imsi)
+				}(sess.IMSI)
 			}
 
 			// invoke goroutine to let the logger work
@@ -8,35 +8,52 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	"github.com/wmnsk/go-gtp/mmesim"
 	v1 "github.com/wmnsk/go-gtp/v1"
 	v2 "github.com/wmnsk/go-gtp/v2"
-	"github.com/wmnsk/go-gtp/v2/ies"
 )
 
 // getPGWIP is to get P-GW's IP address according to APN.
 //
 // DNS should be used in the real case, but here, to keep the example simple,
-// this function just returns IP address hard-coded in apnIPMap.
+// this function just returns IP address configured for the APN.
 func getPGWIP(apn string) (string, error) {
-	apnIPMap := map[string]string{
-		"some-apn-1.example": "127.0.0.52",
-		"some-apn-2.example": "127.0.0.53",
+	a, err := cfg.LookupAPN(apn)
+	if err != nil {
+		return "", err
 	}
-
-	if ip, ok := apnIPMap[apn]; ok {
-		return ip, nil
+	if a.PGW == "" {
+		return "", fmt.Errorf("no P-GW configured for APN: %s", apn)
 	}
-	return "", fmt.Errorf("got unknown APN: %s", apn)
+	return a.PGW, nil
+}
+
+// subscriber is a subscriber to be attached, with the APN and the static IP address
+// in the subscriber database.
+type subscriber struct {
+	*v2.Subscriber
+	apn, staticIP string
 }
 
-// dispatch sends subscribers to attachCh, which will be handled in handleAttach().
-func dispatch(subs []*v2.Subscriber) {
-	for _, sub := range subs {
+// dispatch sends the subscribers in db to attachCh, which will be handled in handleAttach().
+func dispatch(db *config.SubscriberDB) {
+	for _, s := range db.Subscribers {
+		sub := &subscriber{
+			Subscriber: &v2.Subscriber{
+				IMSI: s.IMSI, MSISDN: s.MSISDN, IMEI: s.IMEI,
+				Location: &v2.Location{
+					MCC: db.MCC, MNC: db.MNC, RATType: v2.RATTypeEUTRAN, TAI: s.TAI, ECI: s.ECI,
+				},
+			},
+			apn:      s.APN,
+			staticIP: s.StaticIP,
+		}
+
 		// wait for 0-255ms before sending request (just for a little bit of reality)
 		/*
 			u8buf := make([]byte, 1)
@@ -49,72 +66,67 @@ func dispatch(subs []*v2.Subscriber) {
 	}
 }
 
-// handleAttach is to start the session creation on S11.
+// handleAttach attaches the subscriber with MME, and starts the mocked UE and eNB
+// sending the payload to S-GW told in the procedure.
 // in the real case this should be called after the procedure on S1AP/NAS has been done.
-func handleAttach(raddr net.Addr, c *v2.Conn, sub *v2.Subscriber, br *v2.Bearer) error {
-	// remove previous session for the same subscriber if exists.
-	sess, err := c.GetSessionByIMSI(sub.IMSI)
-	if err != nil {
-		if err == v2.ErrUnknownIMSI {
-			// whole new session. just ignore.
-		}
-		return errors.Wrap(err, "got something unexpected")
+func handleAttach(mme *mmesim.MME, sub *subscriber) error {
+	var sess *v2.Session
+	var err error
+	if sub.staticIP != "" {
+		sess, err = mme.AttachWithIP(sub.Subscriber, sub.apn, sub.staticIP)
 	} else {
-		// send Delete Session Request to cleanup sessions in S/P-GW.
-		if err := sess.Delete(c, v2.IFTypeS11S4SGWGTPC); err != nil {
-			return errors.Wrap(err, "got something unexpected")
-		}
-		c.RemoveSession(sess)
+		sess, err = mme.Attach(sub.Subscriber, sub.apn)
 	}
-
-	pgwAddr, err := getPGWIP(br.APN)
 	if err != nil {
 		return err
 	}
 
-	var pci, pvi uint8
-	if br.PCI {
-		pci = 1
-	}
-	if br.PVI {
-		pvi = 1
-	}
-	localIP := strings.Split(c.LocalAddr().String(), ":")[0]
-	session, err := c.CreateSession(
-		raddr,
-		ies.NewIMSI(sub.IMSI),
-		ies.NewMSISDN(sub.MSISDN),
-		ies.NewMobileEquipmentIdentity(sub.IMEI),
-		ies.NewUserLocationInformation(
-			0, 0, 0, 1, 1, 0, 0, 0,
-			sub.MCC, sub.MCC, sub.LAC, sub.CI, sub.SAI, sub.RAI, sub.TAI, sub.ECI, sub.MeNBI, sub.EMeNBI,
-		),
-		ies.NewRATType(sub.RATType),
-		ies.NewIndicationFromOctets(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
-		c.NewFTEID(v2.IFTypeS11MMEGTPC, localIP, ""),
-		c.NewFTEID(v2.IFTypeS5S8PGWGTPC, pgwAddr, "").WithInstance(1),
-		ies.NewAccessPointName(br.APN),
-		ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
-		ies.NewPDNType(v2.PDNTypeIPv4),
-		ies.NewPDNAddressAllocation("0.0.0.0"),
-		ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
-		ies.NewAggregateMaximumBitRate(0, 0),
-		ies.NewBearerContext(
-			ies.NewEPSBearerID(br.EBI),
-			ies.NewBearerQoS(pci, br.PL, pvi, br.QCI, br.MBRUL, br.MBRDL, br.GBRUL, br.GBRDL),
-		),
-		ies.NewFullyQualifiedCSID(localIP, 1),
-		ies.NewServingNetwork(sub.MCC, sub.MNC),
-		ies.NewUETimeZone(9*time.Hour, 0),
+	br := sess.GetDefaultBearer()
+	loggerCh <- fmt.Sprintf(
+		"Session created with S-GW for Subscriber: %s;\n\tSubscriber IP: %s, S1-U S-GW: %s, TEID->: %#x, TEID<-: %#x",
+		sess.IMSI, br.SubscriberIP, br.RemoteAddress(), br.OutgoingTEID(), br.IncomingTEID(),
 	)
-	if err != nil {
-		return err
+
+	mock := &mockUEeNB{
+		sess:         sess,
+		subscriberIP: br.SubscriberIP,
+		raddr:        br.RemoteAddress(),
+		teidOut:      br.OutgoingTEID(),
+		payload:      payload,
 	}
+	go mock.run(errCh)
 
-	c.AddSession(session)
+	if cfg.Timers.Idle > 0 {
+		go cycleIdle(mme, sess.IMSI)
+	}
 	return nil
 }
 
+// cycleIdle moves the subscriber between ECM-CONNECTED and ECM-IDLE every time the
+// idle timer expires, until the session is deleted.
+// in the real case this is triggered by S1 release on inactivity and Service Request from UE.
+func cycleIdle(mme *mmesim.MME, imsi string) {
+	for {
+		time.Sleep(cfg.Timers.Idle)
+		if err := mme.ReleaseAccessBearers(imsi); err != nil {
+			if err != v2.ErrUnknownIMSI {
+				errCh <- err
+			}
+			return
+		}
+		loggerCh <- fmt.Sprintf("Released S1-U bearers for Subscriber: %s, now in ECM-IDLE", imsi)
+
+		time.Sleep(cfg.Timers.Idle)
+		if err := mme.ServiceRequest(imsi, ""); err != nil {
+			if err != v2.ErrUnknownIMSI {
+				errCh <- err
+			}
+			return
+		}
+		loggerCh <- fmt.Sprintf("Service Request accepted for Subscriber: %s, now in ECM-CONNECTED", imsi)
+	}
+}
+
 var (
 	uConn   *v1.UPlaneConn
 	payload = []byte{ // ICMP Echo to 8.8.8.8 over IP(src will be replaced), checksum is invalid.
@@ -132,6 +144,8 @@ var (
 
 type mockUEeNB struct {
 	laddr, raddr net.Addr
+	// sess is the session of the UE, which does not send while it is in ECM-IDLE.
+	sess *v2.Session
 
 	subscriberIP string
 	teidOut      uint32
@@ -141,7 +155,7 @@ type mockUEeNB struct {
 func (m mockUEeNB) run(errCh chan error) {
 	if uConn == nil {
 		// Listen on eNB S1-U interface.
-		enbUPlaneAddr, err := net.ResolveUDPAddr("udp", *s1enb)
+		enbUPlaneAddr, err := cfg.UDPAddr("s1enb")
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -156,6 +170,10 @@ func (m mockUEeNB) run(errCh chan error) {
 
 	go func(teid uint32, payload []byte, raddr net.Addr) {
 		for {
+			if !m.sess.IsActive() {
+				time.Sleep(3 * time.Second)
+				continue
+			}
 			copy(payload[12:16], net.ParseIP(m.subscriberIP).To4())
 			if _, err := uConn.WriteToGTP(teid, m.payload, raddr); err != nil {
 				errCh <- err
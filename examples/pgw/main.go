@@ -7,38 +7,89 @@
 // P-GW follows the steps below if there's no unexpected events in the middle. Note
 // that the Gx procedure is just mocked to make it work in standalone manner.
 //
-// 1. Wait for Create Session Request from S-GW.
+// 1. Wait for Create Session Request from S-GW, from ePDG over S2b, or from TWAN over S2a.
 //
 // 2. Send Create Session Response to S-GW if the required IEs are not missing, and
-// start listening on the interface specified with s5u flag.
+// start listening on the s5u interface in the config. The DNS servers of the APN are
+// told to ePDG in APCO if requested.
 //
 // 3. If Modify Bearer Request comes from S-GW, update bearer information.
 //
 // 4. If T-PDU comes from S-GW, print the payload of encapsulated packets received,
 // and respond to it with payload(ICMP Echo Reply).
+//
+// 5. If Delete Session Request comes from S-GW, delete the session and print the Secondary
+// RAT usage reported in it, which is relayed by S-GW from MME.
+//
+// With the sgi device in the config, P-GW routes the packets between the tunnels and
+// the host network through the TUN device instead of 4., which requires the privilege
+// to configure the device, the routes and the NAT.
+//
+// With the charging file in the config, P-GW counts the volume of the packets of each
+// session and appends the CDRs to the file when the session is deleted, or when the
+// time or the volume limit is reached.
+//
+// For the UEs with the IPv6 prefixes, P-GW sends Router Advertisement through the tunnel
+// when the session is created and when the UE solicits it, and SGi routes the prefixes.
+//
+// With the charging ID file in the config, P-GW keeps the Charging IDs of the bearers
+// unique after restarting.
+//
+// The interfaces, the IP pools for the subscribers of each APN, and the timers can be
+// configured with the YAML file given with config flag. See pgw.yml for the example.
 package main
 
 import (
 	"flag"
 	"log"
-	"net"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/wmnsk/go-gtp/charging"
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	"github.com/wmnsk/go-gtp/examples/internal/sgi"
+	v1 "github.com/wmnsk/go-gtp/v1"
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
 // command-line arguments
-var (
-	s5c = flag.String("s5c", "127.0.0.52:2123", "IP Address:Port for S5-C interface.")
-	s5u = flag.String("s5u", "127.0.0.4:2152", "IP Address:Port for S5-U interface.")
-)
+var configPath = flag.String("config", "", "path to the YAML config file.")
+
+// cfg is the configuration with the defaults, overridden by the config file.
+var cfg = &config.Config{
+	Interfaces: map[string]string{
+		"s5c": "127.0.0.52:2123",
+		"s5u": "127.0.0.4:2152",
+	},
+	IPPools: []*config.IPPoolConfig{
+		{Name: "pool-1", CIDR: "10.10.10.0/24", IPv6Prefix: "2001:db8:10::/48"},
+	},
+	APNs: []*config.APNConfig{
+		{Name: "some-apn-1.example", IPPool: "pool-1", DNS: []string{"8.8.8.8", "8.8.4.4"}},
+		{Name: "some-apn-2.example", IPPool: "pool-1", DNS: []string{"8.8.8.8", "8.8.4.4"}},
+	},
+	Timers: config.Timers{StatusInterval: 10 * time.Second},
+	Log:    config.Log{Prefix: "[P-GW] "},
+}
 
 func main() {
 	flag.Parse()
-	log.SetPrefix("[P-GW] ")
+	if err := config.Load(*configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.SetupLog(); err != nil {
+		log.Fatal(err)
+	}
+
+	laddr, err := cfg.UDPAddr("s5c")
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	laddr, err := net.ResolveUDPAddr("udp", *s5c)
+	addrs, err = cfg.NewIPAM()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -51,19 +102,76 @@ func main() {
 	defer s5cConn.Close()
 	log.Printf("Started serving on %s", s5cConn.LocalAddr())
 
+	if cfg.Charging.IDFile != "" {
+		a, err := v2.NewChargingIDAllocator(v2.NewChargingIDFileStore(cfg.Charging.IDFile), 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		s5cConn.SetChargingIDAllocator(a)
+	}
+
+	if cfg.SGi.Device != "" {
+		uaddr, err := cfg.UDPAddr("s5u")
+		if err != nil {
+			log.Fatal(err)
+		}
+		uConn, err = v1.ListenAndServeUPlane(uaddr, 0, errCh)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer uConn.Close()
+		advertiser = v1.NewRouterAdvertiser(uConn)
+
+		s, err := sgi.Setup(cfg, uConn, errCh)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer s.Close()
+		sgiBridge = s.Bridge
+		sgiBridge.RouterAdvertiser = advertiser
+	}
+
+	var chargingTick <-chan time.Time
+	if cfg.Charging.File != "" {
+		w, err := charging.NewFileWriter(cfg.Charging.File)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer w.Close()
+		cdrs = charging.NewTracker(w, charging.Triggers{
+			TimeLimit:   cfg.Charging.TimeLimit,
+			VolumeLimit: cfg.Charging.VolumeLimit,
+		})
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		chargingTick = ticker.C
+	}
+
 	// register handlers for ALL the messages you expect remote endpoint to send.
 	s5cConn.AddHandlers(map[uint8]v2.HandlerFunc{
 		messages.MsgTypeCreateSessionRequest: handleCreateSessionRequest,
 		messages.MsgTypeDeleteSessionRequest: handleDeleteSessionRequest,
 	})
+	s5cConn.SetUsageReportHandler(logUsageReports)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
 	for {
 		select {
+		case <-sigCh:
+			log.Println("Shutting down...")
+			return
 		case str := <-loggerCh:
 			log.Printf("%s", str)
 		case err := <-errCh:
 			log.Printf("Warning: %s", err)
-		case <-time.After(10 * time.Second):
+		case <-chargingTick:
+			if err := cdrs.Check(); err != nil {
+				log.Printf("Warning: failed to write CDR: %s", err)
+			}
+		case <-time.After(cfg.Timers.StatusInterval):
 			var activeIMSIs []string
 			for _, sess := range s5cConn.Sessions {
 				if !sess.IsActive() {
@@ -5,13 +5,18 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/wmnsk/go-gtp/charging"
+	"github.com/wmnsk/go-gtp/ipam"
 	v1 "github.com/wmnsk/go-gtp/v1"
-
-	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v1/tun"
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
@@ -20,28 +25,165 @@ import (
 // getSubscriberIP is to get IP address to be assigned to the subscriber.
 //
 // In the real case, P-GW may ask AAA and PCRF retrieve required information for subscriber,
-// but here, to keep the example simple, this just returns subscriber's IP address defined in
-// the map "subIPMap".
-func getSubscriberIP(sub *v2.Subscriber) (string, error) {
-	subIPMap := map[string]string{
-		"123451234567891": "10.10.10.1",
-		"123451234567892": "10.10.10.2",
-		"123451234567893": "10.10.10.3",
-		"123451234567894": "10.10.10.4",
-		"123451234567895": "10.10.10.5",
+// but here, to keep the example simple, this just leases the addresses of the PDN type
+// requested from the IP pool configured for the APN. The subscriber gets the same
+// addresses while the lease is kept.
+//
+// The static IPv4 address in the PDN Address Allocation from MME, which is subscribed
+// in HSS, is leased as it is if it is in the IP pool.
+func getSubscriberIP(imsi, apn string, pdnType uint8, paa *ies.IE) (*ipam.Lease, error) {
+	if paa != nil && pdnType == v2.PDNTypeIPv4 {
+		if addr, err := netip.ParseAddr(paa.IPAddress()); err == nil && addr.Is4() && !addr.IsUnspecified() {
+			return addrs.AllocateStatic(imsi, apn, addr)
+		}
+	}
+	return addrs.Allocate(imsi, apn, ipam.AddressType(pdnType))
+}
+
+// releaseSubscriberIP releases the IP address assigned to the subscriber of the session.
+func releaseSubscriberIP(session *v2.Session) {
+	if err := addrs.Release(session.IMSI, session.GetDefaultBearer().APN); err != nil {
+		loggerCh <- fmt.Sprintf("Failed to release IP address: %s", err)
+	}
+}
+
+// newPAA returns the PDN Address Allocation IE with the addresses in the lease.
+func newPAA(lease *ipam.Lease) *ies.IE {
+	switch lease.Type() {
+	case ipam.IPv4:
+		return ies.NewPDNAddressAllocation(lease.IPv4.String())
+	case ipam.IPv6:
+		return ies.NewPDNAddressAllocationIPv6(lease.IPv6.String())
+	default:
+		return ies.NewPDNAddressAllocationIPv4v6(lease.IPv4.String(), lease.IPv6.String())
+	}
+}
+
+// leaseIPs returns the addresses in the lease to route the packets of the UE with. The
+// IPv6 one is the prefix, in which the UE forms its addresses.
+func leaseIPs(lease *ipam.Lease) []net.IP {
+	var ips []net.IP
+	if lease.IPv4.IsValid() {
+		ips = append(ips, lease.IPv4.AsSlice())
+	}
+	if lease.IPv6.IsValid() {
+		ips = append(ips, lease.IPv6.Addr().AsSlice())
+	}
+	return ips
+}
+
+// newPGWFTEIDs creates the F-TEIDs of P-GW for C-Plane and U-Plane, with the interface
+// types for the access that the peer sends Create Session Request over. The instance of
+// the one for U-Plane is set by BearerContextBuilder.
+func newPGWFTEIDs(c *v2.Conn, peerIFType uint8, cIP, uIP string) (cFTEID, uFTEID *ies.IE) {
+	switch peerIFType {
+	case v2.IFTypeS2bePDGGTPC:
+		return c.NewFTEID(v2.IFTypeS2bPGWGTPC, cIP, "").WithInstance(1),
+			c.NewFTEID(v2.IFTypeS2bUPGWGTPU, uIP, "")
+	case v2.IFTypeS2aTWANGTPC:
+		return c.NewFTEID(v2.IFTypeS2aPGWGTPC, cIP, "").WithInstance(1),
+			c.NewFTEID(v2.IFTypeS2aPGWGTPU, uIP, "")
+	default:
+		return c.NewFTEID(v2.IFTypeS5S8PGWGTPC, cIP, "").WithInstance(1),
+			c.NewFTEID(v2.IFTypeS5S8PGWGTPU, uIP, "")
+	}
+}
+
+// peerIFTypes are the interface types of the peers of P-GW on C-Plane.
+var peerIFTypes = []uint8{v2.IFTypeS5S8SGWGTPC, v2.IFTypeS2bePDGGTPC, v2.IFTypeS2aTWANGTPC}
+
+// getPeerTEID returns the TEID of the peer of the session, which is S-GW, ePDG or TWAN.
+func getPeerTEID(session *v2.Session) (uint32, error) {
+	for _, ifType := range peerIFTypes {
+		if teid, err := session.GetTEID(ifType); err == nil {
+			return teid, nil
+		}
+	}
+	return 0, v2.ErrTEIDNotFound
+}
+
+// newAPCO returns the APCO IE with the DNS server addresses of the APN, if requested
+// in the APCO from ePDG or TWAN. Otherwise it returns nil, which is ignored in the message.
+func newAPCO(apn string, req *ies.IE) *ies.IE {
+	if req == nil {
+		return nil
+	}
+	apnCfg, err := cfg.LookupAPN(apn)
+	if err != nil {
+		return nil
+	}
+	apco := req.AdditionalProtocolConfigurationOptions()
+	if apco == nil {
+		return nil
+	}
+
+	var opts []*ies.ConfigurationProtocolOption
+	for _, opt := range apco.ConfigurationProtocolOptions {
+		if opt.ProtocolID != v2.ContIDDNSServerIPv4AddressRequest {
+			continue
+		}
+		for _, dns := range apnCfg.DNS {
+			opts = append(opts, ies.NewConfigurationProtocolOption(
+				v2.ContIDDNSServerIPv4AddressRequest, net.ParseIP(dns).To4(),
+			))
+		}
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	return ies.NewAdditionalProtocolConfigurationOptions(v2.ConfigProtocolPPPWithIP, opts...)
+}
+
+// startCharging starts counting the packets of the default bearer of the session for
+// the CDRs, if enabled.
+func startCharging(session *v2.Session) error {
+	if cdrs == nil {
+		return nil
 	}
 
-	if ip, ok := subIPMap[sub.IMSI]; ok {
-		return ip, nil
+	bearer := session.GetDefaultBearer()
+	c := v1.NewCounter()
+	uConn.SetCounter(bearer.IncomingTEID(), v1.Uplink, c)
+	uConn.SetSentCounter(bearer.OutgoingTEID(), v1.Downlink, c)
+	return cdrs.Start(&charging.Bearer{
+		IMSI:          session.IMSI,
+		MSISDN:        session.MSISDN,
+		APN:           bearer.APN,
+		EBI:           bearer.EBI,
+		ChargingID:    bearer.ChargingID,
+		ServedAddress: bearer.SubscriberIP,
+	}, c)
+}
+
+// stopCharging stops counting the packets of the session and writes the last CDR.
+func stopCharging(session *v2.Session) {
+	if cdrs == nil {
+		return
+	}
+
+	bearer := session.GetDefaultBearer()
+	uConn.RemoveCounter(bearer.IncomingTEID())
+	uConn.RemoveSentCounter(bearer.OutgoingTEID())
+	if err := cdrs.Stop(session.IMSI, bearer.EBI); err != nil {
+		loggerCh <- fmt.Sprintf("Failed to write CDR: %s", err)
 	}
-	return "", fmt.Errorf("Subscriber %s not found", sub.IMSI)
 }
 
 var (
 	loggerCh = make(chan string)
 	errCh    = make(chan error)
+	echoOnce sync.Once
+
+	// addrs is the IP addresses leased to the subscribers.
+	addrs *ipam.IPAM
+	// cdrs tracks the sessions for the CDRs if configured.
+	cdrs *charging.Tracker
 
 	uConn *v1.UPlaneConn
+	// advertiser sends the Router Advertisements to the UEs with the IPv6 prefixes.
+	advertiser *v1.RouterAdvertiser
+	// sgiBridge routes the packets through SGi if configured.
+	sgiBridge *tun.Bridge
 )
 
 func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
@@ -60,14 +202,16 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		session.IMSI = imsi
 
 		// remove previous session for the same subscriber if exists.
+		// the IP address is kept leased to be assigned again.
 		sess, err := c.GetSessionByIMSI(imsi)
-		if err != nil {
-			if err == v2.ErrUnknownIMSI {
-				// whole new session. just ignore.
-			}
-			return errors.Wrap(err, "got something unexpected")
-		} else {
+		switch err {
+		case nil:
+			stopCharging(sess)
 			c.RemoveSession(sess)
+		case v2.ErrUnknownIMSI:
+			// whole new session. just ignore.
+		default:
+			return fmt.Errorf("got something unexpected: %w", err)
 		}
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.IMSI}
@@ -78,7 +222,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.ErrRequiredIEMissing{Type: ies.MSISDN}
 	}
 	if ie := csReqFromSGW.MEI; ie != nil {
-		session.IMEI = ie.MobileEquipmentIdentity()
+		session.IMEI, session.SVN = ie.IMEI(), ie.SoftwareVersion()
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.MobileEquipmentIdentity}
 	}
@@ -98,68 +242,103 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.RATType}
 	}
+	// S-GW over S5/S8, ePDG over S2b, or TWAN over S2a.
+	var peerIFType uint8
 	if ie := csReqFromSGW.SenderFTEIDC; ie != nil {
-		session.AddTEID(v2.IFTypeS5S8SGWGTPC, ie.TEID())
+		peerIFType = ie.InterfaceType()
+		session.AddTEID(peerIFType, ie.TEID())
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
 	}
 
 	var teidOut uint32
+	var sgwUAddr net.Addr
 	if brCtxIE := csReqFromSGW.BearerContextsToBeCreated; brCtxIE != nil {
-		for _, ie := range brCtxIE.ChildIEs {
+		for _, ie := range brCtxIE.BearerContext() {
 			switch ie.Type {
 			case ies.EPSBearerID:
 				bearer.EBI = ie.EPSBearerID()
 			case ies.FullyQualifiedTEID:
 				session.AddTEID(ie.InterfaceType(), ie.TEID())
 				teidOut = ie.TEID()
+				sgwUAddr = &net.UDPAddr{IP: net.ParseIP(ie.IPAddress()), Port: 2152}
 			}
 		}
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.BearerContext}
 	}
 
-	var err error
-	bearer.SubscriberIP, err = getSubscriberIP(session.Subscriber)
+	pdnType := v2.PDNTypeIPv4
+	if ie := csReqFromSGW.PDNType; ie != nil {
+		pdnType = ie.PDNType()
+	}
+	lease, err := getSubscriberIP(session.IMSI, bearer.APN, pdnType, csReqFromSGW.PAA)
 	if err != nil {
+		cause := v2.CauseAllDynamicAddressesAreOccupied
+		switch {
+		case errors.Is(err, ipam.ErrTypeNotSupported):
+			cause = v2.CausePreferredPDNTypeNotSupported
+		case errors.Is(err, ipam.ErrAddressUnavailable):
+			cause = v2.CauseRequestRejectedReasonNotSpecified
+		}
+		if rerr := c.RespondTo(sgwAddr, csReqFromSGW, messages.NewCreateSessionResponse(
+			0, 0, ies.NewCause(cause, 0, 0, 0, nil),
+		)); rerr != nil {
+			return rerr
+		}
 		return err
 	}
+	if lease.IPv4.IsValid() {
+		bearer.SubscriberIP = lease.IPv4.String()
+	} else {
+		bearer.SubscriberIP = lease.IPv6.Addr().String()
+	}
+	acceptedCause := v2.CauseRequestAccepted
+	if uint8(lease.Type()) != pdnType {
+		acceptedCause = v2.CauseNewPDNTypeDueToNetworkPreference
+	}
 
 	cIP := strings.Split(c.LocalAddr().String(), ":")[0]
-	uIP := strings.Split(*s5u, ":")[0]
-	s5cFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPC, cIP, "").WithInstance(1)
-	s5uFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPU, uIP, "").WithInstance(2)
-	s5sgwTEID, err := session.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	uIP, err := cfg.IP("s5u")
+	if err != nil {
+		return err
+	}
+	s5cFTEID, s5uFTEID := newPGWFTEIDs(c, peerIFType, cIP, uIP)
+	s5sgwTEID, err := session.GetTEID(peerIFType)
+	if err != nil {
+		return err
+	}
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextCreated, bearer.EBI).
+		Cause(v2.CauseRequestAccepted).
+		FTEID(s5uFTEID).
+		AllocateChargingID(c.ChargingIDAllocator(), bearer).
+		Build()
 	if err != nil {
 		return err
 	}
 	csRspFromPGW := messages.NewCreateSessionResponse(
 		s5sgwTEID, 0,
-		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewCause(acceptedCause, 0, 0, 0, nil),
 		s5cFTEID,
-		ies.NewPDNAddressAllocation(bearer.SubscriberIP),
+		newPAA(lease),
 		ies.NewAPNRestriction(v2.APNRestrictionPublic2),
-		ies.NewBearerContext(
-			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
-			ies.NewEPSBearerID(bearer.EBI),
-			s5uFTEID,
-			ies.NewChargingID(bearer.ChargingID),
-		),
+		brCtx,
 	)
 	if csReqFromSGW.SGWFQCSID != nil {
 		csRspFromPGW.PGWFQCSID = ies.NewFullyQualifiedCSID(cIP, 1)
 	}
-	session.AddTEID(v2.IFTypeS5S8PGWGTPC, s5cFTEID.TEID())
-	session.AddTEID(v2.IFTypeS5S8PGWGTPU, s5uFTEID.TEID())
+	csRspFromPGW.APCO = newAPCO(bearer.APN, csReqFromSGW.APCO)
+	csRspFromPGW.SetLength()
+	session.AddTEID(s5cFTEID.InterfaceType(), s5cFTEID.TEID())
+	session.AddTEID(s5uFTEID.InterfaceType(), s5uFTEID.TEID())
+	bearer.SetIncomingTEID(s5uFTEID.TEID())
+	bearer.SetOutgoingTEID(teidOut)
+	bearer.SetRemoteAddress(sgwUAddr)
 
 	if err := c.RespondTo(sgwAddr, csReqFromSGW, csRspFromPGW); err != nil {
 		return err
 	}
-
-	s5pgwTEID, err := session.GetTEID(v2.IFTypeS5S8PGWGTPC)
-	if err != nil {
-		return err
-	}
+	s5pgwTEID := s5cFTEID.TEID()
 
 	// don't forget to activate and add session created to the session list
 	if err := session.Activate(); err != nil {
@@ -167,8 +346,21 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 	}
 	c.AddSession(session)
 
+	peer := "S5C S-GW"
+	switch peerIFType {
+	case v2.IFTypeS2bePDGGTPC:
+		peer = "S2b ePDG"
+	case v2.IFTypeS2aTWANGTPC:
+		peer = "S2a TWAN"
+		// the location of the UE on the trusted WLAN access.
+		if ie := csReqFromSGW.TWANIdentifier; ie != nil {
+			if twan := ie.TWANIdentifier(); twan != nil {
+				peer += fmt.Sprintf("(SSID: %s, BSSID: %s)", twan.SSID, twan.BSSID)
+			}
+		}
+	}
 	if uConn == nil {
-		laddr, err := net.ResolveUDPAddr("udp", *s5u)
+		laddr, err := cfg.UDPAddr("s5u")
 		if err != nil {
 			return err
 		}
@@ -176,34 +368,35 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		if err != nil {
 			return err
 		}
+		advertiser = v1.NewRouterAdvertiser(uConn)
+	}
+	if err := startCharging(session); err != nil {
+		return err
+	}
+	if lease.IPv6.IsValid() {
+		// the UE solicits it again if failed.
+		if err := advertiser.AddTunnel(bearer.IncomingTEID(), lease.IPv6, teidOut, sgwUAddr); err != nil {
+			loggerCh <- fmt.Sprintf("Failed to send Router Advertisement: %s", err)
+		}
 	}
-	loggerCh <- fmt.Sprintf("Started listening on %s", uConn.LocalAddr())
-
-	go func() {
-		buf := make([]byte, 1500)
-		for {
-			n, raddr, _, err := uConn.ReadFromGTP(buf)
-			if err != nil {
-				return
-			}
 
-			rsp := make([]byte, n)
-			// update message type and checksum
-			copy(rsp, buf[:n])
-			rsp[20] = 0
-			rsp[22] = 0x9b
-			// swap IP
-			copy(rsp[12:16], buf[16:20])
-			copy(rsp[16:20], buf[12:16])
-
-			if _, err := uConn.WriteToGTP(teidOut, rsp, raddr); err != nil {
-				return
-			}
+	if sgiBridge != nil {
+		for _, ip := range leaseIPs(lease) {
+			sgiBridge.AddSession(ip, bearer.IncomingTEID(), teidOut, sgwUAddr)
 		}
-	}()
+		loggerCh <- fmt.Sprintf("Session created for subscriber: %s;\n\t%s: %s, TEID->: %#x, TEID<-: %#x, SGi: %s",
+			session.Subscriber.IMSI, peer, sgwAddr, s5sgwTEID, s5pgwTEID, bearer.SubscriberIP,
+		)
+		return nil
+	}
 
-	loggerCh <- fmt.Sprintf("Session created with S-GW for subscriber: %s;\n\tS5C S-GW: %s, TEID->: %#x, TEID<-: %#x",
-		session.Subscriber.IMSI, sgwAddr, s5sgwTEID, s5pgwTEID,
+	echoOnce.Do(func() {
+		loggerCh <- fmt.Sprintf("Started listening on %s", uConn.LocalAddr())
+		go serveEcho(c)
+	})
+
+	loggerCh <- fmt.Sprintf("Session created for subscriber: %s;\n\t%s: %s, TEID->: %#x, TEID<-: %#x",
+		session.Subscriber.IMSI, peer, sgwAddr, s5sgwTEID, s5pgwTEID,
 	)
 	return nil
 }
@@ -227,10 +420,10 @@ func handleDeleteSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return err
 	}
 
-	// respond to S-GW with DeleteSessionResponse.
-	teid, err := session.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	// respond to S-GW or ePDG with DeleteSessionResponse.
+	teid, err := getPeerTEID(session)
 	if err != nil {
-		loggerCh <- errors.Wrap(err, "Error").Error()
+		loggerCh <- fmt.Sprintf("Error: %s", err)
 		return nil
 	}
 	dsr := messages.NewDeleteSessionResponse(
@@ -242,6 +435,71 @@ func handleDeleteSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 	}
 
 	loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", session.IMSI)
+	if lease, err := addrs.Lookup(session.IMSI, session.GetDefaultBearer().APN); err == nil && sgiBridge != nil {
+		for _, ip := range leaseIPs(lease) {
+			sgiBridge.RemoveSession(ip)
+		}
+	}
+	if advertiser != nil {
+		advertiser.RemoveTunnel(session.GetDefaultBearer().IncomingTEID())
+	}
+	stopCharging(session)
+	releaseSubscriberIP(session)
 	c.RemoveSession(session)
 	return nil
 }
+
+// logUsageReports prints the Secondary RAT usage reported for the session, when the
+// session is deleted. In the real case they should be put in the CDRs.
+func logUsageReports(c *v2.Conn, session *v2.Session, reports []*ies.SecondaryRATUsageDataReportPayload) {
+	for _, r := range reports {
+		if !r.IRPGW {
+			continue
+		}
+		loggerCh <- fmt.Sprintf(
+			"Secondary RAT usage for Subscriber: %s; EBI: %d, DL: %d bytes, UL: %d bytes, from %s to %s",
+			session.IMSI, r.EBI, r.UsageDL, r.UsageUL, r.StartTime.Format(time.RFC3339), r.EndTime.Format(time.RFC3339),
+		)
+	}
+}
+
+// serveEcho responds to the ICMP Echo Requests from the subscribers with ICMP Echo
+// Reply through the tunnel of the session the T-PDU comes in, and to the Router
+// Solicitations from the ones with the IPv6 prefixes with Router Advertisement.
+func serveEcho(c *v2.Conn) {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, teid, err := uConn.ReadFromGTP(buf)
+		if err != nil {
+			return
+		}
+
+		session, err := c.GetSessionByTEID(teid)
+		if err != nil {
+			errCh <- fmt.Errorf("got T-PDU with unknown TEID %#x: %w", teid, err)
+			continue
+		}
+		if handled, err := advertiser.HandleUplink(buf[:n], teid); handled {
+			if err != nil {
+				errCh <- fmt.Errorf("failed to send Router Advertisement: %w", err)
+			}
+			continue
+		}
+		if n < 24 || buf[0]>>4 != 4 {
+			continue
+		}
+
+		rsp := make([]byte, n)
+		// update message type and checksum
+		copy(rsp, buf[:n])
+		rsp[20] = 0
+		rsp[22] = 0x9b
+		// swap IP
+		copy(rsp[12:16], buf[16:20])
+		copy(rsp[16:20], buf[12:16])
+
+		if _, err := uConn.WriteToGTP(session.GetDefaultBearer().OutgoingTEID(), rsp, raddr); err != nil {
+			return
+		}
+	}
+}
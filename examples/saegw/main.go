@@ -0,0 +1,162 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command saegw is a dead simple implementation of SAE-GW, the S-GW and P-GW
+// collapsed into one node, only with GTP-related features.
+//
+// As there's no S5/S8 between the S-GW and P-GW, the both parts of a subscriber share
+// one Session on the S11 Conn, which holds the TEIDs allocated for each part, instead
+// of two Sessions on S11 and S5/S8-C associated with each other by IMSI. The messages
+// from MME are answered directly without waiting for the P-GW over the network, and
+// the U-Plane is terminated at S1-U.
+//
+// SAE-GW follows the steps below if there's no unexpected events in the middle. Note
+// that the Gx procedure is just mocked to make it work in standalone manner.
+//
+// 1. Wait for Create Session Request from MME.
+//
+// 2. Assign the IP address to the subscriber from the IP pool for the APN, and respond
+// to MME with Create Session Response with the F-TEIDs of S11 and S1-U.
+//
+// 3. If Modify Bearer Request comes from MME with the F-TEID of eNB, start sending the
+// downlink packets to the eNB.
+//
+// 4. If T-PDU comes from eNB, print the payload of encapsulated packets received, and
+// respond to it with payload(ICMP Echo Reply).
+//
+// 5. If Release Access Bearers Request comes from MME, stop sending the downlink packets
+// until the next Modify Bearer Request, and if Delete Session Request comes, release the
+// IP address assigned.
+//
+// With the sgi device in the config, SAE-GW routes the packets between the tunnels and
+// the host network through the TUN device instead of 4., in the same way as P-GW.
+//
+// The interfaces, the IP pools for the subscribers of each APN, and the timers can be
+// configured with the YAML file given with config flag. See saegw.yml for the example.
+// The defaults let the MME example work with SAE-GW in place of S-GW and P-GW.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	"github.com/wmnsk/go-gtp/examples/internal/sgi"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// command-line arguments
+var configPath = flag.String("config", "", "path to the YAML config file.")
+
+// cfg is the configuration with the defaults, overridden by the config file.
+var cfg = &config.Config{
+	Interfaces: map[string]string{
+		"s11": "127.0.0.112:2123",
+		"s1u": "127.0.0.2:2152",
+	},
+	IPPools: []*config.IPPoolConfig{
+		{Name: "pool-1", CIDR: "10.10.10.0/24", IPv6Prefix: "2001:db8:10::/48"},
+	},
+	APNs: []*config.APNConfig{
+		{Name: "some-apn-1.example", IPPool: "pool-1"},
+		{Name: "some-apn-2.example", IPPool: "pool-1"},
+	},
+	Timers: config.Timers{StatusInterval: 10 * time.Second},
+	Log:    config.Log{Prefix: "[SAE-GW] "},
+}
+
+func main() {
+	flag.Parse()
+	if err := config.Load(*configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.SetupLog(); err != nil {
+		log.Fatal(err)
+	}
+
+	s11, err := cfg.UDPAddr("s11")
+	if err != nil {
+		log.Fatal(err)
+	}
+	s1u, err := cfg.UDPAddr("s1u")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	addrs, err = cfg.NewIPAM()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s11Conn, err := v2.ListenAndServe(s11, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s11Conn.Close()
+	log.Printf("Started serving on %s", s11Conn.LocalAddr())
+
+	uConn, err = v1.ListenAndServeUPlane(s1u, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer uConn.Close()
+	log.Printf("Started listening on %s", uConn.LocalAddr())
+
+	if cfg.SGi.Device != "" {
+		s, err := sgi.Setup(cfg, uConn, errCh)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer s.Close()
+		sgiBridge = s.Bridge
+	} else {
+		go serveEcho(s11Conn)
+	}
+
+	// register handlers for ALL the messages you expect remote endpoint to send.
+	s11Conn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionRequest:        handleCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest:         handleModifyBearerRequest,
+		messages.MsgTypeReleaseAccessBearersRequest: handleReleaseAccessBearersRequest,
+		messages.MsgTypeDeleteSessionRequest:        handleDeleteSessionRequest,
+	})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-sigCh:
+			log.Println("Shutting down...")
+			return
+		case str := <-loggerCh:
+			log.Printf("%s", str)
+		case err := <-errCh:
+			log.Printf("Warning: %s", err)
+		case <-time.After(cfg.Timers.StatusInterval):
+			var activeIMSIs []string
+			for _, sess := range s11Conn.Sessions {
+				if !sess.IsActive() {
+					continue
+				}
+				activeIMSIs = append(activeIMSIs, sess.IMSI)
+			}
+			if len(activeIMSIs) == 0 {
+				continue
+			}
+
+			log.Println("Active Subscribers:")
+			for _, imsi := range activeIMSIs {
+				log.Printf("\t%s", imsi)
+			}
+			activeIMSIs = nil
+		}
+	}
+}
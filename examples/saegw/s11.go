@@ -0,0 +1,400 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/wmnsk/go-gtp/ipam"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/tun"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+var (
+	loggerCh = make(chan string)
+	errCh    = make(chan error)
+
+	// addrs is the IP addresses leased to the subscribers.
+	addrs *ipam.IPAM
+
+	uConn *v1.UPlaneConn
+	// sgiBridge routes the packets through SGi if configured.
+	sgiBridge *tun.Bridge
+)
+
+// getSubscriberIP is to get IP address to be assigned to the subscriber.
+//
+// This leases the addresses of the PDN type requested from the IP pool configured
+// for the APN, or the static IPv4 address requested by MME, in the same way as P-GW.
+func getSubscriberIP(imsi, apn string, pdnType uint8, paa *ies.IE) (*ipam.Lease, error) {
+	if paa != nil && pdnType == v2.PDNTypeIPv4 {
+		if addr, err := netip.ParseAddr(paa.IPAddress()); err == nil && addr.Is4() && !addr.IsUnspecified() {
+			return addrs.AllocateStatic(imsi, apn, addr)
+		}
+	}
+	return addrs.Allocate(imsi, apn, ipam.AddressType(pdnType))
+}
+
+// newPAA returns the PDN Address Allocation IE with the addresses in the lease.
+func newPAA(lease *ipam.Lease) *ies.IE {
+	switch lease.Type() {
+	case ipam.IPv4:
+		return ies.NewPDNAddressAllocation(lease.IPv4.String())
+	case ipam.IPv6:
+		return ies.NewPDNAddressAllocationIPv6(lease.IPv6.String())
+	default:
+		return ies.NewPDNAddressAllocationIPv4v6(lease.IPv4.String(), lease.IPv6.String())
+	}
+}
+
+func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
+
+	// assert type to refer to the struct field specific to the message.
+	// in general, no need to check if it can be type-asserted, as long as the MessageType is
+	// specified correctly in AddHandler().
+	csReqFromMME := msg.(*messages.CreateSessionRequest)
+
+	// keep session information retrieved from the message.
+	// the S-GW part and the P-GW part of the subscriber share this session.
+	session := v2.NewSession(mmeAddr, &v2.Subscriber{Location: &v2.Location{}})
+	bearer := session.GetDefaultBearer()
+	if ie := csReqFromMME.IMSI; ie != nil {
+		imsi := ie.IMSI()
+		session.IMSI = imsi
+
+		// remove previous session for the same subscriber if exists.
+		// the IP address is kept leased to be assigned again.
+		sess, err := s11Conn.GetSessionByIMSI(imsi)
+		switch err {
+		case nil:
+			if sgiBridge != nil {
+				sgiBridge.RemoveSession(net.ParseIP(sess.GetDefaultBearer().SubscriberIP))
+			}
+			s11Conn.RemoveSession(sess)
+		case v2.ErrUnknownIMSI:
+			// whole new session. just ignore.
+		default:
+			return fmt.Errorf("got something unexpected: %w", err)
+		}
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.IMSI}
+	}
+	if ie := csReqFromMME.MSISDN; ie != nil {
+		session.MSISDN = ie.MSISDN()
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.MSISDN}
+	}
+	if ie := csReqFromMME.MEI; ie != nil {
+		session.IMEI, session.SVN = ie.IMEI(), ie.SoftwareVersion()
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.MobileEquipmentIdentity}
+	}
+	if ie := csReqFromMME.APN; ie != nil {
+		bearer.APN = ie.AccessPointName()
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.AccessPointName}
+	}
+	if ie := csReqFromMME.ServingNetwork; ie != nil {
+		session.MCC = ie.MCC()
+		session.MNC = ie.MNC()
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.ServingNetwork}
+	}
+	if ie := csReqFromMME.RATType; ie != nil {
+		session.RATType = ie.RATType()
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.RATType}
+	}
+	if ie := csReqFromMME.SenderFTEIDC; ie != nil {
+		session.AddTEID(v2.IFTypeS11MMEGTPC, ie.TEID())
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+	if brCtxIE := csReqFromMME.BearerContextsToBeCreated; brCtxIE != nil {
+		for _, ie := range brCtxIE.BearerContext() {
+			if ie.Type == ies.EPSBearerID {
+				bearer.EBI = ie.EPSBearerID()
+			}
+		}
+	} else {
+		return &v2.ErrRequiredIEMissing{Type: ies.BearerContext}
+	}
+
+	s11mmeTEID, err := session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	pdnType := v2.PDNTypeIPv4
+	if ie := csReqFromMME.PDNType; ie != nil {
+		pdnType = ie.PDNType()
+	}
+	lease, err := getSubscriberIP(session.IMSI, bearer.APN, pdnType, csReqFromMME.PAA)
+	if err != nil {
+		cause := v2.CauseAllDynamicAddressesAreOccupied
+		switch {
+		case errors.Is(err, ipam.ErrTypeNotSupported):
+			cause = v2.CausePreferredPDNTypeNotSupported
+		case errors.Is(err, ipam.ErrAddressUnavailable):
+			cause = v2.CauseRequestRejectedReasonNotSpecified
+		}
+		if rerr := s11Conn.RespondTo(mmeAddr, csReqFromMME, messages.NewCreateSessionResponse(
+			s11mmeTEID, 0, ies.NewCause(cause, 0, 0, 0, nil),
+		)); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+	if lease.IPv4.IsValid() {
+		bearer.SubscriberIP = lease.IPv4.String()
+	} else {
+		bearer.SubscriberIP = lease.IPv6.Addr().String()
+	}
+	acceptedCause := v2.CauseRequestAccepted
+	if uint8(lease.Type()) != pdnType {
+		acceptedCause = v2.CauseNewPDNTypeDueToNetworkPreference
+	}
+
+	s11IP, err := cfg.IP("s11")
+	if err != nil {
+		return err
+	}
+	s1uIP, err := cfg.IP("s1u")
+	if err != nil {
+		return err
+	}
+	s11sgwFTEID := s11Conn.NewFTEID(v2.IFTypeS11S4SGWGTPC, s11IP, "")
+	s1usgwFTEID := s11Conn.NewFTEID(v2.IFTypeS1USGWGTPU, s1uIP, "")
+	// the F-TEID of the P-GW part is required in the response, though nobody sends
+	// anything to it as there's no S5/S8.
+	s5pgwFTEID := s11Conn.NewFTEID(v2.IFTypeS5S8PGWGTPC, s11IP, "").WithInstance(1)
+	session.AddTEID(s11sgwFTEID.InterfaceType(), s11sgwFTEID.TEID())
+	session.AddTEID(s1usgwFTEID.InterfaceType(), s1usgwFTEID.TEID())
+	session.AddTEID(s5pgwFTEID.InterfaceType(), s5pgwFTEID.TEID())
+	bearer.SetIncomingTEID(s1usgwFTEID.TEID())
+
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextCreated, bearer.EBI).
+		Cause(v2.CauseRequestAccepted).
+		FTEID(s1usgwFTEID).
+		ChargingID(bearer.ChargingID).
+		Build()
+	if err != nil {
+		return err
+	}
+	csRspFromSAEGW := messages.NewCreateSessionResponse(
+		s11mmeTEID, 0,
+		ies.NewCause(acceptedCause, 0, 0, 0, nil),
+		s11sgwFTEID,
+		s5pgwFTEID,
+		newPAA(lease),
+		ies.NewAPNRestriction(v2.APNRestrictionPublic2),
+		brCtx,
+	)
+	if err := s11Conn.RespondTo(mmeAddr, csReqFromMME, csRspFromSAEGW); err != nil {
+		return err
+	}
+
+	// don't forget to activate and add session created to the session list
+	if err := session.Activate(); err != nil {
+		return err
+	}
+	s11Conn.AddSession(session)
+
+	loggerCh <- fmt.Sprintf(
+		"Session created with MME for Subscriber: %s;\n\tS11 MME: %s, TEID->: %#x, TEID<-: %#x\n\tSubscriber IP: %s",
+		session.IMSI, mmeAddr, s11mmeTEID, s11sgwFTEID.TEID(), bearer.SubscriberIP,
+	)
+	return nil
+}
+
+func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
+
+	session, err := s11Conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	bearer := session.GetDefaultBearer()
+
+	// assert type to refer to the struct field specific to the message.
+	// in general, no need to check if it can be type-asserted, as long as the MessageType is
+	// specified correctly in AddHandler().
+	mbReqFromMME := msg.(*messages.ModifyBearerRequest)
+	if brCtxIE := mbReqFromMME.BearerContextsToBeModified; brCtxIE != nil {
+		for _, ie := range brCtxIE.BearerContext() {
+			if ie.Type != ies.FullyQualifiedTEID || ie.InterfaceType() != v2.IFTypeS1UeNodeBGTPU {
+				continue
+			}
+			addr, err := net.ResolveUDPAddr("udp", ie.IPAddress()+":2152")
+			if err != nil {
+				return err
+			}
+			bearer.SetRemoteAddress(addr)
+			bearer.SetOutgoingTEID(ie.TEID())
+		}
+	}
+
+	s11mmeTEID, err := session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	s1uIP, err := cfg.IP("s1u")
+	if err != nil {
+		return err
+	}
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextModified, bearer.EBI).
+		Cause(v2.CauseRequestAccepted).
+		FTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, bearer.IncomingTEID(), s1uIP, "")).
+		Build()
+	if err != nil {
+		return err
+	}
+	mbRspFromSAEGW := messages.NewModifyBearerResponse(
+		s11mmeTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		brCtx,
+	)
+	if err := s11Conn.RespondTo(mmeAddr, msg, mbRspFromSAEGW); err != nil {
+		return err
+	}
+
+	// the session is deactivated while the UE is in ECM-IDLE.
+	if err := session.Activate(); err != nil {
+		return err
+	}
+	if bearer.RemoteAddress() == nil {
+		return nil
+	}
+	if sgiBridge != nil {
+		sgiBridge.AddSession(net.ParseIP(bearer.SubscriberIP), bearer.IncomingTEID(), bearer.OutgoingTEID(), bearer.RemoteAddress())
+	}
+	loggerCh <- fmt.Sprintf(
+		"Started sending downlink for Subscriber: %s;\n\tS1-U eNB: %s, TEID->: %#x, TEID<-: %#x",
+		session.IMSI, bearer.RemoteAddress(), bearer.OutgoingTEID(), bearer.IncomingTEID(),
+	)
+	return nil
+}
+
+func handleReleaseAccessBearersRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
+
+	session, err := s11Conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	s11mmeTEID, err := session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	// forget the eNB until the UE comes back with Service Request. the downlink packets
+	// are discarded in the meantime, as this example does not page the UE.
+	bearer := session.GetDefaultBearer()
+	if sgiBridge != nil {
+		sgiBridge.RemoveSession(net.ParseIP(bearer.SubscriberIP))
+	}
+	bearer.SetOutgoingTEID(0)
+	bearer.SetRemoteAddress(nil)
+	if err := session.Deactivate(); err != nil {
+		return err
+	}
+
+	rabRspFromSAEGW := messages.NewReleaseAccessBearersResponse(
+		s11mmeTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	if err := s11Conn.RespondTo(mmeAddr, msg, rabRspFromSAEGW); err != nil {
+		return err
+	}
+
+	loggerCh <- fmt.Sprintf("Released S1-U bearers for Subscriber: %s, now in ECM-IDLE", session.IMSI)
+	return nil
+}
+
+func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
+
+	session, err := s11Conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		dsr := messages.NewDeleteSessionResponse(
+			0, 0,
+			ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		)
+		if err := s11Conn.RespondTo(mmeAddr, msg, dsr); err != nil {
+			return err
+		}
+		return err
+	}
+
+	s11mmeTEID, err := session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	dsr := messages.NewDeleteSessionResponse(
+		s11mmeTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	if err := s11Conn.RespondTo(mmeAddr, msg, dsr); err != nil {
+		return err
+	}
+
+	bearer := session.GetDefaultBearer()
+	if sgiBridge != nil {
+		sgiBridge.RemoveSession(net.ParseIP(bearer.SubscriberIP))
+	}
+	if err := addrs.Release(session.IMSI, bearer.APN); err != nil {
+		loggerCh <- fmt.Sprintf("Failed to release IP address: %s", err)
+	}
+	s11Conn.RemoveSession(session)
+
+	loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", session.IMSI)
+	return nil
+}
+
+// serveEcho responds to the ICMP Echo Requests from the subscribers with ICMP Echo
+// Reply through the tunnel toward the eNB, in place of the host network.
+//
+// The session is looked up by the TEID of the S1-U on the S11 Conn directly, which
+// is where the S-GW and P-GW parts are linked in SAE-GW.
+func serveEcho(s11Conn *v2.Conn) {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, teid, err := uConn.ReadFromGTP(buf)
+		if err != nil {
+			return
+		}
+		loggerCh <- fmt.Sprintf("Received from %s: %x", raddr, buf[:n])
+
+		session, err := s11Conn.GetSessionByTEID(teid)
+		if err != nil {
+			errCh <- fmt.Errorf("got T-PDU with unknown TEID %#x: %w", teid, err)
+			continue
+		}
+		bearer := session.GetDefaultBearer()
+		if bearer.RemoteAddress() == nil || n < 24 {
+			continue
+		}
+
+		rsp := make([]byte, n)
+		// update message type and checksum
+		copy(rsp, buf[:n])
+		rsp[20] = 0
+		rsp[22] = 0x9b
+		// swap IP
+		copy(rsp[12:16], buf[16:20])
+		copy(rsp[16:20], buf[12:16])
+
+		if _, err := uConn.WriteToGTP(bearer.OutgoingTEID(), rsp, bearer.RemoteAddress()); err != nil {
+			errCh <- err
+		}
+	}
+}
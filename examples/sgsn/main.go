@@ -0,0 +1,170 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command sgsn is a reference implementation of SGSN with go-gtp.
+//
+// SGSN follows the steps below if there's no unexpected events in the middle.
+// Note that the Iu/Gb and DNS procedures are just mocked to make it work in
+// standalone manner.
+//
+// 1. Exchange Echo to GGSN address specified in command-line argument, and start
+// sending Echo Request periodically to supervise the path to GGSN.
+//
+// 2. Start dispatching subscribers by sending Create PDP Context Request to GGSN.
+//
+// 3. Wait for Create PDP Context Response coming from GGSN with Cause="request accepted".
+//
+// 4. Start sending payload(ICMP Echo Request) from the end-user address allocated by
+// GGSN encapsulated with GTPv1-U Header, and printing the payload of encapsulated
+// packets received.
+//
+// 5. Send Delete PDP Context Request for all the subscribers after the duration
+// specified with duration flag, and exit when all of them are responded.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// command-line flags.
+var (
+	gnc      = flag.String("gnc", "127.0.0.61:2123", "local IP:Port on Gn-C interface.")
+	gnu      = flag.String("gnu", "127.0.0.7:2152", "local IP:Port on Gn-U interface.")
+	ggsn     = flag.String("ggsn", "127.0.0.62:2123", "GGSN's IP:Port on Gn-C interface.")
+	apn      = flag.String("apn", "some-apn.example", "APN requested by subscribers.")
+	echo     = flag.Duration("echo", 60*time.Second, "Interval to send Echo Request to GGSN.")
+	duration = flag.Duration("duration", 30*time.Second, "Duration to keep PDP contexts active.")
+)
+
+// variables globally shared.
+var (
+	attachCh = make(chan *v1.Subscriber)
+	loggerCh = make(chan string)
+	errCh    = make(chan error)
+
+	delWG = sync.WaitGroup{}
+)
+
+func main() {
+	flag.Parse()
+	log.SetPrefix("[SGSN] ")
+
+	laddr, err := net.ResolveUDPAddr("udp", *gnc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	raddr, err := net.ResolveUDPAddr("udp", *ggsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// setup *CPlaneConn first to check if the remote endpoint is awaken.
+	gnConn, err := v1.DialCPlane(laddr, raddr, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer gnConn.Close()
+	log.Printf("Connection established with %s", raddr.String())
+
+	// register handlers for ALL the messages you expect remote endpoint to send.
+	// by default, Echo and VersionNotsupported is handled without explicit declaration.
+	gnConn.AddHandlers(map[uint8]v1.HandlerFunc{
+		messages.MsgTypeCreatePDPContextResponse: handleCreatePDPContextResponse,
+		messages.MsgTypeDeletePDPContextResponse: handleDeletePDPContextResponse,
+	})
+
+	// the PDP contexts are no longer valid in GGSN if it has restarted or is unreachable.
+	gnConn.SetPeerRestartHandler(func(c *v1.CPlaneConn, ggsnAddr net.Addr, _, _ uint8) {
+		loggerCh <- "Detected restart of GGSN: " + ggsnAddr.String()
+		removeAllSessions(c)
+	})
+	gnConn.SetPathFailureHandler(func(c *v1.CPlaneConn, ggsnAddr net.Addr) {
+		loggerCh <- "Detected path failure with GGSN: " + ggsnAddr.String()
+		removeAllSessions(c)
+	})
+	gnConn.StartEchoKeepalive(raddr, *echo, 3)
+
+	if err := listenUPlane(gnConn); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Started serving U-Plane on %s", uConn.LocalAddr())
+
+	// here you should wait for MSs to come attaching to your network.
+	// in this example, the following five subscribers are to be attached.
+	go dispatch([]*v1.Subscriber{
+		&v1.Subscriber{
+			IMSI: "123451234567891", MSISDN: "8130900000001", IMEI: "1234567800000101",
+			Location: &v1.Location{MCC: "123", MNC: "45", RATType: v1.RatTypeUTRAN, LAC: 0x0001, RAC: 0x01},
+		},
+		&v1.Subscriber{
+			IMSI: "123451234567892", MSISDN: "8130900000002", IMEI: "1234567800000201",
+			Location: &v1.Location{MCC: "123", MNC: "45", RATType: v1.RatTypeUTRAN, LAC: 0x0001, RAC: 0x02},
+		},
+		&v1.Subscriber{
+			IMSI: "123451234567893", MSISDN: "8130900000003", IMEI: "1234567800000301",
+			Location: &v1.Location{MCC: "123", MNC: "45", RATType: v1.RatTypeUTRAN, LAC: 0x0002, RAC: 0x01},
+		},
+		&v1.Subscriber{
+			IMSI: "123451234567894", MSISDN: "8130900000004", IMEI: "1234567800000401",
+			Location: &v1.Location{MCC: "123", MNC: "45", RATType: v1.RatTypeGERAN, LAC: 0x0002, RAC: 0x02},
+		},
+		&v1.Subscriber{
+			IMSI: "123451234567895", MSISDN: "8130900000005", IMEI: "1234567800000501",
+			Location: &v1.Location{MCC: "123", MNC: "45", RATType: v1.RatTypeGERAN, LAC: 0x0003, RAC: 0x01},
+		},
+	})
+
+	// R99 QoS Profile with Interactive class, which is common for the best-effort traffic.
+	qos := ies.NewQoSProfilePayloadR99(0x02, 3, 1, 0, 0, 0, 0)
+	qos.SetMaximumBitRateKbps(512, 2048)
+
+	expired := time.After(*duration)
+	for {
+		select {
+		// print logs coming from handlers working background
+		case str := <-loggerCh:
+			log.Println(str)
+		// print errors coming from handlers working background
+		// it's better to switch over the error to distinguish fatal ones to others.
+		case err := <-errCh:
+			log.Printf("Warning: %s", err)
+		// handle attach requests
+		case sub := <-attachCh:
+			log.Printf("Started activating PDP context for subscriber: %s", sub.IMSI)
+			go func() {
+				if err := handleAttach(raddr, gnConn, sub, qos); err != nil {
+					errCh <- err
+					return
+				}
+			}()
+		// delete all the PDP contexts after the duration specified
+		case <-expired:
+			for _, sess := range gnConn.Sessions {
+				if !sess.IsActive() {
+					continue
+				}
+				if err := sess.Delete(gnConn, v1.IFTypeGnGpGGSNGTPC, ies.NewTeardownInd(true)); err != nil {
+					log.Printf("Warning: %s", err)
+					continue
+				}
+				delWG.Add(1)
+				log.Printf("Sent Delete PDP Context Request for %s", sess.IMSI)
+			}
+
+			// invoke goroutine to let the logger work
+			go func() {
+				delWG.Wait()
+				log.Fatal("Inactivity timer expired, exitting...")
+			}()
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// gtpuPort is the well-known port of GTP-U, which is used to send T-PDU to GGSN.
+const gtpuPort = 2152
+
+var uConn *v1.UPlaneConn
+
+// dispatch sends subscribers to attachCh, which will be handled in handleAttach().
+func dispatch(subs []*v1.Subscriber) {
+	for _, sub := range subs {
+		// wait for a while before sending request (just for a little bit of reality)
+		time.Sleep(100 * time.Millisecond)
+
+		attachCh <- sub
+	}
+}
+
+// handleAttach is to start the PDP context activation on Gn.
+// in the real case this should be called after the procedure on Iu/Gb has been done.
+func handleAttach(raddr net.Addr, c *v1.CPlaneConn, sub *v1.Subscriber, qos *ies.QoSProfilePayload) error {
+	// remove previous session for the same subscriber if exists.
+	sess, err := c.GetSessionByIMSI(sub.IMSI)
+	if err != nil {
+		if err != v1.ErrUnknownIMSI {
+			return errors.Wrap(err, "got something unexpected")
+		}
+		// whole new session. just ignore.
+	} else {
+		// send Delete PDP Context Request to cleanup the PDP context in GGSN.
+		if err := sess.Delete(c, v1.IFTypeGnGpGGSNGTPC, ies.NewTeardownInd(true)); err != nil {
+			return errors.Wrap(err, "got something unexpected")
+		}
+		c.RemoveSession(sess)
+	}
+
+	cIP := strings.Split(c.LocalAddr().String(), ":")[0]
+	uIP := strings.Split(uConn.LocalAddr().String(), ":")[0]
+	session, err := c.CreatePDPContext(
+		raddr,
+		ies.NewIMSI(sub.IMSI),
+		ies.NewRouteingAreaIdentity(sub.MCC, sub.MNC, sub.LAC, sub.RAC),
+		ies.NewSelectionMode(v1.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+		ies.NewTEIDDataI(c.NewTEID(v1.IFTypeGnGpSGSNGTPU)),
+		ies.NewTEIDCPlane(c.NewTEID(v1.IFTypeGnGpSGSNGTPC)),
+		ies.NewNSAPI(5),
+		ies.NewEndUserAddressIPv4(""),
+		ies.NewAccessPointName(*apn),
+		ies.NewGSNAddress(cIP),
+		ies.NewGSNAddress(uIP),
+		ies.NewMSISDN(sub.MSISDN),
+		ies.NewQoSProfileFromPayload(qos),
+		ies.NewRATType(sub.RATType),
+		ies.NewIMEISV(sub.IMEI),
+	)
+	if err != nil {
+		return err
+	}
+
+	c.AddSession(session)
+	return nil
+}
+
+// listenUPlane starts listening on Gn-U, and prints the payload of encapsulated
+// packets received from GGSN.
+func listenUPlane(c *v1.CPlaneConn) error {
+	laddr, err := net.ResolveUDPAddr("udp", *gnu)
+	if err != nil {
+		return err
+	}
+	uConn, err = v1.ListenAndServeUPlane(laddr, 0, errCh)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, raddr, teid, err := uConn.ReadFromGTP(buf)
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			imsi, err := c.GetIMSIByTEID(teid)
+			if err != nil {
+				loggerCh <- fmt.Sprintf("Discarded T-PDU with unknown TEID %#x from %s", teid, raddr)
+				continue
+			}
+			loggerCh <- fmt.Sprintf("Received from %s for %s: %x", raddr, imsi, buf[:n])
+		}
+	}()
+	return nil
+}
+
+// generateTraffic sends ICMP Echo Request to 8.8.8.8 from the subscriber periodically,
+// as long as the session is active.
+func generateTraffic(session *v1.Session) {
+	pdp := session.GetDefaultPDPContext()
+	src := net.ParseIP(pdp.SubscriberIP).To4()
+	if src == nil {
+		errCh <- fmt.Errorf("subscriber %s has no IPv4 address", session.IMSI)
+		return
+	}
+
+	for seq := uint16(1); session.IsActive(); seq++ {
+		payload := icmpEchoRequest(src, net.IPv4(8, 8, 8, 8).To4(), seq)
+		if _, err := uConn.WriteToGTP(pdp.OutgoingTEID(), payload, pdp.RemoteAddress()); err != nil {
+			errCh <- err
+			return
+		}
+		time.Sleep(3 * time.Second)
+	}
+}
+
+// icmpEchoRequest creates an ICMP Echo Request over IPv4 with the checksums filled.
+func icmpEchoRequest(src, dst net.IP, seq uint16) []byte {
+	data := []byte("go-gtp example sgsn")
+	b := make([]byte, 20+8+len(data))
+
+	// IP
+	b[0] = 0x45
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	binary.BigEndian.PutUint16(b[4:6], seq)
+	b[8] = 64 // TTL
+	b[9] = 1  // ICMP
+	copy(b[12:16], src)
+	copy(b[16:20], dst)
+	binary.BigEndian.PutUint16(b[10:12], checksum.Sum(b[:20], 0))
+
+	// ICMP
+	icmp := b[20:]
+	icmp[0] = 8
+	binary.BigEndian.PutUint16(icmp[4:6], 1)
+	binary.BigEndian.PutUint16(icmp[6:8], seq)
+	copy(icmp[8:], data)
+	binary.BigEndian.PutUint16(icmp[2:4], checksum.Sum(icmp, 0))
+
+	return b
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func handleCreatePDPContextResponse(c v1.Conn, ggsnAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), ggsnAddr)
+
+	// find the session associated with TEID
+	conn := c.(*v1.CPlaneConn)
+	session, err := conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	pdp := session.GetDefaultPDPContext()
+
+	// assert type to refer to the struct field specific to the message.
+	// in general, no need to check if it can be type-asserted, as long as the MessageType is
+	// specified correctly in AddHandler().
+	cpRspFromGGSN := msg.(*messages.CreatePDPContextResponse)
+
+	// check Cause value first.
+	if ie := cpRspFromGGSN.Cause; ie != nil {
+		if cause := ie.Cause(); cause != v1.ResCauseRequestAccepted {
+			conn.RemoveSession(session)
+			return &v1.ErrCauseNotOK{
+				MsgType: cpRspFromGGSN.MessageTypeName(),
+				Cause:   cause,
+				Msg:     fmt.Sprintf("subscriber: %s", session.IMSI),
+			}
+		}
+	} else {
+		conn.RemoveSession(session)
+		return &v1.ErrRequiredIEMissing{Type: ies.Cause}
+	}
+
+	if ie := cpRspFromGGSN.EndUserAddress; ie != nil {
+		pdp.SubscriberIP = ie.IPAddress()
+	} else {
+		conn.RemoveSession(session)
+		return &v1.ErrRequiredIEMissing{Type: ies.EndUserAddress}
+	}
+	if ie := cpRspFromGGSN.ChargingID; ie != nil {
+		pdp.ChargingID = ie.ChargingID()
+	}
+	if ie := cpRspFromGGSN.QoSProfile; ie != nil {
+		// the QoS negotiated by GGSN may differ from the one requested.
+		pdp.QoSProfile = ie.QoSProfile()
+	}
+	if ie := cpRspFromGGSN.TEIDCPlane; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpGGSNGTPC, ie.TEID())
+	} else {
+		conn.RemoveSession(session)
+		return &v1.ErrRequiredIEMissing{Type: ies.TEIDCPlane}
+	}
+	if ie := cpRspFromGGSN.TEIDDataI; ie != nil {
+		session.AddTEID(v1.IFTypeGnGpGGSNGTPU, ie.TEID())
+		pdp.SetOutgoingTEID(ie.TEID())
+	} else {
+		conn.RemoveSession(session)
+		return &v1.ErrRequiredIEMissing{Type: ies.TEIDDataI}
+	}
+	if ie := cpRspFromGGSN.GGSNAddressForUserTraffic; ie != nil {
+		pdp.SetRemoteAddress(&net.UDPAddr{IP: ie.GSNAddressIP(), Port: gtpuPort})
+	} else {
+		conn.RemoveSession(session)
+		return &v1.ErrRequiredIEMissing{Type: ies.GSNAddress}
+	}
+
+	ggsnTEID, err := session.GetTEID(v1.IFTypeGnGpGGSNGTPC)
+	if err != nil {
+		conn.RemoveSession(session)
+		return err
+	}
+	sgsnTEID, err := session.GetTEID(v1.IFTypeGnGpSGSNGTPC)
+	if err != nil {
+		conn.RemoveSession(session)
+		return err
+	}
+
+	if err := session.Activate(); err != nil {
+		conn.RemoveSession(session)
+		return err
+	}
+
+	go generateTraffic(session)
+
+	loggerCh <- fmt.Sprintf(
+		"PDP context activated with GGSN for Subscriber: %s, address: %s;\n\tGn-C GGSN: %s, TEID->: %#x, TEID<-: %#x",
+		session.IMSI, pdp.SubscriberIP, ggsnAddr, ggsnTEID, sgsnTEID,
+	)
+	return nil
+}
+
+func handleDeletePDPContextResponse(c v1.Conn, ggsnAddr net.Addr, msg messages.Message) error {
+	loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), ggsnAddr)
+
+	conn := c.(*v1.CPlaneConn)
+	session, err := conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+
+	if err := session.Deactivate(); err != nil {
+		return err
+	}
+	conn.RemoveSession(session)
+	delWG.Done()
+	loggerCh <- fmt.Sprintf("PDP context deleted with GGSN for Subscriber: %s", session.IMSI)
+	return nil
+}
+
+// removeAllSessions removes all the sessions locally without signalling to GGSN.
+func removeAllSessions(c *v1.CPlaneConn) {
+	var sessions []*v1.Session
+	sessions = append(sessions, c.Sessions...)
+	for _, sess := range sessions {
+		if err := sess.Deactivate(); err != nil {
+			errCh <- err
+		}
+		c.RemoveSession(sess)
+		loggerCh <- fmt.Sprintf("PDP context deleted locally for Subscriber: %s", sess.IMSI)
+	}
+}
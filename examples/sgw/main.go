@@ -9,7 +9,10 @@
 // 1. Start listening on S11 interface.
 //
 // 2. If MME connects to S-GW with Create Session Request, S-GW sends Create Session Request
-// to P-GW whose IP is specified by MME with F-TEID IE.
+// to P-GW whose IP is specified by MME with F-TEID IE. With the routes in the config, the
+// P-GW is selected by the home PLMN of the subscriber and the APN instead, to route the
+// PDN connections of the roaming subscribers to their HPLMN over S8 or break them out
+// locally, and the one specified by MME is used only if no route matches.
 //
 // 3. Wait for Create Session Response coming from P-GW with Cause="request accepted", and
 // other IEs required are properly set.
@@ -22,16 +25,51 @@
 //
 // 6. If some U-Plane message comes from eNB/P-GW, relay it to P-GW/eNB with TEID and IP
 // properly set as told while exchanging the C-Plane signals.
+//
+// 7. If MME sends Modify Bearer Request with another eNB F-TEID on X2/S1-based handover,
+// switch the downlink relay to the target eNB atomically, and send End Marker to the
+// source eNB on the old path.
+//
+// 8. If MME sends Release Access Bearers Request on S1 release, stop relaying the downlink
+// to the eNB until the UE comes back with the next Modify Bearer Request on Service Request.
+//
+// 9. If eNB sends Error Indication on S1-U as it has lost the context of the UE, release
+// the S1-U bearer as on Release Access Bearers Request, and send Downlink Data Notification
+// to MME to page the UE, so that the UE comes back with Service Request.
+//
+// 10. If MME sends Delete Session Request with Secondary RAT Usage Data Reports, relay the
+// ones intended for P-GW in Delete Session Request to P-GW, and print the ones intended
+// for S-GW when the session is deleted.
+//
+// With the session directory in the config, S-GW saves the sessions on SIGINT or SIGTERM,
+// and restores them with the relays on U-Plane at the next startup, so that the sessions
+// established are kept over the restart.
+//
+// The metrics of C-Plane and U-Plane are served to be scraped by Prometheus on the
+// "metrics" interface, e.g., 127.0.0.1:9100, only if it is configured.
+//
+// With the devices in the config, each interface is bound to the network device or VRF,
+// so that S11, S5 and S1-U can be served in the separate routing domains on the host.
+//
+// The interfaces, the routes to P-GWs and the timers can be configured with the YAML file
+// given with config flag. See sgw.yml for the example.
 package main
 
 import (
 	"flag"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	"github.com/wmnsk/go-gtp/gtpmetrics"
+	"github.com/wmnsk/go-gtp/pgwsel"
 	v1 "github.com/wmnsk/go-gtp/v1"
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/messages"
@@ -39,17 +77,33 @@ import (
 
 // command-line arguments and global variables
 var (
-	s11 = flag.String("s11", "127.0.0.112:2123", "local IP:Port on S11 interface.")
-	s5c = flag.String("s5c", "127.0.0.51:2123", "local IP:Port on S5-C interface.")
-	s1u = flag.String("s1u", "127.0.0.2:2152", "local IP:Port on S1-U interface.")
-	s5u = flag.String("s5u", "127.0.0.3:2152", "local IP:Port on S5-U interface.")
+	configPath = flag.String("config", "", "path to the YAML config file.")
 
 	sgw *sGateway
 )
 
+// cfg is the configuration with the defaults, overridden by the config file.
+var cfg = &config.Config{
+	Interfaces: map[string]string{
+		"s11": "127.0.0.112:2123",
+		"s5c": "127.0.0.51:2123",
+		"s1u": "127.0.0.2:2152",
+		"s5u": "127.0.0.3:2152",
+	},
+	Timers: config.Timers{
+		ResponseTimeout: 5 * time.Second,
+		StatusInterval:  10 * time.Second,
+	},
+	Log: config.Log{Prefix: "[S-GW] "},
+}
+
 type sGateway struct {
 	s11Conn, s5cConn *v2.Conn
 	s1uConn, s5uConn *v1.UPlaneConn
+	// pgwSel selects the P-GW if the routes are configured.
+	pgwSel *pgwsel.Selector
+	// ddn sends Downlink Data Notification to MME.
+	ddn *v2.DownlinkDataNotifier
 
 	loggerCh chan string
 	errCh    chan error
@@ -68,6 +122,7 @@ func newSGW(s11, s5c, s1u, s5u net.Addr) (*sGateway, error) {
 		return nil, err
 	}
 	log.Printf("Started serving on %s", s.s11Conn.LocalAddr())
+	s.ddn = v2.NewDownlinkDataNotifier(s.s11Conn)
 
 	s.s5cConn, err = v2.ListenAndServe(s5c, 0, s.errCh)
 	if err != nil {
@@ -85,23 +140,84 @@ func newSGW(s11, s5c, s1u, s5u net.Addr) (*sGateway, error) {
 		log.Fatal(err)
 	}
 
+	if err := s.bindToDevices(); err != nil {
+		return nil, err
+	}
+
+	// log the packets that cannot be decoded, at most 10 in a second on each interface.
+	s.s11Conn.SetMalformedHandler(logMalformed("S11"), 10, time.Second)
+	s.s5cConn.SetMalformedHandler(logMalformed("S5-C"), 10, time.Second)
+	s.s1uConn.SetMalformedHandler(logMalformed("S1-U"), 10, time.Second)
+	s.s5uConn.SetMalformedHandler(logMalformed("S5-U"), 10, time.Second)
+
 	return s, nil
 }
 
+// logMalformed returns the func to log the packet that cannot be decoded on the interface.
+func logMalformed(name string) func(net.Addr, error, uint64) {
+	return func(peer net.Addr, err error, suppressed uint64) {
+		log.Printf("Warning: malformed packet from %s on %s: %v (%d suppressed)", peer, name, err, suppressed)
+	}
+}
+
+// metrics returns the Registry of the metrics of all the interfaces.
+func (s *sGateway) metrics() *gtpmetrics.Registry {
+	reg := gtpmetrics.NewRegistry()
+	reg.Register(gtpmetrics.NewConnCollector(s.s11Conn, gtpmetrics.Label{Name: "interface", Value: "s11"}))
+	reg.Register(gtpmetrics.NewConnCollector(s.s5cConn, gtpmetrics.Label{Name: "interface", Value: "s5c"}))
+	reg.Register(gtpmetrics.NewUPlaneCollector(s.s1uConn, gtpmetrics.Label{Name: "interface", Value: "s1u"}))
+	reg.Register(gtpmetrics.NewUPlaneCollector(s.s5uConn, gtpmetrics.Label{Name: "interface", Value: "s5u"}))
+	return reg
+}
+
+// bindToDevices binds each interface to the device or VRF in the configuration.
+func (s *sGateway) bindToDevices() error {
+	binders := map[string]interface{ BindToDevice(string) error }{
+		"s11": s.s11Conn,
+		"s5c": s.s5cConn,
+		"s1u": s.s1uConn,
+		"s5u": s.s5uConn,
+	}
+	for name, dev := range cfg.Devices {
+		b, ok := binders[name]
+		if !ok {
+			return errors.Errorf("unknown interface to bind to %s: %s", dev, name)
+		}
+		if err := b.BindToDevice(dev); err != nil {
+			return errors.Wrapf(err, "failed to bind %s to %s", name, dev)
+		}
+		log.Printf("Bound %s to device %s", name, dev)
+	}
+	return nil
+}
+
 func (s *sGateway) run() error {
 	defer func() {
 		s.s11Conn.Close()
 		s.s5cConn.Close()
 	}()
 
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
 	// wait for events(logs, errors, timers).
 	for {
 		select {
+		case <-sigCh:
+			log.Println("Shutting down...")
+			if cfg.SessionDir == "" {
+				return nil
+			}
+			if err := s.saveSessions(); err != nil {
+				return err
+			}
+			log.Printf("Saved %d sessions in %s", len(s.s11Conn.ListSessions()), cfg.SessionDir)
+			return nil
 		case str := <-s.loggerCh:
 			log.Println(str)
 		case err := <-s.errCh:
 			log.Printf("Warning: %s", errors.WithStack(err))
-		case <-time.After(10 * time.Second):
+		case <-time.After(cfg.Timers.StatusInterval):
 			var activeIMSIs []string
 			for _, sess := range s.s11Conn.Sessions {
 				if !sess.IsActive() {
@@ -124,22 +240,27 @@ func (s *sGateway) run() error {
 
 func main() {
 	flag.Parse()
-	log.SetPrefix("[S-GW] ")
+	if err := config.Load(*configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.SetupLog(); err != nil {
+		log.Fatal(err)
+	}
 
-	// resolve specified IP:Port as net.UDPAddr.
-	s11, err := net.ResolveUDPAddr("udp", *s11)
+	// resolve configured IP:Port as net.UDPAddr.
+	s11, err := cfg.UDPAddr("s11")
 	if err != nil {
 		log.Fatal(err)
 	}
-	s5c, err := net.ResolveUDPAddr("udp", *s5c)
+	s5c, err := cfg.UDPAddr("s5c")
 	if err != nil {
 		log.Fatal(err)
 	}
-	s1u, err := net.ResolveUDPAddr("udp", *s1u)
+	s1u, err := cfg.UDPAddr("s1u")
 	if err != nil {
 		log.Fatal(err)
 	}
-	s5u, err := net.ResolveUDPAddr("udp", *s5u)
+	s5u, err := cfg.UDPAddr("s5u")
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -149,16 +270,46 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// serve metrics of C-Plane and U-Plane to be scraped by Prometheus, if configured.
+	if addr, err := cfg.Interface("metrics"); err == nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", sgw.metrics())
+		go func() {
+			log.Fatal(http.ListenAndServe(addr, mux))
+		}()
+		log.Printf("Started serving metrics on %s/metrics", addr)
+	}
+	if len(cfg.Routes) != 0 {
+		sgw.pgwSel, err = cfg.NewPGWSelector()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// register handlers for ALL the messages you expect remote endpoint to send.
 	sgw.s11Conn.AddHandlers(map[uint8]v2.HandlerFunc{
-		messages.MsgTypeCreateSessionRequest: handleCreateSessionRequest,
-		messages.MsgTypeModifyBearerRequest:  handleModifyBearerRequest,
-		messages.MsgTypeDeleteSessionRequest: handleDeleteSessionRequest,
+		messages.MsgTypeCreateSessionRequest:        handleCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest:         handleModifyBearerRequest,
+		messages.MsgTypeReleaseAccessBearersRequest: handleReleaseAccessBearersRequest,
+		messages.MsgTypeDeleteSessionRequest:        handleDeleteSessionRequest,
 	})
 	sgw.s5cConn.AddHandlers(map[uint8]v2.HandlerFunc{
 		messages.MsgTypeCreateSessionResponse: handleCreateSessionResponse,
 		messages.MsgTypeDeleteSessionResponse: handleDeleteSessionResponse,
 	})
+	sgw.s11Conn.SetUsageReportHandler(logUsageReports)
+	sgw.s1uConn.SetErrorIndicationHandler(handleErrorIndication)
+
+	if cfg.SessionDir != "" {
+		if err := sgw.restoreSessions(); err != nil {
+			log.Fatal(err)
+		}
+		if n := len(sgw.s11Conn.ListSessions()); n != 0 {
+			log.Printf("Restored %d sessions from %s", n, cfg.SessionDir)
+		}
+	}
 
-	log.Fatal(sgw.run())
+	if err := sgw.run(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	v1 "github.com/wmnsk/go-gtp/v1"
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
@@ -31,7 +32,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 	if ie := csReqFromMME.PGWS5S8FTEIDC; ie != nil {
 		pgwAddrString = ie.IPAddress() + ":2123"
 		s11Session.AddTEID(v2.IFTypeS5S8PGWGTPC, ie.TEID())
-	} else {
+	} else if sgw.pgwSel == nil {
 		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
 	}
 	if ie := csReqFromMME.SenderFTEIDC; ie != nil {
@@ -40,11 +41,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
 	}
 
-	laddr, err := net.ResolveUDPAddr("udp", *s5c)
-	if err != nil {
-		return err
-	}
-	raddr, err := net.ResolveUDPAddr("udp", pgwAddrString)
+	laddr, err := cfg.UDPAddr("s5c")
 	if err != nil {
 		return err
 	}
@@ -75,7 +72,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return &v2.ErrRequiredIEMissing{Type: ies.MSISDN}
 	}
 	if ie := csReqFromMME.MEI; ie != nil {
-		s11Session.IMEI = ie.MobileEquipmentIdentity()
+		s11Session.IMEI, s11Session.SVN = ie.IMEI(), ie.SoftwareVersion()
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.MobileEquipmentIdentity}
 	}
@@ -95,11 +92,45 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 	} else {
 		return &v2.ErrRequiredIEMissing{Type: ies.RATType}
 	}
+
+	// select P-GW by the routes if configured, otherwise use the one MME specified.
+	if sgw.pgwSel != nil {
+		route, err := sgw.pgwSel.Select(s11Session.IMSI, s11Bearer.APN)
+		switch {
+		case err == nil:
+			pgwAddrString = route.PGW.String()
+			how := "home subscriber"
+			if route.Roaming {
+				how = "roaming, " + route.Mode.String()
+			}
+			sgw.loggerCh <- fmt.Sprintf(
+				"Selected P-GW %s for Subscriber: %s, APN: %s (%s over %s)",
+				pgwAddrString, s11Session.IMSI, s11Bearer.APN, how, route.Interface(),
+			)
+		case pgwAddrString == "":
+			return err
+		}
+	}
+	raddr, err := net.ResolveUDPAddr("udp", pgwAddrString)
+	if err != nil {
+		return err
+	}
 	s11Conn.AddSession(s11Session)
 
 	s5cIP := laddr.IP.String()
+	s5uIP, err := cfg.IP("s5u")
+	if err != nil {
+		return err
+	}
 	s5cFTEID := sgw.s5cConn.NewFTEID(v2.IFTypeS5S8SGWGTPC, s5cIP, "")
-	s5uFTEID := sgw.s5cConn.NewFTEID(v2.IFTypeS5S8SGWGTPU, s5cIP, "").WithInstance(2)
+	s5uFTEID := sgw.s5cConn.NewFTEID(v2.IFTypeS5S8SGWGTPU, s5uIP, "")
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextToBeCreated, 5).
+		FTEID(s5uFTEID).
+		QoS(&v2.QoSProfile{PCI: true, PL: 2, PVI: true, QCI: 0xff}).
+		Build()
+	if err != nil {
+		return err
+	}
 
 	s5Session, err := sgw.s5cConn.CreateSession(
 		raddr,
@@ -107,11 +138,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		csReqFromMME.RATType, csReqFromMME.IndicationFlags, s5cFTEID, csReqFromMME.PGWS5S8FTEIDC,
 		csReqFromMME.APN, csReqFromMME.SelectionMode, csReqFromMME.PDNType, csReqFromMME.PAA,
 		csReqFromMME.APNRestriction, csReqFromMME.AMBR, csReqFromMME.ULI,
-		ies.NewBearerContext(
-			ies.NewEPSBearerID(5),
-			s5uFTEID,
-			ies.NewBearerQoS(1, 2, 1, 0xff, 0, 0, 0, 0),
-		),
+		brCtx,
 		csReqFromMME.MMEFQCSID,
 		ies.NewFullyQualifiedCSID(s5cIP, 1).WithInstance(1),
 	)
@@ -133,7 +160,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 			return
 		}
 
-		message, err := s11Session.WaitMessage(5 * time.Second)
+		message, err := s11Session.WaitMessage(cfg.Timers.ResponseTimeout)
 		if err != nil {
 			csRspFromSGW = messages.NewCreateSessionResponse(
 				s11mmeTEID, 0,
@@ -163,12 +190,16 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 			return
 		}
 		// if everything in CreateSessionResponse seems OK, relay it to MME.
-		s11IP, _, err := net.SplitHostPort(*s11)
+		s11IP, err := cfg.IP("s11")
+		if err != nil {
+			return
+		}
+		s1uIP, err := cfg.IP("s1u")
 		if err != nil {
 			return
 		}
 		senderFTEID := s11Conn.NewFTEID(v2.IFTypeS11S4SGWGTPC, s11IP, "")
-		s1usgwFTEID := s11Conn.NewFTEID(v2.IFTypeS1USGWGTPU, s11IP, "")
+		s1usgwFTEID := s11Conn.NewFTEID(v2.IFTypeS1USGWGTPU, s1uIP, "")
 		csRspFromSGW = csRspFromPGW
 		csRspFromSGW.SenderFTEIDC = senderFTEID
 		csRspFromSGW.SGWFQCSID = ies.NewFullyQualifiedCSID(laddr.IP.String(), 1).WithInstance(1)
@@ -217,7 +248,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 	case err := <-failCh:
 		s11Conn.RemoveSession(s11Session)
 		return err
-	case <-time.After(10 * time.Second):
+	case <-time.After(2 * cfg.Timers.ResponseTimeout):
 		s11Conn.RemoveSession(s11Session)
 		return v2.ErrTimeout
 	}
@@ -237,18 +268,22 @@ func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.
 	s1uBearer := s11Session.GetDefaultBearer()
 	s5uBearer := s5cSession.GetDefaultBearer()
 
+	// keep the current eNB to tell if the path is switched by handover.
+	oldENBTEID, oldENBAddr := s1uBearer.OutgoingTEID(), s1uBearer.RemoteAddress()
+
 	// assert type to refer to the struct field specific to the message.
 	// in general, no need to check if it can be type-asserted, as long as the MessageType is
 	// specified correctly in AddHandler().
 	mbReqFromMME := msg.(*messages.ModifyBearerRequest)
 	if brCtxIE := mbReqFromMME.BearerContextsToBeModified; brCtxIE != nil {
-		for _, ie := range brCtxIE.ChildIEs {
+		for _, ie := range brCtxIE.BearerContext() {
 			switch ie.Type {
 			case ies.Indication:
 				// do nothing in this example.
 				// S-GW should change its beahavior based on indication flags like;
 				//  - pass Modify Bearer Request to P-GW if handover is indicated.
 				//  - XXX...
+				// X2/S1-based handover is detected by the change of eNB F-TEID below.
 			case ies.FullyQualifiedTEID:
 				if err := handleFTEIDU(ie, s11Session, s1uBearer); err != nil {
 					return err
@@ -270,33 +305,89 @@ func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.
 		return err
 	}
 	sgw.s1uConn.RelayTo(sgw.s5uConn, s1usgwTEID, s5uBearer.OutgoingTEID(), s5uBearer.RemoteAddress())
-	sgw.s5uConn.RelayTo(sgw.s1uConn, s5usgwTEID, s1uBearer.OutgoingTEID(), s1uBearer.RemoteAddress())
 
-	s1uIP, _, err := net.SplitHostPort(*s1u)
+	// on handover, switch the downlink to the target eNB atomically, with End Marker
+	// sent to the source eNB after the last packet on the old path.
+	switched := oldENBAddr != nil &&
+		(oldENBTEID != s1uBearer.OutgoingTEID() || oldENBAddr.String() != s1uBearer.RemoteAddress().String())
+	if switched {
+		err = sgw.s5uConn.ReplaceRelay(sgw.s1uConn, s5usgwTEID, s1uBearer.OutgoingTEID(), s1uBearer.RemoteAddress())
+		switch err {
+		case nil:
+			sgw.loggerCh <- fmt.Sprintf(
+				"Switched downlink path for Subscriber: %s;\n\tfrom %s(TEID: %#x) to %s(TEID: %#x), End Marker sent",
+				s11Session.IMSI, oldENBAddr, oldENBTEID, s1uBearer.RemoteAddress(), s1uBearer.OutgoingTEID(),
+			)
+		case v1.ErrRelayNotFound:
+			switched = false
+		default:
+			return err
+		}
+	}
+	if !switched {
+		sgw.s5uConn.RelayTo(sgw.s1uConn, s5usgwTEID, s1uBearer.OutgoingTEID(), s1uBearer.RemoteAddress())
+	}
+
+	s1uIP, err := cfg.IP("s1u")
+	if err != nil {
+		return err
+	}
+	brCtx, err := v2.NewBearerContextBuilder(v2.BearerContextModified, s1uBearer.EBI).
+		Cause(v2.CauseRequestAccepted).
+		FTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, s1usgwTEID, s1uIP, "")).
+		Build()
 	if err != nil {
 		return err
 	}
 	mbRspFromSGW := messages.NewModifyBearerResponse(
 		s11mmeTEID, 0,
 		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
-		ies.NewBearerContext(
-			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
-			ies.NewEPSBearerID(s1uBearer.EBI),
-			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, s1usgwTEID, s1uIP, ""),
-		),
+		brCtx,
 	)
 
 	if err := s11Conn.RespondTo(mmeAddr, msg, mbRspFromSGW); err != nil {
 		return err
 	}
+	// the session is deactivated while the UE is in ECM-IDLE.
+	if err := s11Session.Activate(); err != nil {
+		return err
+	}
+	sgw.ddn.Clear(s11Session.IMSI)
 
 	sgw.loggerCh <- fmt.Sprintf(
 		"Started listening on U-Plane for Subscriber: %s;\n\tS1-U: %s\n\tS5-U: %s",
-		s11Session.IMSI, *s1u, *s5u,
+		s11Session.IMSI, cfg.Interfaces["s1u"], cfg.Interfaces["s5u"],
 	)
 	return nil
 }
 
+func handleReleaseAccessBearersRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	sgw.loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
+
+	s11Session, err := s11Conn.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	s11mmeTEID, err := s11Session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	if err := releaseS1UBearer(s11Session); err != nil {
+		return err
+	}
+
+	rabRspFromSGW := messages.NewReleaseAccessBearersResponse(
+		s11mmeTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	if err := s11Conn.RespondTo(mmeAddr, msg, rabRspFromSGW); err != nil {
+		return err
+	}
+
+	sgw.loggerCh <- fmt.Sprintf("Released S1-U bearers for Subscriber: %s, now in ECM-IDLE", s11Session.IMSI)
+	return nil
+}
+
 func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
 	sgw.loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
 
@@ -320,10 +411,14 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return err
 	}
 
-	if err := sgw.s5cConn.DeleteSession(
-		s5cpgwTEID,
-		ies.NewEPSBearerID(s5Session.GetDefaultBearer().EBI),
-	); err != nil {
+	// relay the Secondary RAT usage reports intended for P-GW, to be charged there.
+	ieToPGW := []*ies.IE{ies.NewEPSBearerID(s5Session.GetDefaultBearer().EBI)}
+	for _, ie := range v2.SecondaryRATUsageDataReports(dsReqFromMME) {
+		if r := ie.SecondaryRATUsageDataReport(); r != nil && r.IRPGW {
+			ieToPGW = append(ieToPGW, ie)
+		}
+	}
+	if err := sgw.s5cConn.DeleteSession(s5cpgwTEID, ieToPGW...); err != nil {
 		return err
 	}
 
@@ -338,7 +433,7 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 			return
 		}
 
-		message, err := s11Session.WaitMessage(5 * time.Second)
+		message, err := s11Session.WaitMessage(cfg.Timers.ResponseTimeout)
 		if err != nil {
 			dsRspFromSGW = messages.NewDeleteSessionResponse(
 				s11mmeTEID, 0,
@@ -385,6 +480,20 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 	}
 }
 
+// logUsageReports prints the Secondary RAT usage reported by MME for the session intended
+// for S-GW, when the session is deleted. In the real case they should be put in the CDRs.
+func logUsageReports(s11Conn *v2.Conn, session *v2.Session, reports []*ies.SecondaryRATUsageDataReportPayload) {
+	for _, r := range reports {
+		if !r.IRSGW {
+			continue
+		}
+		sgw.loggerCh <- fmt.Sprintf(
+			"Secondary RAT usage for Subscriber: %s; EBI: %d, DL: %d bytes, UL: %d bytes, from %s to %s",
+			session.IMSI, r.EBI, r.UsageDL, r.UsageUL, r.StartTime.Format(time.RFC3339), r.EndTime.Format(time.RFC3339),
+		)
+	}
+}
+
 func handleDeleteBearerResponse(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
 	sgw.loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
 
@@ -398,7 +507,7 @@ func handleDeleteBearerResponse(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return err
 	}
 
-	if err := v2.PassMessageTo(s5Session, msg, 5*time.Second); err != nil {
+	if err := v2.PassMessageTo(s5Session, msg, cfg.Timers.ResponseTimeout); err != nil {
 		return err
 	}
 
@@ -0,0 +1,71 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command sgw is a dead simple implementation of S-GW only with GTP-related features.
+package main
+
+import (
+	"fmt"
+	"net"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// releaseS1UBearer stops relaying the downlink to the eNB, and forgets it until the UE
+// comes back with Service Request, which is told by the next Modify Bearer Request.
+// The downlink packets are discarded in the meantime, as this example does not buffer them.
+func releaseS1UBearer(s11Session *v2.Session) error {
+	s5cSession, err := sgw.s5cConn.GetSessionByIMSI(s11Session.IMSI)
+	if err != nil {
+		return err
+	}
+	s5usgwTEID, err := s5cSession.GetTEID(v2.IFTypeS5S8SGWGTPU)
+	if err != nil {
+		return err
+	}
+
+	if err := sgw.s5uConn.RemoveRelay(s5usgwTEID); err != nil && err != v1.ErrRelayNotFound {
+		return err
+	}
+	s1uBearer := s11Session.GetDefaultBearer()
+	s1uBearer.SetOutgoingTEID(0)
+	s1uBearer.SetRemoteAddress(nil)
+	return s11Session.Deactivate()
+}
+
+// handleErrorIndication releases the S1-U bearer whose context is lost in the eNB, and
+// sends Downlink Data Notification to MME to page the UE, as the downlink packet which
+// caused the Error Indication has been lost.
+func handleErrorIndication(teid uint32, enbIP string) {
+	sgw.loggerCh <- fmt.Sprintf("Received Error Indication from %s, TEID: %#x", enbIP, teid)
+
+	var s11Session *v2.Session
+	for _, sess := range sgw.s11Conn.ListSessions() {
+		br := sess.GetDefaultBearer()
+		if br.OutgoingTEID() != teid || br.RemoteAddress() == nil {
+			continue
+		}
+		if ip, _, err := net.SplitHostPort(br.RemoteAddress().String()); err == nil && ip == enbIP {
+			s11Session = sess
+			break
+		}
+	}
+	if s11Session == nil {
+		sgw.errCh <- fmt.Errorf("no S1-U bearer found for Error Indication from %s, TEID: %#x", enbIP, teid)
+		return
+	}
+
+	if err := releaseS1UBearer(s11Session); err != nil {
+		sgw.errCh <- err
+		return
+	}
+	sgw.loggerCh <- fmt.Sprintf("Released S1-U bearers for Subscriber: %s on Error Indication", s11Session.IMSI)
+
+	if err := sgw.ddn.Notify(s11Session, s11Session.GetDefaultBearer()); err != nil {
+		sgw.errCh <- err
+		return
+	}
+	sgw.loggerCh <- fmt.Sprintf("Sent Downlink Data Notification for Subscriber: %s", s11Session.IMSI)
+}
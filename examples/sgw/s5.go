@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"net"
-	"time"
 
 	"github.com/pkg/errors"
 	v2 "github.com/wmnsk/go-gtp/v2"
@@ -59,7 +58,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 	}
 
 	if brCtxIE := csRspFromPGW.BearerContextsCreated; brCtxIE != nil {
-		for _, ie := range brCtxIE.ChildIEs {
+		for _, ie := range brCtxIE.BearerContext() {
 			switch ie.Type {
 			case ies.Cause:
 				if cause := ie.Cause(); cause != v2.CauseRequestAccepted {
@@ -95,7 +94,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 		return err
 	}
 
-	if err := v2.PassMessageTo(s11Session, csRspFromPGW, 5*time.Second); err != nil {
+	if err := v2.PassMessageTo(s11Session, csRspFromPGW, cfg.Timers.ResponseTimeout); err != nil {
 		return err
 	}
 
@@ -115,7 +114,7 @@ func handleDeleteSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 		return err
 	}
 
-	if err := v2.PassMessageTo(s11Session, msg, 5*time.Second); err != nil {
+	if err := v2.PassMessageTo(s11Session, msg, cfg.Timers.ResponseTimeout); err != nil {
 		return err
 	}
 
@@ -210,7 +209,7 @@ func handleDeleteBearerRequest(s5cConn *v2.Conn, pgwAddr net.Addr, msg messages.
 	doneCh := make(chan struct{})
 	failCh := make(chan error)
 	go func() {
-		message, err := s5Session.WaitMessage(5 * time.Second)
+		message, err := s5Session.WaitMessage(cfg.Timers.ResponseTimeout)
 		if err != nil {
 			dbRspFromSGW = messages.NewDeleteBearerResponse(
 				s5cpgwTEID, 0,
@@ -0,0 +1,105 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// snapshotFiles returns the files to save the sessions of S11 and S5-C in.
+func (s *sGateway) snapshotFiles() map[string]*v2.Conn {
+	return map[string]*v2.Conn{
+		filepath.Join(cfg.SessionDir, "s11.json"): s.s11Conn,
+		filepath.Join(cfg.SessionDir, "s5c.json"): s.s5cConn,
+	}
+}
+
+// saveSessions saves the sessions in the session directory, to be restored by
+// restoreSessions at the next startup.
+func (s *sGateway) saveSessions() error {
+	if err := os.MkdirAll(cfg.SessionDir, 0o755); err != nil {
+		return err
+	}
+
+	for path, c := range s.snapshotFiles() {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := c.SnapshotSessions(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSessions restores the sessions saved by saveSessions, and the relays on U-Plane
+// for them. The files are removed once restored, not to restore the stale sessions again
+// after the crash.
+func (s *sGateway) restoreSessions() error {
+	for path, c := range s.snapshotFiles() {
+		f, err := os.Open(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		err = c.RestoreSessions(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	for _, s11Session := range s.s11Conn.ListSessions() {
+		if err := s.restoreRelays(s11Session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreRelays sets the relays of the session in the same way as Modify Bearer Request
+// does. The downlink is not relayed while the UE is in ECM-IDLE.
+func (s *sGateway) restoreRelays(s11Session *v2.Session) error {
+	s5cSession, err := s.s5cConn.GetSessionByIMSI(s11Session.IMSI)
+	if err != nil {
+		return err
+	}
+	s1uBearer := s11Session.GetDefaultBearer()
+	s5uBearer := s5cSession.GetDefaultBearer()
+
+	// the eNB is not told yet before the first Modify Bearer Request.
+	if s1uBearer.RemoteAddress() == nil || s5uBearer.RemoteAddress() == nil {
+		return nil
+	}
+
+	s1usgwTEID, err := s11Session.GetTEID(v2.IFTypeS1USGWGTPU)
+	if err != nil {
+		return err
+	}
+	s5usgwTEID, err := s5cSession.GetTEID(v2.IFTypeS5S8SGWGTPU)
+	if err != nil {
+		return err
+	}
+	s.s1uConn.RelayTo(s.s5uConn, s1usgwTEID, s5uBearer.OutgoingTEID(), s5uBearer.RemoteAddress())
+	if s11Session.IsActive() {
+		s.s5uConn.RelayTo(s.s1uConn, s5usgwTEID, s1uBearer.OutgoingTEID(), s1uBearer.RemoteAddress())
+	}
+	return nil
+}
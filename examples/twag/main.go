@@ -0,0 +1,170 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command twag is a dead simple implementation of TWAG(Trusted WLAN Access Gateway)
+// only with GTP-related features, which connects the UEs on the trusted WLAN access
+// to P-GW over S2a.
+//
+// TWAG follows the steps below if there's no unexpected events in the middle. Note
+// that the WLAN association and STa procedures are just mocked to make it work in
+// standalone manner.
+//
+// 1. Start dispatching subscribers by sending Create Session Request to the P-GW of
+// the APN, with the F-TEIDs of TWAN on S2a, the TWAN Identifier of the WLAN that
+// the UE is associated with, and APCO requesting the DNS servers.
+//
+// 2. Wait for Create Session Response coming from P-GW with Cause="request accepted",
+// and create mocked UE with the IP address and the F-TEID of S2a-U told by P-GW.
+//
+// 3. Start sending payload(ICMP Echo Request) encapsulated with GTPv1-U Header, and
+// printing the payload of encapsulated packets received.
+//
+// 4. Delete all the sessions with Delete Session Request with the TWAN Identifier and
+// its timestamp after the inactivity timer expires.
+//
+// The subscribers are read from the subscriber file given with subscriber_file in the
+// config, in the same format as MME. The interfaces, the P-GW of each APN, and the
+// timers can be configured with the YAML file given with config flag. See twag.yml
+// for the example.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/examples/internal/config"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// command-line flags.
+var configPath = flag.String("config", "", "path to the YAML config file.")
+
+// cfg is the configuration with the defaults, overridden by the config file.
+var cfg = &config.Config{
+	Interfaces: map[string]string{
+		"s2ac": "127.0.0.131:2123",
+		"s2au": "127.0.0.7:2152",
+	},
+	APNs: []*config.APNConfig{
+		{Name: "some-apn-1.example", PGW: "127.0.0.52"},
+		{Name: "some-apn-2.example", PGW: "127.0.0.53"},
+	},
+	Timers: config.Timers{ResponseTimeout: 5 * time.Second, Inactivity: 30 * time.Second},
+	Log:    config.Log{Prefix: "[TWAG] "},
+}
+
+// subscribers are the subscribers to be attached if no subscriber file is configured.
+var subscribers = &config.SubscriberDB{
+	MCC: "123", MNC: "45",
+	Subscribers: []*config.Subscriber{
+		{IMSI: "123451234567911", MSISDN: "8130900000111", IMEI: "123456780000111", APN: "some-apn-1.example"},
+		{IMSI: "123451234567912", MSISDN: "8130900000112", IMEI: "123456780000112", APN: "some-apn-2.example"},
+		{IMSI: "123451234567913", MSISDN: "8130900000113", IMEI: "123456780000113", APN: "some-apn-1.example"},
+	},
+}
+
+// variables globally shared.
+var (
+	attachCh = make(chan *config.Subscriber)
+	loggerCh = make(chan string)
+	errCh    = make(chan error)
+
+	uConn *v1.UPlaneConn
+)
+
+func main() {
+	flag.Parse()
+	if err := config.Load(*configPath, cfg); err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.SetupLog(); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.SubscriberFile != "" {
+		db, err := config.LoadSubscriberDB(cfg.SubscriberFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		subscribers = db
+	}
+
+	cAddr, err := cfg.UDPAddr("s2ac")
+	if err != nil {
+		log.Fatal(err)
+	}
+	uAddr, err := cfg.UDPAddr("s2au")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// the P-GWs are selected per APN, so listen on S2a instead of dialing to a P-GW.
+	s2aConn, err := v2.ListenAndServe(cAddr, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s2aConn.Close()
+	log.Printf("Started serving on %s", s2aConn.LocalAddr())
+
+	uConn, err = v1.ListenAndServeUPlane(uAddr, 0, errCh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer uConn.Close()
+	log.Printf("Started listening on %s", uConn.LocalAddr())
+	go receive()
+
+	// register handlers for ALL the messages you expect remote endpoint to send.
+	s2aConn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionResponse: handleCreateSessionResponse,
+	})
+
+	// here you should wait for UEs to come attaching to your network over WLAN.
+	// in this example, the subscribers in the subscriber database are to be attached.
+	go dispatch(subscribers)
+
+	inactivity := time.After(cfg.Timers.Inactivity)
+	for {
+		select {
+		// print logs coming from handlers working background
+		case str := <-loggerCh:
+			log.Println(str)
+		// print errors coming from handlers working background
+		case err := <-errCh:
+			log.Printf("Warning: %s", err)
+		// handle attach requests
+		case sub := <-attachCh:
+			log.Printf("Started creating session for subscriber: %s", sub.IMSI)
+			go func() {
+				if err := handleAttach(s2aConn, sub); err != nil {
+					errCh <- err
+				}
+			}()
+		// delete all the sessions after the inactivity timer expires
+		case <-inactivity:
+			delWG := sync.WaitGroup{}
+			for _, sess := range s2aConn.ListSessions() {
+				delWG.Add(1)
+				go func(sess *v2.Session) {
+					defer delWG.Done()
+					if err := handleDetach(s2aConn, sess); err != nil {
+						errCh <- err
+						return
+					}
+					loggerCh <- fmt.Sprintf("Session deleted with P-GW for Subscriber: %s", sess.IMSI)
+				}(sess)
+			}
+
+			// invoke goroutine to let the logger work
+			go func() {
+				delWG.Wait()
+				log.Fatal("Inactivity timer expired, exitting...")
+			}()
+		}
+	}
+}
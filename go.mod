@@ -1,7 +1,19 @@
 module github.com/wmnsk/go-gtp
 
+go 1.25.0
+
 require (
-	github.com/google/go-cmp v0.2.0
+	github.com/google/go-cmp v0.7.0
 	github.com/pascaldekloe/goe v0.0.0-20180627143212-57f6aae5913c
 	github.com/pkg/errors v0.8.1
+	golang.org/x/sys v0.47.0
+	google.golang.org/grpc v1.84.0
+	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/net v0.57.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260706201446-f0a921348800 // indirect
 )
@@ -0,0 +1,175 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpdemux
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+type datagram struct {
+	b    []byte
+	addr net.Addr
+}
+
+// PacketConn is a net.PacketConn that reads the datagrams of a GTP version routed by
+// Demux, and writes the datagrams to the net.PacketConn shared.
+//
+// The deadlines and Close affect only the PacketConn, not the others sharing the
+// net.PacketConn, so that closing the Conn of a version doesn't stop the others.
+type PacketConn struct {
+	demux *Demux
+	rcvCh chan *datagram
+
+	readDeadline, writeDeadline *deadline
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+func newPacketConn(d *Demux, queueSize int) *PacketConn {
+	return &PacketConn{
+		demux:         d,
+		rcvCh:         make(chan *datagram, queueSize),
+		readDeadline:  newDeadline(),
+		writeDeadline: newDeadline(),
+		closeCh:       make(chan struct{}),
+	}
+}
+
+// deliver queues the datagram without blocking, and returns false if it is full or closed.
+func (c *PacketConn) deliver(dg *datagram) bool {
+	select {
+	case <-c.closeCh:
+		return false
+	default:
+	}
+
+	select {
+	case c.rcvCh <- dg:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadFrom reads a datagram of the version routed by Demux.
+func (c *PacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	select {
+	case dg := <-c.rcvCh:
+		return copy(p, dg.b), dg.addr, nil
+	case <-c.closeCh:
+		return 0, nil, c.opError("read", net.ErrClosed)
+	case <-c.readDeadline.done():
+		return 0, nil, c.opError("read", os.ErrDeadlineExceeded)
+	}
+}
+
+// WriteTo writes a datagram to addr from the net.PacketConn shared.
+func (c *PacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.closeCh:
+		return 0, c.opError("write", net.ErrClosed)
+	case <-c.writeDeadline.done():
+		return 0, c.opError("write", os.ErrDeadlineExceeded)
+	default:
+	}
+	return c.demux.conn.WriteTo(p, addr)
+}
+
+// Close closes the PacketConn, without closing the net.PacketConn shared. The datagrams
+// of the version are discarded afterwards.
+func (c *PacketConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closeCh)
+	})
+	return nil
+}
+
+// LocalAddr returns the local address of the net.PacketConn shared.
+func (c *PacketConn) LocalAddr() net.Addr {
+	return c.demux.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the PacketConn.
+func (c *PacketConn) SetDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	c.writeDeadline.set(t)
+	return nil
+}
+
+// SetReadDeadline sets the read deadline of the PacketConn.
+func (c *PacketConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.set(t)
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the PacketConn.
+func (c *PacketConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.set(t)
+	return nil
+}
+
+func (c *PacketConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: c.LocalAddr().Network(), Addr: c.LocalAddr(), Err: err}
+}
+
+// deadline is a channel closed when the time set is passed.
+type deadline struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	ch    chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{ch: make(chan struct{})}
+}
+
+func (d *deadline) done() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ch
+}
+
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if isClosed(d.ch) {
+		d.ch = make(chan struct{})
+	}
+	if t.IsZero() {
+		return
+	}
+
+	dur := time.Until(t)
+	if dur <= 0 {
+		close(d.ch)
+		return
+	}
+	ch := d.ch
+	d.timer = time.AfterFunc(dur, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.ch == ch && !isClosed(ch) {
+			close(ch)
+		}
+	})
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
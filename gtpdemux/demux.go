@@ -0,0 +1,127 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpdemux
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultQueueSize is the number of the datagrams queued for each version until
+// they are read.
+const DefaultQueueSize = 1024
+
+// ErrClosed indicates that the Demux or the PacketConn is already closed.
+var ErrClosed = errors.New("demux closed")
+
+// Demux routes the datagrams received on a net.PacketConn to the PacketConn of the
+// version of GTP.
+type Demux struct {
+	conn net.PacketConn
+
+	mu        sync.Mutex
+	conns     [8]*PacketConn
+	queueSize int
+
+	dropped   atomic.Uint64
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// New creates a new Demux over conn and start reading from it.
+func New(conn net.PacketConn) *Demux {
+	d := &Demux{
+		conn:      conn,
+		queueSize: DefaultQueueSize,
+		closeCh:   make(chan struct{}),
+	}
+
+	go d.serve()
+	return d
+}
+
+// Listen listens on the address and creates a new Demux over it.
+func Listen(network, address string) (*Demux, error) {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return New(conn), nil
+}
+
+// PacketConn returns the net.PacketConn to read and write the datagrams of the GTP
+// version given, creating it on the first call. The datagrams of the version are
+// discarded until it is called.
+func (d *Demux) PacketConn(version uint8) *PacketConn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	version &= 0x07
+	if d.conns[version] == nil {
+		d.conns[version] = newPacketConn(d, d.queueSize)
+	}
+	return d.conns[version]
+}
+
+// LocalAddr returns the local address of the shared net.PacketConn.
+func (d *Demux) LocalAddr() net.Addr {
+	return d.conn.LocalAddr()
+}
+
+// Dropped returns the number of the datagrams discarded as no PacketConn is retrieved
+// for the version or the queue is full.
+func (d *Demux) Dropped() uint64 {
+	return d.dropped.Load()
+}
+
+// Close closes the shared net.PacketConn and all the PacketConns.
+func (d *Demux) Close() error {
+	var err error
+	d.closeOnce.Do(func() {
+		close(d.closeCh)
+		err = d.conn.Close()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for _, c := range d.conns {
+			if c != nil {
+				c.Close()
+			}
+		}
+	})
+	return err
+}
+
+func (d *Demux) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, raddr, err := d.conn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-d.closeCh:
+				return
+			default:
+			}
+			var nerr net.Error
+			if errors.As(err, &nerr) && nerr.Timeout() {
+				continue
+			}
+			d.Close()
+			return
+		}
+		if n == 0 {
+			continue
+		}
+
+		d.mu.Lock()
+		c := d.conns[buf[0]>>5]
+		d.mu.Unlock()
+		if c == nil || !c.deliver(&datagram{b: append([]byte(nil), buf[:n]...), addr: raddr}) {
+			d.dropped.Add(1)
+		}
+	}
+}
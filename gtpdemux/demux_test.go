@@ -0,0 +1,133 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpdemux_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtpdemux"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestDemux(t *testing.T) {
+	d, err := gtpdemux.Listen("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	errCh := make(chan error, 8)
+	v1Conn := v1.NewCPlaneConn(d.PacketConn(1), 0, errCh)
+	defer v1Conn.Close()
+	v2Conn := v2.ServeConn(d.PacketConn(2), 0, errCh)
+	defer v2Conn.Close()
+
+	peer, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	exchange := func(req []byte) []byte {
+		t.Helper()
+		if _, err := peer.WriteTo(req, d.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		if err := peer.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1500)
+		n, _, err := peer.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf[:n]
+	}
+
+	t.Run("v1", func(t *testing.T) {
+		req, err := v1msg.NewEchoRequest(1, v1ies.NewRecovery(0)).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := v1msg.Decode(exchange(req))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := msg.(*v1msg.EchoResponse); !ok {
+			t.Errorf("got %s, want EchoResponse in GTPv1", msg.MessageTypeName())
+		}
+	})
+
+	t.Run("v2", func(t *testing.T) {
+		req, err := v2msg.NewEchoRequest(1, v2ies.NewRecovery(0)).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := v2msg.Decode(exchange(req))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := msg.(*v2msg.EchoResponse); !ok {
+			t.Errorf("got %s, want EchoResponse in GTPv2", msg.MessageTypeName())
+		}
+	})
+
+	t.Run("unrouted", func(t *testing.T) {
+		before := d.Dropped()
+		// GTP' (version 0 with PT=0) has no PacketConn retrieved.
+		if _, err := peer.WriteTo([]byte{0x0e, 0x01, 0x00, 0x00, 0x00, 0x01}, d.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		deadline := time.Now().Add(3 * time.Second)
+		for d.Dropped() == before {
+			if time.Now().After(deadline) {
+				t.Fatal("datagram of unrouted version not dropped")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	select {
+	case err := <-errCh:
+		t.Errorf("unexpected error: %v", err)
+	default:
+	}
+}
+
+func TestPacketConnClose(t *testing.T) {
+	d, err := gtpdemux.Listen("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+
+	c1, c2 := d.PacketConn(1), d.PacketConn(2)
+	if d.PacketConn(1) != c1 {
+		t.Error("PacketConn returned different conn for the same version")
+	}
+
+	if err := c1.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c1.ReadFrom(make([]byte, 10)); err == nil {
+		t.Error("ReadFrom did not time out")
+	} else if nerr, ok := err.(net.Error); !ok || !nerr.Timeout() {
+		t.Errorf("got %v, want timeout", err)
+	}
+
+	if err := c1.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c2.WriteTo([]byte{0x48}, d.LocalAddr()); err != nil {
+		t.Errorf("closing v1 affected v2: %v", err)
+	}
+}
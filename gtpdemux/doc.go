@@ -0,0 +1,25 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpdemux shares a UDP socket among the GTP versions, e.g., GTPv1-C and
+// GTPv2-C on port 2123, so that the nodes speaking both of them like SGSN and MME
+// on the interworking don't need two ports.
+//
+// Demux reads the datagrams from the socket, and routes them by the version in the
+// first octet to the net.PacketConn of the version, over which the Conn of each
+// version is created as usual. The datagrams written to them are sent from the
+// shared socket as they are.
+//
+//	d, err := gtpdemux.Listen("udp", "0.0.0.0:2123")
+//	if err != nil {
+//		// ...
+//	}
+//	defer d.Close()
+//
+//	v1Conn := v1.NewCPlaneConn(d.PacketConn(1), 0, errCh)
+//	v2Conn := v2.ServeConn(d.PacketConn(2), 0, errCh)
+//
+// The datagrams of the versions without the net.PacketConn retrieved are discarded,
+// as well as the ones arriving while the queue of the version is full.
+package gtpdemux
@@ -0,0 +1,1052 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: control.proto
+
+package controlpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SessionEvent_Type int32
+
+const (
+	SessionEvent_TYPE_UNSPECIFIED SessionEvent_Type = 0
+	SessionEvent_TYPE_ADDED       SessionEvent_Type = 1
+	SessionEvent_TYPE_REMOVED     SessionEvent_Type = 2
+)
+
+// Enum value maps for SessionEvent_Type.
+var (
+	SessionEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_ADDED",
+		2: "TYPE_REMOVED",
+	}
+	SessionEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_ADDED":       1,
+		"TYPE_REMOVED":     2,
+	}
+)
+
+func (x SessionEvent_Type) Enum() *SessionEvent_Type {
+	p := new(SessionEvent_Type)
+	*p = x
+	return p
+}
+
+func (x SessionEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_control_proto_enumTypes[0].Descriptor()
+}
+
+func (SessionEvent_Type) Type() protoreflect.EnumType {
+	return &file_control_proto_enumTypes[0]
+}
+
+func (x SessionEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionEvent_Type.Descriptor instead.
+func (SessionEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{11, 0}
+}
+
+type CreateSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// peer_addr is the IP:Port of the peer to send Create Session Request to.
+	PeerAddr string `protobuf:"bytes,1,opt,name=peer_addr,json=peerAddr,proto3" json:"peer_addr,omitempty"`
+	Imsi     string `protobuf:"bytes,2,opt,name=imsi,proto3" json:"imsi,omitempty"`
+	Msisdn   string `protobuf:"bytes,3,opt,name=msisdn,proto3" json:"msisdn,omitempty"`
+	Mei      string `protobuf:"bytes,4,opt,name=mei,proto3" json:"mei,omitempty"`
+	Apn      string `protobuf:"bytes,5,opt,name=apn,proto3" json:"apn,omitempty"`
+	RatType  uint32 `protobuf:"varint,6,opt,name=rat_type,json=ratType,proto3" json:"rat_type,omitempty"`
+	// ebi is the EPS Bearer ID of the default bearer.
+	Ebi uint32 `protobuf:"varint,7,opt,name=ebi,proto3" json:"ebi,omitempty"`
+	// sender_if_type and sender_ipv4 are the InterfaceType and the IP address of
+	// the Sender F-TEID, whose TEID is allocated by the server. The IP address of
+	// the Conn is used if sender_ipv4 is empty.
+	SenderIfType uint32 `protobuf:"varint,8,opt,name=sender_if_type,json=senderIfType,proto3" json:"sender_if_type,omitempty"`
+	SenderIpv4   string `protobuf:"bytes,9,opt,name=sender_ipv4,json=senderIpv4,proto3" json:"sender_ipv4,omitempty"`
+	// ies are the additional IEs in wire format, each of which is a whole IE
+	// including the header.
+	Ies           [][]byte `protobuf:"bytes,10,rep,name=ies,proto3" json:"ies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_control_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateSessionRequest) GetPeerAddr() string {
+	if x != nil {
+		return x.PeerAddr
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetImsi() string {
+	if x != nil {
+		return x.Imsi
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetMsisdn() string {
+	if x != nil {
+		return x.Msisdn
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetMei() string {
+	if x != nil {
+		return x.Mei
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetApn() string {
+	if x != nil {
+		return x.Apn
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetRatType() uint32 {
+	if x != nil {
+		return x.RatType
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetEbi() uint32 {
+	if x != nil {
+		return x.Ebi
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetSenderIfType() uint32 {
+	if x != nil {
+		return x.SenderIfType
+	}
+	return 0
+}
+
+func (x *CreateSessionRequest) GetSenderIpv4() string {
+	if x != nil {
+		return x.SenderIpv4
+	}
+	return ""
+}
+
+func (x *CreateSessionRequest) GetIes() [][]byte {
+	if x != nil {
+		return x.Ies
+	}
+	return nil
+}
+
+type CreateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *Session               `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
+	mi := &file_control_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionResponse) ProtoMessage() {}
+
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateSessionResponse) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+type DeleteSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Imsi  string                 `protobuf:"bytes,1,opt,name=imsi,proto3" json:"imsi,omitempty"`
+	// if_type is the InterfaceType of the peer's TEID to send Delete Session
+	// Request with. The default of the server is used if zero.
+	IfType        uint32 `protobuf:"varint,2,opt,name=if_type,json=ifType,proto3" json:"if_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionRequest) Reset() {
+	*x = DeleteSessionRequest{}
+	mi := &file_control_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionRequest) ProtoMessage() {}
+
+func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DeleteSessionRequest) GetImsi() string {
+	if x != nil {
+		return x.Imsi
+	}
+	return ""
+}
+
+func (x *DeleteSessionRequest) GetIfType() uint32 {
+	if x != nil {
+		return x.IfType
+	}
+	return 0
+}
+
+type DeleteSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionResponse) Reset() {
+	*x = DeleteSessionResponse{}
+	mi := &file_control_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionResponse) ProtoMessage() {}
+
+func (x *DeleteSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSessionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSessionResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{3}
+}
+
+type GetSessionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Key:
+	//
+	//	*GetSessionRequest_Imsi
+	//	*GetSessionRequest_Teid
+	Key           isGetSessionRequest_Key `protobuf_oneof:"key"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_control_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetSessionRequest) GetKey() isGetSessionRequest_Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *GetSessionRequest) GetImsi() string {
+	if x != nil {
+		if x, ok := x.Key.(*GetSessionRequest_Imsi); ok {
+			return x.Imsi
+		}
+	}
+	return ""
+}
+
+func (x *GetSessionRequest) GetTeid() uint32 {
+	if x != nil {
+		if x, ok := x.Key.(*GetSessionRequest_Teid); ok {
+			return x.Teid
+		}
+	}
+	return 0
+}
+
+type isGetSessionRequest_Key interface {
+	isGetSessionRequest_Key()
+}
+
+type GetSessionRequest_Imsi struct {
+	Imsi string `protobuf:"bytes,1,opt,name=imsi,proto3,oneof"`
+}
+
+type GetSessionRequest_Teid struct {
+	Teid uint32 `protobuf:"varint,2,opt,name=teid,proto3,oneof"`
+}
+
+func (*GetSessionRequest_Imsi) isGetSessionRequest_Key() {}
+
+func (*GetSessionRequest_Teid) isGetSessionRequest_Key() {}
+
+type ListSessionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// imsi_prefix filters the sessions by the prefix of IMSI, e.g., MCC and MNC.
+	ImsiPrefix string `protobuf:"bytes,1,opt,name=imsi_prefix,json=imsiPrefix,proto3" json:"imsi_prefix,omitempty"`
+	Offset     uint32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// limit is the maximum number of sessions to return. The default of the
+	// server is used if zero.
+	Limit         uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_control_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSessionsRequest) GetImsiPrefix() string {
+	if x != nil {
+		return x.ImsiPrefix
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListSessionsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSessionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// total is the number of sessions matched, regardless of offset and limit.
+	Total         uint32     `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Sessions      []*Session `protobuf:"bytes,2,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_control_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSessionsResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type WatchSessionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// imsi_prefix filters the events by the prefix of IMSI.
+	ImsiPrefix    string `protobuf:"bytes,1,opt,name=imsi_prefix,json=imsiPrefix,proto3" json:"imsi_prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSessionsRequest) Reset() {
+	*x = WatchSessionsRequest{}
+	mi := &file_control_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSessionsRequest) ProtoMessage() {}
+
+func (x *WatchSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSessionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchSessionsRequest) GetImsiPrefix() string {
+	if x != nil {
+		return x.ImsiPrefix
+	}
+	return ""
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Imsi          string                 `protobuf:"bytes,1,opt,name=imsi,proto3" json:"imsi,omitempty"`
+	Msisdn        string                 `protobuf:"bytes,2,opt,name=msisdn,proto3" json:"msisdn,omitempty"`
+	Mei           string                 `protobuf:"bytes,3,opt,name=mei,proto3" json:"mei,omitempty"`
+	PeerAddr      string                 `protobuf:"bytes,4,opt,name=peer_addr,json=peerAddr,proto3" json:"peer_addr,omitempty"`
+	Active        bool                   `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	Teids         []*TEID                `protobuf:"bytes,6,rep,name=teids,proto3" json:"teids,omitempty"`
+	Bearers       []*Bearer              `protobuf:"bytes,7,rep,name=bearers,proto3" json:"bearers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_control_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Session) GetImsi() string {
+	if x != nil {
+		return x.Imsi
+	}
+	return ""
+}
+
+func (x *Session) GetMsisdn() string {
+	if x != nil {
+		return x.Msisdn
+	}
+	return ""
+}
+
+func (x *Session) GetMei() string {
+	if x != nil {
+		return x.Mei
+	}
+	return ""
+}
+
+func (x *Session) GetPeerAddr() string {
+	if x != nil {
+		return x.PeerAddr
+	}
+	return ""
+}
+
+func (x *Session) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Session) GetTeids() []*TEID {
+	if x != nil {
+		return x.Teids
+	}
+	return nil
+}
+
+func (x *Session) GetBearers() []*Bearer {
+	if x != nil {
+		return x.Bearers
+	}
+	return nil
+}
+
+type TEID struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	IfType uint32                 `protobuf:"varint,1,opt,name=if_type,json=ifType,proto3" json:"if_type,omitempty"`
+	// if_type_name is the name of if_type, e.g., "S11S4SGWGTPC".
+	IfTypeName    string `protobuf:"bytes,2,opt,name=if_type_name,json=ifTypeName,proto3" json:"if_type_name,omitempty"`
+	Teid          uint32 `protobuf:"varint,3,opt,name=teid,proto3" json:"teid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TEID) Reset() {
+	*x = TEID{}
+	mi := &file_control_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TEID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TEID) ProtoMessage() {}
+
+func (x *TEID) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TEID.ProtoReflect.Descriptor instead.
+func (*TEID) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TEID) GetIfType() uint32 {
+	if x != nil {
+		return x.IfType
+	}
+	return 0
+}
+
+func (x *TEID) GetIfTypeName() string {
+	if x != nil {
+		return x.IfTypeName
+	}
+	return ""
+}
+
+func (x *TEID) GetTeid() uint32 {
+	if x != nil {
+		return x.Teid
+	}
+	return 0
+}
+
+type Bearer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ebi           uint32                 `protobuf:"varint,2,opt,name=ebi,proto3" json:"ebi,omitempty"`
+	Apn           string                 `protobuf:"bytes,3,opt,name=apn,proto3" json:"apn,omitempty"`
+	SubscriberIp  string                 `protobuf:"bytes,4,opt,name=subscriber_ip,json=subscriberIp,proto3" json:"subscriber_ip,omitempty"`
+	ChargingId    uint32                 `protobuf:"varint,5,opt,name=charging_id,json=chargingId,proto3" json:"charging_id,omitempty"`
+	IncomingTeid  uint32                 `protobuf:"varint,6,opt,name=incoming_teid,json=incomingTeid,proto3" json:"incoming_teid,omitempty"`
+	OutgoingTeid  uint32                 `protobuf:"varint,7,opt,name=outgoing_teid,json=outgoingTeid,proto3" json:"outgoing_teid,omitempty"`
+	Qci           uint32                 `protobuf:"varint,8,opt,name=qci,proto3" json:"qci,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Bearer) Reset() {
+	*x = Bearer{}
+	mi := &file_control_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bearer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bearer) ProtoMessage() {}
+
+func (x *Bearer) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bearer.ProtoReflect.Descriptor instead.
+func (*Bearer) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Bearer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Bearer) GetEbi() uint32 {
+	if x != nil {
+		return x.Ebi
+	}
+	return 0
+}
+
+func (x *Bearer) GetApn() string {
+	if x != nil {
+		return x.Apn
+	}
+	return ""
+}
+
+func (x *Bearer) GetSubscriberIp() string {
+	if x != nil {
+		return x.SubscriberIp
+	}
+	return ""
+}
+
+func (x *Bearer) GetChargingId() uint32 {
+	if x != nil {
+		return x.ChargingId
+	}
+	return 0
+}
+
+func (x *Bearer) GetIncomingTeid() uint32 {
+	if x != nil {
+		return x.IncomingTeid
+	}
+	return 0
+}
+
+func (x *Bearer) GetOutgoingTeid() uint32 {
+	if x != nil {
+		return x.OutgoingTeid
+	}
+	return 0
+}
+
+func (x *Bearer) GetQci() uint32 {
+	if x != nil {
+		return x.Qci
+	}
+	return 0
+}
+
+type SessionEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          SessionEvent_Type      `protobuf:"varint,1,opt,name=type,proto3,enum=gogtp.control.SessionEvent_Type" json:"type,omitempty"`
+	Session       *Session               `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	mi := &file_control_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_control_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_control_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SessionEvent) GetType() SessionEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return SessionEvent_TYPE_UNSPECIFIED
+}
+
+func (x *SessionEvent) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *SessionEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_control_proto protoreflect.FileDescriptor
+
+const file_control_proto_rawDesc = "" +
+	"\n" +
+	"\rcontrol.proto\x12\rgogtp.control\x1a\x1fgoogle/protobuf/timestamp.proto\"\x89\x02\n" +
+	"\x14CreateSessionRequest\x12\x1b\n" +
+	"\tpeer_addr\x18\x01 \x01(\tR\bpeerAddr\x12\x12\n" +
+	"\x04imsi\x18\x02 \x01(\tR\x04imsi\x12\x16\n" +
+	"\x06msisdn\x18\x03 \x01(\tR\x06msisdn\x12\x10\n" +
+	"\x03mei\x18\x04 \x01(\tR\x03mei\x12\x10\n" +
+	"\x03apn\x18\x05 \x01(\tR\x03apn\x12\x19\n" +
+	"\brat_type\x18\x06 \x01(\rR\aratType\x12\x10\n" +
+	"\x03ebi\x18\a \x01(\rR\x03ebi\x12$\n" +
+	"\x0esender_if_type\x18\b \x01(\rR\fsenderIfType\x12\x1f\n" +
+	"\vsender_ipv4\x18\t \x01(\tR\n" +
+	"senderIpv4\x12\x10\n" +
+	"\x03ies\x18\n" +
+	" \x03(\fR\x03ies\"I\n" +
+	"\x15CreateSessionResponse\x120\n" +
+	"\asession\x18\x01 \x01(\v2\x16.gogtp.control.SessionR\asession\"C\n" +
+	"\x14DeleteSessionRequest\x12\x12\n" +
+	"\x04imsi\x18\x01 \x01(\tR\x04imsi\x12\x17\n" +
+	"\aif_type\x18\x02 \x01(\rR\x06ifType\"\x17\n" +
+	"\x15DeleteSessionResponse\"F\n" +
+	"\x11GetSessionRequest\x12\x14\n" +
+	"\x04imsi\x18\x01 \x01(\tH\x00R\x04imsi\x12\x14\n" +
+	"\x04teid\x18\x02 \x01(\rH\x00R\x04teidB\x05\n" +
+	"\x03key\"d\n" +
+	"\x13ListSessionsRequest\x12\x1f\n" +
+	"\vimsi_prefix\x18\x01 \x01(\tR\n" +
+	"imsiPrefix\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\rR\x06offset\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"`\n" +
+	"\x14ListSessionsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\rR\x05total\x122\n" +
+	"\bsessions\x18\x02 \x03(\v2\x16.gogtp.control.SessionR\bsessions\"7\n" +
+	"\x14WatchSessionsRequest\x12\x1f\n" +
+	"\vimsi_prefix\x18\x01 \x01(\tR\n" +
+	"imsiPrefix\"\xd8\x01\n" +
+	"\aSession\x12\x12\n" +
+	"\x04imsi\x18\x01 \x01(\tR\x04imsi\x12\x16\n" +
+	"\x06msisdn\x18\x02 \x01(\tR\x06msisdn\x12\x10\n" +
+	"\x03mei\x18\x03 \x01(\tR\x03mei\x12\x1b\n" +
+	"\tpeer_addr\x18\x04 \x01(\tR\bpeerAddr\x12\x16\n" +
+	"\x06active\x18\x05 \x01(\bR\x06active\x12)\n" +
+	"\x05teids\x18\x06 \x03(\v2\x13.gogtp.control.TEIDR\x05teids\x12/\n" +
+	"\abearers\x18\a \x03(\v2\x15.gogtp.control.BearerR\abearers\"U\n" +
+	"\x04TEID\x12\x17\n" +
+	"\aif_type\x18\x01 \x01(\rR\x06ifType\x12 \n" +
+	"\fif_type_name\x18\x02 \x01(\tR\n" +
+	"ifTypeName\x12\x12\n" +
+	"\x04teid\x18\x03 \x01(\rR\x04teid\"\xe2\x01\n" +
+	"\x06Bearer\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03ebi\x18\x02 \x01(\rR\x03ebi\x12\x10\n" +
+	"\x03apn\x18\x03 \x01(\tR\x03apn\x12#\n" +
+	"\rsubscriber_ip\x18\x04 \x01(\tR\fsubscriberIp\x12\x1f\n" +
+	"\vcharging_id\x18\x05 \x01(\rR\n" +
+	"chargingId\x12#\n" +
+	"\rincoming_teid\x18\x06 \x01(\rR\fincomingTeid\x12#\n" +
+	"\routgoing_teid\x18\a \x01(\rR\foutgoingTeid\x12\x10\n" +
+	"\x03qci\x18\b \x01(\rR\x03qci\"\xe6\x01\n" +
+	"\fSessionEvent\x124\n" +
+	"\x04type\x18\x01 \x01(\x0e2 .gogtp.control.SessionEvent.TypeR\x04type\x120\n" +
+	"\asession\x18\x02 \x01(\v2\x16.gogtp.control.SessionR\asession\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\">\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"TYPE_ADDED\x10\x01\x12\x10\n" +
+	"\fTYPE_REMOVED\x10\x022\xbe\x03\n" +
+	"\x0eSessionControl\x12Z\n" +
+	"\rCreateSession\x12#.gogtp.control.CreateSessionRequest\x1a$.gogtp.control.CreateSessionResponse\x12Z\n" +
+	"\rDeleteSession\x12#.gogtp.control.DeleteSessionRequest\x1a$.gogtp.control.DeleteSessionResponse\x12F\n" +
+	"\n" +
+	"GetSession\x12 .gogtp.control.GetSessionRequest\x1a\x16.gogtp.control.Session\x12W\n" +
+	"\fListSessions\x12\".gogtp.control.ListSessionsRequest\x1a#.gogtp.control.ListSessionsResponse\x12S\n" +
+	"\rWatchSessions\x12#.gogtp.control.WatchSessionsRequest\x1a\x1b.gogtp.control.SessionEvent0\x01B+Z)github.com/wmnsk/go-gtp/gtpgrpc/controlpbb\x06proto3"
+
+var (
+	file_control_proto_rawDescOnce sync.Once
+	file_control_proto_rawDescData []byte
+)
+
+func file_control_proto_rawDescGZIP() []byte {
+	file_control_proto_rawDescOnce.Do(func() {
+		file_control_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_control_proto_rawDesc), len(file_control_proto_rawDesc)))
+	})
+	return file_control_proto_rawDescData
+}
+
+var file_control_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_control_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_control_proto_goTypes = []any{
+	(SessionEvent_Type)(0),        // 0: gogtp.control.SessionEvent.Type
+	(*CreateSessionRequest)(nil),  // 1: gogtp.control.CreateSessionRequest
+	(*CreateSessionResponse)(nil), // 2: gogtp.control.CreateSessionResponse
+	(*DeleteSessionRequest)(nil),  // 3: gogtp.control.DeleteSessionRequest
+	(*DeleteSessionResponse)(nil), // 4: gogtp.control.DeleteSessionResponse
+	(*GetSessionRequest)(nil),     // 5: gogtp.control.GetSessionRequest
+	(*ListSessionsRequest)(nil),   // 6: gogtp.control.ListSessionsRequest
+	(*ListSessionsResponse)(nil),  // 7: gogtp.control.ListSessionsResponse
+	(*WatchSessionsRequest)(nil),  // 8: gogtp.control.WatchSessionsRequest
+	(*Session)(nil),               // 9: gogtp.control.Session
+	(*TEID)(nil),                  // 10: gogtp.control.TEID
+	(*Bearer)(nil),                // 11: gogtp.control.Bearer
+	(*SessionEvent)(nil),          // 12: gogtp.control.SessionEvent
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
+}
+var file_control_proto_depIdxs = []int32{
+	9,  // 0: gogtp.control.CreateSessionResponse.session:type_name -> gogtp.control.Session
+	9,  // 1: gogtp.control.ListSessionsResponse.sessions:type_name -> gogtp.control.Session
+	10, // 2: gogtp.control.Session.teids:type_name -> gogtp.control.TEID
+	11, // 3: gogtp.control.Session.bearers:type_name -> gogtp.control.Bearer
+	0,  // 4: gogtp.control.SessionEvent.type:type_name -> gogtp.control.SessionEvent.Type
+	9,  // 5: gogtp.control.SessionEvent.session:type_name -> gogtp.control.Session
+	13, // 6: gogtp.control.SessionEvent.time:type_name -> google.protobuf.Timestamp
+	1,  // 7: gogtp.control.SessionControl.CreateSession:input_type -> gogtp.control.CreateSessionRequest
+	3,  // 8: gogtp.control.SessionControl.DeleteSession:input_type -> gogtp.control.DeleteSessionRequest
+	5,  // 9: gogtp.control.SessionControl.GetSession:input_type -> gogtp.control.GetSessionRequest
+	6,  // 10: gogtp.control.SessionControl.ListSessions:input_type -> gogtp.control.ListSessionsRequest
+	8,  // 11: gogtp.control.SessionControl.WatchSessions:input_type -> gogtp.control.WatchSessionsRequest
+	2,  // 12: gogtp.control.SessionControl.CreateSession:output_type -> gogtp.control.CreateSessionResponse
+	4,  // 13: gogtp.control.SessionControl.DeleteSession:output_type -> gogtp.control.DeleteSessionResponse
+	9,  // 14: gogtp.control.SessionControl.GetSession:output_type -> gogtp.control.Session
+	7,  // 15: gogtp.control.SessionControl.ListSessions:output_type -> gogtp.control.ListSessionsResponse
+	12, // 16: gogtp.control.SessionControl.WatchSessions:output_type -> gogtp.control.SessionEvent
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_control_proto_init() }
+func file_control_proto_init() {
+	if File_control_proto != nil {
+		return
+	}
+	file_control_proto_msgTypes[4].OneofWrappers = []any{
+		(*GetSessionRequest_Imsi)(nil),
+		(*GetSessionRequest_Teid)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_control_proto_rawDesc), len(file_control_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_control_proto_goTypes,
+		DependencyIndexes: file_control_proto_depIdxs,
+		EnumInfos:         file_control_proto_enumTypes,
+		MessageInfos:      file_control_proto_msgTypes,
+	}.Build()
+	File_control_proto = out.File
+	file_control_proto_goTypes = nil
+	file_control_proto_depIdxs = nil
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpgrpc provides a gRPC control interface over a GTPv2-C Conn, to let
+// the external orchestrators create and delete sessions, look up the sessions
+// and their TEIDs, and watch the sessions added and removed.
+//
+// The service is defined in controlpb/control.proto, and the Go code in
+// controlpb is generated from it with protoc-gen-go and protoc-gen-go-grpc.
+//
+// The sessions are created and deleted by sending the request messages on the
+// Conn. The responses should be handled by the handlers registered on the Conn,
+// as they are for the sessions created by the gateway itself.
+package gtpgrpc
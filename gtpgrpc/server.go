@@ -0,0 +1,325 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpgrpc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/wmnsk/go-gtp/gtpgrpc/controlpb"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Limits of the number of sessions returned by ListSessions.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// watchBufferSize is the number of events buffered for each WatchSessions stream.
+// The stream is closed with ResourceExhausted if the client cannot keep up.
+const watchBufferSize = 64
+
+// Server implements controlpb.SessionControlServer over a Conn.
+type Server struct {
+	controlpb.UnimplementedSessionControlServer
+
+	conn *v2.Conn
+
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+
+	// DeleteIFType is the InterfaceType of the peer's TEID to send Delete Session
+	// Request with, used when if_type is not given.
+	DeleteIFType uint8
+}
+
+type watcher struct {
+	prefix   string
+	eventCh  chan *controlpb.SessionEvent
+	overflow chan struct{}
+}
+
+// NewServer creates a new Server over conn.
+//
+// deleteIFType is the InterfaceType of the peer's TEID that the gateway uses to
+// send Delete Session Request, e.g., IFTypeS11S4SGWGTPC on MME.
+//
+// The Server sets the SessionEventHandler of conn to stream the events, which
+// replaces the one set before.
+func NewServer(conn *v2.Conn, deleteIFType uint8) *Server {
+	s := &Server{
+		conn:         conn,
+		watchers:     map[*watcher]struct{}{},
+		DeleteIFType: deleteIFType,
+	}
+	conn.SetSessionEventHandler(s.publish)
+	return s
+}
+
+// Register registers the Server to the grpc.Server.
+func (s *Server) Register(g *grpc.Server) {
+	controlpb.RegisterSessionControlServer(g, s)
+}
+
+// CreateSession sends Create Session Request with the IEs built from req.
+func (s *Server) CreateSession(ctx context.Context, req *controlpb.CreateSessionRequest) (*controlpb.CreateSessionResponse, error) {
+	if req.Imsi == "" {
+		return nil, status.Error(codes.InvalidArgument, "imsi is required")
+	}
+	raddr, err := net.ResolveUDPAddr("udp", req.PeerAddr)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid peer_addr: %s", err)
+	}
+	if _, err := s.conn.GetSessionByIMSI(req.Imsi); err == nil {
+		return nil, status.Errorf(codes.AlreadyExists, "session already exists: %s", req.Imsi)
+	}
+
+	ie, err := s.buildIEs(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sess, err := s.conn.CreateSession(raddr, ie...)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	s.conn.AddSession(sess)
+
+	return &controlpb.CreateSessionResponse{Session: toSession(sess)}, nil
+}
+
+func (s *Server) buildIEs(req *controlpb.CreateSessionRequest) ([]*ies.IE, error) {
+	senderIP := req.SenderIpv4
+	if senderIP == "" {
+		if laddr, ok := s.conn.LocalAddr().(*net.UDPAddr); ok {
+			senderIP = laddr.IP.String()
+		}
+	}
+
+	ie := []*ies.IE{
+		ies.NewIMSI(req.Imsi),
+		s.conn.NewFTEID(uint8(req.SenderIfType), senderIP, ""),
+	}
+	if req.Msisdn != "" {
+		ie = append(ie, ies.NewMSISDN(req.Msisdn))
+	}
+	if req.Mei != "" {
+		ie = append(ie, ies.NewMobileEquipmentIdentity(req.Mei))
+	}
+	if req.Apn != "" {
+		ie = append(ie, ies.NewAccessPointName(req.Apn))
+	}
+	if req.RatType != 0 {
+		ie = append(ie, ies.NewRATType(uint8(req.RatType)))
+	}
+	if req.Ebi != 0 {
+		ie = append(ie, ies.NewBearerContext(ies.NewEPSBearerID(uint8(req.Ebi))))
+	}
+	for _, b := range req.Ies {
+		i, err := ies.Decode(b)
+		if err != nil {
+			return nil, err
+		}
+		ie = append(ie, i)
+	}
+	return ie, nil
+}
+
+// DeleteSession sends Delete Session Request for the session.
+func (s *Server) DeleteSession(ctx context.Context, req *controlpb.DeleteSessionRequest) (*controlpb.DeleteSessionResponse, error) {
+	ifType := s.DeleteIFType
+	if req.IfType != 0 {
+		ifType = uint8(req.IfType)
+	}
+
+	sess, err := s.conn.GetSessionByIMSI(req.Imsi)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if !sess.IsActive() {
+		return nil, status.Errorf(codes.FailedPrecondition, "session is not active: %s", req.Imsi)
+	}
+
+	if err := sess.Delete(s.conn, ifType); err != nil {
+		if err == v2.ErrTEIDNotFound {
+			return nil, status.Errorf(codes.FailedPrecondition, "no TEID for %s", v2.IFType(ifType))
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &controlpb.DeleteSessionResponse{}, nil
+}
+
+// GetSession returns the session looked up by IMSI or TEID.
+func (s *Server) GetSession(ctx context.Context, req *controlpb.GetSessionRequest) (*controlpb.Session, error) {
+	var (
+		sess *v2.Session
+		err  error
+	)
+	switch key := req.Key.(type) {
+	case *controlpb.GetSessionRequest_Imsi:
+		sess, err = s.conn.GetSessionByIMSI(key.Imsi)
+	case *controlpb.GetSessionRequest_Teid:
+		sess, err = s.conn.GetSessionByTEID(key.Teid)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "imsi or teid is required")
+	}
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return toSession(sess), nil
+}
+
+// ListSessions returns the sessions matched in the order of IMSI.
+func (s *Server) ListSessions(ctx context.Context, req *controlpb.ListSessionsRequest) (*controlpb.ListSessionsResponse, error) {
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be less than or equal to %d", MaxLimit)
+	}
+
+	var sessions []*v2.Session
+	for _, sess := range s.conn.ListSessions() {
+		if strings.HasPrefix(imsiOf(sess), req.ImsiPrefix) {
+			sessions = append(sessions, sess)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return imsiOf(sessions[i]) < imsiOf(sessions[j])
+	})
+
+	res := &controlpb.ListSessionsResponse{Total: uint32(len(sessions))}
+	for i := int(req.Offset); i < len(sessions) && i < int(req.Offset)+limit; i++ {
+		res.Sessions = append(res.Sessions, toSession(sessions[i]))
+	}
+	return res, nil
+}
+
+// WatchSessions streams the events of the sessions added or removed.
+//
+// The header is sent when the events start to be watched, so that the client
+// can wait for it before changing the sessions.
+func (s *Server) WatchSessions(req *controlpb.WatchSessionsRequest, stream controlpb.SessionControl_WatchSessionsServer) error {
+	w := &watcher{
+		prefix:   req.ImsiPrefix,
+		eventCh:  make(chan *controlpb.SessionEvent, watchBufferSize),
+		overflow: make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, w)
+		s.mu.Unlock()
+	}()
+
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-w.overflow:
+			return status.Error(codes.ResourceExhausted, "too many events not received")
+		case ev := <-w.eventCh:
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish is the SessionEventHandlerFunc passing the events to the watchers.
+func (s *Server) publish(c *v2.Conn, event v2.SessionEvent, sess *v2.Session) {
+	ev := &controlpb.SessionEvent{Session: toSession(sess), Time: timestamppb.New(time.Now())}
+	switch event {
+	case v2.SessionEventAdded:
+		ev.Type = controlpb.SessionEvent_TYPE_ADDED
+	case v2.SessionEventRemoved:
+		ev.Type = controlpb.SessionEvent_TYPE_REMOVED
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for w := range s.watchers {
+		if !strings.HasPrefix(ev.Session.Imsi, w.prefix) {
+			continue
+		}
+		select {
+		case w.eventCh <- ev:
+		default:
+			// drops the slow watcher instead of blocking the handlers on Conn.
+			delete(s.watchers, w)
+			close(w.overflow)
+		}
+	}
+}
+
+func toSession(sess *v2.Session) *controlpb.Session {
+	ps := &controlpb.Session{
+		Imsi:   imsiOf(sess),
+		Active: sess.IsActive(),
+	}
+	if sess.Subscriber != nil {
+		ps.Msisdn = sess.MSISDN
+		ps.Mei = sess.IMEI
+	}
+	if sess.PeerAddr != nil {
+		ps.PeerAddr = sess.PeerAddr.String()
+	}
+
+	for ifType, teid := range sess.TEIDs() {
+		ps.Teids = append(ps.Teids, &controlpb.TEID{
+			IfType:     uint32(ifType),
+			IfTypeName: v2.IFType(ifType).String(),
+			Teid:       teid,
+		})
+	}
+	sort.Slice(ps.Teids, func(i, j int) bool {
+		return ps.Teids[i].IfType < ps.Teids[j].IfType
+	})
+
+	for name, br := range sess.Bearers() {
+		pb := &controlpb.Bearer{
+			Name:         name,
+			Ebi:          uint32(br.EBI),
+			Apn:          br.APN,
+			SubscriberIp: br.SubscriberIP,
+			ChargingId:   br.ChargingID,
+			IncomingTeid: br.IncomingTEID(),
+			OutgoingTeid: br.OutgoingTEID(),
+		}
+		if br.QoSProfile != nil {
+			pb.Qci = uint32(br.QCI)
+		}
+		ps.Bearers = append(ps.Bearers, pb)
+	}
+	sort.Slice(ps.Bearers, func(i, j int) bool {
+		return ps.Bearers[i].Name < ps.Bearers[j].Name
+	})
+	return ps
+}
+
+func imsiOf(sess *v2.Session) string {
+	if sess.Subscriber == nil {
+		return ""
+	}
+	return sess.IMSI
+}
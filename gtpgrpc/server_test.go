@@ -0,0 +1,168 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/wmnsk/go-gtp/gtpgrpc"
+	"github.com/wmnsk/go-gtp/gtpgrpc/controlpb"
+	"github.com/wmnsk/go-gtp/gtptest"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func setup(t *testing.T) (*v2.Conn, net.PacketConn, controlpb.SessionControlClient) {
+	t.Helper()
+
+	conn := gtptest.Listen(t, "127.0.0.1:0", 0)
+	peer := gtptest.ListenPeer(t)
+
+	lis := bufconn.Listen(1 << 16)
+	g := grpc.NewServer()
+	gtpgrpc.NewServer(conn, v2.IFTypeS11S4SGWGTPC).Register(g)
+	go func() { _ = g.Serve(lis) }()
+
+	cc, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close(); g.Stop() })
+
+	return conn, peer, controlpb.NewSessionControlClient(cc)
+}
+
+func TestCreateAndGetSession(t *testing.T) {
+	_, peer, client := setup(t)
+	ctx := context.Background()
+
+	res, err := client.CreateSession(ctx, &controlpb.CreateSessionRequest{
+		PeerAddr:     peer.LocalAddr().String(),
+		Imsi:         "001010000000001",
+		Msisdn:       "8130900000001",
+		Apn:          "some-apn.example",
+		Ebi:          5,
+		SenderIfType: uint32(v2.IFTypeS11MMEGTPC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Session.Imsi != "001010000000001" || res.Session.Active || len(res.Session.Teids) != 1 {
+		t.Errorf("unexpected session: %v", res.Session)
+	}
+
+	msg := gtptest.ReadMessage(t, peer)
+	csReq, ok := msg.(*messages.CreateSessionRequest)
+	if !ok {
+		t.Fatalf("unexpected message: %s", msg.MessageTypeName())
+	}
+	if imsi := csReq.IMSI.IMSI(); imsi != "001010000000001" {
+		t.Errorf("unexpected IMSI: %s", imsi)
+	}
+
+	teid := res.Session.Teids[0].Teid
+	sess, err := client.GetSession(ctx, &controlpb.GetSessionRequest{Key: &controlpb.GetSessionRequest_Teid{Teid: teid}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Imsi != "001010000000001" || sess.Teids[0].IfTypeName != "S11MMEGTPC" {
+		t.Errorf("unexpected session: %v", sess)
+	}
+
+	_, err = client.CreateSession(ctx, &controlpb.CreateSessionRequest{
+		PeerAddr: peer.LocalAddr().String(), Imsi: "001010000000001",
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("got %v, want AlreadyExists", err)
+	}
+	_, err = client.GetSession(ctx, &controlpb.GetSessionRequest{Key: &controlpb.GetSessionRequest_Imsi{Imsi: "999"}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestListAndDeleteSessions(t *testing.T) {
+	conn, peer, client := setup(t)
+	ctx := context.Background()
+
+	for i, imsi := range []string{"440100000000001", "001010000000002", "001010000000001"} {
+		sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: imsi})
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, uint32(0x100+i))
+		if i != 0 {
+			if err := sess.Activate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		conn.AddSession(sess)
+	}
+
+	res, err := client.ListSessions(ctx, &controlpb.ListSessionsRequest{ImsiPrefix: "00101", Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 2 || len(res.Sessions) != 1 || res.Sessions[0].Imsi != "001010000000002" {
+		t.Errorf("unexpected sessions: %v", res)
+	}
+
+	_, err = client.DeleteSession(ctx, &controlpb.DeleteSessionRequest{Imsi: "440100000000001"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("got %v, want FailedPrecondition", err)
+	}
+	if _, err := client.DeleteSession(ctx, &controlpb.DeleteSessionRequest{Imsi: "001010000000001"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := gtptest.ReadMessage(t, peer)
+	if msg.MessageType() != messages.MsgTypeDeleteSessionRequest || msg.TEID() != 0x102 {
+		t.Errorf("unexpected message: %s, TEID: %#x", msg.MessageTypeName(), msg.TEID())
+	}
+}
+
+func TestWatchSessions(t *testing.T) {
+	conn, peer, client := setup(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchSessions(ctx, &controlpb.WatchSessionsRequest{ImsiPrefix: "00101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the header is sent after the events are ready to be streamed.
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	ignored := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: "440100000000001"})
+	sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: "001010000000001"})
+	conn.AddSession(ignored)
+	conn.AddSession(sess)
+	conn.RemoveSession(sess)
+
+	for _, want := range []controlpb.SessionEvent_Type{
+		controlpb.SessionEvent_TYPE_ADDED, controlpb.SessionEvent_TYPE_REMOVED,
+	} {
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Type != want || ev.Session.Imsi != "001010000000001" {
+			t.Errorf("got %v, want %v", ev, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpha
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Error definitions.
+var (
+	ErrNotSynced        = errors.New("delta received before sync")
+	ErrUnknownOperation = errors.New("unknown operation")
+	ErrAlreadyPromoted  = errors.New("standby already promoted")
+)
+
+// Op is the operation of a Delta.
+type Op string
+
+// Op definitions.
+const (
+	// OpSync is sent first on connection, to replace all the sessions on the Standby
+	// with the ones sent with OpCreate following it.
+	OpSync   Op = "sync"
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Delta is a change of the sessions sent from the Primary to the Standby.
+type Delta struct {
+	Op Op `json:"op"`
+
+	// Version and RestartCounter are set with OpSync only. Version is the
+	// v2.SnapshotVersion of the sessions.
+	Version        int   `json:"version,omitempty"`
+	RestartCounter uint8 `json:"restart_counter,omitempty"`
+
+	IMSI string `json:"imsi,omitempty"`
+
+	// Session is set with OpCreate and OpUpdate, in the format of v2.MarshalSession.
+	Session json.RawMessage `json:"session,omitempty"`
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpha provides the replication of the sessions on a GTPv2-C Conn from the
+// active gateway to the standby ones, to take over the sessions on failover.
+//
+// The Primary streams the sessions created, updated and deleted on the Conn to the
+// standby instances connected over TCP, as the deltas in JSON lines. The sessions are
+// in the format of v2.MarshalSession, which is the one of the snapshot written by
+// v2.Conn.SnapshotSessions.
+//
+//	p := gtpha.NewPrimary(s11Conn)
+//	s11Conn.SetSessionEventHandler(p.HandleSessionEvent)
+//	go p.Serve(ln)
+//	// ...
+//	// after the session is modified, e.g., on Modify Bearer Request.
+//	p.Update(session)
+//
+// The session event handler tells only the sessions added and removed, and Update
+// should be called when the session is modified after added to the Conn.
+//
+// The Standby keeps the sessions received from the Primary until it is promoted. When
+// the connection is lost, Promote adds the sessions to the Conn of the standby with
+// the RestartCounter of the Primary, so that the peers keep the sessions, and calls
+// the function given for each session to re-arm the relays and timers on the gateway.
+//
+//	s := gtpha.NewStandby()
+//	conn, err := net.Dial("tcp", primaryAddr)
+//	// ...
+//	err = s.Run(conn) // returns when the Primary goes away.
+//	// ...
+//	err = s.Promote(s11Conn, func(sess *v2.Session) error {
+//		return rearm(sess)
+//	})
+package gtpha
@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpha_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtpha"
+	"github.com/wmnsk/go-gtp/gtptest"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+func newSession(imsi string, teid uint32) *v2.Session {
+	peerAddr := &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 2123}
+	sess := v2.NewSession(peerAddr, &v2.Subscriber{IMSI: imsi})
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
+	return sess
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(1 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	activeConn := gtptest.Listen(t, "127.0.0.101:2123", 5)
+	standbyConn := gtptest.Listen(t, "127.0.0.102:2123", 0)
+
+	p := gtpha.NewPrimary(activeConn)
+	defer p.Close()
+	activeConn.SetSessionEventHandler(p.HandleSessionEvent)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go p.Serve(ln)
+
+	// added before the Standby connects, to be sent on sync.
+	activeConn.AddSession(newSession("123451234567890", 0x11111111))
+
+	s := gtpha.NewStandby()
+	c, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- s.Run(c)
+	}()
+	waitFor(t, func() bool { return p.Len() == 1 && s.Len() == 1 })
+
+	updated := newSession("123451234567891", 0x22222222)
+	activeConn.AddSession(updated)
+	removed := newSession("123451234567892", 0x33333333)
+	activeConn.AddSession(removed)
+	waitFor(t, func() bool { return s.Len() == 3 })
+
+	updated.AddTEID(v2.IFTypeS11MMEGTPC, 0x44444444)
+	p.Update(updated)
+	activeConn.RemoveSession(removed)
+	waitFor(t, func() bool { return s.Len() == 2 })
+
+	// the failover.
+	p.Close()
+	select {
+	case err := <-doneCh:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for Run to return")
+	}
+
+	var rearmed []string
+	if err := s.Promote(standbyConn, func(sess *v2.Session) error {
+		rearmed = append(rearmed, sess.IMSI)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if standbyConn.RestartCounter != 5 {
+		t.Errorf("RestartCounter not taken over: %d", standbyConn.RestartCounter)
+	}
+	if len(rearmed) != 2 || rearmed[0] != "123451234567890" || rearmed[1] != "123451234567891" {
+		t.Errorf("unexpected sessions re-armed: %v", rearmed)
+	}
+	if _, err := standbyConn.GetSessionByIMSI("123451234567892"); err == nil {
+		t.Error("removed session promoted")
+	}
+	sess, err := standbyConn.GetSessionByTEID(0x22222222)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teid, err := sess.GetTEID(v2.IFTypeS11MMEGTPC); err != nil || teid != 0x44444444 {
+		t.Errorf("update not replicated: %#x, %v", teid, err)
+	}
+
+	if err := s.Promote(standbyConn, nil); !errors.Is(err, gtpha.ErrAlreadyPromoted) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestStandbyNotSynced(t *testing.T) {
+	primary, standby := net.Pipe()
+	defer primary.Close()
+
+	s := gtpha.NewStandby()
+	doneCh := make(chan error, 1)
+	go func() {
+		doneCh <- s.Run(standby)
+	}()
+
+	if _, err := primary.Write([]byte(`{"op":"create","imsi":"123451234567890","session":{}}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-doneCh; !errors.Is(err, gtpha.ErrNotSynced) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := s.Promote(nil, nil); !errors.Is(err, gtpha.ErrNotSynced) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpha
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// DefaultWriteTimeout is the default WriteTimeout of Primary.
+const DefaultWriteTimeout = 1 * time.Second
+
+// Primary streams the changes of the sessions on a Conn to the Standby instances.
+type Primary struct {
+	conn *v2.Conn
+
+	mu       sync.Mutex
+	standbys map[net.Conn]*json.Encoder
+	closed   bool
+
+	// WriteTimeout is the time to wait for a Standby to receive a delta. The Standby
+	// that does not receive it in time is disconnected, not to block the handlers on
+	// the Conn.
+	WriteTimeout time.Duration
+}
+
+// NewPrimary creates a new Primary replicating the sessions on conn.
+//
+// HandleSessionEvent should be set as the session event handler of conn, or called
+// from it, to replicate the sessions added and removed.
+func NewPrimary(conn *v2.Conn) *Primary {
+	return &Primary{
+		conn:         conn,
+		standbys:     map[net.Conn]*json.Encoder{},
+		WriteTimeout: DefaultWriteTimeout,
+	}
+}
+
+// Serve accepts the connections from the Standby instances on ln, and sends all the
+// sessions on the Conn to each of them before streaming the changes.
+//
+// Serve always returns a non-nil error, when ln fails to accept.
+func (p *Primary) Serve(ln net.Listener) error {
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		p.addStandby(c)
+	}
+}
+
+// Close disconnects all the Standby instances. No deltas are sent after Close.
+func (p *Primary) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for c := range p.standbys {
+		c.Close()
+		delete(p.standbys, c)
+	}
+	return nil
+}
+
+// Len returns the number of the Standby instances connected.
+func (p *Primary) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.standbys)
+}
+
+// HandleSessionEvent sends the session added or removed to the Standby instances.
+// It is a v2.SessionEventHandlerFunc.
+func (p *Primary) HandleSessionEvent(c *v2.Conn, event v2.SessionEvent, sess *v2.Session) {
+	switch event {
+	case v2.SessionEventAdded:
+		p.sendSession(OpCreate, sess)
+	case v2.SessionEventRemoved:
+		p.broadcast(&Delta{Op: OpDelete, IMSI: sess.IMSI})
+	}
+}
+
+// Update sends the session to the Standby instances again, after it is modified on
+// the Conn, e.g., with the TEIDs or the bearers.
+func (p *Primary) Update(sess *v2.Session) {
+	p.sendSession(OpUpdate, sess)
+}
+
+func (p *Primary) sendSession(op Op, sess *v2.Session) {
+	b, err := v2.MarshalSession(sess)
+	if err != nil {
+		return
+	}
+	p.broadcast(&Delta{Op: op, IMSI: sess.IMSI, Session: b})
+}
+
+// the deltas are sent while holding mu, so that the ones for a new Standby are not
+// interleaved with the changes on the Conn.
+func (p *Primary) broadcast(d *Delta) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for c, enc := range p.standbys {
+		if err := p.send(c, enc, d); err != nil {
+			c.Close()
+			delete(p.standbys, c)
+		}
+	}
+}
+
+func (p *Primary) addStandby(c net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		c.Close()
+		return
+	}
+
+	enc := json.NewEncoder(c)
+	deltas := []*Delta{{
+		Op:             OpSync,
+		Version:        v2.SnapshotVersion,
+		RestartCounter: p.conn.RestartCounter,
+	}}
+	for _, sess := range p.conn.ListSessions() {
+		b, err := v2.MarshalSession(sess)
+		if err != nil {
+			continue
+		}
+		deltas = append(deltas, &Delta{Op: OpCreate, IMSI: sess.IMSI, Session: b})
+	}
+
+	for _, d := range deltas {
+		if err := p.send(c, enc, d); err != nil {
+			c.Close()
+			return
+		}
+	}
+	p.standbys[c] = enc
+}
+
+// the caller must hold mu.
+func (p *Primary) send(c net.Conn, enc *json.Encoder, d *Delta) error {
+	if p.WriteTimeout > 0 {
+		if err := c.SetWriteDeadline(time.Now().Add(p.WriteTimeout)); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(d)
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpha
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// Standby keeps the sessions received from the Primary, to take them over on failover.
+type Standby struct {
+	mu             sync.Mutex
+	synced         bool
+	promoted       bool
+	restartCounter uint8
+	sessions       map[string]json.RawMessage
+}
+
+// NewStandby creates a new Standby.
+func NewStandby() *Standby {
+	return &Standby{sessions: map[string]json.RawMessage{}}
+}
+
+// Run reads the deltas from the Primary on conn and applies them to the sessions kept,
+// until the connection is closed. conn is closed when Run returns.
+//
+// Run returns nil when the Primary closes the connection, and the error otherwise.
+// In both cases the sessions received so far are kept, and Run can be called again
+// with a new connection, which begins with the sync of all the sessions.
+func (s *Standby) Run(conn net.Conn) error {
+	defer conn.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		d := &Delta{}
+		if err := dec.Decode(d); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := s.apply(d); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Standby) apply(d *Delta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.promoted {
+		return ErrAlreadyPromoted
+	}
+
+	switch d.Op {
+	case OpSync:
+		if d.Version != v2.SnapshotVersion {
+			return fmt.Errorf("%w: %d", v2.ErrUnsupportedSnapshot, d.Version)
+		}
+		s.synced = true
+		s.restartCounter = d.RestartCounter
+		s.sessions = map[string]json.RawMessage{}
+		return nil
+	case OpCreate, OpUpdate, OpDelete:
+		if !s.synced {
+			return ErrNotSynced
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownOperation, d.Op)
+	}
+
+	if d.Op == OpDelete {
+		delete(s.sessions, d.IMSI)
+		return nil
+	}
+	s.sessions[d.IMSI] = d.Session
+	return nil
+}
+
+// Len returns the number of the sessions kept.
+func (s *Standby) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Promote adds the sessions kept to conn with the RestartCounter of the Primary, and
+// calls rearm for each session to set up what is not in the session, e.g., the
+// relays on U-Plane and the timers of the gateway. rearm can be nil.
+//
+// Promote should be called after Run returns, and the Standby cannot be used after
+// promoted. The sessions are not added if any of them fails to restore.
+func (s *Standby) Promote(conn *v2.Conn, rearm func(*v2.Session) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.promoted {
+		return ErrAlreadyPromoted
+	}
+	if !s.synced {
+		return ErrNotSynced
+	}
+
+	imsis := make([]string, 0, len(s.sessions))
+	for imsi := range s.sessions {
+		imsis = append(imsis, imsi)
+	}
+	sort.Strings(imsis)
+
+	// the sessions are restored by RestoreSessions as a snapshot, not to duplicate
+	// the format in this package.
+	snap := struct {
+		Version        int               `json:"version"`
+		RestartCounter uint8             `json:"restart_counter"`
+		Sessions       []json.RawMessage `json:"sessions"`
+	}{
+		Version:        v2.SnapshotVersion,
+		RestartCounter: s.restartCounter,
+		Sessions:       make([]json.RawMessage, 0, len(imsis)),
+	}
+	for _, imsi := range imsis {
+		snap.Sessions = append(snap.Sessions, s.sessions[imsi])
+	}
+
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := conn.RestoreSessions(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	s.promoted = true
+
+	if rearm == nil {
+		return nil
+	}
+	for _, imsi := range imsis {
+		sess, err := conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			return err
+		}
+		if err := rearm(sess); err != nil {
+			return fmt.Errorf("failed to re-arm session of %s: %w", imsi, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpinterwork provides the converters between the GTPv1 and GTPv2 IEs,
+// for the nodes interworking between Gn/Gp and S5/S8 such as the SGSN-MME
+// interworking gateway or the P-GW serving the Gn/Gp SGSNs.
+//
+// The mappings follow 3GPP TS 23.401 Annex E where it is defined. The mappings that
+// are left to the operator, such as the locations between E-UTRAN and UTRAN/GERAN,
+// are given by the user.
+package gtpinterwork
@@ -0,0 +1,245 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpinterwork
+
+import (
+	"errors"
+
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// ErrInvalidIE indicates that the IE given is nil or not the type expected.
+var ErrInvalidIE = errors.New("invalid IE given")
+
+// Traffic Class definitions in QoS Profile.
+const (
+	TrafficClassSubscribed uint8 = iota
+	TrafficClassConversational
+	TrafficClassStreaming
+	TrafficClassInteractive
+	TrafficClassBackground
+)
+
+// The boundaries of the ARP Priority Level used in the mapping between the ARP in
+// QoS Profile(1-3) and the ARP Priority Level in EPS(1-15). The Priority Levels
+// 1-H, H+1-M and M+1-15 are mapped to 1, 2 and 3 respectively.
+const (
+	PriorityLevelH uint8 = 5
+	PriorityLevelM uint8 = 10
+)
+
+// QoSProfileToBearerQoS converts the QoS Profile into Bearer QoS IE.
+//
+// The QCI is derived from the Traffic Class and the related fields, and the MBR and
+// GBR are set only for the GBR QCIs(1-4). The R97/98 format is treated as Interactive
+// or Background class, following the mapping defined in 3GPP TS 23.107.
+//
+// As there is no corresponding value in QoS Profile, the Pre-emption Capability is
+// set to "disabled" and the Pre-emption Vulnerability to "enabled".
+func QoSProfileToBearerQoS(qos *v1ies.QoSProfilePayload) *v2ies.IE {
+	qci := QoSProfileToQCI(qos)
+
+	var mbrUL, mbrDL, gbrUL, gbrDL uint64
+	if isGBR(qci) {
+		mbrUL, mbrDL = qos.MaximumBitRateULKbps(), qos.MaximumBitRateDLKbps()
+		gbrUL, gbrDL = qos.GuaranteedBitRateULKbps(), qos.GuaranteedBitRateDLKbps()
+	}
+
+	return v2ies.NewBearerQoS(
+		1, ARPToPriorityLevel(qos.AllocationRetentionPriority), 0,
+		qci, mbrUL, mbrDL, gbrUL, gbrDL,
+	)
+}
+
+// QoSProfileToAMBR converts the QoS Profile into AMBR IE, with the Maximum bit rate
+// in it as the APN-AMBR. The QoS Profile given should be the one of the non-GBR PDP
+// context, typically the first one activated for the APN.
+func QoSProfileToAMBR(qos *v1ies.QoSProfilePayload) *v2ies.IE {
+	if qos.IsR99 {
+		return v2ies.NewAggregateMaximumBitRate(
+			uint32(qos.MaximumBitRateULKbps()), uint32(qos.MaximumBitRateDLKbps()),
+		)
+	}
+
+	peak := uint32(peakThroughputKbps(qos.PeakThroughput))
+	return v2ies.NewAggregateMaximumBitRate(peak, peak)
+}
+
+// BearerQoSToQoSProfile converts the Bearer QoS IE into QoS Profile in R99 format.
+//
+// For the non-GBR QCIs, the Maximum bit rate is taken from the AMBR IE if given, as the
+// APN-AMBR is the closest value in EPS. ambr can be nil. The fields in R97/98 format are
+// also set so that the QoS Profile can be understood by the pre-R99 nodes.
+func BearerQoSToQoSProfile(bqos, ambr *v2ies.IE) (*v1ies.QoSProfilePayload, error) {
+	if bqos == nil || bqos.Type != v2ies.BearerQoS || len(bqos.Payload) < 22 {
+		return nil, ErrInvalidIE
+	}
+	if ambr != nil && (ambr.Type != v2ies.AggregateMaximumBitRate || len(ambr.Payload) < 8) {
+		return nil, ErrInvalidIE
+	}
+
+	qci := bqos.QCILabel()
+	arp := PriorityLevelToARP(bqos.PriorityLevel())
+	qos := NewQoSProfileFromQCI(arp, qci)
+
+	if isGBR(qci) {
+		qos.SetMaximumBitRateKbps(bqos.MBRForUplink(), bqos.MBRForDownlink())
+		qos.SetGuaranteedBitRateKbps(bqos.GBRForUplink(), bqos.GBRForDownlink())
+	} else if ambr != nil {
+		qos.SetMaximumBitRateKbps(
+			uint64(ambr.AggregateMaximumBitRateUp()), uint64(ambr.AggregateMaximumBitRateDown()),
+		)
+	}
+	if mbr := qos.MaximumBitRateDLKbps(); mbr != 0 {
+		qos.PeakThroughput = peakThroughputClass(mbr)
+	}
+
+	return qos, nil
+}
+
+// NewQoSProfileFromQCI creates a new QoSProfilePayload in R99 format with the Traffic Class
+// and the related fields derived from the QCI. The bit rates are left "subscribed".
+func NewQoSProfileFromQCI(arp, qci uint8) *v1ies.QoSProfilePayload {
+	qos := &v1ies.QoSProfilePayload{
+		AllocationRetentionPriority: arp,
+		IsR99:                       true,
+		ReliabilityClass:            3,
+		PrecedenceClass:             arp,
+		MeanThroughput:              0x1f, // best effort
+	}
+
+	switch qci {
+	case 1:
+		qos.TrafficClass = TrafficClassConversational
+		qos.TransferDelay = 0x0a // 100ms
+		qos.SetSignallingIndicationAndSSD(false, 1)
+	case 2:
+		qos.TrafficClass = TrafficClassConversational
+		qos.TransferDelay = 0x0f // 150ms
+	case 3:
+		qos.TrafficClass = TrafficClassConversational
+		qos.TransferDelay = 0x08 // 80ms
+	case 4:
+		qos.TrafficClass = TrafficClassStreaming
+		qos.TransferDelay = 0x12 // 300ms
+	case 5:
+		qos.TrafficClass = TrafficClassInteractive
+		qos.TrafficHandlingPriority = 1
+		qos.SetSignallingIndicationAndSSD(true, 0)
+	case 6:
+		qos.TrafficClass = TrafficClassInteractive
+		qos.TrafficHandlingPriority = 1
+	case 7:
+		qos.TrafficClass = TrafficClassInteractive
+		qos.TrafficHandlingPriority = 2
+	case 8:
+		qos.TrafficClass = TrafficClassInteractive
+		qos.TrafficHandlingPriority = 3
+	default:
+		qos.TrafficClass = TrafficClassBackground
+	}
+
+	switch qos.TrafficClass {
+	case TrafficClassInteractive:
+		qos.DelayClass = qos.TrafficHandlingPriority
+	case TrafficClassBackground:
+		qos.DelayClass = 4
+	default:
+		qos.DelayClass = 1
+	}
+
+	return qos
+}
+
+// QoSProfileToQCI returns the QCI derived from the QoS Profile.
+func QoSProfileToQCI(qos *v1ies.QoSProfilePayload) uint8 {
+	if !qos.IsR99 {
+		switch qos.DelayClass {
+		case 1:
+			return 6
+		case 2:
+			return 7
+		case 3:
+			return 8
+		default:
+			return 9
+		}
+	}
+
+	switch qos.TrafficClass {
+	case TrafficClassConversational:
+		if qos.SourceStatisticsDescriptor() == 1 {
+			return 1
+		}
+		if qos.TransferDelay >= 0x0f { // 150ms
+			return 2
+		}
+		return 3
+	case TrafficClassStreaming:
+		return 4
+	case TrafficClassInteractive:
+		switch qos.TrafficHandlingPriority {
+		case 1:
+			if qos.SignallingIndication() {
+				return 5
+			}
+			return 6
+		case 2:
+			return 7
+		default:
+			return 8
+		}
+	default:
+		return 9
+	}
+}
+
+// ARPToPriorityLevel returns the ARP Priority Level in EPS mapped from the ARP in QoS
+// Profile, which is the highest one in the range mapped to the ARP.
+func ARPToPriorityLevel(arp uint8) uint8 {
+	switch arp {
+	case 1:
+		return 1
+	case 2:
+		return PriorityLevelH + 1
+	default:
+		return PriorityLevelM + 1
+	}
+}
+
+// PriorityLevelToARP returns the ARP in QoS Profile mapped from the ARP Priority Level.
+func PriorityLevelToARP(pl uint8) uint8 {
+	switch {
+	case pl <= PriorityLevelH:
+		return 1
+	case pl <= PriorityLevelM:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func isGBR(qci uint8) bool {
+	return qci >= 1 && qci <= 4
+}
+
+// peakThroughputKbps returns the Peak Throughput class in R97/98 format in kbps.
+// Class 1 is 1000 octets/s(8kbps), and each class doubles it up to class 9.
+func peakThroughputKbps(class uint8) uint64 {
+	if class < 1 || class > 9 {
+		return 0
+	}
+	return 8 << (class - 1)
+}
+
+// peakThroughputClass returns the highest Peak Throughput class not exceeding kbps.
+func peakThroughputClass(kbps uint64) uint8 {
+	var class uint8 = 1
+	for class < 9 && peakThroughputKbps(class+1) <= kbps {
+		class++
+	}
+	return class
+}
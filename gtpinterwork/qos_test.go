@@ -0,0 +1,113 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpinterwork_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/gtpinterwork"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestQoSProfileToBearerQoS(t *testing.T) {
+	cases := []struct {
+		description string
+		qos         *v1ies.QoSProfilePayload
+		pl, qci     uint8
+		mbr, gbr    uint64
+	}{
+		{
+			"Interactive/THP2",
+			v1ies.NewQoSProfilePayloadR99(2, gtpinterwork.TrafficClassInteractive, 2, 0x40, 0x40, 0, 0),
+			6, 7, 0, 0,
+		}, {
+			"Streaming",
+			v1ies.NewQoSProfilePayloadR99(1, gtpinterwork.TrafficClassStreaming, 0, 0x80, 0x80, 0x40, 0x40),
+			1, 4, 576, 64,
+		}, {
+			"R97/DelayClass4",
+			v1ies.NewQoSProfilePayloadR97(3, 4, 3, 9, 3, 0x1f),
+			11, 9, 0, 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			bqos := gtpinterwork.QoSProfileToBearerQoS(c.qos)
+			if got := bqos.PriorityLevel(); got != c.pl {
+				t.Errorf("unexpected Priority Level: got %d, want %d", got, c.pl)
+			}
+			if got := bqos.QCILabel(); got != c.qci {
+				t.Errorf("unexpected QCI: got %d, want %d", got, c.qci)
+			}
+			if got := bqos.MBRForDownlink(); got != c.mbr {
+				t.Errorf("unexpected MBR: got %d, want %d", got, c.mbr)
+			}
+			if got := bqos.GBRForUplink(); got != c.gbr {
+				t.Errorf("unexpected GBR: got %d, want %d", got, c.gbr)
+			}
+		})
+	}
+}
+
+func TestBearerQoSToQoSProfile(t *testing.T) {
+	t.Run("GBR", func(t *testing.T) {
+		qos, err := gtpinterwork.BearerQoSToQoSProfile(
+			v2ies.NewBearerQoS(1, 2, 0, 1, 128, 128, 64, 64), nil,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if qos.AllocationRetentionPriority != 1 {
+			t.Errorf("unexpected ARP: %d", qos.AllocationRetentionPriority)
+		}
+		if qos.TrafficClass != gtpinterwork.TrafficClassConversational || qos.SourceStatisticsDescriptor() != 1 {
+			t.Errorf("unexpected Traffic Class/SSD: %d/%d", qos.TrafficClass, qos.SourceStatisticsDescriptor())
+		}
+		if got := qos.MaximumBitRateULKbps(); got != 128 {
+			t.Errorf("unexpected MBR: %d", got)
+		}
+		if got := qos.GuaranteedBitRateDLKbps(); got != 64 {
+			t.Errorf("unexpected GBR: %d", got)
+		}
+		if got := gtpinterwork.QoSProfileToQCI(qos); got != 1 {
+			t.Errorf("unexpected QCI on reverse conversion: %d", got)
+		}
+	})
+
+	t.Run("NonGBR/WithAMBR", func(t *testing.T) {
+		qos, err := gtpinterwork.BearerQoSToQoSProfile(
+			v2ies.NewBearerQoS(1, 15, 0, 5, 0, 0, 0, 0),
+			v2ies.NewAggregateMaximumBitRate(50000, 100000),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if qos.AllocationRetentionPriority != 3 {
+			t.Errorf("unexpected ARP: %d", qos.AllocationRetentionPriority)
+		}
+		if !qos.SignallingIndication() || qos.TrafficHandlingPriority != 1 {
+			t.Error("QCI 5 should be mapped to Interactive THP1 with Signalling Indication")
+		}
+		if got := qos.MaximumBitRateULKbps(); got != 50000 {
+			t.Errorf("unexpected MBR for uplink: %d", got)
+		}
+		if got := qos.MaximumBitRateDLKbps(); got != 100000 {
+			t.Errorf("unexpected MBR for downlink: %d", got)
+		}
+
+		ambr := gtpinterwork.QoSProfileToAMBR(v1ies.NewQoSProfileFromPayload(qos).QoSProfilePayload())
+		if ambr.AggregateMaximumBitRateUp() != 50000 || ambr.AggregateMaximumBitRateDown() != 100000 {
+			t.Errorf("unexpected AMBR on reverse conversion: %d/%d", ambr.AggregateMaximumBitRateUp(), ambr.AggregateMaximumBitRateDown())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := gtpinterwork.BearerQoSToQoSProfile(v2ies.NewRecovery(1), nil); err != gtpinterwork.ErrInvalidIE {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
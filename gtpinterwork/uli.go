@@ -0,0 +1,283 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpinterwork
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/utils"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// ErrNoLocation indicates that the ULI has no location that can be converted.
+var ErrNoLocation = errors.New("no location to be converted")
+
+// Geographic Location Type definitions in GTPv1 ULI.
+const (
+	geoLocTypeCGI uint8 = iota
+	geoLocTypeSAI
+	geoLocTypeRAI
+)
+
+type raiKey struct {
+	mcc, mnc string
+	lac      uint16
+	rac      uint8
+}
+
+type taiKey struct {
+	mcc, mnc string
+	tac      uint16
+}
+
+type saiKey struct {
+	mcc, mnc string
+	lac, sac uint16
+}
+
+type ecgiKey struct {
+	mcc, mnc string
+	eci      uint32
+}
+
+// LocationMap is a set of the mappings between the locations in E-UTRAN(TAI and ECGI)
+// and the ones in UTRAN/GERAN(RAI and SAI), which is used to convert the ULI.
+//
+// There is no standardized mapping between them, so they should be added with AddRAITAI
+// and AddSAIECGI based on the network planning. The methods can be called on nil
+// *LocationMap, in which case only the locations that exist in both versions of ULI,
+// i.e., CGI, SAI and RAI, are converted.
+type LocationMap struct {
+	mu        sync.RWMutex
+	raiToTAI  map[raiKey]taiKey
+	taiToRAI  map[taiKey]raiKey
+	saiToECGI map[saiKey]ecgiKey
+	ecgiToSAI map[ecgiKey]saiKey
+}
+
+// NewLocationMap creates a new LocationMap.
+func NewLocationMap() *LocationMap {
+	return &LocationMap{
+		raiToTAI:  map[raiKey]taiKey{},
+		taiToRAI:  map[taiKey]raiKey{},
+		saiToECGI: map[saiKey]ecgiKey{},
+		ecgiToSAI: map[ecgiKey]saiKey{},
+	}
+}
+
+// AddRAITAI adds the mapping between the RAI and the TAI in the PLMN given.
+func (m *LocationMap) AddRAITAI(mcc, mnc string, lac uint16, rac uint8, tac uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rai := raiKey{mcc: mcc, mnc: mnc, lac: lac, rac: rac}
+	tai := taiKey{mcc: mcc, mnc: mnc, tac: tac}
+	m.raiToTAI[rai] = tai
+	m.taiToRAI[tai] = rai
+}
+
+// AddSAIECGI adds the mapping between the SAI and the ECGI in the PLMN given.
+func (m *LocationMap) AddSAIECGI(mcc, mnc string, lac, sac uint16, eci uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sai := saiKey{mcc: mcc, mnc: mnc, lac: lac, sac: sac}
+	ecgi := ecgiKey{mcc: mcc, mnc: mnc, eci: eci}
+	m.saiToECGI[sai] = ecgi
+	m.ecgiToSAI[ecgi] = sai
+}
+
+// ULIToV2 converts the GTPv1 ULI into the GTPv2 ULI.
+//
+// The CGI, SAI or RAI in the GTPv1 ULI is set as it is, and the TAI or ECGI mapped from
+// the RAI or SAI is added if the mapping exists.
+func (m *LocationMap) ULIToV2(uli *v1ies.IE) (*v2ies.IE, error) {
+	if uli == nil || uli.Type != v1ies.UserLocationInformation || len(uli.Payload) < 7 {
+		return nil, ErrInvalidIE
+	}
+
+	p := uli.Payload
+	mcc, mnc, err := utils.DecodePLMN(p[1:4])
+	if err != nil {
+		return nil, err
+	}
+	lac := binary.BigEndian.Uint16(p[4:6])
+
+	var (
+		hasCGI, hasSAI, hasRAI, hasTAI, hasECGI uint8
+		ci, sac, rac, tac                       uint16
+		eci                                     uint32
+	)
+	switch p[0] {
+	case geoLocTypeCGI, geoLocTypeSAI:
+		if len(p) < 8 {
+			return nil, ErrInvalidIE
+		}
+		v := binary.BigEndian.Uint16(p[6:8])
+		if p[0] == geoLocTypeCGI {
+			hasCGI, ci = 1, v
+			break
+		}
+		hasSAI, sac = 1, v
+		if ecgi, ok := m.lookupECGI(saiKey{mcc: mcc, mnc: mnc, lac: lac, sac: sac}); ok {
+			hasECGI, eci = 1, ecgi.eci
+		}
+	case geoLocTypeRAI:
+		// RAC is in the first octet and the second octet is all 1s in GTPv2.
+		hasRAI, rac = 1, uint16(p[6])<<8|0xff
+		if tai, ok := m.lookupTAI(raiKey{mcc: mcc, mnc: mnc, lac: lac, rac: p[6]}); ok {
+			hasTAI, tac = 1, tai.tac
+		}
+	default:
+		return nil, ErrNoLocation
+	}
+
+	return v2ies.NewUserLocationInformation(
+		hasCGI, hasSAI, hasRAI, hasTAI, hasECGI, 0, 0, 0,
+		mcc, mnc, lac, ci, sac, rac, tac, eci, 0, 0,
+	), nil
+}
+
+// ULIToV1 converts the GTPv2 ULI into the GTPv1 ULI.
+//
+// The SAI, CGI or RAI in the GTPv2 ULI is used in this order of preference if exists.
+// Otherwise, the SAI mapped from the ECGI or the RAI mapped from the TAI is used.
+// ErrNoLocation is returned if none of them is available.
+func (m *LocationMap) ULIToV1(uli *v2ies.IE) (*v1ies.IE, error) {
+	if uli == nil || uli.Type != v2ies.UserLocationInformation {
+		return nil, ErrInvalidIE
+	}
+	loc, err := decodeV2ULI(uli.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case loc.sai != nil:
+		return newV1ULI(geoLocTypeSAI, loc.sai.mcc, loc.sai.mnc, loc.sai.lac, loc.sai.sac)
+	case loc.cgi != nil:
+		return newV1ULI(geoLocTypeCGI, loc.cgi.mcc, loc.cgi.mnc, loc.cgi.lac, loc.cgi.sac)
+	case loc.rai != nil:
+		return newV1ULI(geoLocTypeRAI, loc.rai.mcc, loc.rai.mnc, loc.rai.lac, uint16(loc.rai.rac)<<8|0xff)
+	}
+
+	if loc.ecgi != nil {
+		if sai, ok := m.lookupSAI(*loc.ecgi); ok {
+			return newV1ULI(geoLocTypeSAI, sai.mcc, sai.mnc, sai.lac, sai.sac)
+		}
+	}
+	if loc.tai != nil {
+		if rai, ok := m.lookupRAI(*loc.tai); ok {
+			return newV1ULI(geoLocTypeRAI, rai.mcc, rai.mnc, rai.lac, uint16(rai.rac)<<8|0xff)
+		}
+	}
+	return nil, ErrNoLocation
+}
+
+func (m *LocationMap) lookupTAI(rai raiKey) (taiKey, bool) {
+	if m == nil {
+		return taiKey{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tai, ok := m.raiToTAI[rai]
+	return tai, ok
+}
+
+func (m *LocationMap) lookupRAI(tai taiKey) (raiKey, bool) {
+	if m == nil {
+		return raiKey{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rai, ok := m.taiToRAI[tai]
+	return rai, ok
+}
+
+func (m *LocationMap) lookupECGI(sai saiKey) (ecgiKey, bool) {
+	if m == nil {
+		return ecgiKey{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ecgi, ok := m.saiToECGI[sai]
+	return ecgi, ok
+}
+
+func (m *LocationMap) lookupSAI(ecgi ecgiKey) (saiKey, bool) {
+	if m == nil {
+		return saiKey{}, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sai, ok := m.ecgiToSAI[ecgi]
+	return sai, ok
+}
+
+// newV1ULI creates a GTPv1 ULI, with v being the CI, SAC or RAC(in the first octet,
+// followed by an octet of all 1s) depending on the typ.
+func newV1ULI(typ uint8, mcc, mnc string, lac, v uint16) (*v1ies.IE, error) {
+	plmn, err := utils.EncodePLMN(mcc, mnc)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 8)
+	b[0] = typ
+	copy(b[1:4], plmn)
+	binary.BigEndian.PutUint16(b[4:6], lac)
+	binary.BigEndian.PutUint16(b[6:8], v)
+	return v1ies.New(v1ies.UserLocationInformation, b), nil
+}
+
+// v2Location is the locations in GTPv2 ULI. CGI is kept in saiKey with CI as SAC.
+type v2Location struct {
+	cgi, sai *saiKey
+	rai      *raiKey
+	tai      *taiKey
+	ecgi     *ecgiKey
+}
+
+// decodeV2ULI decodes the payload of GTPv2 ULI. LAI and the following fields are ignored.
+func decodeV2ULI(b []byte) (*v2Location, error) {
+	if len(b) < 1 {
+		return nil, ErrInvalidIE
+	}
+
+	loc := &v2Location{}
+	flags := b[0]
+	offset := 1
+	for bit, l := range []int{7, 7, 7, 5, 7} {
+		if flags>>uint(bit)&0x01 == 0 {
+			continue
+		}
+		if len(b) < offset+l {
+			return nil, ErrInvalidIE
+		}
+		f := b[offset : offset+l]
+		offset += l
+
+		mcc, mnc, err := utils.DecodePLMN(f[0:3])
+		if err != nil {
+			return nil, err
+		}
+		switch bit {
+		case 0:
+			loc.cgi = &saiKey{mcc: mcc, mnc: mnc, lac: binary.BigEndian.Uint16(f[3:5]), sac: binary.BigEndian.Uint16(f[5:7])}
+		case 1:
+			loc.sai = &saiKey{mcc: mcc, mnc: mnc, lac: binary.BigEndian.Uint16(f[3:5]), sac: binary.BigEndian.Uint16(f[5:7])}
+		case 2:
+			loc.rai = &raiKey{mcc: mcc, mnc: mnc, lac: binary.BigEndian.Uint16(f[3:5]), rac: f[5]}
+		case 3:
+			loc.tai = &taiKey{mcc: mcc, mnc: mnc, tac: binary.BigEndian.Uint16(f[3:5])}
+		case 4:
+			loc.ecgi = &ecgiKey{mcc: mcc, mnc: mnc, eci: binary.BigEndian.Uint32(f[3:7]) & 0x0fffffff}
+		}
+	}
+	return loc, nil
+}
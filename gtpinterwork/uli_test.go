@@ -0,0 +1,60 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpinterwork_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/gtpinterwork"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestULIToV2(t *testing.T) {
+	m := gtpinterwork.NewLocationMap()
+	m.AddSAIECGI("123", "45", 0x1111, 0x2222, 0x1234567)
+
+	got, err := m.ULIToV2(v1ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := v2ies.NewUserLocationInformation(
+		0, 1, 0, 0, 1, 0, 0, 0, "123", "45",
+		0x1111, 0, 0x2222, 0, 0, 0x1234567, 0, 0,
+	)
+	if diff := cmp.Diff(got.Payload, want.Payload); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestULIToV1(t *testing.T) {
+	m := gtpinterwork.NewLocationMap()
+	m.AddRAITAI("123", "456", 0x1111, 0x22, 0x3333)
+
+	t.Run("MappedFromTAI", func(t *testing.T) {
+		got, err := m.ULIToV1(v2ies.NewUserLocationInformationLazy(
+			"123", "456", -1, -1, -1, -1, 0x3333, -1, -1, -1,
+		))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []byte{0x02, 0x21, 0x63, 0x54, 0x11, 0x11, 0x22, 0xff}
+		if diff := cmp.Diff(got.Payload, want); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("NotMapped", func(t *testing.T) {
+		var nilMap *gtpinterwork.LocationMap
+		_, err := nilMap.ULIToV1(v2ies.NewUserLocationInformationLazy(
+			"123", "456", -1, -1, -1, -1, 0x3333, -1, -1, -1,
+		))
+		if err != gtpinterwork.ErrNoLocation {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
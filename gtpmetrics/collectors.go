@@ -0,0 +1,105 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmetrics
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// NewConnCollector creates a Collector of the C-Plane metrics of the GTPv2-C Conn,
+// with the labels given added to all the metrics.
+func NewConnCollector(conn *v2.Conn, labels ...Label) Collector {
+	return CollectorFunc(func(w *Writer) {
+		stats := conn.Stats()
+		for _, typ := range slices.Sorted(maps.Keys(stats.Received)) {
+			n := stats.Received[typ]
+			w.Counter("gtpc_messages_received_total", "Number of GTPv2-C messages received, by message type.",
+				float64(n), with(labels, Label{"message_type", typ.String()})...)
+		}
+		for _, typ := range slices.Sorted(maps.Keys(stats.Sent)) {
+			n := stats.Sent[typ]
+			w.Counter("gtpc_messages_sent_total", "Number of GTPv2-C messages sent, by message type.",
+				float64(n), with(labels, Label{"message_type", typ.String()})...)
+		}
+		writeCauses(w, "gtpc_causes_received_total", "Number of GTPv2-C messages with Cause received, by peer, message type and cause.",
+			stats.ReceivedCauses, labels)
+		writeCauses(w, "gtpc_causes_sent_total", "Number of GTPv2-C messages with Cause sent, by peer, message type and cause.",
+			stats.SentCauses, labels)
+
+		w.Gauge("gtpc_transactions_outstanding", "Number of transactions waiting for the answer from the peer.",
+			float64(stats.Transactions.Outstanding), labels...)
+		w.Counter("gtpc_transactions_answered_total", "Number of transactions answered by the peer.",
+			float64(stats.Transactions.Answered), labels...)
+		w.Counter("gtpc_transactions_abandoned_total", "Number of transactions not answered within the timeout.",
+			float64(stats.Transactions.Abandoned), labels...)
+		w.Counter("gtpc_transactions_rejected_total", "Number of requests not sent as too many transactions are outstanding.",
+			float64(stats.Transactions.Rejected), labels...)
+
+		var sessions, active, bearers int
+		for _, sess := range conn.ListSessions() {
+			sessions++
+			if sess.IsActive() {
+				active++
+			}
+			bearers += len(sess.Bearers())
+		}
+		w.Gauge("gtpc_sessions", "Number of sessions on the Conn.", float64(sessions), labels...)
+		w.Gauge("gtpc_active_sessions", "Number of active sessions on the Conn.", float64(active), labels...)
+		w.Gauge("gtpc_bearers", "Number of bearers of the sessions on the Conn.", float64(bearers), labels...)
+	})
+}
+
+// NewUPlaneCollector creates a Collector of the U-Plane metrics of the UPlaneConn,
+// with the labels given added to all the metrics.
+func NewUPlaneCollector(conn *v1.UPlaneConn, labels ...Label) Collector {
+	return CollectorFunc(func(w *Writer) {
+		stats := conn.Stats()
+		writePeerStats(w, "", stats.Total, labels)
+		for _, addr := range slices.Sorted(maps.Keys(stats.Peers)) {
+			writePeerStats(w, "peer_", stats.Peers[addr], with(labels, Label{"peer", addr}))
+		}
+		for _, reason := range slices.Sorted(maps.Keys(stats.Dropped)) {
+			n := stats.Dropped[reason]
+			w.Counter("gtpu_dropped_packets_total", "Number of GTP-U packets dropped, by reason.",
+				float64(n), with(labels, Label{"reason", reason.String()})...)
+		}
+
+		w.Gauge("gtpu_relays", "Number of relay entries on the UPlaneConn.", float64(stats.Relays), labels...)
+		w.Gauge("gtpu_buffered_packets", "Number of T-PDUs currently buffered.", float64(stats.BufferedPackets), labels...)
+		w.Gauge("gtpu_buffered_teids", "Number of TEIDs with T-PDUs currently buffered.", float64(stats.BufferedTEIDs), labels...)
+	})
+}
+
+func writeCauses(w *Writer, name, help string, counts map[v2.CauseKey]uint64, labels []Label) {
+	keys := slices.SortedFunc(maps.Keys(counts), func(a, b v2.CauseKey) int {
+		return cmp.Or(
+			cmp.Compare(a.Peer, b.Peer),
+			cmp.Compare(a.MessageType, b.MessageType),
+			cmp.Compare(a.Cause, b.Cause),
+		)
+	})
+	for _, key := range keys {
+		l := with(labels, Label{"peer", key.Peer})
+		l = append(l, Label{"message_type", key.MessageType.String()}, Label{"cause", key.Cause.String()})
+		w.Counter(name, help, float64(counts[key]), l...)
+	}
+}
+
+func writePeerStats(w *Writer, prefix string, ps v1.PeerStats, labels []Label) {
+	w.Counter("gtpu_"+prefix+"received_packets_total", "Number of GTP-U packets received.", float64(ps.ReceivedPackets), labels...)
+	w.Counter("gtpu_"+prefix+"received_bytes_total", "Number of bytes of GTP-U packets received.", float64(ps.ReceivedBytes), labels...)
+	w.Counter("gtpu_"+prefix+"sent_packets_total", "Number of GTP-U packets sent.", float64(ps.SentPackets), labels...)
+	w.Counter("gtpu_"+prefix+"sent_bytes_total", "Number of bytes of GTP-U packets sent.", float64(ps.SentBytes), labels...)
+}
+
+// with returns the labels with the additional one, without modifying the labels given.
+func with(labels []Label, l Label) []Label {
+	return append(labels[:len(labels):len(labels)], l)
+}
@@ -0,0 +1,23 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpmetrics exposes the statistics of v2.Conn and v1.UPlaneConn as the
+// metrics in the Prometheus text exposition format, without depending on the
+// Prometheus client library.
+//
+// Registry is an http.Handler to be scraped, which collects the metrics from the
+// Collectors registered on each request. The Collectors of the Conns are labeled
+// to tell the interfaces from each other.
+//
+//	reg := gtpmetrics.NewRegistry()
+//	reg.Register(gtpmetrics.NewConnCollector(s11Conn, gtpmetrics.Label{Name: "interface", Value: "s11"}))
+//	reg.Register(gtpmetrics.NewUPlaneCollector(s1uConn, gtpmetrics.Label{Name: "interface", Value: "s1u"}))
+//	http.Handle("/metrics", reg)
+//
+// The C-Plane metrics are prefixed with "gtpc_" and the U-Plane ones with "gtpu_".
+// The U-Plane metrics include the throughput and the packet rates in total and by
+// peer as the counters, the size of the relay table, the occupancy of the buffer
+// and the number of the packets dropped by the reason. Any other statistics can
+// be exposed together by registering a CollectorFunc.
+package gtpmetrics
@@ -0,0 +1,143 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmetrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Content-Type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Label is a label of a metric.
+type Label struct {
+	Name, Value string
+}
+
+// Collector collects the metrics into the Writer on each scrape.
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc is a func that implements Collector.
+type CollectorFunc func(w *Writer)
+
+// Collect calls f(w).
+func (f CollectorFunc) Collect(w *Writer) {
+	f(w)
+}
+
+// Writer accumulates the metrics collected, grouping the samples of the same name
+// into a metric family, so that the Collectors can expose the same metrics with
+// the different labels.
+type Writer struct {
+	families map[string]*family
+	order    []string
+}
+
+type family struct {
+	help, typ string
+	samples   bytes.Buffer
+}
+
+// Counter writes a sample of the counter metric.
+func (w *Writer) Counter(name, help string, value float64, labels ...Label) {
+	w.sample(name, help, "counter", value, labels)
+}
+
+// Gauge writes a sample of the gauge metric.
+func (w *Writer) Gauge(name, help string, value float64, labels ...Label) {
+	w.sample(name, help, "gauge", value, labels)
+}
+
+func (w *Writer) sample(name, help, typ string, value float64, labels []Label) {
+	if w.families == nil {
+		w.families = map[string]*family{}
+	}
+	f, ok := w.families[name]
+	if !ok {
+		f = &family{help: help, typ: typ}
+		w.families[name] = f
+		w.order = append(w.order, name)
+	}
+
+	f.samples.WriteString(name)
+	if len(labels) > 0 {
+		f.samples.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				f.samples.WriteByte(',')
+			}
+			f.samples.WriteString(l.Name)
+			f.samples.WriteString(`="`)
+			f.samples.WriteString(labelEscaper.Replace(l.Value))
+			f.samples.WriteByte('"')
+		}
+		f.samples.WriteByte('}')
+	}
+	f.samples.WriteByte(' ')
+	f.samples.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	f.samples.WriteByte('\n')
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// WriteTo writes the metrics accumulated in the text exposition format.
+func (w *Writer) WriteTo(dst io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, name := range w.order {
+		f := w.families[name]
+		buf.WriteString("# HELP " + name + " " + helpEscaper.Replace(f.help) + "\n")
+		buf.WriteString("# TYPE " + name + " " + f.typ + "\n")
+		buf.Write(f.samples.Bytes())
+	}
+	return buf.WriteTo(dst)
+}
+
+// Registry is a set of the Collectors to be exposed together.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the Collector to the Registry.
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteTo collects the metrics from all the Collectors and writes them to w in the
+// text exposition format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	mw := &Writer{}
+	for _, c := range collectors {
+		c.Collect(mw)
+	}
+	return mw.WriteTo(w)
+}
+
+// ServeHTTP implements http.Handler, which serves the metrics to be scraped.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = r.WriteTo(w)
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmetrics_test
+
+import (
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtpmetrics"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestWriter(t *testing.T) {
+	w := &gtpmetrics.Writer{}
+	w.Counter("a_total", "A.", 1, gtpmetrics.Label{Name: "x", Value: "1"})
+	w.Gauge("b", "B\nb.", 2.5)
+	w.Counter("a_total", "A.", 3, gtpmetrics.Label{Name: "x", Value: `"2"`})
+
+	var sb strings.Builder
+	if _, err := w.WriteTo(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP a_total A.
+# TYPE a_total counter
+a_total{x="1"} 1
+a_total{x="\"2\""} 3
+# HELP b B\nb.
+# TYPE b gauge
+b 2.5
+`
+	if got := sb.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	uaddr, err := net.ResolveUDPAddr("udp", "127.0.0.35:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(uaddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	caddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cConn, err := v2.ListenAndServe(caddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cConn.Close()
+
+	reg := gtpmetrics.NewRegistry()
+	reg.Register(gtpmetrics.NewConnCollector(cConn, gtpmetrics.Label{Name: "interface", Value: "s11"}))
+	reg.Register(gtpmetrics.NewUPlaneCollector(uConn, gtpmetrics.Label{Name: "interface", Value: "s1u"}))
+
+	peer, err := net.ListenPacket("udp", "127.0.0.36:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	if _, err := peer.WriteTo([]byte{0xde, 0xad}, uaddr); err != nil {
+		t.Fatal(err)
+	}
+	if err := cConn.SendMessageTo(
+		messages.NewDeleteSessionResponse(0, 1, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil)),
+		peer.LocalAddr(),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	wants := []string{
+		`gtpc_sessions{interface="s11"} 0`,
+		`gtpu_received_packets_total{interface="s1u"} 1`,
+		`gtpu_received_bytes_total{interface="s1u"} 2`,
+		`gtpu_peer_received_packets_total{interface="s1u",peer="127.0.0.36:2152"} 1`,
+		`gtpu_dropped_packets_total{interface="s1u",reason="malformed"} 1`,
+		`gtpu_relays{interface="s1u"} 0`,
+		`gtpc_transactions_outstanding{interface="s11"} 0`,
+		`gtpc_causes_sent_total{interface="s11",peer="127.0.0.36:2152",message_type="DeleteSessionResponse",cause="ContextNotFound"} 1`,
+	}
+
+	var body string
+	for range 50 {
+		rec := httptest.NewRecorder()
+		reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		if ct := rec.Header().Get("Content-Type"); ct != gtpmetrics.ContentType {
+			t.Fatalf("got Content-Type %s", ct)
+		}
+		body = rec.Body.String()
+		if strings.Contains(body, wants[1]) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, want := range wants {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q not found in:\n%s", want, body)
+		}
+	}
+}
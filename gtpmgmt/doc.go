@@ -0,0 +1,21 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpmgmt provides an HTTP management API for the gateways running on
+// a GTPv2-C Conn, to inspect and operate the sessions from outside.
+//
+// The Server is an http.Handler that serves the following endpoints in JSON.
+//
+//	GET    /sessions          lists the sessions, with ?imsi=, ?offset= and ?limit=
+//	GET    /sessions/{imsi}   shows the details of the session
+//	DELETE /sessions/{imsi}   sends Delete Session Request for the session, with ?iftype=
+//	GET    /stats             shows the number of sessions and messages on the Conn
+//
+// The imsi parameter on listing is a prefix, so that the sessions can be filtered
+// by MCC and MNC. The sessions are listed in the order of IMSI.
+//
+// Deleting a session only sends Delete Session Request to the peer. The session
+// is removed by the handler for Delete Session Response registered on the Conn,
+// as it is for the Delete Session Request sent by the gateway itself.
+package gtpmgmt
@@ -0,0 +1,315 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Limits of the number of sessions in a page.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Server serves the management API over a Conn.
+type Server struct {
+	conn    *v2.Conn
+	mux     *http.ServeMux
+	started time.Time
+
+	// DeleteIFType is the InterfaceType of the peer's TEID to send Delete Session
+	// Request with, used when the iftype parameter is not given.
+	DeleteIFType uint8
+}
+
+// NewServer creates a new Server over conn.
+//
+// deleteIFType is the InterfaceType of the peer's TEID that the gateway uses to
+// send Delete Session Request, e.g., IFTypeS11S4SGWGTPC on MME.
+func NewServer(conn *v2.Conn, deleteIFType uint8) *Server {
+	s := &Server{
+		conn:         conn,
+		mux:          http.NewServeMux(),
+		started:      time.Now(),
+		DeleteIFType: deleteIFType,
+	}
+
+	s.mux.HandleFunc("GET /sessions", s.listSessions)
+	s.mux.HandleFunc("GET /sessions/{imsi}", s.showSession)
+	s.mux.HandleFunc("DELETE /sessions/{imsi}", s.deleteSession)
+	s.mux.HandleFunc("GET /stats", s.showStats)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SessionSummary is a session in the list.
+type SessionSummary struct {
+	IMSI     string `json:"imsi"`
+	MSISDN   string `json:"msisdn,omitempty"`
+	PeerAddr string `json:"peer_addr"`
+	Active   bool   `json:"active"`
+	Bearers  int    `json:"bearers"`
+}
+
+// SessionList is a page of the sessions.
+type SessionList struct {
+	Total    int               `json:"total"`
+	Offset   int               `json:"offset"`
+	Limit    int               `json:"limit"`
+	Sessions []*SessionSummary `json:"sessions"`
+}
+
+// BearerDetail is a bearer in SessionDetail.
+type BearerDetail struct {
+	Name         string `json:"name"`
+	EBI          uint8  `json:"ebi"`
+	APN          string `json:"apn,omitempty"`
+	SubscriberIP string `json:"subscriber_ip,omitempty"`
+	ChargingID   uint32 `json:"charging_id"`
+	IncomingTEID uint32 `json:"incoming_teid"`
+	OutgoingTEID uint32 `json:"outgoing_teid"`
+	QCI          uint8  `json:"qci"`
+	MBRUL        uint64 `json:"mbr_ul"`
+	MBRDL        uint64 `json:"mbr_dl"`
+}
+
+// SessionDetail is the details of a session.
+type SessionDetail struct {
+	IMSI     string               `json:"imsi"`
+	MSISDN   string               `json:"msisdn,omitempty"`
+	IMEI     string               `json:"imei,omitempty"`
+	PeerAddr string               `json:"peer_addr"`
+	Active   bool                 `json:"active"`
+	Sequence uint32               `json:"sequence"`
+	TEIDs    map[v2.IFType]uint32 `json:"teids"`
+	Bearers  []*BearerDetail      `json:"bearers"`
+}
+
+// MessageStats is the number of messages received and sent, by message name.
+type MessageStats struct {
+	Received map[messages.MessageType]uint64 `json:"received"`
+	Sent     map[messages.MessageType]uint64 `json:"sent"`
+}
+
+// Stats is the statistics of the Conn.
+type Stats struct {
+	LocalAddr      string       `json:"local_addr"`
+	RestartCounter uint8        `json:"restart_counter"`
+	Uptime         string       `json:"uptime"`
+	Sessions       int          `json:"sessions"`
+	ActiveSessions int          `json:"active_sessions"`
+	Bearers        int          `json:"bearers"`
+	Messages       MessageStats `json:"messages"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	offset, err := intParam(q.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid offset: %s", q.Get("offset")))
+		return
+	}
+	limit, err := intParam(q.Get("limit"), DefaultLimit)
+	if err != nil || limit <= 0 || limit > MaxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit: %s", q.Get("limit")))
+		return
+	}
+
+	prefix := q.Get("imsi")
+	var sessions []*v2.Session
+	for _, sess := range s.conn.ListSessions() {
+		if strings.HasPrefix(imsiOf(sess), prefix) {
+			sessions = append(sessions, sess)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return imsiOf(sessions[i]) < imsiOf(sessions[j])
+	})
+
+	list := &SessionList{
+		Total:    len(sessions),
+		Offset:   offset,
+		Limit:    limit,
+		Sessions: []*SessionSummary{},
+	}
+	for i := offset; i < len(sessions) && i < offset+limit; i++ {
+		sess := sessions[i]
+		sum := &SessionSummary{
+			IMSI:     imsiOf(sess),
+			PeerAddr: addrOf(sess),
+			Active:   sess.IsActive(),
+			Bearers:  len(sess.Bearers()),
+		}
+		if sess.Subscriber != nil {
+			sum.MSISDN = sess.MSISDN
+		}
+		list.Sessions = append(list.Sessions, sum)
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) showSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.conn.GetSessionByIMSI(r.PathValue("imsi"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	detail := &SessionDetail{
+		IMSI:     imsiOf(sess),
+		PeerAddr: addrOf(sess),
+		Active:   sess.IsActive(),
+		Sequence: sess.Sequence,
+		TEIDs:    map[v2.IFType]uint32{},
+		Bearers:  []*BearerDetail{},
+	}
+	if sess.Subscriber != nil {
+		detail.MSISDN = sess.MSISDN
+		detail.IMEI = sess.IMEI
+	}
+	for ifType, teid := range sess.TEIDs() {
+		detail.TEIDs[v2.IFType(ifType)] = teid
+	}
+	for name, br := range sess.Bearers() {
+		bd := &BearerDetail{
+			Name:         name,
+			EBI:          br.EBI,
+			APN:          br.APN,
+			SubscriberIP: br.SubscriberIP,
+			ChargingID:   br.ChargingID,
+			IncomingTEID: br.IncomingTEID(),
+			OutgoingTEID: br.OutgoingTEID(),
+		}
+		if br.QoSProfile != nil {
+			bd.QCI = br.QCI
+			bd.MBRUL = br.MBRUL
+			bd.MBRDL = br.MBRDL
+		}
+		detail.Bearers = append(detail.Bearers, bd)
+	}
+	sort.Slice(detail.Bearers, func(i, j int) bool {
+		return detail.Bearers[i].Name < detail.Bearers[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+func (s *Server) deleteSession(w http.ResponseWriter, r *http.Request) {
+	ifType := s.DeleteIFType
+	if v := r.URL.Query().Get("iftype"); v != "" {
+		t, err := parseIFType(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		ifType = t
+	}
+
+	sess, err := s.conn.GetSessionByIMSI(r.PathValue("imsi"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if !sess.IsActive() {
+		writeError(w, http.StatusConflict, fmt.Errorf("session is not active: %s", imsiOf(sess)))
+		return
+	}
+
+	if err := sess.Delete(s.conn, ifType); err != nil {
+		if err == v2.ErrTEIDNotFound {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("no TEID for %s: %w", v2.IFType(ifType), err))
+			return
+		}
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	// the session is removed when Delete Session Response comes.
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) showStats(w http.ResponseWriter, r *http.Request) {
+	cs := s.conn.Stats()
+	stats := &Stats{
+		LocalAddr:      s.conn.LocalAddr().String(),
+		RestartCounter: s.conn.RestartCounter,
+		Uptime:         time.Since(s.started).Round(time.Second).String(),
+		Messages: MessageStats{
+			Received: cs.Received,
+			Sent:     cs.Sent,
+		},
+	}
+	for _, sess := range s.conn.ListSessions() {
+		stats.Sessions++
+		if sess.IsActive() {
+			stats.ActiveSessions++
+		}
+		stats.Bearers += len(sess.Bearers())
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func imsiOf(sess *v2.Session) string {
+	if sess.Subscriber == nil {
+		return ""
+	}
+	return sess.IMSI
+}
+
+func addrOf(sess *v2.Session) string {
+	if sess.PeerAddr == nil {
+		return ""
+	}
+	return sess.PeerAddr.String()
+}
+
+func intParam(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+// parseIFType parses the InterfaceType given by name, e.g., "S11S4SGWGTPC", or by value.
+func parseIFType(v string) (uint8, error) {
+	if n, err := strconv.ParseUint(v, 10, 8); err == nil {
+		return uint8(n), nil
+	}
+	t, err := v2.IFTypeFromString(v)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(t), nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, &errorResponse{Error: err.Error()})
+}
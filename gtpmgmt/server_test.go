@@ -0,0 +1,173 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmgmt_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/gtpmgmt"
+	"github.com/wmnsk/go-gtp/gtptest"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func setup(t *testing.T) (*v2.Conn, net.PacketConn) {
+	t.Helper()
+
+	conn := gtptest.Listen(t, "127.0.0.1:0", 0)
+	peer := gtptest.ListenPeer(t)
+
+	for i, imsi := range []string{"001010000000003", "001010000000001", "440100000000001"} {
+		sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: imsi})
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, uint32(0x1000+i))
+		if imsi != "440100000000001" {
+			if err := sess.Activate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		conn.AddSession(sess)
+	}
+	return conn, peer
+}
+
+func request(t *testing.T, h http.Handler, method, target string, v interface{}) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if v != nil && rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code
+}
+
+func TestListSessions(t *testing.T) {
+	conn, _ := setup(t)
+	srv := gtpmgmt.NewServer(conn, v2.IFTypeS11S4SGWGTPC)
+
+	cases := []struct {
+		description, target string
+		code                int
+		imsis               []string
+		total               int
+	}{
+		{"all", "/sessions", http.StatusOK, []string{"001010000000001", "001010000000003", "440100000000001"}, 3},
+		{"paged", "/sessions?offset=1&limit=1", http.StatusOK, []string{"001010000000003"}, 3},
+		{"filtered", "/sessions?imsi=00101", http.StatusOK, []string{"001010000000001", "001010000000003"}, 2},
+		{"out-of-range", "/sessions?offset=10", http.StatusOK, []string{}, 3},
+		{"invalid-limit", "/sessions?limit=0", http.StatusBadRequest, nil, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var list gtpmgmt.SessionList
+			if code := request(t, srv, http.MethodGet, c.target, &list); code != c.code {
+				t.Fatalf("got status %d, want %d", code, c.code)
+			}
+			if c.code != http.StatusOK {
+				return
+			}
+
+			imsis := []string{}
+			for _, s := range list.Sessions {
+				imsis = append(imsis, s.IMSI)
+			}
+			if diff := cmp.Diff(c.imsis, imsis); diff != "" {
+				t.Error(diff)
+			}
+			if list.Total != c.total {
+				t.Errorf("got total %d, want %d", list.Total, c.total)
+			}
+		})
+	}
+}
+
+func TestShowSession(t *testing.T) {
+	conn, _ := setup(t)
+	srv := gtpmgmt.NewServer(conn, v2.IFTypeS11S4SGWGTPC)
+
+	var detail gtpmgmt.SessionDetail
+	if code := request(t, srv, http.MethodGet, "/sessions/001010000000001", &detail); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if diff := cmp.Diff(map[v2.IFType]uint32{v2.IFType(v2.IFTypeS11S4SGWGTPC): 0x1001}, detail.TEIDs); diff != "" {
+		t.Error(diff)
+	}
+	if !detail.Active || len(detail.Bearers) != 1 || detail.Bearers[0].Name != "default" {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+
+	if code := request(t, srv, http.MethodGet, "/sessions/999999999999999", nil); code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	conn, peer := setup(t)
+	srv := gtpmgmt.NewServer(conn, v2.IFTypeS11S4SGWGTPC)
+
+	cases := []struct {
+		description, target string
+		code                int
+	}{
+		{"unknown-iftype", "/sessions/001010000000001?iftype=Foo", http.StatusBadRequest},
+		{"no-teid", "/sessions/001010000000001?iftype=S5S8PGWGTPC", http.StatusBadRequest},
+		{"not-found", "/sessions/999999999999999", http.StatusNotFound},
+		{"inactive", "/sessions/440100000000001", http.StatusConflict},
+		{"ok", "/sessions/001010000000001", http.StatusAccepted},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if code := request(t, srv, http.MethodDelete, c.target, nil); code != c.code {
+				t.Errorf("got status %d, want %d", code, c.code)
+			}
+		})
+	}
+
+	buf := make([]byte, 1500)
+	if err := peer.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageType() != messages.MsgTypeDeleteSessionRequest || msg.TEID() != 0x1001 {
+		t.Errorf("unexpected message: %s, TEID: %#x", msg.MessageTypeName(), msg.TEID())
+	}
+}
+
+func TestStats(t *testing.T) {
+	conn, peer := setup(t)
+	srv := gtpmgmt.NewServer(conn, v2.IFTypeS11S4SGWGTPC)
+
+	if err := conn.EchoRequest(peer.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats gtpmgmt.Stats
+	if code := request(t, srv, http.MethodGet, "/stats", &stats); code != http.StatusOK {
+		t.Fatalf("got status %d", code)
+	}
+	if stats.Sessions != 3 || stats.ActiveSessions != 2 || stats.Bearers != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if diff := cmp.Diff(map[messages.MessageType]uint64{messages.MessageType(messages.MsgTypeEchoRequest): 1}, stats.Messages.Sent); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtppcap provides the helpers to write GTP messages into pcapng files,
+// so that they can be inspected with the tools like Wireshark, and to read GTP
+// messages from pcap or pcapng files, so that the tests can be driven by the
+// real captures.
+//
+// The messages are written with the IP and UDP headers synthesized from the
+// addresses given, as the packets handled by go-gtp don't have them.
+// On reading, the UDP payloads are extracted from the frames of the link types
+// commonly used, e.g., Ethernet, Linux cooked capture and raw IP.
+package gtppcap
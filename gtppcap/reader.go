@@ -0,0 +1,478 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtppcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"time"
+)
+
+// Link types supported by Reader, in addition to LinkTypeRaw.
+const (
+	LinkTypeNull      = 0
+	LinkTypeEthernet  = 1
+	LinkTypeLinuxSLL  = 113
+	LinkTypeIPv4      = 228
+	LinkTypeIPv6      = 229
+	LinkTypeLinuxSLL2 = 276
+)
+
+// Well-known ports of GTP, which are used by Reader to extract GTP messages by default.
+const (
+	PortGTPv0 = 3386
+	PortGTPC  = 2123
+	PortGTPU  = 2152
+)
+
+const (
+	blockTypeSPB = 0x00000003
+
+	magicMicroseconds        = 0xa1b2c3d4
+	magicNanoseconds         = 0xa1b23c4d
+	magicMicrosecondsSwapped = 0xd4c3b2a1
+	magicNanosecondsSwapped  = 0x4d3cb2a1
+
+	optIfTsresol = 9
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+	etherTypeQinQ = 0x88a8
+
+	protoUDP = 17
+)
+
+// Error definitions for Reader.
+var (
+	// ErrUnsupportedFormat indicates that the input is neither pcap nor pcapng.
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+
+	// ErrUnsupportedLinkType indicates that the link type of the capture is not supported.
+	ErrUnsupportedLinkType = errors.New("unsupported link type")
+
+	// ErrMalformedFile indicates that the structure of the file is broken.
+	ErrMalformedFile = errors.New("malformed file")
+)
+
+// Packet is a GTP message extracted from a capture file with the addresses in
+// the IP and UDP headers that carried it.
+type Packet struct {
+	Timestamp time.Time
+	Src, Dst  *net.UDPAddr
+
+	// Payload is the UDP payload, which is expected to be a GTP message.
+	Payload []byte
+}
+
+type iface struct {
+	linkType uint16
+	tsUnit   time.Duration
+	tsPow2   uint8
+}
+
+// Reader reads the GTP messages from pcap or pcapng format.
+//
+// Only the UDP packets sent from or to the ports given by SetPorts are returned,
+// which are the well-known ports of GTP by default. The packets that are not UDP
+// over IPv4/IPv6 or fragmented are skipped.
+type Reader struct {
+	r     io.Reader
+	order binary.ByteOrder
+	ports map[int]bool
+
+	// for pcapng.
+	isNG   bool
+	ifaces []*iface
+
+	// for pcap.
+	linkType uint16
+	tsUnit   time.Duration
+}
+
+// NewReader creates a new Reader, detecting the format from the header read from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: r}
+	rd.SetPorts(PortGTPv0, PortGTPC, PortGTPU)
+
+	b := make([]byte, 8)
+	if _, err := io.ReadFull(r, b[:4]); err != nil {
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint32(b[:4]) == blockTypeSHB {
+		rd.isNG = true
+		if _, err := io.ReadFull(r, b[4:8]); err != nil {
+			return nil, ErrMalformedFile
+		}
+		if err := rd.readSHB(b[4:8]); err != nil {
+			return nil, err
+		}
+		return rd, nil
+	}
+
+	switch binary.LittleEndian.Uint32(b[:4]) {
+	case magicMicroseconds:
+		rd.order, rd.tsUnit = binary.LittleEndian, time.Microsecond
+	case magicNanoseconds:
+		rd.order, rd.tsUnit = binary.LittleEndian, time.Nanosecond
+	case magicMicrosecondsSwapped:
+		rd.order, rd.tsUnit = binary.BigEndian, time.Microsecond
+	case magicNanosecondsSwapped:
+		rd.order, rd.tsUnit = binary.BigEndian, time.Nanosecond
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	// the rest of the global header.
+	hdr := make([]byte, 20)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	rd.linkType = uint16(rd.order.Uint32(hdr[16:20]))
+	if !isSupportedLinkType(rd.linkType) {
+		return nil, ErrUnsupportedLinkType
+	}
+	return rd, nil
+}
+
+// SetPorts sets the UDP ports to extract the packets. If no port is given, all
+// the UDP packets are returned.
+func (rd *Reader) SetPorts(ports ...int) {
+	rd.ports = map[int]bool{}
+	for _, p := range ports {
+		rd.ports[p] = true
+	}
+}
+
+// Next returns the next GTP message in the capture.
+// It returns io.EOF when there's no more packets.
+func (rd *Reader) Next() (*Packet, error) {
+	for {
+		var (
+			ts       time.Time
+			linkType uint16
+			data     []byte
+			err      error
+		)
+		if rd.isNG {
+			ts, linkType, data, err = rd.nextNG()
+		} else {
+			ts, linkType, data, err = rd.next()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+
+		pkt, ok := extractUDP(linkType, data)
+		if !ok {
+			continue
+		}
+		if len(rd.ports) != 0 && !rd.ports[pkt.Src.Port] && !rd.ports[pkt.Dst.Port] {
+			continue
+		}
+		pkt.Timestamp = ts
+		return pkt, nil
+	}
+}
+
+// ReadAll reads all the GTP messages in the capture.
+func (rd *Reader) ReadAll() ([]*Packet, error) {
+	var pkts []*Packet
+	for {
+		pkt, err := rd.Next()
+		if err == io.EOF {
+			return pkts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		pkts = append(pkts, pkt)
+	}
+}
+
+func (rd *Reader) next() (time.Time, uint16, []byte, error) {
+	hdr := make([]byte, 16)
+	if _, err := io.ReadFull(rd.r, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		return time.Time{}, 0, nil, err
+	}
+
+	capLen := rd.order.Uint32(hdr[8:12])
+	if capLen > math.MaxUint16*4 {
+		return time.Time{}, 0, nil, ErrMalformedFile
+	}
+	data := make([]byte, capLen)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		return time.Time{}, 0, nil, ErrMalformedFile
+	}
+
+	ts := time.Unix(int64(rd.order.Uint32(hdr[0:4])), 0).Add(
+		time.Duration(rd.order.Uint32(hdr[4:8])) * rd.tsUnit,
+	)
+	return ts, rd.linkType, data, nil
+}
+
+// nextNG reads the next block in pcapng. data is nil if the block is not the
+// one containing a packet.
+func (rd *Reader) nextNG() (time.Time, uint16, []byte, error) {
+	hdr := make([]byte, 8)
+	if _, err := io.ReadFull(rd.r, hdr); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		return time.Time{}, 0, nil, err
+	}
+
+	// the byte order is unknown until the SHB body is read.
+	if binary.LittleEndian.Uint32(hdr[0:4]) == blockTypeSHB {
+		return time.Time{}, 0, nil, rd.readSHB(hdr[4:8])
+	}
+
+	blockType := rd.order.Uint32(hdr[0:4])
+	total := rd.order.Uint32(hdr[4:8])
+	if total < 12 || total%4 != 0 || total > math.MaxUint16*4 {
+		return time.Time{}, 0, nil, ErrMalformedFile
+	}
+	body := make([]byte, total-8)
+	if _, err := io.ReadFull(rd.r, body); err != nil {
+		return time.Time{}, 0, nil, ErrMalformedFile
+	}
+	body = body[:len(body)-4] // trailing Block Total Length.
+
+	switch blockType {
+	case blockTypeIDB:
+		if len(body) < 8 {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		ifc := &iface{linkType: rd.order.Uint16(body[0:2]), tsUnit: time.Microsecond}
+		rd.parseIDBOptions(ifc, body[8:])
+		rd.ifaces = append(rd.ifaces, ifc)
+	case blockTypeEPB:
+		if len(body) < 20 {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		id := rd.order.Uint32(body[0:4])
+		if int(id) >= len(rd.ifaces) {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		ifc := rd.ifaces[id]
+		if !isSupportedLinkType(ifc.linkType) {
+			return time.Time{}, 0, nil, ErrUnsupportedLinkType
+		}
+		capLen := rd.order.Uint32(body[12:16])
+		if int(capLen) > len(body)-20 {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		ticks := uint64(rd.order.Uint32(body[4:8]))<<32 | uint64(rd.order.Uint32(body[8:12]))
+		return ifc.timestamp(ticks), ifc.linkType, body[20 : 20+capLen], nil
+	case blockTypeSPB:
+		if len(body) < 4 || len(rd.ifaces) == 0 {
+			return time.Time{}, 0, nil, ErrMalformedFile
+		}
+		ifc := rd.ifaces[0]
+		if !isSupportedLinkType(ifc.linkType) {
+			return time.Time{}, 0, nil, ErrUnsupportedLinkType
+		}
+		origLen := rd.order.Uint32(body[0:4])
+		data := body[4:]
+		if int(origLen) < len(data) {
+			data = data[:origLen]
+		}
+		// Simple Packet Block has no timestamp.
+		return time.Time{}, ifc.linkType, data, nil
+	}
+	return time.Time{}, 0, nil, nil
+}
+
+// readSHB reads the rest of Section Header Block after the Block Total Length,
+// which is given as lenField as it cannot be decoded before the Byte-Order Magic.
+func (rd *Reader) readSHB(lenField []byte) error {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(rd.r, magic); err != nil {
+		return ErrMalformedFile
+	}
+
+	switch binary.LittleEndian.Uint32(magic) {
+	case byteOrderMagic:
+		rd.order = binary.LittleEndian
+	case 0x4d3c2b1a:
+		rd.order = binary.BigEndian
+	default:
+		return ErrMalformedFile
+	}
+
+	total := rd.order.Uint32(lenField)
+	if total < 28 || total%4 != 0 || total > math.MaxUint16*4 {
+		return ErrMalformedFile
+	}
+	if _, err := io.ReadFull(rd.r, make([]byte, total-12)); err != nil {
+		return ErrMalformedFile
+	}
+
+	// interfaces are defined per section.
+	rd.ifaces = nil
+	return nil
+}
+
+func (rd *Reader) parseIDBOptions(ifc *iface, opts []byte) {
+	for len(opts) >= 4 {
+		code := rd.order.Uint16(opts[0:2])
+		l := int(rd.order.Uint16(opts[2:4]))
+		if code == optEndOfOpt || 4+l > len(opts) {
+			return
+		}
+		if code == optIfTsresol && l >= 1 {
+			v := opts[4]
+			if v&0x80 != 0 {
+				ifc.tsPow2 = v & 0x7f
+			} else {
+				ifc.tsUnit = time.Second
+				for i := uint8(0); i < v && ifc.tsUnit > 1; i++ {
+					ifc.tsUnit /= 10
+				}
+			}
+		}
+		opts = opts[4+((l+3)&^3):]
+	}
+}
+
+func (ifc *iface) timestamp(ticks uint64) time.Time {
+	if ifc.tsPow2 != 0 {
+		div := uint64(1) << ifc.tsPow2
+		sec := ticks / div
+		nsec := (ticks % div) * uint64(time.Second) / div
+		return time.Unix(int64(sec), int64(nsec))
+	}
+
+	perSec := uint64(time.Second / ifc.tsUnit)
+	return time.Unix(int64(ticks/perSec), int64(ticks%perSec)*int64(ifc.tsUnit))
+}
+
+func isSupportedLinkType(t uint16) bool {
+	switch t {
+	case LinkTypeNull, LinkTypeEthernet, LinkTypeRaw, LinkTypeLinuxSLL,
+		LinkTypeIPv4, LinkTypeIPv6, LinkTypeLinuxSLL2:
+		return true
+	}
+	return false
+}
+
+// ParseFrame returns the UDP payload with addresses in the frame of the link
+// type given. The Timestamp of the returned Packet is left zero, and the Payload
+// refers to the memory of frame.
+//
+// It returns false if the frame does not contain an unfragmented UDP datagram
+// over IPv4 or IPv6.
+func ParseFrame(linkType uint16, frame []byte) (*Packet, bool) {
+	return extractUDP(linkType, frame)
+}
+
+// extractUDP returns the UDP payload with addresses in the frame given.
+func extractUDP(linkType uint16, b []byte) (*Packet, bool) {
+	switch linkType {
+	case LinkTypeNull:
+		if len(b) < 4 {
+			return nil, false
+		}
+		b = b[4:]
+	case LinkTypeEthernet:
+		if len(b) < 14 {
+			return nil, false
+		}
+		etherType := binary.BigEndian.Uint16(b[12:14])
+		b = b[14:]
+		for etherType == etherTypeVLAN || etherType == etherTypeQinQ {
+			if len(b) < 4 {
+				return nil, false
+			}
+			etherType = binary.BigEndian.Uint16(b[2:4])
+			b = b[4:]
+		}
+		if etherType != etherTypeIPv4 && etherType != etherTypeIPv6 {
+			return nil, false
+		}
+	case LinkTypeLinuxSLL:
+		if len(b) < 16 {
+			return nil, false
+		}
+		b = b[16:]
+	case LinkTypeLinuxSLL2:
+		if len(b) < 20 {
+			return nil, false
+		}
+		b = b[20:]
+	}
+
+	if len(b) < 1 {
+		return nil, false
+	}
+	switch b[0] >> 4 {
+	case 4:
+		return extractUDPFromIPv4(b)
+	case 6:
+		return extractUDPFromIPv6(b)
+	}
+	return nil, false
+}
+
+func extractUDPFromIPv4(b []byte) (*Packet, bool) {
+	if len(b) < 20 {
+		return nil, false
+	}
+	ihl := int(b[0]&0x0f) * 4
+	totalLen := int(binary.BigEndian.Uint16(b[2:4]))
+	if ihl < 20 || totalLen < ihl || totalLen > len(b) || b[9] != protoUDP {
+		return nil, false
+	}
+	// fragmented packets are not reassembled.
+	if binary.BigEndian.Uint16(b[6:8])&0x3fff != 0 {
+		return nil, false
+	}
+
+	return newPacket(net.IP(b[12:16]), net.IP(b[16:20]), b[ihl:totalLen])
+}
+
+func extractUDPFromIPv6(b []byte) (*Packet, bool) {
+	if len(b) < 40 {
+		return nil, false
+	}
+	payloadLen := int(binary.BigEndian.Uint16(b[4:6]))
+	if 40+payloadLen > len(b) || b[6] != protoUDP {
+		return nil, false
+	}
+
+	return newPacket(net.IP(b[8:24]), net.IP(b[24:40]), b[40:40+payloadLen])
+}
+
+func newPacket(src, dst net.IP, udp []byte) (*Packet, bool) {
+	if len(udp) < 8 {
+		return nil, false
+	}
+	udpLen := int(binary.BigEndian.Uint16(udp[4:6]))
+	if udpLen < 8 || udpLen > len(udp) {
+		return nil, false
+	}
+
+	return &Packet{
+		Src:     &net.UDPAddr{IP: copyIP(src), Port: int(binary.BigEndian.Uint16(udp[0:2]))},
+		Dst:     &net.UDPAddr{IP: copyIP(dst), Port: int(binary.BigEndian.Uint16(udp[2:4]))},
+		Payload: udp[8:udpLen],
+	}, true
+}
+
+func copyIP(ip net.IP) net.IP {
+	c := make(net.IP, len(ip))
+	copy(c, ip)
+	return c
+}
@@ -0,0 +1,166 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtppcap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestReaderPcapng(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := gtppcap.NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		src4 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1).To4(), Port: 2123}
+		dst4 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2).To4(), Port: 2123}
+		src6 = &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 40000}
+		dst6 = &net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2152}
+		ts   = time.Unix(1500000000, 123456000)
+	)
+	echo := messages.NewEchoRequest(1, ies.NewRecovery(2))
+	if err := w.WriteMessage(ts, src4, dst4, gtppcap.DirectionOutbound, echo); err != nil {
+		t.Fatal(err)
+	}
+	// not a GTP port.
+	dns := &net.UDPAddr{IP: dst4.IP, Port: 53}
+	if err := w.WritePacket(ts, &net.UDPAddr{IP: src4.IP, Port: 40001}, dns, gtppcap.DirectionUnknown, []byte{0x00}); err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte{0x30, 0xff, 0x00, 0x01, 0x11, 0x22, 0x33, 0x44, 0xde}
+	if err := w.WritePacket(ts.Add(time.Second), src6, dst6, gtppcap.DirectionInbound, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := gtppcap.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkts, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkts) != 2 {
+		t.Fatalf("got unexpected number of packets: %d", len(pkts))
+	}
+
+	msg, err := messages.Decode(pkts[0].Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := msg.(*messages.EchoRequest); !ok {
+		t.Errorf("got unexpected message: %s", msg.MessageTypeName())
+	}
+	if diff := cmp.Diff(pkts[0].Src, src4); diff != "" {
+		t.Error(diff)
+	}
+	if !pkts[0].Timestamp.Equal(ts) {
+		t.Errorf("got unexpected timestamp: %s", pkts[0].Timestamp)
+	}
+
+	if diff := cmp.Diff(pkts[1].Payload, payload); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(pkts[1].Dst, dst6); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestReaderPcap(t *testing.T) {
+	udp := func(srcPort, dstPort uint16, payload []byte) []byte {
+		b := make([]byte, 8+len(payload))
+		binary.BigEndian.PutUint16(b[0:2], srcPort)
+		binary.BigEndian.PutUint16(b[2:4], dstPort)
+		binary.BigEndian.PutUint16(b[4:6], uint16(len(b)))
+		copy(b[8:], payload)
+		return b
+	}
+	ipv4 := func(flagsFrag uint16, l4 []byte) []byte {
+		b := make([]byte, 20+len(l4))
+		b[0] = 0x45
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+		binary.BigEndian.PutUint16(b[6:8], flagsFrag)
+		b[8] = 64
+		b[9] = 17
+		copy(b[12:16], []byte{10, 0, 0, 1})
+		copy(b[16:20], []byte{10, 0, 0, 2})
+		copy(b[20:], l4)
+		return b
+	}
+	// Ethernet with a VLAN tag.
+	ether := func(ip []byte) []byte {
+		b := make([]byte, 18, 18+len(ip))
+		binary.BigEndian.PutUint16(b[12:14], 0x8100)
+		binary.BigEndian.PutUint16(b[14:16], 100)
+		binary.BigEndian.PutUint16(b[16:18], 0x0800)
+		return append(b, ip...)
+	}
+
+	payload := []byte{0x1e, 0x01, 0x00, 0x00}
+	frames := [][]byte{
+		ether(ipv4(0x4000, udp(3386, 3386, payload))),
+		// fragmented.
+		ether(ipv4(0x2000, udp(3386, 3386, payload))),
+	}
+
+	buf := &bytes.Buffer{}
+	hdr := make([]byte, 24)
+	binary.BigEndian.PutUint32(hdr[0:4], 0xa1b2c3d4)
+	binary.BigEndian.PutUint16(hdr[4:6], 2)
+	binary.BigEndian.PutUint16(hdr[6:8], 4)
+	binary.BigEndian.PutUint32(hdr[16:20], 65535)
+	binary.BigEndian.PutUint32(hdr[20:24], gtppcap.LinkTypeEthernet)
+	buf.Write(hdr)
+	for i, f := range frames {
+		rec := make([]byte, 16)
+		binary.BigEndian.PutUint32(rec[0:4], uint32(100+i))
+		binary.BigEndian.PutUint32(rec[4:8], 500)
+		binary.BigEndian.PutUint32(rec[8:12], uint32(len(f)))
+		binary.BigEndian.PutUint32(rec[12:16], uint32(len(f)))
+		buf.Write(rec)
+		buf.Write(f)
+	}
+
+	r, err := gtppcap.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkt, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pkt.Payload, payload); diff != "" {
+		t.Error(diff)
+	}
+	if got := pkt.Src.String(); got != "10.0.0.1:3386" {
+		t.Errorf("got unexpected source: %s", got)
+	}
+	if want := time.Unix(100, 500000); !pkt.Timestamp.Equal(want) {
+		t.Errorf("got unexpected timestamp: %s", pkt.Timestamp)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("got unexpected error: %v", err)
+	}
+}
+
+func TestReaderUnsupported(t *testing.T) {
+	if _, err := gtppcap.NewReader(bytes.NewReader([]byte{0xde, 0xad, 0xbe, 0xef})); err != gtppcap.ErrUnsupportedFormat {
+		t.Errorf("got unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtppcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+)
+
+// Block types and other constants in pcapng.
+const (
+	blockTypeSHB = 0x0a0d0d0a
+	blockTypeIDB = 0x00000001
+	blockTypeEPB = 0x00000006
+
+	byteOrderMagic = 0x1a2b3c4d
+
+	// LinkTypeRaw is a link type for the packets beginning with IPv4 or IPv6 header.
+	LinkTypeRaw = 101
+
+	optEndOfOpt = 0
+	optEPBFlags = 2
+)
+
+// Direction definitions, used as the direction in the flags of Enhanced Packet Block.
+const (
+	DirectionUnknown uint8 = iota
+	DirectionInbound
+	DirectionOutbound
+)
+
+// ErrInvalidAddress indicates that the address given is not a valid UDP address.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Writer writes the GTP messages into pcapng format.
+// It is safe to use a Writer from multiple goroutines.
+type Writer struct {
+	mu    sync.Mutex
+	w     io.Writer
+	ipID  uint16
+	order binary.ByteOrder
+}
+
+// NewWriter creates a new Writer and writes Section Header Block and Interface
+// Description Block to w.
+func NewWriter(w io.Writer) (*Writer, error) {
+	wr := &Writer{w: w, order: binary.LittleEndian}
+
+	// Section Header Block.
+	shb := make([]byte, 28)
+	wr.order.PutUint32(shb[0:4], blockTypeSHB)
+	wr.order.PutUint32(shb[4:8], 28)
+	wr.order.PutUint32(shb[8:12], byteOrderMagic)
+	wr.order.PutUint16(shb[12:14], 1)
+	wr.order.PutUint16(shb[14:16], 0)
+	wr.order.PutUint64(shb[16:24], 0xffffffffffffffff) // section length is unknown.
+	wr.order.PutUint32(shb[24:28], 28)
+
+	// Interface Description Block, with the default timestamp resolution(microseconds).
+	idb := make([]byte, 20)
+	wr.order.PutUint32(idb[0:4], blockTypeIDB)
+	wr.order.PutUint32(idb[4:8], 20)
+	wr.order.PutUint16(idb[8:10], LinkTypeRaw)
+	wr.order.PutUint32(idb[12:16], 0) // no limit on snap length.
+	wr.order.PutUint32(idb[16:20], 20)
+
+	if _, err := w.Write(append(shb, idb...)); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+// WritePacket writes a GTP message(UDP payload) sent from src to dst with IP and UDP headers.
+// The direction should be one of DirectionUnknown, DirectionInbound or DirectionOutbound.
+func (wr *Writer) WritePacket(ts time.Time, src, dst net.Addr, direction uint8, payload []byte) error {
+	srcAddr, ok := src.(*net.UDPAddr)
+	if !ok {
+		return ErrInvalidAddress
+	}
+	dstAddr, ok := dst.(*net.UDPAddr)
+	if !ok {
+		return ErrInvalidAddress
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	wr.ipID++
+	pkt, err := NewUDPPacket(srcAddr, dstAddr, wr.ipID, payload)
+	if err != nil {
+		return err
+	}
+
+	padded := (len(pkt) + 3) &^ 3
+	optLen := 0
+	if direction != DirectionUnknown {
+		optLen = 4 + 4 + 4 // epb_flags + opt_endofopt
+	}
+	total := 28 + padded + optLen + 4
+
+	b := make([]byte, total)
+	us := uint64(ts.UnixNano() / 1000)
+	wr.order.PutUint32(b[0:4], blockTypeEPB)
+	wr.order.PutUint32(b[4:8], uint32(total))
+	wr.order.PutUint32(b[8:12], 0) // interface ID
+	wr.order.PutUint32(b[12:16], uint32(us>>32))
+	wr.order.PutUint32(b[16:20], uint32(us))
+	wr.order.PutUint32(b[20:24], uint32(len(pkt)))
+	wr.order.PutUint32(b[24:28], uint32(len(pkt)))
+	copy(b[28:], pkt)
+
+	offset := 28 + padded
+	if optLen > 0 {
+		wr.order.PutUint16(b[offset:offset+2], optEPBFlags)
+		wr.order.PutUint16(b[offset+2:offset+4], 4)
+		wr.order.PutUint32(b[offset+4:offset+8], uint32(direction))
+		wr.order.PutUint16(b[offset+8:offset+10], optEndOfOpt)
+		offset += optLen
+	}
+	wr.order.PutUint32(b[offset:offset+4], uint32(total))
+
+	_, err = wr.w.Write(b)
+	return err
+}
+
+// Serializer is the interface implemented by the messages in go-gtp, e.g.,
+// v1/messages.Message and v2/messages.Message.
+type Serializer interface {
+	Serialize() ([]byte, error)
+}
+
+// WriteMessage serializes the message given and writes it in the same way as WritePacket.
+func (wr *Writer) WriteMessage(ts time.Time, src, dst net.Addr, direction uint8, msg Serializer) error {
+	b, err := msg.Serialize()
+	if err != nil {
+		return err
+	}
+	return wr.WritePacket(ts, src, dst, direction, b)
+}
+
+// NewUDPPacket creates an IPv4 or IPv6 packet that contains a UDP datagram
+// with the payload given. Both src and dst must be in the same address family.
+func NewUDPPacket(src, dst *net.UDPAddr, id uint16, payload []byte) ([]byte, error) {
+	udpLen := 8 + len(payload)
+
+	if src4, dst4 := src.IP.To4(), dst.IP.To4(); src4 != nil && dst4 != nil {
+		b := make([]byte, 20+udpLen)
+		b[0] = 0x45
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+		binary.BigEndian.PutUint16(b[4:6], id)
+		b[8] = 64
+		b[9] = 17
+		copy(b[12:16], src4)
+		copy(b[16:20], dst4)
+		binary.BigEndian.PutUint16(b[10:12], checksum.Sum(b[:20], 0))
+
+		udp := b[20:]
+		putUDPHeader(udp, src.Port, dst.Port)
+		copy(udp[8:], payload)
+		binary.BigEndian.PutUint16(udp[6:8], nonZero(checksum.Sum(udp, checksum.PseudoHeader(b[12:20], 17, udpLen))))
+		return b, nil
+	}
+
+	src16, dst16 := src.IP.To16(), dst.IP.To16()
+	if src16 == nil || dst16 == nil || src.IP.To4() != nil || dst.IP.To4() != nil {
+		return nil, ErrInvalidAddress
+	}
+
+	b := make([]byte, 40+udpLen)
+	b[0] = 0x60
+	binary.BigEndian.PutUint16(b[4:6], uint16(udpLen))
+	b[6] = 17
+	b[7] = 64
+	copy(b[8:24], src16)
+	copy(b[24:40], dst16)
+
+	udp := b[40:]
+	putUDPHeader(udp, src.Port, dst.Port)
+	copy(udp[8:], payload)
+	binary.BigEndian.PutUint16(udp[6:8], nonZero(checksum.Sum(udp, checksum.PseudoHeader(b[8:40], 17, udpLen))))
+	return b, nil
+}
+
+func putUDPHeader(b []byte, srcPort, dstPort int) {
+	binary.BigEndian.PutUint16(b[0:2], uint16(srcPort))
+	binary.BigEndian.PutUint16(b[2:4], uint16(dstPort))
+	binary.BigEndian.PutUint16(b[4:6], uint16(len(b)))
+}
+
+// nonZero returns 0xffff for the computed checksum 0, as 0 means no checksum in UDP.
+func nonZero(cs uint16) uint16 {
+	if cs == 0 {
+		return 0xffff
+	}
+	return cs
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtppcap_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+)
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := gtppcap.NewWriter(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2152}
+	dst := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 2152}
+	payload := []byte{0x30, 0xff, 0x00, 0x01, 0x11, 0x22, 0x33, 0x44, 0xde}
+	if err := w.WritePacket(time.Unix(1, 0), src, dst, gtppcap.DirectionOutbound, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	b := buf.Bytes()
+	// SHB(28) + IDB(20) + EPB(28 + 40 + 12 + 4).
+	if len(b) != 132 {
+		t.Fatalf("got unexpected length: %d", len(b))
+	}
+
+	epb := b[48:]
+	if got := binary.LittleEndian.Uint32(epb[0:4]); got != 6 {
+		t.Errorf("got unexpected block type: %d", got)
+	}
+	if got := binary.LittleEndian.Uint32(epb[20:24]); got != 37 {
+		t.Errorf("got unexpected captured length: %d", got)
+	}
+
+	pkt := epb[28 : 28+37]
+	if diff := cmp.Diff(pkt[28:], payload); diff != "" {
+		t.Error(diff)
+	}
+	if got := binary.LittleEndian.Uint32(epb[len(epb)-4:]); got != 84 {
+		t.Errorf("got unexpected trailing length: %d", got)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/wmnsk/go-gtp"
+	v0ies "github.com/wmnsk/go-gtp/v0/ies"
+	v0msg "github.com/wmnsk/go-gtp/v0/messages"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DiffMessages returns the differences between the messages a and b, or an empty
+// string if they are the same on the wire.
+//
+// The messages are compared after being serialized, the header field by field and
+// the IEs by the type and the instance(in GTPv2), one difference per line like:
+//
+//	header.TEID: 0x11223344 != 0x11223345
+//	Cause(2)/0: 16 != 64
+//	BearerContext(93)/0 > FullyQualifiedTEID(87)/2: missing in b
+//
+// The grouped IEs are compared recursively, and the value of the IEs are shown in
+// human readable format where possible. The payload of the messages that don't
+// consist of IEs, e.g., T-PDU, is compared as a whole.
+func DiffMessages(a, b gtp.Message) string {
+	ta, err := dissect(a)
+	if err != nil {
+		return fmt.Sprintf("a: %v\n", err)
+	}
+	tb, err := dissect(b)
+	if err != nil {
+		return fmt.Sprintf("b: %v\n", err)
+	}
+
+	var d differ
+	d.diffTree(ta, tb)
+	return d.String()
+}
+
+// tree is a message dissected to be compared.
+type tree struct {
+	version int
+	header  []field
+	ies     []*node
+}
+
+type field struct {
+	name, value string
+}
+
+// node is an IE, or the payload that is not decoded as IEs.
+type node struct {
+	key      string
+	value    string
+	payload  []byte
+	children []*node
+}
+
+func dissect(m gtp.Message) (*tree, error) {
+	b, err := gtp.Serialize(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize: %w", err)
+	}
+
+	t := &tree{version: m.Version()}
+	var payload []byte
+	switch t.version {
+	case 0:
+		h, err := v0msg.DecodeHeader(b)
+		if err != nil {
+			return nil, err
+		}
+		t.header, payload = headerFields(h), h.Payload
+		t.ies = v0Nodes(payload)
+	case 1:
+		h, err := v1msg.DecodeHeader(b)
+		if err != nil {
+			return nil, err
+		}
+		t.header, payload = headerFields(h), h.Payload
+		if h.Type != v1msg.MsgTypeTPDU {
+			t.ies = v1Nodes(payload)
+		}
+	case 2:
+		h, err := v2msg.DecodeHeader(b)
+		if err != nil {
+			return nil, err
+		}
+		t.header, payload = headerFields(h), h.Payload
+		t.ies = v2Nodes(payload)
+	default:
+		return nil, gtp.ErrInvalidVersion
+	}
+
+	if t.ies == nil && len(payload) > 0 {
+		t.ies = []*node{{key: "payload", value: fmt.Sprintf("%x", payload), payload: payload}}
+	}
+	return t, nil
+}
+
+// headerFields returns the fields of the header other than Payload.
+func headerFields(h interface{}) []field {
+	v := reflect.ValueOf(h).Elem()
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if name == "Payload" {
+			continue
+		}
+
+		f := v.Field(i)
+		var value string
+		switch f.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = fmt.Sprintf("%#x", f.Uint())
+		case reflect.Slice:
+			elems := make([]string, f.Len())
+			for j := range elems {
+				elems[j] = fmt.Sprintf("%+v", reflect.Indirect(f.Index(j)).Interface())
+			}
+			value = "[" + strings.Join(elems, " ") + "]"
+		default:
+			value = fmt.Sprintf("%v", f.Interface())
+		}
+		fields = append(fields, field{name: name, value: value})
+	}
+	return fields
+}
+
+func v0Nodes(b []byte) []*node {
+	ies, err := v0ies.DecodeMultiIEs(b)
+	if err != nil {
+		return nil
+	}
+
+	nodes := make([]*node, 0, len(ies))
+	for _, ie := range ies {
+		nodes = append(nodes, &node{
+			key:     fmt.Sprintf("IE(%d)", ie.Type),
+			value:   fmt.Sprintf("%x", ie.Payload),
+			payload: ie.Payload,
+		})
+	}
+	return uniqueKeys(nodes)
+}
+
+func v1Nodes(b []byte) []*node {
+	ies, err := v1ies.DecodeMultiIEs(b)
+	if err != nil {
+		return nil
+	}
+
+	nodes := make([]*node, 0, len(ies))
+	for _, ie := range ies {
+		nodes = append(nodes, &node{
+			key:     fmt.Sprintf("%s(%d)", ie.Name(), ie.Type),
+			value:   ie.ValueString(),
+			payload: ie.Payload,
+		})
+	}
+	return uniqueKeys(nodes)
+}
+
+func v2Nodes(b []byte) []*node {
+	ies, err := v2ies.DecodeMultiIEs(b)
+	if err != nil {
+		return nil
+	}
+	return v2IENodes(ies)
+}
+
+func v2IENodes(ies []*v2ies.IE) []*node {
+	nodes := make([]*node, 0, len(ies))
+	for _, ie := range ies {
+		n := &node{
+			key:     fmt.Sprintf("%s(%d)/%d", ie.Name(), ie.Type, ie.Instance()),
+			payload: ie.Payload,
+		}
+		if children, err := ie.Children(); err == nil && ie.IsGrouped() {
+			n.children = v2IENodes(children)
+		} else {
+			n.value = ie.ValueString()
+		}
+		nodes = append(nodes, n)
+	}
+	return uniqueKeys(nodes)
+}
+
+// uniqueKeys appends the index to the keys of the nodes that appear more than once.
+func uniqueKeys(nodes []*node) []*node {
+	counts := map[string]int{}
+	for _, n := range nodes {
+		counts[n.key]++
+	}
+	seen := map[string]int{}
+	for _, n := range nodes {
+		if counts[n.key] > 1 {
+			key := n.key
+			n.key = fmt.Sprintf("%s[%d]", key, seen[key])
+			seen[key]++
+		}
+	}
+	return nodes
+}
+
+type differ struct {
+	lines []string
+}
+
+func (d *differ) add(format string, args ...interface{}) {
+	d.lines = append(d.lines, fmt.Sprintf(format, args...))
+}
+
+func (d *differ) String() string {
+	if len(d.lines) == 0 {
+		return ""
+	}
+	return strings.Join(d.lines, "\n") + "\n"
+}
+
+func (d *differ) diffTree(a, b *tree) {
+	if a.version != b.version {
+		d.add("version: %d != %d", a.version, b.version)
+		return
+	}
+
+	for i := range a.header {
+		if fa, fb := a.header[i], b.header[i]; fa.value != fb.value {
+			d.add("header.%s: %s != %s", fa.name, fa.value, fb.value)
+		}
+	}
+	d.diffNodes("", a.ies, b.ies)
+}
+
+func (d *differ) diffNodes(path string, a, b []*node) {
+	nodesB := map[string]*node{}
+	for _, n := range b {
+		nodesB[n.key] = n
+	}
+	nodesA := map[string]*node{}
+	for _, n := range a {
+		nodesA[n.key] = n
+	}
+
+	var commonA []string
+	for _, na := range a {
+		nb, ok := nodesB[na.key]
+		if !ok {
+			d.add("%s%s: missing in b", path, na.key)
+			continue
+		}
+		commonA = append(commonA, na.key)
+
+		switch {
+		case na.children != nil && nb.children != nil:
+			d.diffNodes(path+na.key+" > ", na.children, nb.children)
+		case !bytes.Equal(na.payload, nb.payload):
+			d.add("%s%s: %s != %s", path, na.key, valueOf(na), valueOf(nb))
+		}
+	}
+
+	var commonB []string
+	for _, nb := range b {
+		if _, ok := nodesA[nb.key]; !ok {
+			d.add("%s%s: missing in a", path, nb.key)
+			continue
+		}
+		commonB = append(commonB, nb.key)
+	}
+
+	if strings.Join(commonA, ",") != strings.Join(commonB, ",") {
+		d.add("%sorder: %s != %s", path, strings.Join(commonA, ", "), strings.Join(commonB, ", "))
+	}
+}
+
+func valueOf(n *node) string {
+	if n.value != "" {
+		return n.value
+	}
+	return fmt.Sprintf("%x", n.payload)
+}
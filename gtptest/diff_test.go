@@ -0,0 +1,65 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp"
+	"github.com/wmnsk/go-gtp/gtptest"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func newBearerContext(ebi uint8) *ies.IE {
+	return ies.NewBearerContext(
+		ies.NewEPSBearerID(ebi),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111, "127.0.0.1", "").WithInstance(0),
+	)
+}
+
+func TestDiffMessages(t *testing.T) {
+	cases := []struct {
+		description string
+		a, b        gtp.Message
+		want        string
+	}{
+		{
+			"same",
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			"",
+		}, {
+			"header",
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			gtptest.NewCreateSessionRequest(0x11223345, 1, newBearerContext(5)),
+			"header.TEID: 0x11223344 != 0x11223345\n",
+		}, {
+			"grouped",
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(6)),
+			"BearerContext(93)/0 > EPSBearerID(73)/0: 5 != 6\n",
+		}, {
+			"missing",
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5), ies.NewAccessPointName("some.apn.example")),
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			"header.Length: 0xa5 != 0x90\nAccessPointName(71)/0: missing in b\n",
+		}, {
+			"version",
+			gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5)),
+			v1msg.NewEchoRequest(0),
+			"version: 2 != 1\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if got := gtptest.DiffMessages(c.a, c.b); got != c.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtptest provides an in-process EPC network for the tests that cover
+// the whole GTPv2-C signaling chain rather than the individual messages.
+//
+// The Network runs MME, S-GW and P-GW in one process, each of them on its own
+// v2.Conn over the loopback address with an ephemeral port, so that the tests
+// in the different packages can run in parallel.
+//
+//	MME --(S11)-- S-GW --(S5/S8-C)-- P-GW
+//
+// The MME is the simulator in mmesim, which drives the procedures with Attach,
+// TriggerHandover, ReleaseAccessBearers, ServiceRequest and Detach, and waits
+// for the whole chain to complete before returning. The sessions established on
+// each role can be inspected through the Conns exported.
+//
+// Another S-GW can be added with AddSGW, to relocate the sessions to with
+// RelocateSGW of the MME.
+//
+// The roles only handle C-plane with the minimal set of IEs required by the
+// procedures, and no U-plane is set up.
+//
+// For the tests of the individual messages, DiffMessages tells the differences
+// between two messages IE by IE, and GoldenEncode and GoldenDecode check the
+// messages against the golden files of the bytes on the wire.
+//
+//	gtptest.GoldenEncode(t, "testdata/create-session-request.golden", msg)
+//
+// The golden files are (re)written by running the tests with GTPTEST_UPDATE_GOLDEN=1.
+//
+// The helpers shared by the tests are also here: StartNetwork starts a Network
+// closed at the end of the test, Listen and ListenPeer open a v2.Conn and a bare
+// UDP socket to talk to it, ReadMessage reads what the peer received, and
+// NewCreateSessionRequest builds a Create Session Request with the usual IEs.
+package gtptest
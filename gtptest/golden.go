@@ -0,0 +1,132 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wmnsk/go-gtp"
+)
+
+// UpdateGolden makes GoldenEncode write the golden files instead of checking them.
+// It is true if the environment variable GTPTEST_UPDATE_GOLDEN is set to non-empty,
+// e.g., GTPTEST_UPDATE_GOLDEN=1 go test ./...
+var UpdateGolden = os.Getenv("GTPTEST_UPDATE_GOLDEN") != ""
+
+// WriteGolden writes msg serialized to the golden file at path, in hex dump with
+// the name of the message as a comment, creating the directory if not exists.
+func WriteGolden(path string, msg gtp.Message) error {
+	b, err := gtp.Serialize(msg)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# GTPv%d %s\n", msg.Version(), msg.MessageTypeName())
+	for len(b) > 0 {
+		n := min(len(b), 16)
+		buf.WriteString(hex.EncodeToString(b[:n]))
+		buf.WriteByte('\n')
+		b = b[n:]
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// ReadGolden reads the bytes in the golden file at path, which is the hex dump with
+// the comment lines starting with "#". The spaces in the hex dump are ignored, so that
+// the bytes copied from Wireshark or the specifications can be pasted as they are.
+func ReadGolden(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var b []byte
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.Join(strings.Fields(sc.Text()), "")
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		h, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		b = append(b, h...)
+	}
+	return b, sc.Err()
+}
+
+// GoldenEncode checks that msg is serialized into the bytes in the golden file at path,
+// and reports the differences found by DiffMessages if not. The golden file is written
+// instead if UpdateGolden is true.
+func GoldenEncode(t testing.TB, path string, msg gtp.Message) {
+	t.Helper()
+
+	if UpdateGolden {
+		if err := WriteGolden(path, msg); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ReadGolden(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := gtp.Serialize(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, want) {
+		return
+	}
+
+	golden, err := gtp.Decode(want)
+	if err != nil {
+		t.Fatalf("serialized into %x, golden %x is not decodable: %v", got, want, err)
+	}
+	if diff := DiffMessages(msg, golden); diff != "" {
+		t.Errorf("serialized message differs from %s (a: got, b: golden):\n%s", path, diff)
+		return
+	}
+	t.Errorf("serialized into %x, want %x", got, want)
+}
+
+// GoldenDecode checks that the bytes in the golden file at path are decoded into the
+// message same as want, and reports the differences found by DiffMessages if not.
+// The decoded message is returned to check the fields further.
+func GoldenDecode(t testing.TB, path string, want gtp.Message) gtp.Message {
+	t.Helper()
+
+	b, err := ReadGolden(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := gtp.Decode(b)
+	if err != nil {
+		t.Fatalf("failed to decode %s: %v", path, err)
+	}
+
+	if got.MessageType() != want.MessageType() {
+		t.Fatalf("decoded %s as %s, want %s", path, got.MessageTypeName(), want.MessageTypeName())
+	}
+	if diff := DiffMessages(got, want); diff != "" {
+		t.Errorf("decoded message differs from want (a: got, b: want):\n%s", diff)
+	}
+	return got
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/gtptest"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join("testdata", "create-session-request.golden")
+	msg := gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5))
+
+	gtptest.GoldenEncode(t, path, msg)
+	decoded := gtptest.GoldenDecode(t, path, msg)
+	if _, ok := decoded.(*messages.CreateSessionRequest); !ok {
+		t.Errorf("got %T", decoded)
+	}
+}
+
+func TestWriteGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir", "msg.golden")
+	msg := gtptest.NewCreateSessionRequest(0x11223344, 1, newBearerContext(5))
+	if err := gtptest.WriteGolden(path, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := gtptest.ReadGolden(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := msg.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != string(want) {
+		t.Errorf("got %x, want %x", b, want)
+	}
+}
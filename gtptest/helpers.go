@@ -0,0 +1,117 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// StartNetwork starts a Network, which is closed at the end of the test.
+// The errors occurred in the handlers of the roles are reported then.
+func StartNetwork(t testing.TB) *Network {
+	t.Helper()
+
+	n, err := NewNetwork()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		n.Close()
+		for _, err := range n.Errors() {
+			t.Errorf("error in handler: %v", err)
+		}
+	})
+	return n
+}
+
+// LoopbackAddr returns the loopback address with an ephemeral port.
+func LoopbackAddr(t testing.TB) *net.UDPAddr {
+	t.Helper()
+
+	laddr, err := net.ResolveUDPAddr("udp", loopback+":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return laddr
+}
+
+// Listen starts a v2.Conn serving on addr, which is closed at the end of the test.
+func Listen(t testing.TB, addr string, restartCounter uint8) *v2.Conn {
+	t.Helper()
+
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, restartCounter, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// ListenPeer opens a bare UDP socket on the loopback address to act as the peer
+// of a v2.Conn, which is closed at the end of the test.
+func ListenPeer(t testing.TB) net.PacketConn {
+	t.Helper()
+
+	peer, err := net.ListenPacket("udp", loopback+":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { peer.Close() })
+	return peer
+}
+
+// ReadMessage reads a GTPv2-C message from peer, failing the test if nothing
+// arrives within Timeout.
+func ReadMessage(t testing.TB, peer net.PacketConn) messages.Message {
+	t.Helper()
+
+	buf := make([]byte, 1500)
+	if err := peer.SetReadDeadline(time.Now().Add(Timeout)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// NewCreateSessionRequest returns a Create Session Request with the IEs set by
+// MME on the initial attach, followed by ie. The AccessPointName and
+// BearerContext are left to the caller, as the tests usually vary them.
+func NewCreateSessionRequest(teid, seq uint32, ie ...*ies.IE) *messages.CreateSessionRequest {
+	return messages.NewCreateSessionRequest(
+		teid, seq,
+		append([]*ies.IE{
+			ies.NewIMSI("123451234567890"),
+			ies.NewMSISDN("819012345678"),
+			ies.NewMobileEquipmentIdentity("123450123456789"),
+			ies.NewServingNetwork("123", "45"),
+			ies.NewRATType(v2.RATTypeEUTRAN),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0, "1.1.1.2", "").WithInstance(1),
+			ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+			ies.NewPDNType(v2.PDNTypeIPv4),
+			ies.NewPDNAddressAllocation("0.0.0.0"),
+			ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+			ies.NewAggregateMaximumBitRate(0x11111111, 0x22222222),
+			ies.NewUETimeZone(9*time.Hour, 0),
+		}, ie...)...,
+	)
+}
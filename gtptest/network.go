@@ -0,0 +1,194 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/mmesim"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Timeout is how long each role waits for the response from its peer.
+const Timeout = 3 * time.Second
+
+// loopback is the address that all the roles listen on.
+const loopback = "127.0.0.1"
+
+// gtpuPort is the port of the U-plane peers set in the bearers, which is
+// not actually served in the Network.
+const gtpuPort = 2152
+
+// Network is a set of MME, S-GW and P-GW connected with each other.
+type Network struct {
+	MME *mmesim.MME
+	SGW *SGW
+	PGW *PGW
+
+	mu      sync.Mutex
+	errs    []error
+	sgws    []*SGW
+	closeCh chan struct{}
+}
+
+// NewNetwork starts P-GW, S-GW and MME in this order, and returns the Network
+// after the MME has exchanged Echo with the S-GW.
+//
+// The Network should be closed with Close after use.
+func NewNetwork() (*Network, error) {
+	n := &Network{closeCh: make(chan struct{})}
+
+	var err error
+	n.PGW, err = newPGW(n)
+	if err != nil {
+		return nil, err
+	}
+	n.SGW, err = newSGW(n, n.PGW.Conn.LocalAddr())
+	if err != nil {
+		n.Close()
+		return nil, err
+	}
+	n.MME, err = newMME(n)
+	if err != nil {
+		n.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+// Close closes all the roles in the Network.
+func (n *Network) Close() {
+	select {
+	case <-n.closeCh:
+		return
+	default:
+		close(n.closeCh)
+	}
+
+	if n.MME != nil {
+		n.MME.Close()
+	}
+	n.mu.Lock()
+	sgws := append([]*SGW{n.SGW}, n.sgws...)
+	n.mu.Unlock()
+	for _, sgw := range sgws {
+		if sgw != nil {
+			sgw.S11Conn.Close()
+			sgw.S5CConn.Close()
+		}
+	}
+	if n.PGW != nil {
+		n.PGW.Conn.Close()
+	}
+}
+
+// AddSGW starts another S-GW connected to the P-GW, e.g., to relocate the sessions
+// to with RelocateSGW of the MME. The S-GW is closed with the Network.
+func (n *Network) AddSGW() (*SGW, error) {
+	sgw, err := newSGW(n, n.PGW.Conn.LocalAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sgws = append(n.sgws, sgw)
+	return sgw, nil
+}
+
+// Errors returns the errors occurred in the handlers of the roles so far.
+//
+// The procedures that failed on the way return an error to the caller, but the
+// details of the failure on the other roles can only be found here.
+func (n *Network) Errors() []error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	errs := make([]error, len(n.errs))
+	copy(errs, n.errs)
+	return errs
+}
+
+func (n *Network) recordError(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.errs = append(n.errs, err)
+}
+
+// monitor records the errors sent to errCh until the Network is closed.
+func (n *Network) monitor(errCh chan error) {
+	for {
+		select {
+		case <-n.closeCh:
+			return
+		case err := <-errCh:
+			n.recordError(err)
+		}
+	}
+}
+
+// wrap returns the handlers that record the error returned by the ones given
+// instead of passing it to the Conn.
+func (n *Network) wrap(handlers map[uint8]v2.HandlerFunc) map[uint8]v2.HandlerFunc {
+	wrapped := make(map[uint8]v2.HandlerFunc, len(handlers))
+	for msgType, fn := range handlers {
+		fn := fn
+		wrapped[msgType] = func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			if err := fn(c, senderAddr, msg); err != nil {
+				n.recordError(err)
+			}
+			return nil
+		}
+	}
+	return wrapped
+}
+
+// newMME starts the MME dialing to the S-GW, whose errors are recorded in n.
+func newMME(n *Network) (*mmesim.MME, error) {
+	laddr, err := net.ResolveUDPAddr("udp", loopback+":0")
+	if err != nil {
+		return nil, err
+	}
+
+	errCh := make(chan error)
+	go n.monitor(errCh)
+	return mmesim.Dial(&mmesim.Config{
+		LocalAddr: laddr,
+		SGWAddr:   n.SGW.S11Conn.LocalAddr(),
+		Timeout:   Timeout,
+	}, errCh)
+}
+
+func listen() (*v2.Conn, error) {
+	laddr, err := net.ResolveUDPAddr("udp", loopback+":0")
+	if err != nil {
+		return nil, err
+	}
+	return v2.ListenAndServe(laddr, 0, make(chan error))
+}
+
+// checkCause returns an error if the Cause IE in msg is missing or not accepted.
+func checkCause(msg messages.Message, cause *ies.IE) error {
+	if cause == nil {
+		return &v2.ErrRequiredIEMissing{Type: ies.Cause}
+	}
+	if c := cause.Cause(); c != v2.CauseRequestAccepted {
+		return &v2.ErrCauseNotOK{MsgType: msg.MessageTypeName(), Cause: c}
+	}
+	return nil
+}
+
+// passResponse passes the response to the session waiting for it.
+func passResponse(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	return v2.PassMessageTo(sess, msg, Timeout)
+}
@@ -0,0 +1,250 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/gtptest"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+func newSubscriber(imsi string) *v2.Subscriber {
+	return &v2.Subscriber{
+		IMSI:     imsi,
+		MSISDN:   "819012345678",
+		IMEI:     "123456789012345",
+		Location: &v2.Location{MCC: "001", MNC: "01"},
+	}
+}
+
+func sessionCounts(n *gtptest.Network) [4]int {
+	return [4]int{
+		len(n.MME.Conn.ListSessions()),
+		len(n.SGW.S11Conn.ListSessions()),
+		len(n.SGW.S5CConn.ListSessions()),
+		len(n.PGW.Conn.ListSessions()),
+	}
+}
+
+func TestAttachDetach(t *testing.T) {
+	n := gtptest.StartNetwork(t)
+
+	imsis := []string{"001010000000001", "001010000000002"}
+	ips := map[string]bool{}
+	for _, imsi := range imsis {
+		sess, err := n.MME.Attach(newSubscriber(imsi), "some.apn.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sess.IsActive() {
+			t.Errorf("session for %s is not active", imsi)
+		}
+		ip := sess.GetDefaultBearer().SubscriberIP
+		if !gtptest.SubscriberNetwork.Contains(net.ParseIP(ip)) || ips[ip] {
+			t.Errorf("unexpected subscriber IP for %s: %v", imsi, ip)
+		}
+		ips[ip] = true
+	}
+	if got, want := sessionCounts(n), [4]int{2, 2, 2, 2}; got != want {
+		t.Fatalf("unexpected session counts: got %v, want %v", got, want)
+	}
+
+	// the TEIDs exchanged should match on both sides of each interface.
+	for _, imsi := range imsis {
+		mmeSess, err := n.MME.Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s11Sess, err := n.SGW.S11Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s5Sess, err := n.SGW.S5CConn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pgwSess, err := n.PGW.Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pairs := []struct {
+			ifType uint8
+			a, b   *v2.Session
+		}{
+			{v2.IFTypeS11MMEGTPC, mmeSess, s11Sess},
+			{v2.IFTypeS11S4SGWGTPC, mmeSess, s11Sess},
+			{v2.IFTypeS1USGWGTPU, mmeSess, s11Sess},
+			{v2.IFTypeS1UeNodeBGTPU, mmeSess, s11Sess},
+			{v2.IFTypeS5S8SGWGTPC, s5Sess, pgwSess},
+			{v2.IFTypeS5S8PGWGTPC, s5Sess, pgwSess},
+			{v2.IFTypeS5S8SGWGTPU, s5Sess, pgwSess},
+			{v2.IFTypeS5S8PGWGTPU, s5Sess, pgwSess},
+		}
+		for _, p := range pairs {
+			a, err := p.a.GetTEID(p.ifType)
+			if err != nil {
+				t.Fatalf("%s: %v: %v", imsi, v2.IFType(p.ifType), err)
+			}
+			b, err := p.b.GetTEID(p.ifType)
+			if err != nil {
+				t.Fatalf("%s: %v: %v", imsi, v2.IFType(p.ifType), err)
+			}
+			if a != b {
+				t.Errorf("%s: %v: TEID mismatch: %#x != %#x", imsi, v2.IFType(p.ifType), a, b)
+			}
+		}
+	}
+
+	for _, imsi := range imsis {
+		if err := n.MME.Detach(imsi); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := sessionCounts(n), [4]int{0, 0, 0, 0}; got != want {
+		t.Fatalf("unexpected session counts: got %v, want %v", got, want)
+	}
+}
+
+func TestHandover(t *testing.T) {
+	n := gtptest.StartNetwork(t)
+
+	imsi := "001010000000001"
+	if _, err := n.MME.Attach(newSubscriber(imsi), "some.apn.example"); err != nil {
+		t.Fatal(err)
+	}
+	s11Sess, err := n.SGW.S11Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := s11Sess.GetTEID(v2.IFTypeS1UeNodeBGTPU)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.MME.TriggerHandover(imsi, "127.0.0.2"); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := s11Sess.GetTEID(v2.IFTypeS1UeNodeBGTPU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Errorf("eNB TEID not updated on S-GW: %#x", after)
+	}
+	mmeSess, err := n.MME.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teid, _ := mmeSess.GetTEID(v2.IFTypeS1UeNodeBGTPU); teid != after {
+		t.Errorf("eNB TEID mismatch: MME: %#x, S-GW: %#x", teid, after)
+	}
+
+	if err := n.MME.Detach(imsi); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRelocateSGW(t *testing.T) {
+	n := gtptest.StartNetwork(t)
+
+	imsis := []string{"001010000000001", "001010000000002"}
+	ips := map[string]string{}
+	for _, imsi := range imsis {
+		sess, err := n.MME.Attach(newSubscriber(imsi), "some.apn.example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ips[imsi] = sess.GetDefaultBearer().SubscriberIP
+	}
+	// to relocate the UE in ECM-IDLE as well.
+	if err := n.MME.ReleaseAccessBearers(imsis[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	newSGW, err := n.AddSGW()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := n.MME.RelocateSGW(newSGW.S11Conn.LocalAddr(), imsis...); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sessionCounts(n), [4]int{2, 0, 0, 2}; got != want {
+		t.Fatalf("unexpected session counts: got %v, want %v", got, want)
+	}
+	for _, imsi := range imsis {
+		mmeSess, err := n.MME.Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := mmeSess.PeerAddr.String(), newSGW.S11Conn.LocalAddr().String(); got != want {
+			t.Errorf("%s: S-GW not relocated: got %s, want %s", imsi, got, want)
+		}
+		s11Sess, err := newSGW.S11Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s5Sess, err := newSGW.S5CConn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pgwSess, err := n.PGW.Conn.GetSessionByIMSI(imsi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pgwSess.GetDefaultBearer().SubscriberIP; got != ips[imsi] {
+			t.Errorf("%s: PDN connection not kept: got %s, want %s", imsi, got, ips[imsi])
+		}
+
+		pairs := []struct {
+			ifType uint8
+			a, b   *v2.Session
+		}{
+			{v2.IFTypeS11S4SGWGTPC, mmeSess, s11Sess},
+			{v2.IFTypeS1USGWGTPU, mmeSess, s11Sess},
+			{v2.IFTypeS5S8SGWGTPC, s5Sess, pgwSess},
+			{v2.IFTypeS5S8SGWGTPU, s5Sess, pgwSess},
+			{v2.IFTypeS5S8PGWGTPC, s5Sess, pgwSess},
+		}
+		for _, p := range pairs {
+			a, err := p.a.GetTEID(p.ifType)
+			if err != nil {
+				t.Fatalf("%s: %v: %v", imsi, v2.IFType(p.ifType), err)
+			}
+			b, err := p.b.GetTEID(p.ifType)
+			if err != nil {
+				t.Fatalf("%s: %v: %v", imsi, v2.IFType(p.ifType), err)
+			}
+			if a != b {
+				t.Errorf("%s: %v: TEID mismatch: %#x != %#x", imsi, v2.IFType(p.ifType), a, b)
+			}
+		}
+	}
+
+	// the session relocated is detached through the new S-GW.
+	for _, imsi := range imsis {
+		if err := n.MME.Detach(imsi); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := sessionCounts(n), [4]int{0, 0, 0, 0}; got != want {
+		t.Fatalf("unexpected session counts: got %v, want %v", got, want)
+	}
+	if got := len(newSGW.S11Conn.ListSessions()); got != 0 {
+		t.Errorf("sessions left on the new S-GW: %d", got)
+	}
+}
+
+func TestDetachUnknown(t *testing.T) {
+	n := gtptest.StartNetwork(t)
+
+	if err := n.MME.Detach("001010000000009"); err != v2.ErrUnknownIMSI {
+		t.Errorf("unexpected error: got %v, want %v", err, v2.ErrUnknownIMSI)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SubscriberNetwork is the network that the P-GW allocates the IP addresses
+// of the subscribers from, in the order of the attach.
+var SubscriberNetwork = &net.IPNet{
+	IP:   net.IPv4(10, 45, 0, 0).To4(),
+	Mask: net.CIDRMask(16, 32),
+}
+
+// PGW is a P-GW that accepts all the sessions requested by the S-GW.
+type PGW struct {
+	// Conn is the S5/S8-C Conn of the P-GW.
+	Conn *v2.Conn
+
+	mu     sync.Mutex
+	lastIP uint32
+}
+
+func newPGW(n *Network) (*PGW, error) {
+	conn, err := listen()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &PGW{Conn: conn}
+	conn.AddHandlers(n.wrap(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionRequest: p.handleCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest:  p.handleModifyBearerRequest,
+		messages.MsgTypeDeleteSessionRequest: p.handleDeleteSessionRequest,
+	}))
+	return p, nil
+}
+
+// allocateIP returns the next IP address in SubscriberNetwork.
+func (p *PGW) allocateIP() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastIP++
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(SubscriberNetwork.IP)+p.lastIP)
+	return ip.String()
+}
+
+func (p *PGW) handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
+	csReqFromSGW, ok := msg.(*messages.CreateSessionRequest)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+
+	var missing uint8
+	switch {
+	case csReqFromSGW.IMSI == nil:
+		missing = ies.IMSI
+	case csReqFromSGW.SenderFTEIDC == nil:
+		missing = ies.FullyQualifiedTEID
+	case csReqFromSGW.BearerContextsToBeCreated == nil:
+		missing = ies.BearerContext
+	}
+	if missing != 0 {
+		csRsp := messages.NewCreateSessionResponse(
+			0, 0, ies.NewCause(v2.CauseMandatoryIEMissing, 0, 0, 0, nil),
+		)
+		if ie := csReqFromSGW.SenderFTEIDC; ie != nil {
+			csRsp.SetTEID(ie.TEID())
+		}
+		if err := c.RespondTo(sgwAddr, csReqFromSGW, csRsp); err != nil {
+			return err
+		}
+		return &v2.ErrRequiredIEMissing{Type: missing}
+	}
+
+	session := v2.NewSession(sgwAddr, &v2.Subscriber{
+		IMSI: csReqFromSGW.IMSI.IMSI(), Location: &v2.Location{},
+	})
+	sgwTEID := csReqFromSGW.SenderFTEIDC.TEID()
+	session.AddTEID(v2.IFTypeS5S8SGWGTPC, sgwTEID)
+
+	br := session.GetDefaultBearer()
+	if ie := csReqFromSGW.APN; ie != nil {
+		br.APN = ie.AccessPointName()
+	}
+	var ebi *ies.IE
+	for _, ie := range csReqFromSGW.BearerContextsToBeCreated.BearerContext() {
+		switch ie.Type {
+		case ies.EPSBearerID:
+			ebi = ie
+			br.EBI = ie.EPSBearerID()
+		case ies.FullyQualifiedTEID:
+			session.AddTEID(ie.InterfaceType(), ie.TEID())
+			br.SetOutgoingTEID(ie.TEID())
+		}
+	}
+
+	cFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPC, loopback, "").WithInstance(1)
+	session.AddTEID(v2.IFTypeS5S8PGWGTPC, cFTEID.TEID())
+	uFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPU, loopback, "").WithInstance(2)
+	session.AddTEID(v2.IFTypeS5S8PGWGTPU, uFTEID.TEID())
+	br.SetIncomingTEID(uFTEID.TEID())
+	// the static address requested in the PDN Address Allocation is honored as it is.
+	br.SubscriberIP = p.allocateIP()
+	if ie := csReqFromSGW.PAA; ie != nil {
+		if ip := ie.IPAddress(); ip != "" && ip != "0.0.0.0" {
+			br.SubscriberIP = ip
+		}
+	}
+
+	if err := session.Activate(); err != nil {
+		return err
+	}
+	c.AddSession(session)
+
+	csRsp := messages.NewCreateSessionResponse(
+		sgwTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		cFTEID,
+		ies.NewPDNAddressAllocation(br.SubscriberIP),
+		ies.NewBearerContext(
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ebi,
+			uFTEID,
+			ies.NewChargingID(1),
+		),
+	)
+	return c.RespondTo(sgwAddr, csReqFromSGW, csRsp)
+}
+
+// handleModifyBearerRequest switches the path of the session to the S-GW that sent
+// the request with its F-TEIDs, on S-GW relocation.
+//
+// The P-GW with U-plane should send End Markers to the old S-GW here, which is not
+// done as no U-plane is set up.
+func (p *PGW) handleModifyBearerRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
+	mbReqFromSGW, ok := msg.(*messages.ModifyBearerRequest)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+
+	session, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+
+	if ie := mbReqFromSGW.SenderFTEIDC; ie != nil {
+		session.AddTEID(v2.IFTypeS5S8SGWGTPC, ie.TEID())
+		session.PeerAddr = sgwAddr
+	}
+	sgwTEID, err := session.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	var ebi *ies.IE
+	if ie := mbReqFromSGW.BearerContextsToBeModified; ie != nil {
+		br := session.GetDefaultBearer()
+		for _, child := range ie.BearerContext() {
+			switch child.Type {
+			case ies.EPSBearerID:
+				ebi = child
+			case ies.FullyQualifiedTEID:
+				session.AddTEID(child.InterfaceType(), child.TEID())
+				br.SetOutgoingTEID(child.TEID())
+			}
+		}
+	}
+
+	rspIEs := []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)}
+	if ebi != nil {
+		rspIEs = append(rspIEs, ies.NewBearerContext(
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), ebi,
+		))
+	}
+	return c.RespondTo(sgwAddr, mbReqFromSGW, messages.NewModifyBearerResponse(sgwTEID, 0, rspIEs...))
+}
+
+func (p *PGW) handleDeleteSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
+	session, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	defer c.RemoveSession(session)
+
+	sgwTEID, err := session.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	dsRsp := messages.NewDeleteSessionResponse(
+		sgwTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	return c.RespondTo(sgwAddr, msg, dsRsp)
+}
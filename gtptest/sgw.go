@@ -0,0 +1,476 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtptest
+
+import (
+	"errors"
+	"net"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SGW is an S-GW that relays the requests from the MME to the P-GW.
+//
+// The sessions on S11 and S5/S8-C are kept separately in each Conn, and
+// they are associated with each other by IMSI.
+type SGW struct {
+	// S11Conn is the Conn of the S-GW facing the MME.
+	S11Conn *v2.Conn
+	// S5CConn is the Conn of the S-GW facing the P-GW.
+	S5CConn *v2.Conn
+
+	pgwAddr net.Addr
+}
+
+// newSGW starts the S-GW that sends the requests to the P-GW at pgwAddr.
+//
+// The P-GW address is given instead of taken from the F-TEID in the Create Session
+// Request, as the P-GW does not listen on the well-known port.
+func newSGW(n *Network, pgwAddr net.Addr) (*SGW, error) {
+	s11Conn, err := listen()
+	if err != nil {
+		return nil, err
+	}
+	s5cConn, err := listen()
+	if err != nil {
+		s11Conn.Close()
+		return nil, err
+	}
+
+	s := &SGW{S11Conn: s11Conn, S5CConn: s5cConn, pgwAddr: pgwAddr}
+	s11Conn.AddHandlers(n.wrap(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionRequest:        s.handleCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest:         s.handleModifyBearerRequest,
+		messages.MsgTypeReleaseAccessBearersRequest: s.handleReleaseAccessBearersRequest,
+		messages.MsgTypeDeleteSessionRequest:        s.handleDeleteSessionRequest,
+	}))
+	s5cConn.AddHandlers(n.wrap(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionResponse: passResponse,
+		messages.MsgTypeModifyBearerResponse:  passResponse,
+		messages.MsgTypeDeleteSessionResponse: passResponse,
+	}))
+	return s, nil
+}
+
+func (s *SGW) handleCreateSessionRequest(c *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	csReqFromMME, ok := msg.(*messages.CreateSessionRequest)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+
+	reject := func(cause uint8, err error) error {
+		csRsp := messages.NewCreateSessionResponse(0, 0, ies.NewCause(cause, 0, 0, 0, nil))
+		if ie := csReqFromMME.SenderFTEIDC; ie != nil {
+			csRsp.SetTEID(ie.TEID())
+		}
+		if rerr := c.RespondTo(mmeAddr, csReqFromMME, csRsp); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	var missing uint8
+	switch {
+	case csReqFromMME.IMSI == nil:
+		missing = ies.IMSI
+	case csReqFromMME.SenderFTEIDC == nil:
+		missing = ies.FullyQualifiedTEID
+	case csReqFromMME.BearerContextsToBeCreated == nil:
+		missing = ies.BearerContext
+	}
+	if missing != 0 {
+		return reject(v2.CauseMandatoryIEMissing, &v2.ErrRequiredIEMissing{Type: missing})
+	}
+
+	sub := &v2.Subscriber{IMSI: csReqFromMME.IMSI.IMSI(), Location: &v2.Location{}}
+	if ie := csReqFromMME.MSISDN; ie != nil {
+		sub.MSISDN = ie.MSISDN()
+	}
+	if ie := csReqFromMME.MEI; ie != nil {
+		sub.IMEI = ie.MobileEquipmentIdentity()
+	}
+	if ie := csReqFromMME.ServingNetwork; ie != nil {
+		sub.MCC = ie.MCC()
+		sub.MNC = ie.MNC()
+	}
+
+	mmeTEID := csReqFromMME.SenderFTEIDC.TEID()
+	s11Session := v2.NewSession(mmeAddr, sub)
+	s11Session.AddTEID(v2.IFTypeS11MMEGTPC, mmeTEID)
+	s11FTEID := s.S11Conn.NewFTEID(v2.IFTypeS11S4SGWGTPC, loopback, "")
+	s11Session.AddTEID(v2.IFTypeS11S4SGWGTPC, s11FTEID.TEID())
+	s1uFTEID := s.S11Conn.NewFTEID(v2.IFTypeS1USGWGTPU, loopback, "")
+	s11Session.AddTEID(v2.IFTypeS1USGWGTPU, s1uFTEID.TEID())
+
+	s5Session := v2.NewSession(s.pgwAddr, sub)
+	s5cFTEID := s.S5CConn.NewFTEID(v2.IFTypeS5S8SGWGTPC, loopback, "")
+	s5Session.AddTEID(v2.IFTypeS5S8SGWGTPC, s5cFTEID.TEID())
+	s5uFTEID := s.S5CConn.NewFTEID(v2.IFTypeS5S8SGWGTPU, loopback, "")
+	s5Session.AddTEID(v2.IFTypeS5S8SGWGTPU, s5uFTEID.TEID())
+
+	// the F-TEIDs of P-GW and eNB are given by the MME on S-GW relocation.
+	var ebi, qos, pgwS5UFTEID, enbFTEID *ies.IE
+	for _, ie := range csReqFromMME.BearerContextsToBeCreated.BearerContext() {
+		switch ie.Type {
+		case ies.EPSBearerID:
+			ebi = ie
+		case ies.BearerQoS:
+			qos = ie
+		case ies.FullyQualifiedTEID:
+			switch ie.InterfaceType() {
+			case v2.IFTypeS5S8PGWGTPU:
+				pgwS5UFTEID = ie
+			case v2.IFTypeS1UeNodeBGTPU:
+				enbFTEID = ie
+			}
+		}
+	}
+	if ebi == nil {
+		return reject(v2.CauseMandatoryIEMissing, &v2.ErrRequiredIEMissing{Type: ies.EPSBearerID})
+	}
+	for _, sess := range []*v2.Session{s11Session, s5Session} {
+		br := sess.GetDefaultBearer()
+		br.EBI = ebi.EPSBearerID()
+		if ie := csReqFromMME.APN; ie != nil {
+			br.APN = ie.AccessPointName()
+		}
+	}
+
+	s.S11Conn.AddSession(s11Session)
+	s.S5CConn.AddSession(s5Session)
+	cleanup := func() {
+		s.S11Conn.RemoveSession(s11Session)
+		s.S5CConn.RemoveSession(s5Session)
+	}
+
+	var (
+		pgwS5CFTEID = csReqFromMME.PGWS5S8FTEIDC
+		paa         *ies.IE
+		err         error
+	)
+	if pgwS5CFTEID != nil && pgwS5UFTEID != nil {
+		// the P-GW keeps the PDN connection and only switches the path to this S-GW.
+		paa = csReqFromMME.PAA
+		err = s.modifyS5Session(s5Session, pgwS5CFTEID, ebi, s5cFTEID, s5uFTEID)
+	} else {
+		pgwS5CFTEID, pgwS5UFTEID, paa, err = s.createS5Session(s5Session, csReqFromMME, ebi, qos, s5cFTEID, s5uFTEID)
+	}
+	if err == nil && paa == nil {
+		err = &v2.ErrRequiredIEMissing{Type: ies.PDNAddressAllocation}
+	}
+	if err != nil {
+		cleanup()
+		return reject(causeOf(err), err)
+	}
+	s5Session.AddTEID(v2.IFTypeS5S8PGWGTPC, pgwS5CFTEID.TEID())
+	s5Session.AddTEID(v2.IFTypeS5S8PGWGTPU, pgwS5UFTEID.TEID())
+
+	subscriberIP := paa.IPAddress()
+	s11br := s11Session.GetDefaultBearer()
+	s11br.SubscriberIP = subscriberIP
+	if enbFTEID != nil {
+		s11Session.AddTEID(v2.IFTypeS1UeNodeBGTPU, enbFTEID.TEID())
+		s11br.SetOutgoingTEID(enbFTEID.TEID())
+		s11br.SetRemoteAddress(&net.UDPAddr{IP: net.ParseIP(enbFTEID.IPAddress()), Port: gtpuPort})
+	}
+	s5br := s5Session.GetDefaultBearer()
+	s5br.SubscriberIP = subscriberIP
+	s5br.SetOutgoingTEID(pgwS5UFTEID.TEID())
+
+	if err := s11Session.Activate(); err != nil {
+		cleanup()
+		return reject(v2.CauseNoResourcesAvailable, err)
+	}
+	if err := s5Session.Activate(); err != nil {
+		cleanup()
+		return reject(v2.CauseNoResourcesAvailable, err)
+	}
+
+	csRspToMME := messages.NewCreateSessionResponse(
+		mmeTEID, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		s11FTEID,
+		pgwS5CFTEID,
+		paa,
+		ies.NewBearerContext(
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ebi,
+			s1uFTEID,
+			pgwS5UFTEID.WithInstance(2),
+		),
+	)
+	return c.RespondTo(mmeAddr, csReqFromMME, csRspToMME)
+}
+
+// createS5Session creates the session on the P-GW for the one requested by the MME,
+// and returns the F-TEIDs of the P-GW and the PDN Address Allocation.
+func (s *SGW) createS5Session(
+	s5Session *v2.Session, csReqFromMME *messages.CreateSessionRequest,
+	ebi, qos, s5cFTEID, s5uFTEID *ies.IE,
+) (pgwS5CFTEID, pgwS5UFTEID, paa *ies.IE, err error) {
+	csReqToPGW := messages.NewCreateSessionRequest(
+		0, s5Session.Sequence,
+		csReqFromMME.IMSI,
+		csReqFromMME.MSISDN,
+		csReqFromMME.MEI,
+		csReqFromMME.ServingNetwork,
+		csReqFromMME.RATType,
+		s5cFTEID,
+		csReqFromMME.APN,
+		csReqFromMME.SelectionMode,
+		csReqFromMME.PDNType,
+		csReqFromMME.PAA,
+		csReqFromMME.AMBR,
+		ies.NewBearerContext(ebi, s5uFTEID.WithInstance(2), qos),
+	)
+	if err := s.S5CConn.SendMessageTo(csReqToPGW, s.pgwAddr); err != nil {
+		return nil, nil, nil, err
+	}
+	rsp, err := s5Session.WaitMessage(Timeout)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	csRspFromPGW, ok := rsp.(*messages.CreateSessionResponse)
+	if !ok {
+		return nil, nil, nil, v2.ErrUnexpectedType
+	}
+	if err := checkCause(csRspFromPGW, csRspFromPGW.Cause); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if ie := csRspFromPGW.BearerContextsCreated; ie != nil {
+		for _, child := range ie.BearerContext() {
+			if child.Type == ies.FullyQualifiedTEID && child.InterfaceType() == v2.IFTypeS5S8PGWGTPU {
+				pgwS5UFTEID = child
+			}
+		}
+	}
+	pgwS5CFTEID = csRspFromPGW.PGWS5S8FTEIDC
+	if pgwS5CFTEID == nil || pgwS5UFTEID == nil {
+		return nil, nil, nil, &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+	return pgwS5CFTEID, pgwS5UFTEID, csRspFromPGW.PAA, nil
+}
+
+// modifyS5Session switches the path of the session on the P-GW to this S-GW with
+// Modify Bearer Request, on S-GW relocation.
+func (s *SGW) modifyS5Session(s5Session *v2.Session, pgwS5CFTEID, ebi, s5cFTEID, s5uFTEID *ies.IE) error {
+	s5Session.Sequence++
+	mbReqToPGW := messages.NewModifyBearerRequest(
+		pgwS5CFTEID.TEID(), s5Session.Sequence,
+		s5cFTEID,
+		ies.NewBearerContext(ebi, s5uFTEID.WithInstance(1)),
+	)
+	if err := s.S5CConn.SendMessageTo(mbReqToPGW, s.pgwAddr); err != nil {
+		return err
+	}
+	rsp, err := s5Session.WaitMessage(Timeout)
+	if err != nil {
+		return err
+	}
+	mbRspFromPGW, ok := rsp.(*messages.ModifyBearerResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	return checkCause(mbRspFromPGW, mbRspFromPGW.Cause)
+}
+
+// causeOf returns the Cause to reject the request from the MME with, for the error
+// occurred on S5/S8-C.
+func causeOf(err error) uint8 {
+	var (
+		causeErr   *v2.ErrCauseNotOK
+		missingErr *v2.ErrRequiredIEMissing
+	)
+	switch {
+	case errors.As(err, &causeErr):
+		return causeErr.Cause
+	case errors.As(err, &missingErr):
+		return v2.CauseMandatoryIEMissing
+	default:
+		return v2.CauseNoResourcesAvailable
+	}
+}
+
+func (s *SGW) handleModifyBearerRequest(c *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	mbReqFromMME, ok := msg.(*messages.ModifyBearerRequest)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+
+	s11Session, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	mmeTEID, err := s11Session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	s1uTEID, err := s11Session.GetTEID(v2.IFTypeS1USGWGTPU)
+	if err != nil {
+		return err
+	}
+
+	respond := func(cause uint8, ie ...*ies.IE) error {
+		mbRsp := messages.NewModifyBearerResponse(
+			mmeTEID, 0,
+			append([]*ies.IE{ies.NewCause(cause, 0, 0, 0, nil)}, ie...)...,
+		)
+		return c.RespondTo(mmeAddr, mbReqFromMME, mbRsp)
+	}
+
+	var ebi, enbFTEID *ies.IE
+	if ie := mbReqFromMME.BearerContextsToBeModified; ie != nil {
+		for _, child := range ie.BearerContext() {
+			switch child.Type {
+			case ies.EPSBearerID:
+				ebi = child
+			case ies.FullyQualifiedTEID:
+				enbFTEID = child
+			}
+		}
+	}
+	if ebi == nil || enbFTEID == nil {
+		if err := respond(v2.CauseMandatoryIEMissing); err != nil {
+			return err
+		}
+		return &v2.ErrRequiredIEMissing{Type: ies.BearerContext}
+	}
+
+	br, err := s11Session.LookupBearerByEBI(ebi.EPSBearerID())
+	if err != nil {
+		if rerr := respond(v2.CauseContextNotFound); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	s11Session.AddTEID(v2.IFTypeS1UeNodeBGTPU, enbFTEID.TEID())
+	br.SetOutgoingTEID(enbFTEID.TEID())
+	br.SetRemoteAddress(&net.UDPAddr{IP: net.ParseIP(enbFTEID.IPAddress()), Port: gtpuPort})
+	// the session is deactivated while the UE is in ECM-IDLE.
+	if err := s11Session.Activate(); err != nil {
+		return err
+	}
+
+	return respond(
+		v2.CauseRequestAccepted,
+		ies.NewBearerContext(
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ebi,
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, s1uTEID, loopback, ""),
+		),
+	)
+}
+
+func (s *SGW) handleReleaseAccessBearersRequest(c *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	s11Session, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	mmeTEID, err := s11Session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	// forget the eNB until the next Modify Bearer Request, as the UE is in ECM-IDLE.
+	for _, br := range s11Session.Bearers() {
+		br.SetOutgoingTEID(0)
+		br.SetRemoteAddress(nil)
+	}
+	if err := s11Session.Deactivate(); err != nil {
+		return err
+	}
+
+	rabRsp := messages.NewReleaseAccessBearersResponse(
+		mmeTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	return c.RespondTo(mmeAddr, msg, rabRsp)
+}
+
+func (s *SGW) handleDeleteSessionRequest(c *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
+	dsReqFromMME, ok := msg.(*messages.DeleteSessionRequest)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+
+	s11Session, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	mmeTEID, err := s11Session.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	// even if the P-GW does not respond, the sessions should be removed locally.
+	defer c.RemoveSession(s11Session)
+
+	// respond with the Cause from the P-GW, or Context Not Found if not available.
+	cause := v2.CauseRequestAccepted
+	// the MME does not set the OI flag on S-GW relocation, as the P-GW keeps the
+	// PDN connection with the new S-GW.
+	if ie := dsReqFromMME.IndicationFlags; ie != nil && !ie.OperationIndication() {
+		err = s.removeS5Session(s11Session.IMSI)
+	} else {
+		err = s.deleteS5Session(s11Session.IMSI, dsReqFromMME.LinkedEBI)
+	}
+	if err != nil {
+		cause = v2.CauseContextNotFound
+		var causeErr *v2.ErrCauseNotOK
+		if errors.As(err, &causeErr) {
+			cause = causeErr.Cause
+		}
+	}
+
+	if rerr := c.RespondTo(
+		mmeAddr, dsReqFromMME,
+		messages.NewDeleteSessionResponse(mmeTEID, 0, ies.NewCause(cause, 0, 0, 0, nil)),
+	); rerr != nil {
+		return rerr
+	}
+	return err
+}
+
+// removeS5Session removes the session on S5/S8-C for the subscriber locally.
+func (s *SGW) removeS5Session(imsi string) error {
+	s5Session, err := s.S5CConn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	s.S5CConn.RemoveSession(s5Session)
+	return nil
+}
+
+// deleteS5Session sends Delete Session Request to the P-GW for the subscriber,
+// and removes the session on S5/S8-C regardless of the result.
+func (s *SGW) deleteS5Session(imsi string, ebi *ies.IE) error {
+	s5Session, err := s.S5CConn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	defer s.S5CConn.RemoveSession(s5Session)
+
+	pgwTEID, err := s5Session.GetTEID(v2.IFTypeS5S8PGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	s5Session.Sequence++
+	dsReq := messages.NewDeleteSessionRequest(pgwTEID, s5Session.Sequence, ebi)
+	if err := s.S5CConn.SendMessageTo(dsReq, s.pgwAddr); err != nil {
+		return err
+	}
+	rsp, err := s5Session.WaitMessage(Timeout)
+	if err != nil {
+		return err
+	}
+	dsRsp, ok := rsp.(*messages.DeleteSessionResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	return checkCause(dsRsp, dsRsp.Cause)
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpvalidate checks the GTPv1 and GTPv2 messages against the rules in
+// 3GPP TS 29.060 and TS 29.274, and reports the violations found.
+//
+// The rules checked are;
+//
+//   - the flags and spare bits in the header.
+//   - the presence of the mandatory IEs, including the ones in the grouped IEs.
+//   - the instances of the IEs allowed in the message(GTPv2 only).
+//   - the length, the value range and the spare bits of the well-known IEs.
+//
+// The presence and instance rules are defined only for the messages that are
+// commonly used. The other messages are checked only with the rest of the rules.
+//
+// The conditional IEs are not required, as the conditions often depend on the
+// context that cannot be known from the message itself.
+//
+// Validate can be used both in the tests to see if the messages built are
+// conformant, and at runtime to reject the non-conformant messages strictly;
+//
+//	if err := gtpvalidate.Validate(msg).Err(); err != nil {
+//		// respond with the Cause like "Mandatory IE missing" or just drop it.
+//	}
+package gtpvalidate
@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpvalidate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is the kind of the rules checked.
+type Rule uint8
+
+// Rule definitions.
+const (
+	RuleMalformed Rule = iota + 1
+	RuleHeader
+	RuleMandatoryIE
+	RuleInstance
+	RuleLength
+	RuleValueRange
+	RuleSpareBits
+)
+
+// String returns the name of the Rule.
+func (r Rule) String() string {
+	switch r {
+	case RuleMalformed:
+		return "Malformed"
+	case RuleHeader:
+		return "Header"
+	case RuleMandatoryIE:
+		return "MandatoryIE"
+	case RuleInstance:
+		return "Instance"
+	case RuleLength:
+		return "Length"
+	case RuleValueRange:
+		return "ValueRange"
+	case RuleSpareBits:
+		return "SpareBits"
+	default:
+		return fmt.Sprintf("Rule(%d)", uint8(r))
+	}
+}
+
+// Violation is a violation of a rule found in the message.
+type Violation struct {
+	Rule Rule
+
+	// IEType and Instance are the type and instance of the IE that violates the rule.
+	// They are 0 if the violation is in the header or the whole message.
+	IEType, Instance uint8
+
+	// Path is the location of the violation, like "Header" or
+	// "BearerContext(0)/EPSBearerID(0)" for the IEs.
+	Path string
+
+	// Reason describes the violation in human readable format.
+	Reason string
+}
+
+// String returns the Violation in human readable format.
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s", v.Rule, v.Path, v.Reason)
+}
+
+// Report is the result of the validation of a message.
+type Report struct {
+	Version     int
+	MessageType uint8
+
+	// Name is the name of the message type.
+	Name string
+
+	// Violations are the violations found in the message, in the order found.
+	Violations []*Violation
+}
+
+// OK reports whether the message has no violations.
+func (r *Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// Err returns the Report as an error if the message has any violations, or nil.
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return r
+}
+
+// Error returns the violations in one line.
+func (r *Report) Error() string {
+	vs := make([]string, len(r.Violations))
+	for i, v := range r.Violations {
+		vs[i] = v.String()
+	}
+	return fmt.Sprintf("GTPv%d %s violates %d rule(s): %s", r.Version, r.Name, len(vs), strings.Join(vs, "; "))
+}
+
+// String returns the Report in human readable, multi-line format.
+func (r *Report) String() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "GTPv%d %s: %d violation(s)\n", r.Version, r.Name, len(r.Violations))
+	for _, v := range r.Violations {
+		fmt.Fprintf(s, "  %s\n", v)
+	}
+	return s.String()
+}
+
+func (r *Report) add(rule Rule, typ, instance uint8, path, format string, a ...interface{}) {
+	r.Violations = append(r.Violations, &Violation{
+		Rule:     rule,
+		IEType:   typ,
+		Instance: instance,
+		Path:     path,
+		Reason:   fmt.Sprintf(format, a...),
+	})
+}
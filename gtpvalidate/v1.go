@@ -0,0 +1,183 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpvalidate
+
+import (
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func validateV1(b []byte) *Report {
+	r := &Report{Version: 1}
+	h, err := messages.DecodeHeader(b)
+	if err != nil {
+		r.Name = "Unknown"
+		r.add(RuleMalformed, 0, 0, "Header", "%s", err)
+		return r
+	}
+	r.MessageType = h.Type
+	r.Name = messages.MessageType(h.Type).String()
+
+	checkHeaderV1(r, h, len(b))
+
+	// the payload of T-PDU is the user data, not IEs.
+	if h.Type == messages.MsgTypeTPDU {
+		return r
+	}
+
+	decoded, err := ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		r.add(RuleMalformed, 0, 0, "Message", "%s", err)
+	}
+
+	nodes := make([]*node, len(decoded))
+	for i, ie := range decoded {
+		nodes[i] = &node{key: ieKey{ie.Type, 0}, path: ie.Name()}
+		checkIEV1(r, ie, nodes[i].path)
+	}
+
+	if rules, ok := rulesV1[h.Type]; ok {
+		checkPresence(r, rules, nodes, "", labelV1, false)
+	}
+	return r
+}
+
+func labelV1(k ieKey) string {
+	return (&ies.IE{Type: k.typ}).Name()
+}
+
+func checkHeaderV1(r *Report, h *messages.Header, length int) {
+	if int(h.Length)+8 != length {
+		r.add(RuleHeader, 0, 0, "Header", "Length is %d but the message has %d octets after the first 8 octets", h.Length, length-8)
+	}
+
+	if h.Flags&0x10 == 0 {
+		r.add(RuleHeader, 0, 0, "Header", "PT flag must be 1 in GTP")
+	}
+	if h.Flags&0x08 != 0 {
+		r.add(RuleSpareBits, 0, 0, "Header", "spare bit in the first octet is set")
+	}
+
+	switch h.Type {
+	case messages.MsgTypeTPDU, messages.MsgTypeEndMarker:
+	default:
+		if h.Flags&0x02 == 0 {
+			r.add(RuleHeader, 0, 0, "Header", "S flag must be 1 in %s", r.Name)
+		}
+	}
+}
+
+// checkIEV1 checks the length, the value range and the spare bits of the IE.
+//
+// Unlike GTPv2, most of the spare bits in GTPv1 IEs are set to 1.
+func checkIEV1(r *Report, ie *ies.IE, path string) {
+	typ, p := ie.Type, ie.Payload
+	if len(p) == 0 {
+		r.add(RuleLength, typ, 0, path, "length 0 is not allowed")
+		return
+	}
+	spareBits := func(octet int, mask, want uint8) {
+		if spare := p[octet] & mask; spare != want {
+			r.add(RuleSpareBits, typ, 0, path, "spare bits in the octet %d are %#x, must be %#x", octet+2, spare, want)
+		}
+	}
+
+	switch typ {
+	case ies.Cause:
+		if !known(v1.Cause(p[0]), "Cause") {
+			r.add(RuleValueRange, typ, 0, path, "Cause %d is unknown", p[0])
+		}
+	case ies.IMSI:
+		checkDigits(r, typ, 0, path, ie.IMSI(), 6, 15)
+	case ies.ReorderingRequired, ies.MSValidated, ies.TeardownInd:
+		spareBits(0, 0xfe, 0xfe)
+	case ies.SelectionMode:
+		spareBits(0, 0xfc, 0xfc)
+	case ies.NSAPI:
+		spareBits(0, 0xf0, 0x00)
+		if nsapi := p[0] & 0x0f; nsapi < 5 {
+			r.add(RuleValueRange, typ, 0, path, "NSAPI %d is reserved, must be 5 to 15", nsapi)
+		}
+	case ies.EndUserAddress:
+		if !checkMinLength(r, typ, 0, path, len(p), 2) {
+			return
+		}
+		spareBits(0, 0xf0, 0xf0)
+		if p[0] != v1.PDPTypeIETF {
+			return
+		}
+		switch p[1] {
+		case 0x21:
+			checkLength(r, typ, 0, path, len(p), 2, 6)
+		case 0x57:
+			checkLength(r, typ, 0, path, len(p), 2, 18)
+		case 0x8d:
+			checkLength(r, typ, 0, path, len(p), 2, 6, 18, 22)
+		}
+	case ies.AccessPointName:
+		checkAPN(r, typ, 0, path, p)
+	case ies.GSNAddress:
+		checkLength(r, typ, 0, path, len(p), 4, 16)
+	case ies.MSISDN:
+		checkDigits(r, typ, 0, path, ie.MSISDN(), 1, 15)
+	case ies.RATType:
+		if !checkLength(r, typ, 0, path, len(p), 1) {
+			return
+		}
+		if !known(v1.RATType(p[0]), "RATType") {
+			r.add(RuleValueRange, typ, 0, path, "RAT Type %d is unknown", p[0])
+		}
+	}
+}
+
+// mandatoryV1 returns the rules with the mandatory IEs given.
+func mandatoryV1(types ...uint8) *ieRules {
+	keys := make([]ieKey, len(types))
+	for i, t := range types {
+		keys[i] = ieKey{t, 0}
+	}
+	return &ieRules{mandatory: keys}
+}
+
+// rulesV1 is the presence rules of GTPv1 messages defined in TS 29.060.
+var rulesV1 = map[uint8]*ieRules{
+	messages.MsgTypeEchoResponse: mandatoryV1(ies.Recovery),
+	messages.MsgTypeCreatePDPContextRequest: mandatoryV1(
+		ies.TEIDDataI, ies.NSAPI, ies.GSNAddress, ies.GSNAddress, ies.QoSProfile,
+	),
+	messages.MsgTypeCreatePDPContextResponse: mandatoryV1(ies.Cause),
+	messages.MsgTypeUpdatePDPContextRequest:  mandatoryV1(ies.NSAPI),
+	messages.MsgTypeUpdatePDPContextResponse: mandatoryV1(ies.Cause),
+	messages.MsgTypeDeletePDPContextRequest:  mandatoryV1(ies.NSAPI),
+	messages.MsgTypeDeletePDPContextResponse: mandatoryV1(ies.Cause),
+	messages.MsgTypeErrorIndication:          mandatoryV1(ies.TEIDDataI, ies.GSNAddress),
+	messages.MsgTypePDUNotificationRequest: mandatoryV1(
+		ies.IMSI, ies.TEIDCPlane, ies.EndUserAddress, ies.AccessPointName, ies.GSNAddress,
+	),
+	messages.MsgTypePDUNotificationResponse: mandatoryV1(ies.Cause),
+	messages.MsgTypePDUNotificationRejectRequest: mandatoryV1(
+		ies.IMSI, ies.TEIDCPlane, ies.EndUserAddress, ies.AccessPointName,
+	),
+	messages.MsgTypePDUNotificationRejectResponse: mandatoryV1(ies.Cause),
+	messages.MsgTypeIdentificationRequest:         mandatoryV1(ies.RouteingAreaIdentity),
+	messages.MsgTypeIdentificationResponse:        mandatoryV1(ies.Cause),
+	messages.MsgTypeSGSNContextRequest: mandatoryV1(
+		ies.RouteingAreaIdentity, ies.TEIDCPlane, ies.GSNAddress,
+	),
+	messages.MsgTypeSGSNContextResponse:    mandatoryV1(ies.Cause),
+	messages.MsgTypeSGSNContextAcknowledge: mandatoryV1(ies.Cause),
+	messages.MsgTypeForwardRelocationRequest: mandatoryV1(
+		ies.IMSI, ies.TEIDCPlane, ies.RANAPCause, ies.MMContext, ies.GSNAddress,
+		ies.TargetIdentification, ies.UTRANTransparentContainer,
+	),
+	messages.MsgTypeForwardRelocationResponse:             mandatoryV1(ies.Cause),
+	messages.MsgTypeForwardRelocationCompleteAcknowledge:  mandatoryV1(ies.Cause),
+	messages.MsgTypeRelocationCancelResponse:              mandatoryV1(ies.Cause),
+	messages.MsgTypeMSInfoChangeNotificationRequest:       mandatoryV1(ies.RATType),
+	messages.MsgTypeMSInfoChangeNotificationResponse:      mandatoryV1(ies.Cause),
+	messages.MsgTypeSupportedExtensionHeadersNotification: mandatoryV1(ies.ExtensionHeaderTypeList),
+	messages.MsgTypeRANInformationRelay:                   mandatoryV1(ies.RANTransparentContainer),
+}
@@ -0,0 +1,477 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpvalidate
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func validateV2(b []byte) *Report {
+	r := &Report{Version: 2}
+	h, err := messages.DecodeHeader(b)
+	if err != nil {
+		r.Name = "Unknown"
+		r.add(RuleMalformed, 0, 0, "Header", "%s", err)
+		return r
+	}
+	r.MessageType = h.Type
+	r.Name = messages.MessageType(h.Type).String()
+
+	checkHeaderV2(r, h, len(b))
+
+	nodes, err := walkV2(r, h.Payload, "")
+	if err != nil {
+		r.add(RuleMalformed, 0, 0, "Message", "%s", err)
+	}
+
+	if rules, ok := rulesV2[h.Type]; ok {
+		checkPresence(r, rules, nodes, "", labelV2, true)
+	}
+	return r
+}
+
+func labelV2(k ieKey) string {
+	return fmt.Sprintf("%s(%d)", (&ies.IE{Type: k.typ}).Name(), k.instance)
+}
+
+func checkHeaderV2(r *Report, h *messages.Header, length int) {
+	if int(h.Length)+4 != length {
+		r.add(RuleHeader, 0, 0, "Header", "Length is %d but the message has %d octets after the first 4 octets", h.Length, length-4)
+	}
+
+	hasTEID := h.Flags&0x08 != 0
+	switch h.Type {
+	case messages.MsgTypeEchoRequest, messages.MsgTypeEchoResponse, messages.MsgTypeVersionNotSupportedIndication:
+		if hasTEID {
+			r.add(RuleHeader, 0, 0, "Header", "T flag must be 0 in %s", r.Name)
+		}
+	default:
+		if !hasTEID {
+			r.add(RuleHeader, 0, 0, "Header", "T flag must be 1 in %s", r.Name)
+		}
+	}
+
+	if h.Flags&0x03 != 0 {
+		r.add(RuleSpareBits, 0, 0, "Header", "spare bits in the first octet are %#x", h.Flags&0x03)
+	}
+
+	// the high nibble of the last octet is Message Priority if MP flag is set.
+	spare := h.Spare
+	if h.Flags&0x04 != 0 {
+		spare &= 0x0f
+	}
+	if spare != 0 {
+		r.add(RuleSpareBits, 0, 0, "Header", "spare bits in the last octet are %#x", spare)
+	}
+}
+
+// walkV2 checks the IEs in b one by one and returns them as nodes.
+//
+// This does not use ies.DecodeMultiIEs, to get the nodes before the malformed
+// IE and to see the spare bits that are dropped when decoding.
+func walkV2(r *Report, b []byte, parent string) ([]*node, error) {
+	var nodes []*node
+	for offset := 0; offset < len(b); {
+		if len(b[offset:]) < 4 {
+			return nodes, fmt.Errorf("%d octets left after the last IE at %s", len(b[offset:]), join(parent, "IE"))
+		}
+
+		l := int(binary.BigEndian.Uint16(b[offset+1 : offset+3]))
+		if len(b[offset:]) < 4+l {
+			ie := &ies.IE{Type: b[offset]}
+			return nodes, fmt.Errorf(
+				"%s needs %d octets but only %d left",
+				join(parent, fmt.Sprintf("%s(%d)", ie.Name(), b[offset+3]&0x0f)), 4+l, len(b[offset:]),
+			)
+		}
+
+		ie, err := ies.Decode(b[offset : offset+4+l])
+		if err != nil {
+			return nodes, err
+		}
+		n := &node{
+			key:  ieKey{ie.Type, ie.Instance()},
+			path: join(parent, fmt.Sprintf("%s(%d)", ie.Name(), ie.Instance())),
+		}
+		nodes = append(nodes, n)
+
+		if spare := b[offset+3] & 0xf0; spare != 0 {
+			r.add(RuleSpareBits, ie.Type, ie.Instance(), n.path, "spare bits in the octet 4 are %#x", spare)
+		}
+
+		if ie.IsGrouped() {
+			n.children, err = walkV2(r, ie.Payload, n.path)
+			if err != nil {
+				return nodes, err
+			}
+		} else {
+			checkIEV2(r, ie, n.path)
+		}
+
+		offset += 4 + l
+	}
+	return nodes, nil
+}
+
+// checkIEV2 checks the length, the value range and the spare bits of the IE.
+func checkIEV2(r *Report, ie *ies.IE, path string) {
+	typ, ins, p := ie.Type, ie.Instance(), ie.Payload
+	spareBits := func(octet int, mask uint8) {
+		if spare := p[octet] & mask; spare != 0 {
+			r.add(RuleSpareBits, typ, ins, path, "spare bits in the octet %d are %#x", octet+5, spare)
+		}
+	}
+
+	switch typ {
+	case ies.IMSI:
+		if checkMinLength(r, typ, ins, path, len(p), 1) {
+			checkDigits(r, typ, ins, path, ie.IMSI(), 6, 15)
+		}
+	case ies.Cause:
+		if !checkLength(r, typ, ins, path, len(p), 2, 6) {
+			return
+		}
+		if !known(v2.Cause(p[0]), "Cause") {
+			r.add(RuleValueRange, typ, ins, path, "Cause %d is unknown", p[0])
+		}
+		spareBits(1, 0xf8)
+	case ies.Recovery, ies.APNRestriction:
+		if !checkLength(r, typ, ins, path, len(p), 1) {
+			return
+		}
+		if typ == ies.APNRestriction && p[0] > v2.APNRestrictionPrivate2 {
+			r.add(RuleValueRange, typ, ins, path, "APN Restriction %d is unknown", p[0])
+		}
+	case ies.AccessPointName:
+		if checkMinLength(r, typ, ins, path, len(p), 1) {
+			checkAPN(r, typ, ins, path, p)
+		}
+	case ies.AggregateMaximumBitRate:
+		checkLength(r, typ, ins, path, len(p), 8)
+	case ies.EPSBearerID:
+		if !checkLength(r, typ, ins, path, len(p), 1) {
+			return
+		}
+		spareBits(0, 0xf0)
+		if ebi := p[0] & 0x0f; ebi < 5 {
+			r.add(RuleValueRange, typ, ins, path, "EBI %d is reserved, must be 5 to 15", ebi)
+		}
+	case ies.MobileEquipmentIdentity:
+		if checkMinLength(r, typ, ins, path, len(p), 1) {
+			checkDigits(r, typ, ins, path, ie.MobileEquipmentIdentity(), 14, 16)
+		}
+	case ies.MSISDN:
+		if checkMinLength(r, typ, ins, path, len(p), 1) {
+			checkDigits(r, typ, ins, path, ie.MSISDN(), 1, 15)
+		}
+	case ies.PDNAddressAllocation:
+		if !checkMinLength(r, typ, ins, path, len(p), 1) {
+			return
+		}
+		spareBits(0, 0xf8)
+		switch p[0] & 0x07 {
+		case v2.PDNTypeIPv4:
+			checkLength(r, typ, ins, path, len(p), 5)
+		case v2.PDNTypeIPv6:
+			checkLength(r, typ, ins, path, len(p), 18)
+		case v2.PDNTypeIPv4v6:
+			checkLength(r, typ, ins, path, len(p), 22)
+		case v2.PDNTypeNonIP:
+			checkLength(r, typ, ins, path, len(p), 1)
+		default:
+			r.add(RuleValueRange, typ, ins, path, "PDN Type %d is unknown", p[0]&0x07)
+		}
+	case ies.BearerQoS:
+		if !checkLength(r, typ, ins, path, len(p), 22) {
+			return
+		}
+		spareBits(0, 0x82)
+		if pl := (p[0] >> 2) & 0x0f; pl == 0 {
+			r.add(RuleValueRange, typ, ins, path, "Priority Level 0 is spare, must be 1 to 15")
+		}
+	case ies.RATType:
+		if !checkLength(r, typ, ins, path, len(p), 1) {
+			return
+		}
+		if !known(v2.RATType(p[0]), "RATType") {
+			r.add(RuleValueRange, typ, ins, path, "RAT Type %d is unknown", p[0])
+		}
+	case ies.ServingNetwork:
+		if checkLength(r, typ, ins, path, len(p), 3) {
+			checkDigits(r, typ, ins, path, ie.MCC(), 3, 3)
+			checkDigits(r, typ, ins, path, ie.MNC(), 2, 3)
+		}
+	case ies.FullyQualifiedTEID:
+		if !checkMinLength(r, typ, ins, path, len(p), 5) {
+			return
+		}
+		if ift := p[0] & 0x3f; !known(v2.IFType(ift), "IFType") {
+			r.add(RuleValueRange, typ, ins, path, "Interface Type %d is unknown", ift)
+		}
+
+		want := 5
+		hasV4, hasV6 := p[0]&0x80 != 0, p[0]&0x40 != 0
+		if hasV4 {
+			want += 4
+		}
+		if hasV6 {
+			want += 16
+		}
+		if !hasV4 && !hasV6 {
+			r.add(RuleValueRange, typ, ins, path, "neither V4 nor V6 flag is set")
+		}
+		checkLength(r, typ, ins, path, len(p), want)
+	case ies.ChargingID:
+		checkLength(r, typ, ins, path, len(p), 4)
+	case ies.PDNType:
+		if !checkLength(r, typ, ins, path, len(p), 1) {
+			return
+		}
+		spareBits(0, 0xf8)
+		if t := p[0] & 0x07; t < v2.PDNTypeIPv4 || t > v2.PDNTypeNonIP {
+			r.add(RuleValueRange, typ, ins, path, "PDN Type %d is unknown", t)
+		}
+	case ies.UETimeZone:
+		if checkLength(r, typ, ins, path, len(p), 2) {
+			spareBits(1, 0xfc)
+		}
+	case ies.SelectionMode:
+		if checkLength(r, typ, ins, path, len(p), 1) {
+			spareBits(0, 0xfc)
+		}
+	}
+}
+
+// upTo returns the instances from 0 to n.
+func upTo(n uint8) []uint8 {
+	ins := make([]uint8, n+1)
+	for i := range ins {
+		ins[i] = uint8(i)
+	}
+	return ins
+}
+
+// rulesV2 is the presence and instance rules of GTPv2 messages defined in TS 29.274.
+var rulesV2 = map[uint8]*ieRules{
+	messages.MsgTypeEchoRequest: {
+		mandatory: []ieKey{{ies.Recovery, 0}},
+	},
+	messages.MsgTypeEchoResponse: {
+		mandatory: []ieKey{{ies.Recovery, 0}},
+	},
+	messages.MsgTypeCreateSessionRequest: {
+		mandatory: []ieKey{{ies.RATType, 0}, {ies.FullyQualifiedTEID, 0}, {ies.AccessPointName, 0}, {ies.BearerContext, 0}},
+		instances: map[uint8][]uint8{
+			ies.UserLocationInformation:    upTo(1),
+			ies.FullyQualifiedTEID:         upTo(1),
+			ies.BearerContext:              upTo(1),
+			ies.FullyQualifiedCSID:         upTo(3),
+			ies.LocalDistinguishedName:     upTo(3),
+			ies.IPAddress:                  upTo(3),
+			ies.PortNumber:                 upTo(2),
+			ies.TWANIdentifier:             upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.BearerQoS, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(7)},
+			},
+			{ies.BearerContext, 1}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(1)},
+			},
+		},
+	},
+	messages.MsgTypeCreateSessionResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedTEID:         upTo(1),
+			ies.BearerContext:              upTo(1),
+			ies.FullyQualifiedCSID:         upTo(1),
+			ies.LocalDistinguishedName:     upTo(1),
+			ies.LoadControlInformation:     upTo(2),
+			ies.OverloadControlInformation: upTo(1),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(6)},
+			},
+			{ies.BearerContext, 1}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+			},
+		},
+	},
+	messages.MsgTypeModifyBearerRequest: {
+		instances: map[uint8][]uint8{
+			ies.UserLocationInformation:    upTo(1),
+			ies.BearerContext:              upTo(1),
+			ies.FullyQualifiedCSID:         upTo(1),
+			ies.IPAddress:                  upTo(1),
+			ies.PortNumber:                 upTo(2),
+			ies.LocalDistinguishedName:     upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(4)},
+			},
+			{ies.BearerContext, 1}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}},
+			},
+		},
+	},
+	messages.MsgTypeModifyBearerResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.BearerContext:              upTo(1),
+			ies.FullyQualifiedCSID:         upTo(1),
+			ies.LocalDistinguishedName:     upTo(1),
+			ies.LoadControlInformation:     upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(3)},
+			},
+			{ies.BearerContext, 1}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+			},
+		},
+	},
+	messages.MsgTypeDeleteSessionRequest: {
+		instances: map[uint8][]uint8{
+			ies.TWANIdentifier:             upTo(1),
+			ies.TWANIdentifierTimestamp:    upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+			ies.PortNumber:                 upTo(1),
+		},
+	},
+	messages.MsgTypeDeleteSessionResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.LoadControlInformation:     upTo(2),
+			ies.OverloadControlInformation: upTo(2),
+		},
+	},
+	messages.MsgTypeCreateBearerRequest: {
+		mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.BearerContext, 0}},
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedCSID:         upTo(1),
+			ies.LoadControlInformation:     upTo(2),
+			ies.OverloadControlInformation: upTo(1),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.BearerTFT, 0}, {ies.BearerQoS, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(5)},
+			},
+		},
+	},
+	messages.MsgTypeCreateBearerResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}, {ies.BearerContext, 0}},
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedCSID:         upTo(3),
+			ies.TWANIdentifier:             upTo(1),
+			ies.TWANIdentifierTimestamp:    upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+			ies.IPAddress:                  upTo(1),
+			ies.PortNumber:                 upTo(1),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(7)},
+			},
+		},
+	},
+	messages.MsgTypeDeleteBearerRequest: {
+		instances: map[uint8][]uint8{
+			ies.EPSBearerID:                upTo(1),
+			ies.FullyQualifiedCSID:         upTo(1),
+			ies.LoadControlInformation:     upTo(2),
+			ies.OverloadControlInformation: upTo(1),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+			},
+		},
+	},
+	messages.MsgTypeDeleteBearerResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedCSID:         upTo(3),
+			ies.TWANIdentifier:             upTo(1),
+			ies.TWANIdentifierTimestamp:    upTo(1),
+			ies.OverloadControlInformation: upTo(2),
+			ies.IPAddress:                  upTo(1),
+			ies.PortNumber:                 upTo(1),
+		},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+			},
+		},
+	},
+	messages.MsgTypeContextRequest: {
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedDomainName: upTo(1),
+			ies.NodeIdentifier:           upTo(1),
+		},
+	},
+	messages.MsgTypeContextResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.FullyQualifiedTEID:       upTo(1),
+			ies.FullyQualifiedDomainName: upTo(2),
+			ies.IPAddress:                upTo(1),
+			ies.RFSPIndex:                upTo(1),
+			ies.IntegerNumber:            upTo(1),
+		},
+	},
+	messages.MsgTypeContextAcknowledge: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		instances: map[uint8][]uint8{
+			ies.NodeNumber:     upTo(1),
+			ies.NodeIdentifier: upTo(1),
+		},
+	},
+	messages.MsgTypeCreateIndirectDataForwardingTunnelRequest: {
+		mandatory: []ieKey{{ies.BearerContext, 0}},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(5)},
+			},
+		},
+	},
+	messages.MsgTypeCreateIndirectDataForwardingTunnelResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+		children: map[ieKey]*ieRules{
+			{ies.BearerContext, 0}: {
+				mandatory: []ieKey{{ies.EPSBearerID, 0}, {ies.Cause, 0}},
+				instances: map[uint8][]uint8{ies.FullyQualifiedTEID: upTo(7)},
+			},
+		},
+	},
+	messages.MsgTypeDeleteIndirectDataForwardingTunnelResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+	},
+	messages.MsgTypeReleaseAccessBearersResponse: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+	},
+	messages.MsgTypeDownlinkDataNotificationAcknowledge: {
+		mandatory: []ieKey{{ies.Cause, 0}},
+	},
+}
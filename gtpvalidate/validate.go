@@ -0,0 +1,208 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpvalidate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Message is a GTPv1 or GTPv2 message to be validated.
+//
+// Both v1/messages.Message and v2/messages.Message satisfy this interface.
+type Message interface {
+	Version() int
+	MessageType() uint8
+	MessageTypeName() string
+	Len() int
+	SerializeTo([]byte) error
+}
+
+// Validate checks the message against the rules and returns the Report.
+//
+// The message is serialized before being checked, so that the rules are applied
+// to what is actually sent on the wire.
+func Validate(msg Message) *Report {
+	b := make([]byte, msg.Len())
+	if err := msg.SerializeTo(b); err != nil {
+		r := &Report{Version: msg.Version(), MessageType: msg.MessageType(), Name: msg.MessageTypeName()}
+		r.add(RuleMalformed, 0, 0, "Message", "failed to serialize: %s", err)
+		return r
+	}
+	return ValidateBytes(b)
+}
+
+// ValidateBytes checks the message in b against the rules and returns the Report.
+//
+// Unlike Validate, this works with the messages that cannot be decoded, which
+// are reported as RuleMalformed violation together with the other violations
+// found in the part that can be decoded.
+func ValidateBytes(b []byte) *Report {
+	if len(b) < 1 {
+		r := &Report{Name: "Unknown"}
+		r.add(RuleMalformed, 0, 0, "Message", "empty message")
+		return r
+	}
+
+	switch v := int(b[0] >> 5); v {
+	case 1:
+		return validateV1(b)
+	case 2:
+		return validateV2(b)
+	default:
+		r := &Report{Version: v, Name: "Unknown"}
+		r.add(RuleHeader, 0, 0, "Header", "version %d is not supported", v)
+		return r
+	}
+}
+
+// ieKey identifies an IE in a message with its type and instance.
+type ieKey struct {
+	typ, instance uint8
+}
+
+// ieRules is the rules of the IEs in a message or a grouped IE.
+type ieRules struct {
+	// mandatory is the IEs that must be present. The same key appears as many
+	// times as the IE is required.
+	mandatory []ieKey
+
+	// instances is the instances allowed for each IE type. The types not listed
+	// here are allowed only with the instance 0.
+	instances map[uint8][]uint8
+
+	// children is the rules of the IEs in the grouped IEs.
+	children map[ieKey]*ieRules
+}
+
+// node is an IE found in the message, in the version independent form.
+type node struct {
+	key      ieKey
+	path     string
+	children []*node
+}
+
+// checkPresence checks the mandatory IEs and the instances of the IEs with the
+// rules given, recursively into the grouped IEs.
+//
+// The label returns the name of the IE used in the path. The instances are not
+// checked if checkInstance is false.
+func checkPresence(r *Report, rules *ieRules, nodes []*node, parent string, label func(ieKey) string, checkInstance bool) {
+	found := map[ieKey]int{}
+	for _, n := range nodes {
+		found[n.key]++
+	}
+	required := map[ieKey]int{}
+	for _, k := range rules.mandatory {
+		required[k]++
+	}
+
+	checked := map[ieKey]bool{}
+	for _, k := range rules.mandatory {
+		if checked[k] {
+			continue
+		}
+		checked[k] = true
+
+		switch {
+		case found[k] == 0:
+			r.add(RuleMandatoryIE, k.typ, k.instance, join(parent, label(k)), "missing")
+		case found[k] < required[k]:
+			r.add(RuleMandatoryIE, k.typ, k.instance, join(parent, label(k)),
+				"%d required but %d found", required[k], found[k])
+		}
+	}
+
+	for _, n := range nodes {
+		if checkInstance {
+			allowed, ok := rules.instances[n.key.typ]
+			if !ok {
+				allowed = []uint8{0}
+			}
+			if !contains(allowed, n.key.instance) {
+				r.add(RuleInstance, n.key.typ, n.key.instance, n.path, "instance %d is not allowed here", n.key.instance)
+			}
+		}
+
+		if child, ok := rules.children[n.key]; ok {
+			checkPresence(r, child, n.children, n.path, label, checkInstance)
+		}
+	}
+}
+
+func contains(vals []uint8, v uint8) bool {
+	for _, val := range vals {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
+
+func join(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// checkLength reports the violation if the length is not any of the ones given.
+func checkLength(r *Report, typ, instance uint8, path string, got int, want ...int) bool {
+	for _, w := range want {
+		if got == w {
+			return true
+		}
+	}
+
+	ws := make([]string, len(want))
+	for i, w := range want {
+		ws[i] = fmt.Sprint(w)
+	}
+	r.add(RuleLength, typ, instance, path, "length %d is not allowed, must be %s", got, strings.Join(ws, " or "))
+	return false
+}
+
+// checkMinLength reports the violation if the length is shorter than min.
+func checkMinLength(r *Report, typ, instance uint8, path string, got, min int) bool {
+	if got < min {
+		r.add(RuleLength, typ, instance, path, "length %d is too short, must be %d or more", got, min)
+		return false
+	}
+	return true
+}
+
+// checkDigits reports the violation if s is not the digits with the length in the range.
+func checkDigits(r *Report, typ, instance uint8, path, s string, min, max int) {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			r.add(RuleValueRange, typ, instance, path, "%q contains non-digit character", s)
+			return
+		}
+	}
+	if len(s) < min || len(s) > max {
+		r.add(RuleValueRange, typ, instance, path, "%q has %d digits, must be %d to %d", s, len(s), min, max)
+	}
+}
+
+// checkAPN reports the violation if the APN in b is not the valid sequence of labels.
+func checkAPN(r *Report, typ, instance uint8, path string, b []byte) {
+	if len(b) > 100 {
+		r.add(RuleLength, typ, instance, path, "length %d is too long, must be 100 or less", len(b))
+	}
+	for offset := 0; offset < len(b); {
+		l := int(b[offset])
+		if l == 0 || offset+1+l > len(b) {
+			r.add(RuleValueRange, typ, instance, path, "label at octet %d has invalid length %d", offset, l)
+			return
+		}
+		offset += 1 + l
+	}
+}
+
+// known reports whether the name given by the generated String() is the known one,
+// which is not in the "Type(N)" format.
+func known(s fmt.Stringer, typeName string) bool {
+	return !strings.HasPrefix(s.String(), typeName+"(")
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpvalidate_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/gtptest"
+	"github.com/wmnsk/go-gtp/gtpvalidate"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+type violation struct {
+	rule gtpvalidate.Rule
+	path string
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		description string
+		msg         gtpvalidate.Message
+		want        []violation
+	}{
+		{
+			"v2/EchoRequest",
+			v2msg.NewEchoRequest(1, v2ies.NewRecovery(0x80)),
+			nil,
+		}, {
+			"v2/CreateSessionRequest",
+			gtptest.NewCreateSessionRequest(
+				0, 1,
+				v2ies.NewAccessPointName("some.apn.example"),
+				v2ies.NewBearerContext(
+					v2ies.NewEPSBearerID(5),
+					v2ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
+				),
+			),
+			nil,
+		}, {
+			"v2/CreateSessionResponse",
+			v2msg.NewCreateSessionResponse(
+				0xffffffff, 1,
+				v2ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				v2ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0xffffffff, "1.1.1.3", ""),
+				v2ies.NewBearerContext(
+					v2ies.NewEPSBearerID(5),
+					v2ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+					v2ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0xffffffff, "1.1.1.4", ""),
+				),
+			),
+			nil,
+		}, {
+			"v2/MissingMandatoryIE",
+			gtptest.NewCreateSessionRequest(
+				0, 1,
+				v2ies.NewBearerContext(v2ies.NewEPSBearerID(5)),
+			),
+			[]violation{
+				{gtpvalidate.RuleMandatoryIE, "AccessPointName(0)"},
+				{gtpvalidate.RuleMandatoryIE, "BearerContext(0)/BearerQoS(0)"},
+			},
+		}, {
+			"v2/BadValue",
+			v2msg.NewDeleteSessionResponse(
+				0xffffffff, 1,
+				v2ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				v2ies.NewEPSBearerID(3).WithInstance(1),
+			),
+			[]violation{
+				{gtpvalidate.RuleValueRange, "EPSBearerID(1)"},
+				{gtpvalidate.RuleInstance, "EPSBearerID(1)"},
+			},
+		}, {
+			"v1/EchoResponse",
+			v1msg.NewEchoResponse(0, v1ies.NewRecovery(0x80)),
+			nil,
+		}, {
+			"v1/DeletePDPContextRequest",
+			v1msg.NewDeletePDPContextRequest(
+				0xffffffff, 1,
+				v1ies.NewTeardownInd(true),
+			),
+			[]violation{
+				{gtpvalidate.RuleMandatoryIE, "NSAPI"},
+			},
+		}, {
+			"v1/DeletePDPContextResponse",
+			v1msg.NewDeletePDPContextResponse(
+				0xffffffff, 1,
+				v1ies.NewCause(v1.ResCauseRequestAccepted),
+			),
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gtpvalidate.Validate(c.msg)
+			compare(t, r, c.want)
+		})
+	}
+}
+
+func TestValidateBytes(t *testing.T) {
+	cases := []struct {
+		description string
+		serialized  []byte
+		want        []violation
+	}{
+		{
+			"v2/TFlagInEcho",
+			[]byte{
+				0x48, 0x01, 0x00, 0x0d, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x01, 0x00,
+				0x03, 0x00, 0x01, 0x00, 0x80,
+			},
+			[]violation{
+				{gtpvalidate.RuleHeader, "Header"},
+			},
+		}, {
+			"v2/SpareBits",
+			[]byte{
+				0x43, 0x01, 0x00, 0x09, 0x00, 0x00, 0x01, 0x01,
+				0x03, 0x00, 0x01, 0x10, 0x80,
+			},
+			[]violation{
+				{gtpvalidate.RuleSpareBits, "Header"},
+				{gtpvalidate.RuleSpareBits, "Header"},
+				{gtpvalidate.RuleSpareBits, "Recovery(0)"},
+			},
+		}, {
+			"v2/TooShortIE",
+			[]byte{
+				0x40, 0x01, 0x00, 0x09, 0x00, 0x00, 0x01, 0x00,
+				0x03, 0x00, 0x02, 0x00, 0x80,
+			},
+			[]violation{
+				{gtpvalidate.RuleMalformed, "Message"},
+				{gtpvalidate.RuleMandatoryIE, "Recovery(0)"},
+			},
+		}, {
+			"v1/NoSFlag",
+			[]byte{
+				0x30, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00,
+				0x0e, 0x80,
+			},
+			[]violation{
+				{gtpvalidate.RuleHeader, "Header"},
+			},
+		}, {
+			"UnknownVersion",
+			[]byte{0x60, 0x01, 0x00, 0x00},
+			[]violation{
+				{gtpvalidate.RuleHeader, "Header"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := gtpvalidate.ValidateBytes(c.serialized)
+			compare(t, r, c.want)
+		})
+	}
+}
+
+func compare(t *testing.T, r *gtpvalidate.Report, want []violation) {
+	t.Helper()
+
+	if len(r.Violations) != len(want) {
+		t.Fatalf("got %d violations, want %d:\n%s", len(r.Violations), len(want), r)
+	}
+	for i, v := range r.Violations {
+		if v.Rule != want[i].rule || v.Path != want[i].path {
+			t.Errorf("violation %d: got %s at %s, want %s at %s", i, v.Rule, v.Path, want[i].rule, want[i].path)
+		}
+	}
+
+	if (len(want) == 0) != r.OK() || (r.Err() == nil) != r.OK() {
+		t.Errorf("OK and Err are inconsistent: %v, %v", r.OK(), r.Err())
+	}
+}
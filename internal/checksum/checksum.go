@@ -0,0 +1,36 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package checksum provides the Internet checksum defined in RFC 1071, for the IP,
+// UDP and ICMP packets built by the packages in this module.
+package checksum
+
+import "encoding/binary"
+
+// Sum calculates the Internet checksum of b, with the checksum field in it set to zero.
+// initial is the sum of the pseudo header returned by PseudoHeader, or 0 if none.
+func Sum(b []byte, initial uint32) uint16 {
+	sum := initial
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
+
+// PseudoHeader returns the sum of the pseudo header of the upper-layer protocol proto
+// with the length given, to be given to Sum. addrs is the source and destination
+// addresses in the IP header, i.e., 8 bytes for IPv4 or 32 bytes for IPv6.
+func PseudoHeader(addrs []byte, proto uint8, length int) uint32 {
+	var sum uint32
+	for i := 0; i+1 < len(addrs); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(addrs[i : i+2]))
+	}
+	return sum + uint32(proto) + uint32(length)
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package checksum_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+)
+
+func TestSum(t *testing.T) {
+	// the example IPv4 header with the checksum 0xb861.
+	ip := []byte{
+		0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11,
+		0x00, 0x00, 0xc0, 0xa8, 0x00, 0x01, 0xc0, 0xa8, 0x00, 0xc7,
+	}
+	if got := checksum.Sum(ip, 0); got != 0xb861 {
+		t.Errorf("got %#x, want %#x", got, 0xb861)
+	}
+
+	// the sum over the header with the checksum set is zero.
+	binary.BigEndian.PutUint16(ip[10:12], 0xb861)
+	if got := checksum.Sum(ip, 0); got != 0 {
+		t.Errorf("got %#x, want 0", got)
+	}
+
+	// the odd length is padded with zero.
+	if got, want := checksum.Sum([]byte{0x01, 0x02, 0x03}, 0), checksum.Sum([]byte{0x01, 0x02, 0x03, 0x00}, 0); got != want {
+		t.Errorf("got %#x, want %#x", got, want)
+	}
+}
+
+func TestPseudoHeader(t *testing.T) {
+	addrs := []byte{0xc0, 0xa8, 0x00, 0x01, 0xc0, 0xa8, 0x00, 0xc7}
+	if got, want := checksum.PseudoHeader(addrs, 17, 8), uint32(0xc0a8+0x0001+0xc0a8+0x00c7+17+8); got != want {
+		t.Errorf("got %#x, want %#x", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command genconst generates String(), MarshalText(), UnmarshalText() and
+// <Type>FromString() for the named type corresponding to the uint8 constants.
+//
+// The constants are the ones in the const blocks that have the doc comment
+// given with -block in the file given with -file. The names are the constant
+// names without the prefixes given with -trim.
+//
+// It is expected to be used with go:generate like below;
+//
+//	//go:generate go run ../internal/genconst -file $GOFILE -type Cause -block "Cause definitions." -trim Cause
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	file     = flag.String("file", "constants.go", "file that contains the constants.")
+	typeName = flag.String("type", "", "name of the type to generate methods for.")
+	block    = flag.String("block", "", "doc comment of the const blocks to take the constants from.")
+	trim     = flag.String("trim", "", "comma-separated prefixes to trim from the constant names.")
+	output   = flag.String("output", "", "output file name. default is <type>_string.go in lower case.")
+)
+
+type constant8 struct {
+	name  string
+	value uint64
+}
+
+func main() {
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("genconst: ")
+
+	if *typeName == "" || *block == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_string.go"
+	}
+
+	pkg, consts, err := parseConstants(*file, *block)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(consts) == 0 {
+		log.Fatalf("no constants found in the blocks with %q in %s", *block, *file)
+	}
+
+	var prefixes []string
+	if *trim != "" {
+		prefixes = strings.Split(*trim, ",")
+	}
+
+	src, err := generate(pkg, consts, prefixes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseConstants returns the package name and the constants declared in the
+// const blocks with the doc comment given, in the order of declaration.
+func parseConstants(path, doc string) (string, []*constant8, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// type-check only the const blocks to evaluate iota and the expressions,
+	// as the rest of the file may depend on the other packages.
+	blocks := &ast.File{Name: f.Name}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST || gd.Doc == nil {
+			continue
+		}
+		if strings.SplitN(gd.Doc.Text(), "\n", 2)[0] != doc {
+			continue
+		}
+		blocks.Decls = append(blocks.Decls, gd)
+	}
+
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	if _, err := (&types.Config{}).Check(f.Name.Name, fset, []*ast.File{blocks}, info); err != nil {
+		return "", nil, err
+	}
+
+	var consts []*constant8
+	for _, decl := range blocks.Decls {
+		for _, spec := range decl.(*ast.GenDecl).Specs {
+			for _, id := range spec.(*ast.ValueSpec).Names {
+				if id.Name == "_" {
+					continue
+				}
+				c, ok := info.Defs[id].(*types.Const)
+				if !ok {
+					continue
+				}
+				v, ok := constant.Uint64Val(c.Val())
+				if !ok || v > 0xff {
+					return "", nil, fmt.Errorf("%s is not a uint8 value", id.Name)
+				}
+				consts = append(consts, &constant8{name: id.Name, value: v})
+			}
+		}
+	}
+	return f.Name.Name, consts, nil
+}
+
+func generate(pkg string, consts []*constant8, prefixes []string) ([]byte, error) {
+	t := *typeName
+	namesVar := "names" + t
+	valuesVar := "values" + t
+
+	buf := &bytes.Buffer{}
+	args := make([]string, len(os.Args)-1)
+	for i, arg := range os.Args[1:] {
+		args[i] = arg
+		if strings.ContainsAny(arg, " \"") {
+			args[i] = strconv.Quote(arg)
+		}
+	}
+	fmt.Fprintf(buf, "// Code generated by %s; DO NOT EDIT.\n\n", strconv.Quote("genconst "+strings.Join(args, " ")))
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+	fmt.Fprintf(buf, "import (\n\"fmt\"\n\"strconv\"\n\"strings\"\n)\n\n")
+
+	// the first constant wins if the value is defined more than once.
+	seen := map[uint64]bool{}
+	fmt.Fprintf(buf, "var %s = map[%s]string{\n", namesVar, t)
+	for _, c := range consts {
+		if seen[c.value] {
+			continue
+		}
+		seen[c.value] = true
+		fmt.Fprintf(buf, "%s(%s): %q,\n", t, c.name, trimPrefixes(c.name, prefixes))
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	sorted := make([]*constant8, len(consts))
+	copy(sorted, consts)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	fmt.Fprintf(buf, "var %s = map[string]%s{\n", valuesVar, t)
+	seenName := map[string]string{}
+	for _, c := range sorted {
+		name := trimPrefixes(c.name, prefixes)
+		if dup, ok := seenName[name]; ok {
+			return nil, fmt.Errorf("%s and %s have the same name %q after trimming", dup, c.name, name)
+		}
+		seenName[name] = c.name
+		fmt.Fprintf(buf, "%q: %s(%s),\n", name, t, c.name)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+
+	fmt.Fprintf(buf, `// String returns the name of the %[1]s, or "%[1]s(N)" if the value is unknown.
+func (v %[1]s) String() string {
+	if name, ok := %[2]s[v]; ok {
+		return name
+	}
+	return "%[1]s(" + strconv.Itoa(int(v)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler to encode the %[1]s with its name.
+func (v %[1]s) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler to decode the %[1]s from its name.
+func (v *%[1]s) UnmarshalText(b []byte) error {
+	parsed, err := %[1]sFromString(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// %[1]sFromString returns the %[1]s whose name is s.
+//
+// s can also be in the "%[1]s(N)" format that String() returns for the unknown values.
+func %[1]sFromString(s string) (%[1]s, error) {
+	if v, ok := %[3]s[s]; ok {
+		return v, nil
+	}
+
+	if strings.HasPrefix(s, "%[1]s(") && strings.HasSuffix(s, ")") {
+		n, err := strconv.ParseUint(s[len("%[1]s("):len(s)-1], 10, 8)
+		if err == nil {
+			return %[1]s(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %[1]s: %%s", s)
+}
+`, t, namesVar, valuesVar)
+
+	return format.Source(buf.Bytes())
+}
+
+func trimPrefixes(name string, prefixes []string) string {
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p) && len(name) > len(p) {
+			return name[len(p):]
+		}
+	}
+	return name
+}
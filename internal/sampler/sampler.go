@@ -0,0 +1,50 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sampler provides the rate limiting of the events to be reported, e.g.,
+// the packets that cannot be decoded, not to let a flood of them overwhelm the logs.
+package sampler
+
+import (
+	"sync"
+	"time"
+)
+
+// Sampler lets the first burst events in each interval pass, and counts the others
+// as suppressed.
+type Sampler struct {
+	mu       sync.Mutex
+	burst    int
+	interval time.Duration
+
+	start      time.Time
+	passed     int
+	suppressed uint64
+}
+
+// New creates a new Sampler that lets burst events pass in each interval.
+func New(burst int, interval time.Duration) *Sampler {
+	return &Sampler{burst: burst, interval: interval}
+}
+
+// Allow reports whether the event at now passes. If it passes, the number of the
+// events suppressed since the last one passed is also returned.
+func (s *Sampler) Allow(now time.Time) (bool, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.start) >= s.interval {
+		s.start = now
+		s.passed = 0
+	}
+	if s.passed >= s.burst {
+		s.suppressed++
+		return false, 0
+	}
+
+	s.passed++
+	suppressed := s.suppressed
+	s.suppressed = 0
+	return true, suppressed
+}
@@ -0,0 +1,32 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sampler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/sampler"
+)
+
+func TestSampler(t *testing.T) {
+	s := sampler.New(2, time.Second)
+	now := time.Now()
+
+	for i, want := range []bool{true, true, false, false, false} {
+		if ok, _ := s.Allow(now); ok != want {
+			t.Errorf("event %d: got %v, want %v", i, ok, want)
+		}
+	}
+
+	// the suppressed events are told with the first one in the next interval.
+	ok, suppressed := s.Allow(now.Add(time.Second))
+	if !ok || suppressed != 3 {
+		t.Errorf("got %v, %d suppressed, want true, 3", ok, suppressed)
+	}
+	if ok, suppressed := s.Allow(now.Add(time.Second)); !ok || suppressed != 0 {
+		t.Errorf("got %v, %d suppressed, want true, 0", ok, suppressed)
+	}
+}
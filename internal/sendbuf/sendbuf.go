@@ -0,0 +1,113 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sendbuf provides the buffers to serialize the messages into before writing
+// them to the peers, which are reused for all the messages sent to the same peer
+// instead of allocating a new one for each message.
+package sendbuf
+
+import (
+	"container/list"
+	"net"
+	"net/netip"
+	"sync"
+)
+
+// DefaultMaxPeers is the default number of the peers to keep the buffers for.
+const DefaultMaxPeers = 1024
+
+// Buffer is the buffer to serialize the messages to a peer into. It should be locked
+// while the bytes returned by Bytes are in use.
+type Buffer struct {
+	sync.Mutex
+	b []byte
+}
+
+// Bytes returns the buffer of length l, growing it if it is not large enough.
+func (b *Buffer) Bytes(l int) []byte {
+	if cap(b.b) < l {
+		b.b = make([]byte, l)
+	}
+	return b.b[:l]
+}
+
+// Key identifies a peer, without allocating for the UDP addresses.
+type Key struct {
+	addrPort netip.AddrPort
+	str      string
+}
+
+// KeyOf returns the Key of the peer at addr.
+func KeyOf(addr net.Addr) Key {
+	if u, ok := addr.(*net.UDPAddr); ok {
+		return Key{addrPort: u.AddrPort()}
+	}
+	return Key{str: addr.String()}
+}
+
+type entry struct {
+	key Key
+	buf *Buffer
+}
+
+// Buffers holds a Buffer for each peer. The Buffer of the peer least recently sent
+// to is discarded when the number of the peers exceeds MaxPeers, not to keep the
+// ones of the peers that are gone, e.g., the ones on the ephemeral ports.
+//
+// The zero value is ready to use.
+type Buffers struct {
+	mu   sync.Mutex
+	bufs map[Key]*list.Element
+	lru  list.List
+
+	// MaxPeers is the number of the peers to keep the buffers for. If 0,
+	// DefaultMaxPeers is used.
+	MaxPeers int
+}
+
+// Get returns the Buffer for the peer, creating it if not exists.
+func (s *Buffers) Get(addr net.Addr) *Buffer {
+	key := KeyOf(addr)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.bufs[key]; ok {
+		s.lru.MoveToFront(e)
+		return e.Value.(*entry).buf
+	}
+
+	if s.bufs == nil {
+		s.bufs = map[Key]*list.Element{}
+	}
+	max := s.MaxPeers
+	if max <= 0 {
+		max = DefaultMaxPeers
+	}
+	for len(s.bufs) >= max {
+		// the one in use keeps working, as it is just not reused anymore.
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.bufs, oldest.Value.(*entry).key)
+	}
+
+	buf := &Buffer{}
+	s.bufs[key] = s.lru.PushFront(&entry{key, buf})
+	return buf
+}
+
+// Len returns the number of the peers the buffers are kept for.
+func (s *Buffers) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bufs)
+}
+
+// Reset discards all the buffers.
+func (s *Buffers) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bufs = nil
+	s.lru.Init()
+}
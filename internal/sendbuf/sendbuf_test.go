@@ -0,0 +1,53 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sendbuf_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
+)
+
+func TestBuffers(t *testing.T) {
+	s := &sendbuf.Buffers{MaxPeers: 2}
+	peer := func(port int) net.Addr {
+		return &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: port}
+	}
+
+	a, b := s.Get(peer(1)), s.Get(peer(2))
+	if s.Get(peer(1)) != a {
+		t.Error("buffer not reused for the same peer")
+	}
+
+	// peer(2) is the least recently used one.
+	s.Get(peer(3))
+	if got := s.Len(); got != 2 {
+		t.Errorf("got %d buffers, want 2", got)
+	}
+	if s.Get(peer(1)) != a {
+		t.Error("buffer of the recently used peer discarded")
+	}
+	if s.Get(peer(2)) == b {
+		t.Error("buffer of the least recently used peer kept")
+	}
+
+	s.Reset()
+	if got := s.Len(); got != 0 {
+		t.Errorf("got %d buffers after Reset, want 0", got)
+	}
+}
+
+func TestBufferBytes(t *testing.T) {
+	buf := &sendbuf.Buffer{}
+	b := buf.Bytes(10)
+	if len(b) != 10 {
+		t.Fatalf("got %d, want 10", len(b))
+	}
+	b[0] = 1
+	if got := buf.Bytes(5); len(got) != 5 || got[0] != 1 {
+		t.Error("buffer not reused for the shorter one")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// Allocator allocates the addresses to the UEs, typically for the PDN Address
+// Allocation in Create Session Response.
+//
+// IPAM implements it with the static pools, and DHCPAllocator with the external
+// DHCP servers.
+type Allocator interface {
+	Allocate(imsi, apn string, typ AddressType) (*Lease, error)
+	Release(imsi, apn string) error
+	Lookup(imsi, apn string) (*Lease, error)
+}
+
+// DeferredAllocator is the Allocator that can defer the allocation of the IPv4
+// address until the UE requests it with DHCPv4 after the PDN connection is
+// established, which is requested by the UE in the PCO.
+type DeferredAllocator interface {
+	Allocator
+	// AllocateDeferred is the same as Allocate but leaves the IPv4 address
+	// unspecified(0.0.0.0) in the lease.
+	AllocateDeferred(imsi, apn string, typ AddressType) (*Lease, error)
+	// Bind sets the IPv4 address assigned to the UE with DHCPv4 afterwards.
+	Bind(imsi, apn string, addr netip.Addr) (*Lease, error)
+}
+
+var (
+	_ Allocator         = (*IPAM)(nil)
+	_ DeferredAllocator = (*DHCPAllocator)(nil)
+)
+
+// DHCPClient requests the addresses of the UEs to the external DHCP servers.
+//
+// The methods should return ErrTypeNotSupported if no server is available for
+// the type of the address for the APN.
+type DHCPClient interface {
+	// RequestIPv4 requests the IPv4 address to the DHCPv4 server.
+	RequestIPv4(imsi, apn string) (netip.Addr, error)
+	// RequestIPv6 requests the IPv6 prefix delegated with DHCPv6-PD.
+	RequestIPv6(imsi, apn string) (netip.Prefix, error)
+	// Release releases the addresses in the lease to the servers.
+	Release(l *Lease) error
+}
+
+// DHCPAllocator allocates the addresses to the UEs with the DHCPClient, for the
+// operators who don't assign the addresses from the static pools.
+//
+// The leases are kept only in memory, as the DHCP servers keep them.
+type DHCPAllocator struct {
+	client DHCPClient
+
+	mu     sync.Mutex
+	leases map[leaseKey]*Lease
+}
+
+// NewDHCPAllocator creates a new DHCPAllocator with the DHCPClient given.
+func NewDHCPAllocator(client DHCPClient) *DHCPAllocator {
+	return &DHCPAllocator{
+		client: client,
+		leases: map[leaseKey]*Lease{},
+	}
+}
+
+// Allocate requests the addresses of the AddressType for the APN to the DHCP
+// servers, and returns the copy of the lease.
+//
+// As IPAM.Allocate, the same addresses are returned while the UE has the lease
+// for the APN, and only the type the servers support is assigned for IPv4v6.
+func (a *DHCPAllocator) Allocate(imsi, apn string, typ AddressType) (*Lease, error) {
+	return a.allocate(imsi, apn, typ, false)
+}
+
+// AllocateDeferred is the same as Allocate but does not request the IPv4 address,
+// and the lease has the unspecified address(0.0.0.0) to be given in the PDN Address
+// Allocation instead. Bind should be called with the address the UE gets with
+// DHCPv4 later.
+func (a *DHCPAllocator) AllocateDeferred(imsi, apn string, typ AddressType) (*Lease, error) {
+	return a.allocate(imsi, apn, typ, true)
+}
+
+func (a *DHCPAllocator) allocate(imsi, apn string, typ AddressType, deferV4 bool) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := leaseKey{imsi, apn}
+	l := &Lease{IMSI: imsi, APN: apn}
+	if old, ok := a.leases[key]; ok {
+		l = old
+	}
+	c := *l
+
+	if typ&IPv4 != 0 && !c.IPv4.IsValid() {
+		if deferV4 {
+			c.IPv4 = netip.IPv4Unspecified()
+		} else {
+			addr, err := a.client.RequestIPv4(imsi, apn)
+			switch {
+			case err == nil:
+				c.IPv4 = addr
+			case errors.Is(err, ErrTypeNotSupported):
+				typ &^= IPv4
+			default:
+				return nil, fmt.Errorf("failed to request IPv4 address: %w", err)
+			}
+		}
+	}
+	if typ&IPv6 != 0 && !c.IPv6.IsValid() {
+		prefix, err := a.client.RequestIPv6(imsi, apn)
+		switch {
+		case err == nil:
+			c.IPv6 = prefix
+		case errors.Is(err, ErrTypeNotSupported):
+			typ &^= IPv6
+		default:
+			return nil, fmt.Errorf("failed to request IPv6 prefix: %w", err)
+		}
+	}
+	if typ&IPv4v6 == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotSupported, apn)
+	}
+
+	c.Allocated = time.Now()
+	a.leases[key] = &c
+	r := c
+	return &r, nil
+}
+
+// Bind sets the IPv4 address the UE with the IMSI gets with DHCPv4 to the lease
+// for the APN allocated with AllocateDeferred, and returns the copy of the lease.
+//
+// It returns ErrAddressUnavailable if the allocation is not deferred for the UE
+// and the address differs from the one in the lease.
+func (a *DHCPAllocator) Bind(imsi, apn string, addr netip.Addr) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	l, ok := a.leases[leaseKey{imsi, apn}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, %s", ErrLeaseNotFound, imsi, apn)
+	}
+	if !addr.Is4() || addr.IsUnspecified() || (!l.Deferred() && l.IPv4 != addr) {
+		return nil, fmt.Errorf("%w: %s", ErrAddressUnavailable, addr)
+	}
+	l.IPv4 = addr
+	l.Allocated = time.Now()
+
+	c := *l
+	return &c, nil
+}
+
+// Release releases the addresses leased to the UE with the IMSI for the APN to
+// the DHCP servers.
+//
+// The lease is removed even if the servers fail to release it, and the error is
+// returned. The unspecified IPv4 address of the deferred allocation is not passed
+// to the DHCPClient.
+func (a *DHCPAllocator) Release(imsi, apn string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := leaseKey{imsi, apn}
+	l, ok := a.leases[key]
+	if !ok {
+		return fmt.Errorf("%w: %s, %s", ErrLeaseNotFound, imsi, apn)
+	}
+	delete(a.leases, key)
+
+	c := *l
+	if c.Deferred() {
+		c.IPv4 = netip.Addr{}
+	}
+	if err := a.client.Release(&c); err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the copy of the lease of the UE with the IMSI for the APN.
+func (a *DHCPAllocator) Lookup(imsi, apn string) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	l, ok := a.leases[leaseKey{imsi, apn}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, %s", ErrLeaseNotFound, imsi, apn)
+	}
+	c := *l
+	return &c, nil
+}
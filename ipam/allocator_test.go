@@ -0,0 +1,128 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam_test
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/ipam"
+)
+
+type testDHCPClient struct {
+	v4       netip.Addr
+	v6       netip.Prefix
+	requests int
+	released []*ipam.Lease
+}
+
+func (c *testDHCPClient) RequestIPv4(imsi, apn string) (netip.Addr, error) {
+	if !c.v4.IsValid() {
+		return netip.Addr{}, ipam.ErrTypeNotSupported
+	}
+	c.requests++
+	return c.v4, nil
+}
+
+func (c *testDHCPClient) RequestIPv6(imsi, apn string) (netip.Prefix, error) {
+	if !c.v6.IsValid() {
+		return netip.Prefix{}, ipam.ErrTypeNotSupported
+	}
+	c.requests++
+	return c.v6, nil
+}
+
+func (c *testDHCPClient) Release(l *ipam.Lease) error {
+	c.released = append(c.released, l)
+	return nil
+}
+
+func TestDHCPAllocator(t *testing.T) {
+	client := &testDHCPClient{
+		v4: netip.MustParseAddr("10.0.0.1"),
+		v6: netip.MustParsePrefix("2001:db8::/64"),
+	}
+	a := ipam.NewDHCPAllocator(client)
+
+	l, err := a.Allocate("001010000000001", "internet", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != client.v4 || l.IPv6 != client.v6 || l.Deferred() {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	// the same lease is returned without requesting again.
+	if l, err = a.Allocate("001010000000001", "internet", ipam.IPv4v6); err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != client.v4 || client.requests != 2 {
+		t.Errorf("unexpected lease: %s, requests: %d", l, client.requests)
+	}
+
+	if err := a.Release("001010000000001", "internet"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Lookup("001010000000001", "internet"); !errors.Is(err, ipam.ErrLeaseNotFound) {
+		t.Errorf("got %v, want %v", err, ipam.ErrLeaseNotFound)
+	}
+	if len(client.released) != 1 || client.released[0].IPv4 != client.v4 {
+		t.Errorf("unexpected release: %v", client.released)
+	}
+}
+
+func TestDHCPAllocatorDeferred(t *testing.T) {
+	client := &testDHCPClient{v6: netip.MustParsePrefix("2001:db8::/64")}
+	a := ipam.NewDHCPAllocator(client)
+
+	l, err := a.AllocateDeferred("001010000000001", "internet", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Deferred() || l.IPv4.String() != "0.0.0.0" || l.Type() != ipam.IPv4v6 {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	addr := netip.MustParseAddr("10.0.0.2")
+	if _, err := a.Bind("001010000000001", "internet", netip.IPv4Unspecified()); !errors.Is(err, ipam.ErrAddressUnavailable) {
+		t.Errorf("got %v, want %v", err, ipam.ErrAddressUnavailable)
+	}
+	if l, err = a.Bind("001010000000001", "internet", addr); err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != addr || l.Deferred() {
+		t.Errorf("unexpected lease: %s", l)
+	}
+	if _, err := a.Bind("001010000000001", "internet", netip.MustParseAddr("10.0.0.3")); !errors.Is(err, ipam.ErrAddressUnavailable) {
+		t.Errorf("got %v, want %v", err, ipam.ErrAddressUnavailable)
+	}
+
+	// the unspecified address is not released to the servers.
+	if _, err := a.AllocateDeferred("001010000000002", "internet", ipam.IPv4); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release("001010000000002", "internet"); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.released) != 1 || client.released[0].IPv4.IsValid() {
+		t.Errorf("unexpected release: %v", client.released)
+	}
+}
+
+func TestDHCPAllocatorNotSupported(t *testing.T) {
+	a := ipam.NewDHCPAllocator(&testDHCPClient{v4: netip.MustParseAddr("10.0.0.1")})
+
+	l, err := a.Allocate("001010000000001", "internet", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Type() != ipam.IPv4 {
+		t.Errorf("got %d, want %d", l.Type(), ipam.IPv4)
+	}
+	if _, err := a.Allocate("001010000000002", "internet", ipam.IPv6); !errors.Is(err, ipam.ErrTypeNotSupported) {
+		t.Errorf("got %v, want %v", err, ipam.ErrTypeNotSupported)
+	}
+}
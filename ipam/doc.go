@@ -0,0 +1,29 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package ipam provides the management of the IP addresses assigned to the UEs,
+// typically by P-GW in Create Session Response.
+//
+// Each pool has an IPv4 prefix to assign the addresses from, and/or an IPv6 prefix
+// to delegate the prefixes(/64 by default) from, and is used for the APNs given.
+// The addresses are leased to the pair of IMSI and APN, and the same lease is
+// returned while the UE attaches to the APN again without releasing it.
+//
+//	a, err := ipam.New([]*ipam.PoolConfig{{
+//		Name: "internet", IPv4: "10.45.0.0/16", IPv6: "2001:db8:1::/48",
+//		APNs: []string{"internet"},
+//	}}, ipam.NewFileStore("/var/lib/pgw/leases.json"))
+//	// ...
+//	lease, err := a.Allocate(imsi, apn, ipam.IPv4v6)
+//	// ...
+//	err = a.Release(imsi, apn)
+//
+// The leases are persisted with the Store given, to keep assigning the same
+// addresses to the UEs after restarting.
+//
+// DHCPAllocator delegates the allocation to the external DHCP servers with the
+// DHCPClient given instead of the pools. It can also defer the allocation of the
+// IPv4 address until the UE requests it with DHCPv4, with AllocateDeferred and Bind.
+// Both implement Allocator.
+package ipam
@@ -0,0 +1,367 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownAPN indicates that no pool is configured for the APN.
+	ErrUnknownAPN = errors.New("no pool for APN")
+
+	// ErrPoolExhausted indicates that all the addresses in the pool are in use.
+	ErrPoolExhausted = errors.New("pool exhausted")
+
+	// ErrTypeNotSupported indicates that the pool has none of the address types
+	// requested.
+	ErrTypeNotSupported = errors.New("address type not supported")
+
+	// ErrLeaseNotFound indicates that no lease is found for the IMSI and APN.
+	ErrLeaseNotFound = errors.New("lease not found")
+
+	// ErrAddressUnavailable indicates that the static address requested is not
+	// assignable in the pool, or is in use by another UE.
+	ErrAddressUnavailable = errors.New("address unavailable")
+)
+
+// AddressType is the type of the addresses to allocate.
+//
+// The values are the same as the PDN Type in GTPv2, e.g., v2.PDNTypeIPv4.
+type AddressType uint8
+
+// AddressType definitions.
+const (
+	IPv4 AddressType = 1 + iota
+	IPv6
+	IPv4v6
+)
+
+// Lease is the addresses assigned to a UE for an APN.
+type Lease struct {
+	IMSI string `json:"imsi"`
+	APN  string `json:"apn"`
+	// IPv4 is the IPv4 address assigned, which is invalid if not assigned, or
+	// unspecified(0.0.0.0) if the allocation is deferred to DHCPv4.
+	IPv4 netip.Addr `json:"ipv4"`
+	// IPv6 is the IPv6 prefix delegated, which is invalid if not delegated.
+	IPv6 netip.Prefix `json:"ipv6"`
+	// Allocated is the time when the lease is allocated or updated last.
+	Allocated time.Time `json:"allocated"`
+}
+
+// Type returns the AddressType of the addresses in the lease.
+func (l *Lease) Type() AddressType {
+	var t AddressType
+	if l.IPv4.IsValid() {
+		t |= IPv4
+	}
+	if l.IPv6.IsValid() {
+		t |= IPv6
+	}
+	return t
+}
+
+// Deferred reports whether the IPv4 address is to be assigned with DHCPv4 after
+// the PDN connection is established, which is not known yet.
+func (l *Lease) Deferred() bool {
+	return l.IPv4.IsValid() && l.IPv4.IsUnspecified()
+}
+
+// String returns the addresses in the lease in a human-readable form.
+func (l *Lease) String() string {
+	return fmt.Sprintf("IMSI: %s, APN: %s, IPv4: %s, IPv6: %s", l.IMSI, l.APN, l.IPv4, l.IPv6)
+}
+
+type leaseKey struct {
+	imsi, apn string
+}
+
+// IPAM allocates the addresses in the pools to the UEs.
+type IPAM struct {
+	mu     sync.Mutex
+	pools  map[string]*pool
+	leases map[leaseKey]*Lease
+	store  Store
+}
+
+// New creates a new IPAM with the pools, restoring the leases from the store.
+//
+// The leases restored that no longer fit the pools, e.g., for the APN removed
+// from the configuration, are dropped and deleted from the store. store can be
+// nil not to persist the leases.
+func New(pools []*PoolConfig, store Store) (*IPAM, error) {
+	a := &IPAM{
+		pools:  map[string]*pool{},
+		leases: map[leaseKey]*Lease{},
+		store:  store,
+	}
+
+	names := map[string]bool{}
+	for _, cfg := range pools {
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("duplicate pool: %s", cfg.Name)
+		}
+		names[cfg.Name] = true
+
+		p, err := newPool(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool %s: %w", cfg.Name, err)
+		}
+		for _, apn := range cfg.APNs {
+			if _, ok := a.pools[apn]; ok {
+				return nil, fmt.Errorf("duplicate pool for APN: %s", apn)
+			}
+			a.pools[apn] = p
+		}
+	}
+
+	if store == nil {
+		return a, nil
+	}
+	leases, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leases: %w", err)
+	}
+	for _, l := range leases {
+		if a.restore(l) {
+			continue
+		}
+		if err := store.Delete(l); err != nil {
+			return nil, fmt.Errorf("failed to delete lease: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// restore marks the addresses in the lease in use. It returns false if the lease
+// does not fit the pools.
+func (a *IPAM) restore(l *Lease) bool {
+	key := leaseKey{l.IMSI, l.APN}
+	p, ok := a.pools[l.APN]
+	if !ok || a.leases[key] != nil || l.Type() == 0 {
+		return false
+	}
+
+	var v4, v6 uint64
+	if l.IPv4.IsValid() {
+		if v4, ok = p.v4Index(l.IPv4); !ok || !p.v4.mark(v4) {
+			return false
+		}
+	}
+	if l.IPv6.IsValid() {
+		if v6, ok = p.v6Index(l.IPv6); !ok || !p.v6.mark(v6) {
+			if l.IPv4.IsValid() {
+				p.v4.free(v4)
+			}
+			return false
+		}
+	}
+
+	a.leases[key] = l
+	return true
+}
+
+// Allocate assigns the addresses of the type requested in the pool for the APN
+// to the UE with the IMSI, and returns the copy of the lease.
+//
+// If the UE has the lease for the APN already, the same addresses are returned,
+// with the types not requested released and the ones missing assigned. If the
+// pool does not have both of IPv4 and IPv6 for IPv4v6, only the one the pool has
+// is assigned, and the caller should check the Type of the lease returned.
+func (a *IPAM) Allocate(imsi, apn string, typ AddressType) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p, ok := a.pools[apn]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAPN, apn)
+	}
+	if p.v4 == nil {
+		typ &^= IPv4
+	}
+	if p.v6 == nil {
+		typ &^= IPv6
+	}
+	if typ&IPv4v6 == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotSupported, p.name)
+	}
+
+	key := leaseKey{imsi, apn}
+	old, ok := a.leases[key]
+	if !ok {
+		old = &Lease{IMSI: imsi, APN: apn}
+	}
+	l := *old
+	l.Allocated = time.Now()
+
+	var v4, v6 uint64
+	newV4, newV6 := typ&IPv4 != 0 && !l.IPv4.IsValid(), typ&IPv6 != 0 && !l.IPv6.IsValid()
+	if newV4 {
+		if v4, ok = p.v4.take(); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPoolExhausted, p.name)
+		}
+		l.IPv4 = p.v4Addr(v4)
+	}
+	if newV6 {
+		if v6, ok = p.v6.take(); !ok {
+			if newV4 {
+				p.v4.free(v4)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrPoolExhausted, p.name)
+		}
+		l.IPv6 = p.v6Delegated(v6)
+	}
+	if typ&IPv4 == 0 {
+		l.IPv4 = netip.Addr{}
+	}
+	if typ&IPv6 == 0 {
+		l.IPv6 = netip.Prefix{}
+	}
+
+	if a.store != nil {
+		if err := a.store.Save(&l); err != nil {
+			if newV4 {
+				p.v4.free(v4)
+			}
+			if newV6 {
+				p.v6.free(v6)
+			}
+			return nil, fmt.Errorf("failed to save lease: %w", err)
+		}
+	}
+
+	// release the ones no longer requested after the lease is saved.
+	if old.IPv4.IsValid() && !l.IPv4.IsValid() {
+		i, _ := p.v4Index(old.IPv4)
+		p.v4.free(i)
+	}
+	if old.IPv6.IsValid() && !l.IPv6.IsValid() {
+		i, _ := p.v6Index(old.IPv6)
+		p.v6.free(i)
+	}
+
+	a.leases[key] = &l
+	c := l
+	return &c, nil
+}
+
+// AllocateStatic assigns the IPv4 address given in the pool for the APN to the UE
+// with the IMSI, e.g., the static address subscribed in HSS, and returns the copy
+// of the lease.
+//
+// If the UE has the lease for the APN already, its addresses are replaced with the
+// one given, and the IPv6 prefix is released. The address must be assignable in the
+// pool and not in use by another UE.
+func (a *IPAM) AllocateStatic(imsi, apn string, addr netip.Addr) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p, ok := a.pools[apn]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAPN, apn)
+	}
+	i, ok := p.v4Index(addr)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s not in %s", ErrAddressUnavailable, addr, p.name)
+	}
+
+	key := leaseKey{imsi, apn}
+	old, ok := a.leases[key]
+	if !ok {
+		old = &Lease{IMSI: imsi, APN: apn}
+	}
+	l := Lease{IMSI: imsi, APN: apn, IPv4: addr, Allocated: time.Now()}
+
+	marked := old.IPv4 != addr
+	if marked && !p.v4.mark(i) {
+		return nil, fmt.Errorf("%w: %s in use", ErrAddressUnavailable, addr)
+	}
+
+	if a.store != nil {
+		if err := a.store.Save(&l); err != nil {
+			if marked {
+				p.v4.free(i)
+			}
+			return nil, fmt.Errorf("failed to save lease: %w", err)
+		}
+	}
+
+	if marked && old.IPv4.IsValid() {
+		j, _ := p.v4Index(old.IPv4)
+		p.v4.free(j)
+	}
+	if old.IPv6.IsValid() {
+		j, _ := p.v6Index(old.IPv6)
+		p.v6.free(j)
+	}
+
+	a.leases[key] = &l
+	c := l
+	return &c, nil
+}
+
+// Release releases the addresses leased to the UE with the IMSI for the APN.
+//
+// The addresses are released even if it fails to delete the lease from the Store,
+// and the error is returned.
+func (a *IPAM) Release(imsi, apn string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := leaseKey{imsi, apn}
+	l, ok := a.leases[key]
+	if !ok {
+		return fmt.Errorf("%w: %s, %s", ErrLeaseNotFound, imsi, apn)
+	}
+	delete(a.leases, key)
+
+	p := a.pools[apn]
+	if l.IPv4.IsValid() {
+		i, _ := p.v4Index(l.IPv4)
+		p.v4.free(i)
+	}
+	if l.IPv6.IsValid() {
+		i, _ := p.v6Index(l.IPv6)
+		p.v6.free(i)
+	}
+
+	if a.store != nil {
+		if err := a.store.Delete(l); err != nil {
+			return fmt.Errorf("failed to delete lease: %w", err)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the copy of the lease of the UE with the IMSI for the APN.
+func (a *IPAM) Lookup(imsi, apn string) (*Lease, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	l, ok := a.leases[leaseKey{imsi, apn}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, %s", ErrLeaseNotFound, imsi, apn)
+	}
+	c := *l
+	return &c, nil
+}
+
+// Leases returns the copies of all the leases.
+func (a *IPAM) Leases() []*Lease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	leases := make([]*Lease, 0, len(a.leases))
+	for _, l := range a.leases {
+		c := *l
+		leases = append(leases, &c)
+	}
+	return leases
+}
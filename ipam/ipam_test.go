@@ -0,0 +1,222 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam_test
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/ipam"
+)
+
+func newIPAM(t *testing.T, store ipam.Store) *ipam.IPAM {
+	t.Helper()
+
+	a, err := ipam.New([]*ipam.PoolConfig{
+		{Name: "pool-v4", IPv4: "10.0.0.0/30", APNs: []string{"v4.example"}},
+		{Name: "pool-v6", IPv6: "2001:db8::/62", APNs: []string{"v6.example"}},
+		{
+			Name: "pool-dual", IPv4: "10.1.0.0/24", IPv6: "2001:db8:1::/48", IPv6PrefixLen: 56,
+			APNs: []string{"dual-1.example", "dual-2.example"},
+		},
+	}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAllocateIPv4(t *testing.T) {
+	a := newIPAM(t, nil)
+
+	// network and broadcast addresses are not assigned.
+	for i, want := range []string{"10.0.0.1", "10.0.0.2"} {
+		l, err := a.Allocate(fmt.Sprintf("00101000000000%d", i+1), "v4.example", ipam.IPv4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.IPv4 != netip.MustParseAddr(want) || l.IPv6.IsValid() || l.Type() != ipam.IPv4 {
+			t.Errorf("unexpected lease: %s, want IPv4: %s", l, want)
+		}
+	}
+	if _, err := a.Allocate("001010000000003", "v4.example", ipam.IPv4); !errors.Is(err, ipam.ErrPoolExhausted) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the lease is kept for the same IMSI and APN.
+	l, err := a.Allocate("001010000000002", "v4.example", ipam.IPv4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.0.0.2") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	if err := a.Release("001010000000001", "v4.example"); err != nil {
+		t.Fatal(err)
+	}
+	l, err = a.Allocate("001010000000003", "v4.example", ipam.IPv4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	if err := a.Release("001010000000001", "v4.example"); !errors.Is(err, ipam.ErrLeaseNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAllocateIPv6(t *testing.T) {
+	a := newIPAM(t, nil)
+
+	for i, want := range []string{"2001:db8::/64", "2001:db8:0:1::/64", "2001:db8:0:2::/64", "2001:db8:0:3::/64"} {
+		l, err := a.Allocate(fmt.Sprintf("00101000000000%d", i), "v6.example", ipam.IPv6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.IPv6 != netip.MustParsePrefix(want) || l.IPv4.IsValid() || l.Type() != ipam.IPv6 {
+			t.Errorf("unexpected lease: %s, want IPv6: %s", l, want)
+		}
+	}
+	if _, err := a.Allocate("001010000000009", "v6.example", ipam.IPv6); !errors.Is(err, ipam.ErrPoolExhausted) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAllocateIPv4v6(t *testing.T) {
+	a := newIPAM(t, nil)
+	imsi := "001010000000001"
+
+	l, err := a.Allocate(imsi, "dual-1.example", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.1.0.1") || l.IPv6 != netip.MustParsePrefix("2001:db8:1::/56") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	// another APN sharing the pool gets another addresses.
+	l, err = a.Allocate(imsi, "dual-2.example", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.1.0.2") || l.IPv6 != netip.MustParsePrefix("2001:db8:1:100::/56") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	// changing the type keeps the address requested again.
+	l, err = a.Allocate(imsi, "dual-1.example", ipam.IPv4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.1.0.1") || l.IPv6.IsValid() {
+		t.Errorf("unexpected lease: %s", l)
+	}
+	if got, err := a.Lookup(imsi, "dual-1.example"); err != nil || *got != *l {
+		t.Errorf("unexpected lookup: %v, %v", got, err)
+	}
+	if got := len(a.Leases()); got != 2 {
+		t.Errorf("unexpected number of leases: got %d, want %d", got, 2)
+	}
+
+	// IPv4v6 falls back to the type that the pool has.
+	l, err = a.Allocate(imsi, "v4.example", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Type() != ipam.IPv4 {
+		t.Errorf("unexpected lease: %s", l)
+	}
+}
+
+func TestAllocateErrors(t *testing.T) {
+	a := newIPAM(t, nil)
+
+	if _, err := a.Allocate("001010000000001", "unknown.example", ipam.IPv4); !errors.Is(err, ipam.ErrUnknownAPN) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := a.Allocate("001010000000001", "v4.example", ipam.IPv6); !errors.Is(err, ipam.ErrTypeNotSupported) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := a.Lookup("001010000000001", "v4.example"); !errors.Is(err, ipam.ErrLeaseNotFound) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	cases := []struct {
+		description string
+		pools       []*ipam.PoolConfig
+	}{
+		{"no prefix", []*ipam.PoolConfig{{Name: "p"}}},
+		{"IPv4 too long", []*ipam.PoolConfig{{Name: "p", IPv4: "10.0.0.0/31"}}},
+		{"IPv6 in IPv4", []*ipam.PoolConfig{{Name: "p", IPv4: "2001:db8::/64"}}},
+		{"IPv6 too long", []*ipam.PoolConfig{{Name: "p", IPv6: "2001:db8::/64"}}},
+		{"IPv6 prefix length too long", []*ipam.PoolConfig{{Name: "p", IPv6: "2001:db8::/48", IPv6PrefixLen: 80}}},
+		{"duplicate pool", []*ipam.PoolConfig{{Name: "p", IPv4: "10.0.0.0/24"}, {Name: "p", IPv4: "10.1.0.0/24"}}},
+		{"duplicate APN", []*ipam.PoolConfig{
+			{Name: "p1", IPv4: "10.0.0.0/24", APNs: []string{"a"}},
+			{Name: "p2", IPv4: "10.1.0.0/24", APNs: []string{"a"}},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if _, err := ipam.New(c.pools, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAllocateStatic(t *testing.T) {
+	a := newIPAM(t, nil)
+	static := netip.MustParseAddr("10.1.0.10")
+
+	l, err := a.AllocateStatic("001010000000001", "dual-1.example", static)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != static || l.IPv6.IsValid() {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	// the same address is kept for the same UE, and not assigned to others.
+	if _, err := a.AllocateStatic("001010000000001", "dual-1.example", static); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.AllocateStatic("001010000000002", "dual-2.example", static); !errors.Is(err, ipam.ErrAddressUnavailable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// replacing the dynamic addresses releases them.
+	l, err = a.Allocate("001010000000003", "dual-1.example", ipam.IPv4v6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dynamic := l.IPv4
+	if _, err := a.AllocateStatic("001010000000003", "dual-1.example", netip.MustParseAddr("10.1.0.20")); err != nil {
+		t.Fatal(err)
+	}
+	l, err = a.AllocateStatic("001010000000004", "dual-1.example", dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != dynamic {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	for _, addr := range []string{"10.0.0.1", "10.1.0.0", "10.1.0.255"} {
+		if _, err := a.AllocateStatic("001010000000005", "dual-1.example", netip.MustParseAddr(addr)); !errors.Is(err, ipam.ErrAddressUnavailable) {
+			t.Errorf("unexpected error for %s: %v", addr, err)
+		}
+	}
+	if _, err := a.AllocateStatic("001010000000005", "v6.example", static); !errors.Is(err, ipam.ErrAddressUnavailable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
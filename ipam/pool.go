@@ -0,0 +1,175 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+)
+
+// DefaultIPv6PrefixLen is the length of the IPv6 prefixes delegated to the UEs,
+// used when IPv6PrefixLen is not set in PoolConfig.
+const DefaultIPv6PrefixLen = 64
+
+// PoolConfig is the configuration of a pool of the addresses.
+type PoolConfig struct {
+	// Name is the name of the pool, which should be unique.
+	Name string
+	// IPv4 is the IPv4 prefix in CIDR notation to assign the addresses from.
+	// The network and broadcast addresses are not assigned. If empty, the pool
+	// has no IPv4 addresses.
+	IPv4 string
+	// IPv6 is the IPv6 prefix in CIDR notation to delegate the prefixes from.
+	// If empty, the pool has no IPv6 prefixes.
+	IPv6 string
+	// IPv6PrefixLen is the length of the IPv6 prefixes delegated, which should be
+	// 64 or shorter. If zero, DefaultIPv6PrefixLen is used.
+	IPv6PrefixLen int
+	// APNs are the APNs that the pool is used for.
+	APNs []string
+}
+
+// pool is the addresses in use in a pool.
+type pool struct {
+	name string
+
+	v4Prefix netip.Prefix
+	v4       *ring
+
+	v6Prefix netip.Prefix
+	v6Len    int
+	v6       *ring
+}
+
+func newPool(cfg *PoolConfig) (*pool, error) {
+	p := &pool{name: cfg.Name}
+
+	if cfg.IPv4 != "" {
+		prefix, err := netip.ParsePrefix(cfg.IPv4)
+		if err != nil {
+			return nil, err
+		}
+		if !prefix.Addr().Is4() || prefix.Bits() > 30 {
+			return nil, fmt.Errorf("IPv4 prefix should be /30 or shorter: %s", cfg.IPv4)
+		}
+		p.v4Prefix = prefix.Masked()
+		// the first and the last ones are the network and broadcast addresses.
+		p.v4 = newRing(1, 1<<(32-prefix.Bits())-2)
+	}
+
+	if cfg.IPv6 != "" {
+		prefix, err := netip.ParsePrefix(cfg.IPv6)
+		if err != nil {
+			return nil, err
+		}
+		p.v6Len = cfg.IPv6PrefixLen
+		if p.v6Len == 0 {
+			p.v6Len = DefaultIPv6PrefixLen
+		}
+		if !prefix.Addr().Is6() || prefix.Addr().Is4In6() || p.v6Len > 64 || prefix.Bits() >= p.v6Len || prefix.Bits() == 0 {
+			return nil, fmt.Errorf("IPv6 prefix should be shorter than /%d: %s", p.v6Len, cfg.IPv6)
+		}
+		p.v6Prefix = prefix.Masked()
+		p.v6 = newRing(0, 1<<(p.v6Len-prefix.Bits())-1)
+	}
+
+	if p.v4 == nil && p.v6 == nil {
+		return nil, fmt.Errorf("neither IPv4 nor IPv6 prefix is given")
+	}
+	return p, nil
+}
+
+// v4Addr returns the i-th address in the IPv4 prefix.
+func (p *pool) v4Addr(i uint64) netip.Addr {
+	b := p.v4Prefix.Addr().As4()
+	binary.BigEndian.PutUint32(b[:], binary.BigEndian.Uint32(b[:])+uint32(i))
+	return netip.AddrFrom4(b)
+}
+
+// v4Index returns the index of the IPv4 address in the pool. The second returned
+// value is false if it is not assignable in the pool.
+func (p *pool) v4Index(addr netip.Addr) (uint64, bool) {
+	if p.v4 == nil || !p.v4Prefix.Contains(addr) {
+		return 0, false
+	}
+	b, base := addr.As4(), p.v4Prefix.Addr().As4()
+	i := uint64(binary.BigEndian.Uint32(b[:]) - binary.BigEndian.Uint32(base[:]))
+	return i, p.v4.contains(i)
+}
+
+// v6Delegated returns the i-th prefix delegated from the IPv6 prefix.
+func (p *pool) v6Delegated(i uint64) netip.Prefix {
+	b := p.v6Prefix.Addr().As16()
+	hi := binary.BigEndian.Uint64(b[:8]) + i<<(64-p.v6Len)
+	binary.BigEndian.PutUint64(b[:8], hi)
+	return netip.PrefixFrom(netip.AddrFrom16(b), p.v6Len)
+}
+
+// v6Index returns the index of the IPv6 prefix delegated from the pool. The second
+// returned value is false if it is not delegated from the pool.
+func (p *pool) v6Index(prefix netip.Prefix) (uint64, bool) {
+	if p.v6 == nil || prefix.Bits() != p.v6Len || !p.v6Prefix.Contains(prefix.Addr()) || prefix.Masked() != prefix {
+		return 0, false
+	}
+	b, base := prefix.Addr().As16(), p.v6Prefix.Addr().As16()
+	i := (binary.BigEndian.Uint64(b[:8]) - binary.BigEndian.Uint64(base[:8])) >> (64 - p.v6Len)
+	return i, p.v6.contains(i)
+}
+
+// ring assigns the indices between first and last, looking for the unused one
+// from the one next to the last assigned.
+type ring struct {
+	first, last uint64
+	next        uint64
+	inUse       map[uint64]struct{}
+}
+
+func newRing(first, last uint64) *ring {
+	return &ring{first: first, last: last, next: first, inUse: map[uint64]struct{}{}}
+}
+
+func (r *ring) contains(i uint64) bool {
+	return i >= r.first && i <= r.last
+}
+
+// take returns an index not in use. The second returned value is false if all
+// the indices are in use.
+func (r *ring) take() (uint64, bool) {
+	if uint64(len(r.inUse)) > r.last-r.first {
+		return 0, false
+	}
+
+	i := r.next
+	for {
+		if _, ok := r.inUse[i]; !ok {
+			r.inUse[i] = struct{}{}
+			r.next = r.wrap(i)
+			return i, true
+		}
+		i = r.wrap(i)
+	}
+}
+
+// mark makes the index in use. It returns false if it has been in use already.
+func (r *ring) mark(i uint64) bool {
+	if _, ok := r.inUse[i]; ok {
+		return false
+	}
+	r.inUse[i] = struct{}{}
+	return true
+}
+
+func (r *ring) free(i uint64) {
+	delete(r.inUse, i)
+}
+
+// wrap returns the index next to i, which is the first one after the last.
+func (r *ring) wrap(i uint64) uint64 {
+	if i >= r.last {
+		return r.first
+	}
+	return i + 1
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Store persists the leases, to restore them after restarting.
+//
+// The methods are called by IPAM with its lock held, and are not called
+// concurrently.
+type Store interface {
+	// Load returns all the leases persisted, which is called once in New.
+	Load() ([]*Lease, error)
+	// Save persists the lease allocated or updated.
+	Save(l *Lease) error
+	// Delete removes the lease released.
+	Delete(l *Lease) error
+}
+
+// FileStore is a Store that keeps the leases in a JSON file.
+//
+// The whole file is rewritten on every change, which is fine for the example
+// and the test environment but not for a large number of UEs.
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	leases map[leaseKey]*Lease
+}
+
+// NewFileStore creates a new FileStore with the file at path, which is created
+// when the first lease is saved.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path, leases: map[leaseKey]*Lease{}}
+}
+
+// Load reads the leases from the file. It returns no leases if the file does not exist.
+func (s *FileStore) Load() ([]*Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var leases []*Lease
+	if err := json.Unmarshal(b, &leases); err != nil {
+		return nil, err
+	}
+	for _, l := range leases {
+		s.leases[leaseKey{l.IMSI, l.APN}] = l
+	}
+	return leases, nil
+}
+
+// Save writes the lease into the file.
+func (s *FileStore) Save(l *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := *l
+	s.leases[leaseKey{l.IMSI, l.APN}] = &c
+	return s.write()
+}
+
+// Delete removes the lease from the file.
+func (s *FileStore) Delete(l *Lease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.leases, leaseKey{l.IMSI, l.APN})
+	return s.write()
+}
+
+// write replaces the file with the leases, through the temporary file not to
+// leave the file broken on failure.
+func (s *FileStore) write() error {
+	leases := make([]*Lease, 0, len(s.leases))
+	for _, l := range s.leases {
+		leases = append(leases, l)
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		if leases[i].IMSI != leases[j].IMSI {
+			return leases[i].IMSI < leases[j].IMSI
+		}
+		return leases[i].APN < leases[j].APN
+	})
+
+	b, err := json.MarshalIndent(leases, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
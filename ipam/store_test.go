@@ -0,0 +1,81 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ipam_test
+
+import (
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/ipam"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.json")
+
+	a := newIPAM(t, ipam.NewFileStore(path))
+	for _, imsi := range []string{"001010000000001", "001010000000002", "001010000000003"} {
+		if _, err := a.Allocate(imsi, "dual-1.example", ipam.IPv4v6); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Release("001010000000002", "dual-1.example"); err != nil {
+		t.Fatal(err)
+	}
+
+	// restored after restarting.
+	a = newIPAM(t, ipam.NewFileStore(path))
+	if got := len(a.Leases()); got != 2 {
+		t.Fatalf("unexpected number of leases: got %d, want %d", got, 2)
+	}
+	l, err := a.Lookup("001010000000003", "dual-1.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.1.0.3") || l.IPv6 != netip.MustParsePrefix("2001:db8:1:200::/56") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+
+	// the addresses restored are not assigned to others.
+	l, err = a.Allocate("001010000000004", "dual-1.example", ipam.IPv4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.IPv4 != netip.MustParseAddr("10.1.0.2") {
+		t.Errorf("unexpected lease: %s", l)
+	}
+}
+
+func TestFileStoreDropsUnfit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.json")
+
+	a := newIPAM(t, ipam.NewFileStore(path))
+	if _, err := a.Allocate("001010000000001", "dual-1.example", ipam.IPv4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Allocate("001010000000001", "v4.example", ipam.IPv4); err != nil {
+		t.Fatal(err)
+	}
+
+	// restarted without the pool for dual-1.example.
+	store := ipam.NewFileStore(path)
+	a, err := ipam.New([]*ipam.PoolConfig{
+		{Name: "pool-v4", IPv4: "10.0.0.0/30", APNs: []string{"v4.example"}},
+	}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(a.Leases()); got != 1 {
+		t.Fatalf("unexpected number of leases: got %d, want %d", got, 1)
+	}
+
+	leases, err := ipam.NewFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 1 || leases[0].APN != "v4.example" {
+		t.Errorf("unexpected leases persisted: %v", leases)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package mmesim provides a scriptable MME simulator that drives the S11
+// procedures toward an S-GW, to test the S-GW and P-GW implementations.
+//
+// The MME dials to the S-GW given in Config, and each procedure returns after
+// the response from the S-GW is received.
+//
+//	mme, err := mmesim.Dial(&mmesim.Config{LocalAddr: laddr, SGWAddr: sgwAddr}, errCh)
+//	if err != nil {
+//		// ...
+//	}
+//	sess, err := mme.AttachUE("001010123456789", "internet")
+//	// ...
+//	err = mme.TriggerHandover("001010123456789", "192.168.0.2")
+//	// ...
+//	err = mme.ReleaseAccessBearers("001010123456789") // to ECM-IDLE
+//	// ...
+//	err = mme.ServiceRequest("001010123456789", "") // back to ECM-CONNECTED
+//	// ...
+//	err = mme.Detach("001010123456789")
+//
+// The sessions can be moved to another S-GW with RelocateSGW, e.g., to drain the
+// S-GW for maintenance. The P-GW keeps the PDN connections, and the sessions on the
+// old S-GW are deleted without being deleted on the P-GW.
+//
+//	err = mme.RelocateSGW(newSGWAddr, "001010123456789", "001010123456790")
+//
+// The S1 and NAS procedures toward the UE and eNB are not simulated. The F-TEID
+// of eNB is allocated by the MME with the IP address given, and the U-plane
+// addresses are set in the default bearer of the Session for the users to send
+// the traffic by themselves.
+package mmesim
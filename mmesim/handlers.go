@@ -0,0 +1,37 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mmesim
+
+import (
+	"net"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// handleResponse passes the response to the procedure waiting for it.
+func (m *MME) handleResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	return v2.PassMessageTo(sess, msg, m.cfg.Timeout)
+}
+
+// handleDeleteSessionResponse passes the response to Detach if it is waiting.
+// Otherwise the Delete Session Request has been sent by others, e.g., with
+// (*v2.Session).Delete, and the session is just removed here.
+func (m *MME) handleDeleteSessionResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Message) error {
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	if _, ok := m.detaching.Load(sess.IMSI); ok {
+		return v2.PassMessageTo(sess, msg, m.cfg.Timeout)
+	}
+
+	c.RemoveSession(sess)
+	return nil
+}
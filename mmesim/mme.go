@@ -0,0 +1,468 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mmesim
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultTimeout is the time to wait for the response from S-GW used when
+// Timeout is not set in Config.
+const DefaultTimeout = 3 * time.Second
+
+// DefaultEBI is the EPS Bearer ID of the default bearer created by Attach.
+const DefaultEBI uint8 = 5
+
+// gtpuPort is the port of the S-GW set in the default bearer as the remote address.
+const gtpuPort = 2152
+
+// ErrNoPGWFTEID is returned by RelocateSGW when the S-GW has not told the F-TEIDs
+// of the P-GW for the subscriber in Create Session Response.
+var ErrNoPGWFTEID = errors.New("F-TEIDs of P-GW not known")
+
+// Config is the configuration of MME.
+type Config struct {
+	// LocalAddr is the address of S11 interface of the MME.
+	LocalAddr net.Addr
+	// SGWAddr is the address of S11 interface of the S-GW to be tested.
+	SGWAddr net.Addr
+
+	// ENBIP is the IP address of eNB set in the F-TEID for S1-U.
+	// If empty, the IP address of LocalAddr is used.
+	ENBIP string
+	// MCC and MNC are the PLMN of the subscribers attached with AttachUE.
+	// If empty, Serving Network and User Location Information are not sent.
+	MCC, MNC string
+
+	// SelectPGW returns the IP address of P-GW for the APN, which is set in
+	// the F-TEID for S5/S8-C in Create Session Request.
+	// If nil, the IP address of SGWAddr is used for all the APNs.
+	SelectPGW func(apn string) (string, error)
+
+	// QoS is the QoS of the default bearer. If nil, QCI 9 with no bit rates is used.
+	QoS *v2.QoSProfile
+	// Timeout is the time to wait for the response from S-GW.
+	// If zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// EndMarkerWait is the time RelocateSGW waits before deleting the session on
+	// the old S-GW, for the End Markers sent by the P-GW on the old path to reach
+	// the eNB through it. If zero, the session is deleted right after relocated.
+	EndMarkerWait time.Duration
+}
+
+// MME is a simulated MME connected to an S-GW.
+type MME struct {
+	// Conn is the S11 Conn of the MME, which keeps the sessions attached.
+	Conn *v2.Conn
+
+	cfg Config
+	// IMSIs of the sessions being detached by Detach, or being deleted on the old
+	// S-GW by RelocateSGW, which wait for the response.
+	detaching sync.Map
+	// *ueContext of the sessions by IMSI.
+	contexts sync.Map
+}
+
+// ueContext is what the MME knows about the subscriber out of the Session.
+type ueContext struct {
+	enbIP                string
+	pgwCFTEID, pgwUFTEID *ies.IE
+}
+
+func (m *MME) context(imsi string) *ueContext {
+	ctx, ok := m.contexts.Load(imsi)
+	if !ok {
+		return nil
+	}
+	return ctx.(*ueContext)
+}
+
+// Dial creates a new MME that is connected to the S-GW in cfg.
+//
+// The errors occurred in the background, such as the response that does not
+// match any session, are sent to errCh, which should be monitored continuously.
+func Dial(cfg *Config, errCh chan error) (*MME, error) {
+	m := &MME{cfg: *cfg}
+	if m.cfg.ENBIP == "" {
+		m.cfg.ENBIP = ipOf(cfg.LocalAddr)
+	}
+	if m.cfg.SelectPGW == nil {
+		sgwIP := ipOf(cfg.SGWAddr)
+		m.cfg.SelectPGW = func(string) (string, error) {
+			return sgwIP, nil
+		}
+	}
+	if m.cfg.QoS == nil {
+		m.cfg.QoS = &v2.QoSProfile{PL: 15, QCI: 9}
+	}
+	if m.cfg.Timeout == 0 {
+		m.cfg.Timeout = DefaultTimeout
+	}
+
+	conn, err := v2.Dial(cfg.LocalAddr, cfg.SGWAddr, 0, errCh)
+	if err != nil {
+		return nil, err
+	}
+	conn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionResponse:        m.handleResponse,
+		messages.MsgTypeModifyBearerResponse:         m.handleResponse,
+		messages.MsgTypeReleaseAccessBearersResponse: m.handleResponse,
+		messages.MsgTypeDeleteSessionResponse:        m.handleDeleteSessionResponse,
+	})
+	m.Conn = conn
+	return m, nil
+}
+
+// Close closes the Conn of MME. The sessions are not deleted on S-GW.
+func (m *MME) Close() error {
+	return m.Conn.Close()
+}
+
+// AttachUE attaches the subscriber with IMSI to the APN, with the MCC and MNC
+// in Config. See Attach for details.
+func (m *MME) AttachUE(imsi, apn string) (*v2.Session, error) {
+	return m.Attach(&v2.Subscriber{
+		IMSI: imsi,
+		Location: &v2.Location{
+			MCC: m.cfg.MCC, MNC: m.cfg.MNC, RATType: v2.RATTypeEUTRAN,
+		},
+	}, apn)
+}
+
+// Attach establishes a session for the subscriber with the APN given, by sending
+// Create Session Request and then Modify Bearer Request with the F-TEID of eNB.
+//
+// The Session returned is active, and its default bearer has the IP address
+// allocated by the P-GW, the TEID of eNB as incoming TEID, and the TEID and
+// address of S-GW for S1-U as outgoing TEID and remote address.
+// If the subscriber has been attached already, the old session is removed locally.
+func (m *MME) Attach(sub *v2.Subscriber, apn string) (*v2.Session, error) {
+	return m.attach(sub, apn, "0.0.0.0")
+}
+
+// AttachWithIP is the same as Attach, but requests the static IPv4 address given,
+// which is subscribed in HSS in the real network, in the PDN Address Allocation.
+//
+// Whether the address is honored is up to the P-GW. The IP address allocated
+// actually is set in the default bearer of the Session returned.
+func (m *MME) AttachWithIP(sub *v2.Subscriber, apn, ip string) (*v2.Session, error) {
+	if net.ParseIP(ip).To4() == nil {
+		return nil, fmt.Errorf("invalid IPv4 address: %s", ip)
+	}
+	return m.attach(sub, apn, ip)
+}
+
+func (m *MME) attach(sub *v2.Subscriber, apn, ip string) (*v2.Session, error) {
+	if old, err := m.Conn.GetSessionByIMSI(sub.IMSI); err == nil {
+		m.Conn.RemoveSession(old)
+	}
+
+	pgwIP, err := m.cfg.SelectPGW(apn)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := v2.NewSession(m.cfg.SGWAddr, sub)
+	fteid := m.Conn.NewFTEID(v2.IFTypeS11MMEGTPC, ipOf(m.Conn.LocalAddr()), "")
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, fteid.TEID())
+
+	qos := *m.cfg.QoS
+	br := v2.NewBearer(DefaultEBI, apn, &qos)
+	sess.SetDefaultBearer(br)
+
+	rat := v2.RATTypeEUTRAN
+	if sub.Location != nil && sub.RATType != 0 {
+		rat = sub.RATType
+	}
+	var pci, pvi uint8
+	if br.PCI {
+		pci = 1
+	}
+	if br.PVI {
+		pvi = 1
+	}
+	ie := []*ies.IE{
+		ies.NewIMSI(sub.IMSI),
+		ies.NewRATType(rat),
+		fteid,
+		ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0, pgwIP, "").WithInstance(1),
+		ies.NewAccessPointName(apn),
+		ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+		ies.NewPDNType(v2.PDNTypeIPv4),
+		ies.NewPDNAddressAllocation(ip),
+		ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+		ies.NewAggregateMaximumBitRate(0, 0),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(br.EBI),
+			ies.NewBearerQoS(pci, br.PL, pvi, br.QCI, br.MBRUL, br.MBRDL, br.GBRUL, br.GBRDL),
+		),
+	}
+	if sub.MSISDN != "" {
+		ie = append(ie, ies.NewMSISDN(sub.MSISDN))
+	}
+	if sub.IMEI != "" {
+		ie = append(ie, ies.NewMobileEquipmentIdentity(sub.IMEI))
+	}
+	if loc := sub.Location; loc != nil && loc.MCC != "" {
+		ie = append(ie,
+			ies.NewServingNetwork(loc.MCC, loc.MNC),
+			ies.NewUserLocationInformation(
+				0, 0, 0, 1, 1, 0, 0, 0,
+				loc.MCC, loc.MNC, 0, 0, 0, 0, loc.TAI, loc.ECI, 0, 0,
+			),
+		)
+	}
+
+	m.Conn.AddSession(sess)
+	msg, err := m.request(sess, messages.NewCreateSessionRequest(0, sess.Sequence, ie...))
+	if err != nil {
+		m.Conn.RemoveSession(sess)
+		return nil, err
+	}
+	csRsp, ok := msg.(*messages.CreateSessionResponse)
+	if !ok {
+		m.Conn.RemoveSession(sess)
+		return nil, v2.ErrUnexpectedType
+	}
+	if err := checkCause(csRsp, csRsp.Cause, sub.IMSI); err != nil {
+		m.Conn.RemoveSession(sess)
+		return nil, err
+	}
+
+	if ie := csRsp.SenderFTEIDC; ie != nil {
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, ie.TEID())
+	} else {
+		m.Conn.RemoveSession(sess)
+		return nil, &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+	if ie := csRsp.PAA; ie != nil {
+		br.SubscriberIP = ie.IPAddress()
+	} else {
+		m.Conn.RemoveSession(sess)
+		return nil, &v2.ErrRequiredIEMissing{Type: ies.PDNAddressAllocation}
+	}
+	if ie := csRsp.BearerContextsCreated; ie != nil {
+		updateS1USGW(sess, ie)
+	} else {
+		m.Conn.RemoveSession(sess)
+		return nil, &v2.ErrRequiredIEMissing{Type: ies.BearerContext}
+	}
+
+	// kept to relocate the session to another S-GW, if told.
+	ctx := &ueContext{pgwCFTEID: csRsp.PGWS5S8FTEIDC}
+	for _, ie := range csRsp.BearerContextsCreated.BearerContext() {
+		if ie.Type == ies.FullyQualifiedTEID && ie.InterfaceType() == v2.IFTypeS5S8PGWGTPU {
+			ctx.pgwUFTEID = ie
+		}
+	}
+	m.contexts.Store(sub.IMSI, ctx)
+
+	if err := m.modifyBearer(sess, m.cfg.ENBIP); err != nil {
+		m.Conn.RemoveSession(sess)
+		return nil, err
+	}
+	if err := sess.Activate(); err != nil {
+		m.Conn.RemoveSession(sess)
+		return nil, err
+	}
+	return sess, nil
+}
+
+// TriggerHandover moves the subscriber to the eNB at enbIP, by sending Modify Bearer
+// Request with a new F-TEID of eNB, as in the X2-based handover without S-GW relocation.
+// If enbIP is empty, the ENBIP in Config is used.
+func (m *MME) TriggerHandover(imsi, enbIP string) error {
+	sess, err := m.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	if enbIP == "" {
+		enbIP = m.cfg.ENBIP
+	}
+	return m.modifyBearer(sess, enbIP)
+}
+
+// ReleaseAccessBearers moves the subscriber to ECM-IDLE by sending Release Access
+// Bearers Request, as in the S1 release procedure.
+//
+// The S-GW keeps the session without the S1-U path toward the eNB, and the Session
+// is deactivated locally until ServiceRequest is called.
+func (m *MME) ReleaseAccessBearers(imsi string) error {
+	sess, err := m.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	sgwTEID, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	sess.Sequence++
+	msg, err := m.request(sess, messages.NewReleaseAccessBearersRequest(
+		sgwTEID, sess.Sequence, ies.NewNodeType(v2.NodeTypeMME),
+	))
+	if err != nil {
+		return err
+	}
+	rabRsp, ok := msg.(*messages.ReleaseAccessBearersResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	if err := checkCause(rabRsp, rabRsp.Cause, imsi); err != nil {
+		return err
+	}
+	return sess.Deactivate()
+}
+
+// ServiceRequest moves the subscriber in ECM-IDLE back to ECM-CONNECTED at the eNB
+// at enbIP, by sending Modify Bearer Request with a new F-TEID of eNB, as in the
+// UE-triggered Service Request procedure. If enbIP is empty, the ENBIP in Config is used.
+func (m *MME) ServiceRequest(imsi, enbIP string) error {
+	sess, err := m.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	if enbIP == "" {
+		enbIP = m.cfg.ENBIP
+	}
+	if err := m.modifyBearer(sess, enbIP); err != nil {
+		return err
+	}
+	return sess.Activate()
+}
+
+// Detach deletes the session of the subscriber by sending Delete Session Request.
+//
+// The session is removed locally even if the S-GW does not accept it.
+func (m *MME) Detach(imsi string) error {
+	sess, err := m.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	defer m.Conn.RemoveSession(sess)
+	defer m.contexts.Delete(imsi)
+
+	sgwTEID, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	m.detaching.Store(imsi, struct{}{})
+	defer m.detaching.Delete(imsi)
+
+	sess.Sequence++
+	msg, err := m.request(sess, messages.NewDeleteSessionRequest(
+		sgwTEID, sess.Sequence, ies.NewEPSBearerID(sess.GetDefaultBearer().EBI),
+	))
+	if err != nil {
+		return err
+	}
+	dsRsp, ok := msg.(*messages.DeleteSessionResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	return checkCause(dsRsp, dsRsp.Cause, imsi)
+}
+
+// modifyBearer sends Modify Bearer Request with a new F-TEID of eNB at enbIP,
+// and updates the session with it once accepted.
+func (m *MME) modifyBearer(sess *v2.Session, enbIP string) error {
+	sgwTEID, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	br := sess.GetDefaultBearer()
+	enbFTEID := m.Conn.NewFTEID(v2.IFTypeS1UeNodeBGTPU, enbIP, "")
+
+	sess.Sequence++
+	msg, err := m.request(sess, messages.NewModifyBearerRequest(
+		sgwTEID, sess.Sequence,
+		ies.NewBearerContext(ies.NewEPSBearerID(br.EBI), enbFTEID),
+	))
+	if err != nil {
+		return err
+	}
+	mbRsp, ok := msg.(*messages.ModifyBearerResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	if err := checkCause(mbRsp, mbRsp.Cause, sess.IMSI); err != nil {
+		return err
+	}
+	if ie := mbRsp.BearerContextsModified; ie != nil {
+		updateS1USGW(sess, ie)
+	}
+
+	sess.AddTEID(v2.IFTypeS1UeNodeBGTPU, enbFTEID.TEID())
+	br.SetIncomingTEID(enbFTEID.TEID())
+	if ctx := m.context(sess.IMSI); ctx != nil {
+		ctx.enbIP = enbIP
+	}
+	return nil
+}
+
+// request sends the request to the S-GW serving the session and waits for the
+// response to it.
+func (m *MME) request(sess *v2.Session, req messages.Message) (messages.Message, error) {
+	return m.requestTo(sess, sess.PeerAddr, req)
+}
+
+// requestTo sends the request to the S-GW at sgwAddr and waits for the response to it.
+func (m *MME) requestTo(sess *v2.Session, sgwAddr net.Addr, req messages.Message) (messages.Message, error) {
+	if err := m.Conn.SendMessageTo(req, sgwAddr); err != nil {
+		return nil, err
+	}
+	return sess.WaitMessage(m.cfg.Timeout)
+}
+
+// updateS1USGW updates the TEID and address of S-GW for S1-U in the session
+// with the F-TEID in the Bearer Context, if any.
+func updateS1USGW(sess *v2.Session, brCtx *ies.IE) {
+	for _, ie := range brCtx.BearerContext() {
+		if ie.Type != ies.FullyQualifiedTEID || ie.InterfaceType() != v2.IFTypeS1USGWGTPU {
+			continue
+		}
+		sess.AddTEID(v2.IFTypeS1USGWGTPU, ie.TEID())
+		br := sess.GetDefaultBearer()
+		br.SetOutgoingTEID(ie.TEID())
+		br.SetRemoteAddress(&net.UDPAddr{IP: net.ParseIP(ie.IPAddress()), Port: gtpuPort})
+	}
+}
+
+// checkCause returns an error if the Cause IE is missing or not accepted.
+func checkCause(msg messages.Message, cause *ies.IE, imsi string) error {
+	if cause == nil {
+		return &v2.ErrRequiredIEMissing{Type: ies.Cause}
+	}
+	if c := cause.Cause(); c != v2.CauseRequestAccepted {
+		return &v2.ErrCauseNotOK{
+			MsgType: msg.MessageTypeName(),
+			Cause:   c,
+			Msg:     "subscriber: " + imsi,
+		}
+	}
+	return nil
+}
+
+func ipOf(addr net.Addr) string {
+	if a, ok := addr.(*net.UDPAddr); ok {
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
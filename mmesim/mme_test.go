@@ -0,0 +1,283 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mmesim_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtptest"
+	"github.com/wmnsk/go-gtp/mmesim"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// setup returns an MME connected to the S-GW in gtptest.Network, separately
+// from the MME in the Network.
+func setup(t *testing.T, cfg *mmesim.Config) (*mmesim.MME, *gtptest.Network) {
+	t.Helper()
+
+	n := gtptest.StartNetwork(t)
+	cfg.LocalAddr = gtptest.LoopbackAddr(t)
+	cfg.SGWAddr = n.SGW.S11Conn.LocalAddr()
+
+	errCh := make(chan error, 16)
+	mme, err := mmesim.Dial(cfg, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mme.Close()
+		close(errCh)
+		for err := range errCh {
+			t.Errorf("error in handler: %v", err)
+		}
+	})
+	return mme, n
+}
+
+func TestAttachUE(t *testing.T) {
+	mme, n := setup(t, &mmesim.Config{MCC: "001", MNC: "01", ENBIP: "127.0.0.3"})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachUE(imsi, "some.apn.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sess.IsActive() {
+		t.Error("session is not active")
+	}
+	if sess.MCC != "001" || sess.MNC != "01" {
+		t.Errorf("unexpected PLMN: %s-%s", sess.MCC, sess.MNC)
+	}
+
+	br := sess.GetDefaultBearer()
+	if br.EBI != mmesim.DefaultEBI || br.APN != "some.apn.example" {
+		t.Errorf("unexpected bearer: EBI=%d, APN=%s", br.EBI, br.APN)
+	}
+	if !gtptest.SubscriberNetwork.Contains(net.ParseIP(br.SubscriberIP)) {
+		t.Errorf("unexpected subscriber IP: %s", br.SubscriberIP)
+	}
+
+	s11Sess, err := n.SGW.S11Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1u, err := s11Sess.GetTEID(v2.IFTypeS1USGWGTPU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.OutgoingTEID() != s1u {
+		t.Errorf("unexpected outgoing TEID: got %#x, want %#x", br.OutgoingTEID(), s1u)
+	}
+	if got, want := br.RemoteAddress().String(), "127.0.0.1:2152"; got != want {
+		t.Errorf("unexpected remote address: got %s, want %s", got, want)
+	}
+	enb, err := s11Sess.GetTEID(v2.IFTypeS1UeNodeBGTPU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.IncomingTEID() != enb {
+		t.Errorf("unexpected incoming TEID: got %#x, want %#x", br.IncomingTEID(), enb)
+	}
+}
+
+func TestTriggerHandoverAndDetach(t *testing.T) {
+	mme, n := setup(t, &mmesim.Config{})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachUE(imsi, "some.apn.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := sess.GetDefaultBearer().IncomingTEID()
+
+	if err := mme.TriggerHandover(imsi, "127.0.0.2"); err != nil {
+		t.Fatal(err)
+	}
+	after := sess.GetDefaultBearer().IncomingTEID()
+	if after == before {
+		t.Errorf("incoming TEID not updated: %#x", after)
+	}
+	s11Sess, err := n.SGW.S11Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teid, _ := s11Sess.GetTEID(v2.IFTypeS1UeNodeBGTPU); teid != after {
+		t.Errorf("eNB TEID mismatch: S-GW: %#x, MME: %#x", teid, after)
+	}
+
+	if err := mme.Detach(imsi); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mme.Conn.GetSessionByIMSI(imsi); err != v2.ErrUnknownIMSI {
+		t.Errorf("session not removed on MME: %v", err)
+	}
+	if _, err := n.PGW.Conn.GetSessionByIMSI(imsi); err != v2.ErrUnknownIMSI {
+		t.Errorf("session not removed on P-GW: %v", err)
+	}
+
+	if err := mme.TriggerHandover(imsi, ""); err != v2.ErrUnknownIMSI {
+		t.Errorf("unexpected error: got %v, want %v", err, v2.ErrUnknownIMSI)
+	}
+}
+
+func TestDeleteOutsideDetach(t *testing.T) {
+	mme, _ := setup(t, &mmesim.Config{})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachUE(imsi, "some.apn.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Delete(mme.Conn, v2.IFTypeS11S4SGWGTPC); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := mme.Conn.GetSessionByIMSI(imsi); err == v2.ErrUnknownIMSI {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("session not removed by Delete Session Response")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSelectPGW(t *testing.T) {
+	errNoPGW := errors.New("no P-GW")
+	mme, _ := setup(t, &mmesim.Config{
+		SelectPGW: func(apn string) (string, error) {
+			if apn != "some.apn.example" {
+				return "", errNoPGW
+			}
+			return "127.0.0.1", nil
+		},
+	})
+
+	if _, err := mme.AttachUE("001010000000001", "some.apn.example"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mme.AttachUE("001010000000002", "other.apn.example"); err != errNoPGW {
+		t.Errorf("unexpected error: got %v, want %v", err, errNoPGW)
+	}
+}
+
+func TestReleaseAccessBearersAndServiceRequest(t *testing.T) {
+	mme, n := setup(t, &mmesim.Config{})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachUE(imsi, "some.apn.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s11Sess, err := n.SGW.S11Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mme.ReleaseAccessBearers(imsi); err != nil {
+		t.Fatal(err)
+	}
+	if sess.IsActive() || s11Sess.IsActive() {
+		t.Errorf("session is active in ECM-IDLE: MME: %v, S-GW: %v", sess.IsActive(), s11Sess.IsActive())
+	}
+	if addr := s11Sess.GetDefaultBearer().RemoteAddress(); addr != nil {
+		t.Errorf("eNB is not released on S-GW: %s", addr)
+	}
+
+	before := sess.GetDefaultBearer().IncomingTEID()
+	if err := mme.ServiceRequest(imsi, "127.0.0.2"); err != nil {
+		t.Fatal(err)
+	}
+	if !sess.IsActive() || !s11Sess.IsActive() {
+		t.Errorf("session is not active after Service Request: MME: %v, S-GW: %v", sess.IsActive(), s11Sess.IsActive())
+	}
+	after := sess.GetDefaultBearer().IncomingTEID()
+	if after == before {
+		t.Errorf("incoming TEID not updated: %#x", after)
+	}
+	if got := s11Sess.GetDefaultBearer().OutgoingTEID(); got != after {
+		t.Errorf("eNB TEID mismatch: S-GW: %#x, MME: %#x", got, after)
+	}
+
+	if err := mme.ReleaseAccessBearers("001010000000002"); err != v2.ErrUnknownIMSI {
+		t.Errorf("unexpected error: got %v, want %v", err, v2.ErrUnknownIMSI)
+	}
+}
+
+func TestAttachWithIP(t *testing.T) {
+	mme, n := setup(t, &mmesim.Config{})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachWithIP(&v2.Subscriber{IMSI: imsi}, "some.apn.example", "10.45.100.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sess.GetDefaultBearer().SubscriberIP; got != "10.45.100.1" {
+		t.Errorf("unexpected subscriber IP: %s", got)
+	}
+	pgwSess, err := n.PGW.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pgwSess.GetDefaultBearer().SubscriberIP; got != "10.45.100.1" {
+		t.Errorf("unexpected subscriber IP on P-GW: %s", got)
+	}
+
+	if _, err := mme.AttachWithIP(&v2.Subscriber{IMSI: imsi}, "some.apn.example", "2001:db8::1"); err == nil {
+		t.Error("expected error for IPv6 address")
+	}
+}
+
+func TestRelocateSGW(t *testing.T) {
+	mme, n := setup(t, &mmesim.Config{ENBIP: "127.0.0.3", EndMarkerWait: 10 * time.Millisecond})
+
+	imsi := "001010000000001"
+	sess, err := mme.AttachUE(imsi, "some.apn.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enbTEID := sess.GetDefaultBearer().IncomingTEID()
+
+	newSGW, err := n.AddSGW()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the unknown subscriber does not stop the relocation of the others.
+	err = mme.RelocateSGW(newSGW.S11Conn.LocalAddr(), "001010000000009", imsi)
+	if !errors.Is(err, v2.ErrUnknownIMSI) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := len(n.SGW.S11Conn.ListSessions()); got != 0 {
+		t.Errorf("sessions left on the old S-GW: %d", got)
+	}
+
+	s11Sess, err := newSGW.S11Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s11Sess.GetDefaultBearer().OutgoingTEID(); got != enbTEID {
+		t.Errorf("eNB TEID not given to the new S-GW: got %#x, want %#x", got, enbTEID)
+	}
+	if got, want := s11Sess.GetDefaultBearer().RemoteAddress().String(), "127.0.0.3:2152"; got != want {
+		t.Errorf("unexpected eNB address: got %s, want %s", got, want)
+	}
+	s1u, err := s11Sess.GetTEID(v2.IFTypeS1USGWGTPU)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sess.GetDefaultBearer().OutgoingTEID(); got != s1u {
+		t.Errorf("S1-U TEID of the new S-GW not updated: got %#x, want %#x", got, s1u)
+	}
+
+	if err := mme.Detach(imsi); err != nil {
+		t.Fatal(err)
+	}
+}
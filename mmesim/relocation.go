@@ -0,0 +1,128 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mmesim
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// RelocateSGW moves the sessions of the subscribers to the S-GW at sgwAddr, as in the
+// MME triggered Serving GW relocation in TS 23.401, e.g., to drain the current S-GW.
+//
+// For each subscriber, Create Session Request is sent to the new S-GW with the F-TEIDs
+// of the P-GW and the eNB in use, so that the new S-GW switches the path on the P-GW
+// with Modify Bearer Request instead of creating a new PDN connection. Once accepted,
+// the session is updated with the TEIDs allocated by the new S-GW, and the session on
+// the old S-GW is deleted with Delete Session Request without the OI flag, not to be
+// deleted on the P-GW. The MME S11 TEID is kept as it is.
+//
+// The sessions are relocated one by one, and the ones failed are left on the old
+// S-GW. The error returned has the errors of all the subscribers failed.
+func (m *MME) RelocateSGW(sgwAddr net.Addr, imsis ...string) error {
+	var errs []error
+	for _, imsi := range imsis {
+		if err := m.relocateSGW(imsi, sgwAddr); err != nil {
+			errs = append(errs, fmt.Errorf("failed to relocate %s: %w", imsi, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MME) relocateSGW(imsi string, sgwAddr net.Addr) error {
+	sess, err := m.Conn.GetSessionByIMSI(imsi)
+	if err != nil {
+		return err
+	}
+	ctx := m.context(imsi)
+	if ctx == nil || ctx.pgwCFTEID == nil || ctx.pgwUFTEID == nil {
+		return ErrNoPGWFTEID
+	}
+	mmeTEID, err := sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	oldTEID, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+	oldAddr := sess.PeerAddr
+
+	br := sess.GetDefaultBearer()
+	brCtx := []*ies.IE{
+		ies.NewEPSBearerID(br.EBI),
+		ctx.pgwUFTEID.WithInstance(3),
+		ies.NewBearerQoS(0, br.PL, 0, br.QCI, br.MBRUL, br.MBRDL, br.GBRUL, br.GBRDL),
+	}
+	// the S1-U path of eNB is not given while the UE is in ECM-IDLE.
+	if enbTEID, err := sess.GetTEID(v2.IFTypeS1UeNodeBGTPU); err == nil && sess.IsActive() {
+		brCtx = append(brCtx, ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, enbTEID, ctx.enbIP, ""))
+	}
+
+	sess.Sequence++
+	msg, err := m.requestTo(sess, sgwAddr, messages.NewCreateSessionRequest(
+		0, sess.Sequence,
+		ies.NewIMSI(imsi),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, mmeTEID, ipOf(m.Conn.LocalAddr()), ""),
+		ctx.pgwCFTEID.WithInstance(1),
+		ies.NewAccessPointName(br.APN),
+		ies.NewPDNType(v2.PDNTypeIPv4),
+		ies.NewPDNAddressAllocation(br.SubscriberIP),
+		ies.NewBearerContext(brCtx...),
+	))
+	if err != nil {
+		return err
+	}
+	csRsp, ok := msg.(*messages.CreateSessionResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	if err := checkCause(csRsp, csRsp.Cause, imsi); err != nil {
+		return err
+	}
+	if csRsp.SenderFTEIDC == nil {
+		return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
+	}
+
+	sess.PeerAddr = sgwAddr
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, csRsp.SenderFTEIDC.TEID())
+	if ie := csRsp.BearerContextsCreated; ie != nil {
+		updateS1USGW(sess, ie)
+	}
+
+	if m.cfg.EndMarkerWait > 0 {
+		time.Sleep(m.cfg.EndMarkerWait)
+	}
+	return m.deleteOnOldSGW(sess, oldAddr, oldTEID)
+}
+
+// deleteOnOldSGW deletes the session relocated on the old S-GW, without the OI flag
+// so that the old S-GW does not delete it on the P-GW.
+func (m *MME) deleteOnOldSGW(sess *v2.Session, sgwAddr net.Addr, sgwTEID uint32) error {
+	m.detaching.Store(sess.IMSI, struct{}{})
+	defer m.detaching.Delete(sess.IMSI)
+
+	sess.Sequence++
+	msg, err := m.requestTo(sess, sgwAddr, messages.NewDeleteSessionRequest(
+		sgwTEID, sess.Sequence,
+		ies.NewEPSBearerID(sess.GetDefaultBearer().EBI),
+		ies.NewIndicationFromOctets(0x00, 0x00),
+	))
+	if err != nil {
+		return err
+	}
+	dsRsp, ok := msg.(*messages.DeleteSessionResponse)
+	if !ok {
+		return v2.ErrUnexpectedType
+	}
+	return checkCause(dsRsp, dsRsp.Cause, sess.IMSI)
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package pgwsel provides the selection of the P-GW for the PDN connections, by the
+// home PLMN of the subscriber derived from IMSI and the APN, typically by S-GW or MME
+// in place of DNS.
+//
+// The subscribers whose IMSI begins with the serving PLMN are the home subscribers,
+// and always connect to the P-GW in the serving PLMN over S5. For the roaming
+// subscribers, each rule decides whether the PDN connection is home-routed to the
+// P-GW in their HPLMN over S8, or broken out locally to the P-GW in the VPLMN.
+//
+//	s, err := pgwsel.New("00101", []*pgwsel.Rule{
+//		{PGW: "10.0.0.1"},
+//		{PLMN: "310260", Mode: pgwsel.HomeRouted, PGW: "192.0.2.1"},
+//		{PLMN: "310260", APN: "ims", Mode: pgwsel.LocalBreakout, PGW: "10.0.0.2"},
+//	})
+//	// ...
+//	route, err := s.Select(imsi, apn)
+//
+// The most specific rule is used for the subscriber: the rule with the longer PLMN
+// wins, and the rule with the APN wins over the one without it for the same PLMN.
+package pgwsel
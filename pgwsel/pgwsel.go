@@ -0,0 +1,187 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pgwsel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNoRoute indicates that no rule matches the subscriber and the APN.
+var ErrNoRoute = errors.New("no P-GW for the subscriber")
+
+// Mode is how the PDN connections of the roaming subscribers are routed.
+type Mode uint8
+
+// Mode definitions.
+const (
+	// HomeRouted routes the PDN connection to the P-GW in HPLMN over S8.
+	HomeRouted Mode = iota
+	// LocalBreakout routes the PDN connection to the P-GW in VPLMN over S5.
+	LocalBreakout
+)
+
+// String returns the name of the Mode.
+func (m Mode) String() string {
+	switch m {
+	case HomeRouted:
+		return "home-routed"
+	case LocalBreakout:
+		return "local-breakout"
+	default:
+		return fmt.Sprintf("Mode(%d)", uint8(m))
+	}
+}
+
+// ParseMode returns the Mode by the name given by String().
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "home-routed":
+		return HomeRouted, nil
+	case "local-breakout":
+		return LocalBreakout, nil
+	default:
+		return 0, fmt.Errorf("unknown mode: %s", s)
+	}
+}
+
+// Rule is a rule to select the P-GW.
+type Rule struct {
+	// PLMN is the home PLMN of the subscribers as MCC and MNC, which is matched with
+	// the beginning of IMSI. Empty matches any subscribers.
+	PLMN string
+	// APN is the APN to match, case-insensitively. Empty matches any APN.
+	APN string
+	// Mode is how to route the PDN connections of the roaming subscribers. It is
+	// ignored for the home subscribers.
+	Mode Mode
+	// PGW is the IP address of the P-GW, optionally with the port(2123 by default).
+	PGW string
+
+	addr *net.UDPAddr
+}
+
+func (r *Rule) matches(imsi, apn string) bool {
+	return strings.HasPrefix(imsi, r.PLMN) && (r.APN == "" || strings.EqualFold(r.APN, apn))
+}
+
+// Route is the P-GW selected for the subscriber.
+type Route struct {
+	// PGW is the C-Plane address of the P-GW.
+	PGW *net.UDPAddr
+	// Roaming is true if the subscriber is not of the serving PLMN.
+	Roaming bool
+	// Mode is how the PDN connection is routed, which is LocalBreakout for the home
+	// subscribers.
+	Mode Mode
+}
+
+// Interface returns the name of the interface toward the P-GW, which is S8 only
+// for the home-routed PDN connections.
+func (r *Route) Interface() string {
+	if r.Roaming && r.Mode == HomeRouted {
+		return "S8"
+	}
+	return "S5"
+}
+
+// Selector selects the P-GW by the rules.
+type Selector struct {
+	servingPLMN string
+	rules       []*Rule
+}
+
+// New creates a new Selector serving the PLMN given as MCC and MNC, with the rules.
+func New(servingPLMN string, rules []*Rule) (*Selector, error) {
+	if !isPLMN(servingPLMN) {
+		return nil, fmt.Errorf("invalid serving PLMN: %q", servingPLMN)
+	}
+
+	s := &Selector{servingPLMN: servingPLMN}
+	for _, r := range rules {
+		if r.PLMN != "" && !isPLMN(r.PLMN) {
+			return nil, fmt.Errorf("invalid PLMN: %q", r.PLMN)
+		}
+
+		addr, err := parseAddr(r.PGW)
+		if err != nil {
+			return nil, fmt.Errorf("invalid P-GW for PLMN %q and APN %q: %w", r.PLMN, r.APN, err)
+		}
+
+		rule := *r
+		rule.addr = addr
+		s.rules = append(s.rules, &rule)
+	}
+
+	// the more specific rules come first.
+	sort.SliceStable(s.rules, func(i, j int) bool {
+		ri, rj := s.rules[i], s.rules[j]
+		if len(ri.PLMN) != len(rj.PLMN) {
+			return len(ri.PLMN) > len(rj.PLMN)
+		}
+		return ri.APN != "" && rj.APN == ""
+	})
+	return s, nil
+}
+
+// Select returns the P-GW for the subscriber of the IMSI to connect to the APN.
+func (s *Selector) Select(imsi, apn string) (*Route, error) {
+	for _, r := range s.rules {
+		if !r.matches(imsi, apn) {
+			continue
+		}
+
+		route := &Route{
+			PGW:     r.addr,
+			Roaming: s.IsRoaming(imsi),
+			Mode:    r.Mode,
+		}
+		if !route.Roaming {
+			route.Mode = LocalBreakout
+		}
+		return route, nil
+	}
+	return nil, fmt.Errorf("%w: IMSI %s, APN %s", ErrNoRoute, imsi, apn)
+}
+
+// IsRoaming reports whether the subscriber of the IMSI is not of the serving PLMN.
+func (s *Selector) IsRoaming(imsi string) bool {
+	return !strings.HasPrefix(imsi, s.servingPLMN)
+}
+
+// parseAddr parses the IP address with the optional port into *net.UDPAddr.
+func parseAddr(s string) (*net.UDPAddr, error) {
+	host, port := s, 2123
+	if h, p, err := net.SplitHostPort(s); err == nil {
+		n, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", s)
+		}
+		host, port = h, int(n)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address: %q", s)
+	}
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
+
+// isPLMN reports whether s is MCC and MNC, which is 5 or 6 digits.
+func isPLMN(s string) bool {
+	if len(s) != 5 && len(s) != 6 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pgwsel_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/pgwsel"
+)
+
+func TestSelect(t *testing.T) {
+	s, err := pgwsel.New("00101", []*pgwsel.Rule{
+		{PGW: "10.0.0.1"},
+		{APN: "internet", PGW: "10.0.0.3:2124"},
+		{PLMN: "310260", Mode: pgwsel.HomeRouted, PGW: "192.0.2.1"},
+		{PLMN: "310260", APN: "IMS", Mode: pgwsel.LocalBreakout, PGW: "10.0.0.2"},
+		{PLMN: "31026", Mode: pgwsel.LocalBreakout, PGW: "10.0.0.4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description, imsi, apn string
+		pgw                    string
+		roaming                bool
+		mode                   pgwsel.Mode
+		iface                  string
+	}{
+		{"home/default", "001010000000001", "some.apn", "10.0.0.1:2123", false, pgwsel.LocalBreakout, "S5"},
+		{"home/apn", "001010000000001", "internet", "10.0.0.3:2124", false, pgwsel.LocalBreakout, "S5"},
+		{"roaming/home-routed", "310260000000001", "internet", "192.0.2.1:2123", true, pgwsel.HomeRouted, "S8"},
+		{"roaming/local-breakout-by-apn", "310260000000001", "ims", "10.0.0.2:2123", true, pgwsel.LocalBreakout, "S5"},
+		{"roaming/shorter-plmn", "310261000000001", "internet", "10.0.0.4:2123", true, pgwsel.LocalBreakout, "S5"},
+		{"roaming/no-rule-for-plmn", "440100000000001", "some.apn", "10.0.0.1:2123", true, pgwsel.HomeRouted, "S8"},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r, err := s.Select(c.imsi, c.apn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.PGW.String(); got != c.pgw {
+				t.Errorf("PGW: got %s, want %s", got, c.pgw)
+			}
+			if r.Roaming != c.roaming {
+				t.Errorf("Roaming: got %v, want %v", r.Roaming, c.roaming)
+			}
+			if r.Mode != c.mode {
+				t.Errorf("Mode: got %s, want %s", r.Mode, c.mode)
+			}
+			if got := r.Interface(); got != c.iface {
+				t.Errorf("Interface: got %s, want %s", got, c.iface)
+			}
+		})
+	}
+}
+
+func TestSelectNoRoute(t *testing.T) {
+	s, err := pgwsel.New("00101", []*pgwsel.Rule{
+		{PLMN: "00101", PGW: "10.0.0.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Select("310260000000001", "internet"); !errors.Is(err, pgwsel.ErrNoRoute) {
+		t.Errorf("got %v, want %v", err, pgwsel.ErrNoRoute)
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		description string
+		servingPLMN string
+		rule        *pgwsel.Rule
+	}{
+		{"invalid-serving-plmn", "0010", &pgwsel.Rule{PGW: "10.0.0.1"}},
+		{"invalid-plmn", "00101", &pgwsel.Rule{PLMN: "31026a", PGW: "10.0.0.1"}},
+		{"invalid-pgw", "00101", &pgwsel.Rule{PGW: "pgw.invalid:2123:1"}},
+		{"no-pgw", "00101", &pgwsel.Rule{}},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if _, err := pgwsel.New(c.servingPLMN, []*pgwsel.Rule{c.rule}); err == nil {
+				t.Error("should fail")
+			}
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, m := range []pgwsel.Mode{pgwsel.HomeRouted, pgwsel.LocalBreakout} {
+		got, err := pgwsel.ParseMode(m.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != m {
+			t.Errorf("got %s, want %s", got, m)
+		}
+	}
+
+	if _, err := pgwsel.ParseMode("unknown"); err == nil {
+		t.Error("should fail")
+	}
+}
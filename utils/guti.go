@@ -0,0 +1,34 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+// GUTIToRAI maps the MME Group ID, MME Code and M-TMSI in GUTI into the LAC and
+// RAC in RAI, P-TMSI and P-TMSI Signature, as defined in TS 23.003 2.8.2.1.
+//
+// The P-TMSI has the bits 31 and 30 set to indicate it is mapped from GUTI, the
+// MME Code in bits 23 to 16 as NRI, and the rest from M-TMSI. The bits 23 to 16 of
+// M-TMSI are carried in the bits 23 to 16 of the P-TMSI Signature.
+func GUTIToRAI(groupID uint16, code uint8, mTMSI uint32) (lac uint16, rac uint8, ptmsi, ptmsiSig uint32) {
+	ptmsi = 0xc0000000 | (mTMSI & 0x3f00ffff) | uint32(code)<<16
+	ptmsiSig = mTMSI & 0x00ff0000
+	return groupID, code, ptmsi, ptmsiSig
+}
+
+// RAIToGUTI maps the LAC in RAI, P-TMSI and P-TMSI Signature into the
+// MME Group ID, MME Code and M-TMSI in GUTI, as defined in TS 23.003 2.8.2.2.
+// This is the reverse of GUTIToRAI.
+//
+// The MME Code is taken from the NRI in the bits 23 to 16 of P-TMSI rather than RAC,
+// and the bits 31 and 30 of M-TMSI are taken from P-TMSI as they are not carried.
+func RAIToGUTI(lac uint16, ptmsi, ptmsiSig uint32) (groupID uint16, code uint8, mTMSI uint32) {
+	mTMSI = (ptmsi & 0xff00ffff) | (ptmsiSig & 0x00ff0000)
+	return lac, uint8(ptmsi >> 16), mTMSI
+}
+
+// IsMappedPTMSI reports whether ptmsi is the one mapped from GUTI with GUTIToRAI,
+// i.e., the bits 31 and 30 are set.
+func IsMappedPTMSI(ptmsi uint32) bool {
+	return ptmsi&0xc0000000 == 0xc0000000
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+func TestGUTIMapping(t *testing.T) {
+	cases := []struct {
+		description      string
+		groupID          uint16
+		code             uint8
+		mTMSI            uint32
+		lac              uint16
+		rac              uint8
+		ptmsi, ptmsiSig  uint32
+		wantMTMSIFromRAI uint32
+	}{
+		{"Normal", 0x8001, 0x01, 0xc0a1b2c3, 0x8001, 0x01, 0xc001b2c3, 0xa10000, 0xc0a1b2c3},
+		{"Bits31and30Lost", 0x1111, 0x22, 0x12345678, 0x1111, 0x22, 0xd2225678, 0x340000, 0xd2345678},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			lac, rac, ptmsi, sig := utils.GUTIToRAI(c.groupID, c.code, c.mTMSI)
+			if lac != c.lac || rac != c.rac || ptmsi != c.ptmsi || sig != c.ptmsiSig {
+				t.Errorf("got %#x/%#x/%#x/%#x, want %#x/%#x/%#x/%#x", lac, rac, ptmsi, sig, c.lac, c.rac, c.ptmsi, c.ptmsiSig)
+			}
+			if !utils.IsMappedPTMSI(ptmsi) {
+				t.Errorf("%#x is not reported as mapped", ptmsi)
+			}
+
+			groupID, code, mTMSI := utils.RAIToGUTI(lac, ptmsi, sig)
+			if groupID != c.groupID || code != c.code || mTMSI != c.wantMTMSIFromRAI {
+				t.Errorf("got %#x/%#x/%#x, want %#x/%#x/%#x", groupID, code, mTMSI, c.groupID, c.code, c.wantMTMSIFromRAI)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Errors in validating the identities.
+var (
+	ErrInvalidDigits     = errors.New("identity contains non-digit characters")
+	ErrInvalidLength     = errors.New("identity has invalid number of digits")
+	ErrInvalidCheckDigit = errors.New("IMEI has invalid check digit")
+	ErrUnknownMCC        = errors.New("unknown MCC")
+)
+
+// IdentityValidation is how strictly the identities given to the IE constructors,
+// e.g., NewIMSI, are validated. The constructors return nil if the validation fails.
+type IdentityValidation uint32
+
+// IdentityValidation definitions.
+const (
+	// IdentityValidationDigits accepts the identities consisting only of the digits,
+	// regardless of the length. This is the default.
+	IdentityValidationDigits IdentityValidation = iota
+	// IdentityValidationStrict accepts the identities that pass ValidateIMSI for IMSI
+	// and ValidateMEI for IMEI(SV).
+	IdentityValidationStrict
+	// IdentityValidationNone accepts anything, which is useful to craft the test traffic
+	// with the malformed identities. The characters other than the hex digits are still
+	// rejected as they cannot be encoded.
+	IdentityValidationNone
+)
+
+var identityValidation atomic.Uint32
+
+// SetIdentityValidation sets how strictly the identities given to the IE constructors
+// are validated, process-wide.
+func SetIdentityValidation(v IdentityValidation) {
+	identityValidation.Store(uint32(v))
+}
+
+// CheckIMSI validates imsi as the IdentityValidation set with SetIdentityValidation.
+// This is used by the IE constructors.
+func CheckIMSI(imsi string) error {
+	return checkIdentity(imsi, ValidateIMSI)
+}
+
+// CheckMEI validates mei (IMEI or IMEISV) as the IdentityValidation set with
+// SetIdentityValidation. This is used by the IE constructors.
+func CheckMEI(mei string) error {
+	return checkIdentity(mei, ValidateMEI)
+}
+
+func checkIdentity(s string, strict func(string) error) error {
+	switch IdentityValidation(identityValidation.Load()) {
+	case IdentityValidationNone:
+		return nil
+	case IdentityValidationStrict:
+		return strict(s)
+	default:
+		return validateDigits(s)
+	}
+}
+
+func validateDigits(s string) error {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return ErrInvalidDigits
+		}
+	}
+	return nil
+}
+
+// ValidateIMSI validates imsi has 6 to 15 digits, i.e., 3-digit MCC, 2 or 3-digit MNC
+// and at least 1-digit MSIN.
+func ValidateIMSI(imsi string) error {
+	if err := validateDigits(imsi); err != nil {
+		return err
+	}
+	if len(imsi) < 6 || len(imsi) > 15 {
+		return ErrInvalidLength
+	}
+	return nil
+}
+
+// ValidateIMEI validates imei has 15 digits with the valid check digit at the end.
+func ValidateIMEI(imei string) error {
+	if err := validateDigits(imei); err != nil {
+		return err
+	}
+	if len(imei) != 15 {
+		return ErrInvalidLength
+	}
+	if cd, _ := LuhnCheckDigit(imei[:14]); cd != imei[14] {
+		return ErrInvalidCheckDigit
+	}
+	return nil
+}
+
+// ValidateIMEISV validates imeisv has 16 digits, i.e., 14-digit IMEI without the
+// check digit and 2-digit software version number.
+func ValidateIMEISV(imeisv string) error {
+	if err := validateDigits(imeisv); err != nil {
+		return err
+	}
+	if len(imeisv) != 16 {
+		return ErrInvalidLength
+	}
+	return nil
+}
+
+// ValidateMEI validates mei as IMEI if it has 15 digits, or IMEISV otherwise.
+func ValidateMEI(mei string) error {
+	if len(mei) == 15 {
+		return ValidateIMEI(mei)
+	}
+	return ValidateIMEISV(mei)
+}
+
+// LuhnCheckDigit returns the check digit of digits computed with the Luhn algorithm,
+// e.g., the 15th digit of IMEI for the first 14 digits.
+func LuhnCheckDigit(digits string) (byte, error) {
+	if err := validateDigits(digits); err != nil {
+		return 0, err
+	}
+
+	var sum int
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		// double every other digit from the rightmost one, as the check digit is
+		// appended to the right.
+		if i%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// SplitIMSI splits imsi into MCC, MNC and MSIN. The length of MNC is looked up from
+// the MCC table bundled, and ErrUnknownMCC is returned for the MCC not in the table.
+func SplitIMSI(imsi string) (mcc, mnc, msin string, err error) {
+	if err := ValidateIMSI(imsi); err != nil {
+		return "", "", "", err
+	}
+
+	mcc = imsi[:3]
+	info, ok := LookupMCC(mcc)
+	if !ok {
+		return "", "", "", ErrUnknownMCC
+	}
+	return mcc, imsi[3 : 3+info.MNCLength], imsi[3+info.MNCLength:], nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+func TestValidateIdentities(t *testing.T) {
+	cases := []struct {
+		description string
+		validate    func(string) error
+		str         string
+		err         error
+	}{
+		{"IMSI/Normal", utils.ValidateIMSI, "310150123456789", nil},
+		{"IMSI/Short", utils.ValidateIMSI, "31015", utils.ErrInvalidLength},
+		{"IMSI/Long", utils.ValidateIMSI, "3101501234567890", utils.ErrInvalidLength},
+		{"IMSI/NonDigit", utils.ValidateIMSI, "31015012345678a", utils.ErrInvalidDigits},
+		{"IMEI/Normal", utils.ValidateIMEI, "490154203237518", nil},
+		{"IMEI/CheckDigit", utils.ValidateIMEI, "490154203237519", utils.ErrInvalidCheckDigit},
+		{"IMEI/Short", utils.ValidateIMEI, "49015420323751", utils.ErrInvalidLength},
+		{"IMEISV/Normal", utils.ValidateIMEISV, "4901542032375101", nil},
+		{"IMEISV/Short", utils.ValidateIMEISV, "490154203237510", utils.ErrInvalidLength},
+		{"MEI/IMEI", utils.ValidateMEI, "490154203237518", nil},
+		{"MEI/IMEISV", utils.ValidateMEI, "4901542032375101", nil},
+		{"MEI/CheckDigit", utils.ValidateMEI, "490154203237519", utils.ErrInvalidCheckDigit},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if err := c.validate(c.str); !errors.Is(err, c.err) {
+				t.Errorf("got %v, want %v", err, c.err)
+			}
+		})
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	cases := []struct {
+		digits string
+		cd     byte
+	}{
+		{"49015420323751", '8'},
+		{"35209900176148", '1'},
+		{"00000000000000", '0'},
+	}
+
+	for _, c := range cases {
+		t.Run(c.digits, func(t *testing.T) {
+			cd, err := utils.LuhnCheckDigit(c.digits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cd != c.cd {
+				t.Errorf("got %c, want %c", cd, c.cd)
+			}
+		})
+	}
+}
+
+func TestSplitIMSI(t *testing.T) {
+	cases := []struct {
+		description    string
+		imsi           string
+		mcc, mnc, msin string
+		err            error
+	}{
+		{"3-digit MNC", "310150123456789", "310", "150", "123456789", nil},
+		{"2-digit MNC", "440101234567890", "440", "10", "1234567890", nil},
+		{"Test network", "001011234567890", "001", "01", "1234567890", nil},
+		{"Unknown MCC", "123451234567890", "", "", "", utils.ErrUnknownMCC},
+		{"Invalid", "12345", "", "", "", utils.ErrInvalidLength},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mcc, mnc, msin, err := utils.SplitIMSI(c.imsi)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("got %v, want %v", err, c.err)
+			}
+			if mcc != c.mcc || mnc != c.mnc || msin != c.msin {
+				t.Errorf("got %s/%s/%s, want %s/%s/%s", mcc, mnc, msin, c.mcc, c.mnc, c.msin)
+			}
+		})
+	}
+}
+
+func TestIdentityValidation(t *testing.T) {
+	defer utils.SetIdentityValidation(utils.IdentityValidationDigits)
+
+	cases := []struct {
+		description string
+		validation  utils.IdentityValidation
+		imsi, mei   string
+		ok          bool
+	}{
+		{"Digits/Normal", utils.IdentityValidationDigits, "123451234567890", "123450123456789", true},
+		{"Digits/NonDigit", utils.IdentityValidationDigits, "12345123456789a", "12345012345678a", false},
+		{"Strict/Normal", utils.IdentityValidationStrict, "310150123456789", "490154203237518", true},
+		{"Strict/Invalid", utils.IdentityValidationStrict, "12345", "123450123456789", false},
+		{"None/NonDigit", utils.IdentityValidationNone, "12345123456789a", "12345", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			utils.SetIdentityValidation(c.validation)
+			if err := utils.CheckIMSI(c.imsi); (err == nil) != c.ok {
+				t.Errorf("CheckIMSI(%s): got %v", c.imsi, err)
+			}
+			if err := utils.CheckMEI(c.mei); (err == nil) != c.ok {
+				t.Errorf("CheckMEI(%s): got %v", c.mei, err)
+			}
+		})
+	}
+}
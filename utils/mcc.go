@@ -0,0 +1,218 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+// MCCInfo is the information of a Mobile Country Code.
+type MCCInfo struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country, or "XX" for the MCCs
+	// not assigned to a country, e.g., 001 for the test networks.
+	Country string
+	// MNCLength is the number of digits of the MNCs used with the MCC. For the MCCs
+	// with both 2 and 3-digit MNCs, the one used by the most of the networks is set.
+	MNCLength int
+}
+
+// LookupMCC returns the MCCInfo of mcc in the MCC table bundled.
+func LookupMCC(mcc string) (MCCInfo, bool) {
+	info, ok := mccTable[mcc]
+	return info, ok
+}
+
+// mccTable is the MCCs in ITU-T E.212 in use for the public networks, plus the ones
+// for the test networks(001), the international networks(901) and the private
+// networks(999).
+var mccTable = map[string]MCCInfo{
+	"001": {"XX", 2},
+	"202": {"GR", 2},
+	"204": {"NL", 2},
+	"206": {"BE", 2},
+	"208": {"FR", 2},
+	"212": {"MC", 2},
+	"213": {"AD", 2},
+	"214": {"ES", 2},
+	"216": {"HU", 2},
+	"218": {"BA", 2},
+	"219": {"HR", 2},
+	"220": {"RS", 2},
+	"222": {"IT", 2},
+	"226": {"RO", 2},
+	"228": {"CH", 2},
+	"230": {"CZ", 2},
+	"231": {"SK", 2},
+	"232": {"AT", 2},
+	"234": {"GB", 2},
+	"235": {"GB", 2},
+	"238": {"DK", 2},
+	"240": {"SE", 2},
+	"242": {"NO", 2},
+	"244": {"FI", 2},
+	"246": {"LT", 2},
+	"247": {"LV", 2},
+	"248": {"EE", 2},
+	"250": {"RU", 2},
+	"255": {"UA", 2},
+	"257": {"BY", 2},
+	"259": {"MD", 2},
+	"260": {"PL", 2},
+	"262": {"DE", 2},
+	"266": {"GI", 2},
+	"268": {"PT", 2},
+	"270": {"LU", 2},
+	"272": {"IE", 2},
+	"274": {"IS", 2},
+	"276": {"AL", 2},
+	"278": {"MT", 2},
+	"280": {"CY", 2},
+	"282": {"GE", 2},
+	"283": {"AM", 2},
+	"284": {"BG", 2},
+	"286": {"TR", 2},
+	"288": {"FO", 2},
+	"290": {"GL", 2},
+	"293": {"SI", 2},
+	"294": {"MK", 2},
+	"295": {"LI", 2},
+	"297": {"ME", 2},
+	"302": {"CA", 3},
+	"308": {"PM", 2},
+	"310": {"US", 3},
+	"311": {"US", 3},
+	"312": {"US", 3},
+	"313": {"US", 3},
+	"314": {"US", 3},
+	"315": {"US", 3},
+	"316": {"US", 3},
+	"330": {"PR", 3},
+	"334": {"MX", 3},
+	"338": {"JM", 3},
+	"340": {"GP", 2},
+	"342": {"BB", 3},
+	"344": {"AG", 3},
+	"346": {"KY", 3},
+	"348": {"VG", 3},
+	"350": {"BM", 2},
+	"352": {"GD", 3},
+	"354": {"MS", 3},
+	"356": {"KN", 3},
+	"358": {"LC", 3},
+	"360": {"VC", 3},
+	"362": {"CW", 2},
+	"363": {"AW", 2},
+	"364": {"BS", 2},
+	"365": {"AI", 3},
+	"366": {"DM", 3},
+	"368": {"CU", 2},
+	"370": {"DO", 2},
+	"372": {"HT", 2},
+	"374": {"TT", 2},
+	"376": {"TC", 3},
+	"400": {"AZ", 2},
+	"401": {"KZ", 2},
+	"402": {"BT", 2},
+	"404": {"IN", 2},
+	"405": {"IN", 2},
+	"410": {"PK", 2},
+	"412": {"AF", 2},
+	"413": {"LK", 2},
+	"414": {"MM", 2},
+	"415": {"LB", 2},
+	"416": {"JO", 2},
+	"417": {"SY", 2},
+	"418": {"IQ", 2},
+	"419": {"KW", 2},
+	"420": {"SA", 2},
+	"421": {"YE", 2},
+	"422": {"OM", 2},
+	"424": {"AE", 2},
+	"425": {"IL", 2},
+	"426": {"BH", 2},
+	"427": {"QA", 2},
+	"428": {"MN", 2},
+	"429": {"NP", 2},
+	"432": {"IR", 2},
+	"434": {"UZ", 2},
+	"436": {"TJ", 2},
+	"437": {"KG", 2},
+	"438": {"TM", 2},
+	"440": {"JP", 2},
+	"441": {"JP", 2},
+	"450": {"KR", 2},
+	"452": {"VN", 2},
+	"454": {"HK", 2},
+	"455": {"MO", 2},
+	"456": {"KH", 2},
+	"457": {"LA", 2},
+	"460": {"CN", 2},
+	"466": {"TW", 2},
+	"467": {"KP", 2},
+	"470": {"BD", 2},
+	"472": {"MV", 2},
+	"502": {"MY", 2},
+	"505": {"AU", 2},
+	"510": {"ID", 2},
+	"514": {"TL", 2},
+	"515": {"PH", 2},
+	"520": {"TH", 2},
+	"525": {"SG", 2},
+	"528": {"BN", 2},
+	"530": {"NZ", 2},
+	"537": {"PG", 2},
+	"539": {"TO", 2},
+	"540": {"SB", 2},
+	"541": {"VU", 2},
+	"542": {"FJ", 2},
+	"602": {"EG", 2},
+	"603": {"DZ", 2},
+	"604": {"MA", 2},
+	"605": {"TN", 2},
+	"606": {"LY", 2},
+	"608": {"SN", 2},
+	"612": {"CI", 2},
+	"614": {"NE", 2},
+	"618": {"LR", 2},
+	"620": {"GH", 2},
+	"621": {"NG", 2},
+	"624": {"CM", 2},
+	"630": {"CD", 2},
+	"633": {"SC", 2},
+	"634": {"SD", 2},
+	"636": {"ET", 2},
+	"639": {"KE", 2},
+	"640": {"TZ", 2},
+	"641": {"UG", 2},
+	"642": {"BI", 2},
+	"643": {"MZ", 2},
+	"645": {"ZM", 2},
+	"646": {"MG", 2},
+	"647": {"RE", 2},
+	"648": {"ZW", 2},
+	"649": {"NA", 2},
+	"650": {"MW", 2},
+	"652": {"BW", 2},
+	"655": {"ZA", 2},
+	"659": {"SS", 2},
+	"702": {"BZ", 2},
+	"704": {"GT", 2},
+	"706": {"SV", 2},
+	"708": {"HN", 3},
+	"710": {"NI", 3},
+	"712": {"CR", 2},
+	"714": {"PA", 3},
+	"716": {"PE", 2},
+	"722": {"AR", 3},
+	"724": {"BR", 2},
+	"730": {"CL", 2},
+	"732": {"CO", 3},
+	"734": {"VE", 2},
+	"736": {"BO", 2},
+	"738": {"GY", 2},
+	"740": {"EC", 2},
+	"744": {"PY", 2},
+	"746": {"SR", 2},
+	"748": {"UY", 2},
+	"750": {"FK", 3},
+	"901": {"XX", 2},
+	"999": {"XX", 2},
+}
@@ -7,8 +7,12 @@ package utils
 
 import (
 	"encoding/hex"
+	"errors"
 )
 
+// ErrTooShortToDecode indicates the given bytes are too short to decode.
+var ErrTooShortToDecode = errors.New("too short to decode")
+
 // StrToSwappedBytes returns swapped bits from a byte.
 // It is used for some values where some values are represented in swapped format.
 //
@@ -35,7 +39,7 @@ func StrToSwappedBytes(s, filler string) ([]byte, error) {
 // The second parameter is to decide whether to cut the last digit or not.
 func SwappedBytesToStr(raw []byte, cutLastDigit bool) string {
 	s := hex.EncodeToString(swap(raw))
-	if cutLastDigit {
+	if cutLastDigit && len(s) > 0 {
 		s = s[:len(s)-1]
 	}
 
@@ -109,6 +113,9 @@ func EncodePLMN(mcc, mnc string) ([]byte, error) {
 
 // DecodePLMN decodes BCD-encoded bytes into MCC and MNC.
 func DecodePLMN(b []byte) (mcc, mnc string, err error) {
+	if len(b) < 3 {
+		return "", "", ErrTooShortToDecode
+	}
 	raw := hex.EncodeToString(b)
 	mcc = string(raw[1]) + string(raw[0]) + string(raw[3])
 	mnc = string(raw[5]) + string(raw[4])
@@ -0,0 +1,612 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+)
+
+// Conn represents a GTPv0 connection.
+//
+// In GTPv0, both signalling messages and T-PDUs are exchanged over the same port(3386).
+// The T-PDUs can be read by ReadFromGTP, or relayed to another peer by RelayTo.
+type Conn struct {
+	mu      sync.Mutex
+	pktConn net.PacketConn
+	*msgHandlerMap
+
+	validationEnabled bool
+
+	rcvBuf  []byte
+	tpduCh  chan *tpduSet
+	closeCh chan struct{}
+	errCh   chan error
+
+	sessMu sync.RWMutex
+
+	relayMu  sync.RWMutex
+	relayMap map[uint16]*peer
+
+	sendBufs sendbuf.Buffers
+
+	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
+	// times the GTPv0 endpoint is restarted.
+	RestartCounter uint8
+
+	// Sessions is a set of sessions exists on the Conn.
+	// Use AddSession and RemoveSession to modify it.
+	Sessions []*Session
+}
+
+func newConn(pktConn net.PacketConn, counter uint8, errCh chan error) *Conn {
+	return &Conn{
+		mu:                sync.Mutex{},
+		pktConn:           pktConn,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		validationEnabled: true,
+
+		rcvBuf: make([]byte, 2048),
+		tpduCh: make(chan *tpduSet),
+
+		closeCh: make(chan struct{}),
+		errCh:   errCh,
+
+		RestartCounter: counter,
+	}
+}
+
+// Dial sends Echo Request to raddr to check if the endpoint is alive and returns *Conn.
+//
+// Dial does not actually Dial() remote address so that the *Conn can be used with
+// multiple source/destination address.
+//
+// The errCh given should be monitored continuously after retrieving *Conn.
+// Otherwise the background process may get stuck.
+func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	pktConn, err := net.ListenPacket(raddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(pktConn, counter, errCh)
+
+	// if no response coming within 3 seconds, returns error without retrying.
+	if err := c.pktConn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		return nil, err
+	}
+	if err := c.EchoRequest(raddr); err != nil {
+		return nil, err
+	}
+	for {
+		n, _, err := c.pktConn.ReadFrom(c.rcvBuf)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := messages.Decode(c.rcvBuf[:n])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := msg.(*messages.EchoResponse); ok {
+			break
+		}
+	}
+	if err := c.pktConn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	go c.serve()
+	return c, nil
+}
+
+// NewConn creates a new Conn over existing net.PacketConn and start serving.
+//
+// This is for special situation that the user already have a net.PacketConn to be used for
+// GTPv0 connection. Otherwise, Dial() or ListenAndServe() should be used.
+func NewConn(pktConn net.PacketConn, counter uint8, errCh chan error) *Conn {
+	c := newConn(pktConn, counter, errCh)
+
+	go c.serve()
+	return c
+}
+
+// ListenAndServe creates a new GTPv0 *Conn and start serving.
+//
+// The errCh given should be monitored continuously after retrieving *Conn.
+// Otherwise the background process may get stuck.
+func ListenAndServe(laddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	pktConn, err := net.ListenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	c := newConn(pktConn, counter, errCh)
+
+	go c.serve()
+	return c, nil
+}
+
+// closed would be used in multiple goroutines.
+// never send struct{}{} to it; instead, use close(c.closeCh).
+func (c *Conn) closed() <-chan struct{} {
+	return c.closeCh
+}
+
+func (c *Conn) serve() {
+	for {
+		select {
+		case <-c.closed():
+			return
+		default:
+			// do nothing and go forward.
+		}
+
+		n, raddr, err := c.pktConn.ReadFrom(c.rcvBuf)
+		if err != nil {
+			continue
+		}
+
+		// the message is passed to the handler running in another goroutine, so
+		// the IEs must not refer to rcvBuf which is overwritten by the next read.
+		b := make([]byte, n)
+		copy(b, c.rcvBuf[:n])
+
+		msg, err := messages.Decode(b)
+		if err != nil {
+			continue
+		}
+
+		// just forward T-PDU instead of passing it to reader
+		// if relayer is configured for the Flow Label.
+		if msg.MessageType() == messages.MsgTypeTPDU && c.hasRelay() {
+			relayed, err := c.relay(msg.Label(), b)
+			if err != nil {
+				go func() {
+					c.errCh <- err
+				}()
+			}
+			if relayed {
+				continue
+			}
+		}
+
+		if err := c.handleMessage(raddr, msg); err != nil {
+			// errors should be handled by user
+			go func() {
+				c.errCh <- err
+			}()
+		}
+	}
+}
+
+// ReadFrom reads a packet from the connection,
+// copying the payload into p. It returns the number of
+// bytes copied into p and the return address that
+// was on the packet.
+// It returns the number of bytes read (0 <= n <= len(p))
+// and any error encountered. Callers should always process
+// the n > 0 bytes returned before considering the error err.
+// ReadFrom can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetReadDeadline.
+func (c *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	return c.pktConn.ReadFrom(p)
+}
+
+// WriteTo writes a packet with payload p to addr.
+// WriteTo can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetWriteDeadline.
+// On packet-oriented connections, write timeouts are rare.
+func (c *Conn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	return c.pktConn.WriteTo(p, addr)
+}
+
+// Close closes the connection.
+// Any blocked Read or Write operations will be unblocked and return errors.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.RestartCounter = 0
+	c.sendBufs.Reset()
+	close(c.closeCh)
+
+	// unblocks Read() in serve() and releases the address.
+	return c.pktConn.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.pktConn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines associated
+// with the connection. It is equivalent to calling both
+// SetReadDeadline and SetWriteDeadline.
+//
+// A deadline is an absolute time after which I/O operations
+// fail with a timeout (see type Error) instead of
+// blocking. The deadline applies to all future and pending
+// I/O, not just the immediately following call to Read or
+// Write. After a deadline has been exceeded, the connection
+// can be refreshed by setting a deadline in the future.
+//
+// An idle timeout can be implemented by repeatedly extending
+// the deadline after successful Read or Write calls.
+//
+// A zero value for t means I/O operations will not time out.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.pktConn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for future Read calls
+// and any currently-blocked Read call.
+// A zero value for t means Read will not time out.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.pktConn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for future Write calls
+// and any currently-blocked Write call.
+// Even if write times out, it may return n > 0, indicating that
+// some of the data was successfully written.
+// A zero value for t means Write will not time out.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.pktConn.SetWriteDeadline(t)
+}
+
+// AddHandler adds a message handler to *Conn.
+//
+// By adding HandlerFuncs, *Conn will handle the specified type of message with
+// it's paired HandlerFunc when receiving. Messages without registered handlers
+// are just ignored and discarded and the user will get ErrNoHandlersFound error.
+//
+// HandlerFuncs for EchoRequest, EchoResponse, ErrorIndication and T-PDU are registered
+// by default. These HandlerFuncs can be overwritten by specifying their message
+// types as msgType parameter. Note that ReadFromGTP no longer works if the HandlerFunc
+// for T-PDU is overwritten.
+func (c *Conn) AddHandler(msgType uint8, fn HandlerFunc) {
+	c.msgHandlerMap.store(msgType, fn)
+}
+
+// AddHandlers adds multiple handler funcs at a time.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) AddHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		c.msgHandlerMap.store(msgType, fn)
+	}
+}
+
+func (c *Conn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	c.mu.Lock()
+	validationEnabled := c.validationEnabled
+	c.mu.Unlock()
+
+	if validationEnabled {
+		if err := c.validate(senderAddr, msg); err != nil {
+			return err
+		}
+	}
+
+	handle, ok := c.msgHandlerMap.load(msg.MessageType())
+	if !ok {
+		return ErrNoHandlersFound
+	}
+	go func() {
+		if err := handle(c, senderAddr, msg); err != nil {
+			c.errCh <- err
+		}
+	}()
+
+	return nil
+}
+
+// EnableValidation turns on automatic validation of incoming messages.
+// This is expected to be used only after DisableValidation() is used, as the validation
+// is enabled by default.
+func (c *Conn) EnableValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validationEnabled = true
+}
+
+// DisableValidation turns off automatic validation of incoming messages.
+// It is not recommended to use this except the node is in debugging mode.
+func (c *Conn) DisableValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validationEnabled = false
+}
+
+func (c *Conn) validate(senderAddr net.Addr, msg messages.Message) error {
+	// Error Indication has the Flow Label of the T-PDU sent from this endpoint,
+	// which is the one allocated by the peer.
+	if msg.MessageType() == messages.MsgTypeErrorIndication {
+		return nil
+	}
+
+	// check if Flow Label is known or not.
+	// the Flow Label is zero only in the messages that create a new PDP context
+	// or belong to no PDP context.
+	if label := msg.Label(); label != 0 {
+		if _, err := c.GetSessionByFlowLabel(label); err != nil {
+			// the peer should be notified that the PDP context for the T-PDU does not exist.
+			if msg.MessageType() == messages.MsgTypeTPDU {
+				if err := c.ErrorIndication(senderAddr, msg); err != nil {
+					return err
+				}
+			}
+			return ErrUnknownFlowLabel
+		}
+	}
+	return nil
+}
+
+// EchoRequest sends a EchoRequest.
+func (c *Conn) EchoRequest(raddr net.Addr) error {
+	return c.SendMessageTo(messages.NewEchoRequest(0, 0, 0, ies.NewRecovery(c.RestartCounter)), raddr)
+}
+
+// EchoResponse sends a EchoResponse.
+func (c *Conn) EchoResponse(raddr net.Addr) error {
+	return c.SendMessageTo(messages.NewEchoResponse(0, 0, 0, ies.NewRecovery(c.RestartCounter)), raddr)
+}
+
+// ErrorIndication just sends ErrorIndication message in response to the T-PDU received.
+//
+// The Flow Label and TID in the header are the same as the ones in the T-PDU, so that
+// the peer can identify the PDP context.
+func (c *Conn) ErrorIndication(raddr net.Addr, received messages.Message) error {
+	return c.RespondTo(
+		raddr, received, messages.NewErrorIndication(0, received.Label(), received.RawTID()),
+	)
+}
+
+// CreatePDPContext sends a CreatePDPContextRequest with TID and IEs given, and
+// stores information given with IE in the Session returned.
+//
+// The Flow Labels given with Flow Label Data I and Flow Label Signalling IEs are
+// stored in the Session as the ones of SGSN, as this is sent by SGSN.
+//
+// The Session returned is not added to the Conn. Use AddSession to let the Conn
+// find it when the response comes.
+func (c *Conn) CreatePDPContext(raddr net.Addr, tid uint64, ie ...*ies.IE) (*Session, error) {
+	// retrieve values from IEs given.
+	sess := NewSession(raddr, tid)
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.MSISDN:
+			sess.MSISDN = i.MSISDN()
+		case ies.AccessPointName:
+			sess.APN = i.AccessPointName()
+		case ies.QualityOfServiceProfile:
+			sess.QoSProfile = i.QualityOfServiceProfile()
+		case ies.FlowLabelSignalling:
+			sess.AddFlowLabel(IFTypeSGSNSignalling, i.FlowLabelSignalling())
+		case ies.FlowLabelDataI:
+			sess.AddFlowLabel(IFTypeSGSNDataI, i.FlowLabelDataI())
+		}
+	}
+
+	// the Flow Label is zero as the GGSN has not allocated any yet.
+	if err := c.SendMessageTo(messages.NewCreatePDPContextRequest(sess.Sequence, 0, tid, ie...), raddr); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// UpdatePDPContext sends a UpdatePDPContextRequest with TID and IEs given.
+//
+// The Flow Label in the header is the one registered in the Session for ifType,
+// which should be the one allocated by the peer.
+func (c *Conn) UpdatePDPContext(tid uint64, ifType uint8, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTID(tid)
+	if err != nil {
+		return err
+	}
+	label, err := sess.GetFlowLabel(ifType)
+	if err != nil {
+		return err
+	}
+
+	msg := messages.NewUpdatePDPContextRequest(sess.Sequence+1, label, tid, ie...)
+	if err := c.SendMessageTo(msg, sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// DeletePDPContext sends a DeletePDPContextRequest with TID and IEs given.
+//
+// The Flow Label in the header is the one registered in the Session for ifType,
+// which should be the one allocated by the peer.
+func (c *Conn) DeletePDPContext(tid uint64, ifType uint8, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTID(tid)
+	if err != nil {
+		return err
+	}
+	label, err := sess.GetFlowLabel(ifType)
+	if err != nil {
+		return err
+	}
+
+	msg := messages.NewDeletePDPContextRequest(sess.Sequence+1, label, tid, ie...)
+	if err := c.SendMessageTo(msg, sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// RespondTo sends a message(specified with "toBeSent" param) in response to
+// a message(specified with "received" param).
+//
+// This is to make it easier to handle SequenceNumber.
+func (c *Conn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
+	toBeSent.SetSequenceNumber(received.Sequence())
+	return c.SendMessageTo(toBeSent, raddr)
+}
+
+// SendMessageTo serializes the message and writes it to addr.
+//
+// The message is serialized into the buffer kept for each addr, which is reused
+// for the messages sent to the same addr afterwards, instead of allocating a new
+// one for each message. The Payload of msg is copied out of the buffer.
+func (c *Conn) SendMessageTo(msg messages.Message, addr net.Addr) error {
+	buf := c.sendBufs.Get(addr)
+	buf.Lock()
+	defer buf.Unlock()
+
+	b := buf.Bytes(msg.Len())
+	if err := msg.SerializeTo(b); err != nil {
+		return err
+	}
+	messages.DetachPayload(msg)
+
+	if _, err := c.WriteTo(b, addr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restarts returns the number of restarts in uint8.
+func (c *Conn) Restarts() uint8 {
+	return c.RestartCounter
+}
+
+// GetSessionByTID returns the current session looked up by TID.
+func (c *Conn) GetSessionByTID(tid uint64) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	for _, sess := range c.Sessions {
+		if tid == sess.TID {
+			return sess, nil
+		}
+	}
+
+	return nil, ErrUnknownTID
+}
+
+// GetSessionByFlowLabel returns the current session looked up by Flow Label.
+// The Flow Label of any interface registered in the Session matches.
+func (c *Conn) GetSessionByFlowLabel(label uint16) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	for _, sess := range c.Sessions {
+		found := false
+		sess.flowLabelMap.rangeWithFunc(func(i, l interface{}) bool {
+			if label == l {
+				found = true
+				return false
+			}
+			return true
+		})
+		if found {
+			return sess, nil
+		}
+	}
+
+	return nil, ErrUnknownFlowLabel
+}
+
+// GetSessionByIMSI returns the first session found with the IMSI given.
+// A subscriber may have multiple sessions with different NSAPIs.
+func (c *Conn) GetSessionByIMSI(imsi string) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	for _, sess := range c.Sessions {
+		if imsi == sess.IMSI {
+			return sess, nil
+		}
+	}
+
+	return nil, ErrUnknownIMSI
+}
+
+// AddSession adds a session to c.Sessions.
+// If the session with the same TID already exists, this replaces the old one.
+func (c *Conn) AddSession(session *Session) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+
+	for i, oldSession := range c.Sessions {
+		if session.TID == oldSession.TID {
+			c.Sessions[i] = session
+			return
+		}
+	}
+	c.Sessions = append(c.Sessions, session)
+}
+
+// RemoveSession removes a session from c.Sessions.
+func (c *Conn) RemoveSession(session *Session) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+
+	var newSessions []*Session
+	for _, sess := range c.Sessions {
+		if session.TID == sess.TID {
+			continue
+		}
+		newSessions = append(newSessions, sess)
+	}
+
+	c.Sessions = newSessions
+}
+
+// NewFlowLabel returns a random non-zero Flow Label value that is different from
+// the ones registered for ifType in existing Sessions.
+// If there's a lot of Session on the Conn, it may take a long time to find unique one.
+func (c *Conn) NewFlowLabel(ifType uint8) uint16 {
+	c.sessMu.RLock()
+	var labels []uint16
+	for _, sess := range c.Sessions {
+		if label, ok := sess.flowLabelMap.load(ifType); ok {
+			labels = append(labels, label)
+		}
+	}
+	c.sessMu.RUnlock()
+
+	return generateUniqueUint16(labels)
+}
+
+func generateUniqueUint16(vals []uint16) uint16 {
+	b := make([]byte, 2)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			return 0
+		}
+
+		generated := binary.BigEndian.Uint16(b)
+		if generated == 0 {
+			continue
+		}
+		unique := true
+		for _, existing := range vals {
+			if generated == existing {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return generated
+		}
+	}
+}
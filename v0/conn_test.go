@@ -0,0 +1,177 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	v0 "github.com/wmnsk/go-gtp/v0"
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+)
+
+func setupConn(errCh chan error) (cliConn, srvConn *v0.Conn, err error) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.85:3386")
+	if err != nil {
+		return nil, nil, err
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.86:3386")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	srvConn, err = v0.ListenAndServe(srvAddr, 0, errCh)
+	if err != nil {
+		return nil, nil, err
+	}
+	srvConn.AddHandlers(map[uint8]v0.HandlerFunc{
+		messages.MsgTypeCreatePDPContextRequest: func(c *v0.Conn, cliAddr net.Addr, msg messages.Message) error {
+			req := msg.(*messages.CreatePDPContextRequest)
+			if req.FlowLabelSignalling == nil {
+				return &v0.ErrRequiredIEMissing{Type: ies.FlowLabelSignalling}
+			}
+
+			sess := v0.NewSession(cliAddr, req.RawTID())
+			if sess.IMSI != "123451234567890" {
+				return errors.Errorf("unexpected IMSI: %s", sess.IMSI)
+			}
+			sess.AddFlowLabel(v0.IFTypeSGSNSignalling, req.FlowLabelSignalling.FlowLabelSignalling())
+			sess.AddFlowLabel(v0.IFTypeGGSNSignalling, 0x2222)
+			if err := sess.Activate(); err != nil {
+				return err
+			}
+			c.AddSession(sess)
+
+			rsp := messages.NewCreatePDPContextResponse(
+				0, req.FlowLabelSignalling.FlowLabelSignalling(), req.RawTID(),
+				ies.NewCause(v0.CauseRequestAccepted),
+				ies.NewFlowLabelSignalling(0x2222),
+			)
+			return c.RespondTo(cliAddr, req, rsp)
+		},
+		messages.MsgTypeDeletePDPContextRequest: func(c *v0.Conn, cliAddr net.Addr, msg messages.Message) error {
+			sess, err := c.GetSessionByTID(msg.RawTID())
+			if err != nil {
+				return err
+			}
+			label, err := sess.GetFlowLabel(v0.IFTypeSGSNSignalling)
+			if err != nil {
+				return err
+			}
+			c.RemoveSession(sess)
+
+			rsp := messages.NewDeletePDPContextResponse(
+				0, label, msg.RawTID(), ies.NewCause(v0.CauseRequestAccepted),
+			)
+			return c.RespondTo(cliAddr, msg, rsp)
+		},
+	})
+
+	cliConn, err = v0.Dial(cliAddr, srvAddr, 0, errCh)
+	if err != nil {
+		srvConn.Close()
+		return nil, nil, err
+	}
+	return cliConn, srvConn, nil
+}
+
+func TestPDPContext(t *testing.T) {
+	var (
+		createdCh = make(chan uint16)
+		deletedCh = make(chan struct{})
+		errCh     = make(chan error)
+	)
+	cliConn, srvConn, err := setupConn(errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.AddHandlers(map[uint8]v0.HandlerFunc{
+		messages.MsgTypeCreatePDPContextResponse: func(c *v0.Conn, srvAddr net.Addr, msg messages.Message) error {
+			rsp := msg.(*messages.CreatePDPContextResponse)
+			if rsp.Cause == nil {
+				return &v0.ErrRequiredIEMissing{Type: ies.Cause}
+			}
+			if cause := rsp.Cause.Cause(); cause != v0.CauseRequestAccepted {
+				return &v0.ErrCauseNotOK{
+					MsgType: rsp.MessageTypeName(),
+					Cause:   cause,
+					Msg:     "something went wrong",
+				}
+			}
+			createdCh <- rsp.FlowLabelSignalling.FlowLabelSignalling()
+			return nil
+		},
+		messages.MsgTypeDeletePDPContextResponse: func(c *v0.Conn, srvAddr net.Addr, msg messages.Message) error {
+			deletedCh <- struct{}{}
+			return nil
+		},
+	})
+
+	tid, err := v0.NewTID("123451234567890", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	label := cliConn.NewFlowLabel(v0.IFTypeSGSNSignalling)
+	sess, err := cliConn.CreatePDPContext(
+		srvConn.LocalAddr(), tid,
+		ies.NewSelectionMode(v0.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+		ies.NewFlowLabelSignalling(label),
+		ies.NewAccessPointName("some.apn.example"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	cliConn.AddSession(sess)
+
+	select {
+	case peerLabel := <-createdCh:
+		sess.AddFlowLabel(v0.IFTypeGGSNSignalling, peerLabel)
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for Create PDP Context Response")
+	}
+
+	if sess.APN != "some.apn.example" {
+		t.Errorf("unexpected APN: %s", sess.APN)
+	}
+	got, err := cliConn.GetSessionByFlowLabel(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sess {
+		t.Error("GetSessionByFlowLabel returned unexpected Session")
+	}
+	got, err = cliConn.GetSessionByTID(tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sess {
+		t.Error("GetSessionByTID returned unexpected Session")
+	}
+
+	if err := sess.Delete(cliConn, v0.IFTypeGGSNSignalling); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-deletedCh:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for Delete PDP Context Response")
+	}
+
+	if _, err := srvConn.GetSessionByTID(tid); err != v0.ErrUnknownTID {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
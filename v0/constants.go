@@ -4,6 +4,9 @@
 
 package v0
 
+//go:generate go run ../internal/genconst -file $GOFILE -type Cause -block "Cause definitions." -trim Cause
+//go:generate go run ../internal/genconst -file $GOFILE -type IFType -block "InterfaceType definitions." -trim IFType
+
 // Cause definitions.
 const (
 	CauseRequestIMSI              uint8 = 0
@@ -31,6 +34,12 @@ const (
 	CauseUserAuthenticationFailed uint8 = 209
 )
 
+// Cause is the type of the Cause values, which gives the name with String().
+//
+// The Cause* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into Cause to get the name.
+type Cause uint8
+
 // PDP Type Organization definitions.
 const (
 	PDPTypeETSI uint8 = iota | 0xf0
@@ -43,3 +52,22 @@ const (
 	SelectionModeMSProvidedAPNSubscriptionNotVerified
 	SelectionModeNetworkProvidedAPNSubscriptionNotVerified
 )
+
+// Port is the registered port for GTPv0, which is used for both signalling and user traffic.
+const Port = 3386
+
+// InterfaceType definitions.
+//
+// GTPv0 has no such concept, but they are used to identify the Flow Label stored in Session.
+const (
+	IFTypeSGSNSignalling uint8 = iota
+	IFTypeSGSNDataI
+	IFTypeGGSNSignalling
+	IFTypeGGSNDataI
+)
+
+// IFType is the type of the InterfaceType values, which gives the name with String().
+//
+// The IFType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into IFType to get the name.
+type IFType uint8
@@ -2,8 +2,9 @@
 // Use of this source code is governed by a MIT-style license that can be
 // found in the LICENSE file.
 
-// Package v0 provides the simple and painless handling of GTPv1-C and GTPv1-U protocol in pure Golang.
+// Package v0 provides the simple and painless handling of GTPv0 protocol in pure Golang.
 //
-// This package is still under construction. The networking feature would be available in the future.
-// See messages and ies directory for what you can do with the current implementation.
+// Conn handles the signalling messages exchanged over port 3386, and keeps the PDP contexts
+// as Sessions identified by TID and Flow Labels.
+// See messages and ies directory for how to build messages by yourself.
 package v0
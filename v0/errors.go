@@ -0,0 +1,79 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoHandlersFound indicates that the handler func is not registered in *Conn
+	// for the incoming GTPv0 message. In usual cases this error should not be taken
+	// as fatal, as the other endpoint can make your program stop working just by
+	// sending unregistered messages.
+	ErrNoHandlersFound = errors.New("no handlers found for incoming message, ignoring")
+
+	// ErrUnexpectedType indicates that the type of incoming message is not expected.
+	ErrUnexpectedType = errors.New("got unexpected type of message")
+
+	// ErrInvalidTID indicates that the TID given is malformed.
+	ErrInvalidTID = errors.New("got invalid TID")
+
+	// ErrUnknownTID indicates that the TID is not registered in any Session.
+	ErrUnknownTID = errors.New("got unknown TID")
+
+	// ErrUnknownFlowLabel indicates that the Flow Label is not registered in any Session.
+	ErrUnknownFlowLabel = errors.New("got unknown Flow Label")
+
+	// ErrFlowLabelNotFound indicates that Flow Label is not registered for the interface specified.
+	ErrFlowLabelNotFound = errors.New("no Flow Label found")
+
+	// ErrUnknownIMSI indicates that the IMSI is different from expected one.
+	ErrUnknownIMSI = errors.New("got unknown IMSI")
+
+	// ErrConnNotOpened indicates that some operation is failed due to the status of
+	// Conn is not valid.
+	ErrConnNotOpened = errors.New("connection is not opened")
+
+	// ErrRelayNotFound indicates that no relay is registered for the Flow Label given.
+	ErrRelayNotFound = errors.New("no relay found for the Flow Label")
+
+	// ErrErrorIndicated indicates that the peer has sent an Error Indication, which
+	// means the PDP context is not known in the peer anymore.
+	ErrErrorIndicated = errors.New("got Error Indication")
+)
+
+// ErrCauseNotOK indicates that the value in Cause IE is not OK.
+type ErrCauseNotOK struct {
+	MsgType string
+	Cause   uint8
+	Msg     string
+}
+
+// Error returns error cause with message.
+func (e *ErrCauseNotOK) Error() string {
+	return fmt.Sprintf("got non-OK Cause: %s in %s; %s", Cause(e.Cause), e.MsgType, e.Msg)
+}
+
+// ErrRequiredIEMissing indicates that the IE required is missing.
+type ErrRequiredIEMissing struct {
+	Type uint8
+}
+
+// Error returns error with missing IE type.
+func (e *ErrRequiredIEMissing) Error() string {
+	return fmt.Sprintf("required IE missing: %d", e.Type)
+}
+
+// ErrRequiredParameterMissing indicates that the parameter required is missing.
+type ErrRequiredParameterMissing struct {
+	Name, Msg string
+}
+
+// Error returns missing parameter with message.
+func (e *ErrRequiredParameterMissing) Error() string {
+	return fmt.Sprintf("required parameter: %s is missing. %s", e.Name, e.Msg)
+}
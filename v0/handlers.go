@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+)
+
+// HandlerFunc is a handler for specific GTPv0 message.
+type HandlerFunc func(c *Conn, senderAddr net.Addr, msg messages.Message) error
+
+type msgHandlerMap struct {
+	syncMap sync.Map
+}
+
+func (m *msgHandlerMap) store(msgType uint8, handler HandlerFunc) {
+	m.syncMap.Store(msgType, handler)
+}
+
+func (m *msgHandlerMap) load(msgType uint8) (HandlerFunc, bool) {
+	handler, ok := m.syncMap.Load(msgType)
+	if !ok {
+		return nil, false
+	}
+
+	return handler.(HandlerFunc), true
+}
+
+func newMsgHandlerMap(m map[uint8]HandlerFunc) *msgHandlerMap {
+	mhm := &msgHandlerMap{syncMap: sync.Map{}}
+	for k, v := range m {
+		mhm.store(k, v)
+	}
+
+	return mhm
+}
+
+// newDefaultHandlerMap returns the default handlers for Conn.
+// This is created for each Conn so that the handlers added to one Conn
+// do not affect the others.
+func newDefaultHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:     handleEchoRequest,
+			messages.MsgTypeEchoResponse:    handleEchoResponse,
+			messages.MsgTypeErrorIndication: handleErrorIndication,
+			messages.MsgTypeTPDU:            handleTPDU,
+		},
+	)
+}
+
+func handleTPDU(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	pdu, ok := msg.(*messages.TPDU)
+	if !ok {
+		return ErrUnexpectedType
+	}
+
+	tpdu := &tpduSet{
+		raddr:   senderAddr,
+		label:   pdu.Label(),
+		tid:     pdu.RawTID(),
+		seq:     pdu.Sequence(),
+		payload: pdu.Decapsulate(),
+	}
+
+	// wait for the T-PDU passed to c.tpduCh to be read by ReadFromGTP.
+	// if it got stuck for 3 seconds, it discards the T-PDU received.
+	go func() {
+		select {
+		case c.tpduCh <- tpdu:
+			return
+		case <-time.After(3 * time.Second):
+			return
+		}
+	}()
+	return nil
+}
+
+func handleEchoRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EchoRequest); !ok {
+		return ErrUnexpectedType
+	}
+
+	// respond with EchoResponse.
+	return c.RespondTo(
+		senderAddr, msg, messages.NewEchoResponse(0, 0, 0, ies.NewRecovery(c.RestartCounter)),
+	)
+}
+
+func handleEchoResponse(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EchoResponse); !ok {
+		return ErrUnexpectedType
+	}
+
+	// do nothing.
+	return nil
+}
+
+func handleErrorIndication(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.ErrorIndication); !ok {
+		return ErrUnexpectedType
+	}
+
+	// let's just return err anyway, as the action to be taken depends on the node.
+	return ErrErrorIndicated
+}
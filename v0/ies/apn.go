@@ -1,4 +1,4 @@
-// Copyright 2019 go-gtp. All rights reserved.
+// Copyright 2019 go-gtp authors. All rights reserved.
 // Use of this source code is governed by a MIT-style license that can be
 // found in the LICENSE file.
 
@@ -8,16 +8,7 @@ import "strings"
 
 // NewAccessPointName creates a new AccessPointName IE.
 func NewAccessPointName(apn string) *IE {
-	i := New(AccessPointName, make([]byte, len(apn)+1))
-	var offset = 0
-	for _, label := range strings.Split(apn, ".") {
-		l := len(label)
-		i.Payload[offset] = uint8(l)
-		copy(i.Payload[offset+1:], []byte(label))
-		offset += l + 1
-	}
-
-	return i
+	return New(AccessPointName, encodeAPN(apn))
 }
 
 // AccessPointName returns AccessPointName in string if type of IE matches.
@@ -26,18 +17,66 @@ func (i *IE) AccessPointName() string {
 		return ""
 	}
 
+	return decodeAPN(i.Payload)
+}
+
+// APNNetworkIdentifier returns the Network Identifier part of AccessPointName, which is
+// the APN without the Operator Identifier("mncXXX.mccYYY.gprs") at the end.
+func (i *IE) APNNetworkIdentifier() string {
+	ni, _ := splitAPN(i.AccessPointName())
+	return ni
+}
+
+// APNOperatorIdentifier returns the Operator Identifier part of AccessPointName in the
+// format of "mncXXX.mccYYY.gprs". This returns "" if it is not included.
+func (i *IE) APNOperatorIdentifier() string {
+	_, oi := splitAPN(i.AccessPointName())
+	return oi
+}
+
+// splitAPN splits apn into the Network Identifier and the Operator Identifier.
+func splitAPN(apn string) (string, string) {
+	labels := strings.Split(apn, ".")
+	n := len(labels)
+	if n < 4 {
+		return apn, ""
+	}
+	if labels[n-1] != "gprs" || !strings.HasPrefix(labels[n-2], "mcc") || !strings.HasPrefix(labels[n-3], "mnc") {
+		return apn, ""
+	}
+
+	return strings.Join(labels[:n-3], "."), strings.Join(labels[n-3:], ".")
+}
+
+func encodeAPN(apn string) []byte {
+	b := make([]byte, len(apn)+1)
+	var offset = 0
+	for _, label := range strings.Split(apn, ".") {
+		l := len(label)
+		b[offset] = uint8(l)
+		copy(b[offset+1:], []byte(label))
+		offset += l + 1
+	}
+
+	return b
+}
+
+func decodeAPN(b []byte) string {
 	var (
 		apn    []string
 		offset int
 	)
 
-	max := len(i.Payload)
+	max := len(b)
 	for {
 		if offset >= max {
 			break
 		}
-		l := int(i.Payload[offset])
-		apn = append(apn, string(i.Payload[offset+1:offset+l+1]))
+		l := int(b[offset])
+		if offset+l+1 > max {
+			break
+		}
+		apn = append(apn, string(b[offset+1:offset+l+1]))
 		offset += l + 1
 	}
 
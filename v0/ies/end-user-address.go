@@ -99,6 +99,9 @@ func (i *IE) PDPTypeOrganization() uint8 {
 	if i.Type != EndUserAddress {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
 
@@ -107,6 +110,9 @@ func (i *IE) PDPTypeNumber() uint8 {
 	if i.Type != EndUserAddress {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return i.Payload[1]
 }
 
@@ -114,15 +120,15 @@ func (i *IE) PDPTypeNumber() uint8 {
 func (i *IE) IPAddress() string {
 	switch i.Type {
 	case EndUserAddress:
-		if i.PDPTypeOrganization() != pdpTypeIETF {
+		if i.PDPTypeOrganization() != pdpTypeIETF || len(i.Payload) < 2 {
 			return ""
 		}
-		if len(i.Payload) < 3 {
-			return ""
+		if ip := ipAddr(i.Payload[2:]); ip != nil {
+			return ip.String()
 		}
-		return net.IP(i.Payload[2:]).String()
+		return ""
 	case GSNAddress:
-		return net.IP(i.Payload).String()
+		return i.GSNAddress()
 	default:
 		return ""
 	}
@@ -11,4 +11,6 @@ var (
 	ErrInvalidLength       = errors.New("got invalid length")
 	ErrTooShortToSerialize = errors.New("too short to serialize")
 	ErrTooShortToDecode    = errors.New("too short to decode as GTPv0 IE")
+	ErrUnknownTVType       = errors.New("got TV type IE with unknown length")
+	ErrNotTVType           = errors.New("not a TV type IE")
 )
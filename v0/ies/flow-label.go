@@ -16,6 +16,9 @@ func (i *IE) FlowLabelDataI() uint16 {
 	if i.Type != FlowLabelDataI {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return binary.BigEndian.Uint16(i.Payload)
 }
 
@@ -29,6 +32,9 @@ func (i *IE) FlowLabelSignalling() uint16 {
 	if i.Type != FlowLabelSignalling {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return binary.BigEndian.Uint16(i.Payload)
 }
 
@@ -52,6 +58,9 @@ func (i *IE) FlowLabelDataII() []byte {
 func (i *IE) NSAPI() uint8 {
 	switch i.Type {
 	case FlowLabelDataII:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return i.Payload[0] & 0x0f
 	default:
 		return 0
@@ -66,6 +75,9 @@ func (i *IE) FlowLabelData() uint16 {
 	case FlowLabelSignalling:
 		return i.FlowLabelSignalling()
 	case FlowLabelDataII:
+		if len(i.Payload) < 3 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[1:3])
 	default:
 		return 0
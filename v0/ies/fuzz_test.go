@@ -0,0 +1,57 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+func FuzzDecode(f *testing.F) {
+	seeds := []*ies.IE{
+		ies.NewCause(128),
+		ies.NewIMSI("123451234567891"),
+		ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+		ies.NewQualityOfServiceProfile(1, 1, 1, 1, 1),
+		ies.NewRecovery(0xff),
+		ies.NewFlowLabelDataI(11),
+		ies.NewFlowLabelDataII(5, 11),
+		ies.NewChargingID(0xffffffff),
+		ies.NewEndUserAddressIPv6("2001::1"),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewGSNAddress("1.1.1.1"),
+		ies.NewMSISDN("8130900000001"),
+		ies.NewPrivateExtension(10415, []byte{0xde, 0xad, 0xbe, 0xef}),
+	}
+	for _, ie := range seeds {
+		b, err := ie.Serialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ie, err := ies.Decode(b)
+		if err != nil {
+			return
+		}
+		callGetters(ie)
+		_, _ = ie.Serialize()
+	})
+}
+
+// callGetters calls all the methods of IE that take no arguments, so that
+// the getters are checked not to panic with the malformed payload.
+func callGetters(ie *ies.IE) {
+	v := reflect.ValueOf(ie)
+	for i := 0; i < v.NumMethod(); i++ {
+		if m := v.Method(i); m.Type().NumIn() == 0 {
+			m.Call(nil)
+		}
+	}
+}
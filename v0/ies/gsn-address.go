@@ -7,22 +7,51 @@ package ies
 import "net"
 
 // NewGSNAddress creates a new GSNAddress IE from string.
+//
+// The address is encoded in 4 octets if it is IPv4, and 16 octets if IPv6.
 func NewGSNAddress(addr string) *IE {
+	return newIPAddrIE(GSNAddress, addr)
+}
+
+// GSNAddress returns GSNAddress value if type matches.
+func (i *IE) GSNAddress() string {
+	if ip := i.GSNAddressIP(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// GSNAddressIP returns GSNAddress in net.IP if type matches.
+// This returns nil if the length is neither of IPv4 nor IPv6.
+func (i *IE) GSNAddressIP() net.IP {
+	if i.Type != GSNAddress {
+		return nil
+	}
+	return ipAddr(i.Payload)
+}
+
+// IsIPv6GSNAddress checks if GSNAddress is IPv6.
+func (i *IE) IsIPv6GSNAddress() bool {
+	return i.Type == GSNAddress && len(i.Payload) == net.IPv6len
+}
+
+func newIPAddrIE(t uint8, addr string) *IE {
 	ip := net.ParseIP(addr)
 	v4 := ip.To4()
 
 	// IPv4
 	if v4 != nil {
-		return New(GSNAddress, v4)
+		return New(t, v4)
 	}
 	//IPv6
-	return New(GSNAddress, ip)
+	return New(t, ip)
 }
 
-// GSNAddress returns GSNAddress value if type matches.
-func (i *IE) GSNAddress() string {
-	if i.Type != GSNAddress {
-		return ""
+func ipAddr(b []byte) net.IP {
+	switch len(b) {
+	case net.IPv4len, net.IPv6len:
+		return net.IP(b)
+	default:
+		return nil
 	}
-	return net.IP(i.Payload).String()
 }
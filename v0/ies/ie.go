@@ -10,6 +10,7 @@ package ies
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
 )
 
 // TV IE definitions.
@@ -116,11 +117,18 @@ func decodeTVFromBytes(i *IE, b []byte) error {
 	if l < 2 {
 		return ErrTooShortToDecode
 	}
-	if i.Len() > l {
+
+	// the length of TV IE cannot be known from the bytes. decoding it with
+	// a wrong length breaks all the IEs that follow.
+	tvLen, ok := lookupTVLength(i.Type)
+	if !ok {
+		return ErrUnknownTVType
+	}
+	if tvLen+1 > l {
 		return ErrInvalidLength
 	}
 	i.Length = 0
-	i.Payload = b[1:i.Len()]
+	i.Payload = b[1 : tvLen+1]
 
 	return nil
 }
@@ -140,26 +148,57 @@ func decodeTLVFromBytes(i *IE, b []byte) error {
 	return nil
 }
 
-var tvLengthMap = map[uint8]int{
-	0:   0,  // Reserved
-	1:   1,  // Cause
-	2:   8,  // IMSI
-	3:   6,  // RAI
-	4:   4,  // TLLI
-	5:   4,  // P-TMSI
-	6:   3,  // QoS
-	8:   1,  // Reordering Required
-	9:   28, // Authentication Triplet
-	11:  1,  // MAP Cause
-	12:  3,  // P-TMSI Signature
-	13:  1,  // MS Validated
-	14:  1,  // Recovery
-	15:  1,  // Selection Mode
-	16:  2,  // Flow Label Data I
-	17:  2,  // Flow Label Signalling
-	18:  3,  // Flow Label Data II
-	19:  1,  // MS Not Reachable Reason
-	127: 4,  // Charging ID
+var (
+	tvLengthMu  sync.RWMutex
+	tvLengthMap = map[uint8]int{
+		0:   0,  // Reserved
+		1:   1,  // Cause
+		2:   8,  // IMSI
+		3:   6,  // RAI
+		4:   4,  // TLLI
+		5:   4,  // P-TMSI
+		6:   3,  // QoS
+		8:   1,  // Reordering Required
+		9:   28, // Authentication Triplet
+		11:  1,  // MAP Cause
+		12:  3,  // P-TMSI Signature
+		13:  1,  // MS Validated
+		14:  1,  // Recovery
+		15:  1,  // Selection Mode
+		16:  2,  // Flow Label Data I
+		17:  2,  // Flow Label Signalling
+		18:  3,  // Flow Label Data II
+		19:  1,  // MS Not Reachable Reason
+		127: 4,  // Charging ID
+	}
+)
+
+// RegisterTVLength registers the length of the value of TV type IE, which is
+// used to decode the IEs that are not defined in this package, e.g., the ones
+// used in the vendor-specific implementations.
+//
+// The length of the IEs defined in this package can be overridden as well.
+// Decoding fails with ErrUnknownTVType if the TV type IE is not registered.
+func RegisterTVLength(t uint8, length int) error {
+	if t >= 0x80 {
+		return ErrNotTVType
+	}
+	if length < 0 || length > 0xff {
+		return ErrInvalidLength
+	}
+
+	tvLengthMu.Lock()
+	defer tvLengthMu.Unlock()
+	tvLengthMap[t] = length
+	return nil
+}
+
+func lookupTVLength(t uint8) (int, bool) {
+	tvLengthMu.RLock()
+	defer tvLengthMu.RUnlock()
+
+	l, ok := tvLengthMap[t]
+	return l, ok
 }
 
 // IsTV checks if a IE is TV format. If false, it indicates the IE has Length inside.
@@ -168,19 +207,22 @@ func (i *IE) IsTV() bool {
 }
 
 // Len returns the actual length of IE.
+//
+// For the TV type IE whose length is not registered, the length of Payload is used.
 func (i *IE) Len() int {
-	if l, ok := tvLengthMap[i.Type]; ok {
-		return l + 1
+	if !i.IsTV() {
+		return 3 + len(i.Payload)
 	}
-	if i.Type < 128 {
-		return 1 + len(i.Payload)
+	if l, ok := lookupTVLength(i.Type); ok {
+		return l + 1
 	}
-	return 3 + len(i.Payload)
+	return 1 + len(i.Payload)
 }
 
 // SetLength sets the length in Length field.
 func (i *IE) SetLength() {
-	if _, ok := tvLengthMap[i.Type]; ok {
+	// TV type IE has no Length field.
+	if i.IsTV() {
 		i.Length = 0
 		return
 	}
@@ -199,20 +241,30 @@ func (i *IE) String() string {
 
 // DecodeMultiIEs decodes multiple (unspecified number of) IEs to []*IE at a time.
 func DecodeMultiIEs(b []byte) ([]*IE, error) {
-	var ies []*IE
-	for {
-		if len(b) == 0 {
-			break
+	// count the IEs first to allocate them all at once instead of one by one.
+	// the length of TV IEs cannot be known without decoding them.
+	n := 0
+	for rest := b; len(rest) > 0; n++ {
+		var i IE
+		if err := i.DecodeFromBytes(rest); err != nil {
+			return nil, err
 		}
+		rest = rest[i.Len():]
+	}
 
-		i, err := Decode(b)
-		if err != nil {
+	if n == 0 {
+		return nil, nil
+	}
+
+	decoded := make([]IE, n)
+	ies := make([]*IE, n)
+	for k := range decoded {
+		i := &decoded[k]
+		if err := i.DecodeFromBytes(b); err != nil {
 			return nil, err
 		}
-
-		ies = append(ies, i)
+		ies[k] = i
 		b = b[i.Len():]
-		continue
 	}
 	return ies, nil
 }
@@ -5,6 +5,7 @@
 package ies_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -242,3 +243,100 @@ func TestIE(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedGetters(t *testing.T) {
+	rai := ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22)
+	if rai.MCC() != "123" || rai.MNC() != "45" || rai.LAC() != 0x1111 || rai.RAC() != 0x22 {
+		t.Errorf("unexpected RAI: %s, %s, %#x, %#x", rai.MCC(), rai.MNC(), rai.LAC(), rai.RAC())
+	}
+	rai = ies.NewRouteingAreaIdentity("123", "456", 0x1111, 0x22)
+	if rai.MCC() != "123" || rai.MNC() != "456" {
+		t.Errorf("unexpected RAI with 3-digit MNC: %s, %s", rai.MCC(), rai.MNC())
+	}
+
+	qos := ies.NewQualityOfServiceProfile(1, 2, 3, 1, 31)
+	if qos.QoSDelay() != 1 || qos.QoSReliability() != 2 || qos.QoSPeak() != 3 ||
+		qos.QoSPrecedence() != 1 || qos.QoSMean() != 31 {
+		t.Errorf("unexpected QoS Profile: %#v", qos.QualityOfServiceProfile())
+	}
+
+	if got := ies.NewEndUserAddress("2001::1").IPAddress(); got != "2001::1" {
+		t.Errorf("unexpected End User Address: %s", got)
+	}
+	if got := ies.NewEndUserAddressPPP().IPAddress(); got != "" {
+		t.Errorf("unexpected End User Address for PPP: %s", got)
+	}
+
+	apn := ies.NewAccessPointName("some.apn.mnc001.mcc001.gprs")
+	if got := apn.APNNetworkIdentifier(); got != "some.apn" {
+		t.Errorf("unexpected APN NI: %s", got)
+	}
+	if got := apn.APNOperatorIdentifier(); got != "mnc001.mcc001.gprs" {
+		t.Errorf("unexpected APN OI: %s", got)
+	}
+
+	if !ies.NewGSNAddress("2001::1").IsIPv6GSNAddress() {
+		t.Error("GSN Address should be IPv6")
+	}
+
+	fl := ies.NewFlowLabelDataII(5, 0x1234)
+	if fl.NSAPI() != 5 || fl.FlowLabelData() != 0x1234 {
+		t.Errorf("unexpected Flow Label Data II: %d, %#x", fl.NSAPI(), fl.FlowLabelData())
+	}
+}
+
+func TestGettersWithShortPayload(t *testing.T) {
+	// getters should not panic with the malformed IEs.
+	for _, typ := range []uint8{
+		ies.Cause, ies.RouteingAreaIdentity, ies.QualityOfServiceProfile, ies.FlowLabelDataI,
+		ies.FlowLabelSignalling, ies.FlowLabelDataII, ies.EndUserAddress, ies.AccessPointName,
+		ies.GSNAddress, ies.MSISDN,
+	} {
+		i := ies.New(typ, []byte{0x05})
+		_ = i.Cause()
+		_ = i.MCC()
+		_ = i.MNC()
+		_ = i.LAC()
+		_ = i.RAC()
+		_ = i.QoSDelay()
+		_ = i.QoSPeak()
+		_ = i.QoSMean()
+		_ = i.FlowLabelData()
+		_ = i.NSAPI()
+		_ = i.PDPTypeNumber()
+		_ = i.IPAddress()
+		_ = i.AccessPointName()
+		_ = i.GSNAddress()
+		_ = i.MSISDN()
+	}
+}
+
+func TestRegisterTVLength(t *testing.T) {
+	// vendor-specific TV type IE with 2 octets of value, followed by Recovery.
+	b := []byte{0x64, 0xde, 0xad, 0x0e, 0x01}
+
+	if _, err := ies.DecodeMultiIEs(b); err != ies.ErrUnknownTVType {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := ies.RegisterTVLength(0x64, 2); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ies.DecodeMultiIEs(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("unexpected number of IEs: %d", len(decoded))
+	}
+	if got := decoded[0].Payload; !bytes.Equal(got, []byte{0xde, 0xad}) {
+		t.Errorf("unexpected payload: %x", got)
+	}
+	if got := decoded[1].Recovery(); got != 1 {
+		t.Errorf("unexpected Recovery: %d", got)
+	}
+
+	if err := ies.RegisterTVLength(ies.AccessPointName, 2); err != ies.ErrNotTVType {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
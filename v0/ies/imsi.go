@@ -7,7 +7,12 @@ package ies
 import "github.com/wmnsk/go-gtp/utils"
 
 // NewIMSI creates a new IMSI IE.
+//
+// imsi is validated with utils.CheckIMSI, and IE with no payload is returned if it fails.
 func NewIMSI(imsi string) *IE {
+	if err := utils.CheckIMSI(imsi); err != nil {
+		return New(IMSI, nil)
+	}
 	i, err := utils.StrToSwappedBytes(imsi, "f")
 	if err != nil {
 		return New(IMSI, nil)
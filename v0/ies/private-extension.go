@@ -29,6 +29,9 @@ func (i *IE) ExtensionIdentifier() uint16 {
 	if i.Type != PrivateExtension {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return binary.BigEndian.Uint16(i.Payload[:2])
 }
 
@@ -37,5 +40,8 @@ func (i *IE) ExtensionValue() []byte {
 	if i.Type != PrivateExtension {
 		return nil
 	}
+	if len(i.Payload) < 2 {
+		return nil
+	}
 	return i.Payload[2:]
 }
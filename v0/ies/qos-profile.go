@@ -15,6 +15,9 @@ func NewQualityOfServiceProfile(delay, reliability, peak, precedence, mean uint8
 }
 
 // QualityOfServiceProfile returns QualityOfServiceProfile if type matches.
+//
+// The value of each field can be retrieved with QoSDelay, QoSReliability, QoSPeak,
+// QoSPrecedence and QoSMean, which return the values given to the constructor.
 func (i *IE) QualityOfServiceProfile() []byte {
 	if i.Type != QualityOfServiceProfile {
 		return nil
@@ -27,7 +30,10 @@ func (i *IE) QoSDelay() uint8 {
 	if i.Type != QualityOfServiceProfile {
 		return 0
 	}
-	return i.Payload[0] & 0x38
+	if len(i.Payload) < 1 {
+		return 0
+	}
+	return (i.Payload[0] >> 3) & 0x07
 }
 
 // QoSReliability returns QoS Reliability value in uint8 if type matches.
@@ -35,6 +41,9 @@ func (i *IE) QoSReliability() uint8 {
 	if i.Type != QualityOfServiceProfile {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0] & 0x07
 }
 
@@ -43,7 +52,10 @@ func (i *IE) QoSPeak() uint8 {
 	if i.Type != QualityOfServiceProfile {
 		return 0
 	}
-	return i.Payload[1] & 0xf0
+	if len(i.Payload) < 2 {
+		return 0
+	}
+	return (i.Payload[1] >> 4) & 0x0f
 }
 
 // QoSPrecedence returns QoS Precedence value in uint8 if type matches.
@@ -51,6 +63,9 @@ func (i *IE) QoSPrecedence() uint8 {
 	if i.Type != QualityOfServiceProfile {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return i.Payload[1] & 0x07
 }
 
@@ -59,5 +74,8 @@ func (i *IE) QoSMean() uint8 {
 	if i.Type != QualityOfServiceProfile {
 		return 0
 	}
-	return i.Payload[2] & 0x0f
+	if len(i.Payload) < 3 {
+		return 0
+	}
+	return i.Payload[2] & 0x1f
 }
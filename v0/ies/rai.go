@@ -38,21 +38,29 @@ func (i *IE) RouteingAreaIdentity() []byte {
 
 // MCC returns MCC value if type matches.
 func (i *IE) MCC() string {
-	switch i.Type {
-	case RouteingAreaIdentity:
-		return utils.SwappedBytesToStr(i.Payload[0:2], false)
-	default:
-		return ""
-	}
+	mcc, _ := i.plmn()
+	return mcc
 }
 
 // MNC returns MNC value if type matches.
 func (i *IE) MNC() string {
+	_, mnc := i.plmn()
+	return mnc
+}
+
+func (i *IE) plmn() (mcc, mnc string) {
 	switch i.Type {
 	case RouteingAreaIdentity:
-		return utils.SwappedBytesToStr(i.Payload[1:2], true)
+		if len(i.Payload) < 3 {
+			return "", ""
+		}
+		mcc, mnc, err := utils.DecodePLMN(i.Payload[0:3])
+		if err != nil {
+			return "", ""
+		}
+		return mcc, mnc
 	default:
-		return ""
+		return "", ""
 	}
 }
 
@@ -60,6 +68,9 @@ func (i *IE) MNC() string {
 func (i *IE) LAC() uint16 {
 	switch i.Type {
 	case RouteingAreaIdentity:
+		if len(i.Payload) < 5 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[3:5])
 	default:
 		return 0
@@ -70,6 +81,9 @@ func (i *IE) LAC() uint16 {
 func (i *IE) RAC() uint8 {
 	switch i.Type {
 	case RouteingAreaIdentity:
+		if len(i.Payload) < 6 {
+			return 0
+		}
 		return i.Payload[5]
 	default:
 		return 0
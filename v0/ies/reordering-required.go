@@ -17,5 +17,8 @@ func (i *IE) ReorderingRequired() bool {
 	if i.Type != ReorderingRequired {
 		return false
 	}
+	if len(i.Payload) < 1 {
+		return false
+	}
 	return i.Payload[0]&0x01 == 1
 }
@@ -16,5 +16,8 @@ func (i *IE) SelectionMode() uint8 {
 	if i.Type != SelectionMode {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
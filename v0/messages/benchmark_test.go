@@ -0,0 +1,108 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+	"github.com/wmnsk/go-gtp/v0/testutils"
+)
+
+var (
+	seq   = testutils.TestFlow.Seq
+	label = testutils.TestFlow.Label
+	tid   = testutils.TestFlow.TID
+)
+
+var benchMessages = []struct {
+	description string
+	msg         messages.Message
+}{
+	{
+		"EchoRequest",
+		messages.NewEchoRequest(seq, label, tid),
+	}, {
+		"CreatePDPContextRequest",
+		messages.NewCreatePDPContextRequest(
+			seq, label, tid,
+			ies.NewQualityOfServiceProfile(1, 1, 1, 1, 1),
+			ies.NewRecovery(0x80),
+			ies.NewSelectionMode(0xff),
+			ies.NewFlowLabelDataI(11),
+			ies.NewFlowLabelSignalling(22),
+			ies.NewEndUserAddressIPv4("1.1.1.1"),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewMSISDN("8130900000001"),
+		),
+	}, {
+		"CreatePDPContextResponse",
+		messages.NewCreatePDPContextResponse(
+			seq, label, tid,
+			ies.NewCause(128),
+			ies.NewReorderingRequired(false),
+			ies.NewFlowLabelDataI(11),
+			ies.NewChargingID(0xffffffff),
+			ies.NewEndUserAddressIPv4("1.1.1.1"),
+		),
+	},
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			buf := make([]byte, c.msg.Len())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			serialized, err := messages.Serialize(c.msg)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := messages.Decode(serialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSerializeToAllocs(t *testing.T) {
+	for _, c := range benchMessages {
+		t.Run(c.description, func(t *testing.T) {
+			buf := make([]byte, c.msg.Len())
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("got %v allocations, want 0", allocs)
+			}
+
+			if err := c.msg.SerializeTo(buf[:len(buf)-1]); err != messages.ErrTooShortToSerialize {
+				t.Errorf("got %v, want %v", err, messages.ErrTooShortToSerialize)
+			}
+		})
+	}
+}
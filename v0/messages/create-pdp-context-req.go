@@ -90,10 +90,9 @@ func (c *CreatePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (c *CreatePDPContextRequest) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.RAI; ie != nil {
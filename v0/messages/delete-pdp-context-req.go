@@ -52,10 +52,9 @@ func (d *DeletePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (d *DeletePDPContextRequest) SerializeTo(b []byte) error {
-	if d.Header.Payload != nil {
-		d.Header.Payload = nil
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.PrivateExtension; ie != nil {
@@ -56,7 +56,9 @@ func (d *DeletePDPContextResponse) Serialize() ([]byte, error) {
 func (d *DeletePDPContextResponse) SerializeTo(b []byte) error {
 	// XXX - add validation!
 
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
@@ -54,10 +54,9 @@ func (e *EchoResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (e *EchoResponse) SerializeTo(b []byte) error {
-	if e.Header.Payload != nil {
-		e.Header.Payload = nil
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
 	}
-	e.Header.Payload = make([]byte, e.Len()-e.Header.Len())
 
 	offset := 0
 	if ie := e.Recovery; ie != nil {
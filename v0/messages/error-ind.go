@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+// ErrorIndication is a ErrorIndication Header and its AdditionalIEs above.
+//
+// The Flow Label and TID in the Header should be the ones in the G-PDU that
+// triggered the Error Indication.
+type ErrorIndication struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewErrorIndication creates a new ErrorIndication.
+func NewErrorIndication(seq, label uint16, tid uint64, ie ...*ies.IE) *ErrorIndication {
+	e := &ErrorIndication{
+		Header: NewHeader(
+			0x1e, MsgTypeErrorIndication, seq, label, tid, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	e.SetLength()
+	return e
+}
+
+// Serialize returns the byte sequence generated from a ErrorIndication.
+func (e *ErrorIndication) Serialize() ([]byte, error) {
+	b := make([]byte, e.Len())
+	if err := e.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (e *ErrorIndication) SerializeTo(b []byte) error {
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := e.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(e.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(e.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	e.Header.SetLength()
+	return e.Header.SerializeTo(b)
+}
+
+// DecodeErrorIndication decodes a given byte sequence as a ErrorIndication.
+func DecodeErrorIndication(b []byte) (*ErrorIndication, error) {
+	e := &ErrorIndication{}
+	if err := e.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a ErrorIndication.
+func (e *ErrorIndication) DecodeFromBytes(b []byte) error {
+	var err error
+	e.Header, err = DecodeHeader(b)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode Header:")
+	}
+	if len(e.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(e.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (e *ErrorIndication) Len() int {
+	l := e.Header.Len() - len(e.Header.Payload)
+
+	if ie := e.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (e *ErrorIndication) SetLength() {
+	e.Header.Length = uint16(e.Len() - 20)
+}
+
+// MessageTypeName returns the name of protocol.
+func (e *ErrorIndication) MessageTypeName() string {
+	return "Error Indication"
+}
+
+// TID returns the TID in human-readable string.
+func (e *ErrorIndication) TID() string {
+	return e.tid()
+}
@@ -11,4 +11,5 @@ var (
 	ErrInvalidLength       = errors.New("got invalid length")
 	ErrTooShortToSerialize = errors.New("too short to serialize")
 	ErrTooShortToDecode    = errors.New("too short to decode as GTPv0")
+	ErrInvalidMessageType  = errors.New("got invalid message type")
 )
@@ -0,0 +1,64 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+	"github.com/wmnsk/go-gtp/v0/testutils"
+)
+
+func FuzzDecode(f *testing.F) {
+	var (
+		seq   = testutils.TestFlow.Seq
+		label = testutils.TestFlow.Label
+		tid   = testutils.TestFlow.TID
+	)
+	seeds := []messages.Message{
+		messages.NewEchoRequest(seq, label, tid),
+		messages.NewEchoResponse(seq, label, tid, ies.NewRecovery(0x80)),
+		messages.NewCreatePDPContextRequest(
+			seq, label, tid,
+			ies.NewQualityOfServiceProfile(1, 1, 1, 1, 1),
+			ies.NewRecovery(0x80),
+			ies.NewSelectionMode(0xff),
+			ies.NewFlowLabelDataI(11),
+			ies.NewFlowLabelSignalling(22),
+			ies.NewEndUserAddressIPv4("1.1.1.1"),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewMSISDN("8130900000001"),
+		),
+		messages.NewCreatePDPContextResponse(
+			seq, label, tid,
+			ies.NewCause(128),
+			ies.NewReorderingRequired(false),
+			ies.NewFlowLabelDataI(11),
+			ies.NewChargingID(0xffffffff),
+			ies.NewEndUserAddressIPv4("1.1.1.1"),
+		),
+		messages.NewDeletePDPContextRequest(seq, label, tid),
+		messages.NewErrorIndication(seq, label, tid),
+		messages.NewTPDU(seq, label, tid, []byte{0xde, 0xad, 0xbe, 0xef}),
+	}
+	for _, m := range seeds {
+		b, err := messages.Serialize(m)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		msg, err := messages.Decode(b)
+		if err != nil {
+			return
+		}
+		_ = msg.MessageTypeName()
+		_, _ = messages.Serialize(msg)
+	})
+}
@@ -45,10 +45,9 @@ func (g *Generic) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (g *Generic) SerializeTo(b []byte) error {
-	if g.Header.Payload != nil {
-		g.Header.Payload = nil
+	if err := g.Header.preparePayload(b, g.Len()); err != nil {
+		return err
 	}
-	g.Header.Payload = make([]byte, g.Len()-g.Header.Len())
 
 	offset := 0
 	for _, ie := range g.IEs {
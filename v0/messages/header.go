@@ -85,7 +85,7 @@ func DecodeHeader(b []byte) (*Header, error) {
 // DecodeFromBytes sets the values retrieved from byte sequence in GTPv1 header.
 func (h *Header) DecodeFromBytes(b []byte) error {
 	l := len(b)
-	if l < 11 {
+	if l < 20 {
 		return ErrTooShortToDecode
 	}
 	h.Flags = b[0]
@@ -93,7 +93,7 @@ func (h *Header) DecodeFromBytes(b []byte) error {
 	h.Length = binary.BigEndian.Uint16(b[2:4])
 	h.SequenceNumber = binary.BigEndian.Uint16(b[4:6])
 	h.FlowLabel = binary.BigEndian.Uint16(b[6:8])
-	h.SndcpNumber = b[9]
+	h.SndcpNumber = b[8]
 	h.TID = binary.BigEndian.Uint64(b[12:20])
 
 	if int(h.Length)+20 != l {
@@ -114,6 +114,23 @@ func (h *Header) SetLength() {
 	h.Length = uint16(len(h.Payload))
 }
 
+// preparePayload points the Payload to the part of b after the header, so that
+// the message of length l can put its IEs into b directly without allocating
+// the Payload. The Payload refers to b after the message is serialized.
+func (h *Header) preparePayload(b []byte, l int) error {
+	if len(b) < l {
+		return ErrTooShortToSerialize
+	}
+	h.Payload = b[h.Len()-len(h.Payload) : l]
+	return nil
+}
+
+// detachPayload makes the Payload a copy of its own, not to refer to the buffer
+// given to preparePayload.
+func (h *Header) detachPayload() {
+	h.Payload = append([]byte(nil), h.Payload...)
+}
+
 // String returns the GTPv1 header values in human readable format.
 func (h *Header) String() string {
 	return fmt.Sprintf("{Flags: %#x, Type: %#x, Length: %d, SequenceNumber: %#04x, FlowLabel: %#04x, SndcpNumber: %#02x, TID: %#016x, Payload: %#v}",
@@ -145,3 +162,34 @@ func (h *Header) Version() int {
 func (h *Header) MessageType() uint8 {
 	return h.Type
 }
+
+// Sequence returns SequenceNumber in uint16.
+func (h *Header) Sequence() uint16 {
+	return h.SequenceNumber
+}
+
+// SetSequenceNumber sets the SequenceNumber in Header.
+func (h *Header) SetSequenceNumber(seq uint16) {
+	h.SequenceNumber = seq
+}
+
+// Label returns FlowLabel in uint16.
+func (h *Header) Label() uint16 {
+	return h.FlowLabel
+}
+
+// SetFlowLabel sets the FlowLabel in Header.
+func (h *Header) SetFlowLabel(label uint16) {
+	h.FlowLabel = label
+}
+
+// RawTID returns TID in uint64.
+// Use TID() of each message to get it in human-readable string.
+func (h *Header) RawTID() uint64 {
+	return h.TID
+}
+
+// SetTID sets the TID in Header.
+func (h *Header) SetTID(tid uint64) {
+	h.TID = tid
+}
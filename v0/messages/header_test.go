@@ -55,3 +55,15 @@ func TestHeader(t *testing.T) {
 		return v, nil
 	})
 }
+
+func TestDecodeHeaderTooShort(t *testing.T) {
+	// the TID ends at the 20th octet, which is missing.
+	b := []byte{
+		0x1e, 0xff, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+		0xff, 0xff, 0xff, 0xff,
+		0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43,
+	}
+	if _, err := messages.DecodeHeader(b); err != messages.ErrTooShortToDecode {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -7,6 +7,8 @@ Package messages provides encoding/decoding feature of GTPv0 protocol.
 */
 package messages
 
+//go:generate go run ../../internal/genconst -file $GOFILE -type MessageType -block "MessageType definitions." -trim MsgType
+
 import (
 	"github.com/pkg/errors"
 )
@@ -71,6 +73,12 @@ const (
 	MsgTypeTPDU                       = 255
 )
 
+// MessageType is the type of the message type values, which gives the name with String().
+//
+// The MsgType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from Message into MessageType to get the name.
+type MessageType uint8
+
 // Message is an interface that defines Message messages.
 type Message interface {
 	SerializeTo([]byte) error
@@ -81,6 +89,12 @@ type Message interface {
 	MessageType() uint8
 	MessageTypeName() string
 	TID() string
+	RawTID() uint64
+	SetTID(uint64)
+	Sequence() uint16
+	SetSequenceNumber(uint16)
+	Label() uint16
+	SetFlowLabel(uint16)
 }
 
 // Serialize returns the byte sequence generated from a Message instance.
@@ -94,8 +108,21 @@ func Serialize(g Message) ([]byte, error) {
 	return b, nil
 }
 
+// DetachPayload makes the Payload of the message a copy of its own after SerializeTo,
+// which otherwise refers to the buffer given, so that the buffer can be reused for
+// other messages while the message is kept.
+func DetachPayload(m Message) {
+	if h, ok := m.(interface{ detachPayload() }); ok {
+		h.detachPayload()
+	}
+}
+
 // Decode decodes the given bytes as Message.
 func Decode(b []byte) (Message, error) {
+	if len(b) < 2 {
+		return nil, ErrTooShortToDecode
+	}
+
 	var g Message
 
 	switch b[1] {
@@ -127,6 +154,8 @@ func Decode(b []byte) (Message, error) {
 		g = &DeletePDPContextRequest{}
 	case MsgTypeDeletePDPContextResponse:
 		g = &DeletePDPContextResponse{}
+	case MsgTypeErrorIndication:
+		g = &ErrorIndication{}
 	/* XXX - Implement!
 	case MsgTypeCreateAAPDPContextRequest:
 		g = &CreateAAPDPContextReq{}
@@ -136,8 +165,6 @@ func Decode(b []byte) (Message, error) {
 		g = &DeleteAAPDPContextReq{}
 	case MsgTypeDeleteAAPDPContextResponse:
 		g = &DeleteAAPDPContextRes{}
-	case MsgTypeErrorIndication:
-		g = &ErrorInd{}
 	case MsgTypePDUNotificationRequest:
 		g = &PDUNotificationReq{}
 	case MsgTypePDUNotificationResponse:
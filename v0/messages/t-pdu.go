@@ -72,6 +72,11 @@ func (t *TPDU) SetLength() {
 	t.Header.Length = uint16(len(t.Payload))
 }
 
+// Decapsulate returns payload as raw []byte.
+func (t *TPDU) Decapsulate() []byte {
+	return t.Header.Payload
+}
+
 // MessageTypeName returns the name of protocol.
 func (t *TPDU) MessageTypeName() string {
 	return "T-PDU"
@@ -77,10 +77,9 @@ func (u *UpdatePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
-	if u.Header.Payload != nil {
-		u.Header.Payload = nil
+	if err := u.Header.preparePayload(b, u.Len()); err != nil {
+		return err
 	}
-	u.Header.Payload = make([]byte, u.Len()-u.Header.Len())
 
 	offset := 0
 	if ie := u.RAI; ie != nil {
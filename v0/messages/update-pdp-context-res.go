@@ -83,10 +83,9 @@ func (u *UpdatePDPContextResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (u *UpdatePDPContextResponse) SerializeTo(b []byte) error {
-	if u.Header.Payload != nil {
-		u.Header.Payload = nil
+	if err := u.Header.preparePayload(b, u.Len()); err != nil {
+		return err
 	}
-	u.Header.Payload = make([]byte, u.Len()-u.Header.Len())
 
 	offset := 0
 	if ie := u.Cause; ie != nil {
@@ -0,0 +1,154 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+// Session is a GTPv0 Session, which corresponds to a PDP context identified by TID.
+type Session struct {
+	mu       sync.Mutex
+	isActive bool
+	*flowLabelMap
+
+	// PeerAddr is a net.Addr of the peer of the Session.
+	PeerAddr net.Addr
+
+	// Sequence is the last SequenceNumber used in the request.
+	// This should be incremented when used manually by users.
+	Sequence uint16
+
+	// TID is the Tunnel Identifier of the PDP context, which is made of IMSI and NSAPI.
+	TID uint64
+
+	// IMSI and NSAPI are the values contained in TID.
+	IMSI  string
+	NSAPI uint8
+
+	// MSISDN, APN, SubscriberIP and QoSProfile are the attributes of the PDP context.
+	// They are not set automatically except in (*Conn).CreatePDPContext.
+	MSISDN       string
+	APN          string
+	SubscriberIP string
+	QoSProfile   []byte
+}
+
+// NewSession creates a new Session with the TID given.
+//
+// This is expected to be used by server-like nodes. Otherwise, use CreatePDPContext(),
+// which sends Create PDP Context Request and returns a new Session.
+func NewSession(peerAddr net.Addr, tid uint64) *Session {
+	s := &Session{
+		mu:           sync.Mutex{},
+		flowLabelMap: newFlowLabelMap(),
+		PeerAddr:     peerAddr,
+		TID:          tid,
+	}
+	s.IMSI, s.NSAPI = ParseTID(tid)
+
+	u16buf := make([]byte, 2)
+	if _, err := rand.Read(u16buf); err != nil {
+		u16buf = []byte{0x00, 0x00}
+	}
+	s.Sequence = binary.BigEndian.Uint16(u16buf)
+
+	return s
+}
+
+// Delete sends a Delete PDP Context Request with the Flow Label of the interface
+// specified with ifType, which should be the one allocated by the peer.
+func (s *Session) Delete(c *Conn, ifType uint8, ie ...*ies.IE) error {
+	// do nothing for non-active Session
+	if !s.IsActive() {
+		return nil
+	}
+
+	return c.DeletePDPContext(s.TID, ifType, ie...)
+}
+
+// Update sends an Update PDP Context Request with the Flow Label of the interface
+// specified with ifType, which should be the one allocated by the peer.
+func (s *Session) Update(c *Conn, ifType uint8, ie ...*ies.IE) error {
+	// do nothing for non-active Session
+	if !s.IsActive() {
+		return nil
+	}
+
+	return c.UpdatePDPContext(s.TID, ifType, ie...)
+}
+
+// Activate marks a Session active.
+func (s *Session) Activate() error {
+	if s.TID == 0 {
+		return &ErrRequiredParameterMissing{"TID", "Session must have TID set"}
+	}
+
+	s.mu.Lock()
+	s.isActive = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Deactivate marks a Session inactive.
+func (s *Session) Deactivate() error {
+	s.mu.Lock()
+	s.isActive = false
+	s.mu.Unlock()
+	return nil
+}
+
+// IsActive reports whether a Session is active or not.
+func (s *Session) IsActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isActive
+}
+
+// AddFlowLabel adds Flow Label to session with InterfaceType.
+//
+// The InterfaceType is one of the IFType* constants, which are used only for
+// identifying the Flow Label in the Session and never appear on the wire.
+func (s *Session) AddFlowLabel(ifType uint8, label uint16) {
+	s.flowLabelMap.store(ifType, label)
+}
+
+// GetFlowLabel returns Flow Label associated with InterfaceType given.
+func (s *Session) GetFlowLabel(ifType uint8) (uint16, error) {
+	if label, ok := s.flowLabelMap.load(ifType); ok {
+		return label, nil
+	}
+	return 0, ErrFlowLabelNotFound
+}
+
+type flowLabelMap struct {
+	syncMap sync.Map
+}
+
+func newFlowLabelMap() *flowLabelMap {
+	return &flowLabelMap{}
+}
+
+func (f *flowLabelMap) store(ifType uint8, label uint16) {
+	f.syncMap.Store(ifType, label)
+}
+
+func (f *flowLabelMap) load(ifType uint8) (uint16, bool) {
+	label, ok := f.syncMap.Load(ifType)
+	if !ok {
+		return 0, false
+	}
+
+	return label.(uint16), true
+}
+
+func (f *flowLabelMap) rangeWithFunc(fn func(ifType, label interface{}) bool) {
+	f.syncMap.Range(fn)
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// NewTID creates a TID from IMSI and NSAPI.
+//
+// TID consists of IMSI in swapped BCD with the unused digits filled with 0xf,
+// and NSAPI in the last digit.
+func NewTID(imsi string, nsapi uint8) (uint64, error) {
+	if len(imsi) > 15 || nsapi > 0xf {
+		return 0, ErrInvalidTID
+	}
+
+	b, err := utils.StrToSwappedBytes(
+		imsi+strings.Repeat("f", 15-len(imsi))+strconv.FormatUint(uint64(nsapi), 16), "f",
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// ParseTID returns IMSI and NSAPI in the TID given.
+func ParseTID(tid uint64) (imsi string, nsapi uint8) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, tid)
+
+	s := utils.SwappedBytesToStr(b, false)
+	n, err := strconv.ParseUint(s[15:], 16, 8)
+	if err != nil {
+		return "", 0
+	}
+
+	return strings.TrimRight(s[:15], "f"), uint8(n)
+}
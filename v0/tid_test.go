@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0_test
+
+import (
+	"testing"
+
+	v0 "github.com/wmnsk/go-gtp/v0"
+)
+
+func TestTID(t *testing.T) {
+	cases := []struct {
+		description string
+		imsi        string
+		nsapi       uint8
+		tid         uint64
+	}{
+		{"15-digit", "123456789012345", 5, 0x2143658709214355},
+		{"14-digit", "12345678901234", 0xf, 0x21436587092143ff},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			tid, err := v0.NewTID(c.imsi, c.nsapi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tid != c.tid {
+				t.Errorf("wrong TID. want: %#016x, got: %#016x", c.tid, tid)
+			}
+
+			imsi, nsapi := v0.ParseTID(tid)
+			if imsi != c.imsi {
+				t.Errorf("wrong IMSI. want: %s, got: %s", c.imsi, imsi)
+			}
+			if nsapi != c.nsapi {
+				t.Errorf("wrong NSAPI. want: %d, got: %d", c.nsapi, nsapi)
+			}
+		})
+	}
+
+	if _, err := v0.NewTID("1234567890123456", 5); err != v0.ErrInvalidTID {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
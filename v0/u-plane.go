@@ -0,0 +1,115 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+type tpduSet struct {
+	raddr   net.Addr
+	label   uint16
+	tid     uint64
+	seq     uint16
+	payload []byte
+}
+
+// ReadFromGTP reads a T-PDU from the connection, copying the payload without
+// GTP header into p. It returns the number of bytes copied into p, the return
+// address that was on the packet, Flow Label and TID in the GTP header.
+//
+// The T-PDUs with unknown Flow Label are not passed to the reader, and the
+// Error Indication is sent to the peer instead, as long as the validation is enabled.
+func (c *Conn) ReadFromGTP(p []byte) (n int, addr net.Addr, label uint16, tid uint64, err error) {
+	select {
+	case <-c.closed():
+		err = ErrConnNotOpened
+		return
+	case tpdu, ok := <-c.tpduCh:
+		if !ok {
+			err = ErrConnNotOpened
+			return
+		}
+		n = copy(p, tpdu.payload)
+		addr = tpdu.raddr
+		label = tpdu.label
+		tid = tpdu.tid
+		return
+	}
+}
+
+// WriteToGTP writes a packet with Flow Label, TID and payload to addr.
+//
+// The Flow Label should be the one allocated by the peer for the PDP context.
+func (c *Conn) WriteToGTP(label uint16, tid uint64, p []byte, addr net.Addr) (n int, err error) {
+	msg := Encapsulate(label, tid, p)
+	if err := c.SendMessageTo(msg, addr); err != nil {
+		return 0, err
+	}
+	return msg.Len(), nil
+}
+
+type peer struct {
+	label   uint16
+	tid     uint64
+	addr    net.Addr
+	srcConn *Conn
+}
+
+// RelayTo relays T-PDU type of packet to peer node(specified by raddr) from the Conn given,
+// rewriting the Flow Label and TID with labelOut and tidOut.
+//
+// By using this, owner of Conn won't be able to Read and Write the packets that has labelIn.
+func (c *Conn) RelayTo(conn *Conn, labelIn, labelOut uint16, tidOut uint64, raddr net.Addr) error {
+	c.relayMu.Lock()
+	defer c.relayMu.Unlock()
+
+	if c.relayMap == nil {
+		c.relayMap = map[uint16]*peer{}
+	}
+	c.relayMap[labelIn] = &peer{label: labelOut, tid: tidOut, addr: raddr, srcConn: conn}
+	return nil
+}
+
+// RemoveRelay removes the relay registered for labelIn.
+//
+// The packets with labelIn are no longer relayed after this returns, and they are passed
+// to the handler for T-PDU instead (by default, they can be read by ReadFromGTP).
+// If no relay is registered for labelIn, this returns ErrRelayNotFound.
+func (c *Conn) RemoveRelay(labelIn uint16) error {
+	c.relayMu.Lock()
+	defer c.relayMu.Unlock()
+
+	if _, ok := c.relayMap[labelIn]; !ok {
+		return ErrRelayNotFound
+	}
+	delete(c.relayMap, labelIn)
+	return nil
+}
+
+func (c *Conn) hasRelay() bool {
+	c.relayMu.RLock()
+	defer c.relayMu.RUnlock()
+	return len(c.relayMap) != 0
+}
+
+// relay relays the T-PDU in payload to the peer registered for label.
+// The first returned value is false if no relay is registered for label, which means
+// the T-PDU should be handled by the Conn itself.
+func (c *Conn) relay(label uint16, payload []byte) (bool, error) {
+	c.relayMu.RLock()
+	p, ok := c.relayMap[label]
+	c.relayMu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+
+	// just use original packet not to get it slow.
+	binary.BigEndian.PutUint16(payload[6:8], p.label)
+	binary.BigEndian.PutUint64(payload[12:20], p.tid)
+	_, err := p.srcConn.WriteTo(payload, p.addr)
+	return true, err
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	v0 "github.com/wmnsk/go-gtp/v0"
+)
+
+var testPayload = []byte{0xde, 0xad, 0xbe, 0xef}
+
+func setupUPlane(errCh chan error, addrs ...string) ([]*v0.Conn, error) {
+	var conns []*v0.Conn
+	for _, addr := range addrs {
+		laddr, err := net.ResolveUDPAddr("udp", addr)
+		if err != nil {
+			return nil, err
+		}
+		c, err := v0.ListenAndServe(laddr, 0, errCh)
+		if err != nil {
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return nil, err
+		}
+		conns = append(conns, c)
+	}
+	return conns, nil
+}
+
+func TestEncapsulate(t *testing.T) {
+	b, err := v0.Encapsulate(0x1111, 0x2143658709214355, testPayload).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 20+len(testPayload) {
+		t.Fatalf("unexpected length: %d", len(b))
+	}
+
+	label, tid, p, err := v0.Decapsulate(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != 0x1111 {
+		t.Errorf("unexpected Flow Label: %#x", label)
+	}
+	if tid != 0x2143658709214355 {
+		t.Errorf("unexpected TID: %#x", tid)
+	}
+	if !bytes.Equal(p, testPayload) {
+		t.Errorf("unexpected testPayload: %x", p)
+	}
+}
+
+func TestReadWriteGTP(t *testing.T) {
+	errCh := make(chan error)
+	conns, err := setupUPlane(errCh, "127.0.0.87:3386", "127.0.0.88:3386")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, srvConn := conns[0], conns[1]
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	tid, err := v0.NewTID("123451234567890", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := v0.NewSession(cliConn.LocalAddr(), tid)
+	sess.AddFlowLabel(v0.IFTypeGGSNDataI, 0x1111)
+	srvConn.AddSession(sess)
+
+	if _, err := cliConn.WriteToGTP(0x1111, tid, testPayload, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1500)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		n, _, label, gotTID, err := srvConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if label != 0x1111 {
+			t.Errorf("unexpected Flow Label: %#x", label)
+		}
+		if gotTID != tid {
+			t.Errorf("unexpected TID: %#x", gotTID)
+		}
+		if !bytes.Equal(buf[:n], testPayload) {
+			t.Errorf("unexpected testPayload: %x", buf[:n])
+		}
+	}()
+
+	select {
+	case <-doneCh:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for T-PDU")
+	}
+}
+
+func TestErrorIndication(t *testing.T) {
+	var (
+		srvErrCh = make(chan error)
+		cliErrCh = make(chan error)
+	)
+	srvConns, err := setupUPlane(srvErrCh, "127.0.0.89:3386")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConns, err := setupUPlane(cliErrCh, "127.0.0.90:3386")
+	if err != nil {
+		srvConns[0].Close()
+		t.Fatal(err)
+	}
+	cliConn, srvConn := cliConns[0], srvConns[0]
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	// no Session is registered for the Flow Label in srvConn.
+	if _, err := cliConn.WriteToGTP(0x9999, 0x2143658709214355, testPayload, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotSrvErr, gotCliErr bool
+	for !gotSrvErr || !gotCliErr {
+		select {
+		case err := <-srvErrCh:
+			if err != v0.ErrUnknownFlowLabel {
+				t.Fatalf("unexpected error in server: %v", err)
+			}
+			gotSrvErr = true
+		case err := <-cliErrCh:
+			if err != v0.ErrErrorIndicated {
+				t.Fatalf("unexpected error in client: %v", err)
+			}
+			gotCliErr = true
+		case <-time.After(1 * time.Second):
+			t.Fatal("timed out while waiting for Error Indication")
+		}
+	}
+}
+
+func TestRelayTo(t *testing.T) {
+	errCh := make(chan error)
+	conns, err := setupUPlane(errCh, "127.0.0.91:3386", "127.0.0.92:3386", "127.0.0.93:3386")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, relayConn, srvConn := conns[0], conns[1], conns[2]
+	defer func() { cliConn.Close(); relayConn.Close(); srvConn.Close() }()
+
+	sess := v0.NewSession(relayConn.LocalAddr(), 0x2143658709214355)
+	sess.AddFlowLabel(v0.IFTypeGGSNDataI, 0x1111)
+	srvConn.AddSession(sess)
+
+	if err := relayConn.RelayTo(relayConn, 0x2222, 0x1111, 0x2143658709214355, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliConn.WriteToGTP(0x2222, 0x2143658709214355, testPayload, relayConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1500)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		n, raddr, label, _, err := srvConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if raddr.String() != relayConn.LocalAddr().String() {
+			t.Errorf("unexpected sender: %s", raddr)
+		}
+		if label != 0x1111 {
+			t.Errorf("unexpected Flow Label: %#x", label)
+		}
+		if !bytes.Equal(buf[:n], testPayload) {
+			t.Errorf("unexpected testPayload: %x", buf[:n])
+		}
+	}()
+
+	select {
+	case <-doneCh:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for relayed T-PDU")
+	}
+
+	if err := relayConn.RemoveRelay(0x2222); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.RemoveRelay(0x2222); err != v0.ErrRelayNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v0
+
+import "github.com/wmnsk/go-gtp/v0/messages"
+
+// Encapsulate encapsulates given bytes with GTPv0 Header and returns in message.TPDU.
+func Encapsulate(label uint16, tid uint64, payload []byte) *messages.TPDU {
+	pdu := messages.NewTPDU(0, label, tid, payload)
+	return pdu
+}
+
+// Decapsulate decapsulates given bytes and returns Flow Label, TID and Payload.
+func Decapsulate(b []byte) (uint16, uint64, []byte, error) {
+	header, err := messages.DecodeHeader(b)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if header.Type != messages.MsgTypeTPDU {
+		return 0, 0, nil, messages.ErrInvalidMessageType
+	}
+	return header.FlowLabel, header.TID, header.Payload, nil
+}
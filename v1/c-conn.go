@@ -0,0 +1,631 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// CPlaneConn represents a C-Plane Connection of GTPv1.
+type CPlaneConn struct {
+	mu      sync.Mutex
+	pktConn net.PacketConn
+	*msgHandlerMap
+
+	validationEnabled bool
+	extNotifier       extHeaderNotifier
+	paths             pathManager
+	malformed         malformedReporter
+
+	rcvBuf  []byte
+	closeCh chan struct{}
+	errCh   chan error
+
+	sessMu sync.RWMutex
+
+	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
+	// times the GTPv1-C endpoint is restarted.
+	RestartCounter uint8
+
+	// Sessions is a set of sessions exists on the CPlaneConn.
+	// Use AddSession and RemoveSession to modify it.
+	Sessions []*Session
+}
+
+func newCPlaneConn(pktConn net.PacketConn, counter uint8, errCh chan error) *CPlaneConn {
+	return &CPlaneConn{
+		mu:                sync.Mutex{},
+		pktConn:           pktConn,
+		msgHandlerMap:     newCPlaneHandlerMap(),
+		validationEnabled: true,
+
+		rcvBuf: make([]byte, 2048),
+
+		closeCh: make(chan struct{}),
+		errCh:   errCh,
+
+		RestartCounter: counter,
+	}
+}
+
+// DialCPlane sends Echo Request to raddr to check if the endpoint is alive and
+// returns *CPlaneConn.
+//
+// DialCPlane does not actually Dial() remote address so that the *CPlaneConn can be
+// used with multiple source/destination address.
+//
+// The errCh given should be monitored continuously after retrieving *CPlaneConn.
+// Otherwise the background process may get stuck.
+func DialCPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*CPlaneConn, error) {
+	pktConn, err := net.ListenPacket(raddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	c := newCPlaneConn(pktConn, counter, errCh)
+
+	// if no response coming within 3 seconds, returns error without retrying.
+	if err := c.pktConn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		return nil, err
+	}
+	if err := c.EchoRequest(raddr); err != nil {
+		return nil, err
+	}
+	for {
+		n, _, err := c.pktConn.ReadFrom(c.rcvBuf)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := messages.Decode(c.rcvBuf[:n])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := msg.(*messages.EchoResponse); ok {
+			break
+		}
+	}
+	if err := c.pktConn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	go c.serve()
+	return c, nil
+}
+
+// NewCPlaneConn creates a new CPlaneConn over existing net.PacketConn and start serving.
+//
+// This is for special situation that the user already have a net.PacketConn to be used for
+// GTPv1-C connection. Otherwise, DialCPlane() or ListenAndServeCPlane() should be used.
+func NewCPlaneConn(pktConn net.PacketConn, counter uint8, errCh chan error) *CPlaneConn {
+	c := newCPlaneConn(pktConn, counter, errCh)
+
+	go c.serve()
+	return c
+}
+
+// ListenAndServeCPlane creates a new GTPv1-C *CPlaneConn and start serving.
+//
+// The errCh given should be monitored continuously after retrieving *CPlaneConn.
+// Otherwise the background process may get stuck.
+func ListenAndServeCPlane(laddr net.Addr, counter uint8, errCh chan error) (*CPlaneConn, error) {
+	pktConn, err := net.ListenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	c := newCPlaneConn(pktConn, counter, errCh)
+
+	go c.serve()
+	return c, nil
+}
+
+// closed would be used in multiple goroutines.
+// never send struct{}{} to it; instead, use close(c.closeCh).
+func (c *CPlaneConn) closed() <-chan struct{} {
+	return c.closeCh
+}
+
+func (c *CPlaneConn) serve() {
+	pathCh := make(chan pathMessage, pathQueueSize)
+	go c.servePathManagement(pathCh)
+
+	for {
+		select {
+		case <-c.closed():
+			return
+		default:
+			// do nothing and go forward.
+		}
+
+		n, raddr, err := c.pktConn.ReadFrom(c.rcvBuf)
+		if err != nil {
+			continue
+		}
+
+		// the message is passed to the handler running in another goroutine, so
+		// the IEs must not refer to rcvBuf which is overwritten by the next read.
+		b := make([]byte, n)
+		copy(b, c.rcvBuf[:n])
+
+		msg, err := messages.Decode(b)
+		if err != nil {
+			c.malformed.report(raddr, err)
+			continue
+		}
+
+		if c.extNotifier.shouldNotify(b) {
+			if err := c.SupportedExtensionHeadersNotification(raddr, msg); err != nil {
+				go func() {
+					c.errCh <- err
+				}()
+			}
+			continue
+		}
+
+		c.observePeer(raddr, msg)
+		if isPathManagement(msg.MessageType()) {
+			select {
+			case pathCh <- pathMessage{raddr, msg}:
+			default:
+			}
+			continue
+		}
+
+		if err := c.handleMessage(raddr, msg); err != nil {
+			// errors should be handled by user
+			go func() {
+				c.errCh <- err
+			}()
+		}
+	}
+}
+
+// ReadFrom reads a packet from the connection,
+// copying the payload into p. It returns the number of
+// bytes copied into p and the return address that
+// was on the packet.
+// It returns the number of bytes read (0 <= n <= len(p))
+// and any error encountered. Callers should always process
+// the n > 0 bytes returned before considering the error err.
+// ReadFrom can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetReadDeadline.
+func (c *CPlaneConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	return c.pktConn.ReadFrom(p)
+}
+
+// WriteTo writes a packet with payload p to addr.
+// WriteTo can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetWriteDeadline.
+// On packet-oriented connections, write timeouts are rare.
+func (c *CPlaneConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	return c.pktConn.WriteTo(p, addr)
+}
+
+// Close closes the connection.
+// Any blocked Read or Write operations will be unblocked and return errors.
+func (c *CPlaneConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	close(c.closeCh)
+
+	// unblocks Read() in serve() and releases the address.
+	return c.pktConn.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *CPlaneConn) LocalAddr() net.Addr {
+	return c.pktConn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines associated
+// with the connection. It is equivalent to calling both
+// SetReadDeadline and SetWriteDeadline.
+//
+// A deadline is an absolute time after which I/O operations
+// fail with a timeout (see type Error) instead of
+// blocking. The deadline applies to all future and pending
+// I/O, not just the immediately following call to Read or
+// Write. After a deadline has been exceeded, the connection
+// can be refreshed by setting a deadline in the future.
+//
+// An idle timeout can be implemented by repeatedly extending
+// the deadline after successful Read or Write calls.
+//
+// A zero value for t means I/O operations will not time out.
+func (c *CPlaneConn) SetDeadline(t time.Time) error {
+	return c.pktConn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for future Read calls
+// and any currently-blocked Read call.
+// A zero value for t means Read will not time out.
+func (c *CPlaneConn) SetReadDeadline(t time.Time) error {
+	return c.pktConn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for future Write calls
+// and any currently-blocked Write call.
+// Even if write times out, it may return n > 0, indicating that
+// some of the data was successfully written.
+// A zero value for t means Write will not time out.
+func (c *CPlaneConn) SetWriteDeadline(t time.Time) error {
+	return c.pktConn.SetWriteDeadline(t)
+}
+
+// AddHandler adds a message handler to *CPlaneConn.
+//
+// By adding HandlerFuncs, *CPlaneConn (and *Session, *PDPContext created by the
+// *CPlaneConn) will handle the specified type of message with it's paired HandlerFunc
+// when receiving. Messages without registered handlers are just ignored and discarded
+// and the user will get ErrNoHandlersFound error.
+//
+// HandlerFuncs for EchoRequest, EchoResponse and VersionNotSupported are registered by
+// default. These HandlerFuncs can be overwritten by specifying their message types.
+//
+// The HandlerFuncs for the path management messages, i.e., Echo, Version Not Supported
+// and Supported Extension Headers Notification, are called one by one in a goroutine
+// dedicated to them to be handled ahead of the others, so they should return quickly.
+func (c *CPlaneConn) AddHandler(msgType uint8, fn HandlerFunc) {
+	c.msgHandlerMap.store(msgType, fn)
+}
+
+// AddHandlers adds multiple handler funcs at a time.
+//
+// See AddHandler for detailed usage.
+func (c *CPlaneConn) AddHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		c.msgHandlerMap.store(msgType, fn)
+	}
+}
+
+func (c *CPlaneConn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	c.mu.Lock()
+	validationEnabled := c.validationEnabled
+	c.mu.Unlock()
+
+	if validationEnabled {
+		if err := c.validate(senderAddr, msg); err != nil {
+			return err
+		}
+	}
+
+	handle, ok := c.msgHandlerMap.load(msg.MessageType())
+	if !ok {
+		return ErrNoHandlersFound
+	}
+	go func() {
+		if err := handle(c, senderAddr, msg); err != nil {
+			c.errCh <- err
+		}
+	}()
+
+	return nil
+}
+
+// EnableValidation turns on automatic validation of incoming messages.
+// This is expected to be used only after DisableValidation() is used, as the validation
+// is enabled by default.
+func (c *CPlaneConn) EnableValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validationEnabled = true
+}
+
+// DisableValidation turns off automatic validation of incoming messages.
+// It is not recommended to use this except the node is in debugging mode.
+func (c *CPlaneConn) DisableValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validationEnabled = false
+}
+
+func (c *CPlaneConn) validate(senderAddr net.Addr, msg messages.Message) error {
+	// check GTP version
+	if msg.Version() != 1 {
+		if err := c.VersionNotSupported(senderAddr, msg); err != nil {
+			return err
+		}
+		return ErrInvalidVersion
+	}
+
+	// check if TEID is known or not
+	if teid := msg.TEID(); teid != 0 {
+		if _, err := c.GetSessionByTEID(teid); err != nil {
+			return ErrInvalidTEID
+		}
+	}
+	return nil
+}
+
+// EchoRequest sends a EchoRequest.
+func (c *CPlaneConn) EchoRequest(raddr net.Addr) error {
+	b, err := messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter)).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EchoResponse sends a EchoResponse.
+func (c *CPlaneConn) EchoResponse(raddr net.Addr) error {
+	b, err := messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VersionNotSupported just sends VersionNotSupported message.
+func (c *CPlaneConn) VersionNotSupported(raddr net.Addr, received messages.Message) error {
+	b, err := messages.NewVersionNotSupported(0, received.Sequence()).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreatePDPContext sends a CreatePDPContextRequest and stores information given with IE
+// in the Session returned.
+//
+// By creating a Session with this method, a PDPContext named "default" is also created
+// to be used as primary PDP context. The default one can be retrieved by using
+// (*Session) GetDefaultPDPContext() or (*Session) LookupPDPContextByName("default").
+//
+// The TEIDs given with TEID Data I and TEID Control Plane IEs are stored in the Session
+// as the ones of SGSN, as this is sent by SGSN.
+//
+// Note that this method doesn't care IEs given are sufficient or not, as the required IE
+// varies much depending on the context Create PDP Context Request is used.
+func (c *CPlaneConn) CreatePDPContext(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
+	// retrieve values from IEs given.
+	sess := NewSession(raddr, &Subscriber{Location: &Location{}})
+	pdp := sess.GetDefaultPDPContext()
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			sess.IMSI = i.IMSI()
+		case ies.MSISDN:
+			sess.MSISDN = i.MSISDN()
+		case ies.IMEISV:
+			sess.IMEI = i.IMEISV()
+		case ies.RouteingAreaIdentity:
+			sess.MCC = i.MCC()
+			sess.MNC = i.MNC()
+			sess.LAC = i.LAC()
+			sess.RAC = i.RAC()
+		case ies.UserLocationInformation:
+			sess.MCC = i.MCC()
+			sess.MNC = i.MNC()
+			sess.LAC = i.LAC()
+			sess.CI = i.CGI()
+			sess.SAC = i.SAC()
+			sess.RAC = i.RAC()
+		case ies.RATType:
+			sess.RATType = i.RATType()
+		case ies.AccessPointName:
+			pdp.APN = i.AccessPointName()
+		case ies.NSAPI:
+			// the first one is NSAPI and the second one is Linked NSAPI.
+			if pdp.NSAPI == 0 {
+				pdp.NSAPI = i.NSAPI()
+			}
+		case ies.QoSProfile:
+			pdp.QoSProfile = i.QoSProfile()
+		case ies.TEIDCPlane:
+			sess.AddTEID(IFTypeGnGpSGSNGTPC, i.TEID())
+		case ies.TEIDDataI:
+			sess.AddTEID(IFTypeGnGpSGSNGTPU, i.TEID())
+			pdp.SetIncomingTEID(i.TEID())
+		}
+	}
+
+	// set IEs into CreatePDPContextRequest.
+	b, err := messages.NewCreatePDPContextRequest(0, sess.Sequence, ie...).Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// UpdatePDPContext sends a UpdatePDPContextRequest with TEID and IEs given.
+func (c *CPlaneConn) UpdatePDPContext(teid uint32, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(teid)
+	if err != nil {
+		return err
+	}
+
+	b, err := messages.NewUpdatePDPContextRequest(teid, sess.Sequence+1, ie...).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// DeletePDPContext sends a DeletePDPContextRequest with TEID and IEs given.
+func (c *CPlaneConn) DeletePDPContext(teid uint32, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(teid)
+	if err != nil {
+		return err
+	}
+
+	b, err := messages.NewDeletePDPContextRequest(teid, sess.Sequence+1, ie...).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// RespondTo sends a message(specified with "toBeSent" param) in response to
+// a message(specified with "received" param).
+//
+// This is to make it easier to handle SequenceNumber.
+func (c *CPlaneConn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
+	toBeSent.SetSequenceNumber(received.Sequence())
+	b := make([]byte, toBeSent.Len())
+	if err := toBeSent.SerializeTo(b); err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restarts returns the number of restarts in uint8.
+func (c *CPlaneConn) Restarts() uint8 {
+	return c.RestartCounter
+}
+
+// GetSessionByTEID returns the current session looked up by TEID.
+// The TEID of any interface registered in the Session matches.
+func (c *CPlaneConn) GetSessionByTEID(teid uint32) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	for _, sess := range c.Sessions {
+		found := false
+		sess.teidMap.rangeWithFunc(func(i, t interface{}) bool {
+			if teid == t {
+				found = true
+				return false
+			}
+			return true
+		})
+		if found {
+			return sess, nil
+		}
+	}
+
+	return nil, ErrInvalidTEID
+}
+
+// GetSessionByIMSI returns the current session looked up by IMSI.
+func (c *CPlaneConn) GetSessionByIMSI(imsi string) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	for _, sess := range c.Sessions {
+		if imsi == sess.IMSI {
+			return sess, nil
+		}
+	}
+
+	return nil, ErrUnknownIMSI
+}
+
+// GetIMSIByTEID returns IMSI associated with TEID.
+func (c *CPlaneConn) GetIMSIByTEID(teid uint32) (string, error) {
+	sess, err := c.GetSessionByTEID(teid)
+	if err != nil {
+		return "", err
+	}
+
+	return sess.IMSI, nil
+}
+
+// AddSession adds a session to c.Sessions.
+// If the session with the same IMSI already exists, this replaces the old one.
+func (c *CPlaneConn) AddSession(session *Session) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+
+	for i, oldSession := range c.Sessions {
+		if session.IMSI == oldSession.IMSI {
+			c.Sessions[i] = session
+			return
+		}
+	}
+	c.Sessions = append(c.Sessions, session)
+}
+
+// RemoveSession removes a session from c.Sessions.
+func (c *CPlaneConn) RemoveSession(session *Session) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+
+	var newSessions []*Session
+	for _, sess := range c.Sessions {
+		if session.IMSI == sess.IMSI {
+			continue
+		}
+		newSessions = append(newSessions, sess)
+	}
+
+	c.Sessions = newSessions
+}
+
+// NewTEID returns a non-zero TEID drawn from crypto/rand for ifType, which is different
+// from all the TEIDs in the Sessions on the CPlaneConn regardless of the interface type,
+// as the incoming messages are matched with the TEID of any interface type.
+// If there's a lot of Session on the CPlaneConn, it may take a long time to find unique one.
+func (c *CPlaneConn) NewTEID(ifType uint8) uint32 {
+	c.sessMu.RLock()
+	teids := map[uint32]struct{}{}
+	for _, sess := range c.Sessions {
+		sess.teidMap.rangeWithFunc(func(_, t interface{}) bool {
+			teids[t.(uint32)] = struct{}{}
+			return true
+		})
+	}
+	c.sessMu.RUnlock()
+
+	return generateUniqueUint32(teids)
+}
+
+func generateUniqueUint32(vals map[uint32]struct{}) uint32 {
+	b := make([]byte, 4)
+	for {
+		// crypto/rand.Read never returns an error, and crashes the program
+		// instead if the system fails to provide the random bytes.
+		_, _ = rand.Read(b)
+
+		generated := binary.BigEndian.Uint32(b)
+		if generated == 0 {
+			continue
+		}
+		if _, exists := vals[generated]; !exists {
+			return generated
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func setupCPlane(doneCh chan struct{}, errCh chan error) (cliConn, srvConn *v1.CPlaneConn, err error) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.71:2123")
+	if err != nil {
+		return nil, nil, err
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.72:2123")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	srvConn, err = v1.ListenAndServeCPlane(srvAddr, 0, errCh)
+	if err != nil {
+		return nil, nil, err
+	}
+	srvConn.AddHandler(
+		messages.MsgTypeCreatePDPContextRequest,
+		func(c v1.Conn, cliAddr net.Addr, msg messages.Message) error {
+			req := msg.(*messages.CreatePDPContextRequest)
+			if imsi := req.IMSI.IMSI(); imsi != "123451234567890" {
+				return errors.Errorf("unexpected IMSI: %s", imsi)
+			}
+
+			rsp := messages.NewCreatePDPContextResponse(
+				req.TEIDCPlane.TEID(), 0,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDCPlane(0x22222222),
+			)
+			if err := c.RespondTo(cliAddr, req, rsp); err != nil {
+				return err
+			}
+			doneCh <- struct{}{}
+			return nil
+		},
+	)
+
+	cliConn, err = v1.DialCPlane(cliAddr, srvAddr, 0, errCh)
+	if err != nil {
+		srvConn.Close()
+		return nil, nil, err
+	}
+	return cliConn, srvConn, nil
+}
+
+func TestCreatePDPContext(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspOK   = make(chan uint32)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setupCPlane(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.AddHandler(
+		messages.MsgTypeCreatePDPContextResponse,
+		func(c v1.Conn, srvAddr net.Addr, msg messages.Message) error {
+			rsp := msg.(*messages.CreatePDPContextResponse)
+			if rsp.Cause == nil {
+				return &v1.ErrRequiredIEMissing{Type: ies.Cause}
+			}
+			if cause := rsp.Cause.Cause(); cause != v1.ResCauseRequestAccepted {
+				return &v1.ErrCauseNotOK{
+					MsgType: rsp.MessageTypeName(),
+					Cause:   cause,
+					Msg:     "something went wrong",
+				}
+			}
+			rspOK <- rsp.TEIDCPlane.TEID()
+			return nil
+		},
+	)
+
+	sess, err := cliConn.CreatePDPContext(
+		srvConn.LocalAddr(),
+		ies.NewIMSI("123451234567890"),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewNSAPI(5),
+		ies.NewTEIDDataI(0x11111110),
+		ies.NewTEIDCPlane(0x11111111),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	cliConn.AddSession(sess)
+
+	select {
+	case <-rspSent:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for Create PDP Context Response")
+	}
+
+	var peerTEID uint32
+	select {
+	case peerTEID = <-rspOK:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while validating Create PDP Context Response")
+	}
+	sess.AddTEID(v1.IFTypeGnGpGGSNGTPC, peerTEID)
+
+	got, err := cliConn.GetSessionByIMSI("123451234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sess {
+		t.Error("GetSessionByIMSI returned unexpected Session")
+	}
+	got, err = cliConn.GetSessionByTEID(0x22222222)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sess {
+		t.Error("GetSessionByTEID returned unexpected Session")
+	}
+
+	pdp, err := sess.LookupPDPContextByNSAPI(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pdp != sess.GetDefaultPDPContext() {
+		t.Error("LookupPDPContextByNSAPI returned unexpected PDPContext")
+	}
+	if pdp.APN != "some.apn.example" {
+		t.Errorf("unexpected APN: %s", pdp.APN)
+	}
+	if teid := pdp.IncomingTEID(); teid != 0x11111110 {
+		t.Errorf("unexpected incoming TEID: %#x", teid)
+	}
+
+	cliConn.RemoveSession(sess)
+	if _, err := cliConn.GetSessionByTEID(0x22222222); err != v1.ErrInvalidTEID {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
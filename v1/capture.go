@@ -0,0 +1,105 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"encoding/binary"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+)
+
+// Direction definitions for the captured packets.
+const (
+	DirectionInbound  = gtppcap.DirectionInbound
+	DirectionOutbound = gtppcap.DirectionOutbound
+)
+
+// CapturedPacket is a packet captured on UPlaneConn.
+type CapturedPacket struct {
+	Timestamp time.Time
+	Direction uint8
+	// LocalAddr and RemoteAddr are the addresses of UPlaneConn and its peer.
+	LocalAddr, RemoteAddr net.Addr
+	// TEID is the TEID in GTP-U header, which is 0 if the packet is not a valid GTP-U.
+	TEID uint32
+	// Data is the raw UDP payload, which is the copy of the one sent/received.
+	Data []byte
+}
+
+// CaptureFunc is a callback to receive the packets captured on UPlaneConn.
+//
+// CaptureFunc is called synchronously in the path of sending and receiving
+// packets. It should return quickly not to slow down the traffic.
+type CaptureFunc func(pkt *CapturedPacket)
+
+type capturer struct {
+	fn    CaptureFunc
+	teids map[uint32]struct{}
+}
+
+// StartCapture starts capturing the packets sent and received on UPlaneConn,
+// including the ones relayed by RelayTo, and passes them to fn.
+//
+// If teids are given, only the packets with those TEIDs in GTP-U header are captured.
+// Otherwise, all the packets are captured.
+// Calling StartCapture again replaces the current one.
+func (u *UPlaneConn) StartCapture(fn CaptureFunc, teids ...uint32) {
+	c := &capturer{fn: fn}
+	if len(teids) > 0 {
+		c.teids = map[uint32]struct{}{}
+		for _, teid := range teids {
+			c.teids[teid] = struct{}{}
+		}
+	}
+	u.capturer.Store(c)
+}
+
+// StopCapture stops capturing packets.
+func (u *UPlaneConn) StopCapture() {
+	u.capturer.Store(nil)
+}
+
+func (u *UPlaneConn) capture(direction uint8, raddr net.Addr, b []byte) {
+	// the capturer is loaded without the lock, as this is called for every packet.
+	c := u.capturer.Load()
+	if c == nil {
+		return
+	}
+
+	var teid uint32
+	if len(b) >= 8 {
+		teid = binary.BigEndian.Uint32(b[4:8])
+	}
+	if c.teids != nil {
+		if _, ok := c.teids[teid]; !ok {
+			return
+		}
+	}
+
+	c.fn(&CapturedPacket{
+		Timestamp:  time.Now(),
+		Direction:  direction,
+		LocalAddr:  u.LocalAddr(),
+		RemoteAddr: raddr,
+		TEID:       teid,
+		Data:       append([]byte{}, b...),
+	})
+}
+
+// NewPcapngCaptureFunc returns a CaptureFunc that writes the packets captured
+// into pcapng with the gtppcap.Writer given.
+//
+// The errors in writing are ignored, as there is no way to return them in CaptureFunc.
+func NewPcapngCaptureFunc(w *gtppcap.Writer) CaptureFunc {
+	return func(pkt *CapturedPacket) {
+		src, dst := pkt.RemoteAddr, pkt.LocalAddr
+		if pkt.Direction == DirectionOutbound {
+			src, dst = dst, src
+		}
+		_ = w.WritePacket(pkt.Timestamp, src, dst, pkt.Direction, pkt.Data)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestCapture(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.41:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raddr, err := net.ResolveUDPAddr("udp", "127.0.0.42:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	var captured []*v1.CapturedPacket
+	uConn.StartCapture(func(pkt *v1.CapturedPacket) {
+		captured = append(captured, pkt)
+	}, 0x11111111)
+
+	for _, teid := range []uint32{0x11111111, 0x22222222} {
+		if _, err := uConn.WriteToGTP(teid, []byte{0xde, 0xad, 0xbe, 0xef}, raddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	uConn.StopCapture()
+	if _, err := uConn.WriteToGTP(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}, raddr); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(captured) != 1 {
+		t.Fatalf("got unexpected number of packets: %d", len(captured))
+	}
+	pkt := captured[0]
+	if pkt.TEID != 0x11111111 || pkt.Direction != v1.DirectionOutbound || len(pkt.Data) != 12 {
+		t.Errorf("got unexpected packet: %+v", pkt)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+// UDPChecksumMode represents how the checksum in the outer UDP header is filled.
+type UDPChecksumMode uint8
+
+// UDPChecksumMode definitions.
+const (
+	// UDPChecksumDefault lets the kernel fill the checksum as usual.
+	UDPChecksumDefault UDPChecksumMode = iota
+
+	// UDPChecksumZero disables the checksum calculation and sends the packets with
+	// zero checksum, which is permitted for tunneling protocols over both IPv4 and
+	// IPv6(RFC 6935, RFC 6936). Receiving zero checksum over IPv6 is also allowed.
+	UDPChecksumZero
+)
+
+// SetUDPChecksum sets how the checksum in the outer UDP header is filled for the
+// packets sent from UPlaneConn.
+//
+// This is available only on Linux; on other platforms ErrNotSupported is returned
+// for any mode other than UDPChecksumDefault. See Supported(FeatureZeroUDPChecksum).
+func (u *UPlaneConn) SetUDPChecksum(mode UDPChecksumMode) error {
+	return setZeroUDPChecksum(u.pktConn, mode == UDPChecksumZero)
+}
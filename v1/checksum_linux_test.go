@@ -0,0 +1,98 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package v1_test
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestUDPChecksumZero(t *testing.T) {
+	// the checksum is seen only by capturing the packets with a raw socket.
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_RAW, syscall.IPPROTO_UDP)
+	if err != nil {
+		t.Skipf("failed to open raw socket: %v", err)
+	}
+	defer syscall.Close(fd)
+	tv := syscall.NsecToTimeval((100 * time.Millisecond).Nanoseconds())
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		t.Fatal(err)
+	}
+
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.31:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	rcvConn, err := net.ListenPacket("udp", "127.0.0.32:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcvConn.Close()
+
+	// capture returns the UDP checksum of the packet sent from laddr to rcvConn.
+	capture := func(t *testing.T) uint16 {
+		t.Helper()
+
+		if _, err := uConn.WriteToGTP(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}, rcvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		// packets must be delivered regardless of the checksum.
+		buf := make([]byte, 1500)
+		if err := rcvConn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		n, _, err := rcvConn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := binary.BigEndian.Uint32(buf[4:8]); n != 12 || got != 0x11111111 {
+			t.Errorf("got unexpected packet: %x", buf[:n])
+		}
+
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				continue
+			}
+			if n < 20 {
+				continue
+			}
+			ihl := int(buf[0]&0x0f) * 4
+			if n < ihl+8 || !net.IP(buf[12:16]).Equal(laddr.IP) || !net.IP(buf[16:20]).Equal(net.IPv4(127, 0, 0, 32)) {
+				continue
+			}
+			return binary.BigEndian.Uint16(buf[ihl+6 : ihl+8])
+		}
+		t.Fatal("timed out while capturing packet")
+		return 0
+	}
+
+	if cs := capture(t); cs == 0 {
+		t.Error("got zero checksum by default")
+	}
+
+	if err := uConn.SetUDPChecksum(v1.UDPChecksumZero); err != nil {
+		t.Fatal(err)
+	}
+	if cs := capture(t); cs != 0 {
+		t.Errorf("got non-zero checksum: %#x", cs)
+	}
+}
@@ -4,6 +4,29 @@
 
 package v1
 
+//go:generate go run ../internal/genconst -file $GOFILE -type IFType -block "InterfaceType definitions." -trim IFType
+//go:generate go run ../internal/genconst -file $GOFILE -type Cause -block "Cause definitions."
+//go:generate go run ../internal/genconst -file $GOFILE -type RATType -block "RATType definitions." -trim RatType
+
+// InterfaceType definitions.
+//
+// Unlike GTPv2, they are not carried on the wire and used only to identify
+// the TEIDs registered in a Session.
+const (
+	IFTypeGnGpSGSNGTPC uint8 = iota
+	IFTypeGnGpSGSNGTPU
+	IFTypeGnGpGGSNGTPC
+	IFTypeGnGpGGSNGTPU
+	IFTypeIuSGSNGTPU
+	IFTypeIuRNCGTPU
+)
+
+// IFType is the type of the InterfaceType values, which gives the name with String().
+//
+// The IFType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into IFType to get the name.
+type IFType uint8
+
 // Cause definitions.
 const (
 	ReqCauseRequestIMSI uint8 = iota
@@ -57,6 +80,12 @@ const (
 	ResCauseSemanticErrorsInPacketFilter
 )
 
+// Cause is the type of the Cause values, which gives the name with String().
+//
+// The ReqCause* and ResCause* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into Cause to get the name.
+type Cause uint8
+
 // SelectionMode definitions.
 const (
 	SelectionModeMSorNetworkProvidedAPNSubscribedVerified uint8 = iota | 0xf0
@@ -134,6 +163,12 @@ const (
 	RatTypeEUTRAN
 )
 
+// RATType is the type of the RAT Type values, which gives the name with String().
+//
+// The RatType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into RATType to get the name.
+type RATType uint8
+
 // UserLocationInformation GeographicLocationType definitions.
 const (
 	LocTypeCGI uint8 = iota
@@ -141,6 +176,13 @@ const (
 	LocTypeRAI
 )
 
+// MS Info Change Reporting Action definitions.
+const (
+	MSInfoChangeReportingActionStopReporting uint8 = iota
+	MSInfoChangeReportingActionStartReportingCGISAI
+	MSInfoChangeReportingActionStartReportingRAI
+)
+
 // APN Restriction definitions.
 const (
 	APNRestrictionNoExistingContextsorRestriction uint8 = iota
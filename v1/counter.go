@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"sync"
+	"time"
+)
+
+// Direction is the direction of the traffic counted by a Counter.
+type Direction uint8
+
+// Direction definitions.
+const (
+	Uplink Direction = iota
+	Downlink
+)
+
+// Counter counts the T-PDUs of a bearer in uplink and downlink, which is typically
+// used to feed the charging.
+type Counter struct {
+	mu    sync.Mutex
+	stats CounterStats
+}
+
+// CounterStats is a set of counters of a Counter.
+//
+// LastActivity is the time when the last packet is counted, which is zero if
+// no packet is counted yet.
+type CounterStats struct {
+	UplinkPackets, UplinkBytes     uint64
+	DownlinkPackets, DownlinkBytes uint64
+	LastActivity                   time.Time
+}
+
+// NewCounter creates a new Counter.
+func NewCounter() *Counter {
+	return &Counter{}
+}
+
+// Add counts the packet of the size given in the direction.
+func (c *Counter) Add(dir Direction, size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch dir {
+	case Uplink:
+		c.stats.UplinkPackets++
+		c.stats.UplinkBytes += uint64(size)
+	case Downlink:
+		c.stats.DownlinkPackets++
+		c.stats.DownlinkBytes += uint64(size)
+	default:
+		return
+	}
+	c.stats.LastActivity = time.Now()
+}
+
+// Stats returns the current counters of the Counter.
+func (c *Counter) Stats() CounterStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// counterEntry is the Counter registered for a TEID with the direction.
+type counterEntry struct {
+	counter *Counter
+	dir     Direction
+}
+
+// SetCounter sets the Counter to the T-PDUs received with teidIn, counted as the
+// traffic in dir. The T-PDUs are counted whether they are relayed or passed to
+// ReadFromGTP.
+//
+// The same Counter can be set to several TEIDs and UPlaneConns, e.g., the S1-U
+// TEID as Uplink and the S5-U TEID as Downlink on S-GW.
+//
+// The size of the inner packet is counted, without the overhead of GTP-U encapsulation.
+func (u *UPlaneConn) SetCounter(teidIn uint32, dir Direction, c *Counter) {
+	u.counterMu.Lock()
+	defer u.counterMu.Unlock()
+
+	if u.rcvCounterMap == nil {
+		u.rcvCounterMap = map[uint32]counterEntry{}
+	}
+	u.rcvCounterMap[teidIn] = counterEntry{counter: c, dir: dir}
+}
+
+// RemoveCounter removes the Counter set for teidIn.
+func (u *UPlaneConn) RemoveCounter(teidIn uint32) {
+	u.counterMu.Lock()
+	defer u.counterMu.Unlock()
+	delete(u.rcvCounterMap, teidIn)
+}
+
+// SetSentCounter sets the Counter to the T-PDUs sent with teidOut, counted as the
+// traffic in dir. The T-PDUs are counted whether they are relayed or written with
+// WriteToGTP, which is useful on the node terminating the tunnel, e.g., P-GW.
+func (u *UPlaneConn) SetSentCounter(teidOut uint32, dir Direction, c *Counter) {
+	u.counterMu.Lock()
+	defer u.counterMu.Unlock()
+
+	if u.sndCounterMap == nil {
+		u.sndCounterMap = map[uint32]counterEntry{}
+	}
+	u.sndCounterMap[teidOut] = counterEntry{counter: c, dir: dir}
+}
+
+// RemoveSentCounter removes the Counter set for teidOut.
+func (u *UPlaneConn) RemoveSentCounter(teidOut uint32) {
+	u.counterMu.Lock()
+	defer u.counterMu.Unlock()
+	delete(u.sndCounterMap, teidOut)
+}
+
+// countReceived counts the serialized T-PDU received with teid if the Counter is set.
+func (u *UPlaneConn) countReceived(teid uint32, payload []byte) {
+	u.counterMu.RLock()
+	e, ok := u.rcvCounterMap[teid]
+	u.counterMu.RUnlock()
+	if ok {
+		e.counter.Add(e.dir, innerLen(payload))
+	}
+}
+
+// countSent counts the inner packet of the size sent with teid if the Counter is set.
+func (u *UPlaneConn) countSent(teid uint32, size int) {
+	u.counterMu.RLock()
+	e, ok := u.sndCounterMap[teid]
+	u.counterMu.RUnlock()
+	if ok {
+		e.counter.Add(e.dir, size)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestCounter(t *testing.T) {
+	c := v1.NewCounter()
+	if !c.Stats().LastActivity.IsZero() {
+		t.Error("LastActivity is set before counting")
+	}
+
+	c.Add(v1.Uplink, 100)
+	c.Add(v1.Uplink, 200)
+	c.Add(v1.Downlink, 1000)
+
+	want := v1.CounterStats{
+		UplinkPackets: 2, UplinkBytes: 300,
+		DownlinkPackets: 1, DownlinkBytes: 1000,
+	}
+	got := c.Stats()
+	if diff := cmp.Diff(got, want, cmpopts.IgnoreFields(v1.CounterStats{}, "LastActivity")); diff != "" {
+		t.Error(diff)
+	}
+	if got.LastActivity.IsZero() {
+		t.Error("LastActivity is not set")
+	}
+}
+
+func TestCounterOnConn(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.91:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.92:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v1.ListenAndServeUPlane(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	teid := uint32(0x11111111)
+	sent, rcvd := v1.NewCounter(), v1.NewCounter()
+	cliConn.SetSentCounter(teid, v1.Uplink, sent)
+	srvConn.SetCounter(teid, v1.Downlink, rcvd)
+
+	buf := make([]byte, 2048)
+	for i := 0; i < 2; i++ {
+		if _, err := cliConn.WriteToGTP(teid, []byte{0xde, 0xad, 0xbe, 0xef}, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, err := srvConn.ReadFromGTP(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opt := cmpopts.IgnoreFields(v1.CounterStats{}, "LastActivity")
+	if diff := cmp.Diff(sent.Stats(), v1.CounterStats{UplinkPackets: 2, UplinkBytes: 8}, opt); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(rcvd.Stats(), v1.CounterStats{DownlinkPackets: 2, DownlinkBytes: 8}, opt); diff != "" {
+		t.Error(diff)
+	}
+}
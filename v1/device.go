@@ -0,0 +1,26 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+// BindToDevice binds UPlaneConn to the network device or VRF specified by name
+// (SO_BINDTODEVICE), so that the tunnels are served in the routing domain of the
+// device. An empty name removes the binding.
+//
+// This is available only on Linux and requires CAP_NET_RAW in general; on other
+// platforms ErrNotSupported is returned for any name other than empty. See
+// Supported(FeatureBindToDevice).
+func (u *UPlaneConn) BindToDevice(name string) error {
+	return bindToDevice(u.pktConn, name)
+}
+
+// BindToDevice binds CPlaneConn to the network device or VRF specified by name
+// (SO_BINDTODEVICE). See (*UPlaneConn).BindToDevice for details.
+//
+// Note that the Echo exchanged in DialCPlane is sent before the binding. Use
+// ListenAndServeCPlane and then BindToDevice to send all the messages through
+// the device.
+func (c *CPlaneConn) BindToDevice(name string) error {
+	return bindToDevice(c.pktConn, name)
+}
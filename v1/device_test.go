@@ -0,0 +1,64 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestBindToDevice(t *testing.T) {
+	if !v1.Supported(v1.FeatureBindToDevice) {
+		t.Skip("binding to device is not supported on this platform")
+	}
+
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.33:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	if err := uConn.BindToDevice("lo"); err != nil {
+		if errors.Is(err, syscall.EPERM) {
+			t.Skip("no permission to bind to device")
+		}
+		t.Fatal(err)
+	}
+	if err := uConn.BindToDevice("no-such-device"); err == nil {
+		t.Error("binding to nonexistent device should fail")
+	}
+
+	// packets must still be delivered through the bound device.
+	rcvConn, err := net.ListenPacket("udp", "127.0.0.34:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcvConn.Close()
+
+	if _, err := uConn.WriteToGTP(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}, rcvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1500)
+	if err := rcvConn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rcvConn.ReadFrom(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uConn.BindToDevice(""); err != nil {
+		t.Errorf("failed to remove the binding: %v", err)
+	}
+}
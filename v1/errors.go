@@ -26,6 +26,46 @@ var (
 	// ErrConnNotOpened indicates that some operation is failed due to the status of
 	// Conn is not valid.
 	ErrConnNotOpened = errors.New("connection is not opened")
+
+	// ErrMalformedInnerPacket indicates that the packet encapsulated in T-PDU is not
+	// a valid IPv4/IPv6 packet.
+	ErrMalformedInnerPacket = errors.New("malformed inner packet")
+
+	// ErrInvalidPrefix indicates that the prefix to advertise is not the IPv6 prefix.
+	ErrInvalidPrefix = errors.New("invalid IPv6 prefix")
+
+	// ErrNotSupported indicates that the operation is not supported on the platform.
+	ErrNotSupported = errors.New("not supported on this platform")
+
+	// ErrRelayNotFound indicates that no relay is registered for the TEID given.
+	ErrRelayNotFound = errors.New("no relay found for the TEID")
+
+	// ErrInvalidForwardingRule indicates that the ForwardingRule lacks the required fields.
+	ErrInvalidForwardingRule = errors.New("invalid forwarding rule")
+
+	// ErrInvalidVersion indicates that the version of the message specified by the user
+	// is not acceptable for the receiver.
+	ErrInvalidVersion = errors.New("the version is not acceptable for the receiver")
+
+	// ErrInvalidTEID indicates that the TEID value is different from expected one or
+	// not registered in any Session.
+	ErrInvalidTEID = errors.New("got invalid TEID")
+
+	// ErrTEIDNotFound indicates that TEID is not registered for the interface specified.
+	ErrTEIDNotFound = errors.New("no TEID found")
+
+	// ErrUnknownIMSI indicates that the IMSI is different from expected one.
+	ErrUnknownIMSI = errors.New("got unknown IMSI")
+
+	// ErrTimeout indicates that a handler failed to complete its work due to the
+	// absence of messages expected to come from another endpoint.
+	ErrTimeout = errors.New("timed out")
+
+	// ErrNoPDPContextFound indicates that no PDPContext found by lookup methods.
+	ErrNoPDPContextFound = errors.New("no PDPContext found")
+
+	// errDropped is used internally to tell that the packet is dropped intentionally.
+	errDropped = errors.New("packet dropped")
 )
 
 // ErrErrorIndicated indicates that Error Indication message is received on U-Plane Connection.
@@ -37,3 +77,46 @@ type ErrErrorIndicated struct {
 func (e *ErrErrorIndicated) Error() string {
 	return fmt.Sprintf("error received from %s, TEIDDataI: %#x", e.Peer, e.TEID)
 }
+
+// ErrCauseNotOK indicates that the value in Cause IE is not OK.
+type ErrCauseNotOK struct {
+	MsgType string
+	Cause   uint8
+	Msg     string
+}
+
+// Error returns error cause with message.
+func (e *ErrCauseNotOK) Error() string {
+	return fmt.Sprintf("got non-OK Cause: %s in %s; %s", Cause(e.Cause), e.MsgType, e.Msg)
+}
+
+// ErrRequiredIEMissing indicates that the IE required is missing.
+type ErrRequiredIEMissing struct {
+	Type uint8
+}
+
+// Error returns error with missing IE type.
+func (e *ErrRequiredIEMissing) Error() string {
+	return fmt.Sprintf("required IE missing: %d", e.Type)
+}
+
+// ErrRequiredParameterMissing indicates that the parameter required is missing.
+type ErrRequiredParameterMissing struct {
+	Name, Msg string
+}
+
+// Error returns missing parameter with message.
+func (e *ErrRequiredParameterMissing) Error() string {
+	return fmt.Sprintf("required parameter: %s is missing. %s", e.Name, e.Msg)
+}
+
+// ErrTooBig indicates that the packet exceeds the outer MTU after encapsulation.
+type ErrTooBig struct {
+	// MTU is the maximum size of the inner packet that can be sent to the peer.
+	MTU  int
+	TEID uint32
+}
+
+func (e *ErrTooBig) Error() string {
+	return fmt.Sprintf("packet too big for TEID: %#x, MTU: %d", e.TEID, e.MTU)
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// extHeaderNotifier keeps the Extension Header types that the Conn comprehends,
+// and whether to send Supported Extension Headers Notification automatically.
+type extHeaderNotifier struct {
+	mu      sync.RWMutex
+	enabled bool
+	types   []uint8
+}
+
+func (e *extHeaderNotifier) enable(types []uint8) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enabled = true
+	e.types = types
+}
+
+func (e *extHeaderNotifier) disable() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enabled = false
+}
+
+func (e *extHeaderNotifier) supported() []uint8 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.types
+}
+
+// shouldNotify checks if the message in b has the Extension Header that is required
+// to be comprehended by the receiver but not in the supported types.
+// This always returns false if the notification is not enabled.
+func (e *extHeaderNotifier) shouldNotify(b []byte) bool {
+	// E flag is checked first to avoid decoding the header twice for most messages.
+	if len(b) < 1 || b[0]&0x04 == 0 {
+		return false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.enabled {
+		return false
+	}
+
+	header, err := messages.DecodeHeader(b)
+	if err != nil {
+		return false
+	}
+	for _, ext := range header.ExtensionHeaders {
+		// bit 8 of the type means the comprehension is required by the endpoint.
+		if ext.Type&0x80 == 0 {
+			continue
+		}
+		if !e.isSupported(ext.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *extHeaderNotifier) isSupported(typ uint8) bool {
+	for _, t := range e.types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func newSupportedExtensionHeadersNotification(received messages.Message, types []uint8) ([]byte, error) {
+	return messages.NewSupportedExtensionHeadersNotification(
+		0, received.Sequence(),
+		ies.NewExtensionHeaderTypeList(types...),
+	).Serialize()
+}
+
+// EnableExtensionHeaderNotification makes CPlaneConn send Supported Extension Headers
+// Notification automatically when it receives a message with the Extension Header that
+// is required to be comprehended but not in types given. Such messages are discarded
+// without being passed to the handlers.
+func (c *CPlaneConn) EnableExtensionHeaderNotification(types ...uint8) {
+	c.extNotifier.enable(types)
+}
+
+// DisableExtensionHeaderNotification turns off the automatic Supported Extension Headers
+// Notification enabled by EnableExtensionHeaderNotification.
+func (c *CPlaneConn) DisableExtensionHeaderNotification() {
+	c.extNotifier.disable()
+}
+
+// SupportedExtensionHeadersNotification sends a SupportedExtensionHeadersNotification with
+// the types given to EnableExtensionHeaderNotification, in response to the received message.
+func (c *CPlaneConn) SupportedExtensionHeadersNotification(raddr net.Addr, received messages.Message) error {
+	b, err := newSupportedExtensionHeadersNotification(received, c.extNotifier.supported())
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// EnableExtensionHeaderNotification makes UPlaneConn send Supported Extension Headers
+// Notification automatically when it receives a message with the Extension Header that
+// is required to be comprehended but not in types given. Such messages are discarded
+// without being relayed nor passed to the handlers.
+func (u *UPlaneConn) EnableExtensionHeaderNotification(types ...uint8) {
+	u.extNotifier.enable(types)
+}
+
+// DisableExtensionHeaderNotification turns off the automatic Supported Extension Headers
+// Notification enabled by EnableExtensionHeaderNotification.
+func (u *UPlaneConn) DisableExtensionHeaderNotification() {
+	u.extNotifier.disable()
+}
+
+// SupportedExtensionHeadersNotification sends a SupportedExtensionHeadersNotification with
+// the types given to EnableExtensionHeaderNotification, in response to the received message.
+func (u *UPlaneConn) SupportedExtensionHeadersNotification(raddr net.Addr, received messages.Message) error {
+	b, err := newSupportedExtensionHeadersNotification(received, u.extNotifier.supported())
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestExtensionHeaderNotification(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.81:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.EnableExtensionHeaderNotification(messages.ExtHeaderTypeUDPPort, messages.ExtHeaderTypePDUSessionContainer)
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.82:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerConn.Close()
+
+	b, err := messages.NewTPDUWithExtensionHeaders(
+		0x11111111, []byte{0xde, 0xad, 0xbe, 0xef},
+		messages.NewExtensionHeader(messages.ExtHeaderTypePDCPPDUNumber, []byte{0x00, 0x01}),
+	).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peerConn.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := readMessage(t, peerConn)
+	notif, ok := msg.(*messages.SupportedExtensionHeadersNotification)
+	if !ok {
+		t.Fatalf("got unexpected message: %v", msg.MessageTypeName())
+	}
+	want := []uint8{messages.ExtHeaderTypeUDPPort, messages.ExtHeaderTypePDUSessionContainer}
+	if diff := cmp.Diff(want, notif.ExtensionHeaderTypeList.ExtensionHeaderTypeList()); diff != "" {
+		t.Error(diff)
+	}
+}
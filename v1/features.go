@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+// Feature represents an optional feature of UPlaneConn that depends on the platform.
+//
+// The relay, encapsulation and decapsulation of UPlaneConn work on any platform, and
+// the features listed here return ErrNotSupported where they are not available.
+type Feature uint8
+
+// Feature definitions.
+const (
+	// FeatureZeroUDPChecksum is UDPChecksumZero given to SetUDPChecksum.
+	FeatureZeroUDPChecksum Feature = iota
+
+	// FeatureBindToDevice is BindToDevice with the name of the device or VRF.
+	FeatureBindToDevice
+)
+
+// String returns the name of the Feature.
+func (f Feature) String() string {
+	switch f {
+	case FeatureZeroUDPChecksum:
+		return "ZeroUDPChecksum"
+	case FeatureBindToDevice:
+		return "BindToDevice"
+	default:
+		return "Unknown"
+	}
+}
+
+// Supported reports whether the Feature is available on the platform, so that the
+// programs running on multiple platforms can skip the ones unavailable in advance
+// instead of checking ErrNotSupported.
+func Supported(f Feature) bool {
+	switch f {
+	case FeatureZeroUDPChecksum, FeatureBindToDevice:
+		return sockoptSupported
+	default:
+		return false
+	}
+}
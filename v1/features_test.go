@@ -0,0 +1,43 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestUnsupportedFeatures(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	cases := []struct {
+		feature v1.Feature
+		use     func() error
+	}{
+		{v1.FeatureZeroUDPChecksum, func() error { return uConn.SetUDPChecksum(v1.UDPChecksumZero) }},
+		{v1.FeatureBindToDevice, func() error { return uConn.BindToDevice("lo") }},
+	}
+	for _, c := range cases {
+		t.Run(c.feature.String(), func(t *testing.T) {
+			if v1.Supported(c.feature) {
+				t.Skip("supported on this platform")
+			}
+			if err := c.use(); !errors.Is(err, v1.ErrNotSupported) {
+				t.Errorf("got %v, want ErrNotSupported", err)
+			}
+		})
+	}
+}
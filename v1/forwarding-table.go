@@ -0,0 +1,308 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// ForwardingAction represents what UPlaneConn does with the T-PDU matched to a ForwardingRule.
+type ForwardingAction uint8
+
+// ForwardingAction definitions.
+const (
+	// ActionDecap passes the T-PDU to the handler for T-PDU, which by default makes it
+	// readable by ReadFromGTP. This is the same as the T-PDU not matched to any rule.
+	ActionDecap ForwardingAction = iota
+
+	// ActionRelay relays the T-PDU to the peer specified in ForwardingRule, in the same
+	// way as RelayTo does.
+	ActionRelay
+
+	// ActionDrop drops the T-PDU silently.
+	ActionDrop
+
+	// ActionBuffer keeps the T-PDU in UPlaneConn until FlushBuffer or DiscardBuffer is
+	// called for the TEID.
+	ActionBuffer
+)
+
+// DefaultBufferSize is the number of packets buffered per TEID by ActionBuffer
+// when BufferSize in ForwardingRule is not specified.
+const DefaultBufferSize = 64
+
+// ForwardingRule is the rule returned by ForwardingTable for the incoming T-PDU.
+type ForwardingRule struct {
+	Action ForwardingAction
+
+	// Conn, TEID and Addr are the UPlaneConn to send the packet from, the TEID to
+	// be set in the outgoing packet and the destination, used with ActionRelay.
+	Conn *UPlaneConn
+	TEID uint32
+	Addr net.Addr
+
+	// BufferSize is the maximum number of packets buffered with ActionBuffer.
+	// The packets exceeding it are dropped. DefaultBufferSize is used if 0.
+	BufferSize int
+}
+
+// ForwardingTable is the interface to look up the rule for the incoming T-PDU.
+//
+// This is for the external control plane (e.g., PFCP-driven SGW-U/UPF built on top
+// of this package) to program the forwarding behavior of UPlaneConn. The table set
+// by SetForwardingTable takes precedence over the relays registered by RelayTo and
+// the variants, and the T-PDU not matched to any rule is handled as usual.
+//
+// Lookup is called for each T-PDU with the TEID in the header and the source address
+// of the inner IP packet(nil if it is not IP), and must be safe for concurrent use.
+type ForwardingTable interface {
+	Lookup(teid uint32, ueIP net.IP) (*ForwardingRule, bool)
+}
+
+// SetForwardingTable sets the ForwardingTable to UPlaneConn. Giving nil removes it.
+func (u *UPlaneConn) SetForwardingTable(ft ForwardingTable) {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+	u.fwdTable = ft
+}
+
+// DataWaitingHandlerFunc is a handler called when a T-PDU is buffered by ActionBuffer
+// for the TEID that has no packets buffered, which is the "data waiting" to tell the
+// control plane of, e.g., with Downlink Data Notification on S-GW.
+//
+// The handler is called synchronously on receiving the T-PDU, and should return
+// quickly not to block the other packets.
+type DataWaitingHandlerFunc func(teid uint32)
+
+// SetDataWaitingHandler sets the handler called when the first T-PDU is buffered for
+// a TEID. It is called again after the buffer is flushed or discarded. Giving nil
+// removes it.
+func (u *UPlaneConn) SetDataWaitingHandler(fn DataWaitingHandlerFunc) {
+	u.bufMu.Lock()
+	defer u.bufMu.Unlock()
+	u.dataWaitingFn = fn
+}
+
+// bufferedPacket is a T-PDU buffered by ActionBuffer.
+type bufferedPacket struct {
+	raddr   net.Addr
+	payload []byte
+}
+
+// applyForwardingTable handles the T-PDU with the rule in the ForwardingTable.
+// The first returned value is false if the T-PDU should be handled by the UPlaneConn
+// itself, which is when no table is set, no rule is matched, or the rule says so.
+func (u *UPlaneConn) applyForwardingTable(raddr net.Addr, teid uint32, payload, inner []byte) (bool, error) {
+	u.relayMu.RLock()
+	ft := u.fwdTable
+	u.relayMu.RUnlock()
+	if ft == nil {
+		return false, nil
+	}
+
+	rule, ok := ft.Lookup(teid, innerSourceIP(inner))
+	if !ok {
+		return false, nil
+	}
+
+	switch rule.Action {
+	case ActionDecap:
+		return false, nil
+	case ActionDrop:
+		u.stats.drop(DropRule)
+		return true, errDropped
+	case ActionBuffer:
+		return true, u.bufferPacket(raddr, teid, payload, rule.BufferSize)
+	case ActionRelay:
+		if rule.Conn == nil || rule.Addr == nil {
+			return true, ErrInvalidForwardingRule
+		}
+		p := &peer{teid: rule.TEID, addr: rule.Addr, srcConn: rule.Conn}
+
+		u.relayMu.RLock()
+		tb, err := u.forward(p, teid, payload)
+		u.relayMu.RUnlock()
+		if tb != nil {
+			return true, u.handleTooBig(tb.policy, raddr, p.srcConn, tb.header, tb.imtu)
+		}
+		return true, err
+	default:
+		return true, ErrInvalidForwardingRule
+	}
+}
+
+func (u *UPlaneConn) bufferPacket(raddr net.Addr, teid uint32, payload []byte, size int) error {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	u.bufMu.Lock()
+	if u.bufMap == nil {
+		u.bufMap = map[uint32][]*bufferedPacket{}
+	}
+	if len(u.bufMap[teid]) >= size {
+		u.bufMu.Unlock()
+		u.stats.drop(DropBufferFull)
+		return errDropped
+	}
+
+	// payload is on the receive buffer which is reused.
+	b := make([]byte, len(payload))
+	copy(b, payload)
+	first := len(u.bufMap[teid]) == 0
+	u.bufMap[teid] = append(u.bufMap[teid], &bufferedPacket{raddr: raddr, payload: b})
+	fn := u.dataWaitingFn
+	u.bufMu.Unlock()
+
+	if first && fn != nil {
+		fn(teid)
+	}
+	return nil
+}
+
+// FlushBuffer applies the rule in the ForwardingTable to the packets buffered for teid
+// again, in the order they arrived. This is typically called after the rule for teid
+// is changed from ActionBuffer to the other one, e.g., on the UE becoming reachable.
+//
+// The first error occurred is returned after all the packets are processed.
+func (u *UPlaneConn) FlushBuffer(teid uint32) error {
+	u.bufMu.Lock()
+	pkts := u.bufMap[teid]
+	delete(u.bufMap, teid)
+	u.bufMu.Unlock()
+
+	var firstErr error
+	for _, pkt := range pkts {
+		if err := u.reprocess(pkt); err != nil && err != errDropped && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// DiscardBuffer discards the packets buffered for teid and returns the number of them.
+func (u *UPlaneConn) DiscardBuffer(teid uint32) int {
+	u.bufMu.Lock()
+	defer u.bufMu.Unlock()
+
+	n := len(u.bufMap[teid])
+	delete(u.bufMap, teid)
+	return n
+}
+
+// BufferedPackets returns the number of packets buffered for teid.
+func (u *UPlaneConn) BufferedPackets(teid uint32) int {
+	u.bufMu.Lock()
+	defer u.bufMu.Unlock()
+	return len(u.bufMap[teid])
+}
+
+// reprocess handles the buffered packet as if it is received now.
+func (u *UPlaneConn) reprocess(pkt *bufferedPacket) error {
+	msg, err := messages.Decode(pkt.payload)
+	if err != nil {
+		return err
+	}
+
+	tpdu, ok := msg.(*messages.TPDU)
+	if !ok {
+		return u.handleMessage(pkt.raddr, msg)
+	}
+	handled, err := u.applyForwardingTable(pkt.raddr, tpdu.TEID(), pkt.payload, tpdu.Decapsulate())
+	if handled {
+		return err
+	}
+	if u.hasRelay() {
+		relayed, err := u.relay(pkt.raddr, tpdu.TEID(), pkt.payload)
+		if relayed {
+			return err
+		}
+	}
+	return u.handleMessage(pkt.raddr, msg)
+}
+
+// innerSourceIP returns the source address of the inner IP packet, or nil if it
+// is not a valid IPv4 or IPv6 packet.
+func innerSourceIP(b []byte) net.IP {
+	if len(b) < 1 {
+		return nil
+	}
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < 20 {
+			return nil
+		}
+		return net.IP(b[12:16])
+	case 6:
+		if len(b) < 40 {
+			return nil
+		}
+		return net.IP(b[8:24])
+	default:
+		return nil
+	}
+}
+
+// MapForwardingTable is a ForwardingTable implemented with maps, which looks up
+// the rule by TEID first and then by UE IP address.
+type MapForwardingTable struct {
+	mu     sync.RWMutex
+	byTEID map[uint32]*ForwardingRule
+	byUEIP map[string]*ForwardingRule
+}
+
+// NewMapForwardingTable creates a new MapForwardingTable.
+func NewMapForwardingTable() *MapForwardingTable {
+	return &MapForwardingTable{
+		byTEID: map[uint32]*ForwardingRule{},
+		byUEIP: map[string]*ForwardingRule{},
+	}
+}
+
+// Lookup returns the rule for the T-PDU with teid and ueIP.
+func (m *MapForwardingTable) Lookup(teid uint32, ueIP net.IP) (*ForwardingRule, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if r, ok := m.byTEID[teid]; ok {
+		return r, true
+	}
+	if ueIP == nil {
+		return nil, false
+	}
+	r, ok := m.byUEIP[ueIP.String()]
+	return r, ok
+}
+
+// SetRuleByTEID sets the rule applied to the T-PDU with teid.
+func (m *MapForwardingTable) SetRuleByTEID(teid uint32, rule *ForwardingRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byTEID[teid] = rule
+}
+
+// RemoveRuleByTEID removes the rule set by SetRuleByTEID.
+func (m *MapForwardingTable) RemoveRuleByTEID(teid uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.byTEID, teid)
+}
+
+// SetRuleByUEIP sets the rule applied to the T-PDU whose inner source address is ueIP.
+func (m *MapForwardingTable) SetRuleByUEIP(ueIP net.IP, rule *ForwardingRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.byUEIP[ueIP.String()] = rule
+}
+
+// RemoveRuleByUEIP removes the rule set by SetRuleByUEIP.
+func (m *MapForwardingTable) RemoveRuleByUEIP(ueIP net.IP) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.byUEIP, ueIP.String())
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestForwardingTable(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.66:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.67:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerConn.Close()
+
+	relayRule := &v1.ForwardingRule{
+		Action: v1.ActionRelay, Conn: relayConn, TEID: 0x22222222, Addr: peerConn.LocalAddr(),
+	}
+	ft := v1.NewMapForwardingTable()
+	ft.SetRuleByTEID(0x11111111, relayRule)
+	ft.SetRuleByTEID(0x33333333, &v1.ForwardingRule{Action: v1.ActionBuffer})
+	ft.SetRuleByTEID(0x55555555, &v1.ForwardingRule{Action: v1.ActionDecap})
+	relayConn.SetForwardingTable(ft)
+
+	send := func(teid uint32) {
+		t.Helper()
+		tpdu, err := messages.NewTPDU(teid, []byte{0xde, 0xad, 0xbe, 0xef}).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peerConn.WriteTo(tpdu, relayAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("relay", func(t *testing.T) {
+		send(0x11111111)
+		if msg := readMessage(t, peerConn); msg.TEID() != 0x22222222 {
+			t.Errorf("got unexpected TEID: %#x", msg.TEID())
+		}
+	})
+
+	t.Run("buffer", func(t *testing.T) {
+		waitingCh := make(chan uint32, 2)
+		relayConn.SetDataWaitingHandler(func(teid uint32) {
+			waitingCh <- teid
+		})
+		defer relayConn.SetDataWaitingHandler(nil)
+
+		send(0x33333333)
+		send(0x33333333)
+		time.Sleep(100 * time.Millisecond)
+		if n := relayConn.BufferedPackets(0x33333333); n != 2 {
+			t.Fatalf("got unexpected number of buffered packets: %d", n)
+		}
+		// only the first packet tells the data waiting.
+		if n := len(waitingCh); n != 1 {
+			t.Fatalf("data waiting handler called %d times", n)
+		}
+		if teid := <-waitingCh; teid != 0x33333333 {
+			t.Errorf("got unexpected TEID: %#x", teid)
+		}
+
+		ft.SetRuleByTEID(0x33333333, relayRule)
+		if err := relayConn.FlushBuffer(0x33333333); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if msg := readMessage(t, peerConn); msg.TEID() != 0x22222222 {
+				t.Errorf("got unexpected TEID: %#x", msg.TEID())
+			}
+		}
+		if n := relayConn.BufferedPackets(0x33333333); n != 0 {
+			t.Errorf("buffer not flushed: %d", n)
+		}
+	})
+
+	t.Run("decap", func(t *testing.T) {
+		send(0x55555555)
+		buf := make([]byte, 1500)
+		n, _, teid, err := relayConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if teid != 0x55555555 || n != 4 {
+			t.Errorf("got unexpected T-PDU: teid=%#x, len=%d", teid, n)
+		}
+	})
+}
+
+func TestMapForwardingTable(t *testing.T) {
+	ft := v1.NewMapForwardingTable()
+	ueIP := net.ParseIP("10.0.0.1")
+	ft.SetRuleByUEIP(ueIP, &v1.ForwardingRule{Action: v1.ActionDrop})
+	ft.SetRuleByTEID(1, &v1.ForwardingRule{Action: v1.ActionBuffer})
+
+	if r, ok := ft.Lookup(1, ueIP); !ok || r.Action != v1.ActionBuffer {
+		t.Errorf("TEID should be preferred: %v, %v", r, ok)
+	}
+	if r, ok := ft.Lookup(2, net.IP{10, 0, 0, 1}); !ok || r.Action != v1.ActionDrop {
+		t.Errorf("got unexpected rule: %v, %v", r, ok)
+	}
+
+	ft.RemoveRuleByUEIP(ueIP)
+	if _, ok := ft.Lookup(2, ueIP); ok {
+		t.Error("rule not removed")
+	}
+}
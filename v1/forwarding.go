@@ -0,0 +1,112 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"time"
+)
+
+// forwardingTimer is the expiry timer of a forwarding tunnel. The pointer to it
+// identifies the tunnel instance, as the tunnel can be replaced with the same TEID.
+type forwardingTimer struct {
+	*time.Timer
+}
+
+// AddForwardingTunnel registers a temporary tunnel that relays T-PDU with teidIn to
+// raddr over the UPlaneConn given with teidOut, which is removed automatically after
+// expiry. Giving 0 as expiry makes it live until RemoveForwardingTunnel is called.
+//
+// This is for the indirect data forwarding on handover, which is set up with Create
+// Indirect Data Forwarding Tunnel Request/Response and torn down with Delete Indirect
+// Data Forwarding Tunnel Request/Response. Call this once for each of the DL and UL
+// forwarding TEIDs allocated for a bearer, and RemoveForwardingTunnel on deletion.
+// The expiry is the safeguard for the case the deletion never comes.
+//
+// Adding the tunnel with teidIn that already exists replaces it and restarts the timer.
+func (u *UPlaneConn) AddForwardingTunnel(c *UPlaneConn, teidIn, teidOut uint32, raddr net.Addr, expiry time.Duration) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if u.relayMap == nil {
+		u.relayMap = map[relayKey]*peer{}
+	}
+	u.relayMap[newRelayKey(teidIn, nil)] = &peer{teid: teidOut, addr: raddr, srcConn: c}
+
+	if u.fwdTimers == nil {
+		u.fwdTimers = map[uint32]*forwardingTimer{}
+	}
+	if t, ok := u.fwdTimers[teidIn]; ok {
+		t.Stop()
+		delete(u.fwdTimers, teidIn)
+	}
+	if expiry <= 0 {
+		return nil
+	}
+
+	// the callback blocks on relayMu until ft is stored in fwdTimers.
+	ft := &forwardingTimer{}
+	ft.Timer = time.AfterFunc(expiry, func() {
+		u.expireForwardingTunnel(teidIn, ft)
+	})
+	u.fwdTimers[teidIn] = ft
+	return nil
+}
+
+// RemoveForwardingTunnel removes the tunnel registered by AddForwardingTunnel and
+// stops its timer. If no tunnel is registered for teidIn, this returns ErrRelayNotFound.
+func (u *UPlaneConn) RemoveForwardingTunnel(teidIn uint32) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if t, ok := u.fwdTimers[teidIn]; ok {
+		t.Stop()
+		delete(u.fwdTimers, teidIn)
+	}
+
+	key := newRelayKey(teidIn, nil)
+	if _, ok := u.relayMap[key]; !ok {
+		return ErrRelayNotFound
+	}
+	delete(u.relayMap, key)
+	return nil
+}
+
+// expireForwardingTunnel removes the tunnel for teidIn if the timer ft is still the
+// current one, i.e., the tunnel is not removed nor replaced in the meantime.
+func (u *UPlaneConn) expireForwardingTunnel(teidIn uint32, ft *forwardingTimer) {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if cur, ok := u.fwdTimers[teidIn]; !ok || cur != ft {
+		return
+	}
+	delete(u.fwdTimers, teidIn)
+	delete(u.relayMap, newRelayKey(teidIn, nil))
+}
+
+// stopForwardingTimer stops the timer of the forwarding tunnel for key, if any, so
+// that the relay registered in place of it is not removed on expiry.
+// The caller must hold relayMu.
+func (u *UPlaneConn) stopForwardingTimer(key relayKey) {
+	if key.peer.IsValid() {
+		return
+	}
+	if t, ok := u.fwdTimers[key.teid]; ok {
+		t.Stop()
+		delete(u.fwdTimers, key.teid)
+	}
+}
+
+// stopForwardingTimers stops all the timers of the forwarding tunnels.
+func (u *UPlaneConn) stopForwardingTimers() {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	for teid, t := range u.fwdTimers {
+		t.Stop()
+		delete(u.fwdTimers, teid)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestForwardingTunnel(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.64:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.65:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerConn.Close()
+
+	if err := relayConn.AddForwardingTunnel(relayConn, 0x11111111, 0x22222222, peerConn.LocalAddr(), 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	tpdu, err := messages.NewTPDU(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peerConn.WriteTo(tpdu, relayAddr); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, peerConn); msg.TEID() != 0x22222222 {
+		t.Errorf("got unexpected TEID: %#x", msg.TEID())
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if err := relayConn.RemoveForwardingTunnel(0x11111111); err != v1.ErrRelayNotFound {
+		t.Errorf("forwarding tunnel not expired: %v", err)
+	}
+
+	// without expiry, it lives until removed explicitly.
+	if err := relayConn.AddForwardingTunnel(relayConn, 0x11111111, 0x22222222, peerConn.LocalAddr(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.RemoveForwardingTunnel(0x11111111); err != nil {
+		t.Fatal(err)
+	}
+}
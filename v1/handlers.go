@@ -48,9 +48,23 @@ var defaultHandlerMap = newMsgHandlerMap(
 		messages.MsgTypeEchoRequest:     handleEchoRequest,
 		messages.MsgTypeEchoResponse:    handleEchoResponse,
 		messages.MsgTypeErrorIndication: handleErrorIndication,
+		messages.MsgTypeEndMarker:       handleEndMarker,
 	},
 )
 
+// newCPlaneHandlerMap returns the default handlers for CPlaneConn.
+// This is created for each CPlaneConn so that the handlers added to one CPlaneConn
+// do not affect the others.
+func newCPlaneHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:         handleEchoRequest,
+			messages.MsgTypeEchoResponse:        handleEchoResponse,
+			messages.MsgTypeVersionNotSupported: handleVersionNotSupported,
+		},
+	)
+}
+
 func handleTPDU(c Conn, senderAddr net.Addr, msg messages.Message) error {
 	// this should never happen, as the type should have been assured by
 	// msgHandlerMap before this function is called.
@@ -122,3 +136,25 @@ func handleErrorIndication(c Conn, senderAddr net.Addr, msg messages.Message) er
 		Peer: ind.GTPUPeerAddress.IPAddress(),
 	}
 }
+
+func handleEndMarker(c Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EndMarker); !ok {
+		return ErrUnexpectedType
+	}
+
+	// do nothing.
+	return nil
+}
+
+func handleVersionNotSupported(c Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.VersionNotSupported); !ok {
+		return ErrUnexpectedType
+	}
+
+	// let's just return err anyway.
+	return ErrInvalidVersion
+}
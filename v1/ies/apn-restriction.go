@@ -11,7 +11,10 @@ func NewAPNRestriction(restriction uint8) *IE {
 
 // APNRestriction returns APNRestriction in uint8 if type matches.
 func (i *IE) APNRestriction() uint8 {
-	if i.Type != AuthenticationTriplet {
+	if i.Type != APNRestriction {
+		return 0
+	}
+	if len(i.Payload) < 1 {
 		return 0
 	}
 	return i.Payload[0]
@@ -38,8 +38,14 @@ func (i *IE) AuthenticationQuintuplet() []byte {
 func (i *IE) XRES() []byte {
 	switch i.Type {
 	case AuthenticationQuintuplet:
-		xresLen := i.Payload[16]
-		return i.Payload[17 : 17+xresLen]
+		if len(i.Payload) < 17 {
+			return nil
+		}
+		end := 17 + int(i.Payload[16])
+		if len(i.Payload) < end {
+			return nil
+		}
+		return i.Payload[17:end]
 	default:
 		return nil
 	}
@@ -49,7 +55,13 @@ func (i *IE) XRES() []byte {
 func (i *IE) CK() []byte {
 	switch i.Type {
 	case AuthenticationQuintuplet:
-		offset := 17 + i.Payload[16]
+		if len(i.Payload) < 17 {
+			return nil
+		}
+		offset := 17 + int(i.Payload[16])
+		if len(i.Payload) < offset+16 {
+			return nil
+		}
 		return i.Payload[offset : offset+16]
 	default:
 		return nil
@@ -60,7 +72,13 @@ func (i *IE) CK() []byte {
 func (i *IE) IK() []byte {
 	switch i.Type {
 	case AuthenticationQuintuplet:
-		offset := 33 + i.Payload[16]
+		if len(i.Payload) < 17 {
+			return nil
+		}
+		offset := 33 + int(i.Payload[16])
+		if len(i.Payload) < offset+16 {
+			return nil
+		}
 		return i.Payload[offset : offset+16]
 	default:
 		return nil
@@ -71,8 +89,18 @@ func (i *IE) IK() []byte {
 func (i *IE) AUTN() []byte {
 	switch i.Type {
 	case AuthenticationQuintuplet:
-		offset := 49 + i.Payload[16]
-		autnLen := i.Payload[50]
+		if len(i.Payload) < 17 {
+			return nil
+		}
+		offset := 49 + int(i.Payload[16])
+		if len(i.Payload) < offset+1 {
+			return nil
+		}
+		autnLen := int(i.Payload[offset])
+		offset++
+		if len(i.Payload) < offset+autnLen {
+			return nil
+		}
 		return i.Payload[offset : offset+autnLen]
 	default:
 		return nil
@@ -0,0 +1,74 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+var benchIEs = []struct {
+	description string
+	ie          *ies.IE
+}{
+	{"IMSI", ies.NewIMSI("123451234567890")},
+	{"TEIDCPlane", ies.NewTEIDCPlane(0xdeadbeef)},
+	{"AccessPointName", ies.NewAccessPointName("some.apn.example")},
+	{"GSNAddress", ies.NewGSNAddress("1.1.1.1")},
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, c := range benchIEs {
+		b.Run(c.description, func(b *testing.B) {
+			buf := make([]byte, c.ie.Len())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.ie.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, c := range benchIEs {
+		b.Run(c.description, func(b *testing.B) {
+			serialized, err := c.ie.Serialize()
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ies.Decode(serialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeMultiIEs(b *testing.B) {
+	var serialized []byte
+	for _, c := range benchIEs {
+		s, err := c.ie.Serialize()
+		if err != nil {
+			b.Fatal(err)
+		}
+		serialized = append(serialized, s...)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ies.DecodeMultiIEs(serialized); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "net"
+
+// NewChargingGatewayAddress creates a new ChargingGatewayAddress IE.
+//
+// The address is encoded in 4 octets if it is IPv4, and 16 octets if IPv6.
+func NewChargingGatewayAddress(addr string) *IE {
+	return newIPAddrIE(ChargingGatewayAddress, addr)
+}
+
+// ChargingGatewayAddress returns ChargingGatewayAddress value if type matches.
+func (i *IE) ChargingGatewayAddress() string {
+	if ip := i.ChargingGatewayAddressIP(); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// ChargingGatewayAddressIP returns ChargingGatewayAddress in net.IP if type matches.
+// This returns nil if the length is neither of IPv4 nor IPv6.
+func (i *IE) ChargingGatewayAddressIP() net.IP {
+	if i.Type != ChargingGatewayAddress {
+		return nil
+	}
+	return ipAddr(i.Payload)
+}
@@ -21,6 +21,9 @@ func (i *IE) CommonFlags() uint8 {
 	if i.Type != CommonFlags {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
 
@@ -0,0 +1,229 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "fmt"
+
+// names of the IE types, which are defined as constants in this package.
+var names = map[uint8]string{
+	Cause:                                 "Cause",
+	IMSI:                                  "IMSI",
+	RouteingAreaIdentity:                  "RouteingAreaIdentity",
+	TemporaryLogicalLinkIdentity:          "TemporaryLogicalLinkIdentity",
+	PacketTMSI:                            "PacketTMSI",
+	ReorderingRequired:                    "ReorderingRequired",
+	AuthenticationTriplet:                 "AuthenticationTriplet",
+	MAPCause:                              "MAPCause",
+	PTMSISignature:                        "PTMSISignature",
+	MSValidated:                           "MSValidated",
+	Recovery:                              "Recovery",
+	SelectionMode:                         "SelectionMode",
+	TEIDDataI:                             "TEIDDataI",
+	TEIDCPlane:                            "TEIDCPlane",
+	TEIDDataII:                            "TEIDDataII",
+	TeardownInd:                           "TeardownInd",
+	NSAPI:                                 "NSAPI",
+	RANAPCause:                            "RANAPCause",
+	RABContext:                            "RABContext",
+	RadioPrioritySMS:                      "RadioPrioritySMS",
+	RadioPriority:                         "RadioPriority",
+	PacketFlowID:                          "PacketFlowID",
+	ChargingCharacteristics:               "ChargingCharacteristics",
+	TraceReference:                        "TraceReference",
+	TraceType:                             "TraceType",
+	MSNotReachableReason:                  "MSNotReachableReason",
+	ChargingID:                            "ChargingID",
+	EndUserAddress:                        "EndUserAddress",
+	MMContext:                             "MMContext",
+	PDPContext:                            "PDPContext",
+	AccessPointName:                       "AccessPointName",
+	ProtocolConfigurationOptions:          "ProtocolConfigurationOptions",
+	GSNAddress:                            "GSNAddress",
+	MSISDN:                                "MSISDN",
+	QoSProfile:                            "QoSProfile",
+	AuthenticationQuintuplet:              "AuthenticationQuintuplet",
+	TrafficFlowTemplate:                   "TrafficFlowTemplate",
+	TargetIdentification:                  "TargetIdentification",
+	UTRANTransparentContainer:             "UTRANTransparentContainer",
+	RABSetupInformation:                   "RABSetupInformation",
+	ExtensionHeaderTypeList:               "ExtensionHeaderTypeList",
+	TriggerID:                             "TriggerID",
+	OMCIdentity:                           "OMCIdentity",
+	RANTransparentContainer:               "RANTransparentContainer",
+	PDPContextPrioritization:              "PDPContextPrioritization",
+	AdditionalRABSetupInformation:         "AdditionalRABSetupInformation",
+	SGSNNumber:                            "SGSNNumber",
+	CommonFlags:                           "CommonFlags",
+	APNRestriction:                        "APNRestriction",
+	RadioPriorityLCS:                      "RadioPriorityLCS",
+	RATType:                               "RATType",
+	UserLocationInformation:               "UserLocationInformation",
+	MSTimeZone:                            "MSTimeZone",
+	IMEISV:                                "IMEISV",
+	CAMELChargingInformationContainer:     "CAMELChargingInformationContainer",
+	MBMSUEContext:                         "MBMSUEContext",
+	TemporaryMobileGroupIdentity:          "TemporaryMobileGroupIdentity",
+	RIMRoutingAddress:                     "RIMRoutingAddress",
+	MBMSProtocolConfigurationOptions:      "MBMSProtocolConfigurationOptions",
+	MBMSServiceArea:                       "MBMSServiceArea",
+	SourceRNCPDCPContextInfo:              "SourceRNCPDCPContextInfo",
+	AdditionalTraceInfo:                   "AdditionalTraceInfo",
+	HopCounter:                            "HopCounter",
+	SelectedPLMNID:                        "SelectedPLMNID",
+	MBMSSessionIdentifier:                 "MBMSSessionIdentifier",
+	MBMS2G3GIndicator:                     "MBMS2G3GIndicator",
+	EnhancedNSAPI:                         "EnhancedNSAPI",
+	MBMSSessionDuration:                   "MBMSSessionDuration",
+	AdditionalMBMSTraceInfo:               "AdditionalMBMSTraceInfo",
+	MBMSSessionRepetitionNumber:           "MBMSSessionRepetitionNumber",
+	MBMSTimeToDataTransfer:                "MBMSTimeToDataTransfer",
+	BSSContainer:                          "BSSContainer",
+	CellIdentification:                    "CellIdentification",
+	PDUNumbers:                            "PDUNumbers",
+	BSSGPCause:                            "BSSGPCause",
+	RequiredMBMSBearerCapabilities:        "RequiredMBMSBearerCapabilities",
+	RIMRoutingAddressDiscriminator:        "RIMRoutingAddressDiscriminator",
+	ListOfSetupPFCs:                       "ListOfSetupPFCs",
+	PSHandoverXIDParameters:               "PSHandoverXIDParameters",
+	MSInfoChangeReportingAction:           "MSInfoChangeReportingAction",
+	DirectTunnelFlags:                     "DirectTunnelFlags",
+	CorrelationID:                         "CorrelationID",
+	BearerControlMode:                     "BearerControlMode",
+	MBMSFlowIdentifier:                    "MBMSFlowIdentifier",
+	MBMSIPMulticastDistribution:           "MBMSIPMulticastDistribution",
+	MBMSDistributionAcknowledgement:       "MBMSDistributionAcknowledgement",
+	ReliableInterRATHandoverInfo:          "ReliableInterRATHandoverInfo",
+	RFSPIndex:                             "RFSPIndex",
+	FullyQualifiedDomainName:              "FullyQualifiedDomainName",
+	EvolvedAllocationRetentionPriorityI:   "EvolvedAllocationRetentionPriorityI",
+	EvolvedAllocationRetentionPriorityII:  "EvolvedAllocationRetentionPriorityII",
+	ExtendedCommonFlags:                   "ExtendedCommonFlags",
+	UserCSGInformation:                    "UserCSGInformation",
+	CSGInformationReportingAction:         "CSGInformationReportingAction",
+	CSGID:                                 "CSGID",
+	CSGMembershipIndication:               "CSGMembershipIndication",
+	AggregateMaximumBitRate:               "AggregateMaximumBitRate",
+	UENetworkCapability:                   "UENetworkCapability",
+	UEAMBR:                                "UEAMBR",
+	APNAMBRWithNSAPI:                      "APNAMBRWithNSAPI",
+	GGSNBackOffTime:                       "GGSNBackOffTime",
+	SignallingPriorityIndication:          "SignallingPriorityIndication",
+	SignallingPriorityIndicationWithNSAPI: "SignallingPriorityIndicationWithNSAPI",
+	HigherBitratesThan16MbpsFlag:          "HigherBitratesThan16MbpsFlag",
+	AdditionalMMContextForSRVCC:           "AdditionalMMContextForSRVCC",
+	AdditionalFlagsForSRVCC:               "AdditionalFlagsForSRVCC",
+	STNSR:                                 "STNSR",
+	CMSISDN:                               "CMSISDN",
+	ExtendedRANAPCause:                    "ExtendedRANAPCause",
+	ENodeBID:                              "ENodeBID",
+	SelectionModeWithNSAPI:                "SelectionModeWithNSAPI",
+	ULITimestamp:                          "ULITimestamp",
+	LHNIDWithNSAPI:                        "LHNIDWithNSAPI",
+	CNOperatorSelectionEntity:             "CNOperatorSelectionEntity",
+	UEUsageType:                           "UEUsageType",
+	ExtendedCommonFlagsII:                 "ExtendedCommonFlagsII",
+	NodeIdentifier:                        "NodeIdentifier",
+	CIoTOptimizationsSupportIndication:    "CIoTOptimizationsSupportIndication",
+	SCEFPDNConnection:                     "SCEFPDNConnection",
+	IOVUpdatesCounter:                     "IOVUpdatesCounter",
+	MappedUEUsageType:                     "MappedUEUsageType",
+	UPFunctionSelectionIndicationFlags:    "UPFunctionSelectionIndicationFlags",
+	SpecialIETypeForIETypeExtension:       "SpecialIETypeForIETypeExtension",
+	ChargingGatewayAddress:                "ChargingGatewayAddress",
+	PrivateExtension:                      "PrivateExtension",
+}
+
+// Name returns the name of the IE type, or "Unknown" if the type is not known.
+func (i *IE) Name() string {
+	if name, ok := names[i.Type]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// ValueString returns the value of the IE in human readable format.
+//
+// The value is decoded with the getter corresponding to the type, and the
+// Payload is returned in hex instead if there is no such getter or the Payload
+// is malformed.
+func (i *IE) ValueString() (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("%x (malformed: %v)", i.Payload, r)
+		}
+	}()
+
+	if s = i.value(); s == "" {
+		s = fmt.Sprintf("%x", i.Payload)
+	}
+	return s
+}
+
+func (i *IE) value() string {
+	switch i.Type {
+	case Cause:
+		return fmt.Sprintf("%d", i.Cause())
+	case IMSI:
+		return i.IMSI()
+	case RouteingAreaIdentity:
+		return fmt.Sprintf("MCC: %s, MNC: %s, LAC: %#04x, RAC: %#02x", i.MCC(), i.MNC(), i.LAC(), i.RAC())
+	case TemporaryLogicalLinkIdentity:
+		return fmt.Sprintf("%#08x", i.TemporaryLogicalLinkIdentity())
+	case PacketTMSI:
+		return fmt.Sprintf("%#08x", i.PacketTMSI())
+	case ReorderingRequired:
+		return fmt.Sprintf("%v", i.ReorderingRequired())
+	case MAPCause:
+		return fmt.Sprintf("%d", i.MAPCause())
+	case PTMSISignature:
+		return fmt.Sprintf("%#06x", i.PTMSISignature())
+	case MSValidated:
+		return fmt.Sprintf("%v", i.MSValidated())
+	case Recovery:
+		return fmt.Sprintf("%d", i.Recovery())
+	case SelectionMode:
+		return fmt.Sprintf("%d", i.SelectionMode())
+	case TEIDDataI, TEIDCPlane:
+		return fmt.Sprintf("%#08x", i.TEID())
+	case TeardownInd:
+		return fmt.Sprintf("%v", i.TeardownInd())
+	case NSAPI:
+		return fmt.Sprintf("%d", i.NSAPI())
+	case RANAPCause:
+		return fmt.Sprintf("%d", i.RANAPCause())
+	case TraceReference:
+		return fmt.Sprintf("%d", i.TraceReference())
+	case ChargingID:
+		return fmt.Sprintf("%#08x", i.ChargingID())
+	case EndUserAddress:
+		return fmt.Sprintf("PDPTypeOrganization: %d, PDPTypeNumber: %#02x, Address: %s",
+			i.PDPTypeOrganization(), i.PDPTypeNumber(), i.IPAddress())
+	case AccessPointName:
+		return i.AccessPointName()
+	case GSNAddress:
+		return i.GSNAddress()
+	case MSISDN:
+		return i.MSISDN()
+	case ExtensionHeaderTypeList:
+		return fmt.Sprintf("%v", i.ExtensionHeaderTypeList())
+	case CommonFlags:
+		return fmt.Sprintf("%#02x", i.CommonFlags())
+	case APNRestriction:
+		return fmt.Sprintf("%d", i.APNRestriction())
+	case RATType:
+		return fmt.Sprintf("%d", i.RATType())
+	case UserLocationInformation:
+		return fmt.Sprintf("MCC: %s, MNC: %s, LAC: %#04x, raw: %x", i.MCC(), i.MNC(), i.LAC(), i.Payload)
+	case MSTimeZone:
+		return fmt.Sprintf("%s, DaylightSaving: %d", i.TimeZone(), i.DaylightSaving())
+	case IMEISV:
+		return i.IMEISV()
+	case MSInfoChangeReportingAction:
+		return fmt.Sprintf("%d", i.MSInfoChangeReportingAction())
+	case ChargingGatewayAddress:
+		return i.ChargingGatewayAddress()
+	}
+	return ""
+}
@@ -97,6 +97,9 @@ func (i *IE) PDPTypeOrganization() uint8 {
 	if i.Type != EndUserAddress {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
 
@@ -105,6 +108,9 @@ func (i *IE) PDPTypeNumber() uint8 {
 	if i.Type != EndUserAddress {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return i.Payload[1]
 }
 
@@ -120,6 +126,9 @@ func (i *IE) IPAddress() string {
 		}
 		return net.IP(i.Payload[2:]).String()
 	case GSNAddress:
+		if len(i.Payload) == 0 {
+			return ""
+		}
 		return net.IP(i.Payload).String()
 	default:
 		return ""
@@ -0,0 +1,20 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewExtensionHeaderTypeList creates a new ExtensionHeaderTypeList IE.
+//
+// Unlike the other TLV IEs, the Length field of this IE is 1 octet.
+func NewExtensionHeaderTypeList(types ...uint8) *IE {
+	return New(ExtensionHeaderTypeList, types)
+}
+
+// ExtensionHeaderTypeList returns ExtensionHeaderTypeList in []uint8 if type matches.
+func (i *IE) ExtensionHeaderTypeList() []uint8 {
+	if i.Type != ExtensionHeaderTypeList {
+		return nil
+	}
+	return i.Payload
+}
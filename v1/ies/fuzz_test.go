@@ -0,0 +1,63 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+func FuzzDecode(f *testing.F) {
+	seeds := []*ies.IE{
+		ies.NewCause(128),
+		ies.NewIMSI("123451234567891"),
+		ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+		ies.NewRecovery(0xff),
+		ies.NewTEIDCPlane(0xdeadbeef),
+		ies.NewNSAPI(5),
+		ies.NewEndUserAddressIPv4("2.2.2.2"),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewProtocolConfigurationOptions(0, ies.NewConfigurationProtocolOption(0x000d, []byte{0x01})),
+		ies.NewGSNAddress("2001::1"),
+		ies.NewMSISDN("8130900000001"),
+		ies.NewQoSProfileFromPayload(ies.NewQoSProfilePayloadR99(0x02, 3, 1, 0, 0, 0, 0)),
+		ies.NewAuthenticationQuintuplet(make([]byte, 16), make([]byte, 8), make([]byte, 16), make([]byte, 16), make([]byte, 16)),
+		ies.NewExtensionHeaderTypeList(0x85),
+		ies.NewCommonFlags(0, 1, 0, 0, 0, 0, 0, 0),
+		ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222),
+		ies.NewMSTimeZone(9*time.Hour, 0),
+		ies.NewIMEISV("123450123456789"),
+	}
+	for _, ie := range seeds {
+		b, err := ie.Serialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ie, err := ies.Decode(b)
+		if err != nil {
+			return
+		}
+		callGetters(ie)
+		_, _ = ie.Serialize()
+	})
+}
+
+// callGetters calls all the methods of IE that take no arguments, so that
+// the getters are checked not to panic with the malformed payload.
+func callGetters(ie *ies.IE) {
+	v := reflect.ValueOf(ie)
+	for i := 0; i < v.NumMethod(); i++ {
+		if m := v.Method(i); m.Type().NumIn() == 0 {
+			m.Call(nil)
+		}
+	}
+}
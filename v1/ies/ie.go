@@ -211,7 +211,10 @@ func (i *IE) SerializeTo(b []byte) error {
 
 	var offset = 1
 	b[0] = i.Type
-	if !i.IsTV() {
+	if i.hasShortLength() {
+		b[1] = uint8(i.Length)
+		offset++
+	} else if !i.IsTV() {
 		binary.BigEndian.PutUint16(b[1:3], i.Length)
 		offset += 2
 	}
@@ -238,6 +241,9 @@ func (i *IE) DecodeFromBytes(b []byte) error {
 	if i.IsTV() {
 		return decodeTVFromBytes(i, b)
 	}
+	if i.hasShortLength() {
+		return decodeShortTLVFromBytes(i, b)
+	}
 	return decodeTLVFromBytes(i, b)
 }
 
@@ -271,6 +277,16 @@ func decodeTLVFromBytes(i *IE, b []byte) error {
 	return nil
 }
 
+func decodeShortTLVFromBytes(i *IE, b []byte) error {
+	i.Length = uint16(b[1])
+	if int(i.Length)+2 > len(b) {
+		return ErrInvalidLength
+	}
+
+	i.Payload = b[2 : 2+int(i.Length)]
+	return nil
+}
+
 var tvLengthMap = map[int]int{
 	0:   0,  // Reserved
 	1:   1,  // Cause
@@ -307,6 +323,12 @@ func (i *IE) IsTV() bool {
 	return int(i.Type) < 0x80
 }
 
+// hasShortLength checks if a IE is TLV format with the Length field of 1 octet,
+// which is an exception defined only for Extension Header Type List.
+func (i *IE) hasShortLength() bool {
+	return i.Type == ExtensionHeaderTypeList
+}
+
 // Len returns the actual length of IE.
 func (i *IE) Len() int {
 	if l, ok := tvLengthMap[int(i.Type)]; ok {
@@ -315,6 +337,9 @@ func (i *IE) Len() int {
 	if i.Type < 128 {
 		return 1 + len(i.Payload)
 	}
+	if i.hasShortLength() {
+		return 2 + len(i.Payload)
+	}
 
 	return 3 + len(i.Payload)
 }
@@ -340,24 +365,34 @@ func (i *IE) String() string {
 
 // DecodeMultiIEs decodes multiple (unspecified number of) IEs to []*IE at a time.
 func DecodeMultiIEs(b []byte) ([]*IE, error) {
-	var ies []*IE
-	for {
-		if len(b) == 0 {
-			break
-		}
+	if len(b) == 0 {
+		return nil, nil
+	}
 
-		i, err := Decode(b)
-		if err != nil {
+	// allocate the IEs at once instead of one by one, with the capacity estimated
+	// from the length, as the number of TV IEs cannot be known without decoding them.
+	decoded := make([]IE, 0, len(b)/avgIELen+1)
+	for len(b) > 0 {
+		decoded = append(decoded, IE{})
+		i := &decoded[len(decoded)-1]
+		if err := i.DecodeFromBytes(b); err != nil {
 			return nil, err
 		}
-
-		ies = append(ies, i)
 		b = b[i.Len():]
-		continue
+	}
+
+	// the pointers are taken after the slice stops growing.
+	ies := make([]*IE, len(decoded))
+	for k := range decoded {
+		ies[k] = &decoded[k]
 	}
 	return ies, nil
 }
 
+// avgIELen is the typical length of IEs in the messages, to estimate the number
+// of IEs from the length.
+const avgIELen = 6
+
 func newUint8ValIE(t, v uint8) *IE {
 	return New(t, []byte{v})
 }
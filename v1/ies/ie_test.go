@@ -5,6 +5,7 @@
 package ies_test
 
 import (
+	"net"
 	"testing"
 	"time"
 
@@ -23,6 +24,10 @@ func TestIEs(t *testing.T) {
 			"IMSI",
 			ies.NewIMSI("123451234567890"),
 			[]byte{0x02, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0},
+		}, {
+			"TemporaryLogicalLinkIdentity",
+			ies.NewTemporaryLogicalLinkIdentity(0xdeadbeef),
+			[]byte{0x04, 0xde, 0xad, 0xbe, 0xef},
 		}, {
 			"PacketTMSI",
 			ies.NewPacketTMSI(0xbeebee),
@@ -80,10 +85,22 @@ func TestIEs(t *testing.T) {
 			"NSAPI",
 			ies.NewNSAPI(0x05),
 			[]byte{0x14, 0x05},
+		}, {
+			"RABContext",
+			ies.NewRABContext(0x05, 0x0001, 0x0002, 0x0003, 0x0004),
+			[]byte{0x16, 0x05, 0x00, 0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04},
+		}, {
+			"TraceReference",
+			ies.NewTraceReference(0xbeef),
+			[]byte{0x1b, 0xbe, 0xef},
 		}, {
 			"RANAPCause",
 			ies.NewRANAPCause(v1.MAPCauseUnknownSubscriber),
 			[]byte{0x15, 0x01},
+		}, {
+			"ChargingID",
+			ies.NewChargingID(0xdeadbeef),
+			[]byte{0x7f, 0xde, 0xad, 0xbe, 0xef},
 		}, {
 			"EndUserAddress/v4",
 			ies.NewEndUserAddress("1.1.1.1"),
@@ -113,6 +130,17 @@ func TestIEs(t *testing.T) {
 				0x85, 0x00, 0x10,
 				0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
 			},
+		}, {
+			"QoSProfile/R97",
+			ies.NewQoSProfileFromPayload(ies.NewQoSProfilePayloadR97(2, 1, 3, 9, 2, 0x1f)),
+			[]byte{0x87, 0x00, 0x04, 0x02, 0x0b, 0x92, 0x1f},
+		}, {
+			"QoSProfile/R99",
+			ies.NewQoSProfileFromPayload(ies.NewQoSProfilePayloadR99(2, 3, 1, 0x40, 0x40, 0, 0)),
+			[]byte{
+				0x87, 0x00, 0x0c,
+				0x02, 0x00, 0x00, 0x00, 0x60, 0x00, 0x40, 0x40, 0x00, 0x01, 0x00, 0x00,
+			},
 		}, {
 			"MSISDN",
 			ies.NewMSISDN("818012345678"),
@@ -140,6 +168,10 @@ func TestIEs(t *testing.T) {
 			"CommonFlags",
 			ies.NewCommonFlags(0, 1, 0, 0, 0, 0, 0, 0),
 			[]byte{0x94, 0x00, 0x01, 0x40},
+		}, {
+			"ChargingGatewayAddress",
+			ies.NewChargingGatewayAddress("1.1.1.1"),
+			[]byte{0xfb, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01},
 		}, {
 			"APNRestriction",
 			ies.NewAPNRestriction(v1.APNRestrictionPrivate1),
@@ -172,6 +204,86 @@ func TestIEs(t *testing.T) {
 			"ULITimestamp",
 			ies.NewULITimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
 			[]byte{0xd6, 0x00, 0x04, 0xdf, 0xd5, 0x2c, 0x00},
+		}, {
+			"RANTransparentContainer",
+			ies.NewRANTransparentContainer([]byte{0x71, 0x00, 0x01, 0x02}),
+			[]byte{0x90, 0x00, 0x04, 0x71, 0x00, 0x01, 0x02},
+		}, {
+			"ExtensionHeaderTypeList",
+			ies.NewExtensionHeaderTypeList(0x40, 0x85),
+			[]byte{0x8d, 0x02, 0x40, 0x85},
+		}, {
+			"RIMRoutingAddress",
+			ies.NewRIMRoutingAddress([]byte{0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x00, 0x01}),
+			[]byte{0x9e, 0x00, 0x08, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x00, 0x01},
+		}, {
+			"RIMRoutingAddressDiscriminator",
+			ies.NewRIMRoutingAddressDiscriminator(ies.RIMRoutingAddressRNCIdentifier),
+			[]byte{0xb2, 0x00, 0x01, 0x01},
+		}, {
+			"MSInfoChangeReportingAction",
+			ies.NewMSInfoChangeReportingAction(v1.MSInfoChangeReportingActionStartReportingRAI),
+			[]byte{0xb5, 0x00, 0x01, 0x02},
+		}, {
+			"MMContext/GSMKeyAndTriplets",
+			ies.NewMMContext(ies.NewMMContextPayloadGSM(
+				1, 2, []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77},
+				[]byte{0x09, 0x00}, []byte{0xe5, 0xe0},
+				ies.NewAuthenticationTriplet(
+					[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+					[]byte{0xde, 0xad, 0xbe, 0xef},
+					[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77},
+				),
+			)),
+			[]byte{
+				0x81, 0x00, 0x2d,
+				// CKSN, Security Mode, No of Vectors, Used Cipher
+				0xf9, 0x4a,
+				// Kc
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+				// Triplet
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+				0xde, 0xad, 0xbe, 0xef,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+				// DRX Parameter, MS Network Capability, Container
+				0x09, 0x00, 0x02, 0xe5, 0xe0, 0x00, 0x00,
+			},
+		}, {
+			"PDPContext",
+			ies.NewPDPContext(&ies.PDPContextPayload{
+				Order: true, NSAPI: 5, SAPI: 3,
+				QoSSubscribed:      []byte{0x01, 0x23, 0x45},
+				QoSRequested:       []byte{0x01, 0x23, 0x45},
+				QoSNegotiated:      []byte{0x01, 0x23, 0x45},
+				SequenceNumberDown: 1, SequenceNumberUp: 2,
+				SendNPDUNumber: 3, ReceiveNPDUNumber: 4,
+				UplinkTEIDCPlane: 0x11111111, UplinkTEIDDataI: 0x22222222,
+				PDPContextIdentifier: 1, PDPTypeOrganization: 1, PDPTypeNumber: 0x21,
+				PDPAddress:                net.ParseIP("10.10.10.10").To4(),
+				GGSNAddressForCPlane:      net.ParseIP("1.1.1.1").To4(),
+				GGSNAddressForUserTraffic: net.ParseIP("1.1.1.2").To4(),
+				APN:                       "some.apn.example",
+				TransactionIdentifier:     1,
+			}),
+			[]byte{
+				0x82, 0x00, 0x41,
+				// Flags, NSAPI, SAPI
+				0x15, 0x03,
+				// QoS Subscribed, Requested, Negotiated
+				0x03, 0x01, 0x23, 0x45, 0x03, 0x01, 0x23, 0x45, 0x03, 0x01, 0x23, 0x45,
+				// Sequence Number Down/Up, Send/Receive N-PDU Number
+				0x00, 0x01, 0x00, 0x02, 0x03, 0x04,
+				// Uplink TEID C-Plane, Uplink TEID Data I
+				0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22,
+				// PDP Context Identifier, PDP Type Organization, PDP Type Number
+				0x01, 0xf1, 0x21,
+				// PDP Address, GGSN Address for C-Plane, GGSN Address for User Traffic
+				0x04, 0x0a, 0x0a, 0x0a, 0x0a, 0x04, 0x01, 0x01, 0x01, 0x01, 0x04, 0x01, 0x01, 0x01, 0x02,
+				// APN
+				0x11, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+				// Transaction Identifier
+				0x01,
+			},
 		},
 	}
 
@@ -200,3 +312,101 @@ func TestIEs(t *testing.T) {
 		})
 	}
 }
+
+func TestMMContextPayload(t *testing.T) {
+	want := ies.NewMMContextPayloadUMTS(
+		3, make([]byte, 16), make([]byte, 16),
+		[]byte{0x09, 0x00}, []byte{0xe5, 0xe0},
+		ies.NewAuthenticationQuintuplet(make([]byte, 16), []byte{1, 2, 3, 4}, make([]byte, 16), make([]byte, 16), []byte{5, 6}),
+	)
+	want.Container = []byte{}
+
+	got := ies.NewMMContext(want).MMContext()
+	if got == nil {
+		t.Fatal("failed to decode MMContext")
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+	if xres := got.Quintuplets[0].XRES(); !cmp.Equal(xres, []byte{1, 2, 3, 4}) {
+		t.Errorf("unexpected XRES: %x", xres)
+	}
+}
+
+func TestQoSProfilePayload(t *testing.T) {
+	want := ies.NewQoSProfilePayloadR99(2, 3, 1, 0x40, 0x40, 0, 0)
+	want.ResidualBER = 7
+	want.SDUErrorRatio = 4
+	want.Extensions = []byte{0x00, 0x00, 0x01, 0x01}
+
+	got := ies.NewQoSProfileFromPayload(want).QoSProfilePayload()
+	if got == nil {
+		t.Fatal("failed to decode QoSProfile")
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	apn := ies.NewAccessPointName("some.apn.mnc001.mcc001.gprs")
+	if got := apn.APNNetworkIdentifier(); got != "some.apn" {
+		t.Errorf("unexpected APN NI: %s", got)
+	}
+	if got := apn.APNOperatorIdentifier(); got != "mnc001.mcc001.gprs" {
+		t.Errorf("unexpected APN OI: %s", got)
+	}
+
+	rab := ies.NewRABContext(0x05, 0x0001, 0x0002, 0x0003, 0x0004)
+	if got := rab.NSAPI(); got != 0x05 {
+		t.Errorf("unexpected NSAPI: %d", got)
+	}
+	if got := rab.ULPDCPSequenceNumber(); got != 0x0004 {
+		t.Errorf("unexpected UL PDCP Sequence Number: %d", got)
+	}
+
+	if !ies.NewGSNAddress("2001::1").IsIPv6GSNAddress() {
+		t.Error("GSN Address should be IPv6")
+	}
+	if got := ies.New(ies.GSNAddress, []byte{0x01}).GSNAddress(); got != "" {
+		t.Errorf("unexpected GSN Address with invalid length: %s", got)
+	}
+
+	rai := ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22)
+	if got := rai.MCC() + "/" + rai.MNC(); got != "123/45" {
+		t.Errorf("unexpected PLMN in RAI: %s", got)
+	}
+	uli := ies.NewUserLocationInformationWithSAI("440", "123", 0x1111, 0x2222)
+	if got := uli.MCC() + "/" + uli.MNC(); got != "440/123" {
+		t.Errorf("unexpected PLMN in ULI: %s", got)
+	}
+	if got := ies.NewMSISDN("8130900000001").MSISDN(); got != "8130900000001" {
+		t.Errorf("unexpected MSISDN: %s", got)
+	}
+}
+
+func TestQoSProfileBitRates(t *testing.T) {
+	cases := []struct {
+		kbps, want uint64
+	}{
+		{0, 0},
+		{63, 63},
+		{100, 96},
+		{8640, 8640},
+		{10000, 10000},
+		{100000, 100000},
+		{256000, 256000},
+	}
+
+	for _, c := range cases {
+		q := ies.NewQoSProfilePayloadR99(1, 3, 1, 0, 0, 0, 0)
+		q.SetMaximumBitRateKbps(c.kbps, c.kbps)
+
+		got := ies.NewQoSProfileFromPayload(q).QoSProfilePayload()
+		if got.MaximumBitRateULKbps() != c.want || got.MaximumBitRateDLKbps() != c.want {
+			t.Errorf("unexpected bit rate for %d: got %d/%d, want %d",
+				c.kbps, got.MaximumBitRateULKbps(), got.MaximumBitRateDLKbps(), c.want,
+			)
+		}
+	}
+}
@@ -7,7 +7,12 @@ package ies
 import "github.com/wmnsk/go-gtp/utils"
 
 // NewIMEISV creates a new IMEISV IE.
+//
+// imei is validated with utils.CheckMEI, and nil is returned if it fails.
 func NewIMEISV(imei string) *IE {
+	if err := utils.CheckMEI(imei); err != nil {
+		return nil
+	}
 	i, err := utils.StrToSwappedBytes(imei, "f")
 	if err != nil {
 		return nil
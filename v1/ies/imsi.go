@@ -7,7 +7,12 @@ package ies
 import "github.com/wmnsk/go-gtp/utils"
 
 // NewIMSI creates a new IMSI IE.
+//
+// imsi is validated with utils.CheckIMSI, and nil is returned if it fails.
 func NewIMSI(imsi string) *IE {
+	if err := utils.CheckIMSI(imsi); err != nil {
+		return nil
+	}
 	i, err := utils.StrToSwappedBytes(imsi, "f")
 	if err != nil {
 		return nil
@@ -0,0 +1,278 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "encoding/binary"
+
+// Security Mode definitions used in MM Context.
+const (
+	SecurityModeUsedCipherValueUMTSKeysAndQuintuplets uint8 = iota
+	SecurityModeGSMKeyAndTriplets
+	SecurityModeUMTSKeyAndQuintuplets
+	SecurityModeGSMKeyAndQuintuplets
+)
+
+// MMContextPayload is a Payload of MMContext IE.
+//
+// Triplets and Quintuplets are the AuthenticationTriplet and AuthenticationQuintuplet
+// IEs, so that the values inside can be retrieved with RAND(), XRES() and so on.
+// Which of Kc, CK/IK, Triplets and Quintuplets are used depends on the SecurityMode.
+//
+// The fields following Container (e.g., Access Restriction Data) are not supported
+// and just ignored on decoding.
+type MMContextPayload struct {
+	SecurityMode        uint8
+	CKSN                uint8 // CKSN or KSI
+	UsedCipher          uint8
+	Kc                  []byte
+	CK, IK              []byte
+	Triplets            []*IE
+	Quintuplets         []*IE
+	DRXParameter        []byte
+	MSNetworkCapability []byte
+	Container           []byte
+}
+
+// NewMMContextPayloadGSM creates a new MMContextPayload with GSM Key and Triplets.
+func NewMMContextPayloadGSM(cksn, cipher uint8, kc []byte, drx, msnc []byte, triplets ...*IE) *MMContextPayload {
+	return &MMContextPayload{
+		SecurityMode:        SecurityModeGSMKeyAndTriplets,
+		CKSN:                cksn,
+		UsedCipher:          cipher,
+		Kc:                  kc,
+		Triplets:            triplets,
+		DRXParameter:        drx,
+		MSNetworkCapability: msnc,
+	}
+}
+
+// NewMMContextPayloadUMTS creates a new MMContextPayload with UMTS Keys and Quintuplets.
+func NewMMContextPayloadUMTS(ksi uint8, ck, ik []byte, drx, msnc []byte, quintuplets ...*IE) *MMContextPayload {
+	return &MMContextPayload{
+		SecurityMode:        SecurityModeUMTSKeyAndQuintuplets,
+		CKSN:                ksi,
+		CK:                  ck,
+		IK:                  ik,
+		Quintuplets:         quintuplets,
+		DRXParameter:        drx,
+		MSNetworkCapability: msnc,
+	}
+}
+
+// Serialize serializes MMContextPayload.
+func (m *MMContextPayload) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MMContextPayload.
+func (m *MMContextPayload) SerializeTo(b []byte) error {
+	if len(b) < m.Len() {
+		return ErrTooShortToSerialize
+	}
+
+	b[0] = 0xf8 | (m.CKSN & 0x07)
+	b[1] = ((m.SecurityMode & 0x03) << 6) | ((uint8(m.numVectors()) & 0x07) << 3) | (m.UsedCipher & 0x07)
+	offset := 2
+
+	if m.hasKc() {
+		copy(b[offset:offset+8], m.Kc)
+		offset += 8
+	} else {
+		copy(b[offset:offset+16], m.CK)
+		offset += 16
+		copy(b[offset:offset+16], m.IK)
+		offset += 16
+	}
+
+	if m.SecurityMode == SecurityModeGSMKeyAndTriplets {
+		for _, t := range m.Triplets {
+			copy(b[offset:offset+28], t.Payload)
+			offset += 28
+		}
+	} else {
+		binary.BigEndian.PutUint16(b[offset:offset+2], uint16(m.quintupletsLen()))
+		offset += 2
+		for _, q := range m.Quintuplets {
+			binary.BigEndian.PutUint16(b[offset:offset+2], uint16(len(q.Payload)))
+			offset += 2
+			copy(b[offset:offset+len(q.Payload)], q.Payload)
+			offset += len(q.Payload)
+		}
+	}
+
+	copy(b[offset:offset+2], m.DRXParameter)
+	offset += 2
+
+	b[offset] = uint8(len(m.MSNetworkCapability))
+	offset++
+	copy(b[offset:offset+len(m.MSNetworkCapability)], m.MSNetworkCapability)
+	offset += len(m.MSNetworkCapability)
+
+	binary.BigEndian.PutUint16(b[offset:offset+2], uint16(len(m.Container)))
+	offset += 2
+	copy(b[offset:offset+len(m.Container)], m.Container)
+
+	return nil
+}
+
+// DecodeMMContextPayload decodes MMContextPayload.
+func DecodeMMContextPayload(b []byte) (*MMContextPayload, error) {
+	m := &MMContextPayload{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DecodeFromBytes decodes given bytes into MMContextPayload.
+func (m *MMContextPayload) DecodeFromBytes(b []byte) error {
+	l := len(b)
+	if l < 2 {
+		return ErrTooShortToDecode
+	}
+
+	m.CKSN = b[0] & 0x07
+	m.SecurityMode = (b[1] >> 6) & 0x03
+	n := int((b[1] >> 3) & 0x07)
+	m.UsedCipher = b[1] & 0x07
+	offset := 2
+
+	if m.hasKc() {
+		if l < offset+8 {
+			return ErrTooShortToDecode
+		}
+		m.Kc = b[offset : offset+8]
+		offset += 8
+	} else {
+		if l < offset+32 {
+			return ErrTooShortToDecode
+		}
+		m.CK = b[offset : offset+16]
+		m.IK = b[offset+16 : offset+32]
+		offset += 32
+	}
+
+	m.Triplets = nil
+	m.Quintuplets = nil
+	if m.SecurityMode == SecurityModeGSMKeyAndTriplets {
+		if l < offset+28*n {
+			return ErrTooShortToDecode
+		}
+		for x := 0; x < n; x++ {
+			m.Triplets = append(m.Triplets, New(AuthenticationTriplet, b[offset:offset+28]))
+			offset += 28
+		}
+	} else {
+		if l < offset+2 {
+			return ErrTooShortToDecode
+		}
+		end := offset + 2 + int(binary.BigEndian.Uint16(b[offset:offset+2]))
+		offset += 2
+		if l < end {
+			return ErrTooShortToDecode
+		}
+		for offset < end {
+			if end < offset+2 {
+				return ErrInvalidLength
+			}
+			ql := int(binary.BigEndian.Uint16(b[offset : offset+2]))
+			offset += 2
+			if end < offset+ql {
+				return ErrInvalidLength
+			}
+			m.Quintuplets = append(m.Quintuplets, New(AuthenticationQuintuplet, b[offset:offset+ql]))
+			offset += ql
+		}
+	}
+
+	if l < offset+3 {
+		return ErrTooShortToDecode
+	}
+	m.DRXParameter = b[offset : offset+2]
+	offset += 2
+
+	nl := int(b[offset])
+	offset++
+	if l < offset+nl+2 {
+		return ErrTooShortToDecode
+	}
+	m.MSNetworkCapability = b[offset : offset+nl]
+	offset += nl
+
+	cl := int(binary.BigEndian.Uint16(b[offset : offset+2]))
+	offset += 2
+	if l < offset+cl {
+		return ErrTooShortToDecode
+	}
+	m.Container = b[offset : offset+cl]
+
+	return nil
+}
+
+// Len returns the actual length of MMContextPayload in int.
+func (m *MMContextPayload) Len() int {
+	l := 2
+	if m.hasKc() {
+		l += 8
+	} else {
+		l += 32
+	}
+
+	if m.SecurityMode == SecurityModeGSMKeyAndTriplets {
+		l += 28 * len(m.Triplets)
+	} else {
+		l += 2 + m.quintupletsLen()
+	}
+
+	return l + 2 + 1 + len(m.MSNetworkCapability) + 2 + len(m.Container)
+}
+
+func (m *MMContextPayload) hasKc() bool {
+	return m.SecurityMode == SecurityModeGSMKeyAndTriplets || m.SecurityMode == SecurityModeGSMKeyAndQuintuplets
+}
+
+func (m *MMContextPayload) numVectors() int {
+	if m.SecurityMode == SecurityModeGSMKeyAndTriplets {
+		return len(m.Triplets)
+	}
+	return len(m.Quintuplets)
+}
+
+func (m *MMContextPayload) quintupletsLen() int {
+	l := 0
+	for _, q := range m.Quintuplets {
+		l += 2 + len(q.Payload)
+	}
+	return l
+}
+
+// NewMMContext creates a new MMContext IE.
+func NewMMContext(mm *MMContextPayload) *IE {
+	i := New(MMContext, make([]byte, mm.Len()))
+	if err := mm.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// MMContext returns MMContext in MMContextPayload type if the type of IE matches.
+func (i *IE) MMContext() *MMContextPayload {
+	if i.Type != MMContext {
+		return nil
+	}
+
+	mm, err := DecodeMMContextPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return mm
+}
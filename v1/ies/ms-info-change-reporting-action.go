@@ -0,0 +1,21 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewMSInfoChangeReportingAction creates a new MSInfoChangeReportingAction IE.
+func NewMSInfoChangeReportingAction(action uint8) *IE {
+	return newUint8ValIE(MSInfoChangeReportingAction, action)
+}
+
+// MSInfoChangeReportingAction returns MSInfoChangeReportingAction in uint8 if type matches.
+func (i *IE) MSInfoChangeReportingAction() uint8 {
+	if i.Type != MSInfoChangeReportingAction {
+		return 0
+	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
+	return i.Payload[0]
+}
@@ -17,5 +17,8 @@ func (i *IE) MSValidated() bool {
 	if i.Type != MSValidated {
 		return false
 	}
+	if len(i.Payload) < 1 {
+		return false
+	}
 	return i.Payload[0]%2 == 1
 }
@@ -4,7 +4,11 @@
 
 package ies
 
-import "github.com/wmnsk/go-gtp/utils"
+import (
+	"strings"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
 
 // NewMSISDN creates a new MSISDN IE.
 func NewMSISDN(msisdn string) *IE {
@@ -20,5 +24,9 @@ func (i *IE) MSISDN() string {
 	if i.Type != MSISDN {
 		return ""
 	}
-	return utils.SwappedBytesToStr(i.Payload[1:], false)
+	if len(i.Payload) < 2 {
+		return ""
+	}
+	// the last digit is filled with 0xf if the number of digits is odd.
+	return strings.TrimSuffix(utils.SwappedBytesToStr(i.Payload[1:], false), "f")
 }
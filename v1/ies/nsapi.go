@@ -6,13 +6,20 @@ package ies
 
 // NewNSAPI creates a new NSAPI IE.
 func NewNSAPI(nsapi uint8) *IE {
-	return newUint8ValIE(NSAPI, nsapi)
+	return newUint8ValIE(NSAPI, nsapi&0x0f)
 }
 
 // NSAPI returns NSAPI value if type matches.
+//
+// This works with RABContext as well as NSAPI.
 func (i *IE) NSAPI() uint8 {
-	if i.Type != NSAPI {
+	switch i.Type {
+	case NSAPI, RABContext:
+		if len(i.Payload) < 1 {
+			return 0
+		}
+		return i.Payload[0] & 0x0f
+	default:
 		return 0
 	}
-	return i.Payload[0]
 }
@@ -56,14 +56,16 @@ func DecodeConfigurationProtocolOption(b []byte) (*ConfigurationProtocolOption,
 
 // DecodeFromBytes decodes given bytes into ConfigurationProtocolOption.
 func (c *ConfigurationProtocolOption) DecodeFromBytes(b []byte) error {
-	if len(b) < 4 {
+	if len(b) < 3 {
 		return ErrTooShortToDecode
 	}
 	c.ProtocolID = binary.BigEndian.Uint16(b[0:2])
 	c.Length = b[2]
-	if c.Length != 0 {
-		copy(c.Contents, b[3:])
+	if int(c.Length) > len(b)-3 {
+		return ErrInvalidLength
 	}
+	c.Contents = make([]byte, c.Length)
+	copy(c.Contents, b[3:3+int(c.Length)])
 
 	return nil
 }
@@ -123,6 +125,9 @@ func DecodePCOPayload(b []byte) (*PCOPayload, error) {
 
 // DecodeFromBytes decodes given bytes into PCOPayload.
 func (p *PCOPayload) DecodeFromBytes(b []byte) error {
+	if len(b) < 1 {
+		return ErrTooShortToDecode
+	}
 	p.ConfigurationProtocol = b[0] & 0x07
 
 	offset := 1
@@ -135,6 +140,7 @@ func (p *PCOPayload) DecodeFromBytes(b []byte) error {
 			return err
 		}
 		p.ConfigurationProtocolOptions = append(p.ConfigurationProtocolOptions, opt)
+		offset += opt.Len()
 	}
 }
 
@@ -0,0 +1,221 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// PDPContextPayload is a Payload of PDPContext IE.
+//
+// The octets following the first octet of Transaction Identifier (the extended
+// Transaction Identifier and the second PDP Type/Address that exist when EA is set)
+// are not decoded and kept as they are in AdditionalFields.
+type PDPContextPayload struct {
+	EA, VAA, ASI, Order                             bool
+	NSAPI, SAPI                                     uint8
+	QoSSubscribed, QoSRequested, QoSNegotiated      []byte
+	SequenceNumberDown, SequenceNumberUp            uint16
+	SendNPDUNumber, ReceiveNPDUNumber               uint8
+	UplinkTEIDCPlane, UplinkTEIDDataI               uint32
+	PDPContextIdentifier                            uint8
+	PDPTypeOrganization, PDPTypeNumber              uint8
+	PDPAddress                                      net.IP
+	GGSNAddressForCPlane, GGSNAddressForUserTraffic net.IP
+	APN                                             string
+	TransactionIdentifier                           uint8
+	AdditionalFields                                []byte
+}
+
+// Serialize serializes PDPContextPayload.
+func (p *PDPContextPayload) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes PDPContextPayload.
+func (p *PDPContextPayload) SerializeTo(b []byte) error {
+	if len(b) < p.Len() {
+		return ErrTooShortToSerialize
+	}
+
+	b[0] = p.NSAPI & 0x0f
+	if p.EA {
+		b[0] |= 0x80
+	}
+	if p.VAA {
+		b[0] |= 0x40
+	}
+	if p.ASI {
+		b[0] |= 0x20
+	}
+	if p.Order {
+		b[0] |= 0x10
+	}
+	b[1] = p.SAPI & 0x0f
+	offset := 2
+
+	for _, v := range [][]byte{p.QoSSubscribed, p.QoSRequested, p.QoSNegotiated} {
+		b[offset] = uint8(len(v))
+		copy(b[offset+1:], v)
+		offset += 1 + len(v)
+	}
+
+	binary.BigEndian.PutUint16(b[offset:offset+2], p.SequenceNumberDown)
+	binary.BigEndian.PutUint16(b[offset+2:offset+4], p.SequenceNumberUp)
+	b[offset+4] = p.SendNPDUNumber
+	b[offset+5] = p.ReceiveNPDUNumber
+	binary.BigEndian.PutUint32(b[offset+6:offset+10], p.UplinkTEIDCPlane)
+	binary.BigEndian.PutUint32(b[offset+10:offset+14], p.UplinkTEIDDataI)
+	b[offset+14] = p.PDPContextIdentifier
+	b[offset+15] = 0xf0 | (p.PDPTypeOrganization & 0x0f)
+	b[offset+16] = p.PDPTypeNumber
+	offset += 17
+
+	for _, v := range [][]byte{p.PDPAddress, p.GGSNAddressForCPlane, p.GGSNAddressForUserTraffic, p.encodedAPN()} {
+		b[offset] = uint8(len(v))
+		copy(b[offset+1:], v)
+		offset += 1 + len(v)
+	}
+
+	b[offset] = p.TransactionIdentifier & 0x0f
+	offset++
+	copy(b[offset:], p.AdditionalFields)
+
+	return nil
+}
+
+// DecodePDPContextPayload decodes PDPContextPayload.
+func DecodePDPContextPayload(b []byte) (*PDPContextPayload, error) {
+	p := &PDPContextPayload{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into PDPContextPayload.
+func (p *PDPContextPayload) DecodeFromBytes(b []byte) error {
+	l := len(b)
+	if l < 2 {
+		return ErrTooShortToDecode
+	}
+
+	p.EA = b[0]&0x80 != 0
+	p.VAA = b[0]&0x40 != 0
+	p.ASI = b[0]&0x20 != 0
+	p.Order = b[0]&0x10 != 0
+	p.NSAPI = b[0] & 0x0f
+	p.SAPI = b[1] & 0x0f
+	offset := 2
+
+	var qos [3][]byte
+	for x := range qos {
+		if l <= offset {
+			return ErrTooShortToDecode
+		}
+		vl := int(b[offset])
+		if l < offset+1+vl {
+			return ErrTooShortToDecode
+		}
+		qos[x] = b[offset+1 : offset+1+vl]
+		offset += 1 + vl
+	}
+	p.QoSSubscribed, p.QoSRequested, p.QoSNegotiated = qos[0], qos[1], qos[2]
+
+	if l < offset+17 {
+		return ErrTooShortToDecode
+	}
+	p.SequenceNumberDown = binary.BigEndian.Uint16(b[offset : offset+2])
+	p.SequenceNumberUp = binary.BigEndian.Uint16(b[offset+2 : offset+4])
+	p.SendNPDUNumber = b[offset+4]
+	p.ReceiveNPDUNumber = b[offset+5]
+	p.UplinkTEIDCPlane = binary.BigEndian.Uint32(b[offset+6 : offset+10])
+	p.UplinkTEIDDataI = binary.BigEndian.Uint32(b[offset+10 : offset+14])
+	p.PDPContextIdentifier = b[offset+14]
+	p.PDPTypeOrganization = b[offset+15] & 0x0f
+	p.PDPTypeNumber = b[offset+16]
+	offset += 17
+
+	var vals [4][]byte
+	for x := range vals {
+		if l <= offset {
+			return ErrTooShortToDecode
+		}
+		vl := int(b[offset])
+		if l < offset+1+vl {
+			return ErrTooShortToDecode
+		}
+		vals[x] = b[offset+1 : offset+1+vl]
+		offset += 1 + vl
+	}
+	p.PDPAddress = decodeIP(vals[0])
+	p.GGSNAddressForCPlane = decodeIP(vals[1])
+	p.GGSNAddressForUserTraffic = decodeIP(vals[2])
+	p.APN = decodeAPN(vals[3])
+
+	if l <= offset {
+		return ErrTooShortToDecode
+	}
+	p.TransactionIdentifier = b[offset] & 0x0f
+	offset++
+	if l > offset {
+		p.AdditionalFields = b[offset:]
+	}
+
+	return nil
+}
+
+// Len returns the actual length of PDPContextPayload in int.
+func (p *PDPContextPayload) Len() int {
+	l := 2 + 3 + len(p.QoSSubscribed) + len(p.QoSRequested) + len(p.QoSNegotiated) + 17
+	l += 4 + len(p.PDPAddress) + len(p.GGSNAddressForCPlane) + len(p.GGSNAddressForUserTraffic) + len(p.encodedAPN())
+	return l + 1 + len(p.AdditionalFields)
+}
+
+func (p *PDPContextPayload) encodedAPN() []byte {
+	if p.APN == "" {
+		return nil
+	}
+	return encodeAPN(p.APN)
+}
+
+// decodeIP returns nil if b is empty, so that the empty address is kept empty
+// when serialized again.
+func decodeIP(b []byte) net.IP {
+	if len(b) == 0 {
+		return nil
+	}
+	return net.IP(b)
+}
+
+// NewPDPContext creates a new PDPContext IE.
+func NewPDPContext(pdp *PDPContextPayload) *IE {
+	i := New(PDPContext, make([]byte, pdp.Len()))
+	if err := pdp.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// PDPContext returns PDPContext in PDPContextPayload type if the type of IE matches.
+func (i *IE) PDPContext() *PDPContextPayload {
+	if i.Type != PDPContext {
+		return nil
+	}
+
+	pdp, err := DecodePDPContextPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return pdp
+}
@@ -4,19 +4,356 @@
 
 package ies
 
+// Length of QoS Profile Data in each format, excluding Allocation/Retention Priority.
+const (
+	qosProfileLenR97 = 3
+	qosProfileLenR99 = 11
+)
+
+// QoSProfilePayload is a Payload of QoSProfile IE.
+//
+// The fields from DelayClass to MeanThroughput are the ones defined in R97/98 format,
+// and the rest are the ones added in R99 format, which are used only if IsR99 is true.
+// The octets following the R99 format(e.g., Extended Maximum Bit Rate added in Rel-5
+// and later) are kept as they are in Extensions.
+//
+// The values are encoded as defined in 3GPP TS 24.008 10.5.6.5.
+type QoSProfilePayload struct {
+	AllocationRetentionPriority uint8
+
+	DelayClass       uint8
+	ReliabilityClass uint8
+	PeakThroughput   uint8
+	PrecedenceClass  uint8
+	MeanThroughput   uint8
+
+	IsR99                   bool
+	TrafficClass            uint8
+	DeliveryOrder           uint8
+	DeliveryOfErroneousSDU  uint8
+	MaximumSDUSize          uint8
+	MaximumBitRateUL        uint8
+	MaximumBitRateDL        uint8
+	ResidualBER             uint8
+	SDUErrorRatio           uint8
+	TransferDelay           uint8
+	TrafficHandlingPriority uint8
+	GuaranteedBitRateUL     uint8
+	GuaranteedBitRateDL     uint8
+
+	Extensions []byte
+}
+
+// NewQoSProfilePayloadR97 creates a new QoSProfilePayload in R97/98 format.
+func NewQoSProfilePayloadR97(arp, delay, reliability, peak, precedence, mean uint8) *QoSProfilePayload {
+	return &QoSProfilePayload{
+		AllocationRetentionPriority: arp,
+		DelayClass:                  delay,
+		ReliabilityClass:            reliability,
+		PeakThroughput:              peak,
+		PrecedenceClass:             precedence,
+		MeanThroughput:              mean,
+	}
+}
+
+// NewQoSProfilePayloadR99 creates a new QoSProfilePayload in R99 format with the values
+// that are commonly used. The other fields are set to 0, which means "subscribed" in
+// MS to network direction, and can be set directly if necessary.
+func NewQoSProfilePayloadR99(arp, trafficClass, thp, mbrUL, mbrDL, gbrUL, gbrDL uint8) *QoSProfilePayload {
+	return &QoSProfilePayload{
+		AllocationRetentionPriority: arp,
+		IsR99:                       true,
+		TrafficClass:                trafficClass,
+		TrafficHandlingPriority:     thp,
+		MaximumBitRateUL:            mbrUL,
+		MaximumBitRateDL:            mbrDL,
+		GuaranteedBitRateUL:         gbrUL,
+		GuaranteedBitRateDL:         gbrDL,
+	}
+}
+
+// Serialize serializes QoSProfilePayload.
+func (q *QoSProfilePayload) Serialize() ([]byte, error) {
+	b := make([]byte, q.Len())
+	if err := q.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes QoSProfilePayload.
+func (q *QoSProfilePayload) SerializeTo(b []byte) error {
+	if len(b) < q.Len() {
+		return ErrTooShortToSerialize
+	}
+
+	b[0] = q.AllocationRetentionPriority
+	b[1] = ((q.DelayClass & 0x07) << 3) | (q.ReliabilityClass & 0x07)
+	b[2] = ((q.PeakThroughput & 0x0f) << 4) | (q.PrecedenceClass & 0x07)
+	b[3] = q.MeanThroughput & 0x1f
+	if !q.IsR99 {
+		return nil
+	}
+
+	b[4] = ((q.TrafficClass & 0x07) << 5) | ((q.DeliveryOrder & 0x03) << 3) | (q.DeliveryOfErroneousSDU & 0x07)
+	b[5] = q.MaximumSDUSize
+	b[6] = q.MaximumBitRateUL
+	b[7] = q.MaximumBitRateDL
+	b[8] = ((q.ResidualBER & 0x0f) << 4) | (q.SDUErrorRatio & 0x0f)
+	b[9] = ((q.TransferDelay & 0x3f) << 2) | (q.TrafficHandlingPriority & 0x03)
+	b[10] = q.GuaranteedBitRateUL
+	b[11] = q.GuaranteedBitRateDL
+	copy(b[12:q.Len()], q.Extensions)
+
+	return nil
+}
+
+// DecodeQoSProfilePayload decodes QoSProfilePayload.
+func DecodeQoSProfilePayload(b []byte) (*QoSProfilePayload, error) {
+	q := &QoSProfilePayload{}
+	if err := q.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// DecodeFromBytes decodes given bytes into QoSProfilePayload.
+//
+// The format is determined by the length; it is treated as R99 format if the QoS Profile
+// Data is long enough to contain the R99 fields, and R97/98 format otherwise.
+func (q *QoSProfilePayload) DecodeFromBytes(b []byte) error {
+	l := len(b)
+	if l < 1+qosProfileLenR97 {
+		return ErrTooShortToDecode
+	}
+
+	q.AllocationRetentionPriority = b[0]
+	q.DelayClass = (b[1] >> 3) & 0x07
+	q.ReliabilityClass = b[1] & 0x07
+	q.PeakThroughput = b[2] >> 4
+	q.PrecedenceClass = b[2] & 0x07
+	q.MeanThroughput = b[3] & 0x1f
+
+	q.IsR99 = l >= 1+qosProfileLenR99
+	if !q.IsR99 {
+		return nil
+	}
+
+	q.TrafficClass = b[4] >> 5
+	q.DeliveryOrder = (b[4] >> 3) & 0x03
+	q.DeliveryOfErroneousSDU = b[4] & 0x07
+	q.MaximumSDUSize = b[5]
+	q.MaximumBitRateUL = b[6]
+	q.MaximumBitRateDL = b[7]
+	q.ResidualBER = b[8] >> 4
+	q.SDUErrorRatio = b[8] & 0x0f
+	q.TransferDelay = b[9] >> 2
+	q.TrafficHandlingPriority = b[9] & 0x03
+	q.GuaranteedBitRateUL = b[10]
+	q.GuaranteedBitRateDL = b[11]
+	if l > 1+qosProfileLenR99 {
+		q.Extensions = b[1+qosProfileLenR99:]
+	}
+
+	return nil
+}
+
+// Len returns the actual length of QoSProfilePayload in int.
+func (q *QoSProfilePayload) Len() int {
+	if !q.IsR99 {
+		return 1 + qosProfileLenR97
+	}
+	return 1 + qosProfileLenR99 + len(q.Extensions)
+}
+
+// SignallingIndication reports whether the Signalling Indication is set in the extended
+// octet following the R99 format.
+func (q *QoSProfilePayload) SignallingIndication() bool {
+	if len(q.Extensions) < 1 {
+		return false
+	}
+	return q.Extensions[0]&0x10 != 0
+}
+
+// SourceStatisticsDescriptor returns the Source Statistics Descriptor in the extended
+// octet following the R99 format.
+func (q *QoSProfilePayload) SourceStatisticsDescriptor() uint8 {
+	if len(q.Extensions) < 1 {
+		return 0
+	}
+	return q.Extensions[0] & 0x0f
+}
+
+// SetSignallingIndicationAndSSD sets the Signalling Indication and the Source Statistics
+// Descriptor in the extended octet following the R99 format.
+func (q *QoSProfilePayload) SetSignallingIndicationAndSSD(si bool, ssd uint8) {
+	q.extend(1)
+	q.Extensions[0] = ssd & 0x0f
+	if si {
+		q.Extensions[0] |= 0x10
+	}
+}
+
+// MaximumBitRateULKbps returns the Maximum bit rate for uplink in kbps, taking the
+// extended value into account.
+func (q *QoSProfilePayload) MaximumBitRateULKbps() uint64 {
+	return decodeBitRate(q.MaximumBitRateUL, q.extension(3))
+}
+
+// MaximumBitRateDLKbps returns the Maximum bit rate for downlink in kbps, taking the
+// extended value into account.
+func (q *QoSProfilePayload) MaximumBitRateDLKbps() uint64 {
+	return decodeBitRate(q.MaximumBitRateDL, q.extension(1))
+}
+
+// GuaranteedBitRateULKbps returns the Guaranteed bit rate for uplink in kbps, taking the
+// extended value into account.
+func (q *QoSProfilePayload) GuaranteedBitRateULKbps() uint64 {
+	return decodeBitRate(q.GuaranteedBitRateUL, q.extension(4))
+}
+
+// GuaranteedBitRateDLKbps returns the Guaranteed bit rate for downlink in kbps, taking the
+// extended value into account.
+func (q *QoSProfilePayload) GuaranteedBitRateDLKbps() uint64 {
+	return decodeBitRate(q.GuaranteedBitRateDL, q.extension(2))
+}
+
+// SetMaximumBitRateKbps sets the Maximum bit rate for uplink and downlink in kbps.
+// The extended octets are used if the value exceeds 8640 kbps, up to 256 Mbps.
+func (q *QoSProfilePayload) SetMaximumBitRateKbps(ul, dl uint64) {
+	var ext uint8
+	q.MaximumBitRateUL, ext = encodeBitRate(ul)
+	q.setExtension(3, ext)
+	q.MaximumBitRateDL, ext = encodeBitRate(dl)
+	q.setExtension(1, ext)
+}
+
+// SetGuaranteedBitRateKbps sets the Guaranteed bit rate for uplink and downlink in kbps.
+// The extended octets are used if the value exceeds 8640 kbps, up to 256 Mbps.
+func (q *QoSProfilePayload) SetGuaranteedBitRateKbps(ul, dl uint64) {
+	var ext uint8
+	q.GuaranteedBitRateUL, ext = encodeBitRate(ul)
+	q.setExtension(4, ext)
+	q.GuaranteedBitRateDL, ext = encodeBitRate(dl)
+	q.setExtension(2, ext)
+}
+
+func (q *QoSProfilePayload) extension(n int) uint8 {
+	if len(q.Extensions) <= n {
+		return 0
+	}
+	return q.Extensions[n]
+}
+
+func (q *QoSProfilePayload) setExtension(n int, v uint8) {
+	if v == 0 && len(q.Extensions) <= n {
+		return
+	}
+	q.extend(n + 1)
+	q.Extensions[n] = v
+}
+
+// extend makes Extensions n octets at least, filling the new octets with zeros.
+func (q *QoSProfilePayload) extend(n int) {
+	if len(q.Extensions) >= n {
+		return
+	}
+	ext := make([]byte, n)
+	copy(ext, q.Extensions)
+	q.Extensions = ext
+}
+
+// decodeBitRate decodes the bit rate in kbps, as defined in 3GPP TS 24.008 10.5.6.5.
+// 0 is returned for "subscribed" as well as "0kbps".
+func decodeBitRate(v, ext uint8) uint64 {
+	if v == 0xfe && ext != 0 {
+		switch {
+		case ext <= 0x4a:
+			return 8600 + uint64(ext)*100
+		case ext <= 0xba:
+			return 16000 + uint64(ext-0x4a)*1000
+		default:
+			return 128000 + uint64(ext-0xba)*2000
+		}
+	}
+
+	switch {
+	case v == 0xff:
+		return 0
+	case v < 0x40:
+		return uint64(v)
+	case v < 0x80:
+		return 64 + uint64(v-0x40)*8
+	default:
+		return 576 + uint64(v-0x80)*64
+	}
+}
+
+// encodeBitRate encodes the bit rate in kbps into the value and the extended value,
+// as defined in 3GPP TS 24.008 10.5.6.5. The values not fit in the granularity are
+// rounded down.
+func encodeBitRate(kbps uint64) (uint8, uint8) {
+	switch {
+	case kbps == 0:
+		return 0xff, 0
+	case kbps < 64:
+		return uint8(kbps), 0
+	case kbps < 576:
+		return 0x40 + uint8((kbps-64)/8), 0
+	case kbps <= 8640:
+		return 0x80 + uint8((kbps-576)/64), 0
+	case kbps < 8700:
+		return 0xfe, 0
+	case kbps <= 16000:
+		return 0xfe, uint8((kbps - 8600) / 100)
+	case kbps <= 128000:
+		return 0xfe, 0x4a + uint8((kbps-16000)/1000)
+	case kbps <= 256000:
+		return 0xfe, 0xba + uint8((kbps-128000)/2000)
+	default:
+		return 0xfe, 0xfa
+	}
+}
+
 // NewQoSProfile creates a new QoSProfile IE.
 //
-// XXX - NOT Fully implemented. Users need to put the whole payload in []byte.
+// The payload should be the whole value of the IE starting with Allocation/Retention
+// Priority. Use NewQoSProfileFromPayload to create it from the typed values.
 func NewQoSProfile(payload []byte) *IE {
 	return New(QoSProfile, payload)
 }
 
-// QoSProfile returns QoSProfile if type matches.
+// NewQoSProfileFromPayload creates a new QoSProfile IE from QoSProfilePayload.
+func NewQoSProfileFromPayload(qos *QoSProfilePayload) *IE {
+	i := New(QoSProfile, make([]byte, qos.Len()))
+	if err := qos.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// QoSProfile returns QoSProfile in []byte if type matches.
 //
-// XXX - NOT Fully implemented. This method just returns the whole payload in []byte.
+// This method just returns the whole payload. Use QoSProfilePayload to get the typed values.
 func (i *IE) QoSProfile() []byte {
 	if i.Type != QoSProfile {
 		return nil
 	}
 	return i.Payload
 }
+
+// QoSProfilePayload returns QoSProfile in QoSProfilePayload type if type matches.
+func (i *IE) QoSProfilePayload() *QoSProfilePayload {
+	if i.Type != QoSProfile {
+		return nil
+	}
+
+	q, err := DecodeQoSProfilePayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return q
+}
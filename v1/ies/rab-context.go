@@ -0,0 +1,59 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "encoding/binary"
+
+// NewRABContext creates a new RABContext IE.
+//
+// The NSAPI in RABContext can be retrieved with NSAPI().
+func NewRABContext(nsapi uint8, dlGTPUSeq, ulGTPUSeq, dlPDCPSeq, ulPDCPSeq uint16) *IE {
+	i := New(RABContext, make([]byte, 9))
+
+	i.Payload[0] = nsapi & 0x0f
+	binary.BigEndian.PutUint16(i.Payload[1:3], dlGTPUSeq)
+	binary.BigEndian.PutUint16(i.Payload[3:5], ulGTPUSeq)
+	binary.BigEndian.PutUint16(i.Payload[5:7], dlPDCPSeq)
+	binary.BigEndian.PutUint16(i.Payload[7:9], ulPDCPSeq)
+	return i
+}
+
+// RABContext returns RABContext in []byte if type matches.
+func (i *IE) RABContext() []byte {
+	if i.Type != RABContext {
+		return nil
+	}
+	return i.Payload
+}
+
+// DLGTPUSequenceNumber returns DL GTP-U Sequence Number in RABContext if type matches.
+func (i *IE) DLGTPUSequenceNumber() uint16 {
+	return i.rabContextUint16(1)
+}
+
+// ULGTPUSequenceNumber returns UL GTP-U Sequence Number in RABContext if type matches.
+func (i *IE) ULGTPUSequenceNumber() uint16 {
+	return i.rabContextUint16(3)
+}
+
+// DLPDCPSequenceNumber returns DL PDCP Sequence Number in RABContext if type matches.
+func (i *IE) DLPDCPSequenceNumber() uint16 {
+	return i.rabContextUint16(5)
+}
+
+// ULPDCPSequenceNumber returns UL PDCP Sequence Number in RABContext if type matches.
+func (i *IE) ULPDCPSequenceNumber() uint16 {
+	return i.rabContextUint16(7)
+}
+
+func (i *IE) rabContextUint16(offset int) uint16 {
+	if i.Type != RABContext {
+		return 0
+	}
+	if len(i.Payload) < offset+2 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(i.Payload[offset : offset+2])
+}
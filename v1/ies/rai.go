@@ -12,11 +12,7 @@ import (
 
 // NewRouteingAreaIdentity creates a new RouteingAreaIdentity IE.
 func NewRouteingAreaIdentity(mcc, mnc string, lac uint16, rac uint8) *IE {
-	mc, err := utils.StrToSwappedBytes(mcc, "f")
-	if err != nil {
-		return nil
-	}
-	mn, err := utils.StrToSwappedBytes(mnc, "f")
+	plmn, err := utils.EncodePLMN(mcc, mnc)
 	if err != nil {
 		return nil
 	}
@@ -25,14 +21,26 @@ func NewRouteingAreaIdentity(mcc, mnc string, lac uint16, rac uint8) *IE {
 		RouteingAreaIdentity,
 		make([]byte, 6),
 	)
-	copy(rai.Payload[0:2], mc)
-	rai.Payload[2] = mn[0]
+	copy(rai.Payload[0:3], plmn)
 	binary.BigEndian.PutUint16(rai.Payload[3:5], lac)
 	rai.Payload[5] = rac
 
 	return rai
 }
 
+// NewRouteingAreaIdentityFromGUTI creates a new RouteingAreaIdentity IE, and
+// PacketTMSI and PTMSISignature IEs mapped from the GUTI, which are used by SGSN
+// to identify the UE moved from MME in the Identification and Context procedures.
+// See utils.GUTIToRAI for the mapping.
+func NewRouteingAreaIdentityFromGUTI(mcc, mnc string, groupID uint16, code uint8, mTMSI uint32) (rai, ptmsi, ptmsiSig *IE) {
+	lac, rac, p, sig := utils.GUTIToRAI(groupID, code, mTMSI)
+	rai = NewRouteingAreaIdentity(mcc, mnc, lac, rac)
+	if rai == nil {
+		return nil, nil, nil
+	}
+	return rai, NewPacketTMSI(p), NewPTMSISignature(sig)
+}
+
 // RouteingAreaIdentity returns RouteingAreaIdentity value if type matches.
 func (i *IE) RouteingAreaIdentity() []byte {
 	if i.Type != RouteingAreaIdentity {
@@ -0,0 +1,20 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewRANTransparentContainer creates a new RANTransparentContainer IE.
+//
+// The container is the RIM PDU defined in 3GPP TS 48.018, which is relayed as it is.
+func NewRANTransparentContainer(container []byte) *IE {
+	return New(RANTransparentContainer, container)
+}
+
+// RANTransparentContainer returns RANTransparentContainer in []byte if type matches.
+func (i *IE) RANTransparentContainer() []byte {
+	if i.Type != RANTransparentContainer {
+		return nil
+	}
+	return i.Payload
+}
@@ -14,5 +14,8 @@ func (i *IE) RANAPCause() uint8 {
 	if i.Type != RANAPCause {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
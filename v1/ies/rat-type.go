@@ -17,5 +17,8 @@ func (i *IE) RATType() uint8 {
 	if i.Type != RATType {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 	return i.Payload[0]
 }
@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// RIM Routing Address Discriminator definitions.
+const (
+	RIMRoutingAddressGERANCellIdentifier uint8 = iota
+	RIMRoutingAddressRNCIdentifier
+	RIMRoutingAddressENBIdentifier
+)
+
+// NewRIMRoutingAddress creates a new RIMRoutingAddress IE.
+//
+// The format of addr depends on the RIM Routing Address Discriminator, which
+// should be given together with NewRIMRoutingAddressDiscriminator.
+func NewRIMRoutingAddress(addr []byte) *IE {
+	return New(RIMRoutingAddress, addr)
+}
+
+// RIMRoutingAddress returns RIMRoutingAddress in []byte if type matches.
+func (i *IE) RIMRoutingAddress() []byte {
+	if i.Type != RIMRoutingAddress {
+		return nil
+	}
+	return i.Payload
+}
+
+// NewRIMRoutingAddressDiscriminator creates a new RIMRoutingAddressDiscriminator IE.
+func NewRIMRoutingAddressDiscriminator(disc uint8) *IE {
+	return newUint8ValIE(RIMRoutingAddressDiscriminator, disc&0x0f)
+}
+
+// RIMRoutingAddressDiscriminator returns RIMRoutingAddressDiscriminator value if type matches.
+func (i *IE) RIMRoutingAddressDiscriminator() uint8 {
+	if i.Type != RIMRoutingAddressDiscriminator {
+		return 0
+	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
+	return i.Payload[0] & 0x0f
+}
@@ -17,5 +17,8 @@ func (i *IE) TeardownInd() bool {
 	if i.Type != TeardownInd {
 		return false
 	}
-	return i.Payload[0]%2 == 1
+	if len(i.Payload) < 1 {
+		return false
+	}
+	return i.Payload[0]&0x01 == 1
 }
@@ -25,6 +25,9 @@ func NewTEIDDataII(teid uint32) *IE {
 func (i *IE) TEID() uint32 {
 	switch i.Type {
 	case TEIDCPlane, TEIDDataI, TEIDDataII:
+		if len(i.Payload) < 4 {
+			return 0
+		}
 		return binary.BigEndian.Uint32(i.Payload)
 	default:
 		return 0
@@ -0,0 +1,23 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "encoding/binary"
+
+// NewTraceReference creates a new TraceReference IE.
+func NewTraceReference(ref uint16) *IE {
+	return newUint16ValIE(TraceReference, ref)
+}
+
+// TraceReference returns TraceReference value if type matches.
+func (i *IE) TraceReference() uint16 {
+	if i.Type != TraceReference {
+		return 0
+	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(i.Payload)
+}
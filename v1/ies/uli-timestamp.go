@@ -19,6 +19,9 @@ func NewULITimestamp(ts time.Time) *IE {
 func (i *IE) Timestamp() time.Time {
 	switch i.Type {
 	case ULITimestamp:
+		if len(i.Payload) < 4 {
+			return time.Time{}
+		}
 		return time.Unix(int64(binary.BigEndian.Uint32(i.Payload)-2208988800), 0)
 	default:
 		return time.Time{}
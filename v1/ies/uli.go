@@ -19,11 +19,7 @@ const (
 
 // NewUserLocationInformationWithCGI creates a new UserLocationInformation IE with LAC.
 func NewUserLocationInformationWithCGI(mcc, mnc string, lac, cgi uint16) *IE {
-	mc, err := utils.StrToSwappedBytes(mcc, "f")
-	if err != nil {
-		return nil
-	}
-	mn, err := utils.StrToSwappedBytes(mnc, "f")
+	plmn, err := utils.EncodePLMN(mcc, mnc)
 	if err != nil {
 		return nil
 	}
@@ -33,8 +29,7 @@ func NewUserLocationInformationWithCGI(mcc, mnc string, lac, cgi uint16) *IE {
 		make([]byte, 8),
 	)
 	uli.Payload[0] = locTypeCGI
-	copy(uli.Payload[1:3], mc)
-	uli.Payload[3] = mn[0]
+	copy(uli.Payload[1:4], plmn)
 	binary.BigEndian.PutUint16(uli.Payload[4:6], lac)
 	binary.BigEndian.PutUint16(uli.Payload[6:8], cgi)
 
@@ -43,11 +38,7 @@ func NewUserLocationInformationWithCGI(mcc, mnc string, lac, cgi uint16) *IE {
 
 // NewUserLocationInformationWithSAI creates a new UserLocationInformation IE with LAC.
 func NewUserLocationInformationWithSAI(mcc, mnc string, lac, sac uint16) *IE {
-	mc, err := utils.StrToSwappedBytes(mcc, "f")
-	if err != nil {
-		return nil
-	}
-	mn, err := utils.StrToSwappedBytes(mnc, "f")
+	plmn, err := utils.EncodePLMN(mcc, mnc)
 	if err != nil {
 		return nil
 	}
@@ -57,8 +48,7 @@ func NewUserLocationInformationWithSAI(mcc, mnc string, lac, sac uint16) *IE {
 		make([]byte, 8),
 	)
 	uli.Payload[0] = locTypeSAI
-	copy(uli.Payload[1:3], mc)
-	uli.Payload[3] = mn[0]
+	copy(uli.Payload[1:4], plmn)
 	binary.BigEndian.PutUint16(uli.Payload[4:6], lac)
 	binary.BigEndian.PutUint16(uli.Payload[6:8], sac)
 
@@ -67,11 +57,7 @@ func NewUserLocationInformationWithSAI(mcc, mnc string, lac, sac uint16) *IE {
 
 // NewUserLocationInformationWithRAI creates a new UserLocationInformation IE with LAC.
 func NewUserLocationInformationWithRAI(mcc, mnc string, lac uint16, rac uint8) *IE {
-	mc, err := utils.StrToSwappedBytes(mcc, "f")
-	if err != nil {
-		return nil
-	}
-	mn, err := utils.StrToSwappedBytes(mnc, "f")
+	plmn, err := utils.EncodePLMN(mcc, mnc)
 	if err != nil {
 		return nil
 	}
@@ -81,8 +67,7 @@ func NewUserLocationInformationWithRAI(mcc, mnc string, lac uint16, rac uint8) *
 		make([]byte, 7),
 	)
 	uli.Payload[0] = locTypeRAI
-	copy(uli.Payload[1:3], mc)
-	uli.Payload[3] = mn[0]
+	copy(uli.Payload[1:4], plmn)
 	binary.BigEndian.PutUint16(uli.Payload[4:6], lac)
 	uli.Payload[6] = rac
 
@@ -99,34 +84,52 @@ func (i *IE) UserLocationInformation() []byte {
 
 // MCC returns MCC value if type matches.
 func (i *IE) MCC() string {
-	switch i.Type {
-	case RouteingAreaIdentity:
-		return utils.SwappedBytesToStr(i.Payload[0:2], false)
-	case UserLocationInformation:
-		return utils.SwappedBytesToStr(i.Payload[1:3], false)
-	default:
-		return ""
-	}
+	mcc, _ := i.plmn()
+	return mcc
 }
 
 // MNC returns MNC value if type matches.
 func (i *IE) MNC() string {
+	_, mnc := i.plmn()
+	return mnc
+}
+
+func (i *IE) plmn() (mcc, mnc string) {
+	var b []byte
 	switch i.Type {
 	case RouteingAreaIdentity:
-		return utils.SwappedBytesToStr(i.Payload[1:2], true)
+		if len(i.Payload) < 3 {
+			return "", ""
+		}
+		b = i.Payload[0:3]
 	case UserLocationInformation:
-		return utils.SwappedBytesToStr(i.Payload[2:3], true)
+		if len(i.Payload) < 4 {
+			return "", ""
+		}
+		b = i.Payload[1:4]
 	default:
-		return ""
+		return "", ""
+	}
+
+	mcc, mnc, err := utils.DecodePLMN(b)
+	if err != nil {
+		return "", ""
 	}
+	return mcc, mnc
 }
 
 // LAC returns LAC value if type matches.
 func (i *IE) LAC() uint16 {
 	switch i.Type {
 	case RouteingAreaIdentity:
+		if len(i.Payload) < 5 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[3:5])
 	case UserLocationInformation:
+		if len(i.Payload) < 6 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[4:6])
 	default:
 		return 0
@@ -135,15 +138,15 @@ func (i *IE) LAC() uint16 {
 
 // CGI returns CGI value if type matches.
 func (i *IE) CGI() uint16 {
-	if i.Type == UserLocationInformation && i.Payload[0] == locTypeCGI {
-		return binary.BigEndian.Uint16(i.Payload[6:7])
+	if i.Type == UserLocationInformation && len(i.Payload) >= 8 && i.Payload[0] == locTypeCGI {
+		return binary.BigEndian.Uint16(i.Payload[6:8])
 	}
 	return 0
 }
 
 // SAC returns SAC value if type matches.
 func (i *IE) SAC() uint16 {
-	if i.Type == UserLocationInformation && i.Payload[0] == locTypeSAI {
+	if i.Type == UserLocationInformation && len(i.Payload) >= 8 && i.Payload[0] == locTypeSAI {
 		return binary.BigEndian.Uint16(i.Payload[6:8])
 	}
 	return 0
@@ -153,9 +156,12 @@ func (i *IE) SAC() uint16 {
 func (i *IE) RAC() uint8 {
 	switch i.Type {
 	case RouteingAreaIdentity:
+		if len(i.Payload) < 6 {
+			return 0
+		}
 		return i.Payload[5]
 	case UserLocationInformation:
-		if i.Payload[0] == locTypeRAI {
+		if len(i.Payload) >= 7 && i.Payload[0] == locTypeRAI {
 			return i.Payload[6]
 		}
 	}
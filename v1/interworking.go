@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// pscAction represents how the PDU Session Container is handled on relay.
+type pscAction uint8
+
+const (
+	pscActionNone pscAction = iota
+	pscActionInsert
+	pscActionStrip
+)
+
+// qfiKey is a key of qfiRelayMap.
+type qfiKey struct {
+	teid uint32
+	qfi  uint8
+}
+
+// RelayToN3 relays T-PDU with teidIn to raddr over the UPlaneConn given, with the
+// PDU Session Container that carries qfi as UL PDU SESSION INFORMATION inserted.
+//
+// This is for the uplink direction when UPlaneConn works as an interworking function
+// between S1-U and N3; teidIn is the S1-U TEID of an EPS bearer, and teidOut is the
+// N3 TEID of the PDU session that the QoS flow mapped from the bearer belongs to.
+// The existing PDU Session Container in the incoming packet, if any, is replaced.
+//
+// The relay can be removed by RemoveRelay as well as the one registered by RelayTo.
+func (u *UPlaneConn) RelayToN3(c *UPlaneConn, teidIn, teidOut uint32, qfi uint8, raddr net.Addr) error {
+	return u.addRelay(newRelayKey(teidIn, nil), &peer{
+		teid: teidOut, addr: raddr, srcConn: c,
+		pscAction: pscActionInsert, qfi: qfi & 0x3f,
+	})
+}
+
+// RelayFromN3 relays T-PDU with teidIn and qfi in the PDU Session Container to raddr
+// over the UPlaneConn given, with the PDU Session Container stripped.
+//
+// This is for the downlink direction when UPlaneConn works as an interworking function
+// between S1-U and N3; teidIn is the N3 TEID of a PDU session, and teidOut is the S1-U
+// TEID of the EPS bearer that the QoS flow identified by qfi is mapped to.
+//
+// The relay registered by RelayTo for teidIn takes precedence over this.
+func (u *UPlaneConn) RelayFromN3(c *UPlaneConn, teidIn uint32, qfi uint8, teidOut uint32, raddr net.Addr) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if u.qfiRelayMap == nil {
+		u.qfiRelayMap = map[qfiKey]*peer{}
+	}
+	u.qfiRelayMap[qfiKey{teid: teidIn, qfi: qfi & 0x3f}] = &peer{
+		teid: teidOut, addr: raddr, srcConn: c,
+		pscAction: pscActionStrip,
+	}
+	return nil
+}
+
+// RemoveRelayFromN3 removes the relay registered by RelayFromN3.
+// If no relay is registered for the pair of teidIn and qfi, this returns ErrRelayNotFound.
+func (u *UPlaneConn) RemoveRelayFromN3(teidIn uint32, qfi uint8) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	key := qfiKey{teid: teidIn, qfi: qfi & 0x3f}
+	if _, ok := u.qfiRelayMap[key]; !ok {
+		return ErrRelayNotFound
+	}
+	delete(u.qfiRelayMap, key)
+	return nil
+}
+
+// lookupQFIRelay looks up the relay for the packet with teid by the QFI in the
+// PDU Session Container. The caller must hold relayMu.
+func (u *UPlaneConn) lookupQFIRelay(teid uint32, payload []byte) (*peer, bool) {
+	if len(u.qfiRelayMap) == 0 {
+		return nil, false
+	}
+
+	header, err := messages.DecodeHeader(payload)
+	if err != nil {
+		return nil, false
+	}
+	psc, ok := header.ExtensionHeader(messages.ExtHeaderTypePDUSessionContainer)
+	if !ok {
+		return nil, false
+	}
+	qfi, err := psc.QFI()
+	if err != nil {
+		return nil, false
+	}
+
+	p, ok := u.qfiRelayMap[qfiKey{teid: teid, qfi: qfi}]
+	return p, ok
+}
+
+// convertPSC returns the T-PDU in payload with the PDU Session Container inserted
+// or stripped, and the TEID rewritten.
+func (p *peer) convertPSC(payload []byte) ([]byte, error) {
+	header, err := messages.DecodeHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	header.TEID = p.teid
+	header.RemoveExtensionHeader(messages.ExtHeaderTypePDUSessionContainer)
+	if p.pscAction == pscActionInsert {
+		header.AddExtensionHeaders(
+			messages.NewPDUSessionContainer(messages.PDUTypeULPDUSessionInformation, p.qfi),
+		)
+	}
+
+	return header.Serialize()
+}
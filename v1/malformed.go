@@ -0,0 +1,65 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/sampler"
+)
+
+// MalformedHandlerFunc is a callback to be told the packet that cannot be decoded as
+// a message, with the error and the number of the ones suppressed since the last call.
+type MalformedHandlerFunc func(peer net.Addr, err error, suppressed uint64)
+
+// SetMalformedHandler sets the handler called with the packets received that cannot
+// be decoded, which are discarded silently otherwise, e.g., to log them.
+//
+// The handler is called only for the first burst packets in each interval, and the
+// others are just counted as suppressed, so that a flood of garbage on fuzzing or
+// attack does not produce the same number of logs and keep the Conn busy. The handler
+// is called synchronously and should return quickly. Giving nil removes it.
+func (c *CPlaneConn) SetMalformedHandler(fn MalformedHandlerFunc, burst int, interval time.Duration) {
+	c.malformed.set(fn, burst, interval)
+}
+
+// SetMalformedHandler sets the handler called with the packets received that cannot
+// be decoded. See (*CPlaneConn).SetMalformedHandler for details.
+//
+// The packets are counted as DropMalformed in Stats regardless of the handler.
+func (u *UPlaneConn) SetMalformedHandler(fn MalformedHandlerFunc, burst int, interval time.Duration) {
+	u.malformed.set(fn, burst, interval)
+}
+
+// malformedReporter calls the MalformedHandlerFunc sampled. The zero value is ready
+// to use, which reports nothing.
+type malformedReporter struct {
+	handler atomic.Pointer[sampledHandler]
+}
+
+type sampledHandler struct {
+	fn      MalformedHandlerFunc
+	sampler *sampler.Sampler
+}
+
+func (m *malformedReporter) set(fn MalformedHandlerFunc, burst int, interval time.Duration) {
+	if fn == nil {
+		m.handler.Store(nil)
+		return
+	}
+	m.handler.Store(&sampledHandler{fn: fn, sampler: sampler.New(burst, interval)})
+}
+
+func (m *malformedReporter) report(peer net.Addr, err error) {
+	h := m.handler.Load()
+	if h == nil {
+		return
+	}
+	if ok, suppressed := h.sampler.Allow(time.Now()); ok {
+		h.fn(peer, err, suppressed)
+	}
+}
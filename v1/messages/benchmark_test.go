@@ -0,0 +1,108 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+var benchMessages = []struct {
+	description string
+	msg         messages.Message
+}{
+	{
+		"EchoRequest",
+		messages.NewEchoRequest(1, ies.NewRecovery(0x80)),
+	}, {
+		"CreatePDPContextRequest",
+		messages.NewCreatePDPContextRequest(
+			0, 1,
+			ies.NewIMSI("123451234567891"),
+			ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+			ies.NewSelectionMode(0),
+			ies.NewTEIDDataI(0xdeadbeef),
+			ies.NewTEIDCPlane(0xdeadbeef),
+			ies.NewNSAPI(5),
+			ies.NewEndUserAddressIPv4(""),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewProtocolConfigurationOptions(0, ies.NewConfigurationProtocolOption(0x000d, nil)),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewMSISDN("8130900000001"),
+			ies.NewQoSProfileFromPayload(ies.NewQoSProfilePayloadR99(0x02, 3, 1, 0, 0, 0, 0)),
+			ies.NewCommonFlags(0, 1, 0, 0, 0, 0, 0, 0),
+			ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222),
+			ies.NewMSTimeZone(0, 0),
+		),
+	}, {
+		"CreatePDPContextResponse",
+		messages.NewCreatePDPContextResponse(
+			0xdeadbeef, 1,
+			ies.NewCause(128),
+			ies.NewTEIDDataI(0xdeadbeef),
+			ies.NewTEIDCPlane(0xdeadbeef),
+			ies.NewEndUserAddressIPv4("2.2.2.2"),
+			ies.NewGSNAddress("1.1.1.2"),
+			ies.NewGSNAddress("1.1.1.2"),
+		),
+	},
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			buf := make([]byte, c.msg.Len())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			serialized, err := messages.Serialize(c.msg)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := messages.Decode(serialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSerializeToAllocs(t *testing.T) {
+	for _, c := range benchMessages {
+		t.Run(c.description, func(t *testing.T) {
+			buf := make([]byte, c.msg.Len())
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("got %v allocations, want 0", allocs)
+			}
+
+			if err := c.msg.SerializeTo(buf[:len(buf)-1]); err != messages.ErrTooShortToSerialize {
+				t.Errorf("got %v, want %v", err, messages.ErrTooShortToSerialize)
+			}
+		})
+	}
+}
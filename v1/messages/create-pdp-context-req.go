@@ -171,10 +171,9 @@ func (c *CreatePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (c *CreatePDPContextRequest) SerializeTo(b []byte) error {
-	if len(b) < c.Len() {
-		return ErrTooShortToSerialize
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.IMSI; ie != nil {
@@ -131,10 +131,9 @@ func (c *CreatePDPContextResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (c *CreatePDPContextResponse) SerializeTo(b []byte) error {
-	if len(b) < c.Len() {
-		return ErrTooShortToSerialize
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.Cause; ie != nil {
@@ -73,10 +73,9 @@ func (d *DeletePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (d *DeletePDPContextRequest) SerializeTo(b []byte) error {
-	if len(b) < d.Len() {
-		return ErrTooShortToSerialize
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
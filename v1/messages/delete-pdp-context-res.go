@@ -64,10 +64,9 @@ func (d *DeletePDPContextResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (d *DeletePDPContextResponse) SerializeTo(b []byte) error {
-	if len(b) < d.Len() {
-		return ErrTooShortToSerialize
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
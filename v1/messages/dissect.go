@@ -0,0 +1,73 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// Dissect returns the message in human readable, multi-line format, which lists
+// the header fields and every IE with its name, length and value.
+func Dissect(m Message) string {
+	b, err := Serialize(m)
+	if err != nil {
+		return fmt.Sprintf("GTPv1 %s\n  ! failed to serialize message: %s\n", m.MessageTypeName(), err)
+	}
+	return DissectBytes(b)
+}
+
+// DissectBytes returns the message in b in the same format as Dissect.
+//
+// Unlike Decode, this does not give up on the message which cannot be decoded,
+// but prints the error together with the IEs so that the problematic IE can be
+// found.
+func DissectBytes(b []byte) string {
+	s := &strings.Builder{}
+
+	h, err := DecodeHeader(b)
+	if err != nil {
+		fmt.Fprintf(s, "GTPv1 (malformed header: %s)\n", err)
+		return s.String()
+	}
+
+	name := fmt.Sprintf("Unknown(%d)", h.Type)
+	msg, decErr := Decode(b)
+	if decErr == nil {
+		name = msg.MessageTypeName()
+	}
+	fmt.Fprintf(s, "GTPv1 %s\n", name)
+	fmt.Fprintf(s, "  Header: Flags: %#02x, Type: %d, Length: %d, TEID: %#08x", h.Flags, h.Type, h.Length, h.TEID)
+	if h.HasSequence() {
+		fmt.Fprintf(s, ", SequenceNumber: %d", h.SequenceNumber)
+	}
+	if h.HasNPDUNumber() {
+		fmt.Fprintf(s, ", NPDUNumber: %d", h.NPDUNumber)
+	}
+	fmt.Fprintln(s)
+	for _, eh := range h.ExtensionHeaders {
+		fmt.Fprintf(s, "  ExtensionHeader: Type: %#02x, Content: %x\n", eh.Type, eh.Content)
+	}
+	if decErr != nil {
+		fmt.Fprintf(s, "  ! failed to decode message: %s\n", decErr)
+	}
+
+	if h.Type == MsgTypeTPDU {
+		fmt.Fprintf(s, "  T-PDU: %d bytes\n", len(h.Payload))
+		return s.String()
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		fmt.Fprintf(s, "  ! failed to decode IEs: %s\n", err)
+	}
+	for _, ie := range decodedIEs {
+		fmt.Fprintf(s, "  %s(%d) length: %d, value: %s\n", ie.Name(), ie.Type, len(ie.Payload), ie.ValueString())
+	}
+
+	return s.String()
+}
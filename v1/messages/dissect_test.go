@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestDissect(t *testing.T) {
+	msg := messages.NewDeletePDPContextRequest(
+		0x11223344, 0x1234,
+		ies.NewTeardownInd(true),
+		ies.NewNSAPI(5),
+	)
+
+	want := `GTPv1 Delete PDP Context Request
+  Header: Flags: 0x32, Type: 20, Length: 8, TEID: 0x11223344, SequenceNumber: 4660
+  TeardownInd(19) length: 1, value: true
+  NSAPI(20) length: 1, value: 5
+`
+	if got := messages.Dissect(msg); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDissectBytesTPDU(t *testing.T) {
+	b := []byte{
+		0x32, 0xff, 0x00, 0x08, 0x11, 0x22, 0x33, 0x44, 0x00, 0x01, 0x00, 0x00,
+		0xde, 0xad, 0xbe, 0xef,
+	}
+
+	want := `GTPv1 T-PDU
+  Header: Flags: 0x32, Type: 255, Length: 8, TEID: 0x11223344, SequenceNumber: 1
+  T-PDU: 4 bytes
+`
+	if got := messages.DissectBytes(b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -49,10 +49,9 @@ func (e *EchoRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (e *EchoRequest) SerializeTo(b []byte) error {
-	if e.Header.Payload != nil {
-		e.Header.Payload = nil
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
 	}
-	e.Header.Payload = make([]byte, e.Len()-e.Header.Len())
 
 	offset := 0
 	if ie := e.PrivateExtension; ie != nil {
@@ -52,10 +52,9 @@ func (e *EchoResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (e *EchoResponse) SerializeTo(b []byte) error {
-	if e.Header.Payload != nil {
-		e.Header.Payload = nil
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
 	}
-	e.Header.Payload = make([]byte, e.Len()-e.Header.Len())
 
 	offset := 0
 	if ie := e.Recovery; ie != nil {
@@ -65,7 +64,7 @@ func (e *EchoResponse) SerializeTo(b []byte) error {
 		offset += ie.Len()
 	}
 	if ie := e.PrivateExtension; ie != nil {
-		if err := ie.SerializeTo(e.Header.Payload); err != nil {
+		if err := ie.SerializeTo(e.Header.Payload[offset:]); err != nil {
 			return err
 		}
 		offset += ie.Len()
@@ -0,0 +1,146 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// EndMarker is a EndMarker Header and its IEs above.
+// This is a GTPv1-U message sent to the old path to indicate the end of the payload.
+type EndMarker struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewEndMarker creates a new End Marker.
+func NewEndMarker(teid uint32, ie ...*ies.IE) *EndMarker {
+	e := &EndMarker{
+		Header: NewHeader(0x30, MsgTypeEndMarker, teid, 0, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	e.SetLength()
+	return e
+}
+
+// Serialize returns the byte sequence generated from a EndMarker.
+func (e *EndMarker) Serialize() ([]byte, error) {
+	b := make([]byte, e.Len())
+	if err := e.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (e *EndMarker) SerializeTo(b []byte) error {
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := e.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(e.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(e.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	e.Header.SetLength()
+	return e.Header.SerializeTo(b)
+}
+
+// DecodeEndMarker decodes a given byte sequence as a EndMarker.
+func DecodeEndMarker(b []byte) (*EndMarker, error) {
+	e := &EndMarker{}
+	if err := e.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a EndMarker.
+func (e *EndMarker) DecodeFromBytes(b []byte) error {
+	var err error
+	e.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+
+	ie, err := ies.DecodeMultiIEs(e.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (e *EndMarker) Len() int {
+	l := e.Header.Len() - len(e.Header.Payload)
+
+	if ie := e.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (e *EndMarker) SetLength() {
+	e.Header.Length = uint16(e.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (e *EndMarker) MessageTypeName() string {
+	return "End Marker"
+}
+
+// TEID returns the TEID in human-readable string.
+func (e *EndMarker) TEID() uint32 {
+	return e.Header.TEID
+}
@@ -53,10 +53,9 @@ func (e *ErrorIndication) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (e *ErrorIndication) SerializeTo(b []byte) error {
-	if len(b) < e.Len() {
-		return ErrTooShortToSerialize
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
 	}
-	e.Header.Payload = make([]byte, e.Len()-e.Header.Len())
 
 	offset := 0
 	if ie := e.TEIDDataI; ie != nil {
@@ -12,4 +12,6 @@ var (
 	ErrTooShortToSerialize = errors.New("too short to serialize")
 	ErrTooShortToDecode    = errors.New("too short to decode as GTPv1")
 	ErrInvalidMessageType  = errors.New("got invalid message type")
+
+	ErrInvalidExtensionHeaderType = errors.New("got invalid extension header type")
 )
@@ -0,0 +1,164 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "fmt"
+
+// Extension Header Type definitions.
+const (
+	ExtHeaderTypeNoMoreExtensionHeaders uint8 = 0x00
+	ExtHeaderTypeServiceClassIndicator  uint8 = 0x20
+	ExtHeaderTypeUDPPort                uint8 = 0x40
+	ExtHeaderTypeRANContainer           uint8 = 0x81
+	ExtHeaderTypeLongPDCPPDUNumber      uint8 = 0x82
+	ExtHeaderTypeXwRANContainer         uint8 = 0x83
+	ExtHeaderTypeNRRANContainer         uint8 = 0x84
+	ExtHeaderTypePDUSessionContainer    uint8 = 0x85
+	ExtHeaderTypePDCPPDUNumber          uint8 = 0xc0
+)
+
+// PDU Type definitions in PDU Session Container.
+const (
+	PDUTypeDLPDUSessionInformation uint8 = 0
+	PDUTypeULPDUSessionInformation uint8 = 1
+)
+
+// ExtensionHeader is a GTPv1-U Extension Header.
+//
+// Content does not include the Length and Next Extension Header Type fields,
+// which are calculated on serialization. Content is padded with zeros if its
+// length does not fit in the 4-octet boundary.
+type ExtensionHeader struct {
+	Type    uint8
+	Content []byte
+}
+
+// NewExtensionHeader creates a new ExtensionHeader.
+func NewExtensionHeader(typ uint8, content []byte) *ExtensionHeader {
+	return &ExtensionHeader{Type: typ, Content: content}
+}
+
+// NewPDUSessionContainer creates a new PDU Session Container Extension Header
+// with the minimum content, which consists of PDU Type and QFI.
+func NewPDUSessionContainer(pduType, qfi uint8) *ExtensionHeader {
+	return NewExtensionHeader(
+		ExtHeaderTypePDUSessionContainer,
+		[]byte{(pduType & 0x0f) << 4, qfi & 0x3f},
+	)
+}
+
+// PDUType returns the PDU Type in PDU Session Container.
+func (e *ExtensionHeader) PDUType() (uint8, error) {
+	if e.Type != ExtHeaderTypePDUSessionContainer {
+		return 0, ErrInvalidExtensionHeaderType
+	}
+	if len(e.Content) < 1 {
+		return 0, ErrTooShortToDecode
+	}
+	return e.Content[0] >> 4, nil
+}
+
+// QFI returns the QoS Flow Identifier in PDU Session Container.
+func (e *ExtensionHeader) QFI() (uint8, error) {
+	if e.Type != ExtHeaderTypePDUSessionContainer {
+		return 0, ErrInvalidExtensionHeaderType
+	}
+	if len(e.Content) < 2 {
+		return 0, ErrTooShortToDecode
+	}
+	return e.Content[1] & 0x3f, nil
+}
+
+// Len returns the actual length of ExtensionHeader including Length and
+// Next Extension Header Type fields.
+func (e *ExtensionHeader) Len() int {
+	l := len(e.Content) + 2
+	if r := l % 4; r != 0 {
+		l += 4 - r
+	}
+	return l
+}
+
+func (e *ExtensionHeader) serializeTo(b []byte, next uint8) error {
+	l := e.Len()
+	if len(b) < l {
+		return ErrTooShortToSerialize
+	}
+
+	b[0] = uint8(l / 4)
+	n := copy(b[1:l-1], e.Content)
+	for i := 1 + n; i < l-1; i++ {
+		b[i] = 0
+	}
+	b[l-1] = next
+	return nil
+}
+
+// decodeExtensionHeader decodes an Extension Header of type typ at the head of b,
+// and returns it with the number of octets consumed.
+func decodeExtensionHeader(b []byte, typ uint8) (*ExtensionHeader, int, error) {
+	if len(b) < 1 {
+		return nil, 0, ErrTooShortToDecode
+	}
+	l := int(b[0]) * 4
+	if l == 0 {
+		return nil, 0, ErrInvalidLength
+	}
+	if len(b) < l {
+		return nil, 0, ErrTooShortToDecode
+	}
+
+	return &ExtensionHeader{Type: typ, Content: b[1 : l-1]}, l, nil
+}
+
+// String returns the ExtensionHeader values in human readable format.
+func (e *ExtensionHeader) String() string {
+	return fmt.Sprintf("{Type: %#x, Content: %#v}", e.Type, e.Content)
+}
+
+// AddExtensionHeaders appends the Extension Headers given and sets E flag.
+func (h *Header) AddExtensionHeaders(exts ...*ExtensionHeader) {
+	for _, e := range exts {
+		if e == nil {
+			continue
+		}
+		h.ExtensionHeaders = append(h.ExtensionHeaders, e)
+	}
+	if len(h.ExtensionHeaders) != 0 {
+		h.Flags |= 0x04
+		h.NextExtensionHeaderType = h.ExtensionHeaders[0].Type
+	}
+	h.SetLength()
+}
+
+// ExtensionHeader returns the first Extension Header of the type given.
+func (h *Header) ExtensionHeader(typ uint8) (*ExtensionHeader, bool) {
+	for _, e := range h.ExtensionHeaders {
+		if e.Type == typ {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// RemoveExtensionHeader removes all the Extension Headers of the type given.
+// E flag is cleared if no Extension Header is left.
+func (h *Header) RemoveExtensionHeader(typ uint8) {
+	exts := h.ExtensionHeaders[:0]
+	for _, e := range h.ExtensionHeaders {
+		if e.Type != typ {
+			exts = append(exts, e)
+		}
+	}
+	h.ExtensionHeaders = exts
+	if len(exts) == 0 {
+		h.ExtensionHeaders = nil
+		h.Flags &^= 0x04
+		h.NextExtensionHeaderType = ExtHeaderTypeNoMoreExtensionHeaders
+	} else {
+		h.NextExtensionHeaderType = exts[0].Type
+	}
+	h.SetLength()
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// ForwardRelocationCompleteAcknowledge is a ForwardRelocationCompleteAcknowledge Header and its IEs above.
+type ForwardRelocationCompleteAcknowledge struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewForwardRelocationCompleteAcknowledge creates a new GTPv1 ForwardRelocationCompleteAcknowledge.
+func NewForwardRelocationCompleteAcknowledge(teid uint32, seq uint16, ie ...*ies.IE) *ForwardRelocationCompleteAcknowledge {
+	f := &ForwardRelocationCompleteAcknowledge{
+		Header: NewHeader(0x32, MsgTypeForwardRelocationCompleteAcknowledge, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			f.Cause = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Serialize returns the byte sequence generated from a ForwardRelocationCompleteAcknowledge.
+func (f *ForwardRelocationCompleteAcknowledge) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (f *ForwardRelocationCompleteAcknowledge) SerializeTo(b []byte) error {
+	if err := f.Header.preparePayload(b, f.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := f.Cause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	f.Header.SetLength()
+	return f.Header.SerializeTo(b)
+}
+
+// DecodeForwardRelocationCompleteAcknowledge decodes a given byte sequence as a ForwardRelocationCompleteAcknowledge.
+func DecodeForwardRelocationCompleteAcknowledge(b []byte) (*ForwardRelocationCompleteAcknowledge, error) {
+	f := &ForwardRelocationCompleteAcknowledge{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a ForwardRelocationCompleteAcknowledge.
+func (f *ForwardRelocationCompleteAcknowledge) DecodeFromBytes(b []byte) error {
+	var err error
+	f.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			f.Cause = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (f *ForwardRelocationCompleteAcknowledge) Len() int {
+	l := f.Header.Len() - len(f.Header.Payload)
+
+	if ie := f.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationCompleteAcknowledge) SetLength() {
+	f.Length = uint16(f.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationCompleteAcknowledge) MessageTypeName() string {
+	return "Forward Relocation Complete Acknowledge"
+}
+
+// TEID returns the TEID in human-readable string.
+func (f *ForwardRelocationCompleteAcknowledge) TEID() uint32 {
+	return f.Header.TEID
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// ForwardRelocationComplete is a ForwardRelocationComplete Header and its IEs above.
+type ForwardRelocationComplete struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewForwardRelocationComplete creates a new GTPv1 ForwardRelocationComplete.
+func NewForwardRelocationComplete(teid uint32, seq uint16, ie ...*ies.IE) *ForwardRelocationComplete {
+	f := &ForwardRelocationComplete{
+		Header: NewHeader(0x32, MsgTypeForwardRelocationComplete, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Serialize returns the byte sequence generated from a ForwardRelocationComplete.
+func (f *ForwardRelocationComplete) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (f *ForwardRelocationComplete) SerializeTo(b []byte) error {
+	if err := f.Header.preparePayload(b, f.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	f.Header.SetLength()
+	return f.Header.SerializeTo(b)
+}
+
+// DecodeForwardRelocationComplete decodes a given byte sequence as a ForwardRelocationComplete.
+func DecodeForwardRelocationComplete(b []byte) (*ForwardRelocationComplete, error) {
+	f := &ForwardRelocationComplete{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a ForwardRelocationComplete.
+func (f *ForwardRelocationComplete) DecodeFromBytes(b []byte) error {
+	var err error
+	f.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (f *ForwardRelocationComplete) Len() int {
+	l := f.Header.Len() - len(f.Header.Payload)
+
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationComplete) SetLength() {
+	f.Length = uint16(f.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationComplete) MessageTypeName() string {
+	return "Forward Relocation Complete"
+}
+
+// TEID returns the TEID in human-readable string.
+func (f *ForwardRelocationComplete) TEID() uint32 {
+	return f.Header.TEID
+}
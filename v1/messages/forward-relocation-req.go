@@ -0,0 +1,759 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// ForwardRelocationRequest is a ForwardRelocationRequest Header and its IEs above.
+type ForwardRelocationRequest struct {
+	*Header
+	IMSI                                   *ies.IE
+	TEIDCPlane                             *ies.IE
+	RANAPCause                             *ies.IE
+	PacketFlowIDs                          []*ies.IE
+	ChargingCharacteristics                *ies.IE
+	MMContext                              *ies.IE
+	PDPContexts                            []*ies.IE
+	SGSNAddressForCPlane                   *ies.IE
+	TargetIdentification                   *ies.IE
+	UTRANTransparentContainer              *ies.IE
+	PDPContextPrioritization               *ies.IE
+	MBMSUEContexts                         []*ies.IE
+	SelectedPLMNID                         *ies.IE
+	BSSContainer                           *ies.IE
+	CellIdentification                     *ies.IE
+	BSSGPCause                             *ies.IE
+	PSHandoverXIDParameters                []*ies.IE
+	DirectTunnelFlags                      *ies.IE
+	ReliableInterRATHandoverInfo           *ies.IE
+	SubscribedRFSPIndex                    *ies.IE
+	RFSPIndexInUse                         *ies.IE
+	ColocatedGGSNPGWFQDN                   *ies.IE
+	EvolvedARPIIs                          []*ies.IE
+	ExtendedCommonFlags                    *ies.IE
+	CSGID                                  *ies.IE
+	CSGMembershipIndication                *ies.IE
+	UENetworkCapability                    *ies.IE
+	UEAMBR                                 *ies.IE
+	APNAMBRWithNSAPIs                      []*ies.IE
+	SignallingPriorityIndicationWithNSAPIs []*ies.IE
+	HigherBitratesThan16MbpsFlag           *ies.IE
+	AdditionalMMContextForSRVCC            *ies.IE
+	AdditionalFlagsForSRVCC                *ies.IE
+	STNSR                                  *ies.IE
+	CMSISDN                                *ies.IE
+	ExtendedRANAPCause                     *ies.IE
+	ENodeBID                               *ies.IE
+	SelectionModeWithNSAPIs                []*ies.IE
+	UEUsageType                            *ies.IE
+	ExtendedCommonFlagsII                  *ies.IE
+	PrivateExtension                       *ies.IE
+	AdditionalIEs                          []*ies.IE
+}
+
+// NewForwardRelocationRequest creates a new GTPv1 ForwardRelocationRequest.
+func NewForwardRelocationRequest(teid uint32, seq uint16, ie ...*ies.IE) *ForwardRelocationRequest {
+	f := &ForwardRelocationRequest{
+		Header: NewHeader(0x32, MsgTypeForwardRelocationRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			f.IMSI = i
+		case ies.TEIDCPlane:
+			f.TEIDCPlane = i
+		case ies.RANAPCause:
+			f.RANAPCause = i
+		case ies.PacketFlowID:
+			f.PacketFlowIDs = append(f.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			f.ChargingCharacteristics = i
+		case ies.MMContext:
+			f.MMContext = i
+		case ies.PDPContext:
+			f.PDPContexts = append(f.PDPContexts, i)
+		case ies.GSNAddress:
+			f.SGSNAddressForCPlane = i
+		case ies.TargetIdentification:
+			f.TargetIdentification = i
+		case ies.UTRANTransparentContainer:
+			f.UTRANTransparentContainer = i
+		case ies.PDPContextPrioritization:
+			f.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			f.MBMSUEContexts = append(f.MBMSUEContexts, i)
+		case ies.SelectedPLMNID:
+			f.SelectedPLMNID = i
+		case ies.BSSContainer:
+			f.BSSContainer = i
+		case ies.CellIdentification:
+			f.CellIdentification = i
+		case ies.BSSGPCause:
+			f.BSSGPCause = i
+		case ies.PSHandoverXIDParameters:
+			f.PSHandoverXIDParameters = append(f.PSHandoverXIDParameters, i)
+		case ies.DirectTunnelFlags:
+			f.DirectTunnelFlags = i
+		case ies.ReliableInterRATHandoverInfo:
+			f.ReliableInterRATHandoverInfo = i
+		case ies.RFSPIndex:
+			if f.SubscribedRFSPIndex == nil {
+				f.SubscribedRFSPIndex = i
+			} else if f.RFSPIndexInUse == nil {
+				f.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			f.ColocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			f.EvolvedARPIIs = append(f.EvolvedARPIIs, i)
+		case ies.ExtendedCommonFlags:
+			f.ExtendedCommonFlags = i
+		case ies.CSGID:
+			f.CSGID = i
+		case ies.CSGMembershipIndication:
+			f.CSGMembershipIndication = i
+		case ies.UENetworkCapability:
+			f.UENetworkCapability = i
+		case ies.UEAMBR:
+			f.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			f.APNAMBRWithNSAPIs = append(f.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			f.SignallingPriorityIndicationWithNSAPIs = append(f.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			f.HigherBitratesThan16MbpsFlag = i
+		case ies.AdditionalMMContextForSRVCC:
+			f.AdditionalMMContextForSRVCC = i
+		case ies.AdditionalFlagsForSRVCC:
+			f.AdditionalFlagsForSRVCC = i
+		case ies.STNSR:
+			f.STNSR = i
+		case ies.CMSISDN:
+			f.CMSISDN = i
+		case ies.ExtendedRANAPCause:
+			f.ExtendedRANAPCause = i
+		case ies.ENodeBID:
+			f.ENodeBID = i
+		case ies.SelectionModeWithNSAPI:
+			f.SelectionModeWithNSAPIs = append(f.SelectionModeWithNSAPIs, i)
+		case ies.UEUsageType:
+			f.UEUsageType = i
+		case ies.ExtendedCommonFlagsII:
+			f.ExtendedCommonFlagsII = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Serialize returns the byte sequence generated from a ForwardRelocationRequest.
+func (f *ForwardRelocationRequest) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (f *ForwardRelocationRequest) SerializeTo(b []byte) error {
+	if err := f.Header.preparePayload(b, f.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := f.IMSI; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.TEIDCPlane; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ChargingCharacteristics; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.MMContext; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SGSNAddressForCPlane; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.TargetIdentification; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.PDPContextPrioritization; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SelectedPLMNID; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.CellIdentification; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.PSHandoverXIDParameters {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.DirectTunnelFlags; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ReliableInterRATHandoverInfo; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SubscribedRFSPIndex; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.RFSPIndexInUse; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ColocatedGGSNPGWFQDN; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.EvolvedARPIIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ExtendedCommonFlags; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.CSGID; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.CSGMembershipIndication; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.UENetworkCapability; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.UEAMBR; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.HigherBitratesThan16MbpsFlag; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.AdditionalMMContextForSRVCC; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.AdditionalFlagsForSRVCC; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.STNSR; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.CMSISDN; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ExtendedRANAPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ENodeBID; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.UEUsageType; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ExtendedCommonFlagsII; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	f.Header.SetLength()
+	return f.Header.SerializeTo(b)
+}
+
+// DecodeForwardRelocationRequest decodes a given byte sequence as a ForwardRelocationRequest.
+func DecodeForwardRelocationRequest(b []byte) (*ForwardRelocationRequest, error) {
+	f := &ForwardRelocationRequest{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a ForwardRelocationRequest.
+func (f *ForwardRelocationRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	f.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			f.IMSI = i
+		case ies.TEIDCPlane:
+			f.TEIDCPlane = i
+		case ies.RANAPCause:
+			f.RANAPCause = i
+		case ies.PacketFlowID:
+			f.PacketFlowIDs = append(f.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			f.ChargingCharacteristics = i
+		case ies.MMContext:
+			f.MMContext = i
+		case ies.PDPContext:
+			f.PDPContexts = append(f.PDPContexts, i)
+		case ies.GSNAddress:
+			f.SGSNAddressForCPlane = i
+		case ies.TargetIdentification:
+			f.TargetIdentification = i
+		case ies.UTRANTransparentContainer:
+			f.UTRANTransparentContainer = i
+		case ies.PDPContextPrioritization:
+			f.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			f.MBMSUEContexts = append(f.MBMSUEContexts, i)
+		case ies.SelectedPLMNID:
+			f.SelectedPLMNID = i
+		case ies.BSSContainer:
+			f.BSSContainer = i
+		case ies.CellIdentification:
+			f.CellIdentification = i
+		case ies.BSSGPCause:
+			f.BSSGPCause = i
+		case ies.PSHandoverXIDParameters:
+			f.PSHandoverXIDParameters = append(f.PSHandoverXIDParameters, i)
+		case ies.DirectTunnelFlags:
+			f.DirectTunnelFlags = i
+		case ies.ReliableInterRATHandoverInfo:
+			f.ReliableInterRATHandoverInfo = i
+		case ies.RFSPIndex:
+			if f.SubscribedRFSPIndex == nil {
+				f.SubscribedRFSPIndex = i
+			} else if f.RFSPIndexInUse == nil {
+				f.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			f.ColocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			f.EvolvedARPIIs = append(f.EvolvedARPIIs, i)
+		case ies.ExtendedCommonFlags:
+			f.ExtendedCommonFlags = i
+		case ies.CSGID:
+			f.CSGID = i
+		case ies.CSGMembershipIndication:
+			f.CSGMembershipIndication = i
+		case ies.UENetworkCapability:
+			f.UENetworkCapability = i
+		case ies.UEAMBR:
+			f.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			f.APNAMBRWithNSAPIs = append(f.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			f.SignallingPriorityIndicationWithNSAPIs = append(f.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			f.HigherBitratesThan16MbpsFlag = i
+		case ies.AdditionalMMContextForSRVCC:
+			f.AdditionalMMContextForSRVCC = i
+		case ies.AdditionalFlagsForSRVCC:
+			f.AdditionalFlagsForSRVCC = i
+		case ies.STNSR:
+			f.STNSR = i
+		case ies.CMSISDN:
+			f.CMSISDN = i
+		case ies.ExtendedRANAPCause:
+			f.ExtendedRANAPCause = i
+		case ies.ENodeBID:
+			f.ENodeBID = i
+		case ies.SelectionModeWithNSAPI:
+			f.SelectionModeWithNSAPIs = append(f.SelectionModeWithNSAPIs, i)
+		case ies.UEUsageType:
+			f.UEUsageType = i
+		case ies.ExtendedCommonFlagsII:
+			f.ExtendedCommonFlagsII = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (f *ForwardRelocationRequest) Len() int {
+	l := f.Header.Len() - len(f.Header.Payload)
+
+	if ie := f.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.TEIDCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.ChargingCharacteristics; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.MMContext; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.SGSNAddressForCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.TargetIdentification; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.PDPContextPrioritization; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.SelectedPLMNID; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.CellIdentification; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.PSHandoverXIDParameters {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.DirectTunnelFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ReliableInterRATHandoverInfo; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.SubscribedRFSPIndex; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.RFSPIndexInUse; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ColocatedGGSNPGWFQDN; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.EvolvedARPIIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.ExtendedCommonFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.CSGID; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.CSGMembershipIndication; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.UENetworkCapability; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.UEAMBR; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	for _, ie := range f.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.HigherBitratesThan16MbpsFlag; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.AdditionalMMContextForSRVCC; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.AdditionalFlagsForSRVCC; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.STNSR; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.CMSISDN; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ExtendedRANAPCause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ENodeBID; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.UEUsageType; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ExtendedCommonFlagsII; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationRequest) SetLength() {
+	f.Length = uint16(f.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationRequest) MessageTypeName() string {
+	return "Forward Relocation Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (f *ForwardRelocationRequest) TEID() uint32 {
+	return f.Header.TEID
+}
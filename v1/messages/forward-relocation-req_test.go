@@ -0,0 +1,71 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestForwardRelocationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewForwardRelocationRequest(
+				0, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewTEIDCPlane(0xdeadbeef),
+				ies.NewRANAPCause(v1.RANAPCauseRelocationTriggered),
+				ies.NewMMContext(ies.NewMMContextPayloadGSM(
+					1, 2, []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77},
+					[]byte{0x09, 0x00}, []byte{0xe5, 0xe0},
+				)),
+				ies.NewPDPContext(&ies.PDPContextPayload{NSAPI: 5, PDPTypeOrganization: 1, PDPTypeNumber: 0x21}),
+				ies.NewGSNAddress("1.1.1.1"),
+				ies.New(ies.TargetIdentification, []byte{0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x00, 0x01}),
+				ies.New(ies.UTRANTransparentContainer, []byte{0xde, 0xad, 0xbe, 0xef}),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x35, 0x00, 0x5f, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00,
+				// IMSI
+				0x02, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// TEID C-Plane
+				0x11, 0xde, 0xad, 0xbe, 0xef,
+				// RANAP Cause
+				0x15, 0x06,
+				// MM Context
+				0x81, 0x00, 0x11,
+				0xf9, 0x42, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+				0x09, 0x00, 0x02, 0xe5, 0xe0, 0x00, 0x00,
+				// PDP Context
+				0x82, 0x00, 0x1b,
+				0x05, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0xf1, 0x21, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// SGSN Address for Control Plane
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+				// Target Identification
+				0x8a, 0x00, 0x08, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x00, 0x01,
+				// UTRAN Transparent Container
+				0x8b, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeForwardRelocationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
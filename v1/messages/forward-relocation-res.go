@@ -0,0 +1,373 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// ForwardRelocationResponse is a ForwardRelocationResponse Header and its IEs above.
+type ForwardRelocationResponse struct {
+	*Header
+	Cause                          *ies.IE
+	TEIDCPlane                     *ies.IE
+	TEIDDataII                     *ies.IE
+	RANAPCause                     *ies.IE
+	SGSNAddressForCPlane           *ies.IE
+	SGSNAddressForUserTraffic      *ies.IE
+	UTRANTransparentContainer      *ies.IE
+	RABSetupInformations           []*ies.IE
+	AdditionalRABSetupInformations []*ies.IE
+	SGSNNumber                     *ies.IE
+	BSSContainer                   *ies.IE
+	BSSGPCause                     *ies.IE
+	ListOfSetupPFCs                *ies.IE
+	ExtendedRANAPCause             *ies.IE
+	NodeIdentifier                 *ies.IE
+	PrivateExtension               *ies.IE
+	AdditionalIEs                  []*ies.IE
+}
+
+// NewForwardRelocationResponse creates a new GTPv1 ForwardRelocationResponse.
+func NewForwardRelocationResponse(teid uint32, seq uint16, ie ...*ies.IE) *ForwardRelocationResponse {
+	f := &ForwardRelocationResponse{
+		Header: NewHeader(0x32, MsgTypeForwardRelocationResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			f.Cause = i
+		case ies.TEIDCPlane:
+			f.TEIDCPlane = i
+		case ies.TEIDDataII:
+			f.TEIDDataII = i
+		case ies.RANAPCause:
+			f.RANAPCause = i
+		case ies.GSNAddress:
+			if f.SGSNAddressForCPlane == nil {
+				f.SGSNAddressForCPlane = i
+			} else if f.SGSNAddressForUserTraffic == nil {
+				f.SGSNAddressForUserTraffic = i
+			}
+		case ies.UTRANTransparentContainer:
+			f.UTRANTransparentContainer = i
+		case ies.RABSetupInformation:
+			f.RABSetupInformations = append(f.RABSetupInformations, i)
+		case ies.AdditionalRABSetupInformation:
+			f.AdditionalRABSetupInformations = append(f.AdditionalRABSetupInformations, i)
+		case ies.SGSNNumber:
+			f.SGSNNumber = i
+		case ies.BSSContainer:
+			f.BSSContainer = i
+		case ies.BSSGPCause:
+			f.BSSGPCause = i
+		case ies.ListOfSetupPFCs:
+			f.ListOfSetupPFCs = i
+		case ies.ExtendedRANAPCause:
+			f.ExtendedRANAPCause = i
+		case ies.NodeIdentifier:
+			f.NodeIdentifier = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Serialize returns the byte sequence generated from a ForwardRelocationResponse.
+func (f *ForwardRelocationResponse) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (f *ForwardRelocationResponse) SerializeTo(b []byte) error {
+	if err := f.Header.preparePayload(b, f.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := f.Cause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.TEIDCPlane; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.TEIDDataII; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SGSNAddressForCPlane; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SGSNAddressForUserTraffic; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.RABSetupInformations {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range f.AdditionalRABSetupInformations {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.SGSNNumber; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ListOfSetupPFCs; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.ExtendedRANAPCause; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.NodeIdentifier; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	f.Header.SetLength()
+	return f.Header.SerializeTo(b)
+}
+
+// DecodeForwardRelocationResponse decodes a given byte sequence as a ForwardRelocationResponse.
+func DecodeForwardRelocationResponse(b []byte) (*ForwardRelocationResponse, error) {
+	f := &ForwardRelocationResponse{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a ForwardRelocationResponse.
+func (f *ForwardRelocationResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	f.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			f.Cause = i
+		case ies.TEIDCPlane:
+			f.TEIDCPlane = i
+		case ies.TEIDDataII:
+			f.TEIDDataII = i
+		case ies.RANAPCause:
+			f.RANAPCause = i
+		case ies.GSNAddress:
+			if f.SGSNAddressForCPlane == nil {
+				f.SGSNAddressForCPlane = i
+			} else if f.SGSNAddressForUserTraffic == nil {
+				f.SGSNAddressForUserTraffic = i
+			}
+		case ies.UTRANTransparentContainer:
+			f.UTRANTransparentContainer = i
+		case ies.RABSetupInformation:
+			f.RABSetupInformations = append(f.RABSetupInformations, i)
+		case ies.AdditionalRABSetupInformation:
+			f.AdditionalRABSetupInformations = append(f.AdditionalRABSetupInformations, i)
+		case ies.SGSNNumber:
+			f.SGSNNumber = i
+		case ies.BSSContainer:
+			f.BSSContainer = i
+		case ies.BSSGPCause:
+			f.BSSGPCause = i
+		case ies.ListOfSetupPFCs:
+			f.ListOfSetupPFCs = i
+		case ies.ExtendedRANAPCause:
+			f.ExtendedRANAPCause = i
+		case ies.NodeIdentifier:
+			f.NodeIdentifier = i
+		case ies.PrivateExtension:
+			f.PrivateExtension = i
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (f *ForwardRelocationResponse) Len() int {
+	l := f.Header.Len() - len(f.Header.Payload)
+
+	if ie := f.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.TEIDCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.TEIDDataII; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.SGSNAddressForCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.SGSNAddressForUserTraffic; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range f.RABSetupInformations {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	for _, ie := range f.AdditionalRABSetupInformations {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := f.SGSNNumber; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ListOfSetupPFCs; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.ExtendedRANAPCause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.NodeIdentifier; ie != nil {
+		l += ie.Len()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationResponse) SetLength() {
+	f.Length = uint16(f.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationResponse) MessageTypeName() string {
+	return "Forward Relocation Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (f *ForwardRelocationResponse) TEID() uint32 {
+	return f.Header.TEID
+}
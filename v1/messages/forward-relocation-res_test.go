@@ -0,0 +1,57 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestForwardRelocationResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewForwardRelocationResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDCPlane(0xdeadbeef),
+				ies.NewRANAPCause(v1.RANAPCauseRelocationTriggered),
+				ies.NewGSNAddress("1.1.1.1"),
+				ies.NewGSNAddress("1.1.1.2"),
+				ies.New(ies.UTRANTransparentContainer, []byte{0xde, 0xad, 0xbe, 0xef}),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x36, 0x00, 0x22, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// Cause
+				0x01, 0x80,
+				// TEID C-Plane
+				0x11, 0xde, 0xad, 0xbe, 0xef,
+				// RANAP Cause
+				0x15, 0x06,
+				// SGSN Address for Control Plane
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+				// SGSN Address for User Traffic
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x02,
+				// UTRAN Transparent Container
+				0x8b, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeForwardRelocationResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func FuzzDecode(f *testing.F) {
+	seeds := []messages.Message{
+		messages.NewEchoRequest(1, ies.NewRecovery(0x80)),
+		messages.NewCreatePDPContextRequest(
+			0, 1,
+			ies.NewIMSI("123451234567891"),
+			ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+			ies.NewSelectionMode(0),
+			ies.NewTEIDDataI(0xdeadbeef),
+			ies.NewTEIDCPlane(0xdeadbeef),
+			ies.NewNSAPI(5),
+			ies.NewEndUserAddressIPv4(""),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewProtocolConfigurationOptions(0, ies.NewConfigurationProtocolOption(0x000d, nil)),
+			ies.NewGSNAddress("1.1.1.1"),
+			ies.NewMSISDN("8130900000001"),
+			ies.NewQoSProfileFromPayload(ies.NewQoSProfilePayloadR99(0x02, 3, 1, 0, 0, 0, 0)),
+			ies.NewCommonFlags(0, 1, 0, 0, 0, 0, 0, 0),
+			ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222),
+			ies.NewMSTimeZone(0, 0),
+		),
+		messages.NewCreatePDPContextResponse(
+			0xdeadbeef, 1,
+			ies.NewCause(128),
+			ies.NewTEIDDataI(0xdeadbeef),
+			ies.NewTEIDCPlane(0xdeadbeef),
+			ies.NewEndUserAddressIPv4("2.2.2.2"),
+			ies.NewGSNAddress("1.1.1.2"),
+		),
+		messages.NewDeletePDPContextRequest(0xdeadbeef, 1, ies.NewTeardownInd(true), ies.NewNSAPI(5)),
+		messages.NewErrorIndication(0, 1, ies.NewTEIDDataI(0xdeadbeef), ies.NewGSNAddress("1.1.1.1")),
+		messages.NewTPDUWithExtensionHeaders(
+			0xdeadbeef, []byte{0xde, 0xad, 0xbe, 0xef},
+			messages.NewExtensionHeader(messages.ExtHeaderTypePDUSessionContainer, []byte{0x00, 0x05}),
+		),
+	}
+	for _, m := range seeds {
+		b, err := messages.Serialize(m)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		msg, err := messages.Decode(b)
+		if err != nil {
+			return
+		}
+		_ = msg.MessageTypeName()
+		_, _ = messages.Serialize(msg)
+	})
+}
@@ -17,7 +17,13 @@ type Header struct {
 	TEID           uint32
 	SequenceNumber uint16
 	Reserved       uint16
-	Payload        []byte
+	// NPDUNumber and NextExtensionHeaderType are meaningful only when the
+	// corresponding flags are set. NextExtensionHeaderType is overwritten with
+	// the type of the first ExtensionHeaders on serialization, if any.
+	NPDUNumber              uint8
+	NextExtensionHeaderType uint8
+	ExtensionHeaders        []*ExtensionHeader
+	Payload                 []byte
 }
 
 // NewHeader creates a new Header.
@@ -62,13 +68,29 @@ func (h *Header) SerializeTo(b []byte) error {
 	binary.BigEndian.PutUint16(b[2:4], h.Length)
 	binary.BigEndian.PutUint32(b[4:8], h.TEID)
 	offset := 8
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
 		binary.BigEndian.PutUint16(b[offset:offset+2], h.SequenceNumber)
-		// two bytes of padding before payload.
+		b[offset+2] = h.NPDUNumber
+		b[offset+3] = h.NextExtensionHeaderType
+		if len(h.ExtensionHeaders) != 0 {
+			b[offset+3] = h.ExtensionHeaders[0].Type
+		}
 		offset += 4
 	}
 
-	// two bytes of padding before payload.
+	if h.HasExtensionHeader() {
+		for i, e := range h.ExtensionHeaders {
+			var next uint8
+			if i+1 < len(h.ExtensionHeaders) {
+				next = h.ExtensionHeaders[i+1].Type
+			}
+			if err := e.serializeTo(b[offset:], next); err != nil {
+				return err
+			}
+			offset += e.Len()
+		}
+	}
+
 	copy(b[offset:], h.Payload)
 	return nil
 }
@@ -85,7 +107,7 @@ func DecodeHeader(b []byte) (*Header, error) {
 // DecodeFromBytes sets the values retrieved from byte sequence in GTPv1 header.
 func (h *Header) DecodeFromBytes(b []byte) error {
 	l := len(b)
-	if l < 11 {
+	if l < 8 {
 		return ErrTooShortToDecode
 	}
 	var offset = 4
@@ -95,12 +117,30 @@ func (h *Header) DecodeFromBytes(b []byte) error {
 
 	h.TEID = binary.BigEndian.Uint32(b[4:8])
 	offset += 4
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
+		if l < 12 {
+			return ErrTooShortToDecode
+		}
 		h.SequenceNumber = binary.BigEndian.Uint16(b[offset : offset+2])
-		// two bytes of padding before payload.
+		h.NPDUNumber = b[offset+2]
+		h.NextExtensionHeaderType = b[offset+3]
 		offset += 4
 	}
 
+	if h.HasExtensionHeader() {
+		h.ExtensionHeaders = nil
+		next := h.NextExtensionHeaderType
+		for next != ExtHeaderTypeNoMoreExtensionHeaders {
+			e, n, err := decodeExtensionHeader(b[offset:], next)
+			if err != nil {
+				return err
+			}
+			h.ExtensionHeaders = append(h.ExtensionHeaders, e)
+			next = b[offset+n-1]
+			offset += n
+		}
+	}
+
 	if int(h.Length)+8 != l {
 		h.Payload = b[offset:]
 		return nil
@@ -115,6 +155,22 @@ func (h *Header) SetTEID(teid uint32) {
 	h.TEID = teid
 }
 
+// HasExtensionHeader determines whether a GTP Header has Extension Headers by checking the flag.
+func (h *Header) HasExtensionHeader() bool {
+	return ((int(h.Flags) >> 2) & 0x1) == 1
+}
+
+// HasNPDUNumber determines whether a GTP Header has N-PDU Number by checking the flag.
+func (h *Header) HasNPDUNumber() bool {
+	return (int(h.Flags) & 0x1) == 1
+}
+
+// hasOptionalFields determines whether the Sequence Number, N-PDU Number and Next
+// Extension Header Type fields exist, which is when any of E, S or PN flag is set.
+func (h *Header) hasOptionalFields() bool {
+	return h.Flags&0x07 != 0
+}
+
 // HasSequence determines whether a GTP Header has TEID inside by checking the flag.
 func (h *Header) HasSequence() bool {
 	return ((int(h.Flags) >> 1) & 0x1) == 1
@@ -133,9 +189,14 @@ func (h *Header) SetSequenceNumber(seq uint16) {
 // Len returns the actual length of Header.
 func (h *Header) Len() int {
 	l := len(h.Payload) + 8
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
 		l += 4
 	}
+	if h.HasExtensionHeader() {
+		for _, e := range h.ExtensionHeaders {
+			l += e.Len()
+		}
+	}
 
 	return l
 }
@@ -145,6 +206,23 @@ func (h *Header) SetLength() {
 	h.Length = uint16(h.Len() - 8)
 }
 
+// preparePayload points the Payload to the part of b after the header, so that
+// the message of length l can put its IEs into b directly without allocating
+// the Payload. The Payload refers to b after the message is serialized.
+func (h *Header) preparePayload(b []byte, l int) error {
+	if len(b) < l {
+		return ErrTooShortToSerialize
+	}
+	h.Payload = b[h.Len()-len(h.Payload) : l]
+	return nil
+}
+
+// detachPayload makes the Payload a copy of its own, not to refer to the buffer
+// given to preparePayload.
+func (h *Header) detachPayload() {
+	h.Payload = append([]byte(nil), h.Payload...)
+}
+
 // Version returns GTP version in int.
 func (h *Header) Version() int {
 	return 1
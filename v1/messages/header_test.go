@@ -34,6 +34,25 @@ func TestHeader(t *testing.T) {
 				0x32, 0x10, 0x00, 0x08, 0xde, 0xad, 0xbe, 0xef,
 				0xca, 0xfe, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef,
 			},
+		}, {
+			Description: "With-Extension-Headers",
+			Structured: func() *messages.Header {
+				h := messages.NewHeader(
+					messages.NewHeaderFlags(1, 1, 0, 1, 0),
+					0xff, 0xdeadbeef, 0xcafe,
+					[]byte{0xde, 0xad, 0xbe, 0xef},
+				)
+				h.AddExtensionHeaders(
+					messages.NewExtensionHeader(messages.ExtHeaderTypeUDPPort, []byte{0x08, 0x68}),
+					messages.NewPDUSessionContainer(messages.PDUTypeDLPDUSessionInformation, 5),
+				)
+				return h
+			}(),
+			Serialized: []byte{
+				0x36, 0xff, 0x00, 0x10, 0xde, 0xad, 0xbe, 0xef,
+				0xca, 0xfe, 0x00, 0x40, 0x01, 0x08, 0x68, 0x85,
+				0x01, 0x00, 0x05, 0x00, 0xde, 0xad, 0xbe, 0xef,
+			},
 		},
 	}
 
@@ -0,0 +1,217 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// IdentificationRequest is a IdentificationRequest Header and its IEs above.
+type IdentificationRequest struct {
+	*Header
+	RAI                  *ies.IE
+	PTMSI                *ies.IE
+	PTMSISignature       *ies.IE
+	SGSNAddressForCPlane *ies.IE
+	HopCounter           *ies.IE
+	PrivateExtension     *ies.IE
+	AdditionalIEs        []*ies.IE
+}
+
+// NewIdentificationRequest creates a new GTPv1 IdentificationRequest.
+func NewIdentificationRequest(teid uint32, seq uint16, ie ...*ies.IE) *IdentificationRequest {
+	d := &IdentificationRequest{
+		Header: NewHeader(0x32, MsgTypeIdentificationRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.RouteingAreaIdentity:
+			d.RAI = i
+		case ies.PacketTMSI:
+			d.PTMSI = i
+		case ies.PTMSISignature:
+			d.PTMSISignature = i
+		case ies.GSNAddress:
+			d.SGSNAddressForCPlane = i
+		case ies.HopCounter:
+			d.HopCounter = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize returns the byte sequence generated from a IdentificationRequest.
+func (d *IdentificationRequest) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (d *IdentificationRequest) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.RAI; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PTMSI; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PTMSISignature; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.SGSNAddressForCPlane; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.HopCounter; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeIdentificationRequest decodes a given byte sequence as a IdentificationRequest.
+func DecodeIdentificationRequest(b []byte) (*IdentificationRequest, error) {
+	d := &IdentificationRequest{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a IdentificationRequest.
+func (d *IdentificationRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.RouteingAreaIdentity:
+			d.RAI = i
+		case ies.PacketTMSI:
+			d.PTMSI = i
+		case ies.PTMSISignature:
+			d.PTMSISignature = i
+		case ies.GSNAddress:
+			d.SGSNAddressForCPlane = i
+		case ies.HopCounter:
+			d.HopCounter = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (d *IdentificationRequest) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.RAI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PTMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PTMSISignature; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.SGSNAddressForCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.HopCounter; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *IdentificationRequest) SetLength() {
+	d.Length = uint16(d.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *IdentificationRequest) MessageTypeName() string {
+	return "Identification Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (d *IdentificationRequest) TEID() uint32 {
+	return d.Header.TEID
+}
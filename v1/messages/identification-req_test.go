@@ -0,0 +1,50 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestIdentificationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewIdentificationRequest(
+				0, testutils.TestBearerInfo.Seq,
+				ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+				ies.NewPacketTMSI(0xbeebee),
+				ies.NewPTMSISignature(0xbeebee),
+				ies.NewGSNAddress("1.1.1.1"),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x30, 0x00, 0x1b, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00,
+				// RAI
+				0x03, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22,
+				// P-TMSI
+				0x05, 0x00, 0xbe, 0xeb, 0xee,
+				// P-TMSI Signature
+				0x0c, 0xbe, 0xeb, 0xee,
+				// SGSN Address for Control Plane
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeIdentificationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// IdentificationResponse is a IdentificationResponse Header and its IEs above.
+type IdentificationResponse struct {
+	*Header
+	Cause                     *ies.IE
+	IMSI                      *ies.IE
+	AuthenticationTriplets    []*ies.IE
+	AuthenticationQuintuplets []*ies.IE
+	UEUsageType               *ies.IE
+	IOVUpdatesCounter         *ies.IE
+	PrivateExtension          *ies.IE
+	AdditionalIEs             []*ies.IE
+}
+
+// NewIdentificationResponse creates a new GTPv1 IdentificationResponse.
+func NewIdentificationResponse(teid uint32, seq uint16, ie ...*ies.IE) *IdentificationResponse {
+	d := &IdentificationResponse{
+		Header: NewHeader(0x32, MsgTypeIdentificationResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.AuthenticationTriplet:
+			d.AuthenticationTriplets = append(d.AuthenticationTriplets, i)
+		case ies.AuthenticationQuintuplet:
+			d.AuthenticationQuintuplets = append(d.AuthenticationQuintuplets, i)
+		case ies.UEUsageType:
+			d.UEUsageType = i
+		case ies.IOVUpdatesCounter:
+			d.IOVUpdatesCounter = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize returns the byte sequence generated from a IdentificationResponse.
+func (d *IdentificationResponse) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (d *IdentificationResponse) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.Cause; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range d.AuthenticationTriplets {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range d.AuthenticationQuintuplets {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.UEUsageType; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.IOVUpdatesCounter; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeIdentificationResponse decodes a given byte sequence as a IdentificationResponse.
+func DecodeIdentificationResponse(b []byte) (*IdentificationResponse, error) {
+	d := &IdentificationResponse{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a IdentificationResponse.
+func (d *IdentificationResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.AuthenticationTriplet:
+			d.AuthenticationTriplets = append(d.AuthenticationTriplets, i)
+		case ies.AuthenticationQuintuplet:
+			d.AuthenticationQuintuplets = append(d.AuthenticationQuintuplets, i)
+		case ies.UEUsageType:
+			d.UEUsageType = i
+		case ies.IOVUpdatesCounter:
+			d.IOVUpdatesCounter = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (d *IdentificationResponse) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range d.AuthenticationTriplets {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	for _, ie := range d.AuthenticationQuintuplets {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := d.UEUsageType; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.IOVUpdatesCounter; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *IdentificationResponse) SetLength() {
+	d.Length = uint16(d.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *IdentificationResponse) MessageTypeName() string {
+	return "Identification Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (d *IdentificationResponse) TEID() uint32 {
+	return d.Header.TEID
+}
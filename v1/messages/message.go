@@ -7,6 +7,8 @@ Package messages provides encoding/decoding feature of GTPv1 protocol.
 */
 package messages
 
+//go:generate go run ../../internal/genconst -file $GOFILE -type MessageType -block "Message Type definitions." -trim MsgType
+
 // Message Type definitions.
 const (
 	_ uint8 = iota
@@ -40,7 +42,7 @@ const (
 	MsgTypePDUNotificationResponse
 	MsgTypePDUNotificationRejectRequest
 	MsgTypePDUNotificationRejectResponse
-	_
+	MsgTypeSupportedExtensionHeadersNotification
 	MsgTypeSendRoutingInfoRequest
 	MsgTypeSendRoutingInfoResponse
 	MsgTypeFailureReportRequest
@@ -57,6 +59,19 @@ const (
 	_
 	_
 	_
+	MsgTypeIdentificationRequest // 48
+	MsgTypeIdentificationResponse
+	MsgTypeSGSNContextRequest
+	MsgTypeSGSNContextResponse
+	MsgTypeSGSNContextAcknowledge
+	MsgTypeForwardRelocationRequest
+	MsgTypeForwardRelocationResponse
+	MsgTypeForwardRelocationComplete
+	MsgTypeRelocationCancelRequest
+	MsgTypeRelocationCancelResponse
+	MsgTypeForwardSRNSContext
+	MsgTypeForwardRelocationCompleteAcknowledge
+	MsgTypeForwardSRNSContextAcknowledge
 	_
 	_
 	_
@@ -65,16 +80,22 @@ const (
 	_
 	_
 	_
-	MsgTypeIdentificationRequest // 48
-	MsgTypeIdentificationResponse
-	MsgTypeSGSNContextRequest
-	MsgTypeSGSNContextResponse
-	MsgTypeSGSNContextAcknowledge
-	MsgTypeDataRecordTransferRequest  uint8 = 240
-	MsgTypeDataRecordTransferResponse uint8 = 241
-	MsgTypeTPDU                       uint8 = 255
+	_
+	MsgTypeRANInformationRelay // 70
+	MsgTypeMSInfoChangeNotificationRequest  uint8 = 128
+	MsgTypeMSInfoChangeNotificationResponse uint8 = 129
+	MsgTypeDataRecordTransferRequest        uint8 = 240
+	MsgTypeDataRecordTransferResponse       uint8 = 241
+	MsgTypeEndMarker                        uint8 = 254
+	MsgTypeTPDU                             uint8 = 255
 )
 
+// MessageType is the type of the message type values, which gives the name with String().
+//
+// The MsgType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from Message into MessageType to get the name.
+type MessageType uint8
+
 // Message is an interface that defines Message messages.
 type Message interface {
 	SerializeTo([]byte) error
@@ -100,8 +121,21 @@ func Serialize(g Message) ([]byte, error) {
 	return b, nil
 }
 
+// DetachPayload makes the Payload of the message a copy of its own after SerializeTo,
+// which otherwise refers to the buffer given, so that the buffer can be reused for
+// other messages while the message is kept.
+func DetachPayload(m Message) {
+	if h, ok := m.(interface{ detachPayload() }); ok {
+		h.detachPayload()
+	}
+}
+
 // Decode decodes the given bytes as Message.
 func Decode(b []byte) (Message, error) {
+	if len(b) < 2 {
+		return nil, ErrTooShortToDecode
+	}
+
 	var m Message
 
 	switch b[1] {
@@ -143,15 +177,45 @@ func Decode(b []byte) (Message, error) {
 	*/
 	case MsgTypeErrorIndication:
 		m = &ErrorIndication{}
+	case MsgTypePDUNotificationRequest:
+		m = &PDUNotificationRequest{}
+	case MsgTypePDUNotificationResponse:
+		m = &PDUNotificationResponse{}
+	case MsgTypePDUNotificationRejectRequest:
+		m = &PDUNotificationRejectRequest{}
+	case MsgTypePDUNotificationRejectResponse:
+		m = &PDUNotificationRejectResponse{}
+	case MsgTypeSupportedExtensionHeadersNotification:
+		m = &SupportedExtensionHeadersNotification{}
+	case MsgTypeIdentificationRequest:
+		m = &IdentificationRequest{}
+	case MsgTypeIdentificationResponse:
+		m = &IdentificationResponse{}
+	case MsgTypeSGSNContextRequest:
+		m = &SGSNContextRequest{}
+	case MsgTypeSGSNContextResponse:
+		m = &SGSNContextResponse{}
+	case MsgTypeSGSNContextAcknowledge:
+		m = &SGSNContextAcknowledge{}
+	case MsgTypeForwardRelocationRequest:
+		m = &ForwardRelocationRequest{}
+	case MsgTypeForwardRelocationResponse:
+		m = &ForwardRelocationResponse{}
+	case MsgTypeForwardRelocationComplete:
+		m = &ForwardRelocationComplete{}
+	case MsgTypeRelocationCancelRequest:
+		m = &RelocationCancelRequest{}
+	case MsgTypeRelocationCancelResponse:
+		m = &RelocationCancelResponse{}
+	case MsgTypeForwardRelocationCompleteAcknowledge:
+		m = &ForwardRelocationCompleteAcknowledge{}
+	case MsgTypeRANInformationRelay:
+		m = &RANInformationRelay{}
+	case MsgTypeMSInfoChangeNotificationRequest:
+		m = &MSInfoChangeNotificationRequest{}
+	case MsgTypeMSInfoChangeNotificationResponse:
+		m = &MSInfoChangeNotificationResponse{}
 	/* XXX - Implement!
-	case MsgTypePduNotificationRequest:
-		m = &PduNotificationReq{}
-	case MsgTypePduNotificationResponse:
-		m = &PduNotificationRes{}
-	case MsgTypePduNotificationRejectRequest:
-		m = &PduNotificationRejectReq{}
-	case MsgTypePduNotificationRejectResponse:
-		m = &PduNotificationRejectRes{}
 	case MsgTypeSendRoutingInfoRequest:
 		m = &SendRoutingInfoReq{}
 	case MsgTypeSendRoutingInfoResponse:
@@ -164,21 +228,13 @@ func Decode(b []byte) (Message, error) {
 		m = &NoteMsPresentReq{}
 	case MsgTypeNoteMsPresentResponse:
 		m = &NoteMsPresentRes{}
-	case MsgTypeIdentificationRequest:
-		m = &IdentificationReq{}
-	case MsgTypeIdentificationResponse:
-		m = &IdentificationRes{}
-	case MsgTypeSgsnContextRequest:
-		m = &SgsnContextReq{}
-	case MsgTypeSgsnContextResponse:
-		m = &SgsnContextRes{}
-	case MsgTypeSgsnContextAcknowledge:
-		m = &SgsnContextAck{}
 	case MsgTypeDataRecordTransferRequest:
 		m = &DataRecordTransferReq{}
 	case MsgTypeDataRecordTransferResponse:
 		m = &DataRecordTransferRes{}
 	*/
+	case MsgTypeEndMarker:
+		m = &EndMarker{}
 	case MsgTypeTPDU:
 		m = &TPDU{}
 	default:
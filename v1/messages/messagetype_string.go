@@ -0,0 +1,153 @@
+// Code generated by "genconst -file message.go -type MessageType -block \"Message Type definitions.\" -trim MsgType"; DO NOT EDIT.
+
+package messages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var namesMessageType = map[MessageType]string{
+	MessageType(MsgTypeEchoRequest):                           "EchoRequest",
+	MessageType(MsgTypeEchoResponse):                          "EchoResponse",
+	MessageType(MsgTypeVersionNotSupported):                   "VersionNotSupported",
+	MessageType(MsgTypeNodeAliveRequest):                      "NodeAliveRequest",
+	MessageType(MsgTypeNodeAliveResponse):                     "NodeAliveResponse",
+	MessageType(MsgTypeRedirectionRequest):                    "RedirectionRequest",
+	MessageType(MsgTypeRedirectionResponse):                   "RedirectionResponse",
+	MessageType(MsgTypeCreatePDPContextRequest):               "CreatePDPContextRequest",
+	MessageType(MsgTypeCreatePDPContextResponse):              "CreatePDPContextResponse",
+	MessageType(MsgTypeUpdatePDPContextRequest):               "UpdatePDPContextRequest",
+	MessageType(MsgTypeUpdatePDPContextResponse):              "UpdatePDPContextResponse",
+	MessageType(MsgTypeDeletePDPContextRequest):               "DeletePDPContextRequest",
+	MessageType(MsgTypeDeletePDPContextResponse):              "DeletePDPContextResponse",
+	MessageType(MsgTypeCreateAAPDPContextRequest):             "CreateAAPDPContextRequest",
+	MessageType(MsgTypeCreateAAPDPContextResponse):            "CreateAAPDPContextResponse",
+	MessageType(MsgTypeDeleteAAPDPContextRequest):             "DeleteAAPDPContextRequest",
+	MessageType(MsgTypeDeleteAAPDPContextResponse):            "DeleteAAPDPContextResponse",
+	MessageType(MsgTypeErrorIndication):                       "ErrorIndication",
+	MessageType(MsgTypePDUNotificationRequest):                "PDUNotificationRequest",
+	MessageType(MsgTypePDUNotificationResponse):               "PDUNotificationResponse",
+	MessageType(MsgTypePDUNotificationRejectRequest):          "PDUNotificationRejectRequest",
+	MessageType(MsgTypePDUNotificationRejectResponse):         "PDUNotificationRejectResponse",
+	MessageType(MsgTypeSupportedExtensionHeadersNotification): "SupportedExtensionHeadersNotification",
+	MessageType(MsgTypeSendRoutingInfoRequest):                "SendRoutingInfoRequest",
+	MessageType(MsgTypeSendRoutingInfoResponse):               "SendRoutingInfoResponse",
+	MessageType(MsgTypeFailureReportRequest):                  "FailureReportRequest",
+	MessageType(MsgTypeFailureReportResponse):                 "FailureReportResponse",
+	MessageType(MsgTypeNoteMSPresentRequest):                  "NoteMSPresentRequest",
+	MessageType(MsgTypeNoteMSPresentResponse):                 "NoteMSPresentResponse",
+	MessageType(MsgTypeIdentificationRequest):                 "IdentificationRequest",
+	MessageType(MsgTypeIdentificationResponse):                "IdentificationResponse",
+	MessageType(MsgTypeSGSNContextRequest):                    "SGSNContextRequest",
+	MessageType(MsgTypeSGSNContextResponse):                   "SGSNContextResponse",
+	MessageType(MsgTypeSGSNContextAcknowledge):                "SGSNContextAcknowledge",
+	MessageType(MsgTypeForwardRelocationRequest):              "ForwardRelocationRequest",
+	MessageType(MsgTypeForwardRelocationResponse):             "ForwardRelocationResponse",
+	MessageType(MsgTypeForwardRelocationComplete):             "ForwardRelocationComplete",
+	MessageType(MsgTypeRelocationCancelRequest):               "RelocationCancelRequest",
+	MessageType(MsgTypeRelocationCancelResponse):              "RelocationCancelResponse",
+	MessageType(MsgTypeForwardSRNSContext):                    "ForwardSRNSContext",
+	MessageType(MsgTypeForwardRelocationCompleteAcknowledge):  "ForwardRelocationCompleteAcknowledge",
+	MessageType(MsgTypeForwardSRNSContextAcknowledge):         "ForwardSRNSContextAcknowledge",
+	MessageType(MsgTypeRANInformationRelay):                   "RANInformationRelay",
+	MessageType(MsgTypeMSInfoChangeNotificationRequest):       "MSInfoChangeNotificationRequest",
+	MessageType(MsgTypeMSInfoChangeNotificationResponse):      "MSInfoChangeNotificationResponse",
+	MessageType(MsgTypeDataRecordTransferRequest):             "DataRecordTransferRequest",
+	MessageType(MsgTypeDataRecordTransferResponse):            "DataRecordTransferResponse",
+	MessageType(MsgTypeEndMarker):                             "EndMarker",
+	MessageType(MsgTypeTPDU):                                  "TPDU",
+}
+
+var valuesMessageType = map[string]MessageType{
+	"CreateAAPDPContextRequest":             MessageType(MsgTypeCreateAAPDPContextRequest),
+	"CreateAAPDPContextResponse":            MessageType(MsgTypeCreateAAPDPContextResponse),
+	"CreatePDPContextRequest":               MessageType(MsgTypeCreatePDPContextRequest),
+	"CreatePDPContextResponse":              MessageType(MsgTypeCreatePDPContextResponse),
+	"DataRecordTransferRequest":             MessageType(MsgTypeDataRecordTransferRequest),
+	"DataRecordTransferResponse":            MessageType(MsgTypeDataRecordTransferResponse),
+	"DeleteAAPDPContextRequest":             MessageType(MsgTypeDeleteAAPDPContextRequest),
+	"DeleteAAPDPContextResponse":            MessageType(MsgTypeDeleteAAPDPContextResponse),
+	"DeletePDPContextRequest":               MessageType(MsgTypeDeletePDPContextRequest),
+	"DeletePDPContextResponse":              MessageType(MsgTypeDeletePDPContextResponse),
+	"EchoRequest":                           MessageType(MsgTypeEchoRequest),
+	"EchoResponse":                          MessageType(MsgTypeEchoResponse),
+	"EndMarker":                             MessageType(MsgTypeEndMarker),
+	"ErrorIndication":                       MessageType(MsgTypeErrorIndication),
+	"FailureReportRequest":                  MessageType(MsgTypeFailureReportRequest),
+	"FailureReportResponse":                 MessageType(MsgTypeFailureReportResponse),
+	"ForwardRelocationComplete":             MessageType(MsgTypeForwardRelocationComplete),
+	"ForwardRelocationCompleteAcknowledge":  MessageType(MsgTypeForwardRelocationCompleteAcknowledge),
+	"ForwardRelocationRequest":              MessageType(MsgTypeForwardRelocationRequest),
+	"ForwardRelocationResponse":             MessageType(MsgTypeForwardRelocationResponse),
+	"ForwardSRNSContext":                    MessageType(MsgTypeForwardSRNSContext),
+	"ForwardSRNSContextAcknowledge":         MessageType(MsgTypeForwardSRNSContextAcknowledge),
+	"IdentificationRequest":                 MessageType(MsgTypeIdentificationRequest),
+	"IdentificationResponse":                MessageType(MsgTypeIdentificationResponse),
+	"MSInfoChangeNotificationRequest":       MessageType(MsgTypeMSInfoChangeNotificationRequest),
+	"MSInfoChangeNotificationResponse":      MessageType(MsgTypeMSInfoChangeNotificationResponse),
+	"NodeAliveRequest":                      MessageType(MsgTypeNodeAliveRequest),
+	"NodeAliveResponse":                     MessageType(MsgTypeNodeAliveResponse),
+	"NoteMSPresentRequest":                  MessageType(MsgTypeNoteMSPresentRequest),
+	"NoteMSPresentResponse":                 MessageType(MsgTypeNoteMSPresentResponse),
+	"PDUNotificationRejectRequest":          MessageType(MsgTypePDUNotificationRejectRequest),
+	"PDUNotificationRejectResponse":         MessageType(MsgTypePDUNotificationRejectResponse),
+	"PDUNotificationRequest":                MessageType(MsgTypePDUNotificationRequest),
+	"PDUNotificationResponse":               MessageType(MsgTypePDUNotificationResponse),
+	"RANInformationRelay":                   MessageType(MsgTypeRANInformationRelay),
+	"RedirectionRequest":                    MessageType(MsgTypeRedirectionRequest),
+	"RedirectionResponse":                   MessageType(MsgTypeRedirectionResponse),
+	"RelocationCancelRequest":               MessageType(MsgTypeRelocationCancelRequest),
+	"RelocationCancelResponse":              MessageType(MsgTypeRelocationCancelResponse),
+	"SGSNContextAcknowledge":                MessageType(MsgTypeSGSNContextAcknowledge),
+	"SGSNContextRequest":                    MessageType(MsgTypeSGSNContextRequest),
+	"SGSNContextResponse":                   MessageType(MsgTypeSGSNContextResponse),
+	"SendRoutingInfoRequest":                MessageType(MsgTypeSendRoutingInfoRequest),
+	"SendRoutingInfoResponse":               MessageType(MsgTypeSendRoutingInfoResponse),
+	"SupportedExtensionHeadersNotification": MessageType(MsgTypeSupportedExtensionHeadersNotification),
+	"TPDU":                                  MessageType(MsgTypeTPDU),
+	"UpdatePDPContextRequest":               MessageType(MsgTypeUpdatePDPContextRequest),
+	"UpdatePDPContextResponse":              MessageType(MsgTypeUpdatePDPContextResponse),
+	"VersionNotSupported":                   MessageType(MsgTypeVersionNotSupported),
+}
+
+// String returns the name of the MessageType, or "MessageType(N)" if the value is unknown.
+func (v MessageType) String() string {
+	if name, ok := namesMessageType[v]; ok {
+		return name
+	}
+	return "MessageType(" + strconv.Itoa(int(v)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler to encode the MessageType with its name.
+func (v MessageType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler to decode the MessageType from its name.
+func (v *MessageType) UnmarshalText(b []byte) error {
+	parsed, err := MessageTypeFromString(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// MessageTypeFromString returns the MessageType whose name is s.
+//
+// s can also be in the "MessageType(N)" format that String() returns for the unknown values.
+func MessageTypeFromString(s string) (MessageType, error) {
+	if v, ok := valuesMessageType[s]; ok {
+		return v, nil
+	}
+
+	if strings.HasPrefix(s, "MessageType(") && strings.HasSuffix(s, ")") {
+		n, err := strconv.ParseUint(s[len("MessageType("):len(s)-1], 10, 8)
+		if err == nil {
+			return MessageType(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown MessageType: %s", s)
+}
@@ -0,0 +1,245 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// MSInfoChangeNotificationRequest is a MSInfoChangeNotificationRequest Header and its IEs above.
+type MSInfoChangeNotificationRequest struct {
+	*Header
+	IMSI                *ies.IE
+	LinkedNSAPI         *ies.IE
+	RATType             *ies.IE
+	ULI                 *ies.IE
+	IMEI                *ies.IE
+	ExtendedCommonFlags *ies.IE
+	UserCSGInformation  *ies.IE
+	PrivateExtension    *ies.IE
+	AdditionalIEs       []*ies.IE
+}
+
+// NewMSInfoChangeNotificationRequest creates a new GTPv1 MSInfoChangeNotificationRequest.
+func NewMSInfoChangeNotificationRequest(teid uint32, seq uint16, ie ...*ies.IE) *MSInfoChangeNotificationRequest {
+	m := &MSInfoChangeNotificationRequest{
+		Header: NewHeader(0x32, MsgTypeMSInfoChangeNotificationRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			m.IMSI = i
+		case ies.NSAPI:
+			m.LinkedNSAPI = i
+		case ies.RATType:
+			m.RATType = i
+		case ies.UserLocationInformation:
+			m.ULI = i
+		case ies.IMEISV:
+			m.IMEI = i
+		case ies.ExtendedCommonFlags:
+			m.ExtendedCommonFlags = i
+		case ies.UserCSGInformation:
+			m.UserCSGInformation = i
+		case ies.PrivateExtension:
+			m.PrivateExtension = i
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Serialize returns the byte sequence generated from a MSInfoChangeNotificationRequest.
+func (m *MSInfoChangeNotificationRequest) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (m *MSInfoChangeNotificationRequest) SerializeTo(b []byte) error {
+	if err := m.Header.preparePayload(b, m.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := m.IMSI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.LinkedNSAPI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.RATType; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.ULI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.IMEI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.ExtendedCommonFlags; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.UserCSGInformation; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	m.Header.SetLength()
+	return m.Header.SerializeTo(b)
+}
+
+// DecodeMSInfoChangeNotificationRequest decodes a given byte sequence as a MSInfoChangeNotificationRequest.
+func DecodeMSInfoChangeNotificationRequest(b []byte) (*MSInfoChangeNotificationRequest, error) {
+	m := &MSInfoChangeNotificationRequest{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a MSInfoChangeNotificationRequest.
+func (m *MSInfoChangeNotificationRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	m.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			m.IMSI = i
+		case ies.NSAPI:
+			m.LinkedNSAPI = i
+		case ies.RATType:
+			m.RATType = i
+		case ies.UserLocationInformation:
+			m.ULI = i
+		case ies.IMEISV:
+			m.IMEI = i
+		case ies.ExtendedCommonFlags:
+			m.ExtendedCommonFlags = i
+		case ies.UserCSGInformation:
+			m.UserCSGInformation = i
+		case ies.PrivateExtension:
+			m.PrivateExtension = i
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (m *MSInfoChangeNotificationRequest) Len() int {
+	l := m.Header.Len() - len(m.Header.Payload)
+
+	if ie := m.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.LinkedNSAPI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.RATType; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.ULI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.IMEI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.ExtendedCommonFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.UserCSGInformation; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MSInfoChangeNotificationRequest) SetLength() {
+	m.Length = uint16(m.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MSInfoChangeNotificationRequest) MessageTypeName() string {
+	return "MS Info Change Notification Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (m *MSInfoChangeNotificationRequest) TEID() uint32 {
+	return m.Header.TEID
+}
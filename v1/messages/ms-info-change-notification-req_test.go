@@ -0,0 +1,51 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestMSInfoChangeNotificationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewMSInfoChangeNotificationRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewNSAPI(5),
+				ies.NewRATType(v1.RatTypeUTRAN),
+				ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x80, 0x00, 0x1e, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// IMSI
+				0x02, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// Linked NSAPI
+				0x14, 0x05,
+				// RAT Type
+				0x97, 0x00, 0x01, 0x01,
+				// ULI
+				0x98, 0x00, 0x08, 0x01, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x22,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeMSInfoChangeNotificationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// MSInfoChangeNotificationResponse is a MSInfoChangeNotificationResponse Header and its IEs above.
+type MSInfoChangeNotificationResponse struct {
+	*Header
+	Cause                         *ies.IE
+	IMSI                          *ies.IE
+	LinkedNSAPI                   *ies.IE
+	IMEI                          *ies.IE
+	MSInfoChangeReportingAction   *ies.IE
+	CSGInformationReportingAction *ies.IE
+	PrivateExtension              *ies.IE
+	AdditionalIEs                 []*ies.IE
+}
+
+// NewMSInfoChangeNotificationResponse creates a new GTPv1 MSInfoChangeNotificationResponse.
+func NewMSInfoChangeNotificationResponse(teid uint32, seq uint16, ie ...*ies.IE) *MSInfoChangeNotificationResponse {
+	m := &MSInfoChangeNotificationResponse{
+		Header: NewHeader(0x32, MsgTypeMSInfoChangeNotificationResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.IMSI:
+			m.IMSI = i
+		case ies.NSAPI:
+			m.LinkedNSAPI = i
+		case ies.IMEISV:
+			m.IMEI = i
+		case ies.MSInfoChangeReportingAction:
+			m.MSInfoChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			m.CSGInformationReportingAction = i
+		case ies.PrivateExtension:
+			m.PrivateExtension = i
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Serialize returns the byte sequence generated from a MSInfoChangeNotificationResponse.
+func (m *MSInfoChangeNotificationResponse) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (m *MSInfoChangeNotificationResponse) SerializeTo(b []byte) error {
+	if err := m.Header.preparePayload(b, m.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := m.Cause; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.IMSI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.LinkedNSAPI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.IMEI; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.MSInfoChangeReportingAction; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.CSGInformationReportingAction; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	m.Header.SetLength()
+	return m.Header.SerializeTo(b)
+}
+
+// DecodeMSInfoChangeNotificationResponse decodes a given byte sequence as a MSInfoChangeNotificationResponse.
+func DecodeMSInfoChangeNotificationResponse(b []byte) (*MSInfoChangeNotificationResponse, error) {
+	m := &MSInfoChangeNotificationResponse{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a MSInfoChangeNotificationResponse.
+func (m *MSInfoChangeNotificationResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	m.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.IMSI:
+			m.IMSI = i
+		case ies.NSAPI:
+			m.LinkedNSAPI = i
+		case ies.IMEISV:
+			m.IMEI = i
+		case ies.MSInfoChangeReportingAction:
+			m.MSInfoChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			m.CSGInformationReportingAction = i
+		case ies.PrivateExtension:
+			m.PrivateExtension = i
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (m *MSInfoChangeNotificationResponse) Len() int {
+	l := m.Header.Len() - len(m.Header.Payload)
+
+	if ie := m.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.LinkedNSAPI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.IMEI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.MSInfoChangeReportingAction; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.CSGInformationReportingAction; ie != nil {
+		l += ie.Len()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MSInfoChangeNotificationResponse) SetLength() {
+	m.Length = uint16(m.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MSInfoChangeNotificationResponse) MessageTypeName() string {
+	return "MS Info Change Notification Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (m *MSInfoChangeNotificationResponse) TEID() uint32 {
+	return m.Header.TEID
+}
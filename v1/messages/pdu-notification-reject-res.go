@@ -0,0 +1,161 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// PDUNotificationRejectResponse is a PDUNotificationRejectResponse Header and its IEs above.
+type PDUNotificationRejectResponse struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewPDUNotificationRejectResponse creates a new GTPv1 PDUNotificationRejectResponse.
+func NewPDUNotificationRejectResponse(teid uint32, seq uint16, ie ...*ies.IE) *PDUNotificationRejectResponse {
+	p := &PDUNotificationRejectResponse{
+		Header: NewHeader(0x32, MsgTypePDUNotificationRejectResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Serialize returns the byte sequence generated from a PDUNotificationRejectResponse.
+func (p *PDUNotificationRejectResponse) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (p *PDUNotificationRejectResponse) SerializeTo(b []byte) error {
+	if err := p.Header.preparePayload(b, p.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := p.Cause; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	p.Header.SetLength()
+	return p.Header.SerializeTo(b)
+}
+
+// DecodePDUNotificationRejectResponse decodes a given byte sequence as a PDUNotificationRejectResponse.
+func DecodePDUNotificationRejectResponse(b []byte) (*PDUNotificationRejectResponse, error) {
+	p := &PDUNotificationRejectResponse{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a PDUNotificationRejectResponse.
+func (p *PDUNotificationRejectResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	p.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (p *PDUNotificationRejectResponse) Len() int {
+	l := p.Header.Len() - len(p.Header.Payload)
+
+	if ie := p.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PDUNotificationRejectResponse) SetLength() {
+	p.Length = uint16(p.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PDUNotificationRejectResponse) MessageTypeName() string {
+	return "PDU Notification Reject Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (p *PDUNotificationRejectResponse) TEID() uint32 {
+	return p.Header.TEID
+}
@@ -0,0 +1,231 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// PDUNotificationRequest is a PDUNotificationRequest Header and its IEs above.
+type PDUNotificationRequest struct {
+	*Header
+	IMSI                 *ies.IE
+	TEIDCPlane           *ies.IE
+	EndUserAddress       *ies.IE
+	APN                  *ies.IE
+	PCO                  *ies.IE
+	GGSNAddressForCPlane *ies.IE
+	PrivateExtension     *ies.IE
+	AdditionalIEs        []*ies.IE
+}
+
+// NewPDUNotificationRequest creates a new GTPv1 PDUNotificationRequest.
+func NewPDUNotificationRequest(teid uint32, seq uint16, ie ...*ies.IE) *PDUNotificationRequest {
+	p := &PDUNotificationRequest{
+		Header: NewHeader(0x32, MsgTypePDUNotificationRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			p.IMSI = i
+		case ies.TEIDCPlane:
+			p.TEIDCPlane = i
+		case ies.EndUserAddress:
+			p.EndUserAddress = i
+		case ies.AccessPointName:
+			p.APN = i
+		case ies.ProtocolConfigurationOptions:
+			p.PCO = i
+		case ies.GSNAddress:
+			p.GGSNAddressForCPlane = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Serialize returns the byte sequence generated from a PDUNotificationRequest.
+func (p *PDUNotificationRequest) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (p *PDUNotificationRequest) SerializeTo(b []byte) error {
+	if err := p.Header.preparePayload(b, p.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := p.IMSI; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.TEIDCPlane; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.EndUserAddress; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.APN; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.PCO; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.GGSNAddressForCPlane; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	p.Header.SetLength()
+	return p.Header.SerializeTo(b)
+}
+
+// DecodePDUNotificationRequest decodes a given byte sequence as a PDUNotificationRequest.
+func DecodePDUNotificationRequest(b []byte) (*PDUNotificationRequest, error) {
+	p := &PDUNotificationRequest{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a PDUNotificationRequest.
+func (p *PDUNotificationRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	p.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			p.IMSI = i
+		case ies.TEIDCPlane:
+			p.TEIDCPlane = i
+		case ies.EndUserAddress:
+			p.EndUserAddress = i
+		case ies.AccessPointName:
+			p.APN = i
+		case ies.ProtocolConfigurationOptions:
+			p.PCO = i
+		case ies.GSNAddress:
+			p.GGSNAddressForCPlane = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (p *PDUNotificationRequest) Len() int {
+	l := p.Header.Len() - len(p.Header.Payload)
+
+	if ie := p.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.TEIDCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.EndUserAddress; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.APN; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.PCO; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.GGSNAddressForCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PDUNotificationRequest) SetLength() {
+	p.Length = uint16(p.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PDUNotificationRequest) MessageTypeName() string {
+	return "PDU Notification Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (p *PDUNotificationRequest) TEID() uint32 {
+	return p.Header.TEID
+}
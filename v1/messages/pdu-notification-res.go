@@ -0,0 +1,161 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// PDUNotificationResponse is a PDUNotificationResponse Header and its IEs above.
+type PDUNotificationResponse struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewPDUNotificationResponse creates a new GTPv1 PDUNotificationResponse.
+func NewPDUNotificationResponse(teid uint32, seq uint16, ie ...*ies.IE) *PDUNotificationResponse {
+	p := &PDUNotificationResponse{
+		Header: NewHeader(0x32, MsgTypePDUNotificationResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Serialize returns the byte sequence generated from a PDUNotificationResponse.
+func (p *PDUNotificationResponse) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (p *PDUNotificationResponse) SerializeTo(b []byte) error {
+	if err := p.Header.preparePayload(b, p.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := p.Cause; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	p.Header.SetLength()
+	return p.Header.SerializeTo(b)
+}
+
+// DecodePDUNotificationResponse decodes a given byte sequence as a PDUNotificationResponse.
+func DecodePDUNotificationResponse(b []byte) (*PDUNotificationResponse, error) {
+	p := &PDUNotificationResponse{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a PDUNotificationResponse.
+func (p *PDUNotificationResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	p.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (p *PDUNotificationResponse) Len() int {
+	l := p.Header.Len() - len(p.Header.Payload)
+
+	if ie := p.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PDUNotificationResponse) SetLength() {
+	p.Length = uint16(p.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PDUNotificationResponse) MessageTypeName() string {
+	return "PDU Notification Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (p *PDUNotificationResponse) TEID() uint32 {
+	return p.Header.TEID
+}
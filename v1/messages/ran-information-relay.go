@@ -0,0 +1,189 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// RANInformationRelay is a RANInformationRelay Header and its IEs above.
+type RANInformationRelay struct {
+	*Header
+	RANTransparentContainer        *ies.IE
+	RIMRoutingAddress              *ies.IE
+	RIMRoutingAddressDiscriminator *ies.IE
+	PrivateExtension               *ies.IE
+	AdditionalIEs                  []*ies.IE
+}
+
+// NewRANInformationRelay creates a new GTPv1 RANInformationRelay.
+func NewRANInformationRelay(teid uint32, seq uint16, ie ...*ies.IE) *RANInformationRelay {
+	r := &RANInformationRelay{
+		Header: NewHeader(0x32, MsgTypeRANInformationRelay, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.RANTransparentContainer:
+			r.RANTransparentContainer = i
+		case ies.RIMRoutingAddress:
+			r.RIMRoutingAddress = i
+		case ies.RIMRoutingAddressDiscriminator:
+			r.RIMRoutingAddressDiscriminator = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Serialize returns the byte sequence generated from a RANInformationRelay.
+func (r *RANInformationRelay) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (r *RANInformationRelay) SerializeTo(b []byte) error {
+	if err := r.Header.preparePayload(b, r.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := r.RANTransparentContainer; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.RIMRoutingAddress; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.RIMRoutingAddressDiscriminator; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	r.Header.SetLength()
+	return r.Header.SerializeTo(b)
+}
+
+// DecodeRANInformationRelay decodes a given byte sequence as a RANInformationRelay.
+func DecodeRANInformationRelay(b []byte) (*RANInformationRelay, error) {
+	r := &RANInformationRelay{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a RANInformationRelay.
+func (r *RANInformationRelay) DecodeFromBytes(b []byte) error {
+	var err error
+	r.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.RANTransparentContainer:
+			r.RANTransparentContainer = i
+		case ies.RIMRoutingAddress:
+			r.RIMRoutingAddress = i
+		case ies.RIMRoutingAddressDiscriminator:
+			r.RIMRoutingAddressDiscriminator = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (r *RANInformationRelay) Len() int {
+	l := r.Header.Len() - len(r.Header.Payload)
+
+	if ie := r.RANTransparentContainer; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.RIMRoutingAddress; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.RIMRoutingAddressDiscriminator; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *RANInformationRelay) SetLength() {
+	r.Length = uint16(r.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *RANInformationRelay) MessageTypeName() string {
+	return "RAN Information Relay"
+}
+
+// TEID returns the TEID in human-readable string.
+func (r *RANInformationRelay) TEID() uint32 {
+	return r.Header.TEID
+}
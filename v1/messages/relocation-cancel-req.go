@@ -0,0 +1,203 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// RelocationCancelRequest is a RelocationCancelRequest Header and its IEs above.
+type RelocationCancelRequest struct {
+	*Header
+	IMSI                *ies.IE
+	IMEI                *ies.IE
+	ExtendedCommonFlags *ies.IE
+	ExtendedRANAPCause  *ies.IE
+	PrivateExtension    *ies.IE
+	AdditionalIEs       []*ies.IE
+}
+
+// NewRelocationCancelRequest creates a new GTPv1 RelocationCancelRequest.
+func NewRelocationCancelRequest(teid uint32, seq uint16, ie ...*ies.IE) *RelocationCancelRequest {
+	r := &RelocationCancelRequest{
+		Header: NewHeader(0x32, MsgTypeRelocationCancelRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.IMEISV:
+			r.IMEI = i
+		case ies.ExtendedCommonFlags:
+			r.ExtendedCommonFlags = i
+		case ies.ExtendedRANAPCause:
+			r.ExtendedRANAPCause = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Serialize returns the byte sequence generated from a RelocationCancelRequest.
+func (r *RelocationCancelRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (r *RelocationCancelRequest) SerializeTo(b []byte) error {
+	if err := r.Header.preparePayload(b, r.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := r.IMSI; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.IMEI; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.ExtendedCommonFlags; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.ExtendedRANAPCause; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	r.Header.SetLength()
+	return r.Header.SerializeTo(b)
+}
+
+// DecodeRelocationCancelRequest decodes a given byte sequence as a RelocationCancelRequest.
+func DecodeRelocationCancelRequest(b []byte) (*RelocationCancelRequest, error) {
+	r := &RelocationCancelRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a RelocationCancelRequest.
+func (r *RelocationCancelRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	r.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.IMEISV:
+			r.IMEI = i
+		case ies.ExtendedCommonFlags:
+			r.ExtendedCommonFlags = i
+		case ies.ExtendedRANAPCause:
+			r.ExtendedRANAPCause = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (r *RelocationCancelRequest) Len() int {
+	l := r.Header.Len() - len(r.Header.Payload)
+
+	if ie := r.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.IMEI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.ExtendedCommonFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.ExtendedRANAPCause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *RelocationCancelRequest) SetLength() {
+	r.Length = uint16(r.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *RelocationCancelRequest) MessageTypeName() string {
+	return "Relocation Cancel Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (r *RelocationCancelRequest) TEID() uint32 {
+	return r.Header.TEID
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// RelocationCancelResponse is a RelocationCancelResponse Header and its IEs above.
+type RelocationCancelResponse struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewRelocationCancelResponse creates a new GTPv1 RelocationCancelResponse.
+func NewRelocationCancelResponse(teid uint32, seq uint16, ie ...*ies.IE) *RelocationCancelResponse {
+	r := &RelocationCancelResponse{
+		Header: NewHeader(0x32, MsgTypeRelocationCancelResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Serialize returns the byte sequence generated from a RelocationCancelResponse.
+func (r *RelocationCancelResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (r *RelocationCancelResponse) SerializeTo(b []byte) error {
+	if err := r.Header.preparePayload(b, r.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := r.Cause; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	r.Header.SetLength()
+	return r.Header.SerializeTo(b)
+}
+
+// DecodeRelocationCancelResponse decodes a given byte sequence as a RelocationCancelResponse.
+func DecodeRelocationCancelResponse(b []byte) (*RelocationCancelResponse, error) {
+	r := &RelocationCancelResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a RelocationCancelResponse.
+func (r *RelocationCancelResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	r.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.PrivateExtension:
+			r.PrivateExtension = i
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (r *RelocationCancelResponse) Len() int {
+	l := r.Header.Len() - len(r.Header.Payload)
+
+	if ie := r.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *RelocationCancelResponse) SetLength() {
+	r.Length = uint16(r.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *RelocationCancelResponse) MessageTypeName() string {
+	return "Relocation Cancel Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (r *RelocationCancelResponse) TEID() uint32 {
+	return r.Header.TEID
+}
@@ -0,0 +1,555 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// SGSNContextResponse is a SGSNContextResponse Header and its IEs above.
+type SGSNContextResponse struct {
+	*Header
+	Cause                                  *ies.IE
+	IMSI                                   *ies.IE
+	TEIDCPlane                             *ies.IE
+	RABContexts                            []*ies.IE
+	RadioPrioritySMS                       *ies.IE
+	RadioPriorities                        []*ies.IE
+	PacketFlowIDs                          []*ies.IE
+	ChargingCharacteristics                *ies.IE
+	RadioPriorityLCS                       *ies.IE
+	MMContext                              *ies.IE
+	PDPContexts                            []*ies.IE
+	SGSNAddressForCPlane                   *ies.IE
+	PDPContextPrioritization               *ies.IE
+	MBMSUEContexts                         []*ies.IE
+	SubscribedRFSPIndex                    *ies.IE
+	RFSPIndexInUse                         *ies.IE
+	ColocatedGGSNPGWFQDN                   *ies.IE
+	EvolvedARPIIs                          []*ies.IE
+	ExtendedCommonFlags                    *ies.IE
+	UENetworkCapability                    *ies.IE
+	UEAMBR                                 *ies.IE
+	APNAMBRWithNSAPIs                      []*ies.IE
+	SignallingPriorityIndicationWithNSAPIs []*ies.IE
+	HigherBitratesThan16MbpsFlag           *ies.IE
+	SelectionModeWithNSAPIs                []*ies.IE
+	PrivateExtension                       *ies.IE
+	AdditionalIEs                          []*ies.IE
+}
+
+// NewSGSNContextResponse creates a new GTPv1 SGSNContextResponse.
+func NewSGSNContextResponse(teid uint32, seq uint16, ie ...*ies.IE) *SGSNContextResponse {
+	s := &SGSNContextResponse{
+		Header: NewHeader(0x32, MsgTypeSGSNContextResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.RABContext:
+			s.RABContexts = append(s.RABContexts, i)
+		case ies.RadioPrioritySMS:
+			s.RadioPrioritySMS = i
+		case ies.RadioPriority:
+			s.RadioPriorities = append(s.RadioPriorities, i)
+		case ies.PacketFlowID:
+			s.PacketFlowIDs = append(s.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			s.ChargingCharacteristics = i
+		case ies.RadioPriorityLCS:
+			s.RadioPriorityLCS = i
+		case ies.MMContext:
+			s.MMContext = i
+		case ies.PDPContext:
+			s.PDPContexts = append(s.PDPContexts, i)
+		case ies.GSNAddress:
+			s.SGSNAddressForCPlane = i
+		case ies.PDPContextPrioritization:
+			s.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			s.MBMSUEContexts = append(s.MBMSUEContexts, i)
+		case ies.RFSPIndex:
+			if s.SubscribedRFSPIndex == nil {
+				s.SubscribedRFSPIndex = i
+			} else if s.RFSPIndexInUse == nil {
+				s.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			s.ColocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			s.EvolvedARPIIs = append(s.EvolvedARPIIs, i)
+		case ies.ExtendedCommonFlags:
+			s.ExtendedCommonFlags = i
+		case ies.UENetworkCapability:
+			s.UENetworkCapability = i
+		case ies.UEAMBR:
+			s.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			s.APNAMBRWithNSAPIs = append(s.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			s.SignallingPriorityIndicationWithNSAPIs = append(s.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			s.HigherBitratesThan16MbpsFlag = i
+		case ies.SelectionModeWithNSAPI:
+			s.SelectionModeWithNSAPIs = append(s.SelectionModeWithNSAPIs, i)
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Serialize returns the byte sequence generated from a SGSNContextResponse.
+func (s *SGSNContextResponse) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (s *SGSNContextResponse) SerializeTo(b []byte) error {
+	if err := s.Header.preparePayload(b, s.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := s.Cause; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.IMSI; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.RABContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.RadioPrioritySMS; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.RadioPriorities {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.ChargingCharacteristics; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.RadioPriorityLCS; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.MMContext; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.PDPContextPrioritization; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.SubscribedRFSPIndex; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.RFSPIndexInUse; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.ColocatedGGSNPGWFQDN; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.EvolvedARPIIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.ExtendedCommonFlags; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.UENetworkCapability; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.UEAMBR; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.HigherBitratesThan16MbpsFlag; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range s.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	s.Header.SetLength()
+	return s.Header.SerializeTo(b)
+}
+
+// DecodeSGSNContextResponse decodes a given byte sequence as a SGSNContextResponse.
+func DecodeSGSNContextResponse(b []byte) (*SGSNContextResponse, error) {
+	s := &SGSNContextResponse{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a SGSNContextResponse.
+func (s *SGSNContextResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	s.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.RABContext:
+			s.RABContexts = append(s.RABContexts, i)
+		case ies.RadioPrioritySMS:
+			s.RadioPrioritySMS = i
+		case ies.RadioPriority:
+			s.RadioPriorities = append(s.RadioPriorities, i)
+		case ies.PacketFlowID:
+			s.PacketFlowIDs = append(s.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			s.ChargingCharacteristics = i
+		case ies.RadioPriorityLCS:
+			s.RadioPriorityLCS = i
+		case ies.MMContext:
+			s.MMContext = i
+		case ies.PDPContext:
+			s.PDPContexts = append(s.PDPContexts, i)
+		case ies.GSNAddress:
+			s.SGSNAddressForCPlane = i
+		case ies.PDPContextPrioritization:
+			s.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			s.MBMSUEContexts = append(s.MBMSUEContexts, i)
+		case ies.RFSPIndex:
+			if s.SubscribedRFSPIndex == nil {
+				s.SubscribedRFSPIndex = i
+			} else if s.RFSPIndexInUse == nil {
+				s.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			s.ColocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			s.EvolvedARPIIs = append(s.EvolvedARPIIs, i)
+		case ies.ExtendedCommonFlags:
+			s.ExtendedCommonFlags = i
+		case ies.UENetworkCapability:
+			s.UENetworkCapability = i
+		case ies.UEAMBR:
+			s.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			s.APNAMBRWithNSAPIs = append(s.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			s.SignallingPriorityIndicationWithNSAPIs = append(s.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			s.HigherBitratesThan16MbpsFlag = i
+		case ies.SelectionModeWithNSAPI:
+			s.SelectionModeWithNSAPIs = append(s.SelectionModeWithNSAPIs, i)
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (s *SGSNContextResponse) Len() int {
+	l := s.Header.Len() - len(s.Header.Payload)
+
+	if ie := s.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.RABContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.RadioPrioritySMS; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.RadioPriorities {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	for _, ie := range s.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.ChargingCharacteristics; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.RadioPriorityLCS; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.MMContext; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.PDPContextPrioritization; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.SubscribedRFSPIndex; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.RFSPIndexInUse; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.ColocatedGGSNPGWFQDN; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.EvolvedARPIIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.ExtendedCommonFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.UENetworkCapability; ie != nil {
+		l += ie.Len()
+	}
+	if ie := s.UEAMBR; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	for _, ie := range s.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.HigherBitratesThan16MbpsFlag; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range s.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SGSNContextResponse) SetLength() {
+	s.Length = uint16(s.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SGSNContextResponse) MessageTypeName() string {
+	return "SGSN Context Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (s *SGSNContextResponse) TEID() uint32 {
+	return s.Header.TEID
+}
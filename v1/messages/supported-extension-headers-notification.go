@@ -0,0 +1,147 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// SupportedExtensionHeadersNotification is a SupportedExtensionHeadersNotification Header and its IEs above.
+type SupportedExtensionHeadersNotification struct {
+	*Header
+	ExtensionHeaderTypeList *ies.IE
+	AdditionalIEs           []*ies.IE
+}
+
+// NewSupportedExtensionHeadersNotification creates a new GTPv1 SupportedExtensionHeadersNotification.
+func NewSupportedExtensionHeadersNotification(teid uint32, seq uint16, ie ...*ies.IE) *SupportedExtensionHeadersNotification {
+	s := &SupportedExtensionHeadersNotification{
+		Header: NewHeader(0x32, MsgTypeSupportedExtensionHeadersNotification, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.ExtensionHeaderTypeList:
+			s.ExtensionHeaderTypeList = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Serialize returns the byte sequence generated from a SupportedExtensionHeadersNotification.
+func (s *SupportedExtensionHeadersNotification) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo puts the byte sequence in the byte array given as b.
+func (s *SupportedExtensionHeadersNotification) SerializeTo(b []byte) error {
+	if err := s.Header.preparePayload(b, s.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := s.ExtensionHeaderTypeList; ie != nil {
+		if err := ie.SerializeTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	s.Header.SetLength()
+	return s.Header.SerializeTo(b)
+}
+
+// DecodeSupportedExtensionHeadersNotification decodes a given byte sequence as a SupportedExtensionHeadersNotification.
+func DecodeSupportedExtensionHeadersNotification(b []byte) (*SupportedExtensionHeadersNotification, error) {
+	s := &SupportedExtensionHeadersNotification{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// DecodeFromBytes decodes a given byte sequence as a SupportedExtensionHeadersNotification.
+func (s *SupportedExtensionHeadersNotification) DecodeFromBytes(b []byte) error {
+	var err error
+	s.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.DecodeMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.ExtensionHeaderTypeList:
+			s.ExtensionHeaderTypeList = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// Len returns the actual length of Data.
+func (s *SupportedExtensionHeadersNotification) Len() int {
+	l := s.Header.Len() - len(s.Header.Payload)
+
+	if ie := s.ExtensionHeaderTypeList; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SupportedExtensionHeadersNotification) SetLength() {
+	s.Length = uint16(s.Len() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SupportedExtensionHeadersNotification) MessageTypeName() string {
+	return "Supported Extension Headers Notification"
+}
+
+// TEID returns the TEID in human-readable string.
+func (s *SupportedExtensionHeadersNotification) TEID() uint32 {
+	return s.Header.TEID
+}
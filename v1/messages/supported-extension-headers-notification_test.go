@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestSupportedExtensionHeadersNotification(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewSupportedExtensionHeadersNotification(
+				0, testutils.TestBearerInfo.Seq,
+				ies.NewExtensionHeaderTypeList(
+					messages.ExtHeaderTypeUDPPort,
+					messages.ExtHeaderTypePDUSessionContainer,
+				),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x1f, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00,
+				// Extension Header Type List
+				0x8d, 0x02, 0x40, 0x85,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeSupportedExtensionHeadersNotification(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
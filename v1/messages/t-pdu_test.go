@@ -27,6 +27,17 @@ func TestTPDU(t *testing.T) {
 				0x32, 0xff, 0x00, 0x08, 0xde, 0xad, 0xbe, 0xef,
 				0x00, 0x01, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef,
 			},
+		}, {
+			Description: "With-PDU-Session-Container",
+			Structured: messages.NewTPDUWithExtensionHeaders(
+				0xdeadbeef, []byte{0xde, 0xad, 0xbe, 0xef},
+				messages.NewPDUSessionContainer(messages.PDUTypeULPDUSessionInformation, 9),
+			),
+			Serialized: []byte{
+				0x34, 0xff, 0x00, 0x0c, 0xde, 0xad, 0xbe, 0xef,
+				0x00, 0x00, 0x00, 0x85, 0x01, 0x10, 0x09, 0x00,
+				0xde, 0xad, 0xbe, 0xef,
+			},
 		},
 	}
 
@@ -9,6 +9,9 @@ import (
 )
 
 // UpdatePDPContextRequest is a UpdatePDPContextRequest Header and its IEs above.
+//
+// This has the IEs for both SGSN-initiated and GGSN-initiated procedures.
+// The GSN Addresses in the message sent by GGSN are not defined, as it has none.
 type UpdatePDPContextRequest struct {
 	*Header
 	IMSI                                 *ies.IE
@@ -19,6 +22,7 @@ type UpdatePDPContextRequest struct {
 	NSAPI                                *ies.IE
 	TraceReference                       *ies.IE
 	TraceType                            *ies.IE
+	EndUserAddress                       *ies.IE
 	PCO                                  *ies.IE
 	SGSNAddressForCPlane                 *ies.IE
 	SGSNAddressForUserTraffic            *ies.IE
@@ -29,14 +33,18 @@ type UpdatePDPContextRequest struct {
 	TriggerID                            *ies.IE
 	OMCIdentity                          *ies.IE
 	CommonFlags                          *ies.IE
+	APNRestriction                       *ies.IE
 	RATType                              *ies.IE
 	ULI                                  *ies.IE
 	MSTimeZone                           *ies.IE
 	AdditionalTraceInfo                  *ies.IE
+	MSInfoChangeReportingAction          *ies.IE
 	DirectTunnelFlags                    *ies.IE
+	BearerControlMode                    *ies.IE
 	EvolvedARPI                          *ies.IE
 	ExtendedCommonFlags                  *ies.IE
 	UCI                                  *ies.IE
+	CSGInformationReportingAction        *ies.IE
 	APNAMBR                              *ies.IE
 	SignallingPriorityIndication         *ies.IE
 	CNOperatorSelectionEntity            *ies.IE
@@ -72,6 +80,8 @@ func NewUpdatePDPContextRequest(teid uint32, seq uint16, ie ...*ies.IE) *UpdateP
 			u.TraceReference = i
 		case ies.TraceType:
 			u.TraceType = i
+		case ies.EndUserAddress:
+			u.EndUserAddress = i
 		case ies.ProtocolConfigurationOptions:
 			u.PCO = i
 		case ies.GSNAddress:
@@ -94,6 +104,8 @@ func NewUpdatePDPContextRequest(teid uint32, seq uint16, ie ...*ies.IE) *UpdateP
 			u.OMCIdentity = i
 		case ies.CommonFlags:
 			u.CommonFlags = i
+		case ies.APNRestriction:
+			u.APNRestriction = i
 		case ies.RATType:
 			u.RATType = i
 		case ies.UserLocationInformation:
@@ -102,14 +114,20 @@ func NewUpdatePDPContextRequest(teid uint32, seq uint16, ie ...*ies.IE) *UpdateP
 			u.MSTimeZone = i
 		case ies.AdditionalTraceInfo:
 			u.AdditionalTraceInfo = i
+		case ies.MSInfoChangeReportingAction:
+			u.MSInfoChangeReportingAction = i
 		case ies.DirectTunnelFlags:
 			u.DirectTunnelFlags = i
+		case ies.BearerControlMode:
+			u.BearerControlMode = i
 		case ies.EvolvedAllocationRetentionPriorityI:
 			u.EvolvedARPI = i
 		case ies.ExtendedCommonFlags:
 			u.ExtendedCommonFlags = i
 		case ies.UserCSGInformation:
 			u.UCI = i
+		case ies.CSGInformationReportingAction:
+			u.CSGInformationReportingAction = i
 		case ies.AggregateMaximumBitRate:
 			u.APNAMBR = i
 		case ies.SignallingPriorityIndication:
@@ -141,10 +159,9 @@ func (u *UpdatePDPContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
-	if len(b) < u.Len() {
-		return ErrTooShortToSerialize
+	if err := u.Header.preparePayload(b, u.Len()); err != nil {
+		return err
 	}
-	u.Header.Payload = make([]byte, u.Len()-u.Header.Len())
 
 	offset := 0
 	if ie := u.IMSI; ie != nil {
@@ -195,6 +212,12 @@ func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.EndUserAddress; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.PCO; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -255,6 +278,12 @@ func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.APNRestriction; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.RATType; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -279,12 +308,24 @@ func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.MSInfoChangeReportingAction; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.DirectTunnelFlags; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
 		}
 		offset += ie.Len()
 	}
+	if ie := u.BearerControlMode; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.EvolvedARPI; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -303,6 +344,12 @@ func (u *UpdatePDPContextRequest) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.CSGInformationReportingAction; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.APNAMBR; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -394,6 +441,8 @@ func (u *UpdatePDPContextRequest) DecodeFromBytes(b []byte) error {
 			u.TraceReference = i
 		case ies.TraceType:
 			u.TraceType = i
+		case ies.EndUserAddress:
+			u.EndUserAddress = i
 		case ies.ProtocolConfigurationOptions:
 			u.PCO = i
 		case ies.GSNAddress:
@@ -416,6 +465,8 @@ func (u *UpdatePDPContextRequest) DecodeFromBytes(b []byte) error {
 			u.OMCIdentity = i
 		case ies.CommonFlags:
 			u.CommonFlags = i
+		case ies.APNRestriction:
+			u.APNRestriction = i
 		case ies.RATType:
 			u.RATType = i
 		case ies.UserLocationInformation:
@@ -424,14 +475,20 @@ func (u *UpdatePDPContextRequest) DecodeFromBytes(b []byte) error {
 			u.MSTimeZone = i
 		case ies.AdditionalTraceInfo:
 			u.AdditionalTraceInfo = i
+		case ies.MSInfoChangeReportingAction:
+			u.MSInfoChangeReportingAction = i
 		case ies.DirectTunnelFlags:
 			u.DirectTunnelFlags = i
+		case ies.BearerControlMode:
+			u.BearerControlMode = i
 		case ies.EvolvedAllocationRetentionPriorityI:
 			u.EvolvedARPI = i
 		case ies.ExtendedCommonFlags:
 			u.ExtendedCommonFlags = i
 		case ies.UserCSGInformation:
 			u.UCI = i
+		case ies.CSGInformationReportingAction:
+			u.CSGInformationReportingAction = i
 		case ies.AggregateMaximumBitRate:
 			u.APNAMBR = i
 		case ies.SignallingPriorityIndication:
@@ -477,6 +534,9 @@ func (u *UpdatePDPContextRequest) Len() int {
 	if ie := u.TraceType; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.EndUserAddress; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.PCO; ie != nil {
 		l += ie.Len()
 	}
@@ -507,6 +567,9 @@ func (u *UpdatePDPContextRequest) Len() int {
 	if ie := u.CommonFlags; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.APNRestriction; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.RATType; ie != nil {
 		l += ie.Len()
 	}
@@ -519,9 +582,15 @@ func (u *UpdatePDPContextRequest) Len() int {
 	if ie := u.AdditionalTraceInfo; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.MSInfoChangeReportingAction; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.DirectTunnelFlags; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.BearerControlMode; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.EvolvedARPI; ie != nil {
 		l += ie.Len()
 	}
@@ -531,6 +600,9 @@ func (u *UpdatePDPContextRequest) Len() int {
 	if ie := u.UCI; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.CSGInformationReportingAction; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.APNAMBR; ie != nil {
 		l += ie.Len()
 	}
@@ -42,6 +42,28 @@ func TestUpdatePDPContextRequest(t *testing.T) {
 				// GSN Address
 				0x85, 0x00, 0x04, 0x02, 0x02, 0x02, 0x02,
 			},
+		}, {
+			Description: "GGSN-initiated",
+			Structured: messages.NewUpdatePDPContextRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123450123456789"),
+				ies.NewNSAPI(5),
+				ies.NewEndUserAddress("10.0.0.1"),
+				ies.NewAPNRestriction(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x12, 0x00, 0x1c, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// IMSI
+				0x02, 0x21, 0x43, 0x05, 0x21, 0x43, 0x65, 0x87, 0xf9,
+				// NSAPI
+				0x14, 0x05,
+				// End User Address
+				0x80, 0x00, 0x06, 0xf1, 0x21, 0x0a, 0x00, 0x00, 0x01,
+				// APN Restriction
+				0x95, 0x00, 0x01, 0x01,
+			},
 		},
 	}
 
@@ -9,6 +9,10 @@ import (
 )
 
 // UpdatePDPContextResponse is a UpdatePDPContextResponse Header and its IEs above.
+//
+// This has the IEs for both responses sent by GGSN and SGSN. In the response sent by
+// SGSN, the only GSN Address, SGSN Address for user traffic, is in GGSNAddressForCPlane
+// as GSN Addresses are distinguished only by their order.
 type UpdatePDPContextResponse struct {
 	*Header
 	Cause                         *ies.IE
@@ -26,8 +30,11 @@ type UpdatePDPContextResponse struct {
 	AltChargingGatewayAddress     *ies.IE
 	CommonFlags                   *ies.IE
 	APNRestriction                *ies.IE
+	ULI                           *ies.IE
+	MSTimeZone                    *ies.IE
 	BearerControlMode             *ies.IE
 	MSInfoChangeReportingAction   *ies.IE
+	DirectTunnelFlags             *ies.IE
 	EvolvedARPI                   *ies.IE
 	CSGInformationReportingAction *ies.IE
 	APNAMBR                       *ies.IE
@@ -80,10 +87,16 @@ func NewUpdatePDPContextResponse(teid uint32, seq uint16, ie ...*ies.IE) *Update
 			u.CommonFlags = i
 		case ies.APNRestriction:
 			u.APNRestriction = i
+		case ies.UserLocationInformation:
+			u.ULI = i
+		case ies.MSTimeZone:
+			u.MSTimeZone = i
 		case ies.BearerControlMode:
 			u.BearerControlMode = i
 		case ies.MSInfoChangeReportingAction:
 			u.MSInfoChangeReportingAction = i
+		case ies.DirectTunnelFlags:
+			u.DirectTunnelFlags = i
 		case ies.EvolvedAllocationRetentionPriorityI:
 			u.EvolvedARPI = i
 		case ies.CSGInformationReportingAction:
@@ -113,10 +126,9 @@ func (u *UpdatePDPContextResponse) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (u *UpdatePDPContextResponse) SerializeTo(b []byte) error {
-	if len(b) < u.Len() {
-		return ErrTooShortToSerialize
+	if err := u.Header.preparePayload(b, u.Len()); err != nil {
+		return err
 	}
-	u.Header.Payload = make([]byte, u.Len()-u.Header.Len())
 
 	offset := 0
 	if ie := u.Cause; ie != nil {
@@ -209,6 +221,18 @@ func (u *UpdatePDPContextResponse) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.ULI; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := u.MSTimeZone; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.BearerControlMode; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -221,6 +245,12 @@ func (u *UpdatePDPContextResponse) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := u.DirectTunnelFlags; ie != nil {
+		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := u.EvolvedARPI; ie != nil {
 		if err := ie.SerializeTo(u.Payload[offset:]); err != nil {
 			return err
@@ -324,10 +354,16 @@ func (u *UpdatePDPContextResponse) DecodeFromBytes(b []byte) error {
 			u.CommonFlags = i
 		case ies.APNRestriction:
 			u.APNRestriction = i
+		case ies.UserLocationInformation:
+			u.ULI = i
+		case ies.MSTimeZone:
+			u.MSTimeZone = i
 		case ies.BearerControlMode:
 			u.BearerControlMode = i
 		case ies.MSInfoChangeReportingAction:
 			u.MSInfoChangeReportingAction = i
+		case ies.DirectTunnelFlags:
+			u.DirectTunnelFlags = i
 		case ies.EvolvedAllocationRetentionPriorityI:
 			u.EvolvedARPI = i
 		case ies.CSGInformationReportingAction:
@@ -392,12 +428,21 @@ func (u *UpdatePDPContextResponse) Len() int {
 	if ie := u.APNRestriction; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.ULI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := u.MSTimeZone; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.BearerControlMode; ie != nil {
 		l += ie.Len()
 	}
 	if ie := u.MSInfoChangeReportingAction; ie != nil {
 		l += ie.Len()
 	}
+	if ie := u.DirectTunnelFlags; ie != nil {
+		l += ie.Len()
+	}
 	if ie := u.EvolvedARPI; ie != nil {
 		l += ie.Len()
 	}
@@ -6,6 +6,7 @@ package messages_test
 
 import (
 	"testing"
+	"time"
 
 	v1 "github.com/wmnsk/go-gtp/v1"
 	"github.com/wmnsk/go-gtp/v1/ies"
@@ -43,6 +44,31 @@ func TestUpdatePDPContextResponse(t *testing.T) {
 				// GSN Address
 				0x85, 0x00, 0x04, 0x02, 0x02, 0x02, 0x02,
 			},
+		}, {
+			Description: "From-SGSN",
+			Structured: messages.NewUpdatePDPContextResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDDataI(0xdeadbeef),
+				ies.NewGSNAddress("1.1.1.1"),
+				ies.NewUserLocationInformationWithSAI("123", "45", 0x1111, 0x2222),
+				ies.NewMSTimeZone(9*time.Hour, 0),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x13, 0x00, 0x22, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// Cause
+				0x01, 0x80,
+				// TEID-U
+				0x10, 0xde, 0xad, 0xbe, 0xef,
+				// GSN Address
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+				// ULI
+				0x98, 0x00, 0x08, 0x01, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22, 0x22,
+				// MS Time Zone
+				0x99, 0x00, 0x02, 0x63, 0x00,
+			},
 		},
 	}
 
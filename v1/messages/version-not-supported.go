@@ -43,10 +43,9 @@ func (v *VersionNotSupported) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (v *VersionNotSupported) SerializeTo(b []byte) error {
-	if len(b) < v.Len() {
-		return ErrTooShortToSerialize
+	if err := v.Header.preparePayload(b, v.Len()); err != nil {
+		return err
 	}
-	v.Header.Payload = make([]byte, v.Len()-v.Header.Len())
 
 	offset := 0
 	for _, ie := range v.AdditionalIEs {
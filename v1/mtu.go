@@ -0,0 +1,192 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+)
+
+// MTUPolicy represents how UPlaneConn handles the packets that exceed the MTU of
+// the outer(transport) network after being encapsulated with GTP-U header.
+type MTUPolicy uint8
+
+// MTUPolicy definitions.
+const (
+	// MTUPolicyNone does nothing special; the packets are passed to the kernel
+	// as they are, and the result depends on the platform and its settings.
+	MTUPolicyNone MTUPolicy = iota
+
+	// MTUPolicyFragment lets the outer IPv4 packet be fragmented, by disabling
+	// Path MTU Discovery (= DF bit) on the underlying socket.
+	MTUPolicyFragment
+
+	// MTUPolicyReflect drops the packet and sends ICMP Destination Unreachable
+	// (Fragmentation Needed) or ICMPv6 Packet Too Big back toward the inner source.
+	// The inner IPv4 packets without DF bit are sent as they are.
+	MTUPolicyReflect
+
+	// MTUPolicyAdvise drops the packet and returns *ErrTooBig which contains
+	// the MTU that the inner packets should be clamped to.
+	MTUPolicyAdvise
+)
+
+// Overheads added by encapsulation, excluding the optional fields in GTP-U header.
+const (
+	OverheadGTPU = 8
+	OverheadUDP  = 8
+	OverheadIPv4 = 20
+	OverheadIPv6 = 40
+)
+
+// SetMTUPolicy sets the MTU of the outer network and the policy to be applied to
+// the packets exceeding it. The mtu given should be the MTU of the outer IP layer,
+// i.e., the one configured on the interface UPlaneConn is bound to.
+//
+// The policy is applied to the packets sent on this UPlaneConn, i.e., both the
+// packets written by WriteToGTP and the ones relayed to it by RelayTo of any
+// UPlaneConn. Giving 0 as mtu disables the check.
+func (u *UPlaneConn) SetMTUPolicy(mtu int, policy MTUPolicy) error {
+	if policy == MTUPolicyFragment {
+		if err := setDontFragment(u.pktConn, false); err != nil {
+			return err
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.mtu = mtu
+	u.mtuPolicy = policy
+	return nil
+}
+
+// MTUPolicy returns the outer MTU and the policy currently set.
+func (u *UPlaneConn) MTUPolicy() (int, MTUPolicy) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.mtu, u.mtuPolicy
+}
+
+// innerMTU returns the maximum size of the inner packet that can be sent to raddr
+// without exceeding the outer MTU given.
+func innerMTU(mtu int, raddr net.Addr, gtpHdrLen int) int {
+	overhead := OverheadIPv4 + OverheadUDP + gtpHdrLen
+	if udpAddr, ok := raddr.(*net.UDPAddr); ok && udpAddr.IP.To4() == nil {
+		overhead = OverheadIPv6 + OverheadUDP + gtpHdrLen
+	}
+	return mtu - overhead
+}
+
+// checkMTU checks if the encapsulated packet fits in the MTU, and returns the
+// inner MTU and whether the packet should be handled by the policy.
+//
+// The inner IPv4 packets without DF bit set are regarded as fitting, as it is
+// legitimate to fragment them on the path.
+func checkMTU(mtu int, policy MTUPolicy, raddr net.Addr, gtpHdrLen int, inner []byte) (int, bool) {
+	if mtu <= 0 || policy == MTUPolicyNone || policy == MTUPolicyFragment {
+		return 0, false
+	}
+
+	imtu := innerMTU(mtu, raddr, gtpHdrLen)
+	if len(inner) <= imtu {
+		return imtu, false
+	}
+
+	if len(inner) > 6 && inner[0]>>4 == 4 && inner[6]&0x40 == 0 {
+		return imtu, false
+	}
+	return imtu, true
+}
+
+// newICMPTooBig creates an ICMP Destination Unreachable(Fragmentation Needed) for
+// IPv4 or ICMPv6 Packet Too Big for IPv6 in response to the inner packet given.
+//
+// The source address of the returned packet is the destination of the original
+// one, as UPlaneConn does not have any address on the inner network.
+func newICMPTooBig(inner []byte, mtu int) ([]byte, error) {
+	if len(inner) < 1 {
+		return nil, ErrMalformedInnerPacket
+	}
+
+	switch inner[0] >> 4 {
+	case 4:
+		return newICMPv4FragNeeded(inner, mtu)
+	case 6:
+		return newICMPv6TooBig(inner, mtu)
+	default:
+		return nil, ErrMalformedInnerPacket
+	}
+}
+
+func newICMPv4FragNeeded(inner []byte, mtu int) ([]byte, error) {
+	if len(inner) < 20 {
+		return nil, ErrMalformedInnerPacket
+	}
+	ihl := int(inner[0]&0x0f) * 4
+	if ihl < 20 || len(inner) < ihl {
+		return nil, ErrMalformedInnerPacket
+	}
+
+	// original IP header + first 8 bytes of original datagram.
+	quoted := inner
+	if len(quoted) > ihl+8 {
+		quoted = quoted[:ihl+8]
+	}
+
+	b := make([]byte, 20+8+len(quoted))
+	b[0] = 0x45
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	b[8] = 64 // TTL
+	b[9] = 1  // ICMP
+	copy(b[12:16], inner[16:20])
+	copy(b[16:20], inner[12:16])
+	binary.BigEndian.PutUint16(b[10:12], checksum.Sum(b[:20], 0))
+
+	icmp := b[20:]
+	icmp[0] = 3 // Destination Unreachable
+	icmp[1] = 4 // Fragmentation Needed and DF set
+	binary.BigEndian.PutUint16(icmp[6:8], uint16(mtu))
+	copy(icmp[8:], quoted)
+	binary.BigEndian.PutUint16(icmp[2:4], checksum.Sum(icmp, 0))
+
+	return b, nil
+}
+
+func newICMPv6TooBig(inner []byte, mtu int) ([]byte, error) {
+	if len(inner) < 40 {
+		return nil, ErrMalformedInnerPacket
+	}
+
+	// as much of invoking packet as possible without exceeding the minimum IPv6 MTU.
+	quoted := inner
+	if max := 1280 - 40 - 8; len(quoted) > max {
+		quoted = quoted[:max]
+	}
+
+	b := make([]byte, 40+8+len(quoted))
+	b[0] = 0x60
+	binary.BigEndian.PutUint16(b[4:6], uint16(8+len(quoted)))
+	b[6] = 58 // ICMPv6
+	b[7] = 64 // Hop Limit
+	copy(b[8:24], inner[24:40])
+	copy(b[24:40], inner[8:24])
+
+	icmp := b[40:]
+	icmp[0] = 2 // Packet Too Big
+	binary.BigEndian.PutUint32(icmp[4:8], uint32(mtu))
+	copy(icmp[8:], quoted)
+
+	binary.BigEndian.PutUint16(icmp[2:4], icmpv6Checksum(b))
+
+	return b, nil
+}
+
+// icmpv6Checksum calculates the checksum of the ICMPv6 message in the IPv6 packet b
+// without the extension headers, with the checksum field in it set to zero.
+func icmpv6Checksum(b []byte) uint16 {
+	return checksum.Sum(b[40:], checksum.PseudoHeader(b[8:40], 58, len(b)-40))
+}
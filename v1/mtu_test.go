@@ -0,0 +1,334 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-gtp/internal/checksum"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestMTUPolicyAdvise(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.21:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raddr, err := net.ResolveUDPAddr("udp", "127.0.0.22:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error)
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	if err := uConn.SetMTUPolicy(1500, v1.MTUPolicyAdvise); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uConn.WriteToGTP(0x11111111, newIPv4Packet(1464), raddr); err != nil {
+		t.Fatalf("packet fitting in MTU is not sent: %v", err)
+	}
+
+	_, err = uConn.WriteToGTP(0x11111111, newIPv4Packet(1465), raddr)
+	tooBig, ok := err.(*v1.ErrTooBig)
+	if !ok {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	if tooBig.MTU != 1464 {
+		t.Errorf("got unexpected MTU advice: %d", tooBig.MTU)
+	}
+}
+
+func TestMTUPolicyFragment(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.110:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uConn, err := v1.ListenAndServeUPlane(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uConn.Close()
+
+	if err := uConn.SetMTUPolicy(1500, v1.MTUPolicyFragment); err != nil {
+		t.Fatal(err)
+	}
+
+	rcvConn, err := net.ListenPacket("udp", "127.0.0.111:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcvConn.Close()
+
+	// the packet exceeding the MTU is sent to be fragmented on the path, and delivered
+	// as a whole after reassembled, even with the DF bit in the inner header set.
+	inner := newIPv4Packet(2000)
+	if _, err := uConn.WriteToGTP(0x11111111, inner, rcvConn.LocalAddr()); err != nil {
+		t.Fatalf("packet exceeding MTU is not sent: %v", err)
+	}
+
+	buf := make([]byte, 4096)
+	if err := rcvConn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := rcvConn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(msg.(*messages.TPDU).Decapsulate(), inner); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMTUPolicyReflect(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.112:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	// inner MTU toward the IPv4 peers is 1500 - 20 - 8 - 8 = 1464.
+	if err := relayConn.SetMTUPolicy(1500, v1.MTUPolicyReflect); err != nil {
+		t.Fatal(err)
+	}
+
+	enb, err := net.ListenPacket("udp", "127.0.0.113:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enb.Close()
+	pgw, err := net.ListenPacket("udp", "127.0.0.114:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgw.Close()
+
+	if err := relayConn.RelayTo(relayConn, 0x11111111, 0x22222222, pgw.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.RelayTo(relayConn, 0x33333333, 0x44444444, enb.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// sendTooBig sends the inner packet from enb, and returns the one reflected to enb.
+	sendTooBig := func(t *testing.T, inner []byte) []byte {
+		t.Helper()
+
+		tpdu, err := messages.NewTPDU(0x11111111, inner).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := enb.WriteTo(tpdu, relayAddr); err != nil {
+			t.Fatal(err)
+		}
+
+		msg := readMessage(t, enb)
+		if msg.MessageType() != messages.MsgTypeTPDU || msg.TEID() != 0x44444444 {
+			t.Fatalf("got unexpected message: %v", msg)
+		}
+		return msg.(*messages.TPDU).Decapsulate()
+	}
+
+	t.Run("ICMPv4", func(t *testing.T) {
+		inner := newIPv4Packet(1465)
+		b := sendTooBig(t, inner)
+
+		if len(b) != 20+8+28 {
+			t.Fatalf("got unexpected length: %d", len(b))
+		}
+		if b[0] != 0x45 || b[9] != 1 || int(binary.BigEndian.Uint16(b[2:4])) != len(b) {
+			t.Errorf("got unexpected IPv4 header: %x", b[:20])
+		}
+		if diff := cmp.Diff(b[12:20], append(inner[16:20:20], inner[12:16]...)); diff != "" {
+			t.Errorf("addresses are not swapped: %s", diff)
+		}
+		if cs := checksum.Sum(b[:20], 0); cs != 0 {
+			t.Errorf("got invalid IPv4 header checksum: %#x", cs)
+		}
+
+		icmp := b[20:]
+		if icmp[0] != 3 || icmp[1] != 4 {
+			t.Errorf("got unexpected ICMP type/code: %d/%d", icmp[0], icmp[1])
+		}
+		if mtu := binary.BigEndian.Uint16(icmp[6:8]); mtu != 1464 {
+			t.Errorf("got unexpected MTU: %d", mtu)
+		}
+		if diff := cmp.Diff(icmp[8:], inner[:28]); diff != "" {
+			t.Errorf("got unexpected quoted packet: %s", diff)
+		}
+		if cs := checksum.Sum(icmp, 0); cs != 0 {
+			t.Errorf("got invalid ICMP checksum: %#x", cs)
+		}
+	})
+
+	t.Run("ICMPv6", func(t *testing.T) {
+		inner := newIPv6Packet(1465)
+		b := sendTooBig(t, inner)
+
+		// the quoted packet is truncated not to exceed the minimum IPv6 MTU.
+		if len(b) != 1280 {
+			t.Fatalf("got unexpected length: %d", len(b))
+		}
+		if b[0]>>4 != 6 || b[6] != 58 || int(binary.BigEndian.Uint16(b[4:6])) != len(b)-40 {
+			t.Errorf("got unexpected IPv6 header: %x", b[:40])
+		}
+		if diff := cmp.Diff(b[8:40], append(inner[24:40:40], inner[8:24]...)); diff != "" {
+			t.Errorf("addresses are not swapped: %s", diff)
+		}
+
+		icmp := b[40:]
+		if icmp[0] != 2 || icmp[1] != 0 {
+			t.Errorf("got unexpected ICMPv6 type/code: %d/%d", icmp[0], icmp[1])
+		}
+		if mtu := binary.BigEndian.Uint32(icmp[4:8]); mtu != 1464 {
+			t.Errorf("got unexpected MTU: %d", mtu)
+		}
+		if diff := cmp.Diff(icmp[8:], inner[:len(icmp)-8]); diff != "" {
+			t.Errorf("got unexpected quoted packet: %s", diff)
+		}
+		if cs := checksum.Sum(icmp, checksum.PseudoHeader(b[8:40], 58, len(icmp))); cs != 0 {
+			t.Errorf("got invalid ICMPv6 checksum: %#x", cs)
+		}
+	})
+
+	// the inner IPv4 packets without DF bit are relayed as they are.
+	inner := newIPv4Packet(1465)
+	inner[6] = 0
+	tpdu, err := messages.NewTPDU(0x11111111, inner).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enb.WriteTo(tpdu, relayAddr); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, pgw); msg.TEID() != 0x22222222 {
+		t.Errorf("got unexpected TEID: %#x", msg.TEID())
+	}
+}
+
+func TestMTUPolicyOfEgressConn(t *testing.T) {
+	s1uAddr, err := net.ResolveUDPAddr("udp", "127.0.0.121:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1uConn, err := v1.ListenAndServeUPlane(s1uAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s1uConn.Close()
+	s5uAddr, err := net.ResolveUDPAddr("udp", "127.0.0.122:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s5uConn, err := v1.ListenAndServeUPlane(s5uAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s5uConn.Close()
+
+	// inner MTU toward the IPv4 peers is 9000 - 36 = 8964 on S1-U, 1500 - 36 = 1464 on S5-U.
+	if err := s1uConn.SetMTUPolicy(9000, v1.MTUPolicyReflect); err != nil {
+		t.Fatal(err)
+	}
+	if err := s5uConn.SetMTUPolicy(1500, v1.MTUPolicyReflect); err != nil {
+		t.Fatal(err)
+	}
+
+	enb, err := net.ListenPacket("udp", "127.0.0.123:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enb.Close()
+	pgw, err := net.ListenPacket("udp", "127.0.0.124:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgw.Close()
+
+	if err := s1uConn.RelayTo(s5uConn, 0x11111111, 0x22222222, pgw.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s5uConn.RelayTo(s1uConn, 0x33333333, 0x44444444, enb.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	inner := newIPv4Packet(1465)
+
+	// uplink exceeds the MTU of S5-U, and is reflected to eNB.
+	tpdu, err := messages.NewTPDU(0x11111111, inner).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enb.WriteTo(tpdu, s1uAddr); err != nil {
+		t.Fatal(err)
+	}
+	msg := readMessage(t, enb)
+	if msg.TEID() != 0x44444444 {
+		t.Fatalf("got unexpected TEID: %#x", msg.TEID())
+	}
+	icmp := msg.(*messages.TPDU).Decapsulate()[20:]
+	if icmp[0] != 3 || icmp[1] != 4 || binary.BigEndian.Uint16(icmp[6:8]) != 1464 {
+		t.Errorf("got unexpected ICMP: %x", icmp[:8])
+	}
+
+	// downlink fits in the MTU of S1-U, and is relayed to eNB.
+	tpdu, err = messages.NewTPDU(0x33333333, inner).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pgw.WriteTo(tpdu, s5uAddr); err != nil {
+		t.Fatal(err)
+	}
+	msg = readMessage(t, enb)
+	if msg.TEID() != 0x44444444 || len(msg.(*messages.TPDU).Decapsulate()) != len(inner) {
+		t.Errorf("got unexpected message: %v", msg)
+	}
+}
+
+// newIPv4Packet returns the IPv4 packet of length l from 10.0.0.1 to 10.0.0.2 with
+// the DF bit set, filled with the sequential bytes after the header.
+func newIPv4Packet(l int) []byte {
+	b := make([]byte, l)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	b[0], b[6], b[8], b[9] = 0x45, 0x40, 64, 17
+	binary.BigEndian.PutUint16(b[2:4], uint16(l))
+	copy(b[12:16], []byte{10, 0, 0, 1})
+	copy(b[16:20], []byte{10, 0, 0, 2})
+	return b
+}
+
+// newIPv6Packet returns the IPv6 packet of length l from fd00::1 to fd00::2, filled
+// with the sequential bytes after the header.
+func newIPv6Packet(l int) []byte {
+	b := make([]byte, l)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	copy(b[:8], []byte{0x60, 0, 0, 0, 0, 0, 17, 64})
+	binary.BigEndian.PutUint16(b[4:6], uint16(l-40))
+	copy(b[8:24], net.ParseIP("fd00::1"))
+	copy(b[24:40], net.ParseIP("fd00::2"))
+	return b
+}
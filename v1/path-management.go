@@ -0,0 +1,60 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// pathQueueSize is the number of the path management messages queued to be handled.
+// The ones arriving while the queue is full are discarded, as the peer retransmits
+// them anyway.
+const pathQueueSize = 64
+
+// pathMessage is the path management message received.
+type pathMessage struct {
+	sender net.Addr
+	msg    messages.Message
+}
+
+// isPathManagement reports whether the message of the type is the path management
+// message, which is handled ahead of the session-level messages.
+func isPathManagement(msgType uint8) bool {
+	switch msgType {
+	case messages.MsgTypeEchoRequest, messages.MsgTypeEchoResponse,
+		messages.MsgTypeVersionNotSupported, messages.MsgTypeSupportedExtensionHeadersNotification:
+		return true
+	default:
+		return false
+	}
+}
+
+// servePathManagement handles the path management messages one by one in a dedicated
+// goroutine, so that Echo Request is answered immediately regardless of the number of
+// the session-level messages being handled, not to let the peer detect the path failure
+// falsely.
+//
+// The HandlerFuncs for the path management messages are called in this goroutine, so
+// they should return quickly.
+func (c *CPlaneConn) servePathManagement(pathCh <-chan pathMessage) {
+	for {
+		select {
+		case <-c.closed():
+			return
+		case pm := <-pathCh:
+			handle, ok := c.msgHandlerMap.load(pm.msg.MessageType())
+			if !ok {
+				continue
+			}
+			if err := handle(c, pm.sender, pm.msg); err != nil {
+				go func() {
+					c.errCh <- err
+				}()
+			}
+		}
+	}
+}
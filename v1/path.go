@@ -0,0 +1,224 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// PeerRestartHandlerFunc is a handler called when the restart of a peer is detected
+// by the change of Restart Counter in the Recovery IE received from it.
+//
+// The PDP contexts established with the peer should be considered inactive and deleted
+// locally in the handler, as specified in 3GPP TS 29.060 7.2.1.
+type PeerRestartHandlerFunc func(c *CPlaneConn, peerAddr net.Addr, oldCounter, newCounter uint8)
+
+// PathFailureHandlerFunc is a handler called when the Echo Requests sent to a peer by
+// StartEchoKeepalive are not responded consecutively for the number of times specified.
+type PathFailureHandlerFunc func(c *CPlaneConn, peerAddr net.Addr)
+
+// peerPath is the state of the path to a peer.
+type peerPath struct {
+	addr       net.Addr
+	counter    uint8
+	hasCounter bool
+
+	// the states of Echo keepalive, which are used only when stopCh is not nil.
+	stopCh   chan struct{}
+	awaiting bool
+	failures int
+	failed   bool
+}
+
+// pathManager keeps the states of the paths to the peers of CPlaneConn.
+type pathManager struct {
+	mu             sync.Mutex
+	peers          map[string]*peerPath
+	restartHandler PeerRestartHandlerFunc
+	failureHandler PathFailureHandlerFunc
+}
+
+// the caller must hold mu.
+func (p *pathManager) peer(addr net.Addr) *peerPath {
+	if p.peers == nil {
+		p.peers = map[string]*peerPath{}
+	}
+	pp, ok := p.peers[addr.String()]
+	if !ok {
+		pp = &peerPath{addr: addr}
+		p.peers[addr.String()] = pp
+	}
+	return pp
+}
+
+// SetPeerRestartHandler sets the handler called when the restart of a peer is detected.
+//
+// The Restart Counter is tracked for each peer address with the Recovery IE in the
+// messages received, regardless of whether StartEchoKeepalive is called for it.
+// The first Restart Counter received from a peer is just recorded.
+func (c *CPlaneConn) SetPeerRestartHandler(fn PeerRestartHandlerFunc) {
+	c.paths.mu.Lock()
+	defer c.paths.mu.Unlock()
+	c.paths.restartHandler = fn
+}
+
+// SetPathFailureHandler sets the handler called when the path to a peer is considered
+// down by StartEchoKeepalive.
+func (c *CPlaneConn) SetPathFailureHandler(fn PathFailureHandlerFunc) {
+	c.paths.mu.Lock()
+	defer c.paths.mu.Unlock()
+	c.paths.failureHandler = fn
+}
+
+// PeerRestartCounter returns the last Restart Counter received from the peer.
+// The second returned value is false if no Recovery IE has been received from it.
+func (c *CPlaneConn) PeerRestartCounter(peerAddr net.Addr) (uint8, bool) {
+	c.paths.mu.Lock()
+	defer c.paths.mu.Unlock()
+
+	pp, ok := c.paths.peers[peerAddr.String()]
+	if !ok || !pp.hasCounter {
+		return 0, false
+	}
+	return pp.counter, true
+}
+
+// StartEchoKeepalive starts sending Echo Request to peerAddr periodically with the
+// interval given, to supervise the path to the peer.
+//
+// If the Echo Response is not received before the next Echo Request is sent for
+// maxFailures times in a row, the path is considered down and the handler set by
+// SetPathFailureHandler is called. The handler is called once until the path gets
+// back, i.e., the Echo Response is received again.
+//
+// Calling this for the peer that is already supervised restarts it with the new values.
+// The keepalive stops when StopEchoKeepalive is called or CPlaneConn is closed.
+func (c *CPlaneConn) StartEchoKeepalive(peerAddr net.Addr, interval time.Duration, maxFailures int) {
+	c.paths.mu.Lock()
+	pp := c.paths.peer(peerAddr)
+	if pp.stopCh != nil {
+		close(pp.stopCh)
+	}
+	stopCh := make(chan struct{})
+	pp.stopCh = stopCh
+	pp.awaiting = false
+	pp.failures = 0
+	pp.failed = false
+	c.paths.mu.Unlock()
+
+	go c.keepalive(pp, stopCh, interval, maxFailures)
+}
+
+// StopEchoKeepalive stops sending Echo Request started by StartEchoKeepalive.
+func (c *CPlaneConn) StopEchoKeepalive(peerAddr net.Addr) {
+	c.paths.mu.Lock()
+	defer c.paths.mu.Unlock()
+
+	pp, ok := c.paths.peers[peerAddr.String()]
+	if !ok || pp.stopCh == nil {
+		return
+	}
+	close(pp.stopCh)
+	pp.stopCh = nil
+}
+
+func (c *CPlaneConn) keepalive(pp *peerPath, stopCh chan struct{}, interval time.Duration, maxFailures int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.paths.mu.Lock()
+		pp.awaiting = true
+		c.paths.mu.Unlock()
+
+		if err := c.EchoRequest(pp.addr); err != nil {
+			go func() {
+				c.errCh <- err
+			}()
+		}
+
+		select {
+		case <-c.closed():
+			return
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		c.paths.mu.Lock()
+		if !pp.awaiting {
+			c.paths.mu.Unlock()
+			continue
+		}
+		pp.failures++
+		var fn PathFailureHandlerFunc
+		if pp.failures >= maxFailures && !pp.failed {
+			pp.failed = true
+			fn = c.paths.failureHandler
+		}
+		c.paths.mu.Unlock()
+
+		if fn != nil {
+			go fn(c, pp.addr)
+		}
+	}
+}
+
+// observePeer updates the state of the path to the peer with the message received.
+func (c *CPlaneConn) observePeer(senderAddr net.Addr, msg messages.Message) {
+	recovery := recoveryIE(msg)
+	_, isEchoRes := msg.(*messages.EchoResponse)
+	if recovery == nil && !isEchoRes {
+		return
+	}
+
+	c.paths.mu.Lock()
+	pp := c.paths.peer(senderAddr)
+	if isEchoRes {
+		pp.awaiting = false
+		pp.failures = 0
+		pp.failed = false
+	}
+
+	var fn PeerRestartHandlerFunc
+	var old uint8
+	if recovery != nil {
+		counter := recovery.Recovery()
+		if pp.hasCounter && pp.counter != counter {
+			fn = c.paths.restartHandler
+			old = pp.counter
+		}
+		pp.counter = counter
+		pp.hasCounter = true
+	}
+	c.paths.mu.Unlock()
+
+	if fn != nil {
+		go fn(c, senderAddr, old, recovery.Recovery())
+	}
+}
+
+// recoveryIE returns the Recovery IE in the message, or nil if not included.
+func recoveryIE(msg messages.Message) *ies.IE {
+	switch m := msg.(type) {
+	case *messages.EchoResponse:
+		return m.Recovery
+	case *messages.CreatePDPContextRequest:
+		return m.Recovery
+	case *messages.CreatePDPContextResponse:
+		return m.Recovery
+	case *messages.UpdatePDPContextRequest:
+		return m.Recovery
+	case *messages.UpdatePDPContextResponse:
+		return m.Recovery
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestEchoKeepalive(t *testing.T) {
+	connAddr, err := net.ResolveUDPAddr("udp", "127.0.0.83:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v1.ListenAndServeCPlane(connAddr, 0, make(chan error, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	peerConn, err := net.ListenPacket("udp", "127.0.0.84:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerConn.Close()
+
+	restartCh := make(chan [2]uint8, 1)
+	conn.SetPeerRestartHandler(func(c *v1.CPlaneConn, peerAddr net.Addr, oldCounter, newCounter uint8) {
+		restartCh <- [2]uint8{oldCounter, newCounter}
+	})
+	failureCh := make(chan net.Addr, 1)
+	conn.SetPathFailureHandler(func(c *v1.CPlaneConn, peerAddr net.Addr) {
+		failureCh <- peerAddr
+	})
+
+	conn.StartEchoKeepalive(peerConn.LocalAddr(), 100*time.Millisecond, 2)
+	defer conn.StopEchoKeepalive(peerConn.LocalAddr())
+
+	// respond to the Echo Requests with the Restart Counter incremented on the second one.
+	for _, counter := range []uint8{1, 2} {
+		msg := readMessage(t, peerConn)
+		if _, ok := msg.(*messages.EchoRequest); !ok {
+			t.Fatalf("got unexpected message: %v", msg.MessageTypeName())
+		}
+		res, err := messages.NewEchoResponse(msg.Sequence(), ies.NewRecovery(counter)).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peerConn.WriteTo(res, connAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case got := <-restartCh:
+		if got != [2]uint8{1, 2} {
+			t.Errorf("got unexpected Restart Counters: %v", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("peer restart not detected")
+	}
+	if counter, ok := conn.PeerRestartCounter(peerConn.LocalAddr()); !ok || counter != 2 {
+		t.Errorf("got unexpected Restart Counter: %d, %v", counter, ok)
+	}
+
+	// stop responding to let the path fail.
+	select {
+	case addr := <-failureCh:
+		if addr.String() != peerConn.LocalAddr().String() {
+			t.Errorf("got unexpected peer: %v", addr)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("path failure not detected")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// PDPContext is a GTPv1 PDP context.
+type PDPContext struct {
+	raddr           net.Addr
+	teidIn, teidOut uint32
+
+	NSAPI             uint8
+	SubscriberIP, APN string
+	ChargingID        uint32
+
+	// QoSProfile is the value of QoS Profile IE, which is kept as it is
+	// as the format varies depending on the release of the peer.
+	QoSProfile []byte
+}
+
+// NewPDPContext creates a new PDPContext.
+func NewPDPContext(nsapi uint8, apn string, qos []byte) *PDPContext {
+	return &PDPContext{
+		NSAPI: nsapi, APN: apn, QoSProfile: qos,
+	}
+}
+
+// Update is just an alias of (*CPlaneConn) UpdatePDPContext.
+func (p *PDPContext) Update(c *CPlaneConn, ie ...*ies.IE) error {
+	return c.UpdatePDPContext(p.teidOut, ie...)
+}
+
+// RemoteAddress returns the remote address associated with PDPContext.
+func (p *PDPContext) RemoteAddress() net.Addr {
+	return p.raddr
+}
+
+// SetRemoteAddress sets the remote address associated with PDPContext.
+func (p *PDPContext) SetRemoteAddress(raddr net.Addr) {
+	p.raddr = raddr
+}
+
+// IncomingTEID returns the incoming TEID associated with PDPContext.
+func (p *PDPContext) IncomingTEID() uint32 {
+	return p.teidIn
+}
+
+// SetIncomingTEID sets the incoming TEID associated with PDPContext.
+func (p *PDPContext) SetIncomingTEID(teid uint32) {
+	p.teidIn = teid
+}
+
+// OutgoingTEID returns the outgoing TEID associated with PDPContext.
+func (p *PDPContext) OutgoingTEID() uint32 {
+	return p.teidOut
+}
+
+// SetOutgoingTEID sets the outgoing TEID associated with PDPContext.
+func (p *PDPContext) SetOutgoingTEID(teid uint32) {
+	p.teidOut = teid
+}
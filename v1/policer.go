@@ -0,0 +1,133 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"sync"
+	"time"
+)
+
+// Policer is a token bucket that polices the rate of the packets, which is typically
+// used to enforce the MBR of a bearer in relay.
+type Policer struct {
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	burst  float64 // bytes
+	tokens float64
+	last   time.Time
+
+	stats PolicerStats
+}
+
+// PolicerStats is a set of counters of a Policer.
+type PolicerStats struct {
+	PassedPackets, PassedBytes   uint64
+	DroppedPackets, DroppedBytes uint64
+}
+
+// NewPolicer creates a new Policer with the rate in bits per second and the burst
+// size in bytes. The bucket is full at first.
+//
+// Note that MBR in Bearer QoS IE of GTPv2 is given in kilobits per second.
+func NewPolicer(rate uint64, burst int) *Policer {
+	return &Policer{
+		rate:   float64(rate) / 8,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether the packet of the size given conforms to the rate,
+// and updates the counters.
+func (p *Policer) Allow(size int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.tokens += now.Sub(p.last).Seconds() * p.rate
+	if p.tokens > p.burst {
+		p.tokens = p.burst
+	}
+	p.last = now
+
+	if float64(size) > p.tokens {
+		p.stats.DroppedPackets++
+		p.stats.DroppedBytes += uint64(size)
+		return false
+	}
+
+	p.tokens -= float64(size)
+	p.stats.PassedPackets++
+	p.stats.PassedBytes += uint64(size)
+	return true
+}
+
+// Stats returns the current counters of the Policer.
+func (p *Policer) Stats() PolicerStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// SetPolicer sets the Policer to the T-PDUs with teidIn relayed by RelayTo.
+// The packets exceeding the rate are dropped silently and counted in the Policer.
+//
+// The size of the inner packet is used for the calculation, as MBR is defined
+// without the overhead of GTP-U encapsulation.
+func (u *UPlaneConn) SetPolicer(teidIn uint32, p *Policer) {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if u.policerMap == nil {
+		u.policerMap = map[uint32]*Policer{}
+	}
+	u.policerMap[teidIn] = p
+}
+
+// RemovePolicer removes the Policer set for teidIn.
+func (u *UPlaneConn) RemovePolicer(teidIn uint32) {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+	delete(u.policerMap, teidIn)
+}
+
+// GetPolicer returns the Policer set for teidIn.
+func (u *UPlaneConn) GetPolicer(teidIn uint32) (*Policer, bool) {
+	u.relayMu.RLock()
+	defer u.relayMu.RUnlock()
+	p, ok := u.policerMap[teidIn]
+	return p, ok
+}
+
+// innerLen returns the length of the payload in serialized T-PDU, without
+// decoding the whole header. The extension headers are skipped if E flag is set.
+func innerLen(b []byte) int {
+	if len(b) < 8 {
+		return 0
+	}
+	if b[0]&0x07 == 0 {
+		return len(b) - 8
+	}
+	if len(b) < 12 {
+		return 0
+	}
+
+	offset := 12
+	if b[0]&0x04 != 0 {
+		// the length of each extension header is in 4 octets, and its last
+		// octet is the type of the next one.
+		for next := b[11]; next != 0; next = b[offset-1] {
+			if offset >= len(b) || b[offset] == 0 {
+				return 0
+			}
+			offset += int(b[offset]) * 4
+			if offset > len(b) {
+				return 0
+			}
+		}
+	}
+	return len(b) - offset
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestPolicer(t *testing.T) {
+	// 8kbps = 1000 bytes per second, with 1500 bytes of burst.
+	p := v1.NewPolicer(8000, 1500)
+
+	if !p.Allow(1000) {
+		t.Error("packet within burst is dropped")
+	}
+	if p.Allow(1000) {
+		t.Error("packet exceeding burst is passed")
+	}
+	if !p.Allow(400) {
+		t.Error("packet within remaining tokens is dropped")
+	}
+
+	want := v1.PolicerStats{
+		PassedPackets: 2, PassedBytes: 1400,
+		DroppedPackets: 1, DroppedBytes: 1000,
+	}
+	if diff := cmp.Diff(p.Stats(), want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestPolicerWithExtensionHeaders(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.118:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	enb, err := net.ListenPacket("udp", "127.0.0.119:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enb.Close()
+	pgw, err := net.ListenPacket("udp", "127.0.0.120:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgw.Close()
+
+	if err := relayConn.RelayTo(relayConn, 0x11111111, 0x22222222, pgw.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the burst fits the inner packet only, not with the extension headers.
+	p := v1.NewPolicer(8000, 100)
+	relayConn.SetPolicer(0x11111111, p)
+
+	tpdu, err := messages.NewTPDUWithExtensionHeaders(
+		0x11111111, make([]byte, 100),
+		messages.NewPDUSessionContainer(messages.PDUTypeULPDUSessionInformation, 9),
+		messages.NewExtensionHeader(messages.ExtHeaderTypeUDPPort, []byte{0x08, 0x68}),
+	).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enb.WriteTo(tpdu, relayAddr); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, pgw); msg.TEID() != 0x22222222 {
+		t.Errorf("got unexpected TEID: %#x", msg.TEID())
+	}
+
+	want := v1.PolicerStats{PassedPackets: 1, PassedBytes: 100}
+	if diff := cmp.Diff(p.Stats(), want); diff != "" {
+		t.Error(diff)
+	}
+}
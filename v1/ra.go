@@ -0,0 +1,213 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// DefaultRouterLifetime is the default RouterLifetime of RouterAdvertisement, which is
+// the default AdvDefaultLifetime in RFC 4861.
+const DefaultRouterLifetime = 1800 * time.Second
+
+// DefaultRouterLinkLocal is the default Source of RouterAdvertisement, which is the
+// link-local address of the GGSN/P-GW on the tunnel.
+var DefaultRouterLinkLocal = netip.MustParseAddr("fe80::1")
+
+var allNodes = netip.MustParseAddr("ff02::1")
+
+// RouterAdvertisement is the ICMPv6 Router Advertisement sent to the UE of the IPv6
+// PDN connection through the GTP-U tunnel, to advertise the IPv6 prefix delegated in
+// PAA for the stateless address autoconfiguration, as defined in TS 29.061 11.2.1.3.
+//
+// The prefix is advertised with the infinite lifetimes and without the on-link flag,
+// as the prefix is delegated to the UE for the lifetime of the PDN connection.
+type RouterAdvertisement struct {
+	// Source is the link-local address of the router, which is DefaultRouterLinkLocal
+	// if not valid.
+	Source netip.Addr
+	// Prefix is the IPv6 prefix delegated to the UE, which should be /64.
+	Prefix netip.Prefix
+	// RouterLifetime is the lifetime of the default router, which is rounded down to
+	// seconds and capped at 65535 seconds. 0 means the router is not the default one.
+	RouterLifetime time.Duration
+	// MTU is advertised in the MTU option if positive, e.g., the inner MTU of the tunnel.
+	MTU int
+}
+
+// NewRouterAdvertisement creates a new RouterAdvertisement of the prefix, with the
+// DefaultRouterLinkLocal and the DefaultRouterLifetime.
+func NewRouterAdvertisement(prefix netip.Prefix) *RouterAdvertisement {
+	return &RouterAdvertisement{
+		Source:         DefaultRouterLinkLocal,
+		Prefix:         prefix,
+		RouterLifetime: DefaultRouterLifetime,
+	}
+}
+
+// Marshal returns the IPv6 packet of the RouterAdvertisement destined for dst, which is
+// the all-nodes multicast address if not valid. ErrInvalidPrefix is returned if Prefix
+// is not the IPv6 prefix.
+func (ra *RouterAdvertisement) Marshal(dst netip.Addr) ([]byte, error) {
+	if !ra.Prefix.IsValid() || !ra.Prefix.Addr().Is6() || ra.Prefix.Addr().Is4In6() {
+		return nil, ErrInvalidPrefix
+	}
+	src := ra.Source
+	if !src.IsValid() {
+		src = DefaultRouterLinkLocal
+	}
+	if !dst.IsValid() {
+		dst = allNodes
+	}
+
+	l := 16 + 32 // RA + Prefix Information option.
+	if ra.MTU > 0 {
+		l += 8
+	}
+	b := make([]byte, 40+l)
+	b[0] = 0x60
+	binary.BigEndian.PutUint16(b[4:6], uint16(l))
+	b[6] = 58  // ICMPv6
+	b[7] = 255 // Hop Limit, required for Neighbor Discovery.
+	src16, dst16 := src.As16(), dst.As16()
+	copy(b[8:24], src16[:])
+	copy(b[24:40], dst16[:])
+
+	icmp := b[40:]
+	icmp[0] = 134 // Router Advertisement
+	icmp[4] = 64  // Cur Hop Limit
+	binary.BigEndian.PutUint16(icmp[6:8], uint16(min(ra.RouterLifetime/time.Second, 0xffff)))
+
+	opt := icmp[16:48]
+	opt[0] = 3 // Prefix Information
+	opt[1] = 4
+	opt[2] = uint8(ra.Prefix.Bits())
+	opt[3] = 0x40 // A flag, without L flag.
+	binary.BigEndian.PutUint32(opt[4:8], 0xffffffff)
+	binary.BigEndian.PutUint32(opt[8:12], 0xffffffff)
+	prefix := ra.Prefix.Masked().Addr().As16()
+	copy(opt[16:32], prefix[:])
+
+	if ra.MTU > 0 {
+		opt = icmp[48:56]
+		opt[0] = 5 // MTU
+		opt[1] = 1
+		binary.BigEndian.PutUint32(opt[4:8], uint32(ra.MTU))
+	}
+
+	binary.BigEndian.PutUint16(icmp[2:4], icmpv6Checksum(b))
+	return b, nil
+}
+
+// IsRouterSolicitation reports whether the packet encapsulated in T-PDU is the ICMPv6
+// Router Solicitation sent by the UE to get the Router Advertisement.
+func IsRouterSolicitation(pkt []byte) bool {
+	return len(pkt) >= 48 && pkt[0]>>4 == 6 && pkt[6] == 58 && pkt[7] == 255 && pkt[40] == 133
+}
+
+// raTunnel is the GTP-U tunnel of the IPv6 PDN connection to send the Router
+// Advertisements through.
+type raTunnel struct {
+	ra   *RouterAdvertisement
+	teid uint32
+	peer net.Addr
+}
+
+// RouterAdvertiser sends the Router Advertisements to the UEs of the IPv6 PDN connections
+// through the GTP-U tunnels on UPlaneConn, when the tunnels are added and when the UEs
+// solicit them.
+//
+// The uplink T-PDUs read from UPlaneConn should be given to HandleUplink, which answers
+// the Router Solicitations.
+type RouterAdvertiser struct {
+	uConn *UPlaneConn
+
+	// Source is the link-local address of the router, which is DefaultRouterLinkLocal
+	// if not valid.
+	Source netip.Addr
+	// RouterLifetime is the RouterLifetime of the Router Advertisements, which is
+	// DefaultRouterLifetime if 0.
+	RouterLifetime time.Duration
+	// MTU is advertised in the MTU option if positive.
+	MTU int
+
+	mu      sync.RWMutex
+	tunnels map[uint32]*raTunnel
+}
+
+// NewRouterAdvertiser creates a new RouterAdvertiser that sends the Router Advertisements
+// on uConn.
+func NewRouterAdvertiser(uConn *UPlaneConn) *RouterAdvertiser {
+	return &RouterAdvertiser{uConn: uConn, tunnels: map[uint32]*raTunnel{}}
+}
+
+// AddTunnel adds the tunnel of the IPv6 PDN connection with the prefix delegated to the
+// UE, and sends the Router Advertisement to the UE. The tunnel is identified by the
+// incomingTEID of the T-PDUs from the UE, and the Router Advertisements are sent with
+// outgoingTEID to peer. If the tunnel is already added, the old one is replaced.
+//
+// The tunnel is added even if it fails to send the Router Advertisement, as the UE
+// solicits it again.
+func (a *RouterAdvertiser) AddTunnel(incomingTEID uint32, prefix netip.Prefix, outgoingTEID uint32, peer net.Addr) error {
+	ra := &RouterAdvertisement{
+		Source:         a.Source,
+		Prefix:         prefix,
+		RouterLifetime: a.RouterLifetime,
+		MTU:            a.MTU,
+	}
+	if ra.RouterLifetime == 0 {
+		ra.RouterLifetime = DefaultRouterLifetime
+	}
+	pkt, err := ra.Marshal(netip.Addr{})
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.tunnels[incomingTEID] = &raTunnel{ra: ra, teid: outgoingTEID, peer: peer}
+	a.mu.Unlock()
+
+	_, err = a.uConn.WriteToGTP(outgoingTEID, pkt, peer)
+	return err
+}
+
+// RemoveTunnel removes the tunnel of incomingTEID.
+func (a *RouterAdvertiser) RemoveTunnel(incomingTEID uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.tunnels, incomingTEID)
+}
+
+// HandleUplink answers the Router Solicitation in pkt from the UE through the tunnel of
+// incomingTEID, and reports whether pkt is consumed. The other packets and the ones from
+// the unknown tunnels are left to the caller.
+func (a *RouterAdvertiser) HandleUplink(pkt []byte, incomingTEID uint32) (bool, error) {
+	if !IsRouterSolicitation(pkt) {
+		return false, nil
+	}
+
+	a.mu.RLock()
+	t, ok := a.tunnels[incomingTEID]
+	a.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+
+	// answer to the link-local address of the UE, or to all the nodes if not yet known.
+	dst, _ := netip.AddrFromSlice(pkt[8:24])
+	if dst.IsUnspecified() {
+		dst = netip.Addr{}
+	}
+	ra, err := t.ra.Marshal(dst)
+	if err != nil {
+		return true, err
+	}
+	_, err = a.uConn.WriteToGTP(t.teid, ra, t.peer)
+	return true, err
+}
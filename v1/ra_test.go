@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+// validICMPv6Checksum reports whether the checksum of the ICMPv6 message in the IPv6
+// packet b is valid.
+func validICMPv6Checksum(b []byte) bool {
+	var sum uint32
+	for i := 8; i < 40; i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	sum += uint32(len(b)-40) + 58
+	for i := 40; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return sum == 0xffff
+}
+
+func newRouterSolicitation(src netip.Addr) []byte {
+	b := make([]byte, 48)
+	b[0] = 0x60
+	b[5] = 8
+	b[6], b[7] = 58, 255
+	src16, dst16 := src.As16(), netip.MustParseAddr("ff02::2").As16()
+	copy(b[8:24], src16[:])
+	copy(b[24:40], dst16[:])
+	b[40] = 133
+	return b
+}
+
+func TestRouterAdvertisement(t *testing.T) {
+	ra := v1.NewRouterAdvertisement(netip.MustParsePrefix("2001:db8:1:2::/64"))
+	ra.MTU = 1400
+	got, err := ra.Marshal(netip.Addr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		// IPv6 header
+		0x60, 0x00, 0x00, 0x00, 0x00, 0x38, 0x3a, 0xff,
+		0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		0xff, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		// Router Advertisement, checksum is cleared below.
+		0x86, 0x00, 0x00, 0x00, 0x40, 0x00, 0x07, 0x08,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// Prefix Information
+		0x03, 0x04, 0x40, 0x40, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+		0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// MTU
+		0x05, 0x01, 0x00, 0x00, 0x00, 0x00, 0x05, 0x78,
+	}
+	if !validICMPv6Checksum(got) {
+		t.Error("invalid checksum")
+	}
+	cleared := append([]byte{}, got...)
+	cleared[42], cleared[43] = 0, 0
+	if diff := cmp.Diff(cleared, want); diff != "" {
+		t.Error(diff)
+	}
+
+	ra.Prefix = netip.MustParsePrefix("10.0.0.0/24")
+	if _, err := ra.Marshal(netip.Addr{}); !errors.Is(err, v1.ErrInvalidPrefix) {
+		t.Errorf("got %v, want %v", err, v1.ErrInvalidPrefix)
+	}
+}
+
+func TestRouterAdvertiser(t *testing.T) {
+	pgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.95:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.96:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pgwConn, err := v1.ListenAndServeUPlane(pgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgwConn.Close()
+	sgwConn, err := v1.ListenAndServeUPlane(sgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sgwConn.Close()
+
+	// readRA reads the Router Advertisement sent to S-GW, and returns its destination.
+	readRA := func() netip.Addr {
+		t.Helper()
+
+		_ = sgwConn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		buf := make([]byte, 1500)
+		n, _, teid, err := sgwConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if teid != 0x22222222 {
+			t.Errorf("got unexpected TEID: %#x", teid)
+		}
+		if n < 41 || buf[40] != 134 {
+			t.Fatalf("not a Router Advertisement: %x", buf[:n])
+		}
+		dst, _ := netip.AddrFromSlice(buf[24:40])
+		return dst
+	}
+
+	ra := v1.NewRouterAdvertiser(pgwConn)
+	if err := ra.AddTunnel(0x11111111, netip.MustParsePrefix("2001:db8:1:2::/64"), 0x22222222, sgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	if got := readRA(); got != netip.MustParseAddr("ff02::1") {
+		t.Errorf("got unexpected destination: %s", got)
+	}
+
+	ue := netip.MustParseAddr("fe80::1234")
+	handled, err := ra.HandleUplink(newRouterSolicitation(ue), 0x11111111)
+	if err != nil || !handled {
+		t.Fatalf("got %v, %v", handled, err)
+	}
+	if got := readRA(); got != ue {
+		t.Errorf("got unexpected destination: %s", got)
+	}
+
+	// the others are left to the caller.
+	if handled, _ := ra.HandleUplink(newRouterSolicitation(ue), 0x33333333); handled {
+		t.Error("Router Solicitation from unknown tunnel is handled")
+	}
+	ra.RemoveTunnel(0x11111111)
+	if handled, _ := ra.HandleUplink(newRouterSolicitation(ue), 0x11111111); handled {
+		t.Error("Router Solicitation from removed tunnel is handled")
+	}
+	if handled, _ := ra.HandleUplink(make([]byte, 48), 0x11111111); handled {
+		t.Error("non-Router Solicitation is handled")
+	}
+}
@@ -7,8 +7,11 @@ package v1_test
 import (
 	"net"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
 )
 
 func TestRelay(t *testing.T) {
@@ -43,3 +46,248 @@ func TestRelay(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestReplaceRelay(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.13:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	oldPeer, err := net.ListenPacket("udp", "127.0.0.14:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer oldPeer.Close()
+	newPeer, err := net.ListenPacket("udp", "127.0.0.15:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer newPeer.Close()
+
+	teidIn, oldTEID, newTEID := uint32(0x11111111), uint32(0x22222222), uint32(0x33333333)
+	if err := relayConn.RelayTo(relayConn, teidIn, oldTEID, oldPeer.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.ReplaceRelay(relayConn, teidIn, newTEID, newPeer.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := readMessage(t, oldPeer); msg.MessageType() != messages.MsgTypeEndMarker || msg.TEID() != oldTEID {
+		t.Errorf("got unexpected message on old path: %v", msg)
+	}
+
+	if _, err := relayConn.WriteToGTP(teidIn, []byte{0xde, 0xad, 0xbe, 0xef}, relayAddr); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, newPeer); msg.MessageType() != messages.MsgTypeTPDU || msg.TEID() != newTEID {
+		t.Errorf("got unexpected message on new path: %v", msg)
+	}
+
+	if err := relayConn.RemoveRelay(teidIn); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.RemoveRelay(teidIn); err != v1.ErrRelayNotFound {
+		t.Errorf("got unexpected error: %v", err)
+	}
+}
+
+func TestRelayToByPeer(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.16:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+	relayConn.SetRelayKeyMode(v1.RelayKeyTEIDAndPeer)
+
+	var conns []net.PacketConn
+	for _, addr := range []string{"127.0.0.17:2152", "127.0.0.18:2152"} {
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	// both peers use the same TEID toward relayConn, and each is relayed back to the sender.
+	teidIn := uint32(0x11111111)
+	for i, conn := range conns {
+		if err := relayConn.RelayToByPeer(relayConn, conn.LocalAddr(), teidIn, uint32(i+1), conn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tpdu, err := messages.NewTPDU(teidIn, []byte{0xde, 0xad, 0xbe, 0xef}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, conn := range conns {
+		if _, err := conn.WriteTo(tpdu, relayAddr); err != nil {
+			t.Fatal(err)
+		}
+		if msg := readMessage(t, conn); msg.TEID() != uint32(i+1) {
+			t.Errorf("got unexpected TEID: %#x", msg.TEID())
+		}
+	}
+
+	if err := relayConn.RemoveRelayByPeer(conns[0].LocalAddr(), teidIn); err != nil {
+		t.Fatal(err)
+	}
+	if err := relayConn.RemoveRelay(teidIn); err != v1.ErrRelayNotFound {
+		t.Errorf("got unexpected error: %v", err)
+	}
+}
+
+func TestRelayToByPeerIPv4Mapped(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.116:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+	relayConn.SetRelayKeyMode(v1.RelayKeyTEIDAndPeer)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.117:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the peer given in the 16-byte form matches the packets from the IPv4 address.
+	peerAddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.117"), Port: 2152}
+	if err := relayConn.RelayToByPeer(relayConn, peerAddr, 0x11111111, 0x22222222, conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	tpdu, err := messages.NewTPDU(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.WriteTo(tpdu, relayAddr); err != nil {
+		t.Fatal(err)
+	}
+	if msg := readMessage(t, conn); msg.TEID() != 0x22222222 {
+		t.Errorf("got unexpected TEID: %#x", msg.TEID())
+	}
+}
+
+func TestRelayN3(t *testing.T) {
+	relayAddr, err := net.ResolveUDPAddr("udp", "127.0.0.61:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayConn, err := v1.ListenAndServeUPlane(relayAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer relayConn.Close()
+
+	enb, err := net.ListenPacket("udp", "127.0.0.62:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer enb.Close()
+	upf, err := net.ListenPacket("udp", "127.0.0.63:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upf.Close()
+
+	payload := []byte{0xde, 0xad, 0xbe, 0xef}
+	t.Run("uplink", func(t *testing.T) {
+		if err := relayConn.RelayToN3(relayConn, 0x11111111, 0x22222222, 9, upf.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		tpdu, err := messages.NewTPDU(0x11111111, payload).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := enb.WriteTo(tpdu, relayAddr); err != nil {
+			t.Fatal(err)
+		}
+
+		msg := readMessage(t, upf).(*messages.TPDU)
+		if msg.TEID() != 0x22222222 {
+			t.Errorf("got unexpected TEID: %#x", msg.TEID())
+		}
+		psc, ok := msg.ExtensionHeader(messages.ExtHeaderTypePDUSessionContainer)
+		if !ok {
+			t.Fatal("PDU Session Container not found")
+		}
+		if pduType, _ := psc.PDUType(); pduType != messages.PDUTypeULPDUSessionInformation {
+			t.Errorf("got unexpected PDU Type: %d", pduType)
+		}
+		if qfi, _ := psc.QFI(); qfi != 9 {
+			t.Errorf("got unexpected QFI: %d", qfi)
+		}
+		if diff := cmp.Diff(msg.Decapsulate(), payload); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("downlink", func(t *testing.T) {
+		if err := relayConn.RelayFromN3(relayConn, 0x33333333, 9, 0x44444444, enb.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		tpdu, err := messages.NewTPDUWithExtensionHeaders(
+			0x33333333, payload,
+			messages.NewPDUSessionContainer(messages.PDUTypeDLPDUSessionInformation, 9),
+		).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := upf.WriteTo(tpdu, relayAddr); err != nil {
+			t.Fatal(err)
+		}
+
+		msg := readMessage(t, enb).(*messages.TPDU)
+		if msg.TEID() != 0x44444444 {
+			t.Errorf("got unexpected TEID: %#x", msg.TEID())
+		}
+		if msg.HasExtensionHeader() {
+			t.Errorf("got unexpected Extension Headers: %v", msg.ExtensionHeaders)
+		}
+		if diff := cmp.Diff(msg.Decapsulate(), payload); diff != "" {
+			t.Error(diff)
+		}
+
+		if err := relayConn.RemoveRelayFromN3(0x33333333, 9); err != nil {
+			t.Fatal(err)
+		}
+		if err := relayConn.RemoveRelayFromN3(0x33333333, 9); err != v1.ErrRelayNotFound {
+			t.Errorf("got unexpected error: %v", err)
+		}
+	})
+}
+
+func readMessage(t *testing.T, conn net.PacketConn) messages.Message {
+	t.Helper()
+
+	buf := make([]byte, 1500)
+	if err := conn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
@@ -0,0 +1,354 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// Location is a subscriber's location.
+type Location struct {
+	MCC, MNC     string
+	RATType      uint8
+	LAC, CI, SAC uint16
+	RAC          uint8
+}
+
+// Subscriber is a subscriber that belongs to a GTPv1 session.
+type Subscriber struct {
+	IMSI, MSISDN, IMEI string
+	*Location
+}
+
+// Session is a GTPv1 Session, which is a set of PDP contexts that belong to
+// a subscriber.
+type Session struct {
+	mu       sync.Mutex
+	isActive bool
+	*teidMap
+	*pdpContextMap
+	inflightCh chan messages.Message
+
+	// PeerAddr is a net.Addr of the peer of the Session.
+	PeerAddr net.Addr
+
+	// Sequence is the last SequenceNumber used in the request.
+	// This should be incremented when used manually by users.
+	Sequence uint16
+
+	// Subscriber is a Subscriber associated with the Session.
+	*Subscriber
+}
+
+// NewSession creates a new Session with subscriber information.
+//
+// This is expected to be used by server-like nodes. Otherwise, use CreatePDPContext(),
+// which sends Create PDP Context Request and returns a new Session.
+func NewSession(peerAddr net.Addr, sub *Subscriber) *Session {
+	s := &Session{
+		mu:            sync.Mutex{},
+		PeerAddr:      peerAddr,
+		teidMap:       newTeidMap(),
+		pdpContextMap: newPDPContextMap("default", &PDPContext{}),
+		Subscriber:    sub,
+		inflightCh:    make(chan messages.Message),
+	}
+
+	u16buf := make([]byte, 2)
+	if _, err := rand.Read(u16buf); err != nil {
+		u16buf = []byte{0x00, 0x00}
+	}
+	s.Sequence = binary.BigEndian.Uint16(u16buf)
+
+	return s
+}
+
+// CreatePDPContext is an alias for (*CPlaneConn).CreatePDPContext.
+// See (*CPlaneConn).CreatePDPContext for details.
+func CreatePDPContext(raddr net.Addr, c *CPlaneConn, ie ...*ies.IE) (*Session, error) {
+	return c.CreatePDPContext(raddr, ie...)
+}
+
+// DeletePDPContext is an alias for (*CPlaneConn).DeletePDPContext.
+// See (*CPlaneConn).DeletePDPContext for details.
+func DeletePDPContext(c *CPlaneConn, teid uint32, ie ...*ies.IE) error {
+	return c.DeletePDPContext(teid, ie...)
+}
+
+// Delete sends a Delete PDP Context Request toward the interface which
+// is specified with c and ifType.
+//
+// By default, IEs on the Delete PDP Context Request is only NSAPI of default
+// PDP context, but it can be overridden by giving NSAPI IE.
+// Also, other IEs can be added by giving them as ie.
+func (s *Session) Delete(c *CPlaneConn, ifType uint8, ie ...*ies.IE) error {
+	// do nothing for non-active Session
+	if !s.IsActive() {
+		return nil
+	}
+
+	teid, err := s.GetTEID(ifType)
+	if err != nil {
+		return err
+	}
+
+	// send NSAPI of default PDP context by default, but if the same type of
+	// IE is given, the default one is replaced.
+	ieToSend := []*ies.IE{ies.NewNSAPI(s.GetDefaultPDPContext().NSAPI)}
+	for _, i := range ie {
+		if i.Type == ies.NSAPI {
+			ieToSend[0] = i
+			continue
+		}
+		// other IEs given are just put regardless of their type.
+		ieToSend = append(ieToSend, i)
+	}
+
+	return c.DeletePDPContext(teid, ieToSend...)
+}
+
+// Update sends an Update PDP Context Request toward the interface which
+// is specified with c and ifType.
+func (s *Session) Update(c *CPlaneConn, ifType uint8, ie ...*ies.IE) error {
+	// do nothing for non-active Session
+	if !s.IsActive() {
+		return nil
+	}
+
+	teid, err := s.GetTEID(ifType)
+	if err != nil {
+		return err
+	}
+
+	return c.UpdatePDPContext(teid, ie...)
+}
+
+// Activate marks a Session active.
+func (s *Session) Activate() error {
+	if s.IMSI == "" {
+		return &ErrRequiredParameterMissing{"IMSI", "Session must have IMSI set"}
+	}
+
+	s.mu.Lock()
+	s.isActive = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Deactivate marks a Session inactive.
+func (s *Session) Deactivate() error {
+	s.mu.Lock()
+	s.isActive = false
+	s.mu.Unlock()
+	return nil
+}
+
+// IsActive reports whether a Session is active or not.
+func (s *Session) IsActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isActive
+}
+
+// AddTEID adds TEID to session with InterfaceType.
+//
+// The InterfaceType is one of the IFType* constants, which are used only for
+// identifying the TEID in the Session and never appear on the wire.
+func (s *Session) AddTEID(ifType uint8, teid uint32) {
+	s.teidMap.store(ifType, teid)
+}
+
+// GetTEID returns TEID associated with InterfaceType given.
+func (s *Session) GetTEID(ifType uint8) (uint32, error) {
+	if teid, ok := s.teidMap.load(ifType); ok {
+		return teid, nil
+	}
+	return 0, ErrTEIDNotFound
+}
+
+// PassMessageTo passes the message (typically "triggerred message") to the session
+// expecting to receive it.
+func PassMessageTo(s *Session, msg messages.Message, timeout time.Duration) error {
+	select {
+	case s.inflightCh <- msg:
+		return nil
+	case <-time.After(timeout):
+		return ErrTimeout
+	}
+}
+
+// WaitMessage waits for a message to come.
+// Unless the user does not use PassMessage() func, this always fails with timeout.
+func (s *Session) WaitMessage(timeout time.Duration) (messages.Message, error) {
+	select {
+	case msg := <-s.inflightCh:
+		return msg, nil
+	case <-time.After(timeout):
+		return nil, ErrTimeout
+	}
+}
+
+// AddPDPContext adds a PDPContext to Session with arbitrary name given.
+//
+// In the single-context environment it is not used, as a PDP context named "default"
+// is always available after created a Session.
+func (s *Session) AddPDPContext(name string, pdp *PDPContext) {
+	s.pdpContextMap.store(name, pdp)
+}
+
+// RemovePDPContext removes a PDPContext looked up by name.
+func (s *Session) RemovePDPContext(name string) {
+	s.pdpContextMap.delete(name)
+}
+
+// RemovePDPContextByNSAPI removes a PDPContext looked up by NSAPI.
+func (s *Session) RemovePDPContextByNSAPI(nsapi uint8) {
+	name, err := s.LookupPDPContextNameByNSAPI(nsapi)
+	if err != nil {
+		return
+	}
+	s.pdpContextMap.delete(name)
+}
+
+// GetDefaultPDPContext returns the pointer to default PDP context.
+func (s *Session) GetDefaultPDPContext() *PDPContext {
+	// it is not expected that the default PDP context cannot be found.
+	pdp, ok := s.pdpContextMap.load("default")
+	if !ok {
+		return nil
+	}
+
+	return pdp
+}
+
+// SetDefaultPDPContext sets given PDP context as the default one.
+func (s *Session) SetDefaultPDPContext(pdp *PDPContext) {
+	s.pdpContextMap.store("default", pdp)
+}
+
+// LookupPDPContextByName looks up PDPContext registered in Session by name.
+func (s *Session) LookupPDPContextByName(name string) (*PDPContext, error) {
+	if pdp, ok := s.pdpContextMap.load(name); ok {
+		return pdp, nil
+	}
+
+	return nil, ErrNoPDPContextFound
+}
+
+// LookupPDPContextByNSAPI looks up PDPContext registered in Session by NSAPI.
+func (s *Session) LookupPDPContextByNSAPI(nsapi uint8) (*PDPContext, error) {
+	var pdpContext *PDPContext
+	s.pdpContextMap.rangeWithFunc(func(name, pdp interface{}) bool {
+		p := pdp.(*PDPContext)
+		if nsapi == p.NSAPI {
+			pdpContext = p
+			return false
+		}
+		return true
+	})
+
+	if pdpContext == nil {
+		return nil, ErrNoPDPContextFound
+	}
+	return pdpContext, nil
+}
+
+// LookupPDPContextNameByNSAPI looks up name of PDPContext by NSAPI.
+func (s *Session) LookupPDPContextNameByNSAPI(nsapi uint8) (string, error) {
+	var name string
+	s.pdpContextMap.rangeWithFunc(func(n, pdp interface{}) bool {
+		if nsapi == pdp.(*PDPContext).NSAPI {
+			name = n.(string)
+			return false
+		}
+		return true
+	})
+
+	if name == "" {
+		return "", ErrNoPDPContextFound
+	}
+	return name, nil
+}
+
+// LookupNSAPIByTEID returns NSAPI associated with TEID given.
+//
+// If no NSAPI found, it returns 0(invalid value for NSAPI).
+func (s *Session) LookupNSAPIByTEID(teid uint32) uint8 {
+	var nsapi uint8
+	s.pdpContextMap.rangeWithFunc(func(name, pdp interface{}) bool {
+		p := pdp.(*PDPContext)
+		if teid == p.teidIn || teid == p.teidOut {
+			nsapi = p.NSAPI
+			return false
+		}
+		return true
+	})
+
+	return nsapi
+}
+
+type teidMap struct {
+	syncMap sync.Map
+}
+
+func newTeidMap() *teidMap {
+	return &teidMap{}
+}
+
+func (t *teidMap) store(ifType uint8, teid uint32) {
+	t.syncMap.Store(ifType, teid)
+}
+
+func (t *teidMap) load(ifType uint8) (uint32, bool) {
+	teid, ok := t.syncMap.Load(ifType)
+	if !ok {
+		return 0, false
+	}
+
+	return teid.(uint32), true
+}
+
+func (t *teidMap) rangeWithFunc(fn func(ifType, teid interface{}) bool) {
+	t.syncMap.Range(fn)
+}
+
+type pdpContextMap struct {
+	syncMap sync.Map
+}
+
+func newPDPContextMap(name string, pdp *PDPContext) *pdpContextMap {
+	p := &pdpContextMap{}
+	p.store(name, pdp)
+
+	return p
+}
+
+func (p *pdpContextMap) store(name string, pdp *PDPContext) {
+	p.syncMap.Store(name, pdp)
+}
+
+func (p *pdpContextMap) load(name string) (*PDPContext, bool) {
+	pdp, ok := p.syncMap.Load(name)
+	if !ok {
+		return nil, false
+	}
+
+	return pdp.(*PDPContext), true
+}
+
+func (p *pdpContextMap) delete(name string) {
+	p.syncMap.Delete(name)
+}
+
+func (p *pdpContextMap) rangeWithFunc(fn func(name, pdp interface{}) bool) {
+	p.syncMap.Range(fn)
+}
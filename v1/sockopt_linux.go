@@ -0,0 +1,122 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package v1
+
+import (
+	"net"
+	"syscall"
+)
+
+// sockoptSupported tells the socket options other than the DF bit are available.
+const sockoptSupported = true
+
+// setDontFragment turns on/off the DF bit of the outgoing IPv4 packets by
+// changing the Path MTU Discovery setting of the socket. For IPv6, the setting
+// also decides whether the packets exceeding the path MTU are fragmented by the
+// host, instead of being rejected with EMSGSIZE.
+func setDontFragment(conn net.PacketConn, df bool) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return ErrInvalidConnection
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	opt, opt6 := syscall.IP_PMTUDISC_DONT, syscall.IPV6_PMTUDISC_DONT
+	if df {
+		opt, opt6 = syscall.IP_PMTUDISC_DO, syscall.IPV6_PMTUDISC_DO
+	}
+	isV6 := true
+	if laddr, ok := conn.LocalAddr().(*net.UDPAddr); ok && laddr.IP.To4() != nil {
+		isV6 = false
+	}
+
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		// the IPv4 one is also set on IPv6 sockets for IPv4-mapped addresses.
+		if serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, opt); serr != nil {
+			return
+		}
+		if !isV6 {
+			return
+		}
+		serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, opt6)
+	}); err != nil {
+		return err
+	}
+	return serr
+}
+
+// socket options for UDP over IPv6, not defined in syscall package.
+const (
+	udpNoCheck6TX = 101
+	udpNoCheck6RX = 102
+)
+
+// setZeroUDPChecksum turns on/off the zero checksum on UDP packets sent from
+// the socket. For IPv6, zero checksum in the packets received is also allowed.
+func setZeroUDPChecksum(conn net.PacketConn, zero bool) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return ErrInvalidConnection
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	val := 0
+	if zero {
+		val = 1
+	}
+	isV6 := true
+	if laddr, ok := conn.LocalAddr().(*net.UDPAddr); ok && laddr.IP.To4() != nil {
+		isV6 = false
+	}
+
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		if serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_NO_CHECK, val); serr != nil {
+			return
+		}
+		if !isV6 {
+			return
+		}
+		if serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_UDP, udpNoCheck6TX, val); serr != nil {
+			return
+		}
+		serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_UDP, udpNoCheck6RX, val)
+	}); err != nil {
+		return err
+	}
+	return serr
+}
+
+// bindToDevice binds the socket to the network device or VRF specified by name,
+// so that the packets are sent and received only through the device. An empty
+// name removes the binding.
+func bindToDevice(conn net.PacketConn, name string) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return ErrInvalidConnection
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		serr = syscall.BindToDevice(int(fd), name)
+	}); err != nil {
+		return err
+	}
+	return serr
+}
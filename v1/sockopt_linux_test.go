@@ -0,0 +1,69 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package v1_test
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+func TestMTUPolicyFragmentSockopt(t *testing.T) {
+	cases := []struct {
+		description string
+		network     string
+		addr        string
+	}{
+		{"IPv4", "udp4", "127.0.0.115:2152"},
+		{"IPv6", "udp6", "[::1]:2152"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			pktConn, err := net.ListenPacket(c.network, c.addr)
+			if err != nil {
+				t.Skipf("failed to listen on %s: %v", c.addr, err)
+			}
+			uConn := v1.NewUPlaneConn(pktConn, 0, make(chan error))
+			defer uConn.Close()
+
+			if err := uConn.SetMTUPolicy(1500, v1.MTUPolicyFragment); err != nil {
+				t.Fatal(err)
+			}
+
+			rc, err := pktConn.(*net.UDPConn).SyscallConn()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var opt, opt6 int
+			var serr error
+			if err := rc.Control(func(fd uintptr) {
+				if opt, serr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER); serr != nil {
+					return
+				}
+				if c.network == "udp6" {
+					opt6, serr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER)
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if serr != nil {
+				t.Fatal(serr)
+			}
+
+			if opt != syscall.IP_PMTUDISC_DONT {
+				t.Errorf("got unexpected IP_MTU_DISCOVER: %d", opt)
+			}
+			if c.network == "udp6" && opt6 != syscall.IPV6_PMTUDISC_DONT {
+				t.Errorf("got unexpected IPV6_MTU_DISCOVER: %d", opt6)
+			}
+		})
+	}
+}
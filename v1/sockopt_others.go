@@ -0,0 +1,38 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package v1
+
+import "net"
+
+// sockoptSupported tells the socket options other than the DF bit are available.
+const sockoptSupported = false
+
+// setDontFragment does nothing on the platforms other than Linux, as the DF bit
+// is not set on the UDP packets by default.
+func setDontFragment(conn net.PacketConn, df bool) error {
+	if df {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// setZeroUDPChecksum is not supported on the platforms other than Linux.
+func setZeroUDPChecksum(conn net.PacketConn, zero bool) error {
+	if zero {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// bindToDevice is not supported on the platforms other than Linux.
+func bindToDevice(conn net.PacketConn, name string) error {
+	if name != "" {
+		return ErrNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
+)
+
+// DropReason is the reason why a packet received on UPlaneConn is dropped.
+type DropReason uint8
+
+// DropReason definitions.
+const (
+	// DropMalformed is the packet that cannot be decoded as GTPv1-U.
+	DropMalformed DropReason = iota
+	// DropPolicer is the T-PDU exceeding the rate of the Policer.
+	DropPolicer
+	// DropRule is the T-PDU dropped by ActionDrop of the ForwardingTable.
+	DropRule
+	// DropBufferFull is the T-PDU to be buffered when the buffer is full.
+	DropBufferFull
+	// DropTooBig is the T-PDU exceeding the MTU set with SetMTUPolicy.
+	DropTooBig
+
+	numDropReasons
+)
+
+// String returns the name of the DropReason.
+func (r DropReason) String() string {
+	switch r {
+	case DropMalformed:
+		return "malformed"
+	case DropPolicer:
+		return "policer"
+	case DropRule:
+		return "rule"
+	case DropBufferFull:
+		return "buffer_full"
+	case DropTooBig:
+		return "too_big"
+	default:
+		return "unknown"
+	}
+}
+
+// MaxStatsPeers is the maximum number of peers whose packets are counted separately
+// in UPlaneStats. The packets from/to the peers beyond it are counted only in total,
+// not to let the garbage from random sources grow the statistics unboundedly.
+const MaxStatsPeers = 4096
+
+// PeerStats is the number of packets and bytes received from and sent to a peer.
+// The bytes are the size of the UDP payload, including GTP-U header.
+type PeerStats struct {
+	ReceivedPackets, ReceivedBytes uint64
+	SentPackets, SentBytes         uint64
+}
+
+// UPlaneStats is the statistics of a UPlaneConn.
+type UPlaneStats struct {
+	// Total is the number of packets and bytes received and sent on the UPlaneConn,
+	// including the ones other than T-PDU such as Echo.
+	Total PeerStats
+	// Peers is the PeerStats by the address of the peer.
+	Peers map[string]PeerStats
+	// Dropped is the number of packets dropped by the reason.
+	Dropped map[DropReason]uint64
+	// Relays is the number of relay entries registered with RelayTo and the variants.
+	Relays int
+	// BufferedPackets is the number of packets currently buffered by ActionBuffer,
+	// in BufferedTEIDs TEIDs.
+	BufferedPackets, BufferedTEIDs int
+}
+
+// Stats returns the statistics of the UPlaneConn so far.
+func (u *UPlaneConn) Stats() *UPlaneStats {
+	s := &UPlaneStats{
+		Total:   u.stats.total.snapshot(),
+		Peers:   u.stats.peerSnapshot(),
+		Dropped: map[DropReason]uint64{},
+	}
+	for i := range u.stats.dropped {
+		if n := u.stats.dropped[i].Load(); n > 0 {
+			s.Dropped[DropReason(i)] = n
+		}
+	}
+
+	u.relayMu.RLock()
+	s.Relays = len(u.relayMap) + len(u.qfiRelayMap)
+	u.relayMu.RUnlock()
+
+	u.bufMu.Lock()
+	for _, pkts := range u.bufMap {
+		if len(pkts) > 0 {
+			s.BufferedTEIDs++
+			s.BufferedPackets += len(pkts)
+		}
+	}
+	u.bufMu.Unlock()
+
+	return s
+}
+
+// peerCounters counts the packets of a peer without locking.
+type peerCounters struct {
+	rcvPackets, rcvBytes atomic.Uint64
+	sndPackets, sndBytes atomic.Uint64
+}
+
+func (p *peerCounters) snapshot() PeerStats {
+	return PeerStats{
+		ReceivedPackets: p.rcvPackets.Load(),
+		ReceivedBytes:   p.rcvBytes.Load(),
+		SentPackets:     p.sndPackets.Load(),
+		SentBytes:       p.sndBytes.Load(),
+	}
+}
+
+type uplaneStats struct {
+	total   peerCounters
+	dropped [numDropReasons]atomic.Uint64
+
+	mu    sync.RWMutex
+	peers map[sendbuf.Key]*peerEntry
+}
+
+type peerEntry struct {
+	peerCounters
+	addr string
+}
+
+// peer returns the counters of the peer, creating it if not exists and the number
+// of the peers doesn't exceed MaxStatsPeers. nil is returned otherwise.
+func (s *uplaneStats) peer(addr net.Addr) *peerCounters {
+	key := sendbuf.KeyOf(addr)
+
+	s.mu.RLock()
+	e, ok := s.peers[key]
+	s.mu.RUnlock()
+	if ok {
+		return &e.peerCounters
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.peers[key]; ok {
+		return &e.peerCounters
+	}
+	if len(s.peers) >= MaxStatsPeers {
+		return nil
+	}
+	if s.peers == nil {
+		s.peers = map[sendbuf.Key]*peerEntry{}
+	}
+	e = &peerEntry{addr: addr.String()}
+	s.peers[key] = e
+	return &e.peerCounters
+}
+
+func (s *uplaneStats) peerSnapshot() map[string]PeerStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	peers := make(map[string]PeerStats, len(s.peers))
+	for _, e := range s.peers {
+		peers[e.addr] = e.snapshot()
+	}
+	return peers
+}
+
+func (s *uplaneStats) received(addr net.Addr, size int) {
+	s.total.rcvPackets.Add(1)
+	s.total.rcvBytes.Add(uint64(size))
+	if p := s.peer(addr); p != nil {
+		p.rcvPackets.Add(1)
+		p.rcvBytes.Add(uint64(size))
+	}
+}
+
+func (s *uplaneStats) sent(addr net.Addr, size int) {
+	s.total.sndPackets.Add(1)
+	s.total.sndBytes.Add(uint64(size))
+	if p := s.peer(addr); p != nil {
+		p.sndPackets.Add(1)
+		p.sndBytes.Add(uint64(size))
+	}
+}
+
+func (s *uplaneStats) drop(reason DropReason) {
+	s.dropped[reason].Add(1)
+}
@@ -0,0 +1,251 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tun
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+var (
+	// ErrNotSupported indicates that the operation is not supported on the platform.
+	ErrNotSupported = errors.New("not supported on this platform")
+
+	// ErrBridgeClosed indicates that the Bridge is already closed.
+	ErrBridgeClosed = errors.New("bridge closed")
+)
+
+// Session is a set of information to encapsulate the downlink packets toward a UE,
+// and to check the uplink packets from the UE.
+type Session struct {
+	UEIP net.IP
+	// TEIDIn is the TEID of the uplink T-PDUs the UE is allowed to send from UEIP.
+	TEIDIn uint32
+	// TEID is the TEID of the downlink T-PDUs sent to the Peer.
+	TEID uint32
+	Peer net.Addr
+}
+
+// Bridge bridges a UPlaneConn and a TUN device.
+type Bridge struct {
+	uConn *v1.UPlaneConn
+	dev   io.ReadWriteCloser
+
+	mu       sync.RWMutex
+	sessions map[string]*Session
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+
+	// AllowUnknownSource makes Bridge write the uplink packets to the device even
+	// if their source address is not registered with AddSession for the TEID they
+	// are received with. It is false by default to prevent UEs from spoofing the
+	// source address.
+	AllowUnknownSource bool
+
+	// RouterAdvertiser answers the Router Solicitations from the UEs of the IPv6 PDN
+	// connections instead of writing them to the device, if set.
+	RouterAdvertiser *v1.RouterAdvertiser
+}
+
+// NewBridge creates a new Bridge between uConn and dev.
+// dev should read and write raw IP packets, without any header(IFF_NO_PI).
+func NewBridge(uConn *v1.UPlaneConn, dev io.ReadWriteCloser) *Bridge {
+	return &Bridge{
+		uConn:    uConn,
+		dev:      dev,
+		sessions: map[string]*Session{},
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// AddSession associates the UE IP address with the TEID and address of the peer, to
+// which the downlink packets destined for the UE are sent with teidOut. The uplink
+// packets from the UE IP address are accepted only if received with teidIn.
+// If the UE IP address is already registered, the old one is replaced.
+//
+// The IPv6 address is associated by the /64 prefix, as the UE forms its addresses in
+// the prefix delegated, e.g., the Prefix of the ipam.Lease.
+func (b *Bridge) AddSession(ueIP net.IP, teidIn, teidOut uint32, peer net.Addr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sessions[sessionKey(ueIP)] = &Session{UEIP: ueIP, TEIDIn: teidIn, TEID: teidOut, Peer: peer}
+}
+
+// RemoveSession removes the session associated with the UE IP address.
+func (b *Bridge) RemoveSession(ueIP net.IP) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionKey(ueIP))
+}
+
+// GetSession returns the session associated with the UE IP address.
+func (b *Bridge) GetSession(ueIP net.IP) (*Session, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	s, ok := b.sessions[sessionKey(ueIP)]
+	return s, ok
+}
+
+// Serve starts bridging packets and blocks until an error occurs or Close is called.
+// It returns nil if the Bridge is closed by Close.
+func (b *Bridge) Serve() error {
+	errCh := make(chan error, 2)
+	go func() { errCh <- b.serveUplink() }()
+	go func() { errCh <- b.serveDownlink() }()
+
+	select {
+	case err := <-errCh:
+		select {
+		case <-b.closeCh:
+			return nil
+		default:
+			return err
+		}
+	case <-b.closeCh:
+		return nil
+	}
+}
+
+// Close stops bridging and closes the device. UPlaneConn is not closed, but the
+// deadline of its ReadFromGTP is set to unblock the Bridge reading from it, which
+// should be reset with SetGTPReadDeadline to keep reading from it afterwards.
+func (b *Bridge) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.closeCh)
+		err = b.uConn.SetGTPReadDeadline(time.Now())
+		if cerr := b.dev.Close(); cerr != nil {
+			err = cerr
+		}
+	})
+	return err
+}
+
+func (b *Bridge) closed() bool {
+	select {
+	case <-b.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// serveUplink decapsulates the T-PDUs from UPlaneConn and writes them to the device.
+func (b *Bridge) serveUplink() error {
+	buf := make([]byte, 2048)
+	for {
+		if b.closed() {
+			return ErrBridgeClosed
+		}
+
+		n, _, teid, err := b.uConn.ReadFromGTP(buf)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			continue
+		}
+		if ra := b.RouterAdvertiser; ra != nil {
+			// the failure to answer is not fatal, as the UE solicits again.
+			if handled, _ := ra.HandleUplink(buf[:n], teid); handled {
+				continue
+			}
+		}
+
+		if !b.AllowUnknownSource {
+			src, ok := sourceIP(buf[:n])
+			if !ok {
+				continue
+			}
+			if sess, ok := b.GetSession(src); !ok || sess.TEIDIn != teid {
+				continue
+			}
+		}
+
+		if _, err := b.dev.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+}
+
+// serveDownlink reads the packets from the device and sends them encapsulated to the peer.
+func (b *Bridge) serveDownlink() error {
+	buf := make([]byte, 2048)
+	for {
+		n, err := b.dev.Read(buf)
+		if err != nil {
+			return err
+		}
+
+		dst, ok := destinationIP(buf[:n])
+		if !ok {
+			continue
+		}
+		sess, ok := b.GetSession(dst)
+		if !ok {
+			continue
+		}
+
+		if _, err := b.uConn.WriteToGTP(sess.TEID, buf[:n], sess.Peer); err != nil {
+			if _, ok := err.(*v1.ErrTooBig); ok {
+				continue
+			}
+			return err
+		}
+	}
+}
+
+// sessionKey returns the key of the session of the UE IP address, which is the /64
+// prefix for IPv6.
+func sessionKey(ip net.IP) string {
+	if ip.To4() == nil {
+		return ip.Mask(net.CIDRMask(64, 128)).String()
+	}
+	return ip.String()
+}
+
+func sourceIP(b []byte) (net.IP, bool) {
+	if len(b) < 1 {
+		return nil, false
+	}
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < 20 {
+			return nil, false
+		}
+		return net.IP(b[12:16]), true
+	case 6:
+		if len(b) < 40 {
+			return nil, false
+		}
+		return net.IP(b[8:24]), true
+	}
+	return nil, false
+}
+
+func destinationIP(b []byte) (net.IP, bool) {
+	if len(b) < 1 {
+		return nil, false
+	}
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < 20 {
+			return nil, false
+		}
+		return net.IP(b[16:20]), true
+	case 6:
+		if len(b) < 40 {
+			return nil, false
+		}
+		return net.IP(b[24:40]), true
+	}
+	return nil, false
+}
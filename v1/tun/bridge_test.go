@@ -0,0 +1,257 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tun_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/tun"
+)
+
+// fakeDevice is an io.ReadWriteCloser that works like a TUN device.
+type fakeDevice struct {
+	toHost, fromHost chan []byte
+	closeCh          chan struct{}
+}
+
+func (d *fakeDevice) Read(p []byte) (int, error) {
+	select {
+	case b := <-d.fromHost:
+		return copy(p, b), nil
+	case <-d.closeCh:
+		return 0, io.EOF
+	}
+}
+
+func (d *fakeDevice) Write(p []byte) (int, error) {
+	d.toHost <- append([]byte{}, p...)
+	return len(p), nil
+}
+
+func (d *fakeDevice) Close() error {
+	close(d.closeCh)
+	return nil
+}
+
+func newIPv4Packet(src, dst net.IP) []byte {
+	b := make([]byte, 24)
+	b[0] = 0x45
+	b[3] = 24
+	b[9] = 17
+	copy(b[12:16], src.To4())
+	copy(b[16:20], dst.To4())
+	return b
+}
+
+func newIPv6Packet(src, dst net.IP, nextHeader, icmpType uint8) []byte {
+	b := make([]byte, 48)
+	b[0] = 0x60
+	b[5] = 8
+	b[6], b[7] = nextHeader, 255
+	copy(b[8:24], src.To16())
+	copy(b[24:40], dst.To16())
+	b[40] = icmpType
+	return b
+}
+
+func TestBridge(t *testing.T) {
+	pgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.51:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.52:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pgwConn, err := v1.ListenAndServeUPlane(pgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgwConn.Close()
+	sgwConn, err := v1.ListenAndServeUPlane(sgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sgwConn.Close()
+
+	dev := &fakeDevice{
+		toHost:   make(chan []byte),
+		fromHost: make(chan []byte),
+		closeCh:  make(chan struct{}),
+	}
+	bridge := tun.NewBridge(pgwConn, dev)
+	defer bridge.Close()
+
+	ueIP, hostIP := net.ParseIP("10.0.0.1"), net.ParseIP("192.168.0.1")
+	bridge.AddSession(ueIP, 0x11111111, 0x22222222, sgwAddr)
+	go bridge.Serve()
+
+	// uplink; the one from the UE IP with the TEID of another session is dropped.
+	spoofed := newIPv4Packet(ueIP, net.ParseIP("192.168.0.2"))
+	if _, err := sgwConn.WriteToGTP(0x33333333, spoofed, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	ul := newIPv4Packet(ueIP, hostIP)
+	if _, err := sgwConn.WriteToGTP(0x11111111, ul, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-dev.toHost:
+		if diff := cmp.Diff(got, ul); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for uplink packet")
+	}
+	select {
+	case got := <-dev.toHost:
+		t.Errorf("got unexpected uplink packet: %x", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// downlink
+	dl := newIPv4Packet(hostIP, ueIP)
+	dev.fromHost <- dl
+
+	buf := make([]byte, 1500)
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		n, _, teid, err := sgwConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if teid != 0x22222222 {
+			t.Errorf("got unexpected TEID: %#x", teid)
+		}
+		if diff := cmp.Diff(buf[:n], dl); diff != "" {
+			t.Error(diff)
+		}
+	}()
+	select {
+	case <-doneCh:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for downlink packet")
+	}
+
+	// Bridge stops reading from UPlaneConn when closed, and the T-PDUs can be read
+	// by others after the deadline is reset.
+	if err := bridge.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := pgwConn.ReadFromGTP(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+	if err := pgwConn.SetGTPReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sgwConn.WriteToGTP(0x11111111, ul, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	n, _, teid, err := pgwConn.ReadFromGTP(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(buf[:n], ul); teid != 0x11111111 || diff != "" {
+		t.Errorf("got unexpected T-PDU with TEID %#x: %s", teid, diff)
+	}
+}
+
+func TestBridgeIPv6(t *testing.T) {
+	pgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.97:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.98:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pgwConn, err := v1.ListenAndServeUPlane(pgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pgwConn.Close()
+	sgwConn, err := v1.ListenAndServeUPlane(sgwAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sgwConn.Close()
+
+	dev := &fakeDevice{
+		toHost:   make(chan []byte),
+		fromHost: make(chan []byte),
+		closeCh:  make(chan struct{}),
+	}
+	bridge := tun.NewBridge(pgwConn, dev)
+	defer bridge.Close()
+
+	prefix := netip.MustParsePrefix("2001:db8:1:2::/64")
+	bridge.RouterAdvertiser = v1.NewRouterAdvertiser(pgwConn)
+	if err := bridge.RouterAdvertiser.AddTunnel(0x11111111, prefix, 0x22222222, sgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	bridge.AddSession(net.IP(prefix.Addr().AsSlice()), 0x11111111, 0x22222222, sgwAddr)
+	go bridge.Serve()
+
+	buf := make([]byte, 1500)
+	readDownlink := func() []byte {
+		t.Helper()
+
+		_ = sgwConn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		n, _, teid, err := sgwConn.ReadFromGTP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if teid != 0x22222222 {
+			t.Errorf("got unexpected TEID: %#x", teid)
+		}
+		return buf[:n]
+	}
+	if got := readDownlink(); got[40] != 134 {
+		t.Errorf("got unexpected packet instead of Router Advertisement: %x", got)
+	}
+
+	// Router Solicitation is answered without being written to the device.
+	rs := newIPv6Packet(net.ParseIP("fe80::abcd"), net.ParseIP("ff02::2"), 58, 133)
+	if _, err := sgwConn.WriteToGTP(0x11111111, rs, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	if got := readDownlink(); got[40] != 134 {
+		t.Errorf("got unexpected packet instead of Router Advertisement: %x", got)
+	}
+
+	// the addresses formed by the UE in the prefix are bridged.
+	ueIP, hostIP := net.ParseIP("2001:db8:1:2::abcd"), net.ParseIP("2001:db8:ffff::1")
+	ul := newIPv6Packet(ueIP, hostIP, 17, 0)
+	if _, err := sgwConn.WriteToGTP(0x11111111, ul, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-dev.toHost:
+		if diff := cmp.Diff(got, ul); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for uplink packet")
+	}
+
+	dl := newIPv6Packet(hostIP, ueIP, 17, 0)
+	dev.fromHost <- dl
+	if diff := cmp.Diff(readDownlink(), dl); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package tun provides a Bridge between v1.UPlaneConn and a TUN device, which
+// terminates GTP-U tunnels toward the IP network, e.g., SGi interface of P-GW.
+//
+// The uplink T-PDUs received on UPlaneConn are decapsulated and written to the
+// device, and the packets read from the device are encapsulated and sent to the
+// peer associated with the destination(UE) IP address.
+//
+//	dev, err := tun.Open("gtp-sgi")
+//	if err != nil {
+//		// ...
+//	}
+//
+//	bridge := tun.NewBridge(uConn, dev)
+//	// associate UE IP with the TEIDs and address of the peer(e.g., S-GW).
+//	bridge.AddSession(ueIP, teidIn, teidOut, sgwAddr)
+//	go func() {
+//		if err := bridge.Serve(); err != nil {
+//			// ...
+//		}
+//	}()
+//
+// Configuring addresses and routes on the device is out of the scope of this package.
+// Opening TUN device is supported on Linux and macOS(utun), which can be checked
+// with Supported, while Bridge works with any io.ReadWriteCloser that reads and
+// writes raw IP packets on any platform.
+package tun
@@ -0,0 +1,139 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build darwin
+// +build darwin
+
+package tun
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	sysprotoControl = 2
+	utunOptIfname   = 2
+	utunControlName = "com.apple.net.utun_control"
+
+	// utunHeaderLen is the length of the protocol family prepended to the packets.
+	utunHeaderLen = 4
+)
+
+// Device is a TUN device.
+//
+// On macOS, this is a utun device, and the protocol family prepended to each packet
+// by the kernel is removed on Read and added on Write, to read and write raw IP
+// packets as on Linux.
+type Device struct {
+	*os.File
+	name string
+
+	rmu, wmu sync.Mutex
+	rbuf     []byte
+	wbuf     []byte
+}
+
+// Open opens or creates a TUN device with the name given, which is configured to
+// read and write raw IP packets without any header.
+//
+// On macOS, the name must be "utunN" or empty. If the name is empty, the kernel
+// chooses the name, which can be retrieved by Name().
+func Open(name string) (*Device, error) {
+	var unit uint32
+	if name != "" {
+		n, err := strconv.ParseUint(strings.TrimPrefix(name, "utun"), 10, 31)
+		if err != nil || !strings.HasPrefix(name, "utun") {
+			return nil, fmt.Errorf("invalid utun device name: %s", name)
+		}
+		unit = uint32(n) + 1
+	}
+
+	fd, err := unix.Socket(unix.AF_SYSTEM, unix.SOCK_DGRAM, sysprotoControl)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &unix.CtlInfo{}
+	copy(info.Name[:], utunControlName)
+	if err := unix.IoctlCtlInfo(fd, info); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	if err := unix.Connect(fd, &unix.SockaddrCtl{ID: info.Id, Unit: unit}); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	ifName, err := unix.GetsockoptString(fd, sysprotoControl, utunOptIfname)
+	if err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+
+	return &Device{File: os.NewFile(uintptr(fd), ifName), name: ifName}, nil
+}
+
+// Supported reports whether Open is available on the platform.
+func Supported() bool {
+	return true
+}
+
+// Name returns the name of the device.
+func (d *Device) Name() string {
+	return d.name
+}
+
+// Read reads a raw IP packet from the device.
+func (d *Device) Read(p []byte) (int, error) {
+	d.rmu.Lock()
+	defer d.rmu.Unlock()
+
+	if cap(d.rbuf) < len(p)+utunHeaderLen {
+		d.rbuf = make([]byte, len(p)+utunHeaderLen)
+	}
+	n, err := d.File.Read(d.rbuf[:len(p)+utunHeaderLen])
+	if n < utunHeaderLen {
+		if err == nil {
+			err = errors.New("too short packet read from utun device")
+		}
+		return 0, err
+	}
+	return copy(p, d.rbuf[utunHeaderLen:n]), err
+}
+
+// Write writes a raw IP packet to the device.
+func (d *Device) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	d.wmu.Lock()
+	defer d.wmu.Unlock()
+
+	if cap(d.wbuf) < len(p)+utunHeaderLen {
+		d.wbuf = make([]byte, len(p)+utunHeaderLen)
+	}
+	b := d.wbuf[:len(p)+utunHeaderLen]
+
+	family := unix.AF_INET
+	if p[0]>>4 == 6 {
+		family = unix.AF_INET6
+	}
+	b[0], b[1], b[2], b[3] = 0, 0, 0, byte(family)
+	copy(b[utunHeaderLen:], p)
+
+	n, err := d.File.Write(b)
+	return max(n-utunHeaderLen, 0), err
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package tun
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	iffTUN    = 0x0001
+	iffNoPI   = 0x1000
+	tunSetIFF = 0x400454ca
+)
+
+// Device is a TUN device.
+type Device struct {
+	*os.File
+	name string
+}
+
+// Open opens or creates a TUN device with the name given, which is configured to
+// read and write raw IP packets without any header.
+//
+// If the name is empty, the kernel chooses the name, which can be retrieved by Name().
+func Open(name string) (*Device, error) {
+	f, err := os.OpenFile("/dev/net/tun", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// struct ifreq with ifr_name and ifr_flags.
+	var ifr [40]byte
+	copy(ifr[:16], name)
+	*(*uint16)(unsafe.Pointer(&ifr[16])) = iffTUN | iffNoPI
+
+	if _, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL, f.Fd(), tunSetIFF, uintptr(unsafe.Pointer(&ifr[0])),
+	); errno != 0 {
+		f.Close()
+		return nil, errno
+	}
+
+	return &Device{File: f, name: strings.TrimRight(string(ifr[:16]), "\x00")}, nil
+}
+
+// Supported reports whether Open is available on the platform.
+func Supported() bool {
+	return true
+}
+
+// Name returns the name of the device.
+func (d *Device) Name() string {
+	return d.name
+}
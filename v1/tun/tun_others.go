@@ -0,0 +1,31 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package tun
+
+import "os"
+
+// Device is a TUN device.
+type Device struct {
+	*os.File
+	name string
+}
+
+// Open is not supported on the platforms other than Linux.
+func Open(name string) (*Device, error) {
+	return nil, ErrNotSupported
+}
+
+// Supported reports whether Open is available on the platform.
+func Supported() bool {
+	return false
+}
+
+// Name returns the name of the device.
+func (d *Device) Name() string {
+	return d.name
+}
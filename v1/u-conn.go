@@ -7,10 +7,14 @@ package v1
 import (
 	"encoding/binary"
 	"net"
+	"net/netip"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
 	"github.com/wmnsk/go-gtp/v1/ies"
 	"github.com/wmnsk/go-gtp/v1/messages"
 )
@@ -33,7 +37,39 @@ type UPlaneConn struct {
 	closeCh chan struct{}
 	errCh   chan error
 
-	relayMap map[uint32]*peer
+	// the deadline of ReadFromGTP, and the channel closed when it is changed.
+	gtpReadMu       sync.Mutex
+	gtpReadDeadline time.Time
+	gtpReadChanged  chan struct{}
+
+	relayMu      sync.RWMutex
+	relayMap     map[relayKey]*peer
+	relayKeyMode RelayKeyMode
+	peerRelays   int
+	qfiRelayMap  map[qfiKey]*peer
+	fwdTimers    map[uint32]*forwardingTimer
+	fwdTable     ForwardingTable
+
+	bufMu         sync.Mutex
+	bufMap        map[uint32][]*bufferedPacket
+	dataWaitingFn DataWaitingHandlerFunc
+
+	policerMap map[uint32]*Policer
+
+	counterMu     sync.RWMutex
+	rcvCounterMap map[uint32]counterEntry
+	sndCounterMap map[uint32]counterEntry
+
+	mtu       int
+	mtuPolicy MTUPolicy
+
+	capturer atomic.Pointer[capturer]
+
+	sendBufs  sendbuf.Buffers
+	stats     uplaneStats
+	malformed malformedReporter
+
+	extNotifier extHeaderNotifier
 
 	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
 	// times the GTPv2-C endpoint is restarted.
@@ -97,6 +133,30 @@ func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlane
 	return u, nil
 }
 
+// NewUPlaneConn creates a new UPlaneConn over existing net.PacketConn and start serving.
+//
+// This is for special situation that the user already have a net.PacketConn to be used for
+// GTPv1-U connection, e.g., the one that bypasses the UDP socket of the kernel.
+// Otherwise, DialUPlane() or ListenAndServeUPlane() should be used to create a UPlaneConn.
+func NewUPlaneConn(pktConn net.PacketConn, counter uint8, errCh chan error) *UPlaneConn {
+	u := &UPlaneConn{
+		mu:            sync.Mutex{},
+		pktConn:       pktConn,
+		msgHandlerMap: defaultHandlerMap,
+
+		rcvBuf: make([]byte, 2048),
+
+		tpduCh:  make(chan *tpduSet),
+		closeCh: make(chan struct{}),
+		errCh:   errCh,
+
+		RestartCounter: counter,
+	}
+
+	go u.serve()
+	return u
+}
+
 // ListenAndServeUPlane creates a new GTPv2-C *Conn and start serving.
 func ListenAndServeUPlane(laddr net.Addr, counter uint8, errCh chan error) (*UPlaneConn, error) {
 	u := &UPlaneConn{
@@ -142,41 +202,54 @@ func (u *UPlaneConn) serve() {
 			continue
 		}
 
-		payload := u.rcvBuf[:n]
+		// the message is passed to the handler running in another goroutine, so
+		// the IEs must not refer to rcvBuf which is overwritten by the next read.
+		payload := make([]byte, n)
+		copy(payload, u.rcvBuf[:n])
+
+		u.capture(DirectionInbound, raddr, payload)
+		u.stats.received(raddr, n)
 		msg, err := messages.Decode(payload)
 		if err != nil {
+			u.stats.drop(DropMalformed)
+			u.malformed.report(raddr, err)
 			continue
 		}
 
-		// just forward T-PDU instead of passing it to reader
-		// if relayer is configured.
-		if len(u.relayMap) != 0 {
-			// handle by handleMessage() if it's not T-PDU.
-			if msg.MessageType() != messages.MsgTypeTPDU {
-				if err := u.handleMessage(raddr, msg); err != nil {
-					// errors should be handled by user
-					go func() {
-						u.errCh <- err
-					}()
-					continue
-				}
+		if u.extNotifier.shouldNotify(payload) {
+			if err := u.SupportedExtensionHeadersNotification(raddr, msg); err != nil {
+				go func() {
+					u.errCh <- err
+				}()
 			}
+			continue
+		}
 
-			u.mu.Lock()
-			peer, ok := u.relayMap[msg.TEID()]
-			u.mu.Unlock()
-			if !ok {
+		if tpdu, ok := msg.(*messages.TPDU); ok {
+			u.countReceived(tpdu.TEID(), payload)
+			handled, err := u.applyForwardingTable(raddr, tpdu.TEID(), payload, tpdu.Decapsulate())
+			if err != nil && err != errDropped {
+				go func() {
+					u.errCh <- err
+				}()
+			}
+			if handled {
 				continue
 			}
+		}
 
-			// just use original packet not to get it slow.
-			binary.BigEndian.PutUint32(payload[4:8], peer.teid)
-			if _, err := peer.srcConn.WriteTo(payload, peer.addr); err != nil {
+		// just forward T-PDU instead of passing it to reader
+		// if relayer is configured for the TEID.
+		if msg.MessageType() == messages.MsgTypeTPDU && u.hasRelay() {
+			relayed, err := u.relay(raddr, msg.TEID(), payload)
+			if err != nil && err != errDropped {
 				go func() {
 					u.errCh <- err
 				}()
 			}
-			continue
+			if relayed {
+				continue
+			}
 		}
 
 		if err := u.handleMessage(raddr, msg); err != nil {
@@ -206,52 +279,122 @@ func (u *UPlaneConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
 // ReadFromGTP reads a packet from the connection, copying the payload without
 // GTP header into p. It returns the number of bytes copied into p, the return
 // address that was on the packet, TEID in the GTP header.
+//
+// ReadFromGTP can be made to time out and return os.ErrDeadlineExceeded after
+// a fixed time limit; see SetGTPReadDeadline.
 func (u *UPlaneConn) ReadFromGTP(p []byte) (n int, addr net.Addr, teid uint32, err error) {
+	for {
+		tpdu, changed, err := u.waitTPDU()
+		if changed {
+			continue
+		}
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		return copy(p, tpdu.payload), tpdu.raddr, tpdu.teid, nil
+	}
+}
+
+// waitTPDU waits for the T-PDU to be read by ReadFromGTP until the deadline, and
+// returns changed=true if the deadline is changed while waiting.
+func (u *UPlaneConn) waitTPDU() (tpdu *tpduSet, changed bool, err error) {
+	u.gtpReadMu.Lock()
+	deadline := u.gtpReadDeadline
+	if u.gtpReadChanged == nil {
+		u.gtpReadChanged = make(chan struct{})
+	}
+	changedCh := u.gtpReadChanged
+	u.gtpReadMu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return nil, false, os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
 	select {
 	case <-u.closed():
-		return
+		return nil, false, ErrConnNotOpened
 	case tpdu, ok := <-u.tpduCh:
 		if !ok {
-			err = ErrConnNotOpened
-			return
+			return nil, false, ErrConnNotOpened
 		}
-		n = copy(p, tpdu.payload)
-		addr = tpdu.raddr
-		teid = tpdu.teid
-		return
+		return tpdu, false, nil
+	case <-timeout:
+		return nil, false, os.ErrDeadlineExceeded
+	case <-changedCh:
+		return nil, true, nil
 	}
 }
 
+// SetGTPReadDeadline sets the deadline for future ReadFromGTP calls and any
+// currently-blocked ReadFromGTP call. A zero value for t means ReadFromGTP will
+// not time out.
+//
+// Unlike SetReadDeadline, it does not affect the underlying connection, which
+// keeps receiving the packets in background.
+func (u *UPlaneConn) SetGTPReadDeadline(t time.Time) error {
+	u.gtpReadMu.Lock()
+	defer u.gtpReadMu.Unlock()
+
+	u.gtpReadDeadline = t
+	if u.gtpReadChanged != nil {
+		close(u.gtpReadChanged)
+		u.gtpReadChanged = nil
+	}
+	return nil
+}
+
 // WriteTo writes a packet with payload p to addr.
 // WriteTo can be made to time out and return
 // an Error with Timeout() == true after a fixed time limit;
 // see SetDeadline and SetWriteDeadline.
 // On packet-oriented connections, write timeouts are rare.
 func (u *UPlaneConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
-	return u.pktConn.WriteTo(p, addr)
+	u.capture(DirectionOutbound, addr, p)
+	n, err = u.pktConn.WriteTo(p, addr)
+	if err == nil {
+		u.stats.sent(addr, n)
+	}
+	return n, err
 }
 
 // WriteToGTP writes a packet with TEID and payload to addr.
+//
+// If the MTU policy is set with SetMTUPolicy and the packet exceeds it, *ErrTooBig is
+// returned without writing the packet, except for MTUPolicyFragment.
 func (u *UPlaneConn) WriteToGTP(teid uint32, p []byte, addr net.Addr) (n int, err error) {
-	b, err := Encapsulate(teid, p).Serialize()
-	if err != nil {
-		return
+	u.mu.Lock()
+	mtu, policy := u.mtu, u.mtuPolicy
+	u.mu.Unlock()
+	if imtu, tooBig := checkMTU(mtu, policy, addr, OverheadGTPU, p); tooBig {
+		u.stats.drop(DropTooBig)
+		return 0, &ErrTooBig{MTU: imtu, TEID: teid}
 	}
 
-	if _, err = u.pktConn.WriteTo(b, addr); err != nil {
-		return
+	msg := Encapsulate(teid, p)
+	if err := u.SendMessageTo(msg, addr); err != nil {
+		return 0, err
 	}
-	return len(b), nil
+	u.countSent(teid, len(p))
+	return msg.Len(), nil
 }
 
 // Close closes the connection.
 // Any blocked Read or Write operations will be unblocked and return errors.
 func (u *UPlaneConn) Close() error {
+	u.stopForwardingTimers()
+
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	u.msgHandlerMap = defaultHandlerMap
 	u.RestartCounter = 0
+	u.sendBufs.Reset()
 	close(u.errCh)
 	close(u.closeCh)
 
@@ -313,9 +456,9 @@ func (u *UPlaneConn) SetWriteDeadline(t time.Time) error {
 // any values, which is in most cases vital to continue working as a node, from the incoming
 // messages.
 //
-// HandlerFuncs for EchoResponse and ErrorIndication are registered by default.
-// These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse and/or
-// messages.MsgTypeErrorIndication as msgType parameter.
+// HandlerFuncs for EchoResponse, ErrorIndication and EndMarker are registered by default.
+// These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse,
+// messages.MsgTypeErrorIndication and/or messages.MsgTypeEndMarker as msgType parameter.
 func (u *UPlaneConn) AddHandler(msgType uint8, fn HandlerFunc) {
 	u.msgHandlerMap.store(msgType, fn)
 }
@@ -329,6 +472,39 @@ func (u *UPlaneConn) AddHandlers(funcs map[uint8]HandlerFunc) {
 	}
 }
 
+// ErrorIndicationHandlerFunc is a handler called when Error Indication is received,
+// with the TEID Data I and the GTP-U Peer Address in it, which are the TEID and the
+// address of the peer that has no context for the T-PDU sent.
+type ErrorIndicationHandlerFunc func(teid uint32, peerAddr string)
+
+// SetErrorIndicationHandler sets the handler called on receiving Error Indication,
+// instead of the default HandlerFunc returning ErrErrorIndicated to the error channel.
+// This is to tear down the bearer whose peer has lost the context, e.g., the eNB on
+// S1-U. Giving nil restores the default one.
+func (u *UPlaneConn) SetErrorIndicationHandler(fn ErrorIndicationHandlerFunc) {
+	if fn == nil {
+		u.AddHandler(messages.MsgTypeErrorIndication, handleErrorIndication)
+		return
+	}
+
+	u.AddHandler(messages.MsgTypeErrorIndication, func(c Conn, senderAddr net.Addr, msg messages.Message) error {
+		ind, ok := msg.(*messages.ErrorIndication)
+		if !ok {
+			return ErrUnexpectedType
+		}
+		if ind.TEIDDataI == nil {
+			return &ErrRequiredIEMissing{Type: ies.TEIDDataI}
+		}
+
+		peer := strings.Split(senderAddr.String(), ":")[0]
+		if ind.GTPUPeerAddress != nil {
+			peer = ind.GTPUPeerAddress.IPAddress()
+		}
+		fn(ind.TEIDDataI.TEID(), peer)
+		return nil
+	})
+}
+
 func (u *UPlaneConn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
 	handle, ok := u.msgHandlerMap.load(msg.MessageType())
 	if !ok {
@@ -345,46 +521,23 @@ func (u *UPlaneConn) handleMessage(senderAddr net.Addr, msg messages.Message) er
 
 // EchoRequest sends a EchoRequest.
 func (u *UPlaneConn) EchoRequest(raddr net.Addr) error {
-	b, err := messages.NewEchoRequest(0, ies.NewRecovery(u.RestartCounter)).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := u.pktConn.WriteTo(b, raddr); err != nil {
-		return err
-	}
-	return nil
+	return u.SendMessageTo(messages.NewEchoRequest(0, ies.NewRecovery(u.RestartCounter)), raddr)
 }
 
 // EchoResponse sends a EchoResponse.
 func (u *UPlaneConn) EchoResponse(raddr net.Addr) error {
-	b, err := messages.NewEchoResponse(0, ies.NewRecovery(u.RestartCounter)).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := u.pktConn.WriteTo(b, raddr); err != nil {
-		return err
-	}
-	return nil
+	return u.SendMessageTo(messages.NewEchoResponse(0, ies.NewRecovery(u.RestartCounter)), raddr)
 }
 
 // ErrorIndication just sends ErrorIndication message.
 func (u *UPlaneConn) ErrorIndication(raddr net.Addr, received messages.Message) error {
 	addr := strings.Split(raddr.String(), ":")[0]
-	errInd, err := messages.NewErrorIndication(
+	errInd := messages.NewErrorIndication(
 		0, received.Sequence(),
 		ies.NewTEIDDataI(received.TEID()),
 		ies.NewGSNAddress(addr),
-	).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := u.WriteTo(errInd, raddr); err != nil {
-		return err
-	}
-	return nil
+	)
+	return u.SendMessageTo(errInd, raddr)
 }
 
 // RespondTo sends a message(specified with "toBeSent" param) in response to
@@ -393,12 +546,26 @@ func (u *UPlaneConn) ErrorIndication(raddr net.Addr, received messages.Message)
 // This is to make it easier to handle SequenceNumber.
 func (u *UPlaneConn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
 	toBeSent.SetSequenceNumber(received.Sequence())
-	b := make([]byte, toBeSent.Len())
-	if err := toBeSent.SerializeTo(b); err != nil {
+	return u.SendMessageTo(toBeSent, raddr)
+}
+
+// SendMessageTo serializes the message and writes it to addr.
+//
+// The message is serialized into the buffer kept for each addr, which is reused
+// for the messages sent to the same addr afterwards, instead of allocating a new
+// one for each message. The Payload of msg is copied out of the buffer.
+func (u *UPlaneConn) SendMessageTo(msg messages.Message, addr net.Addr) error {
+	buf := u.sendBufs.Get(addr)
+	buf.Lock()
+	defer buf.Unlock()
+
+	b := buf.Bytes(msg.Len())
+	if err := msg.SerializeTo(b); err != nil {
 		return err
 	}
+	messages.DetachPayload(msg)
 
-	if _, err := u.WriteTo(b, raddr); err != nil {
+	if _, err := u.WriteTo(b, addr); err != nil {
 		return err
 	}
 	return nil
@@ -413,15 +580,285 @@ type peer struct {
 	teid    uint32
 	addr    net.Addr
 	srcConn *UPlaneConn
+
+	// how to handle PDU Session Container on interworking between S1-U and N3.
+	pscAction pscAction
+	qfi       uint8
+}
+
+// RelayKeyMode represents how the relay registered with RelayTo and the variants is
+// looked up for the incoming T-PDU.
+type RelayKeyMode uint8
+
+// RelayKeyMode definitions.
+const (
+	// RelayKeyTEID looks up the relay by TEID. The relay registered with peer address
+	// by RelayToByPeer is preferred if the address matches, and otherwise the one
+	// registered without peer address is used. This is the default for compatibility.
+	RelayKeyTEID RelayKeyMode = iota
+
+	// RelayKeyTEIDAndPeer looks up the relay by TEID and the peer address strictly.
+	// The relays registered without peer address are never used in this mode.
+	RelayKeyTEIDAndPeer
+)
+
+// relayKey is a key of relayMap. peer is the zero Addr for the relays registered
+// without peer address. The UPlaneConn itself represents the local endpoint.
+type relayKey struct {
+	teid uint32
+	peer netip.Addr
+}
+
+func newRelayKey(teid uint32, peerAddr net.Addr) relayKey {
+	if peerAddr == nil {
+		return relayKey{teid: teid}
+	}
+	return relayKey{teid: teid, peer: peerIP(peerAddr)}
+}
+
+// peerIP returns the IP address part of the address, as the source port of
+// GTP-U is not necessarily 2152. The IPv4-mapped IPv6 addresses are unmapped
+// to match the IPv4 ones.
+func peerIP(addr net.Addr) netip.Addr {
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		return udpAddr.AddrPort().Addr().Unmap()
+	}
+	if ap, err := netip.ParseAddrPort(addr.String()); err == nil {
+		return ap.Addr().Unmap()
+	}
+	ip, _ := netip.ParseAddr(addr.String())
+	return ip.Unmap()
+}
+
+// SetRelayKeyMode sets how the relay is looked up for the incoming T-PDU.
+// See RelayKeyMode for details.
+func (u *UPlaneConn) SetRelayKeyMode(mode RelayKeyMode) {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+	u.relayKeyMode = mode
 }
 
 // RelayTo relays T-PDU type of packet to peer node(specified by raddr) from the UPlaneConn given.
 //
 // By using this, owner of UPlaneConn won't be able to Read and Write the packets that has teidIn.
 func (u *UPlaneConn) RelayTo(c *UPlaneConn, teidIn, teidOut uint32, raddr net.Addr) error {
+	return u.addRelay(newRelayKey(teidIn, nil), &peer{teid: teidOut, addr: raddr, srcConn: c})
+}
+
+// RelayToByPeer is the same as RelayTo, but the relay is applied only to the packets with
+// teidIn sent from peerAddr. Only the IP address of peerAddr is used for the lookup.
+//
+// This is to avoid the collision when multiple peers happen to use the same TEID toward
+// the UPlaneConn.
+func (u *UPlaneConn) RelayToByPeer(c *UPlaneConn, peerAddr net.Addr, teidIn, teidOut uint32, raddr net.Addr) error {
+	return u.addRelay(newRelayKey(teidIn, peerAddr), &peer{teid: teidOut, addr: raddr, srcConn: c})
+}
+
+func (u *UPlaneConn) addRelay(key relayKey, p *peer) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
 	if u.relayMap == nil {
-		u.relayMap = map[uint32]*peer{}
+		u.relayMap = map[relayKey]*peer{}
+	}
+	if _, ok := u.relayMap[key]; !ok && key.peer.IsValid() {
+		u.peerRelays++
 	}
-	u.relayMap[teidIn] = &peer{teid: teidOut, addr: raddr, srcConn: c}
+	u.relayMap[key] = p
+	u.stopForwardingTimer(key)
 	return nil
 }
+
+// ReplaceRelay replaces the destination of the packets that has teidIn with the new one
+// atomically, and sends End Marker to the old destination.
+//
+// This is typically used on handover; packets that are being relayed are sent to the old
+// peer before End Marker, and the ones after that are sent to the new peer, so that the
+// receivers can tell when the last packet arrived on the old path.
+// If no relay is registered for teidIn, this returns ErrRelayNotFound without adding.
+func (u *UPlaneConn) ReplaceRelay(c *UPlaneConn, teidIn, teidOut uint32, raddr net.Addr) error {
+	return u.replaceRelay(newRelayKey(teidIn, nil), &peer{teid: teidOut, addr: raddr, srcConn: c})
+}
+
+// ReplaceRelayByPeer is the same as ReplaceRelay, but for the relay registered by RelayToByPeer.
+func (u *UPlaneConn) ReplaceRelayByPeer(c *UPlaneConn, peerAddr net.Addr, teidIn, teidOut uint32, raddr net.Addr) error {
+	return u.replaceRelay(newRelayKey(teidIn, peerAddr), &peer{teid: teidOut, addr: raddr, srcConn: c})
+}
+
+func (u *UPlaneConn) replaceRelay(key relayKey, p *peer) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	old, ok := u.relayMap[key]
+	if !ok {
+		return ErrRelayNotFound
+	}
+	u.relayMap[key] = p
+
+	// send End Marker while holding the lock not to let any packet to the old peer
+	// be sent after it.
+	return old.srcConn.EndMarker(old.teid, old.addr)
+}
+
+// RemoveRelay removes the relay registered for teidIn.
+//
+// The packets with teidIn are no longer relayed after this returns, and they are passed
+// to the handler for T-PDU instead (by default, they can be read by ReadFromGTP).
+// If no relay is registered for teidIn, this returns ErrRelayNotFound.
+func (u *UPlaneConn) RemoveRelay(teidIn uint32) error {
+	return u.removeRelay(newRelayKey(teidIn, nil))
+}
+
+// RemoveRelayByPeer is the same as RemoveRelay, but for the relay registered by RelayToByPeer.
+func (u *UPlaneConn) RemoveRelayByPeer(peerAddr net.Addr, teidIn uint32) error {
+	return u.removeRelay(newRelayKey(teidIn, peerAddr))
+}
+
+func (u *UPlaneConn) removeRelay(key relayKey) error {
+	u.relayMu.Lock()
+	defer u.relayMu.Unlock()
+
+	if _, ok := u.relayMap[key]; !ok {
+		return ErrRelayNotFound
+	}
+	if key.peer.IsValid() {
+		u.peerRelays--
+	}
+	delete(u.relayMap, key)
+	u.stopForwardingTimer(key)
+	return nil
+}
+
+// lookupRelay looks up the relay for the packet with teid from raddr. The relays
+// registered with peer address are looked up only if any, not to parse raddr for
+// every packet otherwise. The caller must hold relayMu.
+func (u *UPlaneConn) lookupRelay(teid uint32, raddr net.Addr) (*peer, bool) {
+	if u.peerRelays > 0 {
+		if p, ok := u.relayMap[newRelayKey(teid, raddr)]; ok {
+			return p, true
+		}
+	}
+	if u.relayKeyMode == RelayKeyTEIDAndPeer {
+		return nil, false
+	}
+	p, ok := u.relayMap[newRelayKey(teid, nil)]
+	return p, ok
+}
+
+// EndMarker sends an End Marker with teid to raddr.
+func (u *UPlaneConn) EndMarker(teid uint32, raddr net.Addr) error {
+	return u.SendMessageTo(messages.NewEndMarker(teid), raddr)
+}
+
+func (u *UPlaneConn) hasRelay() bool {
+	u.relayMu.RLock()
+	defer u.relayMu.RUnlock()
+	return len(u.relayMap) != 0 || len(u.qfiRelayMap) != 0
+}
+
+// relay relays the T-PDU in payload to the peer registered for teid.
+// The first returned value is false if no relay is registered for teid, which means
+// the T-PDU should be handled by the UPlaneConn itself.
+func (u *UPlaneConn) relay(raddr net.Addr, teid uint32, payload []byte) (bool, error) {
+	u.relayMu.RLock()
+	p, ok := u.lookupRelay(teid, raddr)
+	if !ok {
+		p, ok = u.lookupQFIRelay(teid, payload)
+	}
+	if !ok {
+		u.relayMu.RUnlock()
+		return false, nil
+	}
+
+	tb, err := u.forward(p, teid, payload)
+	u.relayMu.RUnlock()
+	if tb != nil {
+		// don't hold the lock while looking up the reverse path.
+		return true, u.handleTooBig(tb.policy, raddr, p.srcConn, tb.header, tb.imtu)
+	}
+	return true, err
+}
+
+// tooBig is the T-PDU that doesn't fit in the outer MTU, to be handled by handleTooBig.
+type tooBig struct {
+	policy MTUPolicy
+	header *messages.Header
+	imtu   int
+}
+
+// forward sends the T-PDU with teid in payload to the peer, applying the policer
+// of this conn and the MTU policy of the conn the packet is sent on. If the packet
+// doesn't fit in the MTU, it is returned as *tooBig without being sent. The caller
+// must hold relayMu.
+func (u *UPlaneConn) forward(p *peer, teid uint32, payload []byte) (*tooBig, error) {
+	if pl, ok := u.policerMap[teid]; ok && !pl.Allow(innerLen(payload)) {
+		u.stats.drop(DropPolicer)
+		return nil, errDropped
+	}
+
+	if p.pscAction != pscActionNone {
+		converted, err := p.convertPSC(payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = converted
+	}
+
+	mtu, policy := p.srcConn.MTUPolicy()
+	if mtu > 0 {
+		header, err := messages.DecodeHeader(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		hdrLen := len(payload) - len(header.Payload)
+		if imtu, ok := checkMTU(mtu, policy, p.addr, hdrLen, header.Payload); ok {
+			return &tooBig{policy: policy, header: header, imtu: imtu}, nil
+		}
+	}
+
+	// just use original packet not to get it slow.
+	binary.BigEndian.PutUint32(payload[4:8], p.teid)
+	if _, err := p.srcConn.WriteTo(payload, p.addr); err != nil {
+		return nil, err
+	}
+	p.srcConn.countSent(p.teid, innerLen(payload))
+	return nil, nil
+}
+
+// handleTooBig handles the T-PDU that doesn't fit in the outer MTU according to the policy.
+func (u *UPlaneConn) handleTooBig(policy MTUPolicy, raddr net.Addr, dst *UPlaneConn, header *messages.Header, imtu int) error {
+	u.stats.drop(DropTooBig)
+	if policy == MTUPolicyAdvise {
+		return &ErrTooBig{MTU: imtu, TEID: header.TEID}
+	}
+
+	// MTUPolicyReflect: send ICMP back toward the inner source, through the
+	// tunnel that the packet came from.
+	teid, ok := dst.lookupReverseTEID(u, raddr)
+	if !ok {
+		return errDropped
+	}
+	icmp, err := newICMPTooBig(header.Payload, imtu)
+	if err != nil {
+		return err
+	}
+	if _, err := u.WriteToGTP(teid, icmp, raddr); err != nil {
+		return err
+	}
+	return errDropped
+}
+
+// lookupReverseTEID looks up the TEID to be used to send packets back to raddr over
+// the UPlaneConn given, from the relay entries registered with RelayTo.
+func (u *UPlaneConn) lookupReverseTEID(to *UPlaneConn, raddr net.Addr) (uint32, bool) {
+	u.relayMu.RLock()
+	defer u.relayMu.RUnlock()
+
+	for _, p := range u.relayMap {
+		if p.srcConn == to && p.addr.String() == raddr.String() {
+			return p.teid, true
+		}
+	}
+	return 0, false
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
 )
 
 type testVal struct {
@@ -110,3 +112,66 @@ func TestClientWrite(t *testing.T) {
 		t.Fatal("timed out while waiting for response to come")
 	}
 }
+
+func TestErrorIndicationHandler(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.93:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.94:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	cliConn, err := v1.ListenAndServeUPlane(cliAddr, 0, make(chan error, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	type indicated struct {
+		teid uint32
+		peer string
+	}
+	indCh := make(chan indicated, 1)
+	srvConn.SetErrorIndicationHandler(func(teid uint32, peerAddr string) {
+		indCh <- indicated{teid, peerAddr}
+	})
+
+	errInd := messages.NewErrorIndication(0, 0, ies.NewTEIDDataI(0x22222222), ies.NewGSNAddress("127.0.0.93"))
+	if err := cliConn.SendMessageTo(errInd, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-indCh:
+		if diff := cmp.Diff(got, indicated{0x22222222, "127.0.0.93"}, cmp.AllowUnexported(indicated{})); diff != "" {
+			t.Error(diff)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatal("Error Indication not handled")
+	}
+
+	// the default handler returns the error.
+	srvConn.SetErrorIndicationHandler(nil)
+	if err := cliConn.SendMessageTo(errInd, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errCh:
+		var indErr *v1.ErrErrorIndicated
+		if !errors.As(err, &indErr) || indErr.TEID != 0x22222222 {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error not returned by default handler")
+	}
+}
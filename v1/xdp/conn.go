@@ -0,0 +1,432 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux && xdp
+// +build linux,xdp
+
+package xdp
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	// ErrInvalidConfig indicates that the Config given is not valid.
+	ErrInvalidConfig = errors.New("invalid config")
+
+	// ErrTooLarge indicates that the packet to be written does not fit in a frame.
+	ErrTooLarge = errors.New("packet too large for a frame")
+
+	// ErrClosed indicates that the operation is performed on a closed Conn.
+	ErrClosed = errors.New("use of closed connection")
+
+	// ErrNoFrameAvailable indicates that no frame is available for TX before the deadline.
+	ErrNoFrameAvailable = errors.New("no frame available for TX")
+)
+
+// timeoutError is returned when the deadline is exceeded.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// Config is a set of parameters to create a Conn.
+type Config struct {
+	// Interface is the name of the network interface to bind.
+	Interface string
+
+	// QueueID is the ID of the queue of the interface to bind.
+	QueueID int
+
+	// LocalAddr is the local address, to which the packets to be read are destined,
+	// and which is used as a source of the packets written.
+	LocalAddr *net.UDPAddr
+
+	// NextHopMAC is the MAC address of the next hop, to which all the packets
+	// written are sent.
+	NextHopMAC net.HardwareAddr
+
+	// XSKMapFD is the file descriptor of BPF_MAP_TYPE_XSKMAP that the XDP program
+	// redirects the packets to. The socket is inserted with QueueID as a key.
+	XSKMapFD int
+
+	// NumFrames is the number of frames in UMEM, which must be a power of two.
+	// Half of them are used for RX and the rest for TX. 4096 is used if 0.
+	NumFrames int
+
+	// FrameSize is the size of each frame in UMEM, which must be a power of two.
+	// 2048 is used if 0.
+	FrameSize int
+
+	// ZeroCopy forces the zero-copy mode, which is supported only by some drivers.
+	// Otherwise the copy mode is used.
+	ZeroCopy bool
+}
+
+// Conn is a net.PacketConn over AF_XDP socket.
+type Conn struct {
+	fd     int
+	cfg    Config
+	srcMAC net.HardwareAddr
+	closed int32
+
+	umem                 []byte
+	fill, comp, rx, tx   *ring
+	rxMu, txMu, deadline sync.Mutex
+	freeFrames           []uint64
+	ipID                 uint16
+
+	readDeadline, writeDeadline time.Time
+}
+
+// umemReg is struct xdp_umem_reg.
+type umemReg struct {
+	addr      uint64
+	len       uint64
+	chunkSize uint32
+	headroom  uint32
+	flags     uint32
+	_         uint32
+}
+
+// Listen creates a new AF_XDP socket with the Config given, and returns it as Conn.
+func Listen(cfg *Config) (*Conn, error) {
+	c := &Conn{cfg: *cfg}
+	if c.cfg.NumFrames == 0 {
+		c.cfg.NumFrames = 4096
+	}
+	if c.cfg.FrameSize == 0 {
+		c.cfg.FrameSize = 2048
+	}
+	if n := c.cfg.NumFrames; n < 2 || n&(n-1) != 0 || c.cfg.LocalAddr == nil || len(c.cfg.NextHopMAC) != 6 {
+		return nil, ErrInvalidConfig
+	}
+	// the frames are located by masking the addresses in the descriptors.
+	if n := c.cfg.FrameSize; n <= 0 || n&(n-1) != 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	ifi, err := net.InterfaceByName(c.cfg.Interface)
+	if err != nil {
+		return nil, err
+	}
+	c.srcMAC = ifi.HardwareAddr
+
+	if err := c.setup(ifi.Index); err != nil {
+		c.release()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Conn) setup(ifindex int) error {
+	var err error
+	c.fd, err = unix.Socket(unix.AF_XDP, unix.SOCK_RAW, 0)
+	if err != nil {
+		return err
+	}
+
+	size := c.cfg.NumFrames * c.cfg.FrameSize
+	c.umem, err = unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS|unix.MAP_POPULATE)
+	if err != nil {
+		return err
+	}
+
+	reg := &umemReg{
+		addr:      uint64(uintptr(unsafe.Pointer(&c.umem[0]))),
+		len:       uint64(size),
+		chunkSize: uint32(c.cfg.FrameSize),
+	}
+	if err := setsockopt(c.fd, unix.XDP_UMEM_REG, unsafe.Pointer(reg), unsafe.Sizeof(*reg)); err != nil {
+		return err
+	}
+
+	ringSize := c.cfg.NumFrames / 2
+	for _, opt := range []int{unix.XDP_UMEM_FILL_RING, unix.XDP_UMEM_COMPLETION_RING, unix.XDP_RX_RING, unix.XDP_TX_RING} {
+		if err := unix.SetsockoptInt(c.fd, unix.SOL_XDP, opt, ringSize); err != nil {
+			return err
+		}
+	}
+
+	off := &unix.XDPMmapOffsets{}
+	l := uint32(unsafe.Sizeof(*off))
+	if _, _, errno := unix.Syscall6(
+		unix.SYS_GETSOCKOPT, uintptr(c.fd), unix.SOL_XDP, unix.XDP_MMAP_OFFSETS,
+		uintptr(unsafe.Pointer(off)), uintptr(unsafe.Pointer(&l)), 0,
+	); errno != 0 {
+		return errno
+	}
+
+	if c.fill, err = mapRing(c.fd, unix.XDP_UMEM_PGOFF_FILL_RING, off.Fr, ringSize, 8); err != nil {
+		return err
+	}
+	if c.comp, err = mapRing(c.fd, unix.XDP_UMEM_PGOFF_COMPLETION_RING, off.Cr, ringSize, 8); err != nil {
+		return err
+	}
+	if c.rx, err = mapRing(c.fd, unix.XDP_PGOFF_RX_RING, off.Rx, ringSize, 16); err != nil {
+		return err
+	}
+	if c.tx, err = mapRing(c.fd, unix.XDP_PGOFF_TX_RING, off.Tx, ringSize, 16); err != nil {
+		return err
+	}
+
+	// the first half of frames are for RX, the rest for TX.
+	for i := 0; i < ringSize; i++ {
+		*c.fill.addrAt(uint32(i)) = uint64(i * c.cfg.FrameSize)
+	}
+	c.fill.cachedProd = uint32(ringSize)
+	atomic.StoreUint32(c.fill.producer, c.fill.cachedProd)
+	for i := ringSize; i < c.cfg.NumFrames; i++ {
+		c.freeFrames = append(c.freeFrames, uint64(i*c.cfg.FrameSize))
+	}
+
+	flags := uint16(unix.XDP_USE_NEED_WAKEUP | unix.XDP_COPY)
+	if c.cfg.ZeroCopy {
+		flags = unix.XDP_USE_NEED_WAKEUP | unix.XDP_ZEROCOPY
+	}
+	if err := unix.Bind(c.fd, &unix.SockaddrXDP{
+		Flags: flags, Ifindex: uint32(ifindex), QueueID: uint32(c.cfg.QueueID),
+	}); err != nil {
+		return err
+	}
+
+	return updateXSKMap(c.cfg.XSKMapFD, uint32(c.cfg.QueueID), uint32(c.fd))
+}
+
+func (c *Conn) release() {
+	for _, r := range []*ring{c.fill, c.comp, c.rx, c.tx} {
+		if r != nil {
+			_ = unix.Munmap(r.mem)
+		}
+	}
+	if c.umem != nil {
+		_ = unix.Munmap(c.umem)
+	}
+	if c.fd > 0 {
+		_ = unix.Close(c.fd)
+	}
+}
+
+func (c *Conn) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+// ReadFrom reads a UDP payload destined for the LocalAddr into p.
+// It returns the number of bytes copied into p and the source address.
+func (c *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	for {
+		c.rxMu.Lock()
+		if c.isClosed() {
+			c.rxMu.Unlock()
+			return 0, nil, ErrClosed
+		}
+
+		if c.rx.available() > 0 {
+			desc := c.rx.descAt(c.rx.cachedCons)
+			frame := c.umem[desc.Addr : desc.Addr+uint64(desc.Len)]
+			payload, src, ok := parseFrame(frame, c.cfg.LocalAddr)
+			if ok {
+				n = copy(p, payload)
+			}
+			c.rx.release()
+
+			// give the frame back to kernel.
+			*c.fill.addrAt(c.fill.cachedProd) = desc.Addr &^ uint64(c.cfg.FrameSize-1)
+			c.fill.submit()
+			c.rxMu.Unlock()
+
+			if ok {
+				return n, src, nil
+			}
+			continue
+		}
+		c.rxMu.Unlock()
+
+		timeout, expired := c.pollTimeout(c.getDeadline(true))
+		if expired {
+			return 0, nil, timeoutError{}
+		}
+		if _, err := unix.Poll([]unix.PollFd{{Fd: int32(c.fd), Events: unix.POLLIN}}, timeout); err != nil && err != unix.EINTR {
+			return 0, nil, err
+		}
+	}
+}
+
+// WriteTo writes p as a UDP payload to addr.
+func (c *Conn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	dst, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, &net.AddrError{Err: "non-UDP address", Addr: addr.String()}
+	}
+	hl := headerLen(dst)
+	if hl+len(p) > c.cfg.FrameSize {
+		return 0, ErrTooLarge
+	}
+
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	for {
+		if c.isClosed() {
+			return 0, ErrClosed
+		}
+
+		c.reclaim()
+		if len(c.freeFrames) > 0 {
+			break
+		}
+
+		c.kick()
+		timeout, expired := c.pollTimeout(c.getDeadline(false))
+		if expired {
+			return 0, ErrNoFrameAvailable
+		}
+		if _, err := unix.Poll([]unix.PollFd{{Fd: int32(c.fd), Events: unix.POLLOUT}}, timeout); err != nil && err != unix.EINTR {
+			return 0, err
+		}
+	}
+
+	addrInUMEM := c.freeFrames[len(c.freeFrames)-1]
+	c.freeFrames = c.freeFrames[:len(c.freeFrames)-1]
+
+	frame := c.umem[addrInUMEM : addrInUMEM+uint64(hl+len(p))]
+	copy(frame[hl:], p)
+	c.ipID++
+	buildHeaders(frame, c.srcMAC, c.cfg.NextHopMAC, c.cfg.LocalAddr, dst, c.ipID)
+
+	desc := c.tx.descAt(c.tx.cachedProd)
+	desc.Addr = addrInUMEM
+	desc.Len = uint32(len(frame))
+	desc.Options = 0
+	c.tx.submit()
+	c.kick()
+
+	return len(p), nil
+}
+
+// reclaim collects the frames whose transmission has been completed.
+func (c *Conn) reclaim() {
+	for c.comp.available() > 0 {
+		c.freeFrames = append(c.freeFrames, *c.comp.addrAt(c.comp.cachedCons))
+		c.comp.release()
+	}
+}
+
+// kick lets the kernel start transmission if needed.
+func (c *Conn) kick() {
+	if atomic.LoadUint32(c.tx.flags)&unix.XDP_RING_NEED_WAKEUP == 0 {
+		return
+	}
+	// EAGAIN, EBUSY and ENOBUFS just mean it's being processed.
+	_ = unix.Sendto(c.fd, nil, unix.MSG_DONTWAIT, nil)
+}
+
+// pollTimeout returns the timeout to be given to poll(2) in milliseconds, which is
+// limited to 100ms not to miss Close(), and whether the deadline has already passed.
+func (c *Conn) pollTimeout(deadline time.Time) (int, bool) {
+	timeout := 100 * time.Millisecond
+	if !deadline.IsZero() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, true
+		}
+		if remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return int(timeout / time.Millisecond), false
+}
+
+func (c *Conn) getDeadline(read bool) time.Time {
+	c.deadline.Lock()
+	defer c.deadline.Unlock()
+	if read {
+		return c.readDeadline
+	}
+	return c.writeDeadline
+}
+
+// Close closes the Conn and releases the resources.
+func (c *Conn) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return ErrClosed
+	}
+
+	c.rxMu.Lock()
+	c.txMu.Lock()
+	defer c.rxMu.Unlock()
+	defer c.txMu.Unlock()
+	c.release()
+	return nil
+}
+
+// LocalAddr returns the local network address.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.cfg.LocalAddr
+}
+
+// SetDeadline sets the read and write deadlines associated with the connection.
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.deadline.Lock()
+	defer c.deadline.Unlock()
+	c.readDeadline, c.writeDeadline = t, t
+	return nil
+}
+
+// SetReadDeadline sets the deadline for future ReadFrom calls and any
+// currently-blocked ReadFrom call.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.deadline.Lock()
+	defer c.deadline.Unlock()
+	c.readDeadline = t
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for future WriteTo calls.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.deadline.Lock()
+	defer c.deadline.Unlock()
+	c.writeDeadline = t
+	return nil
+}
+
+func setsockopt(fd, opt int, val unsafe.Pointer, l uintptr) error {
+	if _, _, errno := unix.Syscall6(
+		unix.SYS_SETSOCKOPT, uintptr(fd), unix.SOL_XDP, uintptr(opt), uintptr(val), l, 0,
+	); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// updateXSKMap puts the socket into XSKMAP with the key given.
+func updateXSKMap(mapFD int, key, fd uint32) error {
+	// union bpf_attr for BPF_MAP_*_ELEM commands.
+	attr := struct {
+		mapFD uint32
+		_     uint32
+		key   uint64
+		value uint64
+		flags uint64
+	}{
+		mapFD: uint32(mapFD),
+		key:   uint64(uintptr(unsafe.Pointer(&key))),
+		value: uint64(uintptr(unsafe.Pointer(&fd))),
+	}
+
+	const bpfMapUpdateElem = 2
+	if _, _, errno := unix.Syscall(
+		unix.SYS_BPF, bpfMapUpdateElem, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr),
+	); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux && xdp
+// +build linux,xdp
+
+package xdp
+
+import (
+	"net"
+	"testing"
+)
+
+func TestListenInvalidConfig(t *testing.T) {
+	for _, size := range []int{-1, 1000, 3072} {
+		_, err := Listen(&Config{
+			Interface:  "lo",
+			LocalAddr:  &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 2152},
+			NextHopMAC: net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+			FrameSize:  size,
+		})
+		if err != ErrInvalidConfig {
+			t.Errorf("got unexpected error with FrameSize %d: %v", size, err)
+		}
+	}
+}
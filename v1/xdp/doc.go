@@ -0,0 +1,33 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package xdp provides a net.PacketConn over AF_XDP socket, which can be used as
+// the underlying connection of v1.UPlaneConn for kernel-bypass packet I/O.
+//
+// This package is available only on Linux with "xdp" build tag given, as it requires
+// platform-specific codes and privileges(CAP_NET_ADMIN and CAP_NET_RAW).
+//
+// Loading and attaching an XDP program is out of the scope of this package. The program
+// attached to the interface should redirect the GTP-U packets destined for the local
+// address to the BPF_MAP_TYPE_XSKMAP, whose file descriptor is given in Config. Other
+// packets should be passed to the kernel with XDP_PASS.
+//
+//	xconn, err := xdp.Listen(&xdp.Config{
+//		Interface:  "eth1",
+//		QueueID:    0,
+//		LocalAddr:  laddr, // *net.UDPAddr, typically port 2152.
+//		NextHopMAC: gwMAC, // the packets are sent to this MAC address.
+//		XSKMapFD:   fd,
+//	})
+//	if err != nil {
+//		// ...
+//	}
+//
+//	// UPlaneConn over AF_XDP socket can be used in the same way as the one over UDP.
+//	uConn := v1.NewUPlaneConn(xconn, 0, errCh)
+//	uConn.RelayTo(...)
+//
+// Note: IP fragments and IPv6 extension headers are not supported; such packets are
+// silently dropped.
+package xdp
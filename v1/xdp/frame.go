@@ -0,0 +1,156 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux && xdp
+// +build linux,xdp
+
+package xdp
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/checksum"
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+
+	protoUDP = 17
+
+	lenEthernet = 14
+	lenIPv4     = 20
+	lenIPv6     = 40
+	lenUDP      = 8
+)
+
+// parseFrame parses an Ethernet frame and returns the UDP payload and the source
+// address if the frame is an unfragmented UDP datagram destined for laddr. The
+// destination IP address is not checked if laddr has the unspecified one.
+func parseFrame(b []byte, laddr *net.UDPAddr) (payload []byte, src *net.UDPAddr, ok bool) {
+	if len(b) < lenEthernet {
+		return nil, nil, false
+	}
+	etherType := binary.BigEndian.Uint16(b[12:14])
+	offset := lenEthernet
+	if etherType == etherTypeVLAN {
+		if len(b) < offset+4 {
+			return nil, nil, false
+		}
+		etherType = binary.BigEndian.Uint16(b[offset+2 : offset+4])
+		offset += 4
+	}
+
+	var ip, dst net.IP
+	switch etherType {
+	case etherTypeIPv4:
+		if len(b) < offset+lenIPv4 {
+			return nil, nil, false
+		}
+		ihl := int(b[offset]&0x0f) * 4
+		if ihl < lenIPv4 || len(b) < offset+ihl {
+			return nil, nil, false
+		}
+		// reject fragments(MF bit or non-zero offset) and non-UDP.
+		if b[offset+9] != protoUDP || binary.BigEndian.Uint16(b[offset+6:offset+8])&0x3fff != 0 {
+			return nil, nil, false
+		}
+		ip = net.IP(append([]byte{}, b[offset+12:offset+16]...))
+		dst = net.IP(b[offset+16 : offset+20])
+		offset += ihl
+	case etherTypeIPv6:
+		if len(b) < offset+lenIPv6 || b[offset+6] != protoUDP {
+			return nil, nil, false
+		}
+		ip = net.IP(append([]byte{}, b[offset+8:offset+24]...))
+		dst = net.IP(b[offset+24 : offset+40])
+		offset += lenIPv6
+	default:
+		return nil, nil, false
+	}
+	if len(laddr.IP) != 0 && !laddr.IP.IsUnspecified() && !laddr.IP.Equal(dst) {
+		return nil, nil, false
+	}
+
+	if len(b) < offset+lenUDP {
+		return nil, nil, false
+	}
+	if int(binary.BigEndian.Uint16(b[offset+2:offset+4])) != laddr.Port {
+		return nil, nil, false
+	}
+	l := int(binary.BigEndian.Uint16(b[offset+4 : offset+6]))
+	if l < lenUDP || len(b) < offset+l {
+		return nil, nil, false
+	}
+
+	src = &net.UDPAddr{IP: ip, Port: int(binary.BigEndian.Uint16(b[offset : offset+2]))}
+	return b[offset+lenUDP : offset+l], src, true
+}
+
+// headerLen returns the length of headers put before UDP payload for dst.
+func headerLen(dst *net.UDPAddr) int {
+	if dst.IP.To4() != nil {
+		return lenEthernet + lenIPv4 + lenUDP
+	}
+	return lenEthernet + lenIPv6 + lenUDP
+}
+
+// buildHeaders puts Ethernet, IP and UDP headers at the beginning of b, which must
+// already contain the UDP payload after the headerLen(dst) bytes.
+//
+// UDP checksum for IPv4 is left zero, which is permitted for tunnels.
+func buildHeaders(b []byte, srcMAC, dstMAC net.HardwareAddr, src, dst *net.UDPAddr, id uint16) {
+	copy(b[0:6], dstMAC)
+	copy(b[6:12], srcMAC)
+
+	var udp []byte
+	if dst4 := dst.IP.To4(); dst4 != nil {
+		binary.BigEndian.PutUint16(b[12:14], etherTypeIPv4)
+		ip := b[lenEthernet : lenEthernet+lenIPv4]
+		ip[0] = 0x45
+		ip[1] = 0
+		binary.BigEndian.PutUint16(ip[2:4], uint16(len(b)-lenEthernet))
+		binary.BigEndian.PutUint16(ip[4:6], id)
+		binary.BigEndian.PutUint16(ip[6:8], 0)
+		ip[8] = 64
+		ip[9] = protoUDP
+		binary.BigEndian.PutUint16(ip[10:12], 0)
+		copy(ip[12:16], src.IP.To4())
+		copy(ip[16:20], dst4)
+		binary.BigEndian.PutUint16(ip[10:12], checksum.Sum(ip, 0))
+
+		udp = b[lenEthernet+lenIPv4:]
+		fillUDP(udp, src.Port, dst.Port)
+		binary.BigEndian.PutUint16(udp[6:8], 0)
+		return
+	}
+
+	binary.BigEndian.PutUint16(b[12:14], etherTypeIPv6)
+	ip := b[lenEthernet : lenEthernet+lenIPv6]
+	binary.BigEndian.PutUint32(ip[0:4], 6<<28)
+	binary.BigEndian.PutUint16(ip[4:6], uint16(len(b)-lenEthernet-lenIPv6))
+	ip[6] = protoUDP
+	ip[7] = 64
+	copy(ip[8:24], src.IP.To16())
+	copy(ip[24:40], dst.IP.To16())
+
+	udp = b[lenEthernet+lenIPv6:]
+	fillUDP(udp, src.Port, dst.Port)
+
+	// checksum is mandatory for UDP over IPv6.
+	binary.BigEndian.PutUint16(udp[6:8], 0)
+	cs := checksum.Sum(udp, checksum.PseudoHeader(ip[8:40], protoUDP, len(udp)))
+	if cs == 0 {
+		cs = 0xffff
+	}
+	binary.BigEndian.PutUint16(udp[6:8], cs)
+}
+
+func fillUDP(udp []byte, srcPort, dstPort int) {
+	binary.BigEndian.PutUint16(udp[0:2], uint16(srcPort))
+	binary.BigEndian.PutUint16(udp[2:4], uint16(dstPort))
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+}
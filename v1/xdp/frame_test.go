@@ -0,0 +1,84 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux && xdp
+// +build linux,xdp
+
+package xdp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFrame(t *testing.T) {
+	var (
+		srcMAC  = net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+		dstMAC  = net.HardwareAddr{0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb}
+		payload = []byte{0x30, 0xff, 0x00, 0x04, 0x11, 0x22, 0x33, 0x44, 0xde, 0xad, 0xbe, 0xef}
+	)
+
+	cases := []struct {
+		description string
+		src, dst    *net.UDPAddr
+	}{
+		{
+			"IPv4",
+			&net.UDPAddr{IP: net.ParseIP("192.168.0.1").To4(), Port: 2152},
+			&net.UDPAddr{IP: net.ParseIP("192.168.0.2").To4(), Port: 2152},
+		}, {
+			"IPv6",
+			&net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 2152},
+			&net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2152},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			hl := headerLen(c.dst)
+			frame := make([]byte, hl+len(payload))
+			copy(frame[hl:], payload)
+			buildHeaders(frame, srcMAC, dstMAC, c.src, c.dst, 1)
+
+			got, src, ok := parseFrame(frame, c.dst)
+			if !ok {
+				t.Fatal("failed to parse frame")
+			}
+			if diff := cmp.Diff(got, payload); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(src.String(), c.src.String()); diff != "" {
+				t.Error(diff)
+			}
+
+			if _, _, ok := parseFrame(frame, &net.UDPAddr{IP: c.dst.IP, Port: c.dst.Port + 1}); ok {
+				t.Error("frame destined for another port is parsed")
+			}
+			if _, _, ok := parseFrame(frame, &net.UDPAddr{IP: c.src.IP, Port: c.dst.Port}); ok {
+				t.Error("frame destined for another address is parsed")
+			}
+			if _, _, ok := parseFrame(frame, &net.UDPAddr{Port: c.dst.Port}); !ok {
+				t.Error("frame is not parsed with unspecified address")
+			}
+		})
+	}
+
+	t.Run("IPv4-IHL", func(t *testing.T) {
+		src := &net.UDPAddr{IP: net.ParseIP("192.168.0.1").To4(), Port: 2152}
+		dst := &net.UDPAddr{IP: net.ParseIP("192.168.0.2").To4(), Port: 2152}
+		hl := headerLen(dst)
+		frame := make([]byte, hl+len(payload))
+		copy(frame[hl:], payload)
+		buildHeaders(frame, srcMAC, dstMAC, src, dst, 1)
+
+		for _, ihl := range []byte{0, 4} {
+			frame[lenEthernet] = 0x40 | ihl
+			if _, _, ok := parseFrame(frame, dst); ok {
+				t.Errorf("frame with IHL %d is parsed", ihl)
+			}
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux && xdp
+// +build linux,xdp
+
+package xdp
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// ring is a single-producer/single-consumer ring shared with the kernel.
+//
+// For FILL and TX rings this is the producer, and for COMPLETION and RX rings
+// this is the consumer. The cached index is the one this side owns.
+type ring struct {
+	mem                []byte
+	producer, consumer *uint32
+	flags              *uint32
+	descOff            uint64
+	mask, size         uint32
+	elemSize           uint64
+
+	cachedProd, cachedCons uint32
+}
+
+func mapRing(fd int, pgoff int64, off unix.XDPRingOffset, size int, elemSize uint64) (*ring, error) {
+	mem, err := unix.Mmap(
+		fd, pgoff, int(off.Desc+uint64(size)*elemSize),
+		unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ring{
+		mem:      mem,
+		producer: (*uint32)(unsafe.Pointer(&mem[off.Producer])),
+		consumer: (*uint32)(unsafe.Pointer(&mem[off.Consumer])),
+		flags:    (*uint32)(unsafe.Pointer(&mem[off.Flags])),
+		descOff:  off.Desc,
+		mask:     uint32(size - 1),
+		size:     uint32(size),
+		elemSize: elemSize,
+	}, nil
+}
+
+// addrAt returns the pointer to the i-th entry of FILL or COMPLETION ring.
+func (r *ring) addrAt(i uint32) *uint64 {
+	return (*uint64)(unsafe.Pointer(&r.mem[r.descOff+uint64(i&r.mask)*r.elemSize]))
+}
+
+// descAt returns the pointer to the i-th entry of RX or TX ring.
+func (r *ring) descAt(i uint32) *unix.XDPDesc {
+	return (*unix.XDPDesc)(unsafe.Pointer(&r.mem[r.descOff+uint64(i&r.mask)*r.elemSize]))
+}
+
+// available returns the number of entries that can be consumed.
+func (r *ring) available() uint32 {
+	return atomic.LoadUint32(r.producer) - r.cachedCons
+}
+
+// release marks an entry consumed.
+func (r *ring) release() {
+	r.cachedCons++
+	atomic.StoreUint32(r.consumer, r.cachedCons)
+}
+
+// submit marks an entry produced.
+func (r *ring) submit() {
+	r.cachedProd++
+	atomic.StoreUint32(r.producer, r.cachedProd)
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// RequestsDHCPv4Allocation reports whether the UE requests in the PCO or APCO of
+// the Create Session Request to get the IPv4 address with DHCPv4 after the PDN
+// connection is established, instead of in the PDN Address Allocation.
+//
+// P-GW that accepts it responds with the unspecified address(0.0.0.0) in the PDN
+// Address Allocation, e.g., with the lease given by ipam.DHCPAllocator.AllocateDeferred.
+func RequestsDHCPv4Allocation(csReq *messages.CreateSessionRequest) bool {
+	for _, ie := range []*ies.IE{csReq.PCO, csReq.APCO} {
+		if ie == nil {
+			continue
+		}
+
+		var pco *ies.PCOPayload
+		if ie.Type == ies.AdditionalProtocolConfigurationOptions {
+			pco = ie.AdditionalProtocolConfigurationOptions()
+		} else {
+			pco = ie.ProtocolConfigurationOptions()
+		}
+		if pco == nil {
+			continue
+		}
+		for _, opt := range pco.ConfigurationProtocolOptions {
+			if opt.ProtocolID == ContIDIPv4addressAllocationViaDHCPv4 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestRequestsDHCPv4Allocation(t *testing.T) {
+	viaNAS := ies.NewConfigurationProtocolOption(v2.ContIDIPaddressAllocationViaNASSignalling, nil)
+	viaDHCP := ies.NewConfigurationProtocolOption(v2.ContIDIPv4addressAllocationViaDHCPv4, nil)
+	dns := ies.NewConfigurationProtocolOption(v2.ContIDDNSServerIPv4AddressRequest, nil)
+
+	for _, c := range []struct {
+		description string
+		ies         []*ies.IE
+		want        bool
+	}{
+		{"no-pco", nil, false},
+		{"via-nas", []*ies.IE{ies.NewProtocolConfigurationOptions(v2.ConfigProtocolPPPWithIP, dns, viaNAS)}, false},
+		{"via-dhcp", []*ies.IE{ies.NewProtocolConfigurationOptions(v2.ConfigProtocolPPPWithIP, dns, viaDHCP)}, true},
+		{"via-dhcp-in-apco", []*ies.IE{ies.NewAdditionalProtocolConfigurationOptions(v2.ConfigProtocolPPPWithIP, viaDHCP)}, true},
+	} {
+		t.Run(c.description, func(t *testing.T) {
+			csReq := messages.NewCreateSessionRequest(0, 0, c.ies...)
+			if got := v2.RequestsDHCPv4Allocation(csReq); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
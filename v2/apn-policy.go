@@ -0,0 +1,209 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// APNPolicy is the admission policy of an APN applied to the incoming Create Session
+// Requests.
+type APNPolicy struct {
+	// Allowed is whether the subscriber is allowed to access the APN. The request is
+	// rejected with CauseAPNAccessDeniedNoSubscription if false.
+	Allowed bool
+
+	// QoS is the QoS of the default bearer to be used instead of the one requested,
+	// if not nil. The Bearer QoS IE in the Bearer Context to be created in the request
+	// is replaced with it before the HandlerFunc is called.
+	QoS *QoSProfile
+
+	// AMBRUplink and AMBRDownlink are the caps of APN-AMBR in kbps. The AMBR IE in the
+	// request exceeding them is lowered to the caps before the HandlerFunc is called.
+	// 0 means no cap.
+	AMBRUplink, AMBRDownlink uint32
+
+	// IPPool is the name of the pool to assign the address of the UE from, e.g., the
+	// one of ipam.PoolConfig.
+	IPPool string
+
+	// APNRestriction is the APN Restriction of the APN. The request is rejected with
+	// CauseAPNRestrictionTypeIncompatibleWithCurrentlyActivePDNConnection if it is not
+	// allowed with the Maximum APN Restriction. See CheckAPNRestriction.
+	APNRestriction uint8
+
+	// Congestion is the APN-based congestion control of the APN, which is applied to
+	// the requests if not nil. See APNCongestion.
+	Congestion *APNCongestion
+}
+
+// APNCongestion is the APN-based congestion control of an APN by P-GW described in
+// TS 23.401 4.3.7.5. The requests for the APN congested are rejected with
+// CauseAPNCongestion and the PGW Back-Off Time, during which the MME rejects the
+// PDN connectivity to the APN by itself.
+type APNCongestion struct {
+	// BackOffTime is the PGW Back-Off Time sent in the Create Session Response. It is
+	// not sent if 0, and ies.EPCTimerInfinite can be used for the infinite one.
+	BackOffTime time.Duration
+
+	// LowAccessPriorityOnly limits the rejection to the requests from the UEs with the
+	// Low Access Priority Indication set in the Signalling Priority Indication, i.e.,
+	// the delay-tolerant devices, to keep accepting the others in the congestion.
+	LowAccessPriorityOnly bool
+}
+
+// applies reports whether the APNCongestion applies to the request.
+func (a *APNCongestion) applies(csReq *messages.CreateSessionRequest) bool {
+	if !a.LowAccessPriorityOnly {
+		return true
+	}
+	ie := csReq.SignallingPriorityIndication
+	return ie != nil && ie.LowAccessPriorityIndicator()
+}
+
+// congestionIEs returns the IEs to be sent with CauseAPNCongestion.
+func (a *APNCongestion) congestionIEs() []*ies.IE {
+	if a.BackOffTime == 0 {
+		return nil
+	}
+	return []*ies.IE{ies.NewEPCTimer(a.BackOffTime)}
+}
+
+// APNPolicyProvider provides the APNPolicy consulted by Conn on the incoming Create
+// Session Requests, so that the admission policy is centralized instead of embedded
+// in the HandlerFuncs.
+type APNPolicyProvider interface {
+	// APNPolicy returns the APNPolicy of the apn for the subscriber, which is built
+	// from the IMSI, MSISDN and MEI in the request. The request is rejected with
+	// CauseMissingOrUnknownAPN if ErrUnknownAPN is returned, and with CauseSystemFailure
+	// if the other errors are returned. It is not consulted and the request is rejected
+	// with CauseMandatoryIEIncorrect if the IMSI is malformed.
+	APNPolicy(apn string, sub *Subscriber) (*APNPolicy, error)
+}
+
+// APNPolicyMap is the APNPolicyProvider with the fixed APNPolicy for each APN. The one
+// for "*" is used for the APNs not in the map, if exists.
+type APNPolicyMap map[string]*APNPolicy
+
+// APNPolicy returns the APNPolicy of the apn, or ErrUnknownAPN if not found.
+func (m APNPolicyMap) APNPolicy(apn string, sub *Subscriber) (*APNPolicy, error) {
+	if p, ok := m[apn]; ok {
+		return p, nil
+	}
+	if p, ok := m["*"]; ok {
+		return p, nil
+	}
+	return nil, ErrUnknownAPN
+}
+
+// SetAPNPolicyProvider sets the APNPolicyProvider consulted on the incoming Create
+// Session Requests before the HandlerFunc is called. The requests not admitted are
+// answered with Create Session Response with the Cause by Conn, and the error is sent
+// to the error channel instead of calling the HandlerFunc. nil removes it.
+//
+// The APNPolicy admitted is retrieved with AdmittedAPNPolicy in the HandlerFunc.
+func (c *Conn) SetAPNPolicyProvider(p APNPolicyProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apnPolicyProvider = p
+}
+
+// AdmittedAPNPolicy returns the APNPolicy admitted for the Create Session Request being
+// handled by the HandlerFunc, or nil if no APNPolicyProvider is set.
+func (c *Conn) AdmittedAPNPolicy(csReq *messages.CreateSessionRequest) *APNPolicy {
+	if p, ok := c.admittedPolicies.Load(csReq); ok {
+		return p.(*APNPolicy)
+	}
+	return nil
+}
+
+// admit consults the APNPolicyProvider on the Create Session Request, and returns the
+// HandlerFunc wrapped to forget the APNPolicy admitted after it returns.
+func (c *Conn) admit(senderAddr net.Addr, msg messages.Message, handle HandlerFunc) (HandlerFunc, error) {
+	csReq, ok := msg.(*messages.CreateSessionRequest)
+	if !ok {
+		return handle, nil
+	}
+	c.mu.Lock()
+	provider := c.apnPolicyProvider
+	c.mu.Unlock()
+	if provider == nil {
+		return handle, nil
+	}
+
+	var apn string
+	if ie := csReq.APN; ie != nil {
+		apn = ie.AccessPointName()
+	}
+	sub := &Subscriber{}
+	imsi, err := messages.IMSI(csReq)
+	switch {
+	case errors.Is(err, messages.ErrIEMissing):
+	case err != nil:
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseMandatoryIEIncorrect, err)
+	default:
+		sub.IMSI = imsi
+	}
+	if ie := csReq.MSISDN; ie != nil {
+		sub.MSISDN = ie.MSISDN()
+	}
+	if ie := csReq.MEI; ie != nil {
+		sub.IMEI, sub.SVN = ie.IMEI(), ie.SoftwareVersion()
+	}
+
+	policy, err := provider.APNPolicy(apn, sub)
+	switch {
+	case errors.Is(err, ErrUnknownAPN):
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseMissingOrUnknownAPN, err)
+	case err != nil:
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseSystemFailure, err)
+	case policy == nil || !policy.Allowed:
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseAPNAccessDeniedNoSubscription, ErrAPNAccessDenied)
+	}
+	if a := policy.Congestion; a != nil && a.applies(csReq) {
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseAPNCongestion, ErrAPNCongested, a.congestionIEs()...)
+	}
+	if err := c.CheckAPNRestriction(csReq, policy.APNRestriction); err != nil {
+		return nil, c.rejectCreateSession(senderAddr, csReq, CauseAPNRestrictionTypeIncompatibleWithCurrentlyActivePDNConnection, err)
+	}
+
+	if ie := csReq.AMBR; ie != nil && (policy.AMBRUplink != 0 || policy.AMBRDownlink != 0) {
+		up, down := ie.AggregateMaximumBitRateUp(), ie.AggregateMaximumBitRateDown()
+		if policy.AMBRUplink != 0 {
+			up = min(up, policy.AMBRUplink)
+		}
+		if policy.AMBRDownlink != 0 {
+			down = min(down, policy.AMBRDownlink)
+		}
+		csReq.AMBR = ies.NewAggregateMaximumBitRate(up, down)
+	}
+	if ie := csReq.BearerContextsToBeCreated; ie != nil && policy.QoS != nil {
+		ie.Remove(ies.BearerQoS, 0)
+		ie.Add(policy.QoS.bearerQoS())
+	}
+
+	c.admittedPolicies.Store(csReq, policy)
+	return func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		defer c.admittedPolicies.Delete(csReq)
+		return handle(c, senderAddr, msg)
+	}, nil
+}
+
+func (c *Conn) rejectCreateSession(senderAddr net.Addr, csReq *messages.CreateSessionRequest, cause uint8, err error, ie ...*ies.IE) error {
+	var teid uint32
+	if ie := csReq.SenderFTEIDC; ie != nil {
+		teid = ie.TEID()
+	}
+	csRsp := messages.NewCreateSessionResponse(teid, 0, append([]*ies.IE{ies.NewCause(cause, 0, 0, 0, nil)}, ie...)...)
+	if rerr := c.RespondTo(senderAddr, csReq, csRsp); rerr != nil {
+		return rerr
+	}
+	return err
+}
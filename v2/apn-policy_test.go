@@ -0,0 +1,205 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestAPNPolicy(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.34:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pktConn, err := net.ListenPacket("udp", srvAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pktConn.Close()
+	errCh := make(chan error, 10)
+	srvConn := v2.ServeConn(pktConn, 0, errCh)
+	defer srvConn.Close()
+
+	srvConn.SetAPNPolicyProvider(v2.APNPolicyMap{
+		"internet": {Allowed: true, AMBRUplink: 1000, IPPool: "pool-1"},
+		"ims":      {Allowed: false},
+		"video": {
+			Allowed: true,
+			QoS:     &v2.QoSProfile{PCI: true, PL: 2, QCI: 6, MBRUL: 2000, MBRDL: 4000},
+		},
+		"iot": {
+			Allowed:    true,
+			Congestion: &v2.APNCongestion{BackOffTime: 10 * time.Minute, LowAccessPriorityOnly: true},
+		},
+	})
+
+	type admitted struct {
+		policy   *v2.APNPolicy
+		up, down uint32
+		qos      *ies.IE
+	}
+	admittedCh := make(chan admitted, 1)
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		csReq := msg.(*messages.CreateSessionRequest)
+		got := admitted{
+			policy: c.AdmittedAPNPolicy(csReq),
+			up:     csReq.AMBR.AggregateMaximumBitRateUp(),
+			down:   csReq.AMBR.AggregateMaximumBitRateDown(),
+		}
+		if ie := csReq.BearerContextsToBeCreated; ie != nil {
+			got.qos, _ = ie.FindByType(ies.BearerQoS, 0)
+		}
+		admittedCh <- got
+		return nil
+	})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.35:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	send := func(apn string, imsi *ies.IE, extra ...*ies.IE) {
+		t.Helper()
+		b, err := messages.NewCreateSessionRequest(
+			0, 1,
+			append([]*ies.IE{
+				imsi,
+				ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.35", ""),
+				ies.NewAccessPointName(apn),
+				ies.NewAggregateMaximumBitRate(5000, 500),
+			}, extra...)...,
+		).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("admitted", func(t *testing.T) {
+		send("internet", ies.NewIMSI("123451234567890"))
+		select {
+		case got := <-admittedCh:
+			if got.policy == nil || got.policy.IPPool != "pool-1" {
+				t.Errorf("got policy %+v", got.policy)
+			}
+			if got.up != 1000 || got.down != 500 {
+				t.Errorf("got AMBR %d/%d, want 1000/500", got.up, got.down)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("handler not called")
+		}
+	})
+
+	t.Run("qos", func(t *testing.T) {
+		send("video", ies.NewIMSI("123451234567890"), ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewBearerQoS(0, 9, 1, 9, 0, 0, 0, 0),
+		))
+		select {
+		case got := <-admittedCh:
+			qos := got.qos
+			if qos == nil {
+				t.Fatal("Bearer QoS not found")
+			}
+			if qos.QCILabel() != 6 || qos.PriorityLevel() != 2 || !qos.PreemptionCapability() || qos.PreemptionVulnerability() {
+				t.Errorf("got QCI %d, ARP %d/%v/%v", qos.QCILabel(), qos.PriorityLevel(), qos.PreemptionCapability(), qos.PreemptionVulnerability())
+			}
+			if qos.MBRForUplink() != 2000 || qos.MBRForDownlink() != 4000 {
+				t.Errorf("got MBR %d/%d, want 2000/4000", qos.MBRForUplink(), qos.MBRForDownlink())
+			}
+		case <-time.After(time.Second):
+			t.Fatal("handler not called")
+		}
+	})
+
+	t.Run("congested-not-low-priority", func(t *testing.T) {
+		send("iot", ies.NewIMSI("123451234567890"), ies.NewSignallingPriorityIndication(0))
+		select {
+		case got := <-admittedCh:
+			if got.policy == nil || got.policy.Congestion == nil {
+				t.Errorf("got policy %+v", got.policy)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("handler not called")
+		}
+	})
+
+	for _, tc := range []struct {
+		description string
+		apn         string
+		imsi        *ies.IE
+		extra       []*ies.IE
+		cause       uint8
+		backOff     time.Duration
+		wantErr     error
+	}{
+		{"ims", "ims", ies.NewIMSI("123451234567890"), nil, v2.CauseAPNAccessDeniedNoSubscription, 0, v2.ErrAPNAccessDenied},
+		{"unknown", "unknown", ies.NewIMSI("123451234567890"), nil, v2.CauseMissingOrUnknownAPN, 0, v2.ErrUnknownAPN},
+		{"malformed-imsi", "internet", ies.New(ies.IMSI, 0, []byte{0x21, 0xa3}), nil, v2.CauseMandatoryIEIncorrect, 0, ies.ErrMalformed},
+		{
+			"congested", "iot", ies.NewIMSI("123451234567890"),
+			[]*ies.IE{ies.NewSignallingPriorityIndication(1)},
+			v2.CauseAPNCongestion, 10 * time.Minute, v2.ErrAPNCongested,
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			send(tc.apn, tc.imsi, tc.extra...)
+
+			if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			buf := make([]byte, 1500)
+			n, _, err := peer.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg, err := messages.Decode(buf[:n])
+			if err != nil {
+				t.Fatal(err)
+			}
+			csRsp, ok := msg.(*messages.CreateSessionResponse)
+			if !ok {
+				t.Fatalf("got %s, want CreateSessionResponse", msg.MessageTypeName())
+			}
+			if csRsp.TEID() != 0x11111111 {
+				t.Errorf("got TEID %#x", csRsp.TEID())
+			}
+			if got := csRsp.Cause.Cause(); got != tc.cause {
+				t.Errorf("got Cause %s, want %s", v2.Cause(got), v2.Cause(tc.cause))
+			}
+			var backOff time.Duration
+			if ie := csRsp.PGWBackOffTime; ie != nil {
+				backOff = ie.EPCTimer()
+			}
+			if backOff != tc.backOff {
+				t.Errorf("got PGW Back-Off Time %s, want %s", backOff, tc.backOff)
+			}
+
+			select {
+			case err := <-errCh:
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("got %v, want %v", err, tc.wantErr)
+				}
+			case <-time.After(time.Second):
+				t.Error("no error reported")
+			}
+			select {
+			case <-admittedCh:
+				t.Error("handler called for the request rejected")
+			default:
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// MaxAPNRestriction returns the Maximum APN Restriction of the APN Restrictions given,
+// i.e., the most restrictive one.
+func MaxAPNRestriction(restrictions ...uint8) APNRestriction {
+	var maxRestriction uint8
+	for _, r := range restrictions {
+		maxRestriction = max(maxRestriction, r)
+	}
+	return APNRestriction(maxRestriction)
+}
+
+// Allows reports whether the PDN connection to the APN with restriction is allowed to be
+// established while r is the Maximum APN Restriction of the ones already established,
+// as defined in TS 23.060 15.4.
+//
+// The APN with no restriction(APNRestrictionNoExistingContextsorRestriction) is always
+// allowed.
+func (r APNRestriction) Allows(restriction uint8) bool {
+	if restriction == APNRestrictionNoExistingContextsorRestriction {
+		return true
+	}
+
+	switch uint8(r) {
+	case APNRestrictionNoExistingContextsorRestriction:
+		return true
+	case APNRestrictionPublic1:
+		return restriction <= APNRestrictionPrivate1
+	case APNRestrictionPublic2:
+		return restriction <= APNRestrictionPublic2
+	case APNRestrictionPrivate1:
+		return restriction == APNRestrictionPublic1
+	default:
+		return false
+	}
+}
+
+// MaxAPNRestriction returns the Maximum APN Restriction of the PDN connections of the
+// Session, computed from the APNRestriction of the Bearers.
+func (s *Session) MaxAPNRestriction() APNRestriction {
+	return s.maxAPNRestrictionExcept("")
+}
+
+// maxAPNRestrictionExcept returns the Maximum APN Restriction of the Bearers except
+// the ones to apn, which are to be replaced by the new PDN connection to it.
+func (s *Session) maxAPNRestrictionExcept(apn string) APNRestriction {
+	var restrictions []uint8
+	s.bearerMap.rangeWithFunc(func(name, bearer interface{}) bool {
+		br := bearer.(*Bearer)
+		if apn == "" || br.APN != apn {
+			restrictions = append(restrictions, br.APNRestriction)
+		}
+		return true
+	})
+	return MaxAPNRestriction(restrictions...)
+}
+
+// CheckAPNRestriction checks if the PDN connection requested with csReq to the APN with
+// restriction is allowed, against the Maximum APN Restriction in csReq sent by MME or
+// SGSN and the one of the Session of the subscriber in c, if exists. The Bearers to the
+// same APN as csReq are not taken into account, as they are to be replaced.
+//
+// ErrAPNRestrictionIncompatible is returned if not allowed, and the request should be
+// rejected with CauseAPNRestrictionTypeIncompatibleWithCurrentlyActivePDNConnection.
+func (c *Conn) CheckAPNRestriction(csReq *messages.CreateSessionRequest, restriction uint8) error {
+	var restrictions []uint8
+	if ie := csReq.APNRestriction; ie != nil {
+		restrictions = append(restrictions, ie.APNRestriction())
+	}
+	if ie := csReq.IMSI; ie != nil {
+		if sess, err := c.GetSessionByIMSI(ie.IMSI()); err == nil {
+			var apn string
+			if ie := csReq.APN; ie != nil {
+				apn = ie.AccessPointName()
+			}
+			restrictions = append(restrictions, uint8(sess.maxAPNRestrictionExcept(apn)))
+		}
+	}
+
+	if !MaxAPNRestriction(restrictions...).Allows(restriction) {
+		return ErrAPNRestrictionIncompatible
+	}
+	return nil
+}
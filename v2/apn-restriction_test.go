@@ -0,0 +1,84 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestAPNRestrictionAllows(t *testing.T) {
+	// rows are the Maximum APN Restriction, and columns are the APN Restriction of the
+	// new PDN connection, from 0 to 4.
+	cases := []struct {
+		max     uint8
+		allowed [5]bool
+	}{
+		{v2.APNRestrictionNoExistingContextsorRestriction, [5]bool{true, true, true, true, true}},
+		{v2.APNRestrictionPublic1, [5]bool{true, true, true, true, false}},
+		{v2.APNRestrictionPublic2, [5]bool{true, true, true, false, false}},
+		{v2.APNRestrictionPrivate1, [5]bool{true, true, false, false, false}},
+		{v2.APNRestrictionPrivate2, [5]bool{true, false, false, false, false}},
+	}
+
+	for _, c := range cases {
+		max := v2.APNRestriction(c.max)
+		t.Run(max.String(), func(t *testing.T) {
+			for r, want := range c.allowed {
+				if got := max.Allows(uint8(r)); got != want {
+					t.Errorf("%s: got %v, want %v", v2.APNRestriction(r), got, want)
+				}
+			}
+		})
+	}
+
+	if got := v2.MaxAPNRestriction(1, 3, 2); got != v2.APNRestriction(v2.APNRestrictionPrivate1) {
+		t.Errorf("got %s, want Private1", got)
+	}
+}
+
+func TestCheckAPNRestriction(t *testing.T) {
+	conn := &v2.Conn{}
+	sess := v2.NewSession(&net.UDPAddr{}, &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddBearer("internet", &v2.Bearer{APN: "internet", APNRestriction: v2.APNRestrictionPublic2})
+	conn.AddSession(sess)
+
+	csReq := func(apn string, ie ...*ies.IE) *messages.CreateSessionRequest {
+		return messages.NewCreateSessionRequest(0, 0, append(ie,
+			ies.NewIMSI("123451234567890"),
+			ies.NewAccessPointName(apn),
+		)...)
+	}
+
+	cases := []struct {
+		description string
+		csReq       *messages.CreateSessionRequest
+		restriction uint8
+		err         error
+	}{
+		{"Compatible", csReq("mms"), v2.APNRestrictionPublic1, nil},
+		{"Incompatible", csReq("corp"), v2.APNRestrictionPrivate1, v2.ErrAPNRestrictionIncompatible},
+		{"SameAPN", csReq("internet"), v2.APNRestrictionPrivate2, nil},
+		{
+			"MaximumInRequest",
+			csReq("internet", ies.NewAPNRestriction(v2.APNRestrictionPrivate1)),
+			v2.APNRestrictionPublic2,
+			v2.ErrAPNRestrictionIncompatible,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if err := conn.CheckAPNRestriction(c.csReq, c.restriction); !errors.Is(err, c.err) {
+				t.Errorf("got %v, want %v", err, c.err)
+			}
+		})
+	}
+}
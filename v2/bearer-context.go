@@ -0,0 +1,230 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"strconv"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// BearerContextType is the Bearer Context in a message, which decides the instances of
+// the F-TEIDs in it.
+type BearerContextType uint8
+
+// BearerContextType definitions.
+const (
+	_ BearerContextType = iota
+	// BearerContextToBeCreated is the Bearer Contexts to be created in Create Session Request.
+	BearerContextToBeCreated
+	// BearerContextCreated is the Bearer Contexts created in Create Session Response.
+	BearerContextCreated
+	// BearerContextToBeModified is the Bearer Contexts to be modified in Modify Bearer Request.
+	BearerContextToBeModified
+	// BearerContextModified is the Bearer Contexts modified in Modify Bearer Response.
+	BearerContextModified
+	// BearerContextInCreateBearerRequest is the Bearer Contexts in Create Bearer Request.
+	BearerContextInCreateBearerRequest
+	// BearerContextInCreateBearerResponse is the Bearer Contexts in Create Bearer Response.
+	BearerContextInCreateBearerResponse
+	// BearerContextInUpdateBearerRequest is the Bearer Contexts in Update Bearer Request.
+	BearerContextInUpdateBearerRequest
+	// BearerContextInUpdateBearerResponse is the Bearer Contexts in Update Bearer Response.
+	BearerContextInUpdateBearerResponse
+	// BearerContextInDeleteBearerRequest is the Bearer Contexts in Delete Bearer Request.
+	BearerContextInDeleteBearerRequest
+	// BearerContextInDeleteBearerResponse is the Bearer Contexts in Delete Bearer Response.
+	BearerContextInDeleteBearerResponse
+)
+
+var bearerContextNames = []string{
+	BearerContextToBeCreated:            "BearerContextToBeCreated",
+	BearerContextCreated:                "BearerContextCreated",
+	BearerContextToBeModified:           "BearerContextToBeModified",
+	BearerContextModified:               "BearerContextModified",
+	BearerContextInCreateBearerRequest:  "BearerContextInCreateBearerRequest",
+	BearerContextInCreateBearerResponse: "BearerContextInCreateBearerResponse",
+	BearerContextInUpdateBearerRequest:  "BearerContextInUpdateBearerRequest",
+	BearerContextInUpdateBearerResponse: "BearerContextInUpdateBearerResponse",
+	BearerContextInDeleteBearerRequest:  "BearerContextInDeleteBearerRequest",
+	BearerContextInDeleteBearerResponse: "BearerContextInDeleteBearerResponse",
+}
+
+// String returns the name of the BearerContextType.
+func (t BearerContextType) String() string {
+	if int(t) < len(bearerContextNames) && bearerContextNames[t] != "" {
+		return bearerContextNames[t]
+	}
+	return "BearerContextType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// fteidInstances is the instances of the F-TEIDs in the Bearer Context for each interface
+// type, defined in the tables of the Bearer Context in TS 29.274 7.2. The Bearer Contexts
+// not in the map cannot have F-TEIDs.
+var fteidInstances = map[BearerContextType]map[uint8]uint8{
+	BearerContextToBeCreated: {
+		IFTypeS1UeNodeBGTPU: 0,
+		IFTypeS4SGSNGTPU:    1,
+		IFTypeS5S8SGWGTPU:   2,
+		IFTypeS5S8PGWGTPU:   3,
+		IFTypeS12RNCGTPU:    4,
+		IFTypeS2bUePDGGTPU:  5,
+		IFTypeS2aTWANGTPU:   6,
+		IFTypeS11MMEGTPU:    7,
+	},
+	BearerContextCreated: {
+		IFTypeS1USGWGTPU:  0,
+		IFTypeS4SGWGTPU:   1,
+		IFTypeS5S8PGWGTPU: 2,
+		IFTypeS12SGWGTPU:  3,
+		IFTypeS2bUPGWGTPU: 4,
+		IFTypeS2aPGWGTPU:  5,
+		IFTypeS11SGWGTPU:  6,
+	},
+	BearerContextToBeModified: {
+		IFTypeS1UeNodeBGTPU: 0,
+		IFTypeS5S8SGWGTPU:   1,
+		IFTypeS12RNCGTPU:    2,
+		IFTypeS4SGSNGTPU:    3,
+		IFTypeS11MMEGTPU:    4,
+	},
+	BearerContextModified: {
+		IFTypeS1USGWGTPU: 0,
+		IFTypeS12SGWGTPU: 1,
+		IFTypeS4SGWGTPU:  2,
+		IFTypeS11SGWGTPU: 3,
+	},
+	BearerContextInCreateBearerRequest: {
+		IFTypeS1USGWGTPU:  0,
+		IFTypeS5S8PGWGTPU: 1,
+		IFTypeS12SGWGTPU:  2,
+		IFTypeS4SGWGTPU:   3,
+		IFTypeS2bUPGWGTPU: 4,
+		IFTypeS2aPGWGTPU:  5,
+	},
+	BearerContextInCreateBearerResponse: {
+		IFTypeS1UeNodeBGTPU: 0,
+		IFTypeS1USGWGTPU:    1,
+		IFTypeS5S8SGWGTPU:   2,
+		IFTypeS5S8PGWGTPU:   3,
+		IFTypeS12RNCGTPU:    4,
+		IFTypeS12SGWGTPU:    5,
+		IFTypeS4SGSNGTPU:    6,
+		IFTypeS4SGWGTPU:     7,
+		IFTypeS2bUePDGGTPU:  8,
+		IFTypeS2bUPGWGTPU:   9,
+		IFTypeS2aTWANGTPU:   10,
+		IFTypeS2aPGWGTPU:    11,
+	},
+}
+
+// FTEIDInstance returns the instance of the F-TEID of the interface type ifType in the
+// Bearer Context of type t, or false if the F-TEID is not expected in it.
+func FTEIDInstance(t BearerContextType, ifType uint8) (uint8, bool) {
+	ins, ok := fteidInstances[t][ifType]
+	return ins, ok
+}
+
+// BearerContextBuilder builds the Bearer Context IE with the instances of the child IEs
+// set as defined for the type of the Bearer Context, instead of giving the instances of
+// the F-TEIDs manually to ies.NewBearerContext.
+//
+// The methods can be chained, and the first error is returned by Build.
+type BearerContextBuilder struct {
+	typ      BearerContextType
+	children []*ies.IE
+	err      error
+}
+
+// NewBearerContextBuilder creates a new BearerContextBuilder for the Bearer Context of
+// type t with the EBI.
+func NewBearerContextBuilder(t BearerContextType, ebi uint8) *BearerContextBuilder {
+	return &BearerContextBuilder{
+		typ:      t,
+		children: []*ies.IE{ies.NewEPSBearerID(ebi)},
+	}
+}
+
+// FTEID adds the F-TEID IEs with the instances set for their interface types. The
+// F-TEIDs are modified in place, and the nil ones are ignored.
+//
+// *ErrUnexpectedInterfaceType is returned by Build if the F-TEID of the interface type
+// is not expected in the Bearer Context.
+func (b *BearerContextBuilder) FTEID(fteids ...*ies.IE) *BearerContextBuilder {
+	for _, fteid := range fteids {
+		if fteid == nil {
+			continue
+		}
+		ins, ok := FTEIDInstance(b.typ, fteid.InterfaceType())
+		if !ok {
+			if b.err == nil {
+				b.err = &ErrUnexpectedInterfaceType{IFType: fteid.InterfaceType(), BearerContext: b.typ}
+			}
+			continue
+		}
+		fteid.SetInstance(ins)
+		b.children = append(b.children, fteid)
+	}
+	return b
+}
+
+// Cause adds the Cause IE with the value.
+func (b *BearerContextBuilder) Cause(cause uint8) *BearerContextBuilder {
+	b.children = append(b.children, ies.NewCause(cause, 0, 0, 0, nil))
+	return b
+}
+
+// QoS adds the Bearer QoS IE built from the QoSProfile.
+func (b *BearerContextBuilder) QoS(qos *QoSProfile) *BearerContextBuilder {
+	b.children = append(b.children, qos.bearerQoS())
+	return b
+}
+
+// TFT adds the Bearer TFT IE, which is ignored if nil.
+func (b *BearerContextBuilder) TFT(tft *ies.IE) *BearerContextBuilder {
+	return b.Add(tft)
+}
+
+// ChargingID adds the Charging ID IE.
+func (b *BearerContextBuilder) ChargingID(id uint32) *BearerContextBuilder {
+	b.children = append(b.children, ies.NewChargingID(id))
+	return b
+}
+
+// AllocateChargingID adds the Charging ID IE with the one allocated by a, which is also
+// set to br if not nil, e.g., with (*Conn).ChargingIDAllocator on P-GW.
+//
+// The error from (*ChargingIDAllocator).Allocate is returned by Build.
+func (b *BearerContextBuilder) AllocateChargingID(a *ChargingIDAllocator, br *Bearer) *BearerContextBuilder {
+	id, err := a.Allocate()
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return b
+	}
+	if br != nil {
+		br.ChargingID = id
+	}
+	return b.ChargingID(id)
+}
+
+// Add adds the other IEs as they are, e.g., PCO. The nil ones are ignored.
+func (b *BearerContextBuilder) Add(ie ...*ies.IE) *BearerContextBuilder {
+	for _, i := range ie {
+		if i != nil {
+			b.children = append(b.children, i)
+		}
+	}
+	return b
+}
+
+// Build returns the Bearer Context IE built, or the first error occurred while building.
+func (b *BearerContextBuilder) Build() (*ies.IE, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return ies.NewBearerContext(b.children...), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestBearerContextBuilder(t *testing.T) {
+	t.Run("Created", func(t *testing.T) {
+		got, err := v2.NewBearerContextBuilder(v2.BearerContextCreated, 5).
+			Cause(v2.CauseRequestAccepted).
+			FTEID(
+				ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x11111111, "127.0.0.1", ""),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPU, 0x22222222, "127.0.0.2", ""),
+			).
+			ChargingID(1).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x11111111, "127.0.0.1", ""),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPU, 0x22222222, "127.0.0.2", "").WithInstance(2),
+			ies.NewChargingID(1),
+		)
+		if diff := cmp.Diff(serialize(t, got), serialize(t, want)); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("ToBeCreated", func(t *testing.T) {
+		got, err := v2.NewBearerContextBuilder(v2.BearerContextToBeCreated, 5).
+			FTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, 0x11111111, "127.0.0.1", "")).
+			QoS(&v2.QoSProfile{PCI: true, PL: 2, PVI: true, QCI: 9}).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, 0x11111111, "127.0.0.1", "").WithInstance(2),
+			ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0),
+		)
+		if diff := cmp.Diff(serialize(t, got), serialize(t, want)); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("UnexpectedInterfaceType", func(t *testing.T) {
+		_, err := v2.NewBearerContextBuilder(v2.BearerContextModified, 5).
+			FTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPU, 0x11111111, "127.0.0.1", "")).
+			Build()
+
+		var ierr *v2.ErrUnexpectedInterfaceType
+		if !errors.As(err, &ierr) {
+			t.Fatalf("got %v, want ErrUnexpectedInterfaceType", err)
+		}
+		if ierr.IFType != v2.IFTypeS5S8PGWGTPU || ierr.BearerContext != v2.BearerContextModified {
+			t.Errorf("got %v", ierr)
+		}
+	})
+}
+
+func serialize(t *testing.T, ie *ies.IE) []byte {
+	t.Helper()
+	b, err := ie.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestFTEIDInstance(t *testing.T) {
+	cases := []struct {
+		bearerContext v2.BearerContextType
+		ifType        uint8
+		ins           uint8
+		ok            bool
+	}{
+		{v2.BearerContextToBeCreated, v2.IFTypeS2aTWANGTPU, 6, true},
+		{v2.BearerContextToBeModified, v2.IFTypeS1UeNodeBGTPU, 0, true},
+		{v2.BearerContextToBeModified, v2.IFTypeS11MMEGTPU, 4, true},
+		{v2.BearerContextInCreateBearerResponse, v2.IFTypeS2aPGWGTPU, 11, true},
+		{v2.BearerContextInDeleteBearerRequest, v2.IFTypeS1USGWGTPU, 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.bearerContext.String(), func(t *testing.T) {
+			ins, ok := v2.FTEIDInstance(c.bearerContext, c.ifType)
+			if ins != c.ins || ok != c.ok {
+				t.Errorf("%s: got %d/%v, want %d/%v", v2.IFType(c.ifType), ins, ok, c.ins, c.ok)
+			}
+		})
+	}
+}
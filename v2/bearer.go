@@ -6,6 +6,7 @@ package v2
 
 import (
 	"net"
+	"sync"
 
 	"github.com/wmnsk/go-gtp/v2/ies"
 )
@@ -20,14 +21,31 @@ type QoSProfile struct {
 	GBRUL, GBRDL uint64
 }
 
+// bearerQoS returns the Bearer QoS IE built from the QoSProfile.
+func (q *QoSProfile) bearerQoS() *ies.IE {
+	var pci, pvi uint8
+	if q.PCI {
+		pci = 1
+	}
+	if q.PVI {
+		pvi = 1
+	}
+	return ies.NewBearerQoS(pci, q.PL, pvi, q.QCI, q.MBRUL, q.MBRDL, q.GBRUL, q.GBRDL)
+}
+
 // Bearer is a GTPv2 bearer.
 type Bearer struct {
+	// mu guards the fields set by the handlers while the others may read them.
+	mu              sync.Mutex
 	raddr           net.Addr
 	teidIn, teidOut uint32
 
 	EBI               uint8
 	SubscriberIP, APN string
 	ChargingID        uint32
+	// APNRestriction is the APN Restriction of the APN, which is taken into account
+	// in the Maximum APN Restriction of the Session. See MaxAPNRestriction.
+	APNRestriction uint8
 	*QoSProfile
 }
 
@@ -40,35 +58,47 @@ func NewBearer(ebi uint8, apn string, qos *QoSProfile) *Bearer {
 
 // Modify is just an alias of (*Conn) ModifyBearer.
 func (b *Bearer) Modify(c *Conn, ie ...*ies.IE) error {
-	return c.ModifyBearer(b.teidOut, ie...)
+	return c.ModifyBearer(b.OutgoingTEID(), ie...)
 }
 
 // RemoteAddress returns the remote address associated with Bearer.
 func (b *Bearer) RemoteAddress() net.Addr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.raddr
 }
 
 // SetRemoteAddress sets the remote address associated with Bearer.
 func (b *Bearer) SetRemoteAddress(raddr net.Addr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.raddr = raddr
 }
 
 // IncomingTEID returns the incoming TEID associated with Bearer.
 func (b *Bearer) IncomingTEID() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.teidIn
 }
 
 // SetIncomingTEID sets the incoming TEID associated with Bearer.
 func (b *Bearer) SetIncomingTEID(teid uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.teidIn = teid
 }
 
 // OutgoingTEID returns the outgoing TEID associated with Bearer.
 func (b *Bearer) OutgoingTEID() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.teidOut
 }
 
 // SetOutgoingTEID sets the outgoing TEID associated with Bearer.
 func (b *Bearer) SetOutgoingTEID(teid uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	b.teidOut = teid
 }
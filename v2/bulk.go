@@ -0,0 +1,165 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultBulkConcurrency is the number of the Create Session Requests outstanding at
+// the same time in CreateSessions. It is lowered to MaxPerPeer of TransactionLimits
+// if it is set smaller, not to have the requests rejected with ErrTooManyTransactions.
+const DefaultBulkConcurrency = 32
+
+// SessionSpec is the Session to be created by CreateSessions.
+type SessionSpec struct {
+	// PeerAddr is the address to send Create Session Request to.
+	PeerAddr net.Addr
+
+	// IEs is the IEs in Create Session Request, as given to CreateSession.
+	IEs []*ies.IE
+}
+
+// SessionResult is the result of creating the Session of a SessionSpec.
+type SessionResult struct {
+	// Session is the Session created and added to the Conn, or nil if failed.
+	Session *Session
+
+	// Response is the Create Session Response received, which is set also when it is
+	// received with the Cause of rejection.
+	Response *messages.CreateSessionResponse
+
+	// Err is the error occurred while creating the Session, e.g., *ErrCauseNotOK.
+	Err error
+}
+
+// CreateSessions creates the Sessions of specs by sending Create Session Requests to the
+// peers and waiting for the responses with Exchange, with DefaultBulkConcurrency requests
+// outstanding at most. This is useful for the load tools and for restoring the sessions
+// toward the peer after failover.
+//
+// The results are returned in the same order as specs. The Sessions created successfully
+// are activated and added to the Conn with the TEIDs, PAA, EBI and Charging ID in the
+// responses, and the failed ones are not added. The specs not yet sent when ctx is done
+// have ctx.Err() in their results.
+func (c *Conn) CreateSessions(ctx context.Context, specs []SessionSpec) []SessionResult {
+	results := make([]SessionResult, len(specs))
+	sem := make(chan struct{}, c.bulkConcurrency())
+
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.createSessionWithSpec(ctx, spec)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// bulkConcurrency returns the concurrency of CreateSessions.
+func (c *Conn) bulkConcurrency() int {
+	c.txs.mu.Lock()
+	defer c.txs.mu.Unlock()
+
+	if n := c.txs.limits.MaxPerPeer; n > 0 {
+		return min(n, DefaultBulkConcurrency)
+	}
+	return DefaultBulkConcurrency
+}
+
+// createSessionWithSpec creates a Session of spec and waits for the response.
+func (c *Conn) createSessionWithSpec(ctx context.Context, spec SessionSpec) SessionResult {
+	sess, err := newSessionWithIEs(spec.PeerAddr, spec.IEs...)
+	if err != nil {
+		return SessionResult{Err: err}
+	}
+
+	var csRsp *messages.CreateSessionResponse
+	for {
+		csReq := messages.NewCreateSessionRequest(0, sess.Sequence, spec.IEs...)
+		csRsp, err = Exchange[*messages.CreateSessionResponse](ctx, c, spec.PeerAddr, csReq)
+		if !errors.Is(err, ErrDuplicateSequence) {
+			break
+		}
+		// the sequence number drawn collides with the other request to the peer.
+		sess.Sequence = (sess.Sequence + 1) & 0xffffff
+	}
+	if err != nil {
+		return SessionResult{Response: csRsp, Err: err}
+	}
+
+	if err := sess.applyCreateSessionResponse(csRsp); err != nil {
+		return SessionResult{Response: csRsp, Err: err}
+	}
+	if err := sess.Activate(); err != nil {
+		return SessionResult{Response: csRsp, Err: err}
+	}
+	c.AddSession(sess)
+	return SessionResult{Session: sess, Response: csRsp}
+}
+
+// applyCreateSessionResponse stores the values in Create Session Response accepted in
+// the Session.
+func (s *Session) applyCreateSessionResponse(csRsp *messages.CreateSessionResponse) error {
+	for _, ie := range []*ies.IE{csRsp.SenderFTEIDC, csRsp.PGWS5S8FTEIDC} {
+		if ie == nil {
+			continue
+		}
+		if err := s.addFTEID(ie); err != nil {
+			return err
+		}
+	}
+
+	if ie := csRsp.CSGInformationReportingAction; ie != nil {
+		if err := s.SetCSGReportingAction(ie); err != nil {
+			return err
+		}
+	}
+
+	br := s.GetDefaultBearer()
+	if ie := csRsp.PAA; ie != nil {
+		ip, err := ie.ParseIPAddress()
+		if err != nil {
+			return err
+		}
+		br.SubscriberIP = ip
+	}
+	if ie := csRsp.BearerContextsCreated; ie != nil {
+		for _, child := range ie.BearerContext() {
+			switch child.Type {
+			case ies.EPSBearerID:
+				br.EBI = child.EPSBearerID()
+			case ies.ChargingID:
+				br.ChargingID = child.ChargingID()
+			case ies.FullyQualifiedTEID:
+				if err := s.addFTEID(child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestCreateSessions(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.44:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.45:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		csReq := msg.(*messages.CreateSessionRequest)
+		if csReq.IMSI.IMSI() == "123451234567899" {
+			return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+				csReq.SenderFTEIDC.TEID(), 0, ies.NewCause(v2.CauseUserAuthenticationFailed, 0, 0, 0, nil),
+			))
+		}
+
+		teid := csReq.SenderFTEIDC.TEID()
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			teid, 0,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, teid+0x1000, "127.0.0.45", ""),
+			ies.NewPDNAddressAllocation(fmt.Sprintf("10.0.0.%d", teid)),
+			ies.NewBearerContext(
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewEPSBearerID(5),
+				ies.NewChargingID(teid),
+			),
+		))
+	})
+
+	spec := func(imsi string, teid uint32) v2.SessionSpec {
+		return v2.SessionSpec{
+			PeerAddr: srvAddr,
+			IEs: []*ies.IE{
+				ies.NewIMSI(imsi),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, teid, "127.0.0.44", ""),
+				ies.NewBearerContext(ies.NewEPSBearerID(5)),
+			},
+		}
+	}
+	var specs []v2.SessionSpec
+	for i := 1; i <= 50; i++ {
+		specs = append(specs, spec(fmt.Sprintf("12345123456%04d", i), uint32(i)))
+	}
+	specs = append(specs, spec("123451234567899", 99))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	results := cliConn.CreateSessions(ctx, specs)
+	if len(results) != len(specs) {
+		t.Fatalf("got %d results, want %d", len(results), len(specs))
+	}
+
+	for i, res := range results[:50] {
+		if res.Err != nil {
+			t.Fatalf("%d: %v", i, res.Err)
+		}
+		sess := res.Session
+		if sess == nil || !sess.IsActive() {
+			t.Fatalf("%d: session not created", i)
+		}
+		if teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC); err != nil || teid != uint32(i+1)+0x1000 {
+			t.Errorf("%d: got TEID %#x, %v", i, teid, err)
+		}
+		if got, want := sess.GetDefaultBearer().SubscriberIP, fmt.Sprintf("10.0.0.%d", i+1); got != want {
+			t.Errorf("%d: got PAA %s, want %s", i, got, want)
+		}
+		if got, err := cliConn.GetSessionByIMSI(sess.IMSI); err != nil || got != sess {
+			t.Errorf("%d: session not added: %v", i, err)
+		}
+	}
+
+	rejected := results[50]
+	var causeErr *v2.ErrCauseNotOK
+	if !errors.As(rejected.Err, &causeErr) || causeErr.Cause != v2.CauseUserAuthenticationFailed {
+		t.Errorf("got %v, want ErrCauseNotOK", rejected.Err)
+	}
+	if rejected.Session != nil || rejected.Response == nil {
+		t.Errorf("got session %v, response %v", rejected.Session, rejected.Response)
+	}
+	if _, err := cliConn.GetSessionByIMSI("123451234567899"); err == nil {
+		t.Error("session rejected is added")
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, res := range cliConn.CreateSessions(ctx, specs[:3]) {
+			if !errors.Is(res.Err, context.Canceled) {
+				t.Errorf("got %v, want %v", res.Err, context.Canceled)
+			}
+		}
+	})
+}
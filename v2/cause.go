@@ -0,0 +1,196 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// IsAcceptedCause reports whether cause is in the acceptance range defined in
+// TS 29.274 8.4, e.g., CauseRequestAccepted and CauseRequestAcceptedPartially.
+func IsAcceptedCause(cause uint8) bool {
+	return cause >= CauseRequestAccepted && cause <= 63
+}
+
+// BearerCause is the Cause of a Bearer Context in a response.
+type BearerCause struct {
+	EBI   uint8
+	Cause uint8
+	// Remote is the Cause Source(CS) flag, which is set if the Cause is originated by
+	// the remote node, e.g., P-GW in Create Session Response relayed by S-GW.
+	Remote bool
+	// Inherited is set if the Bearer Context has no Cause, and Cause is the one of the
+	// message instead.
+	Inherited bool
+	// MarkedForRemoval is set if the Bearer Context is the one marked for removal, e.g.,
+	// BearerContextsMarkedForRemoval in Modify Bearer Response.
+	MarkedForRemoval bool
+}
+
+// Accepted reports whether the bearer is accepted.
+func (b BearerCause) Accepted() bool {
+	return IsAcceptedCause(b.Cause)
+}
+
+// ResponseCauses is the message-level Cause of a response merged with the ones of the
+// Bearer Contexts in it, to act on the partially accepted responses.
+type ResponseCauses struct {
+	MsgType string
+	Cause   uint8
+	// Remote is the Cause Source(CS) flag of the message-level Cause.
+	Remote  bool
+	Bearers []BearerCause
+}
+
+// CausesOf returns the Causes in the response msg.
+//
+// The Bearer Contexts without Cause inherit the message-level one. It returns
+// ErrRequiredIEMissing if msg has no Cause, or messages.ErrIENotExpected if msg cannot
+// have it.
+func CausesOf(msg messages.Message) (*ResponseCauses, error) {
+	ie, err := messages.CauseIE(msg)
+	switch {
+	case errors.Is(err, messages.ErrIEMissing):
+		return nil, &ErrRequiredIEMissing{Type: ies.Cause}
+	case err != nil:
+		return nil, err
+	}
+	cause, err := ie.ParseCause()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Cause in %s: %w", msg.MessageTypeName(), err)
+	}
+
+	r := &ResponseCauses{
+		MsgType: msg.MessageTypeName(),
+		Cause:   cause,
+		Remote:  ie.IsRemoteCause(),
+	}
+	for _, bc := range responseBearerContexts(msg) {
+		if err := r.addBearer(bc.ie, bc.markedForRemoval); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *ResponseCauses) addBearer(bc *ies.IE, markedForRemoval bool) error {
+	children, err := bc.Children()
+	if err != nil {
+		return err
+	}
+
+	b := BearerCause{
+		Cause:            r.Cause,
+		Remote:           r.Remote,
+		Inherited:        true,
+		MarkedForRemoval: markedForRemoval,
+	}
+	for _, child := range children {
+		switch child.Type {
+		case ies.EPSBearerID:
+			b.EBI = child.EPSBearerID()
+		case ies.Cause:
+			cause, err := child.ParseCause()
+			if err != nil {
+				return err
+			}
+			b.Cause, b.Remote, b.Inherited = cause, child.IsRemoteCause(), false
+		}
+	}
+	r.Bearers = append(r.Bearers, b)
+	return nil
+}
+
+// Accepted reports whether the request is accepted at the message level, which may be
+// partially accepted with some bearers rejected.
+func (r *ResponseCauses) Accepted() bool {
+	return IsAcceptedCause(r.Cause)
+}
+
+// Partial reports whether the request is accepted but some of the bearers are rejected.
+func (r *ResponseCauses) Partial() bool {
+	return r.Accepted() && len(r.RejectedEBIs()) > 0
+}
+
+// AcceptedEBIs returns the EBIs of the bearers accepted, which is none if the request is
+// rejected at the message level.
+func (r *ResponseCauses) AcceptedEBIs() []uint8 {
+	if !r.Accepted() {
+		return nil
+	}
+
+	var ebis []uint8
+	for _, b := range r.Bearers {
+		if b.Accepted() {
+			ebis = append(ebis, b.EBI)
+		}
+	}
+	return ebis
+}
+
+// RejectedEBIs returns the EBIs of the bearers rejected, which is all the bearers if the
+// request is rejected at the message level.
+func (r *ResponseCauses) RejectedEBIs() []uint8 {
+	var ebis []uint8
+	for _, b := range r.Bearers {
+		if !r.Accepted() || !b.Accepted() {
+			ebis = append(ebis, b.EBI)
+		}
+	}
+	return ebis
+}
+
+// Bearer returns the BearerCause of the bearer with ebi, or false if the response has
+// no Bearer Context of it.
+func (r *ResponseCauses) Bearer(ebi uint8) (BearerCause, bool) {
+	for _, b := range r.Bearers {
+		if b.EBI == ebi {
+			return b, true
+		}
+	}
+	return BearerCause{}, false
+}
+
+// Err returns *ErrCauseNotOK if the request is rejected at the message level, or nil
+// otherwise even if some bearers are rejected.
+func (r *ResponseCauses) Err() error {
+	if r.Accepted() {
+		return nil
+	}
+	return &ErrCauseNotOK{MsgType: r.MsgType, Cause: r.Cause}
+}
+
+type responseBearerContext struct {
+	ie               *ies.IE
+	markedForRemoval bool
+}
+
+// responseBearerContexts returns the Bearer Contexts in the response msg.
+func responseBearerContexts(msg messages.Message) []responseBearerContext {
+	var bcs []responseBearerContext
+	add := func(ie *ies.IE, markedForRemoval bool) {
+		if ie != nil {
+			bcs = append(bcs, responseBearerContext{ie, markedForRemoval})
+		}
+	}
+
+	switch m := msg.(type) {
+	case *messages.CreateSessionResponse:
+		add(m.BearerContextsCreated, false)
+		add(m.BearerContextMarkedForRemoval, true)
+	case *messages.ModifyBearerResponse:
+		add(m.BearerContextsModified, false)
+		add(m.BearerContextsMarkedForRemoval, true)
+	case *messages.CreateBearerResponse:
+		add(m.BearerContexts, false)
+	case *messages.DeleteBearerResponse:
+		add(m.BearerContexts, false)
+	}
+	return bcs
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestCausesOf(t *testing.T) {
+	t.Run("partially-accepted", func(t *testing.T) {
+		mbRsp := messages.NewModifyBearerResponse(
+			0x11111111, 1,
+			ies.NewCause(v2.CauseRequestAcceptedPartially, 0, 0, 0, nil),
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(5),
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			),
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(6),
+				ies.NewCause(v2.CauseNoResourcesAvailable, 0, 0, 1, nil),
+			).WithInstance(1),
+		)
+		got, err := v2.CausesOf(mbRsp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &v2.ResponseCauses{
+			MsgType: "Modify Bearer Response",
+			Cause:   v2.CauseRequestAcceptedPartially,
+			Bearers: []v2.BearerCause{
+				{EBI: 5, Cause: v2.CauseRequestAccepted},
+				{EBI: 6, Cause: v2.CauseNoResourcesAvailable, Remote: true, MarkedForRemoval: true},
+			},
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Fatal(diff)
+		}
+		if !got.Accepted() || !got.Partial() || got.Err() != nil {
+			t.Errorf("got Accepted=%v, Partial=%v, Err=%v", got.Accepted(), got.Partial(), got.Err())
+		}
+		if diff := cmp.Diff(got.AcceptedEBIs(), []uint8{5}); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff(got.RejectedEBIs(), []uint8{6}); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		csRsp := messages.NewCreateSessionResponse(
+			0x11111111, 1,
+			ies.NewCause(v2.CauseUserAuthenticationFailed, 0, 0, 1, nil),
+			ies.NewBearerContext(ies.NewEPSBearerID(5)),
+		)
+		got, err := v2.CausesOf(csRsp)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the bearer without Cause inherits the message-level one.
+		b, ok := got.Bearer(5)
+		if !ok {
+			t.Fatal("bearer not found")
+		}
+		if diff := cmp.Diff(b, v2.BearerCause{
+			EBI: 5, Cause: v2.CauseUserAuthenticationFailed, Remote: true, Inherited: true,
+		}); diff != "" {
+			t.Error(diff)
+		}
+		if got.Partial() || got.AcceptedEBIs() != nil {
+			t.Errorf("got Partial=%v, AcceptedEBIs=%v", got.Partial(), got.AcceptedEBIs())
+		}
+		var causeErr *v2.ErrCauseNotOK
+		if !errors.As(got.Err(), &causeErr) || causeErr.Cause != v2.CauseUserAuthenticationFailed {
+			t.Errorf("unexpected error: %v", got.Err())
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		var missing *v2.ErrRequiredIEMissing
+		if _, err := v2.CausesOf(messages.NewDeleteSessionResponse(0, 0)); !errors.As(err, &missing) {
+			t.Errorf("got %v, want %T", err, missing)
+		}
+		if _, err := v2.CausesOf(messages.NewEchoRequest(0)); !errors.Is(err, messages.ErrIENotExpected) {
+			t.Errorf("got %v, want %v", err, messages.ErrIENotExpected)
+		}
+	})
+}
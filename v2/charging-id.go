@@ -0,0 +1,174 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultChargingIDBlock is the number of Charging IDs reserved in the ChargingIDStore
+// at once by ChargingIDAllocator if not specified.
+const DefaultChargingIDBlock = 1024
+
+// ChargingIDStore persists the Charging IDs reserved by ChargingIDAllocator, so that
+// they are not allocated again after restarting.
+//
+// The methods are called by ChargingIDAllocator with its lock held, and are not called
+// concurrently.
+type ChargingIDStore interface {
+	// Load returns the last Charging ID reserved, or 0 if none, which is called once
+	// in NewChargingIDAllocator.
+	Load() (uint32, error)
+	// Save persists the last Charging ID reserved.
+	Save(last uint32) error
+}
+
+// ChargingIDAllocator allocates the non-zero Charging IDs in increasing order, which
+// wraps around to 1 after math.MaxUint32.
+//
+// The Charging IDs are reserved in the ChargingIDStore by blocks ahead of the
+// allocation, like the sequences in the databases, not to write to the store on every
+// allocation. The ones reserved but not allocated before restarting are skipped.
+type ChargingIDAllocator struct {
+	mu        sync.Mutex
+	store     ChargingIDStore
+	block     uint32
+	last      uint32
+	remaining uint32
+}
+
+// NewChargingIDAllocator creates a new ChargingIDAllocator that allocates the Charging
+// IDs after the last one reserved in store, reserving block of them at once. block is
+// DefaultChargingIDBlock if 0.
+//
+// store can be nil not to persist the Charging IDs, which are allocated from 1 again
+// after restarting.
+func NewChargingIDAllocator(store ChargingIDStore, block uint32) (*ChargingIDAllocator, error) {
+	if block == 0 {
+		block = DefaultChargingIDBlock
+	}
+	a := &ChargingIDAllocator{store: store, block: block}
+
+	if store == nil {
+		return a, nil
+	}
+	last, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Charging ID: %w", err)
+	}
+	a.last = last
+	return a, nil
+}
+
+// Allocate returns the next Charging ID, or the error if it fails to reserve it in the
+// ChargingIDStore.
+func (a *ChargingIDAllocator) Allocate() (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	next := a.last + 1
+	if next == 0 {
+		next = 1
+		a.remaining = 0
+	}
+
+	if a.store != nil && a.remaining == 0 {
+		// the block is cut at math.MaxUint32 to reserve the one after wrapping around
+		// from 1 again.
+		end := next + a.block - 1
+		if end < next {
+			end = math.MaxUint32
+		}
+		if err := a.store.Save(end); err != nil {
+			return 0, fmt.Errorf("failed to reserve Charging ID: %w", err)
+		}
+		a.remaining = end - next + 1
+	}
+
+	a.last = next
+	if a.remaining > 0 {
+		a.remaining--
+	}
+	return next, nil
+}
+
+// ChargingIDFileStore is a ChargingIDStore that keeps the last Charging ID reserved in
+// a file in decimal.
+type ChargingIDFileStore struct {
+	path string
+}
+
+// NewChargingIDFileStore creates a new ChargingIDFileStore with the file at path, which
+// is created when the first Charging ID is reserved.
+func NewChargingIDFileStore(path string) *ChargingIDFileStore {
+	return &ChargingIDFileStore{path: path}
+}
+
+// Load reads the last Charging ID reserved from the file. It returns 0 if the file does
+// not exist.
+func (s *ChargingIDFileStore) Load() (uint32, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	last, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(last), nil
+}
+
+// Save replaces the file with the last Charging ID reserved, through the temporary file
+// not to leave the file broken on failure.
+func (s *ChargingIDFileStore) Save(last uint32) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(strconv.FormatUint(uint64(last), 10) + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
+
+// SetChargingIDAllocator sets the ChargingIDAllocator returned by ChargingIDAllocator,
+// e.g., the one with the ChargingIDStore to keep the Charging IDs unique after
+// restarting. nil resets it to the default one.
+func (c *Conn) SetChargingIDAllocator(a *ChargingIDAllocator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chargingIDs = a
+}
+
+// ChargingIDAllocator returns the ChargingIDAllocator of the Conn to allocate the
+// Charging IDs of the bearers created on P-GW. It is the one without ChargingIDStore
+// unless set with SetChargingIDAllocator.
+func (c *Conn) ChargingIDAllocator() *ChargingIDAllocator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chargingIDs == nil {
+		// cannot fail without the store.
+		c.chargingIDs, _ = NewChargingIDAllocator(nil, 0)
+	}
+	return c.chargingIDs
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+type chargingIDStore struct {
+	last  uint32
+	saves []uint32
+	err   error
+}
+
+func (s *chargingIDStore) Load() (uint32, error) { return s.last, nil }
+
+func (s *chargingIDStore) Save(last uint32) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.last = last
+	s.saves = append(s.saves, last)
+	return nil
+}
+
+func allocateChargingIDs(t *testing.T, a *v2.ChargingIDAllocator, n int) []uint32 {
+	t.Helper()
+
+	ids := make([]uint32, n)
+	for i := range ids {
+		id, err := a.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	return ids
+}
+
+func TestChargingIDAllocator(t *testing.T) {
+	store := &chargingIDStore{}
+	a, err := v2.NewChargingIDAllocator(store, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(allocateChargingIDs(t, a, 4), []uint32{1, 2, 3, 4}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(store.saves, []uint32{3, 6}); diff != "" {
+		t.Error(diff)
+	}
+
+	// the ones reserved but not allocated are skipped after restarting.
+	a, err = v2.NewChargingIDAllocator(store, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(allocateChargingIDs(t, a, 1), []uint32{7}); diff != "" {
+		t.Error(diff)
+	}
+
+	store.err = errors.New("disk full")
+	a, err = v2.NewChargingIDAllocator(store, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Allocate(); !errors.Is(err, store.err) {
+		t.Errorf("got %v, want %v", err, store.err)
+	}
+}
+
+func TestChargingIDAllocatorWrapAround(t *testing.T) {
+	store := &chargingIDStore{last: math.MaxUint32 - 1}
+	a, err := v2.NewChargingIDAllocator(store, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(allocateChargingIDs(t, a, 3), []uint32{math.MaxUint32, 1, 2}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(store.saves, []uint32{math.MaxUint32, 3}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestChargingIDFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "charging-id")
+
+	a, err := v2.NewChargingIDAllocator(v2.NewChargingIDFileStore(path), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocateChargingIDs(t, a, 11)
+
+	last, err := v2.NewChargingIDFileStore(path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != 20 {
+		t.Errorf("got %d, want 20", last)
+	}
+}
+
+func TestAllocateChargingID(t *testing.T) {
+	conn := &v2.Conn{}
+	br := v2.NewBearer(5, "", &v2.QoSProfile{})
+
+	got, err := v2.NewBearerContextBuilder(v2.BearerContextCreated, 5).
+		AllocateChargingID(conn.ChargingIDAllocator(), br).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.ChargingID != 1 {
+		t.Errorf("got %d, want 1", br.ChargingID)
+	}
+
+	want := ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(1))
+	if diff := cmp.Diff(serialize(t, got), serialize(t, want)); diff != "" {
+		t.Error(diff)
+	}
+
+	store := &chargingIDStore{err: errors.New("disk full")}
+	a, err := v2.NewChargingIDAllocator(store, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetChargingIDAllocator(a)
+	if _, err := v2.NewBearerContextBuilder(v2.BearerContextCreated, 5).
+		AllocateChargingID(conn.ChargingIDAllocator(), nil).
+		Build(); !errors.Is(err, store.err) {
+		t.Errorf("got %v, want %v", err, store.err)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"encoding/binary"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
@@ -20,14 +22,41 @@ type Conn struct {
 	mu      sync.Mutex
 	pktConn net.PacketConn
 
-	validationEnabled bool
-
-	rcvBuf []byte
+	validationEnabled         bool
+	peerValidationEnabled     bool
+	zeroTEIDValidationEnabled bool
+	peerTrackingEnabled       bool
+	recoveryInclusionEnabled  bool
+	canonicalIEOrderEnabled   bool
+	responsePolicy            ResponsePolicy
+
+	rcvBuf    []byte
+	sendBufs  sendbuf.Buffers
+	contacted sync.Map
+	stats     connStats
+	txs       transactions
+	exchanges sync.Map
 
 	closeCh chan struct{}
 	errCh   chan error
 
 	*msgHandlerMap
+	nodeHandlers msgHandlerMap
+
+	sessMu               sync.RWMutex
+	sessEventHandler     SessionEventHandlerFunc
+	usageReportHandler   UsageReportHandlerFunc
+	servingChangeHandler ServingChangeHandlerFunc
+	unansweredHandler    UnansweredHandlerFunc
+
+	msgObserver MessageObserverFunc
+	malformed   atomic.Pointer[malformedReporter]
+	dispatcher  atomic.Pointer[dispatcher]
+
+	apnPolicyProvider APNPolicyProvider
+	admittedPolicies  sync.Map
+	chargingIDs       *ChargingIDAllocator
+	timerHandlers     sync.Map
 
 	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
 	// times the GTPv2-C endpoint is restarted.
@@ -49,7 +78,7 @@ func NewConn(pktConn net.PacketConn, raddr net.Addr, counter uint8, errCh chan e
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             errCh,
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
 		RestartCounter:    counter,
 	}
 
@@ -75,6 +104,7 @@ func NewConn(pktConn net.PacketConn, raddr net.Addr, counter uint8, errCh chan e
 	if err != nil {
 		return nil, err
 	}
+	c.txs.end(raddr, msg)
 	if err := c.handleMessage(raddr, msg); err != nil {
 		return nil, err
 	}
@@ -101,7 +131,7 @@ func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error)
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             errCh,
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
 		RestartCounter:    counter,
 	}
 
@@ -136,6 +166,7 @@ func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error)
 	if err != nil {
 		return nil, err
 	}
+	c.txs.end(raddr, msg)
 	if err := c.handleMessage(raddr, msg); err != nil {
 		return nil, err
 	}
@@ -144,6 +175,30 @@ func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error)
 	return c, nil
 }
 
+// ServeConn creates a new Conn over existing net.PacketConn and start serving, without
+// the Echo exchange in NewConn.
+//
+// This is the counterpart of ListenAndServe for the net.PacketConn the user already
+// have, e.g., the one shared with GTPv1-C by gtpdemux.
+//
+// The errCh given should be monitored continuously after retrieving *Conn.
+// Otherwise the background process may get stuck.
+func ServeConn(pktConn net.PacketConn, counter uint8, errCh chan error) *Conn {
+	c := &Conn{
+		mu:                sync.Mutex{},
+		rcvBuf:            make([]byte, 2048),
+		pktConn:           pktConn,
+		validationEnabled: true,
+		closeCh:           make(chan struct{}),
+		errCh:             errCh,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		RestartCounter:    counter,
+	}
+
+	go c.serve()
+	return c
+}
+
 // ListenAndServe creates a new GTPv2-C *Conn and start serving.
 //
 // The errCh given should be monitored continuously after retrieving *Conn.
@@ -155,7 +210,7 @@ func ListenAndServe(laddr net.Addr, counter uint8, errCh chan error) (*Conn, err
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             make(chan error),
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
 		RestartCounter:    counter,
 	}
 
@@ -174,6 +229,9 @@ func (c *Conn) closed() <-chan struct{} {
 }
 
 func (c *Conn) serve() {
+	pathCh := make(chan pathMessage, pathQueueSize)
+	go c.servePathManagement(pathCh)
+
 	for {
 		select {
 		case <-c.closed():
@@ -187,8 +245,51 @@ func (c *Conn) serve() {
 			continue
 		}
 
-		msg, err := messages.Decode(c.rcvBuf[:n])
+		// the message is passed to the handler running in another goroutine, so
+		// the IEs must not refer to rcvBuf which is overwritten by the next read.
+		b := make([]byte, n)
+		copy(b, c.rcvBuf[:n])
+
+		msg, err := messages.Decode(b)
 		if err != nil {
+			c.reportMalformed(raddr, err)
+			continue
+		}
+		c.stats.received.add(msg.MessageType())
+		c.stats.receivedCauses.add(raddr, b)
+
+		// the version is checked on the wire ahead of anything else, as the Message
+		// decoded always reports version 2.
+		if b[0]>>5 != 2 && c.validationEnabled {
+			go func() {
+				if err := c.VersionNotSupportedIndication(raddr, msg); err != nil {
+					c.errCh <- err
+				}
+			}()
+			continue
+		}
+
+		c.txs.end(raddr, msg)
+		c.observe(DirectionInbound, raddr, msg)
+
+		if c.deliverExchange(raddr, msg) {
+			continue
+		}
+
+		if isPathManagement(msg.MessageType()) {
+			select {
+			case pathCh <- pathMessage{raddr, msg}:
+			default:
+			}
+			continue
+		}
+
+		if d := c.dispatcher.Load(); d != nil {
+			if err := d.dispatch(raddr, msg); err != nil {
+				go func() {
+					c.errCh <- err
+				}()
+			}
 			continue
 		}
 
@@ -220,7 +321,12 @@ func (c *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
 // see SetDeadline and SetWriteDeadline.
 // On packet-oriented connections, write timeouts are rare.
 func (c *Conn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
-	return c.pktConn.WriteTo(p, addr)
+	n, err = c.pktConn.WriteTo(p, addr)
+	if err == nil && len(p) > 1 {
+		c.stats.sent.add(p[1])
+		c.stats.sentCauses.add(addr, p)
+	}
+	return
 }
 
 // Close closes the connection.
@@ -229,8 +335,9 @@ func (c *Conn) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.msgHandlerMap = defaultHandlerMap
-	c.RestartCounter = 0
+	c.sendBufs.Reset()
+	c.contacted.Clear()
+	c.txs.reset()
 	close(c.closeCh)
 
 	// triggers error in blocking Read() / Write() immediately.
@@ -294,6 +401,14 @@ func (c *Conn) SetWriteDeadline(t time.Time) error {
 // HandlerFuncs for EchoResponse and VersionNotSupportedIndication are registered by default.
 // These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse and/or
 // messages.MsgTypeVersionNotSupportedIndication as msgType parameter.
+//
+// The HandlerFuncs for the path management messages, i.e., Echo and Version Not Supported
+// Indication, are called one by one in a goroutine dedicated to them to be handled ahead
+// of the others, so they should return quickly.
+//
+// The HandlerFuncs are called concurrently by default. With DispatchPerSession set by
+// SetDispatchPolicy, the ones for the same session are called one by one in the order
+// of arrival instead. See DispatchPerSession.
 func (c *Conn) AddHandler(msgType uint8, fn HandlerFunc) {
 	c.msgHandlerMap.store(msgType, fn)
 }
@@ -308,15 +423,9 @@ func (c *Conn) AddHandlers(funcs map[uint8]HandlerFunc) {
 }
 
 func (c *Conn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
-	if c.validationEnabled {
-		if err := c.validate(senderAddr, msg); err != nil {
-			return err
-		}
-	}
-
-	handle, ok := c.msgHandlerMap.load(msg.MessageType())
-	if !ok {
-		return ErrNoHandlersFound
+	handle, err := c.prepareHandler(senderAddr, msg)
+	if err != nil {
+		return err
 	}
 	go func() {
 		if err := handle(c, senderAddr, msg); err != nil {
@@ -327,6 +436,33 @@ func (c *Conn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
 	return nil
 }
 
+// prepareHandler validates the message and returns the HandlerFunc to handle it.
+func (c *Conn) prepareHandler(senderAddr net.Addr, msg messages.Message) (HandlerFunc, error) {
+	if c.validationEnabled {
+		if err := c.validate(senderAddr, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	handle, ok := c.loadHandler(msg)
+	if !ok {
+		return nil, ErrNoHandlersFound
+	}
+
+	handle, err := c.admit(senderAddr, msg, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	c.trackPeer(senderAddr, msg)
+
+	// accumulate before the handler runs, as it may remove the session.
+	c.accumulateUsageReports(msg)
+	c.updateServing(msg)
+	c.updateCSGReportingAction(msg)
+	return handle, nil
+}
+
 // EnableValidation turns on automatic validation of incoming messages.
 // This is expected to be used only after DisableValidation() is used, as the validation
 // is enabled by default.
@@ -344,6 +480,28 @@ func (c *Conn) DisableValidation() {
 	c.validationEnabled = false
 }
 
+// EnablePeerValidation turns on the validation of the sender of the incoming messages
+// with TEID, in addition to the automatic validation. The message is discarded with
+// ErrUnexpectedPeer if it comes from the IP address other than the PeerAddr of the
+// Session with the TEID, to prevent the off-path attackers from injecting messages to
+// the existing sessions with the TEIDs guessed.
+//
+// This is disabled by default, as the peer may send the messages from the address other
+// than the one the Session is created with, e.g., the one with multiple addresses.
+// PeerAddr should be kept up-to-date when enabled, e.g., on S-GW relocation.
+func (c *Conn) EnablePeerValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peerValidationEnabled = true
+}
+
+// DisablePeerValidation turns off the validation of the sender of the incoming messages.
+func (c *Conn) DisablePeerValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peerValidationEnabled = false
+}
+
 func (c *Conn) validate(senderAddr net.Addr, msg messages.Message) error {
 	// check GTP version
 	if msg.Version() != 2 {
@@ -352,52 +510,55 @@ func (c *Conn) validate(senderAddr net.Addr, msg messages.Message) error {
 		}
 	}
 
+	if err := c.validateZeroTEID(senderAddr, msg); err != nil {
+		return err
+	}
+
 	// check if TEID is known or not
 	if teid := msg.TEID(); teid != 0 {
-		if _, err := c.GetSessionByTEID(teid); err != nil {
+		sess, err := c.GetSessionByTEID(teid)
+		if err != nil {
 			return ErrInvalidTEID
 		}
+
+		c.mu.Lock()
+		peerValidationEnabled := c.peerValidationEnabled
+		c.mu.Unlock()
+		if peerValidationEnabled && sess.PeerAddr != nil && !sameIP(sess.PeerAddr, senderAddr) {
+			return ErrUnexpectedPeer
+		}
 	}
 	return nil
 }
 
-// EchoRequest sends a EchoRequest.
-func (c *Conn) EchoRequest(raddr net.Addr) error {
-	b, err := messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter)).Serialize()
-	if err != nil {
-		return err
+// sameIP reports whether the addresses have the same IP, regardless of the port.
+func sameIP(a, b net.Addr) bool {
+	ua, okA := a.(*net.UDPAddr)
+	ub, okB := b.(*net.UDPAddr)
+	if okA && okB {
+		return ua.AddrPort().Addr().Unmap() == ub.AddrPort().Addr().Unmap()
 	}
 
-	if _, err := c.pktConn.WriteTo(b, raddr); err != nil {
-		return err
-	}
-	return nil
+	hostA, _, errA := net.SplitHostPort(a.String())
+	hostB, _, errB := net.SplitHostPort(b.String())
+	return errA == nil && errB == nil && hostA == hostB
+}
+
+// EchoRequest sends a EchoRequest.
+func (c *Conn) EchoRequest(raddr net.Addr) error {
+	return c.SendMessageTo(messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter)), raddr)
 }
 
 // EchoResponse sends a EchoResponse.
 func (c *Conn) EchoResponse(raddr net.Addr) error {
-	b, err := messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := c.pktConn.WriteTo(b, raddr); err != nil {
-		return err
-	}
-	return nil
+	return c.SendMessageTo(messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)), raddr)
 }
 
 // VersionNotSupportedIndication just sends VersionNotSupportedIndication message.
+//
+// The message is sent to the address given by ResponsePolicy for raddr.
 func (c *Conn) VersionNotSupportedIndication(raddr net.Addr, received messages.Message) error {
-	vsi, err := messages.NewVersionNotSupportedIndication(0, received.Sequence()).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := c.WriteTo(vsi, raddr); err != nil {
-		return err
-	}
-	return nil
+	return c.SendMessageTo(messages.NewVersionNotSupportedIndication(0, received.Sequence()), c.responseAddr(raddr))
 }
 
 // CreateSession sends a CreateSessionRequest and stores information given with IE
@@ -408,9 +569,24 @@ func (c *Conn) VersionNotSupportedIndication(raddr net.Addr, received messages.M
 // (*Session) GetDefaultBearer() or (*Session) LookupBearerByName("default").
 //
 // Note that this method doesn't care IEs given are sufficient or not, as the required IE
-// varies much depending on the context Create Session Request is used.
+// varies much depending on the context Create Session Request is used. The error is
+// returned without sending the request if the IMSI or F-TEIDs given are malformed.
 func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
-	// retrieve values from IEs given.
+	sess, err := newSessionWithIEs(raddr, ie...)
+	if err != nil {
+		return nil, err
+	}
+
+	// set IEs into CreateSessionRequest .
+	if err := c.SendMessageTo(messages.NewCreateSessionRequest(0, sess.Sequence, ie...), raddr); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// newSessionWithIEs creates a new Session with the values retrieved from the IEs to be
+// sent in Create Session Request.
+func newSessionWithIEs(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
 	sess := NewSession(raddr, &Subscriber{Location: &Location{}})
 	br := sess.GetDefaultBearer()
 	for _, i := range ie {
@@ -419,11 +595,15 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
 		}
 		switch i.Type {
 		case ies.IMSI:
-			sess.IMSI = i.IMSI()
+			imsi, err := i.ParseIMSI()
+			if err != nil {
+				return nil, err
+			}
+			sess.IMSI = imsi
 		case ies.MSISDN:
 			sess.MSISDN = i.MSISDN()
 		case ies.MobileEquipmentIdentity:
-			sess.IMEI = i.MobileEquipmentIdentity()
+			sess.IMEI, sess.SVN = i.IMEI(), i.SoftwareVersion()
 		case ies.ServingNetwork:
 			sess.MCC = i.MCC()
 			sess.MNC = i.MNC()
@@ -432,11 +612,13 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
 		case ies.RATType:
 			sess.RATType = i.RATType()
 		case ies.FullyQualifiedTEID:
-			sess.AddTEID(i.InterfaceType(), i.TEID())
+			if err := sess.addFTEID(i); err != nil {
+				return nil, err
+			}
 		case ies.BearerContext:
 			switch i.Instance() {
 			case 0:
-				for _, child := range i.ChildIEs {
+				for _, child := range i.BearerContext() {
 					switch child.Type {
 					case ies.EPSBearerID:
 						br.EBI = child.EPSBearerID()
@@ -450,7 +632,9 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
 						br.GBRUL = child.GBRForUplink()
 						br.GBRDL = child.GBRForUplink()
 					case ies.FullyQualifiedTEID:
-						sess.AddTEID(i.InterfaceType(), i.TEID())
+						if err := sess.addFTEID(child); err != nil {
+							return nil, err
+						}
 					case ies.BearerTFT:
 						// XXX - do nothing for BearerTFT?
 					}
@@ -460,16 +644,6 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, error) {
 			}
 		}
 	}
-
-	// set IEs into CreateSessionRequest .
-	csr, err := messages.NewCreateSessionRequest(0, sess.Sequence, ie...).Serialize()
-	if err != nil {
-		return nil, err
-	}
-
-	if _, err := c.WriteTo(csr, raddr); err != nil {
-		return nil, err
-	}
 	return sess, nil
 }
 
@@ -480,12 +654,7 @@ func (c *Conn) DeleteSession(teid uint32, ie ...*ies.IE) error {
 		return err
 	}
 
-	dsr, err := messages.NewDeleteSessionRequest(teid, sess.Sequence+1, ie...).Serialize()
-	if err != nil {
-		return err
-	}
-
-	if _, err := c.WriteTo(dsr, sess.PeerAddr); err != nil {
+	if err := c.SendMessageTo(messages.NewDeleteSessionRequest(teid, sess.Sequence+1, ie...), sess.PeerAddr); err != nil {
 		return err
 	}
 	sess.Sequence++
@@ -499,31 +668,51 @@ func (c *Conn) ModifyBearer(teid uint32, ie ...*ies.IE) error {
 		return err
 	}
 
-	mbr, err := messages.NewModifyBearerRequest(teid, sess.Sequence+1, ie...).Serialize()
+	if err := c.SendMessageTo(messages.NewModifyBearerRequest(teid, sess.Sequence+1, ie...), sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// DeleteBearer sends a DeleteBearerRequest TEID and with IEs given.
+func (c *Conn) DeleteBearer(teid uint32, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(teid)
 	if err != nil {
 		return err
 	}
 
-	if _, err := c.WriteTo(mbr, sess.PeerAddr); err != nil {
+	if err := c.SendMessageTo(messages.NewDeleteBearerRequest(teid, sess.Sequence+1, ie...), sess.PeerAddr); err != nil {
 		return err
 	}
 	sess.Sequence++
 	return nil
 }
 
-// DeleteBearer sends a DeleteBearerRequest TEID and with IEs given.
-func (c *Conn) DeleteBearer(teid uint32, ie ...*ies.IE) error {
+// CreateIndirectDataForwardingTunnel sends a CreateIndirectDataForwardingTunnelRequest
+// with TEID and IEs given.
+func (c *Conn) CreateIndirectDataForwardingTunnel(teid uint32, ie ...*ies.IE) error {
 	sess, err := c.GetSessionByTEID(teid)
 	if err != nil {
 		return err
 	}
 
-	dbr, err := messages.NewDeleteBearerRequest(teid, sess.Sequence+1, ie...).Serialize()
+	if err := c.SendMessageTo(messages.NewCreateIndirectDataForwardingTunnelRequest(teid, sess.Sequence+1, ie...), sess.PeerAddr); err != nil {
+		return err
+	}
+	sess.Sequence++
+	return nil
+}
+
+// DeleteIndirectDataForwardingTunnel sends a DeleteIndirectDataForwardingTunnelRequest
+// with TEID and IEs given.
+func (c *Conn) DeleteIndirectDataForwardingTunnel(teid uint32, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(teid)
 	if err != nil {
 		return err
 	}
 
-	if _, err := c.WriteTo(dbr, sess.PeerAddr); err != nil {
+	if err := c.SendMessageTo(messages.NewDeleteIndirectDataForwardingTunnelRequest(teid, sess.Sequence+1, ie...), sess.PeerAddr); err != nil {
 		return err
 	}
 	sess.Sequence++
@@ -533,22 +722,53 @@ func (c *Conn) DeleteBearer(teid uint32, ie ...*ies.IE) error {
 // RespondTo sends a message(specified with "toBeSent" param) in response to
 // a message(specified with "received" param).
 //
-// This is to make it easier to handle SequenceNumber.
+// This is to make it easier to handle SequenceNumber. The message is sent to the
+// address given by ResponsePolicy for raddr, which is the sender of "received".
 func (c *Conn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
 	toBeSent.SetSequenceNumber(received.Sequence())
-	b := make([]byte, toBeSent.Len())
-	if err := toBeSent.SerializeTo(b); err != nil {
+	return c.SendMessageTo(toBeSent, c.responseAddr(raddr))
+}
+
+// SendMessageTo serializes the message and writes it to addr.
+//
+// The message is serialized into the buffer kept for each addr, which is reused
+// for the messages sent to the same addr afterwards, instead of allocating a new
+// one for each message. The Payload of msg is copied out of the buffer, as msg may
+// be kept after sent, e.g., by the MessageObserverFunc.
+func (c *Conn) SendMessageTo(msg messages.Message, addr net.Addr) error {
+	key := sendbuf.KeyOf(addr)
+	_, contacted := c.contacted.Load(key)
+	hasRecovery := c.includeRecovery(msg, !contacted)
+
+	buf := c.sendBufs.Get(addr)
+	buf.Lock()
+	defer buf.Unlock()
+
+	b := buf.Bytes(msg.Len())
+	if err := c.serialize(msg, b); err != nil {
 		return err
 	}
+	messages.DetachPayload(msg)
 
-	if _, err := c.WriteTo(b, raddr); err != nil {
+	if err := c.beginTransaction(addr, msg); err != nil {
 		return err
 	}
+	if _, err := c.WriteTo(b, addr); err != nil {
+		c.txs.cancel(addr, msg.Sequence())
+		return err
+	}
+	if hasRecovery {
+		c.contacted.Store(key, struct{}{})
+	}
+	c.observe(DirectionOutbound, addr, msg)
 	return nil
 }
 
 // GetSessionByTEID returns the current session looked up by InterfaceType and TEID of the message.
 func (c *Conn) GetSessionByTEID(teid uint32) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
 	var session *Session
 	for _, sess := range c.Sessions {
 		sess.teidMap.rangeWithFunc(func(i, t interface{}) bool {
@@ -568,6 +788,9 @@ func (c *Conn) GetSessionByTEID(teid uint32) (*Session, error) {
 
 // GetSessionByIMSI returns the current session looked up by IMSI.
 func (c *Conn) GetSessionByIMSI(imsi string) (*Session, error) {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
 	for _, sess := range c.Sessions {
 		if imsi == sess.IMSI {
 			return sess, nil
@@ -590,6 +813,16 @@ func (c *Conn) GetIMSIByTEID(teid uint32) (string, error) {
 // AddSession adds a session to c.Sessions.
 // If the session given already exists, this removes the old one.
 func (c *Conn) AddSession(session *Session) {
+	c.sessMu.Lock()
+	c.addSession(session)
+	fn := c.sessEventHandler
+	c.sessMu.Unlock()
+
+	c.notifySessionEvent(fn, SessionEventAdded, session)
+}
+
+// the caller must hold sessMu.
+func (c *Conn) addSession(session *Session) {
 	// TODO: any smarter way?
 	if len(c.Sessions) == 0 {
 		c.Sessions = []*Session{session}
@@ -604,6 +837,10 @@ func (c *Conn) AddSession(session *Session) {
 		if session.IMSI == oldSession.IMSI {
 			exists = true
 			newSessions = append(newSessions, session)
+			if oldSession != session {
+				oldSession.abandonWaiters()
+				oldSession.stopTimers()
+			}
 			continue
 		}
 		newSessions = append(newSessions, oldSession)
@@ -617,42 +854,78 @@ func (c *Conn) AddSession(session *Session) {
 
 // RemoveSession removes a session from c.Session.
 func (c *Conn) RemoveSession(session *Session) {
-	var newSessions []*Session
+	c.sessMu.Lock()
+	var (
+		newSessions []*Session
+		removed     *Session
+	)
 	for _, sess := range c.Sessions {
 		if session.IMSI == sess.IMSI {
+			removed = sess
 			continue
 		}
 		newSessions = append(newSessions, sess)
 	}
-
 	c.Sessions = newSessions
+	fn, reportFn := c.sessEventHandler, c.usageReportHandler
+	c.sessMu.Unlock()
+
+	if removed != nil {
+		removed.abandonWaiters()
+		removed.stopTimers()
+		c.notifySessionEvent(fn, SessionEventRemoved, removed)
+		c.notifyUsageReports(reportFn, removed)
+	}
+}
+
+// ListSessions returns a copy of c.Sessions, which is safe to iterate over while
+// the sessions are added or removed by the handlers.
+func (c *Conn) ListSessions() []*Session {
+	c.sessMu.RLock()
+	defer c.sessMu.RUnlock()
+
+	sessions := make([]*Session, len(c.Sessions))
+	copy(sessions, c.Sessions)
+	return sessions
 }
 
 // NewFTEID creates a new F-TEID with random TEID value that is different from existing one.
-// If there's a lot of Session on the Conn, it may take a long time to find unique one.
+// See NewTEID for how the TEID is generated.
 func (c *Conn) NewFTEID(ifType uint8, v4, v6 string) (fteidIE *ies.IE) {
-	var teids []uint32
+	return ies.NewFullyQualifiedTEID(ifType, c.NewTEID(), v4, v6)
+}
+
+// NewTEID returns a non-zero TEID drawn from crypto/rand, which is different from all
+// the TEIDs in the Sessions on the Conn regardless of the interface type, so that the
+// TEIDs are not predictable by the off-path attackers nor confused with the others.
+// If there's a lot of Session on the Conn, it may take a long time to find unique one.
+func (c *Conn) NewTEID() uint32 {
+	c.sessMu.RLock()
+	teids := map[uint32]struct{}{}
 	for _, sess := range c.Sessions {
-		if teid, ok := sess.teidMap.load(ifType); ok {
-			teids = append(teids, teid)
-		}
+		sess.teidMap.rangeWithFunc(func(_, t interface{}) bool {
+			teids[t.(uint32)] = struct{}{}
+			return true
+		})
 	}
+	c.sessMu.RUnlock()
 
-	return ies.NewFullyQualifiedTEID(ifType, generateUniqueUint32(teids), v4, v6)
+	return generateUniqueUint32(teids)
 }
 
-func generateUniqueUint32(vals []uint32) uint32 {
+func generateUniqueUint32(vals map[uint32]struct{}) uint32 {
 	b := make([]byte, 4)
-	if _, err := rand.Read(b); err != nil {
-		return 0
-	}
+	for {
+		// crypto/rand.Read never returns an error, and crashes the program
+		// instead if the system fails to provide the random bytes.
+		_, _ = rand.Read(b)
 
-	generated := binary.BigEndian.Uint32(b)
-	for _, existing := range vals {
-		if generated == existing {
-			return generateUniqueUint32(vals)
+		generated := binary.BigEndian.Uint32(b)
+		if generated == 0 {
+			continue
+		}
+		if _, exists := vals[generated]; !exists {
+			return generated
 		}
 	}
-
-	return generated
 }
@@ -4,6 +4,12 @@
 
 package v2
 
+//go:generate go run ../internal/genconst -file $GOFILE -type IFType -block "InterfaceType definitions." -trim IFType
+//go:generate go run ../internal/genconst -file $GOFILE -type Cause -block "Cause definitions." -trim Cause
+//go:generate go run ../internal/genconst -file $GOFILE -type RATType -block "RAT Type definitions." -trim RATType
+//go:generate go run ../internal/genconst -file $GOFILE -type APNRestriction -block "APN Restriction definitions." -trim APNRestriction
+//go:generate go run ../internal/genconst -file $GOFILE -type SelectionMode -block "SelectionMode definitions." -trim SelectionMode
+
 // InterfaceType definitions.
 const (
 	IFTypeS1UeNodeBGTPU uint8 = iota
@@ -48,6 +54,12 @@ const (
 	IFTypeS11SGWGTPU
 )
 
+// IFType is the type of the InterfaceType values, which gives the name with String().
+//
+// The IFType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into IFType to get the name.
+type IFType uint8
+
 // APN Restriction definitions.
 const (
 	APNRestrictionNoExistingContextsorRestriction uint8 = iota
@@ -57,6 +69,13 @@ const (
 	APNRestrictionPrivate2
 )
 
+// APNRestriction is the type of the APN Restriction values, which gives the name with
+// String().
+//
+// The APNRestriction* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into APNRestriction to get the name.
+type APNRestriction uint8
+
 // Cause definitions.
 const (
 	_                                                                                   uint8 = 0
@@ -146,6 +165,12 @@ const (
 	CauseS1UPathFailure                                                                 uint8 = 128
 )
 
+// Cause is the type of the Cause values, which gives the name with String().
+//
+// The Cause* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into Cause to get the name.
+type Cause uint8
+
 // CSG Membership Indication definitions.
 const (
 	CMINonCSG uint8 = iota
@@ -269,6 +294,18 @@ const (
 	RATTypeNR
 )
 
+// RATType is the type of the RAT Type values, which gives the name with String().
+//
+// The RATType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into RATType to get the name.
+type RATType uint8
+
+// Secondary RAT Type definitions.
+const (
+	SecondaryRATTypeNR uint8 = iota
+	SecondaryRATTypeUnlicensedSpectrum
+)
+
 // SelectionMode definitions.
 const (
 	SelectionModeMSorNetworkProvidedAPNSubscribedVerified uint8 = iota
@@ -276,6 +313,13 @@ const (
 	SelectionModeNetworkProvidedAPNSubscriptionNotVerified
 )
 
+// SelectionMode is the type of the SelectionMode values, which gives the name with
+// String().
+//
+// The SelectionMode* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from IE into SelectionMode to get the name.
+type SelectionMode uint8
+
 // Service Indicator definitions.
 const (
 	_ uint8 = iota
@@ -0,0 +1,87 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestConstantNames(t *testing.T) {
+	cases := []struct {
+		description string
+		got, want   string
+	}{
+		{"IFType", v2.IFType(v2.IFTypeS11MMEGTPC).String(), "S11MMEGTPC"},
+		{"Cause", v2.Cause(v2.CauseRequestAccepted).String(), "RequestAccepted"},
+		{"RATType", v2.RATType(v2.RATTypeEUTRAN).String(), "EUTRAN"},
+		{"APNRestriction", v2.APNRestriction(v2.APNRestrictionPublic2).String(), "Public2"},
+		{"SelectionMode", v2.SelectionMode(v2.SelectionModeMSProvidedAPNSubscriptionNotVerified).String(), "MSProvidedAPNSubscriptionNotVerified"},
+		{"MessageType", messages.MessageType(messages.MsgTypeCreateSessionRequest).String(), "CreateSessionRequest"},
+		{"Unknown", v2.Cause(200).String(), "Cause(200)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if c.got != c.want {
+				t.Errorf("got %s, want %s", c.got, c.want)
+			}
+		})
+	}
+}
+
+func TestCauseFromString(t *testing.T) {
+	cases := []struct {
+		description string
+		name        string
+		want        uint8
+	}{
+		{"Known", "ContextNotFound", v2.CauseContextNotFound},
+		{"Unknown", "Cause(200)", 200},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := v2.CauseFromString(c.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if uint8(got) != c.want {
+				t.Errorf("got %d, want %d", got, c.want)
+			}
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := v2.CauseFromString("NoSuchCause"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestCauseJSON(t *testing.T) {
+	type result struct {
+		Cause v2.Cause `json:"cause"`
+	}
+
+	b, err := json.Marshal(&result{Cause: v2.Cause(v2.CauseNoResourcesAvailable)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"cause":"NoResourcesAvailable"}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	decoded := &result{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if uint8(decoded.Cause) != v2.CauseNoResourcesAvailable {
+		t.Errorf("got %d, want %d", decoded.Cause, v2.CauseNoResourcesAvailable)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// CSGReportingAction is the CSG Information Reporting Action set by P-GW, with the same
+// bits as the IE, e.g., ies.CSGReportingFlagCSG. 0 means not to report.
+type CSGReportingAction uint8
+
+// Has reports whether the reporting of the flag, e.g., ies.CSGReportingFlagCSG, is set.
+func (a CSGReportingAction) Has(flag uint8) bool {
+	return uint8(a)&flag != 0
+}
+
+var csgReportingNames = []string{"CSG", "SubscribedHybrid", "UnsubscribedHybrid"}
+
+// String returns the names of the reportings set joined with "|".
+func (a CSGReportingAction) String() string {
+	var names []string
+	for i, name := range csgReportingNames {
+		if a.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
+
+// CSGReportingAction returns the CSG Information Reporting Action of the Session last
+// set with SetCSGReportingAction.
+func (s *Session) CSGReportingAction() CSGReportingAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.csgReportingAction
+}
+
+// SetCSGReportingAction sets the CSG Information Reporting Action of the Session from the
+// CSGInformationReportingAction IE, which replaces the one set previously.
+//
+// Conn sets it automatically on the Session with the TEID of Create Session Response,
+// Modify Bearer Response and Create Bearer Request received with the IE.
+func (s *Session) SetCSGReportingAction(ie *ies.IE) error {
+	if ie.Type != ies.CSGInformationReportingAction {
+		return ies.ErrInvalidType
+	}
+	if len(ie.Payload) < 1 {
+		return ies.ErrTooShortToDecode
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.csgReportingAction = CSGReportingAction(ie.CSGInformationReportingAction())
+	return nil
+}
+
+// ShouldReportUCI reports whether the UserCSGInformation IE uci should be reported to
+// P-GW by the CSG Information Reporting Action of the Session, i.e., it is of the CSG
+// cell, or of the hybrid cell the UE is a member of or not, whose reporting is set.
+func (s *Session) ShouldReportUCI(uci *ies.IE) bool {
+	if uci == nil || uci.Type != ies.UserCSGInformation {
+		return false
+	}
+
+	action := s.CSGReportingAction()
+	switch {
+	case uci.AccessMode() == AccessModeClosed:
+		return action.Has(ies.CSGReportingFlagCSG)
+	case uci.CMI() == CMICSG:
+		return action.Has(ies.CSGReportingFlagSubscribedHybrid)
+	default:
+		return action.Has(ies.CSGReportingFlagUnsubscribedHybrid)
+	}
+}
+
+// ReportableUCI returns uci if it should be reported as ShouldReportUCI reports, or nil
+// otherwise, which is ignored by the message constructors, e.g.,
+//
+//	messages.NewModifyBearerRequest(teid, seq, sess.ReportableUCI(uci), ...)
+func (s *Session) ReportableUCI(uci *ies.IE) *ies.IE {
+	if !s.ShouldReportUCI(uci) {
+		return nil
+	}
+	return uci
+}
+
+// updateCSGReportingAction sets the CSG Information Reporting Action in msg to the
+// Session with the TEID of msg.
+func (c *Conn) updateCSGReportingAction(msg messages.Message) {
+	var ie *ies.IE
+	switch m := msg.(type) {
+	case *messages.CreateSessionResponse:
+		ie = m.CSGInformationReportingAction
+	case *messages.ModifyBearerResponse:
+		ie = m.CSGInformationReportingAction
+	case *messages.CreateBearerRequest:
+		ie = m.CSGInformationReportingAction
+	}
+	if ie == nil {
+		return
+	}
+
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return
+	}
+	_ = sess.SetCSGReportingAction(ie)
+}
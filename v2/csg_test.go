@@ -0,0 +1,124 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestCSGReportingAction(t *testing.T) {
+	sess := v2.NewSession(&net.UDPAddr{}, &v2.Subscriber{IMSI: "123451234567890"})
+
+	closed := ies.NewUserCSGInformation("123", "45", 0x00ffffff, v2.AccessModeClosed, 0, v2.CMICSG)
+	member := ies.NewUserCSGInformation("123", "45", 0x00ffffff, v2.AccessModeHybrid, 0, v2.CMICSG)
+	nonMember := ies.NewUserCSGInformation("123", "45", 0x00ffffff, v2.AccessModeHybrid, 0, v2.CMINonCSG)
+
+	if sess.ShouldReportUCI(closed) || sess.ShouldReportUCI(member) || sess.ShouldReportUCI(nonMember) {
+		t.Error("UCI should not be reported without the action")
+	}
+	if err := sess.SetCSGReportingAction(ies.NewCSGID(1)); err != ies.ErrInvalidType {
+		t.Errorf("got %v, want %v", err, ies.ErrInvalidType)
+	}
+
+	if err := sess.SetCSGReportingAction(
+		ies.NewCSGInformationReportingAction(ies.CSGReportingFlagCSG | ies.CSGReportingFlagUnsubscribedHybrid),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sess.CSGReportingAction().String(), "CSG|UnsubscribedHybrid"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	for _, c := range []struct {
+		description string
+		uci         *ies.IE
+		want        bool
+	}{
+		{"closed", closed, true},
+		{"hybrid-member", member, false},
+		{"hybrid-non-member", nonMember, true},
+		{"nil", nil, false},
+	} {
+		t.Run(c.description, func(t *testing.T) {
+			if got := sess.ShouldReportUCI(c.uci); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+			if got := sess.ReportableUCI(c.uci); (got != nil) != c.want {
+				t.Errorf("got %v, want reported=%v", got, c.want)
+			}
+		})
+	}
+
+	// stop reporting.
+	if err := sess.SetCSGReportingAction(ies.NewCSGInformationReportingAction(0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := sess.CSGReportingAction().String(); got != "None" {
+		t.Errorf("got %s, want None", got)
+	}
+}
+
+func TestCSGReportingActionFromPeer(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.54:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.55:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	sess := v2.NewSession(cliAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x11111111)
+	srvConn.AddSession(sess)
+
+	actionCh := make(chan v2.CSGReportingAction, 1)
+	srvConn.AddHandler(
+		messages.MsgTypeModifyBearerResponse,
+		func(c *v2.Conn, cliAddr net.Addr, msg messages.Message) error {
+			s, err := c.GetSessionByTEID(msg.TEID())
+			if err != nil {
+				return err
+			}
+			actionCh <- s.CSGReportingAction()
+			return nil
+		},
+	)
+
+	mbRsp := messages.NewModifyBearerResponse(
+		0x11111111, 1,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewCSGInformationReportingAction(ies.CSGReportingFlagSubscribedHybrid),
+	)
+	if err := cliConn.SendMessageTo(mbRsp, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-actionCh:
+		if !got.Has(ies.CSGReportingFlagSubscribedHybrid) || got.Has(ies.CSGReportingFlagCSG) {
+			t.Errorf("unexpected action: %s", got)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for the handler")
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultDDNRetryInterval is the default RetryInterval of DownlinkDataNotifier.
+const DefaultDDNRetryInterval = 10 * time.Second
+
+// DownlinkDataNotifier sends Downlink Data Notification to the MME for the sessions
+// on the S11 Conn, when the downlink data arrives for the UE in ECM-IDLE.
+//
+// HandleDataWaiting is meant to be set to the UPlaneConn in v1 with
+// SetDataWaitingHandler, which is called when the downlink data is buffered.
+//
+//	n := v2.NewDownlinkDataNotifier(s11Conn)
+//	s5uConn.SetDataWaitingHandler(n.HandleDataWaiting)
+//
+// Downlink Data Notification is sent once for a session until the UE becomes reachable,
+// which is when the session is activated again, or RetryInterval passes, which is run
+// as the timer named TimerDDNRetry on the session to be kept in the snapshot. When the MME
+// requests the throttling with DL low priority traffic Throttling IE in Downlink Data
+// Notification Acknowledge, the notifications for the low priority bearers are dropped
+// at the rate requested, during the delay requested.
+type DownlinkDataNotifier struct {
+	conn *Conn
+
+	mu         sync.Mutex
+	pending    map[string]*Session
+	throttling map[string]*ddnThrottling
+
+	// LookupSession returns the session and the bearer for the TEID of the downlink
+	// data buffered. If nil, the bearer on the Conn whose incoming TEID is the one is
+	// used, or the default bearer of the session with the TEID.
+	//
+	// This should be set when the TEID is not in the sessions on the S11 Conn, e.g.,
+	// the TEID of S5/S8-U kept in the sessions on another Conn.
+	LookupSession func(teid uint32) (*Session, *Bearer, error)
+
+	// LowPriorityLevel is the ARP priority level from which the bearers are taken as
+	// low priority and throttled. If 0, all the bearers are throttled.
+	LowPriorityLevel uint8
+
+	// RetryInterval is how long to wait for the UE to become reachable before sending
+	// Downlink Data Notification again for the session.
+	RetryInterval time.Duration
+}
+
+type ddnThrottling struct {
+	factor uint8
+	until  time.Time
+}
+
+// NewDownlinkDataNotifier creates a new DownlinkDataNotifier sending the notifications
+// on c, and registers the handler for Downlink Data Notification Acknowledge to c.
+func NewDownlinkDataNotifier(c *Conn) *DownlinkDataNotifier {
+	n := &DownlinkDataNotifier{
+		conn:          c,
+		pending:       map[string]*Session{},
+		throttling:    map[string]*ddnThrottling{},
+		RetryInterval: DefaultDDNRetryInterval,
+	}
+	c.AddHandler(messages.MsgTypeDownlinkDataNotificationAcknowledge, n.handleAcknowledge)
+	c.SetTimerHandler(TimerDDNRetry, n.expire)
+	return n
+}
+
+// HandleDataWaiting sends Downlink Data Notification for the session of the TEID of
+// the downlink data buffered, in the background not to block the U-Plane.
+//
+// The errors are sent to the errCh of the Conn, except ErrDownlinkDataThrottled.
+func (n *DownlinkDataNotifier) HandleDataWaiting(teid uint32) {
+	go func() {
+		sess, br, err := n.lookup(teid)
+		if err == nil {
+			err = n.Notify(sess, br)
+		}
+		if err != nil && err != ErrDownlinkDataThrottled {
+			n.conn.errCh <- err
+		}
+	}()
+}
+
+func (n *DownlinkDataNotifier) lookup(teid uint32) (*Session, *Bearer, error) {
+	if n.LookupSession != nil {
+		return n.LookupSession(teid)
+	}
+
+	for _, sess := range n.conn.ListSessions() {
+		for _, br := range sess.Bearers() {
+			if br.IncomingTEID() == teid {
+				return sess, br, nil
+			}
+		}
+	}
+
+	sess, err := n.conn.GetSessionByTEID(teid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sess, sess.GetDefaultBearer(), nil
+}
+
+// Notify sends Downlink Data Notification for the bearer of the session to the MME.
+//
+// Nothing is sent if the session is active, or the notification has been sent already
+// and the UE has not become reachable. ErrDownlinkDataThrottled is returned if the
+// bearer is throttled, and then the data buffered should be discarded.
+func (n *DownlinkDataNotifier) Notify(sess *Session, br *Bearer) error {
+	if sess.IsActive() {
+		n.Clear(sess.IMSI)
+		return nil
+	}
+
+	mmeTEID, err := sess.GetTEID(IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	if _, ok := sess.TimerDeadline(TimerDDNRetry); ok {
+		n.mu.Unlock()
+		return nil
+	}
+	if n.throttled(sess.PeerAddr, br, time.Now()) {
+		n.mu.Unlock()
+		return ErrDownlinkDataThrottled
+	}
+	n.pending[sess.IMSI] = sess
+	sess.StartTimer(TimerDDNRetry, n.RetryInterval, n.expire)
+	sess.Sequence++
+	seq := sess.Sequence
+	n.mu.Unlock()
+
+	var pci, pvi uint8
+	if br.PCI {
+		pci = 1
+	}
+	if br.PVI {
+		pvi = 1
+	}
+	ddn := messages.NewDownlinkDataNotification(
+		mmeTEID, seq,
+		ies.NewEPSBearerID(br.EBI),
+		ies.NewAllocationRetensionPriority(pci, br.PL, pvi),
+	)
+	if err := n.conn.SendMessageTo(ddn, sess.PeerAddr); err != nil {
+		n.Clear(sess.IMSI)
+		return err
+	}
+	return nil
+}
+
+// Clear forgets the notification sent for the subscriber, so that the next downlink
+// data is notified again. This should be called when the UE becomes reachable, if
+// the session is not activated then.
+func (n *DownlinkDataNotifier) Clear(imsi string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sess, ok := n.pending[imsi]
+	if !ok {
+		// restored from the snapshot, if any.
+		var err error
+		if sess, err = n.conn.GetSessionByIMSI(imsi); err != nil {
+			return
+		}
+	}
+	sess.StopTimer(TimerDDNRetry)
+	delete(n.pending, imsi)
+}
+
+// expire is the TimerFunc of TimerDDNRetry.
+func (n *DownlinkDataNotifier) expire(sess *Session, _ string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.pending[sess.IMSI] == sess {
+		delete(n.pending, sess.IMSI)
+	}
+}
+
+// the caller must hold mu.
+func (n *DownlinkDataNotifier) throttled(mmeAddr net.Addr, br *Bearer, now time.Time) bool {
+	if mmeAddr == nil {
+		return false
+	}
+	t, ok := n.throttling[mmeAddr.String()]
+	if !ok {
+		return false
+	}
+	if now.After(t.until) {
+		delete(n.throttling, mmeAddr.String())
+		return false
+	}
+	if br.PL < n.LowPriorityLevel {
+		return false
+	}
+	return rand.IntN(100) < int(t.factor)
+}
+
+func (n *DownlinkDataNotifier) handleAcknowledge(c *Conn, mmeAddr net.Addr, msg messages.Message) error {
+	ack, ok := msg.(*messages.DownlinkDataNotificationAcknowledge)
+	if !ok {
+		return ErrUnexpectedType
+	}
+
+	if ie := ack.DLLowPriorityTrafficThrottling; ie != nil {
+		n.mu.Lock()
+		delay, factor := ie.ThrottlingDelay(), ie.ThrottlingFactor()
+		if delay == 0 || factor == 0 {
+			delete(n.throttling, mmeAddr.String())
+		} else {
+			n.throttling[mmeAddr.String()] = &ddnThrottling{
+				factor: factor, until: time.Now().Add(delay),
+			}
+		}
+		n.mu.Unlock()
+	}
+
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return err
+	}
+	if ie := ack.Cause; ie == nil || ie.Cause() != CauseRequestAccepted {
+		// the MME does not page the UE, and the next downlink data is notified again.
+		n.Clear(sess.IMSI)
+		if ie == nil {
+			return &ErrRequiredIEMissing{Type: ies.Cause}
+		}
+		return &ErrCauseNotOK{
+			MsgType: msg.MessageTypeName(),
+			Cause:   ie.Cause(),
+			Msg:     "subscriber: " + sess.IMSI,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestDownlinkDataNotifier(t *testing.T) {
+	sgwAddr, err := net.ResolveUDPAddr("udp", "127.0.0.9:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mmeAddr, err := net.ResolveUDPAddr("udp", "127.0.0.10:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 10)
+	sgwConn, err := v2.ListenAndServe(sgwAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sgwConn.Close()
+	mmeConn, err := v2.ListenAndServe(mmeAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mmeConn.Close()
+
+	// the MME acknowledges the notifications with the throttling once throttle is set.
+	var throttle atomic.Pointer[ies.IE]
+	ddnCh := make(chan *messages.DownlinkDataNotification, 10)
+	mmeConn.AddHandler(messages.MsgTypeDownlinkDataNotification, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		ddn := msg.(*messages.DownlinkDataNotification)
+		ddnCh <- ddn
+
+		ie := []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)}
+		if th := throttle.Load(); th != nil {
+			ie = append(ie, th)
+		}
+		return c.SendMessageTo(
+			messages.NewDownlinkDataNotificationAcknowledge(0x11111111, ddn.Sequence(), ie...), addr,
+		)
+	})
+
+	mmeSess := v2.NewSession(sgwAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	mmeSess.AddTEID(v2.IFTypeS11MMEGTPC, 0x22222222)
+	mmeConn.AddSession(mmeSess)
+
+	sess := v2.NewSession(mmeAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x22222222)
+	br := sess.GetDefaultBearer()
+	br.EBI = 5
+	br.QoSProfile = &v2.QoSProfile{PL: 10}
+	br.SetIncomingTEID(0x33333333)
+	sgwConn.AddSession(sess)
+
+	n := v2.NewDownlinkDataNotifier(sgwConn)
+	n.HandleDataWaiting(0x33333333)
+
+	select {
+	case ddn := <-ddnCh:
+		if ddn.TEID() != 0x22222222 {
+			t.Errorf("wrong TEID: %#x", ddn.TEID())
+		}
+		if ddn.EPSBearerID == nil || ddn.EPSBearerID.EPSBearerID() != 5 {
+			t.Errorf("wrong EPS Bearer ID: %v", ddn.EPSBearerID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Downlink Data Notification not received")
+	case err := <-errCh:
+		t.Fatal(err)
+	}
+
+	t.Run("pending", func(t *testing.T) {
+		if err := n.Notify(sess, br); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-ddnCh:
+			t.Error("Downlink Data Notification sent twice")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("throttling", func(t *testing.T) {
+		throttle.Store(ies.NewThrottling(time.Hour, 100))
+		n.Clear(sess.IMSI)
+		if err := n.Notify(sess, br); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-ddnCh:
+		case <-time.After(time.Second):
+			t.Fatal("Downlink Data Notification not received after Clear")
+		}
+
+		// wait for the acknowledgement to be handled.
+		deadline := time.Now().Add(time.Second)
+		for {
+			n.Clear(sess.IMSI)
+			err := n.Notify(sess, br)
+			if err == v2.ErrDownlinkDataThrottled {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("notification not throttled")
+			}
+			<-ddnCh
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// high priority bearers are not throttled.
+		n.LowPriorityLevel = 11
+		n.Clear(sess.IMSI)
+		if err := n.Notify(sess, br); err != nil {
+			t.Errorf("high priority bearer throttled: %v", err)
+		}
+	})
+}
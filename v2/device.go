@@ -0,0 +1,19 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+// BindToDevice binds Conn to the network device or VRF specified by name
+// (SO_BINDTODEVICE), so that the interfaces like S11 and S5 can be served in the
+// separate routing domains on the same host. An empty name removes the binding.
+//
+// This is available only on Linux and requires CAP_NET_RAW in general; on other
+// platforms ErrNotSupported is returned for any name other than empty.
+//
+// Note that the Echo exchanged in Dial is sent before the binding. Use
+// ListenAndServe and then BindToDevice to send all the messages through the
+// device.
+func (c *Conn) BindToDevice(name string) error {
+	return bindToDevice(c.pktConn, name)
+}
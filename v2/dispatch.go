@@ -0,0 +1,157 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"hash/fnv"
+	"net"
+	"runtime"
+	"strconv"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DispatchPolicy is how the messages received on Conn are dispatched to the HandlerFuncs.
+type DispatchPolicy uint8
+
+// DispatchPolicy definitions.
+const (
+	// DispatchConcurrent handles each message in its own goroutine, without any order
+	// among the messages. This is the default.
+	DispatchConcurrent DispatchPolicy = iota
+	// DispatchPerSession handles the messages of the same session one by one in the
+	// order of arrival, and the ones of the different sessions in parallel on the pool
+	// of the workers, to preserve the order of the procedures within a session, e.g.,
+	// Modify Bearer Request following Create Session Request.
+	//
+	// The HandlerFuncs are never called concurrently for the same session, for the
+	// requests and responses including the network-triggered ones, e.g., Create Bearer
+	// Request, so that they can access the Session without their own locking. Only the
+	// responses received by Exchange and the path management messages bypass this, as
+	// they are not passed to the HandlerFuncs for the session.
+	//
+	// The session of the message is identified by the Session on the Conn with the TEID
+	// in the header, or the IMSI in Create Session Request with TEID 0, so the messages
+	// with any TEID of the Session are ordered together with Create Session Request
+	// once the Session is added with the IMSI. The ones with the TEID not known are
+	// ordered per TEID, and the ones without any of them are ordered per peer. As the
+	// peer learns the TEID from Create Session Response, the messages with the TEID
+	// never overtake Create Session Request of the session, nor are they handled while
+	// the HandlerFunc of it is still running after responding, if the Session is added
+	// before responding.
+	DispatchPerSession
+)
+
+// DispatchQueueSize is the number of the messages queued for each worker with
+// DispatchPerSession. The messages arriving while the queue is full are discarded
+// with ErrDispatchQueueFull sent to the error channel, as the peer retransmits them.
+const DispatchQueueSize = 1024
+
+// SetDispatchPolicy sets the DispatchPolicy of the Conn, with the number of the workers
+// for DispatchPerSession. If workers is 0 or less, runtime.GOMAXPROCS(0) is used.
+//
+// This should be called just after creating the Conn, as the messages queued for the
+// workers of the previous DispatchPolicy may be discarded. The path management messages
+// are handled ahead of the others regardless of the DispatchPolicy.
+func (c *Conn) SetDispatchPolicy(policy DispatchPolicy, workers int) {
+	if policy != DispatchPerSession {
+		if old := c.dispatcher.Swap(nil); old != nil {
+			old.stop()
+		}
+		return
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	d := newDispatcher(c, workers)
+	if old := c.dispatcher.Swap(d); old != nil {
+		old.stop()
+	}
+}
+
+type dispatchItem struct {
+	sender net.Addr
+	msg    messages.Message
+}
+
+// dispatcher serializes the messages by session on the workers.
+type dispatcher struct {
+	conn   *Conn
+	queues []chan dispatchItem
+	stopCh chan struct{}
+}
+
+func newDispatcher(c *Conn, workers int) *dispatcher {
+	d := &dispatcher{
+		conn:   c,
+		queues: make([]chan dispatchItem, workers),
+		stopCh: make(chan struct{}),
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan dispatchItem, DispatchQueueSize)
+		go d.work(d.queues[i])
+	}
+	return d
+}
+
+func (d *dispatcher) stop() {
+	close(d.stopCh)
+}
+
+// dispatch queues the message to the worker of the session.
+func (d *dispatcher) dispatch(sender net.Addr, msg messages.Message) error {
+	h := fnv.New32a()
+	h.Write([]byte(d.conn.sessionKey(sender, msg)))
+
+	select {
+	case d.queues[h.Sum32()%uint32(len(d.queues))] <- dispatchItem{sender, msg}:
+		return nil
+	default:
+		return ErrDispatchQueueFull
+	}
+}
+
+func (d *dispatcher) work(queue <-chan dispatchItem) {
+	for {
+		select {
+		case <-d.conn.closed():
+			return
+		case <-d.stopCh:
+			return
+		case item := <-queue:
+			d.handle(item)
+		}
+	}
+}
+
+func (d *dispatcher) handle(item dispatchItem) {
+	c := d.conn
+	handle, err := c.prepareHandler(item.sender, item.msg)
+	if err == nil {
+		err = handle(c, item.sender, item.msg)
+	}
+	if err != nil {
+		// not to block the worker while no one receives the errors.
+		go func() {
+			c.errCh <- err
+		}()
+	}
+}
+
+// sessionKey returns the key to identify the session of the message. The messages of
+// the Session on c have the same key, whichever TEID of it they have.
+func (c *Conn) sessionKey(sender net.Addr, msg messages.Message) string {
+	if teid := msg.TEID(); teid != 0 {
+		if sess, err := c.GetSessionByTEID(teid); err == nil && sess.IMSI != "" {
+			return "imsi:" + sess.IMSI
+		}
+		return "teid:" + strconv.FormatUint(uint64(teid), 16)
+	}
+	if csReq, ok := msg.(*messages.CreateSessionRequest); ok && csReq.IMSI != nil {
+		return "imsi:" + csReq.IMSI.IMSI()
+	}
+	return "peer:" + sender.String()
+}
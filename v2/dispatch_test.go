@@ -0,0 +1,232 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestDispatchPerSession(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.32:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetDispatchPolicy(v2.DispatchPerSession, 4)
+
+	peer, err := net.ListenPacket("udp", "127.0.0.33:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	var teids []uint32
+	for _, imsi := range []string{"123451234567891", "123451234567892", "123451234567893"} {
+		sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: imsi})
+		teid := srvConn.NewTEID()
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
+		srvConn.AddSession(sess)
+		teids = append(teids, teid)
+	}
+
+	const perSession = 30
+	var (
+		mu     sync.Mutex
+		seqs   = map[uint32][]uint32{}
+		doneCh = make(chan struct{})
+	)
+	srvConn.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		// the earlier messages take longer, to be overtaken if handled concurrently.
+		time.Sleep(time.Duration(perSession-msg.Sequence()%perSession) * 100 * time.Microsecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		seqs[msg.TEID()] = append(seqs[msg.TEID()], msg.Sequence())
+		var n int
+		for _, s := range seqs {
+			n += len(s)
+		}
+		if n == perSession*len(teids) {
+			close(doneCh)
+		}
+		return nil
+	})
+
+	for i := range uint32(perSession) {
+		for j, teid := range teids {
+			b, err := messages.NewModifyBearerRequest(teid, uint32(j)*perSession+i).Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := peer.WriteTo(b, srvAddr); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("messages not handled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for teid, s := range seqs {
+		if !slices.IsSorted(s) {
+			t.Errorf("messages with TEID %#x handled out of order: %v", teid, s)
+		}
+	}
+}
+
+func TestDispatchPerSessionAcrossTEIDs(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.50:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetDispatchPolicy(v2.DispatchPerSession, 8)
+
+	peer, err := net.ListenPacket("udp", "127.0.0.51:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	var csHandling atomic.Bool
+	overlapCh := make(chan bool, 1)
+	srvConn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionRequest: func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+			csHandling.Store(true)
+			defer csHandling.Store(false)
+
+			csReq := msg.(*messages.CreateSessionRequest)
+			sess := v2.NewSession(addr, &v2.Subscriber{IMSI: csReq.IMSI.IMSI()})
+			fteid := c.NewFTEID(v2.IFTypeS11S4SGWGTPC, "127.0.0.50", "")
+			sess.AddTEID(v2.IFTypeS11S4SGWGTPC, fteid.TEID())
+			c.AddSession(sess)
+			if err := c.RespondTo(addr, msg, messages.NewCreateSessionResponse(
+				0x11111111, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), fteid,
+			)); err != nil {
+				return err
+			}
+
+			// still handling Create Session Request after responding.
+			time.Sleep(200 * time.Millisecond)
+			return nil
+		},
+		messages.MsgTypeModifyBearerRequest: func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+			overlapCh <- csHandling.Load()
+			return nil
+		},
+	})
+
+	b, err := messages.NewCreateSessionRequest(0, 1, ies.NewIMSI("123451234567890")).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csRsp, err := messages.DecodeCreateSessionResponse(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = messages.NewModifyBearerRequest(csRsp.SenderFTEIDC.TEID(), 2).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case overlapped := <-overlapCh:
+		if overlapped {
+			t.Error("Modify Bearer Request handled while handling Create Session Request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Modify Bearer Request not handled")
+	}
+}
+
+func TestDispatchPerSessionHandlerError(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.60:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// no one receives the errors.
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetDispatchPolicy(v2.DispatchPerSession, 1)
+
+	peer, err := net.ListenPacket("udp", "127.0.0.61:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	teid := srvConn.NewTEID()
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
+	srvConn.AddSession(sess)
+
+	handledCh := make(chan uint32, 2)
+	srvConn.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		handledCh <- msg.Sequence()
+		return v2.ErrUnknownIMSI
+	})
+
+	for seq := range uint32(2) {
+		b, err := messages.NewModifyBearerRequest(teid, seq+1).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for want := range uint32(2) {
+		select {
+		case got := <-handledCh:
+			if got != want+1 {
+				t.Errorf("got %d, want %d", got, want+1)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message %d not handled after the handler returned an error", want+1)
+		}
+	}
+}
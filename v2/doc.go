@@ -68,10 +68,10 @@
 //   			return &v2.ErrRequiredIEMissing{Type: ies.FullyQualifiedTEID}
 //   		}
 //
-//   		// IEs inside grouped IE can be handled by ranging over ie.ChildIEs.
+//   		// IEs inside grouped IE can be handled by ranging over ie.BearerContext().
 //   		// also, grouped IE has FindByType(), but it might be slower.
 //   		if brCtxIE := csRsp.BearerContextsCreated; brCtxIE != nil {
-//   			for _, ie := range brCtxIE.ChildIEs {
+//   			for _, ie := range brCtxIE.BearerContext() {
 //   				switch ie.Type {
 //   				case ies.EPSBearerID:
 //   					bearer.EBI = ie.EPSBearerID()
@@ -36,8 +36,20 @@ var (
 	// ErrUnknownAPN indicates that the APN is different from expected one.
 	ErrUnknownAPN = errors.New("got unknown APN")
 
-	// ErrTimeout indicates that a handler failed to complete its work due to the
-	// absence of messages expected to come from another endpoint.
+	// ErrAPNAccessDenied indicates that the Create Session Request is rejected as the
+	// APNPolicy does not allow the subscriber to access the APN.
+	ErrAPNAccessDenied = errors.New("access to APN denied by policy")
+
+	// ErrAPNCongested indicates that the Create Session Request is rejected as the
+	// APNPolicy has the APN congested.
+	ErrAPNCongested = errors.New("APN congested")
+
+	// ErrAPNRestrictionIncompatible indicates that the Create Session Request is rejected
+	// as the APN Restriction of the APN is incompatible with the Maximum APN Restriction.
+	ErrAPNRestrictionIncompatible = errors.New("APN restriction incompatible with active PDN connections")
+
+	// ErrTimeout indicates that a handler or Exchange failed to complete its work due
+	// to the absence of messages expected to come from another endpoint.
 	ErrTimeout = errors.New("timed out")
 
 	// ErrNoBearerFound indicates that no Bearer found by lookup methods.
@@ -47,9 +59,45 @@ var (
 	// a message.
 	ErrNoRemoteAddressFound = errors.New("no remote address found")
 
+	// ErrZeroTEID indicates that the session-level request is discarded as it has TEID 0.
+	// See EnableZeroTEIDValidation.
+	ErrZeroTEID = errors.New("got session-level request with TEID 0")
+
+	// ErrUnexpectedPeer indicates that the message with TEID comes from the address other
+	// than the peer of the Session with the TEID. See EnablePeerValidation.
+	ErrUnexpectedPeer = errors.New("got message from unexpected peer for the TEID")
+
+	// ErrNotSupported indicates that the operation is not supported on the platform
+	// or by the net.PacketConn given to Conn.
+	ErrNotSupported = errors.New("not supported on this platform")
+
 	// ErrDuplicateTEID indicates that the TEID added to a Session already exists.
 	// Users should re-generate TEID and add it again.
 	ErrDuplicateTEID = errors.New("same TEID cannot exist simultaneously in a Session. Re-generate or request another one")
+
+	// ErrUnsupportedSnapshot indicates that the version of the session snapshot given
+	// to RestoreSessions is not supported.
+	ErrUnsupportedSnapshot = errors.New("unsupported version of session snapshot")
+
+	// ErrDownlinkDataThrottled indicates that Downlink Data Notification is not sent
+	// as the bearer is throttled by the MME with DL low priority traffic Throttling.
+	ErrDownlinkDataThrottled = errors.New("downlink data notification throttled")
+
+	// ErrTooManyTransactions indicates that the request is not sent as the number of the
+	// transactions outstanding with the peer exceeds MaxPerPeer of TransactionLimits.
+	ErrTooManyTransactions = errors.New("too many outstanding transactions with the peer")
+
+	// ErrSessionRemoved indicates that the Session is removed from the Conn while waiting
+	// for the message with WaitMessage or passing it with PassMessageTo.
+	ErrSessionRemoved = errors.New("session removed while waiting for message")
+
+	// ErrDispatchQueueFull indicates that the message received is discarded as the queue
+	// of the worker is full with DispatchPerSession.
+	ErrDispatchQueueFull = errors.New("dispatch queue is full, discarding message")
+
+	// ErrDuplicateSequence indicates that the request is not sent by Exchange as the one
+	// with the same sequence number is already waiting for the response from the peer.
+	ErrDuplicateSequence = errors.New("request with the same sequence number is outstanding")
 )
 
 // ErrCauseNotOK indicates that the value in Cause IE is not OK.
@@ -61,7 +109,7 @@ type ErrCauseNotOK struct {
 
 // Error returns error cause with message.
 func (e *ErrCauseNotOK) Error() string {
-	return fmt.Sprintf("got non-OK Cause: %d in %s; %s", e.Cause, e.MsgType, e.Msg)
+	return fmt.Sprintf("got non-OK Cause: %s in %s; %s", Cause(e.Cause), e.MsgType, e.Msg)
 }
 
 // ErrRequiredIEMissing indicates that the IE required is missing.
@@ -74,6 +122,18 @@ func (e *ErrRequiredIEMissing) Error() string {
 	return fmt.Sprintf("required IE missing: %d", e.Type)
 }
 
+// ErrUnexpectedInterfaceType indicates that the F-TEID of the interface type is not
+// expected in the Bearer Context.
+type ErrUnexpectedInterfaceType struct {
+	IFType        uint8
+	BearerContext BearerContextType
+}
+
+// Error returns error with the interface type and the Bearer Context.
+func (e *ErrUnexpectedInterfaceType) Error() string {
+	return fmt.Sprintf("unexpected F-TEID of %s in %s", IFType(e.IFType), e.BearerContext)
+}
+
 // ErrRequiredParameterMissing indicates that no Bearer found by lookup methods.
 type ErrRequiredParameterMissing struct {
 	Name, Msg string
@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Exchange sends the request req to raddr on c and waits for the response with the same
+// sequence number from raddr, which is returned as T, e.g.,
+//
+//	csRsp, err := v2.Exchange[*messages.CreateSessionResponse](ctx, conn, raddr, csReq)
+//
+// The response is not passed to the HandlerFunc registered on c. The errors returned are:
+//
+//   - ctx.Err() if ctx is done before the response comes, or net.ErrClosed if c is closed.
+//   - ErrTimeout if no response comes after all the retransmissions.
+//   - ErrDuplicateSequence if the request with the same sequence number is outstanding.
+//   - ErrUnexpectedType if the response is not T.
+//   - *ErrRequiredIEMissing if the response does not have the Cause IE.
+//   - *ErrCauseNotOK if the Cause is not the one in the acceptance range.
+//
+// The response is returned together with the errors about the Cause, so that the caller
+// can look into it, e.g., the Offending IE.
+//
+// The sequence number of req should be unique among the outstanding requests to raddr.
+// The request is retransmitted by Exchange as the ResponseProfile of its message type
+// in TransactionLimits, if set. Otherwise it is not retransmitted, and Exchange can be
+// called again with req not modified to retransmit it. The Message Priority given with
+// WithMessagePriority is set in the header of req.
+func Exchange[T messages.Message](ctx context.Context, c *Conn, raddr net.Addr, req messages.Message) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	key := txKey{raddr.String(), req.Sequence()}
+	rspCh := make(chan messages.Message, 1)
+	if _, loaded := c.exchanges.LoadOrStore(key, rspCh); loaded {
+		return zero, ErrDuplicateSequence
+	}
+	defer c.exchanges.Delete(key)
+
+	setMessagePriority(ctx, req)
+	if err := c.SendMessageTo(req, raddr); err != nil {
+		return zero, err
+	}
+
+	msg, err := c.waitResponse(ctx, raddr, req, rspCh)
+	if err != nil {
+		return zero, err
+	}
+
+	rsp, ok := msg.(T)
+	if !ok {
+		return zero, ErrUnexpectedType
+	}
+	return rsp, causeError(raddr, rsp)
+}
+
+// waitResponse waits for the response to req on rspCh, retransmitting req as the
+// ResponseProfile of its message type.
+func (c *Conn) waitResponse(ctx context.Context, raddr net.Addr, req messages.Message, rspCh <-chan messages.Message) (messages.Message, error) {
+	p, retransmits := c.ResponseProfile(req.MessageType())
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	if retransmits {
+		timer = time.NewTimer(p.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for retries := 0; ; retries++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed():
+			return nil, net.ErrClosed
+		case msg := <-rspCh:
+			return msg, nil
+		case <-timeout:
+		}
+
+		if retries >= p.Retries {
+			return nil, ErrTimeout
+		}
+		if err := c.SendMessageTo(req, raddr); err != nil {
+			return nil, err
+		}
+		timer.Reset(p.Timeout)
+	}
+}
+
+// causeError returns the error if the Cause in msg is not the one in the acceptance range
+// defined in TS 29.274 8.4, or nil if msg cannot have the Cause.
+func causeError(raddr net.Addr, msg messages.Message) error {
+	cause, err := messages.Cause(msg)
+	switch {
+	case errors.Is(err, messages.ErrIENotExpected):
+		return nil
+	case errors.Is(err, messages.ErrIEMissing):
+		return &ErrRequiredIEMissing{Type: ies.Cause}
+	case err != nil:
+		return err
+	}
+
+	if !IsAcceptedCause(cause) {
+		return &ErrCauseNotOK{
+			MsgType: msg.MessageTypeName(),
+			Cause:   cause,
+			Msg:     "peer: " + raddr.String(),
+		}
+	}
+	return nil
+}
+
+// deliverExchange passes msg to Exchange waiting for it, and reports whether it is passed.
+func (c *Conn) deliverExchange(raddr net.Addr, msg messages.Message) bool {
+	if messages.ExpectsResponse(msg.MessageType()) {
+		return false
+	}
+
+	v, ok := c.exchanges.LoadAndDelete(txKey{raddr.String(), msg.Sequence()})
+	if !ok {
+		return false
+	}
+	v.(chan messages.Message) <- msg
+	return true
+}
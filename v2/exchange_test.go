@@ -0,0 +1,242 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestExchange(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.42:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.43:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	srvConn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionRequest: func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			csReq := msg.(*messages.CreateSessionRequest)
+			cause := v2.CauseRequestAccepted
+			switch {
+			case csReq.IMSI.IMSI() != "123451234567890":
+				cause = v2.CauseUserAuthenticationFailed
+			case csReq.HasMessagePriority() && csReq.MessagePriority() > 8:
+				cause = v2.CauseAPNCongestion
+			}
+			return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(0, 0, ies.NewCause(cause, 0, 0, 0, nil)))
+		},
+		messages.MsgTypeDeleteSessionRequest: func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)))
+		},
+		messages.MsgTypeModifyBearerRequest: func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return nil
+		},
+	})
+
+	t.Run("Accepted", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		csRsp, err := v2.Exchange[*messages.CreateSessionResponse](
+			ctx, cliConn, srvAddr, messages.NewCreateSessionRequest(0, 1, ies.NewIMSI("123451234567890")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := csRsp.Sequence(); got != 1 {
+			t.Errorf("got sequence %d, want 1", got)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		csRsp, err := v2.Exchange[*messages.CreateSessionResponse](
+			ctx, cliConn, srvAddr, messages.NewCreateSessionRequest(0, 2, ies.NewIMSI("123451234567891")),
+		)
+		var causeErr *v2.ErrCauseNotOK
+		if !errors.As(err, &causeErr) {
+			t.Fatalf("got %v, want ErrCauseNotOK", err)
+		}
+		if causeErr.Cause != v2.CauseUserAuthenticationFailed {
+			t.Errorf("got %s, want %s", v2.Cause(causeErr.Cause), v2.Cause(v2.CauseUserAuthenticationFailed))
+		}
+		if csRsp == nil {
+			t.Error("response not returned with the error")
+		}
+	})
+
+	t.Run("MessagePriority", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := v2.Exchange[*messages.CreateSessionResponse](
+			v2.WithMessagePriority(ctx, 12), cliConn, srvAddr,
+			messages.NewCreateSessionRequest(0, 6, ies.NewIMSI("123451234567890")),
+		)
+		var causeErr *v2.ErrCauseNotOK
+		if !errors.As(err, &causeErr) || causeErr.Cause != v2.CauseAPNCongestion {
+			t.Errorf("got %v, want ErrCauseNotOK with %s", err, v2.Cause(v2.CauseAPNCongestion))
+		}
+	})
+
+	t.Run("UnexpectedType", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := v2.Exchange[*messages.DeleteSessionResponse](
+			ctx, cliConn, srvAddr, messages.NewDeleteSessionRequest(0, 3, ies.NewEPSBearerID(5)),
+		)
+		if !errors.Is(err, v2.ErrUnexpectedType) {
+			t.Errorf("got %v, want %v", err, v2.ErrUnexpectedType)
+		}
+	})
+
+	t.Run("Echo", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		if _, err := v2.Exchange[*messages.EchoResponse](
+			ctx, cliConn, srvAddr, messages.NewEchoRequest(4, ies.NewRecovery(0)),
+		); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := v2.Exchange[*messages.ModifyBearerResponse](
+			ctx, cliConn, srvAddr, messages.NewModifyBearerRequest(0, 5, ies.NewEPSBearerID(5)),
+		)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestExchangeRetransmission(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.56:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	cliConn.SetTransactionLimits(v2.TransactionLimits{
+		Profiles: map[uint8]v2.ResponseProfile{
+			messages.MsgTypeDeleteSessionRequest: {Timeout: 50 * time.Millisecond, Retries: 2},
+		},
+	})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.57:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	// receive counts the requests until the one answered, or until timed out.
+	receive := func(answerAt int) <-chan int {
+		countCh := make(chan int, 1)
+		go func() {
+			buf := make([]byte, 1500)
+			count := 0
+			for {
+				_ = peer.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+				n, raddr, err := peer.ReadFrom(buf)
+				if err != nil {
+					countCh <- count
+					return
+				}
+				count++
+				if count != answerAt {
+					continue
+				}
+
+				req, err := messages.Decode(buf[:n])
+				if err != nil {
+					continue
+				}
+				b, err := messages.NewDeleteSessionResponse(
+					0, req.Sequence(), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				).Serialize()
+				if err != nil {
+					continue
+				}
+				_, _ = peer.WriteTo(b, raddr)
+			}
+		}()
+		return countCh
+	}
+
+	t.Run("answered", func(t *testing.T) {
+		countCh := receive(2)
+		if _, err := v2.Exchange[*messages.DeleteSessionResponse](
+			context.Background(), cliConn, peer.LocalAddr(), messages.NewDeleteSessionRequest(0, 1),
+		); err != nil {
+			t.Fatal(err)
+		}
+		if got := <-countCh; got != 2 {
+			t.Errorf("got %d requests, want 2", got)
+		}
+	})
+
+	t.Run("timed-out", func(t *testing.T) {
+		countCh := receive(0)
+		start := time.Now()
+		if _, err := v2.Exchange[*messages.DeleteSessionResponse](
+			context.Background(), cliConn, peer.LocalAddr(), messages.NewDeleteSessionRequest(0, 2),
+		); !errors.Is(err, v2.ErrTimeout) {
+			t.Fatalf("got %v, want %v", err, v2.ErrTimeout)
+		}
+		if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+			t.Errorf("timed out too early: %s", elapsed)
+		}
+		if got := <-countCh; got != 3 {
+			t.Errorf("got %d requests, want 3", got)
+		}
+	})
+
+	// the ones without the profile are not retransmitted.
+	t.Run("no-profile", func(t *testing.T) {
+		countCh := receive(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		if _, err := v2.Exchange[*messages.ModifyBearerResponse](
+			ctx, cliConn, peer.LocalAddr(), messages.NewModifyBearerRequest(0, 3),
+		); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+		if got := <-countCh; got != 1 {
+			t.Errorf("got %d requests, want 1", got)
+		}
+	})
+}
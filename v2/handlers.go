@@ -41,13 +41,18 @@ func newMsgHandlerMap(m map[uint8]HandlerFunc) *msgHandlerMap {
 	return mhm
 }
 
-var defaultHandlerMap = newMsgHandlerMap(
-	map[uint8]HandlerFunc{
-		messages.MsgTypeEchoRequest:                   handleEchoRequest,
-		messages.MsgTypeEchoResponse:                  handleEchoResponse,
-		messages.MsgTypeVersionNotSupportedIndication: handleVersionNotSupportedIndication,
-	},
-)
+// newDefaultHandlerMap returns the default handlers for Conn.
+// This is created for each Conn so that the handlers added to one Conn
+// do not affect the others.
+func newDefaultHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:                   handleEchoRequest,
+			messages.MsgTypeEchoResponse:                  handleEchoResponse,
+			messages.MsgTypeVersionNotSupportedIndication: handleVersionNotSupportedIndication,
+		},
+	)
+}
 
 func handleEchoRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error {
 	// this should never happen, as the type should have been assured by
@@ -0,0 +1,38 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// EnableCanonicalIEOrder turns on the serialization of the messages sent by Conn with
+// the IEs in the canonical order of TS 29.274, as done by messages.SerializeCanonical,
+// for the peers rejecting the messages with the IEs out of order, e.g., the ones with
+// AdditionalIEs which are otherwise placed at the end.
+func (c *Conn) EnableCanonicalIEOrder() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.canonicalIEOrderEnabled = true
+}
+
+// DisableCanonicalIEOrder turns off the serialization in the canonical order. This is
+// the default, and the messages are serialized as they are.
+func (c *Conn) DisableCanonicalIEOrder() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.canonicalIEOrderEnabled = false
+}
+
+// serialize serializes msg into b, in the canonical order if enabled.
+func (c *Conn) serialize(msg messages.Message, b []byte) error {
+	c.mu.Lock()
+	canonical := c.canonicalIEOrderEnabled
+	c.mu.Unlock()
+	if canonical {
+		return messages.SerializeCanonicalTo(msg, b)
+	}
+	return msg.SerializeTo(b)
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestCanonicalIEOrder(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.40:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	peer, err := net.ListenPacket("udp", "127.0.0.41:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	send := func(seq uint32) []byte {
+		t.Helper()
+		csReq := messages.NewCreateSessionRequest(
+			0, seq,
+			ies.NewPrivateExtension(10415, []byte{0x01}),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.40", ""),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.40", "").WithInstance(5),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewIMSI("123451234567890"),
+		)
+		if err := cliConn.SendMessageTo(csReq, peer.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1500)
+		n, _, err := peer.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf[:n]
+	}
+
+	if err := messages.VerifyIEOrder(send(1)); !errors.Is(err, messages.ErrIEOutOfOrder) {
+		t.Errorf("got %v, want %v", err, messages.ErrIEOutOfOrder)
+	}
+
+	cliConn.EnableCanonicalIEOrder()
+	if err := messages.VerifyIEOrder(send(2)); err != nil {
+		t.Error(err)
+	}
+}
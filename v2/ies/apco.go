@@ -0,0 +1,34 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewAdditionalProtocolConfigurationOptions creates a new AdditionalProtocolConfigurationOptions IE.
+//
+// APCO is used on S2a/S2b in place of PCO, which has no way to reach the UE over the
+// non-3GPP access. The payload is encoded in the same way as PCO.
+func NewAdditionalProtocolConfigurationOptions(configProto uint8, options ...*ConfigurationProtocolOption) *IE {
+	apco := NewPCOPayload(configProto, options...)
+
+	i := New(AdditionalProtocolConfigurationOptions, 0x00, make([]byte, apco.Len()))
+	if err := apco.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// AdditionalProtocolConfigurationOptions returns AdditionalProtocolConfigurationOptions
+// in PCOPayload type if the type of IE matches.
+func (i *IE) AdditionalProtocolConfigurationOptions() *PCOPayload {
+	if i.Type != AdditionalProtocolConfigurationOptions {
+		return nil
+	}
+
+	apco, err := DecodePCOPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return apco
+}
@@ -23,14 +23,26 @@ func NewAccessPointName(apn string) *IE {
 }
 
 // AccessPointName returns AccessPointName in string if the type of IE matches.
+//
+// The labels are decoded as far as possible even if the payload is malformed,
+// use ParseAccessPointName to detect it.
 func (i *IE) AccessPointName() string {
+	apn, _ := i.ParseAccessPointName()
+	return apn
+}
+
+// ParseAccessPointName returns AccessPointName in string, or the error if the type
+// of IE does not match or the length of a label exceeds the payload. The labels
+// before the malformed one are returned together with the error.
+func (i *IE) ParseAccessPointName() (string, error) {
 	if i.Type != AccessPointName {
-		return ""
+		return "", ErrInvalidType
 	}
 
 	var (
 		apn    []string
 		offset int
+		err    error
 	)
 	max := len(i.Payload)
 	for {
@@ -38,9 +50,13 @@ func (i *IE) AccessPointName() string {
 			break
 		}
 		l := int(i.Payload[offset])
+		if offset+l+1 > max {
+			err = ErrMalformed
+			break
+		}
 		apn = append(apn, string(i.Payload[offset+1:offset+l+1]))
 		offset += l + 1
 	}
 
-	return strings.Join(apn, ".")
+	return strings.Join(apn, "."), err
 }
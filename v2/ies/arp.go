@@ -15,7 +15,10 @@ func NewAllocationRetensionPriority(pci, pl, pvi uint8) *IE {
 func (i *IE) PreemptionCapability() bool {
 	switch i.Type {
 	case AllocationRetensionPriority, BearerQoS:
-		return (i.Payload[0] & 0x40) == 1
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return (i.Payload[0] & 0x40) != 0
 	default:
 		return false
 	}
@@ -25,7 +28,10 @@ func (i *IE) PreemptionCapability() bool {
 func (i *IE) PriorityLevel() uint8 {
 	switch i.Type {
 	case AllocationRetensionPriority, BearerQoS:
-		return i.Payload[0] & 0x3c
+		if len(i.Payload) < 1 {
+			return 0
+		}
+		return (i.Payload[0] & 0x3c) >> 2
 	default:
 		return 0
 	}
@@ -35,6 +41,9 @@ func (i *IE) PriorityLevel() uint8 {
 func (i *IE) PreemptionVulnerability() bool {
 	switch i.Type {
 	case AllocationRetensionPriority, BearerQoS:
+		if len(i.Payload) < 1 {
+			return false
+		}
 		return (i.Payload[0] & 0x01) == 1
 	default:
 		return false
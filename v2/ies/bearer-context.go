@@ -100,11 +100,17 @@ func NewBearerContextWithinContextAcknowledge(ebi, fwdFTEID *IE) *IE {
 }
 
 // BearerContext returns the []*IE inside BearerContext IE.
+//
+// This returns nil if the type of IE does not match or the child IEs are malformed.
+// Use Children to get the error.
 func (i *IE) BearerContext() []*IE {
-	ies, err := DecodeMultiIEs(i.Payload)
-	if err != nil {
+	if i.Type != BearerContext {
 		return nil
 	}
 
+	ies, err := i.Children()
+	if err != nil {
+		return nil
+	}
 	return ies
 }
@@ -16,6 +16,9 @@ func (i *IE) BearerFlags() uint8 {
 	if i.Type != BearerFlags {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 
 	return i.Payload[0]
 }
@@ -25,7 +28,10 @@ func (i *IE) BearerFlags() uint8 {
 func (i *IE) ActivityStatusIndicator() bool {
 	switch i.Type {
 	case BearerFlags:
-		return i.Payload[0]&0x08 == 1
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return i.Payload[0]&0x08 != 0
 	default:
 		return false
 	}
@@ -36,7 +42,10 @@ func (i *IE) ActivityStatusIndicator() bool {
 func (i *IE) VSRVCC() bool {
 	switch i.Type {
 	case BearerFlags:
-		return i.Payload[0]&0x04 == 1
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return i.Payload[0]&0x04 != 0
 	default:
 		return false
 	}
@@ -46,7 +55,10 @@ func (i *IE) VSRVCC() bool {
 func (i *IE) VoiceBearer() bool {
 	switch i.Type {
 	case BearerFlags:
-		return i.Payload[0]&0x02 == 1
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return i.Payload[0]&0x02 != 0
 	default:
 		return false
 	}
@@ -57,6 +69,9 @@ func (i *IE) VoiceBearer() bool {
 func (i *IE) ProhibitPayloadCompression() bool {
 	switch i.Type {
 	case BearerFlags:
+		if len(i.Payload) < 1 {
+			return false
+		}
 		return i.Payload[0]&0x01 == 1
 	default:
 		return false
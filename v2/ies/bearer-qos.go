@@ -24,8 +24,14 @@ func NewBearerQoS(pci, pl, pvi, qci uint8, umbr, dmbr, ugbr, dgbr uint64) *IE {
 func (i *IE) QCILabel() uint8 {
 	switch i.Type {
 	case BearerQoS:
+		if len(i.Payload) < 2 {
+			return 0
+		}
 		return i.Payload[1]
 	case FlowQoS:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return i.Payload[0]
 	default:
 		return 0
@@ -34,48 +40,37 @@ func (i *IE) QCILabel() uint8 {
 
 // MBRForUplink returns MBRForUplink in uint64 if the type of IE matches.
 func (i *IE) MBRForUplink() uint64 {
-	switch i.Type {
-	case BearerQoS:
-		return utils.Uint40To64(i.Payload[3:7])
-	case FlowQoS:
-		return utils.Uint40To64(i.Payload[2:6])
-	default:
-		return 0
-	}
+	return i.bitRate(2, 1)
 }
 
 // MBRForDownlink returns MBRForDownlink in uint64 if the type of IE matches.
 func (i *IE) MBRForDownlink() uint64 {
-	switch i.Type {
-	case BearerQoS:
-		return utils.Uint40To64(i.Payload[7:12])
-	case FlowQoS:
-		return utils.Uint40To64(i.Payload[6:11])
-	default:
-		return 0
-	}
+	return i.bitRate(7, 6)
 }
 
 // GBRForUplink returns GBRForUplink in uint64 if the type of IE matches.
 func (i *IE) GBRForUplink() uint64 {
-	switch i.Type {
-	case BearerQoS:
-		return utils.Uint40To64(i.Payload[12:17])
-	case FlowQoS:
-		return utils.Uint40To64(i.Payload[11:16])
-	default:
-		return 0
-	}
+	return i.bitRate(12, 11)
 }
 
 // GBRForDownlink returns GBRForDownlink in uint64 if the type of IE matches.
 func (i *IE) GBRForDownlink() uint64 {
+	return i.bitRate(17, 16)
+}
+
+// bitRate returns the 40-bit bit rate at the offset given for each type.
+func (i *IE) bitRate(bearerOffset, flowOffset int) uint64 {
+	var offset int
 	switch i.Type {
 	case BearerQoS:
-		return utils.Uint40To64(i.Payload[17:22])
+		offset = bearerOffset
 	case FlowQoS:
-		return utils.Uint40To64(i.Payload[16:21])
+		offset = flowOffset
 	default:
 		return 0
 	}
+	if len(i.Payload) < offset+5 {
+		return 0
+	}
+	return utils.Uint40To64(i.Payload[offset : offset+5])
 }
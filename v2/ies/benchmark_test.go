@@ -0,0 +1,87 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+var benchIEs = []struct {
+	description string
+	ie          *ies.IE
+}{
+	{"IMSI", ies.NewIMSI("123451234567890")},
+	{"FullyQualifiedTEID", ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "")},
+	{
+		"BearerContext",
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(0x05),
+			ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0xffffffff, "1.1.1.4", ""),
+		),
+	},
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, c := range benchIEs {
+		b.Run(c.description, func(b *testing.B) {
+			buf := make([]byte, c.ie.Len())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.ie.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, c := range benchIEs {
+		b.Run(c.description, func(b *testing.B) {
+			serialized, err := c.ie.Serialize()
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ie, err := ies.Decode(serialized)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if ie.IsGrouped() {
+					if _, err := ie.Children(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeMultiIEs(b *testing.B) {
+	var serialized []byte
+	for _, c := range benchIEs {
+		s, err := c.ie.Serialize()
+		if err != nil {
+			b.Fatal(err)
+		}
+		serialized = append(serialized, s...)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ies.DecodeMultiIEs(serialized); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
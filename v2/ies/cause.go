@@ -19,20 +19,34 @@ func NewCause(cause uint8, pce, bce, cs uint8, offendingIE *IE) *IE {
 
 // Cause returns Cause in uint8 if the type of IE matches.
 func (i *IE) Cause() uint8 {
+	cause, _ := i.ParseCause()
+	return cause
+}
+
+// ParseCause returns Cause in uint8, or the error if the type of IE does not match
+// or the payload is too short.
+func (i *IE) ParseCause() (uint8, error) {
 	if i.Type != Cause {
-		return 0
+		return 0, ErrInvalidType
+	}
+	if len(i.Payload) < 1 {
+		return 0, ErrTooShortToDecode
 	}
 
-	return i.Payload[0]
+	return i.Payload[0], nil
 }
 
-// IsRemoteCause returns IsRemoteCause in bool if the type of IE matches.
+// IsRemoteCause returns IsRemoteCause in bool if the type of IE matches, i.e., the
+// Cause Source(CS) flag telling that the Cause is originated by the remote node.
 func (i *IE) IsRemoteCause() bool {
 	if i.Type != Cause {
 		return false
 	}
+	if len(i.Payload) < 2 {
+		return false
+	}
 
-	if i.Payload[1]>>2&0x01 == 1 {
+	if i.Payload[1]&0x01 == 1 {
 		return true
 	}
 	return false
@@ -43,6 +57,9 @@ func (i *IE) IsBearerContextIEError() bool {
 	if i.Type != Cause {
 		return false
 	}
+	if len(i.Payload) < 2 {
+		return false
+	}
 
 	if i.Payload[1]>>1&0x01 == 1 {
 		return true
@@ -55,8 +72,11 @@ func (i *IE) IsPDNConnectionIEError() bool {
 	if i.Type != Cause {
 		return false
 	}
+	if len(i.Payload) < 2 {
+		return false
+	}
 
-	if i.Payload[1]&0x01 == 1 {
+	if i.Payload[1]>>2&0x01 == 1 {
 		return true
 	}
 	return false
@@ -13,8 +13,14 @@ func NewCSGMembershipIndication(cmi uint8) *IE {
 func (i *IE) CMI() uint8 {
 	switch i.Type {
 	case CSGMembershipIndication:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return i.Payload[0] & 0x01
 	case UserCSGInformation:
+		if len(i.Payload) < 8 {
+			return 0
+		}
 		return i.Payload[7] & 0x01
 	default:
 		return 0
@@ -0,0 +1,38 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// CSGInformationReportingAction flags.
+const (
+	// CSGReportingFlagCSG is UCICSG, to start reporting the User CSG Information in
+	// the CSG cells.
+	CSGReportingFlagCSG uint8 = 1 << iota
+	// CSGReportingFlagSubscribedHybrid is UCISHC, to start reporting it in the hybrid
+	// cells the UE is a member of.
+	CSGReportingFlagSubscribedHybrid
+	// CSGReportingFlagUnsubscribedHybrid is UCIUHC, to start reporting it in the hybrid
+	// cells the UE is not a member of.
+	CSGReportingFlagUnsubscribedHybrid
+)
+
+// NewCSGInformationReportingAction creates a new CSGInformationReportingAction IE.
+//
+// flags is the combination of CSGReportingFlag*, and 0 means to stop reporting.
+func NewCSGInformationReportingAction(flags uint8) *IE {
+	return newUint8ValIE(CSGInformationReportingAction, flags&0x07)
+}
+
+// CSGInformationReportingAction returns the flags in CSGInformationReportingAction,
+// i.e., the combination of CSGReportingFlag*, if the type of IE matches.
+func (i *IE) CSGInformationReportingAction() uint8 {
+	if i.Type != CSGInformationReportingAction {
+		return 0
+	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
+
+	return i.Payload[0] & 0x07
+}
@@ -16,6 +16,9 @@ func (i *IE) DelayValue() time.Duration {
 	if i.Type != DelayValue {
 		return time.Duration(0)
 	}
+	if len(i.Payload) < 1 {
+		return time.Duration(0)
+	}
 
-	return time.Duration(i.Payload[0]/50) * time.Millisecond
+	return time.Duration(i.Payload[0]) * 50 * time.Millisecond
 }
@@ -0,0 +1,259 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "fmt"
+
+// names of the IE types, which are defined as constants in this package.
+var names = map[uint8]string{
+	IMSI:                                     "IMSI",
+	Cause:                                    "Cause",
+	Recovery:                                 "Recovery",
+	STNSR:                                    "STNSR",
+	AccessPointName:                          "AccessPointName",
+	AggregateMaximumBitRate:                  "AggregateMaximumBitRate",
+	EPSBearerID:                              "EPSBearerID",
+	IPAddress:                                "IPAddress",
+	MobileEquipmentIdentity:                  "MobileEquipmentIdentity",
+	MSISDN:                                   "MSISDN",
+	Indication:                               "Indication",
+	ProtocolConfigurationOptions:             "ProtocolConfigurationOptions",
+	PDNAddressAllocation:                     "PDNAddressAllocation",
+	BearerQoS:                                "BearerQoS",
+	FlowQoS:                                  "FlowQoS",
+	RATType:                                  "RATType",
+	ServingNetwork:                           "ServingNetwork",
+	BearerTFT:                                "BearerTFT",
+	TrafficAggregateDescription:              "TrafficAggregateDescription",
+	UserLocationInformation:                  "UserLocationInformation",
+	FullyQualifiedTEID:                       "FullyQualifiedTEID",
+	TMSI:                                     "TMSI",
+	GlobalCNID:                               "GlobalCNID",
+	S103PDNDataForwardingInfo:                "S103PDNDataForwardingInfo",
+	S1UDataForwarding:                        "S1UDataForwarding",
+	DelayValue:                               "DelayValue",
+	BearerContext:                            "BearerContext",
+	ChargingID:                               "ChargingID",
+	ChargingCharacteristics:                  "ChargingCharacteristics",
+	TraceInformation:                         "TraceInformation",
+	BearerFlags:                              "BearerFlags",
+	PDNType:                                  "PDNType",
+	ProcedureTransactionID:                   "ProcedureTransactionID",
+	MMContextGSMKeyAndTriplets:               "MMContextGSMKeyAndTriplets",
+	MMContextUMTSKeyUsedCipherAndQuintuplets: "MMContextUMTSKeyUsedCipherAndQuintuplets",
+	MMContextGSMKeyUsedCipherAndQuintuplets:  "MMContextGSMKeyUsedCipherAndQuintuplets",
+	MMContextUMTSKeyAndQuintuplets:           "MMContextUMTSKeyAndQuintuplets",
+	MMContextEPSSecurityContextQuadrupletsAndQuintuplets: "MMContextEPSSecurityContextQuadrupletsAndQuintuplets",
+	MMContextUMTSKeyQuadrupletsAndQuintuplets:            "MMContextUMTSKeyQuadrupletsAndQuintuplets",
+	PDNConnection:                          "PDNConnection",
+	PDUNumbers:                             "PDUNumbers",
+	PacketTMSI:                             "PacketTMSI",
+	PTMSISignature:                         "PTMSISignature",
+	HopCounter:                             "HopCounter",
+	UETimeZone:                             "UETimeZone",
+	TraceReference:                         "TraceReference",
+	CompleteRequestMessage:                 "CompleteRequestMessage",
+	GUTI:                                   "GUTI",
+	FContainer:                             "FContainer",
+	FCause:                                 "FCause",
+	PLMNID:                                 "PLMNID",
+	TargetIdentification:                   "TargetIdentification",
+	PacketFlowID:                           "PacketFlowID",
+	RABContext:                             "RABContext",
+	SourceRNCPDCPContextInfo:               "SourceRNCPDCPContextInfo",
+	PortNumber:                             "PortNumber",
+	APNRestriction:                         "APNRestriction",
+	SelectionMode:                          "SelectionMode",
+	SourceIdentification:                   "SourceIdentification",
+	Reserved:                               "Reserved",
+	ChangeReportingAction:                  "ChangeReportingAction",
+	FullyQualifiedCSID:                     "FullyQualifiedCSID",
+	ChannelNeeded:                          "ChannelNeeded",
+	EMLPPPriority:                          "EMLPPPriority",
+	NodeType:                               "NodeType",
+	FullyQualifiedDomainName:               "FullyQualifiedDomainName",
+	TI:                                     "TI",
+	MBMSSessionDuration:                    "MBMSSessionDuration",
+	MBMSServiceArea:                        "MBMSServiceArea",
+	MBMSSessionIdentifier:                  "MBMSSessionIdentifier",
+	MBMSFlowIdentifier:                     "MBMSFlowIdentifier",
+	MBMSIPMulticastDistribution:            "MBMSIPMulticastDistribution",
+	MBMSDistributionAcknowledge:            "MBMSDistributionAcknowledge",
+	RFSPIndex:                              "RFSPIndex",
+	UserCSGInformation:                     "UserCSGInformation",
+	CSGInformationReportingAction:          "CSGInformationReportingAction",
+	CSGID:                                  "CSGID",
+	CSGMembershipIndication:                "CSGMembershipIndication",
+	ServiceIndicator:                       "ServiceIndicator",
+	DetachType:                             "DetachType",
+	LocalDistinguishedName:                 "LocalDistinguishedName",
+	NodeFeatures:                           "NodeFeatures",
+	MBMSTimeToDataTransfer:                 "MBMSTimeToDataTransfer",
+	Throttling:                             "Throttling",
+	AllocationRetensionPriority:            "AllocationRetensionPriority",
+	EPCTimer:                               "EPCTimer",
+	SignallingPriorityIndication:           "SignallingPriorityIndication",
+	TMGI:                                   "TMGI",
+	AdditionalMMContextForSRVCC:            "AdditionalMMContextForSRVCC",
+	AdditionalFlagsForSRVCC:                "AdditionalFlagsForSRVCC",
+	MDTConfiguration:                       "MDTConfiguration",
+	AdditionalProtocolConfigurationOptions: "AdditionalProtocolConfigurationOptions",
+	AbsoluteTimeofMBMSDataTransfer:         "AbsoluteTimeofMBMSDataTransfer",
+	HeNBInformationReporting:               "HeNBInformationReporting",
+	IPv4ConfigurationParameters:            "IPv4ConfigurationParameters",
+	ChangeToReportFlags:                    "ChangeToReportFlags",
+	ActionIndication:                       "ActionIndication",
+	TWANIdentifier:                         "TWANIdentifier",
+	ULITimestamp:                           "ULITimestamp",
+	MBMSFlags:                              "MBMSFlags",
+	RANNASCause:                            "RANNASCause",
+	CNOperatorSelectionEntity:              "CNOperatorSelectionEntity",
+	TrustedWLANModeIndication:              "TrustedWLANModeIndication",
+	NodeNumber:                             "NodeNumber",
+	NodeIdentifier:                         "NodeIdentifier",
+	PresenceReportingAreaAction:            "PresenceReportingAreaAction",
+	PresenceReportingAreaInformation:       "PresenceReportingAreaInformation",
+	TWANIdentifierTimestamp:                "TWANIdentifierTimestamp",
+	OverloadControlInformation:             "OverloadControlInformation",
+	LoadControlInformation:                 "LoadControlInformation",
+	Metric:                                 "Metric",
+	SequenceNumber:                         "SequenceNumber",
+	APNAndRelativeCapacity:                 "APNAndRelativeCapacity",
+	WLANOffloadabilityIndication:           "WLANOffloadabilityIndication",
+	PagingAndServiceInformation:            "PagingAndServiceInformation",
+	IntegerNumber:                          "IntegerNumber",
+	MillisecondTimeStamp:                   "MillisecondTimeStamp",
+	MonitoringEventInformation:             "MonitoringEventInformation",
+	ECGIList:                               "ECGIList",
+	RemoteUEContext:                        "RemoteUEContext",
+	RemoteUserID:                           "RemoteUserID",
+	RemoteUEIPinformation:                  "RemoteUEIPinformation",
+	CIoTOptimizationsSupportIndication:     "CIoTOptimizationsSupportIndication",
+	SCEFPDNConnection:                      "SCEFPDNConnection",
+	HeaderCompressionConfiguration:         "HeaderCompressionConfiguration",
+	ExtendedProtocolConfigurationOptions:   "ExtendedProtocolConfigurationOptions",
+	ServingPLMNRateControl:                 "ServingPLMNRateControl",
+	Counter:                                "Counter",
+	MappedUEUsageType:                      "MappedUEUsageType",
+	SecondaryRATUsageDataReport:            "SecondaryRATUsageDataReport",
+	UPFunctionSelectionIndicationFlags:     "UPFunctionSelectionIndicationFlags",
+	MaximumPacketLossRate:                  "MaximumPacketLossRate",
+	APNRateControlStatus:                   "APNRateControlStatus",
+	ExtendedTraceInformation:               "ExtendedTraceInformation",
+	SpecialIETypeForIETypeExtension:        "SpecialIETypeForIETypeExtension",
+	PrivateExtension:                       "PrivateExtension",
+}
+
+// Name returns the name of the IE type, or "Unknown" if the type is not known.
+func (i *IE) Name() string {
+	if name, ok := names[i.Type]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// ValueString returns the value of the IE in human readable format.
+//
+// The value is decoded with the getter corresponding to the type, and the
+// Payload is returned in hex instead if there is no such getter or the Payload
+// is malformed.
+func (i *IE) ValueString() (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("%x (malformed: %v)", i.Payload, r)
+		}
+	}()
+
+	if s = i.value(); s == "" {
+		s = fmt.Sprintf("%x", i.Payload)
+	}
+	return s
+}
+
+func (i *IE) value() string {
+	switch i.Type {
+	case IMSI:
+		return i.IMSI()
+	case Cause:
+		return fmt.Sprintf("%d", i.Cause())
+	case Recovery:
+		return fmt.Sprintf("%d", i.Recovery())
+	case AccessPointName:
+		return i.AccessPointName()
+	case AggregateMaximumBitRate:
+		return fmt.Sprintf("Uplink: %d, Downlink: %d", i.AggregateMaximumBitRateUp(), i.AggregateMaximumBitRateDown())
+	case EPSBearerID:
+		return fmt.Sprintf("%d", i.EPSBearerID())
+	case IPAddress:
+		return i.IPAddress()
+	case MobileEquipmentIdentity:
+		return i.MobileEquipmentIdentity()
+	case MSISDN:
+		return i.MSISDN()
+	case PDNAddressAllocation:
+		return fmt.Sprintf("PDNType: %d, Address: %s", i.PDNType(), i.IPAddress())
+	case BearerQoS:
+		return fmt.Sprintf("PCI: %v, PL: %d, PVI: %v, QCI: %d, MBR UL/DL: %d/%d, GBR UL/DL: %d/%d",
+			i.PreemptionCapability(), i.PriorityLevel(), i.PreemptionVulnerability(), i.QCILabel(),
+			i.MBRForUplink(), i.MBRForDownlink(), i.GBRForUplink(), i.GBRForDownlink())
+	case RATType:
+		return fmt.Sprintf("%d", i.RATType())
+	case ServingNetwork:
+		return fmt.Sprintf("MCC: %s, MNC: %s", i.MCC(), i.MNC())
+	case FullyQualifiedTEID:
+		return fmt.Sprintf("InterfaceType: %d, TEID: %#08x, Address: %s", i.InterfaceType(), i.TEID(), i.IPAddress())
+	case TMSI:
+		return fmt.Sprintf("%#08x", i.TMSI())
+	case DelayValue:
+		return i.DelayValue().String()
+	case ChargingID:
+		return fmt.Sprintf("%#08x", i.ChargingID())
+	case ChargingCharacteristics:
+		return fmt.Sprintf("%#04x", i.ChargingCharacteristics())
+	case BearerFlags:
+		return fmt.Sprintf("%#02x", i.BearerFlags())
+	case PDNType:
+		return fmt.Sprintf("%d", i.PDNType())
+	case ProcedureTransactionID:
+		return fmt.Sprintf("%d", i.ProcedureTransactionID())
+	case PacketTMSI:
+		return fmt.Sprintf("%#08x", i.PacketTMSI())
+	case GUTI:
+		return fmt.Sprintf("MCC: %s, MNC: %s, MMEGroupID: %#04x, MMECode: %#02x, MTMSI: %#08x", i.MCC(), i.MNC(), i.MMEGroupID(), i.MMECode(), i.MTMSI())
+	case PTMSISignature:
+		return fmt.Sprintf("%#06x", i.PTMSISignature())
+	case HopCounter:
+		return fmt.Sprintf("%d", i.HopCounter())
+	case UETimeZone:
+		return fmt.Sprintf("%s, DaylightSaving: %d", i.TimeZone(), i.DaylightSaving())
+	case PLMNID:
+		return i.PLMNID()
+	case PortNumber:
+		return fmt.Sprintf("%d", i.PortNumber())
+	case APNRestriction:
+		return fmt.Sprintf("%d", i.APNRestriction())
+	case SelectionMode:
+		return fmt.Sprintf("%d", i.SelectionMode())
+	case FullyQualifiedCSID:
+		return fmt.Sprintf("NodeIDType: %d, NodeID: %x, CSIDs: %v", i.NodeIDType(), i.NodeID(), i.CSIDs())
+	case NodeType:
+		return fmt.Sprintf("%d", i.NodeType())
+	case FullyQualifiedDomainName:
+		return i.FullyQualifiedDomainName()
+	case RFSPIndex:
+		return fmt.Sprintf("%d", i.RFSPIndex())
+	case CSGID:
+		return fmt.Sprintf("%#08x", i.CSGID())
+	case ServiceIndicator:
+		return fmt.Sprintf("%d", i.ServiceIndicator())
+	case DetachType:
+		return fmt.Sprintf("%d", i.DetachType())
+	case LocalDistinguishedName:
+		return i.LocalDistinguishedName()
+	case PrivateExtension:
+		return fmt.Sprintf("EnterpriseID: %d, Value: %x", i.EnterpriseID(), i.PrivateExtension())
+	}
+	return ""
+}
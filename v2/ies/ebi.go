@@ -12,9 +12,19 @@ func NewEPSBearerID(ebi uint8) *IE {
 
 // EPSBearerID returns EPSBearerID if the type of IE matches.
 func (i *IE) EPSBearerID() uint8 {
+	ebi, _ := i.ParseEPSBearerID()
+	return ebi
+}
+
+// ParseEPSBearerID returns EPSBearerID, or the error if the type of IE does not
+// match or the payload is too short.
+func (i *IE) ParseEPSBearerID() (uint8, error) {
 	if i.Type != EPSBearerID {
-		return 0
+		return 0, ErrInvalidType
+	}
+	if len(i.Payload) < 1 {
+		return 0, ErrTooShortToDecode
 	}
 
-	return i.Payload[0]
+	return i.Payload[0], nil
 }
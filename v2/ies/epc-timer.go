@@ -0,0 +1,55 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"math"
+	"time"
+)
+
+// EPCTimerInfinite is the value of EPC Timer indicating that the timer is infinite.
+const EPCTimerInfinite = time.Duration(math.MaxInt64)
+
+// NewEPCTimer creates a new EPCTimer IE, which is used as, e.g., PGW Back-Off Time.
+//
+// The duration is encoded with the smallest unit that can represent it, which is
+// truncated to the unit, in the same units as Throttling Delay. EPCTimerInfinite
+// is encoded as infinite.
+func NewEPCTimer(d time.Duration) *IE {
+	if d == EPCTimerInfinite {
+		return New(EPCTimer, 0x00, []byte{0xe0})
+	}
+
+	var unit, value uint8
+	for u, ud := range throttlingUnits {
+		unit, value = uint8(u), uint8(min(d/ud, 31))
+		if d <= 31*ud {
+			break
+		}
+	}
+	return New(EPCTimer, 0x00, []byte{unit<<5 | value})
+}
+
+// EPCTimer returns the timer value in time.Duration if the type of IE matches.
+// The timer infinite is returned as EPCTimerInfinite.
+func (i *IE) EPCTimer() time.Duration {
+	if i.Type != EPCTimer {
+		return 0
+	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
+
+	unit, value := int(i.Payload[0]>>5), time.Duration(i.Payload[0]&0x1f)
+	switch {
+	case unit == 7:
+		return EPCTimerInfinite
+	case unit < len(throttlingUnits):
+		return value * throttlingUnits[unit]
+	default:
+		// the other values shall be interpreted as 1 minute.
+		return value * time.Minute
+	}
+}
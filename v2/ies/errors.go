@@ -13,4 +13,5 @@ var (
 
 	ErrInvalidType = errors.New("invalid type")
 	ErrIENotFound  = errors.New("could not find the specified IE in a grouped IE")
+	ErrMalformed   = errors.New("malformed value in IE")
 )
@@ -33,6 +33,9 @@ func (i *IE) HasIPv4() bool {
 	if i.Type != FullyQualifiedTEID {
 		return false
 	}
+	if len(i.Payload) < 1 {
+		return false
+	}
 
 	return i.Payload[0]&0x80>>7 == 1
 }
@@ -42,54 +45,94 @@ func (i *IE) HasIPv6() bool {
 	if i.Type != FullyQualifiedTEID {
 		return false
 	}
+	if len(i.Payload) < 1 {
+		return false
+	}
 
 	return i.Payload[0]&0x48>>6 == 1
 }
 
 // InterfaceType returns InterfaceType in uint8 if the type of IE matches.
 func (i *IE) InterfaceType() uint8 {
+	ifType, _ := i.ParseInterfaceType()
+	return ifType
+}
+
+// ParseInterfaceType returns InterfaceType in uint8, or the error if the type of IE
+// does not match or the payload is too short.
+func (i *IE) ParseInterfaceType() (uint8, error) {
 	if i.Type != FullyQualifiedTEID {
-		return 0
+		return 0, ErrInvalidType
+	}
+	if len(i.Payload) < 1 {
+		return 0, ErrTooShortToDecode
 	}
 
-	return i.Payload[0] & 0x3f
+	return i.Payload[0] & 0x3f, nil
 }
 
 // GREKey returns GREKey in uint32 if the type of IE matches.
 func (i *IE) GREKey() uint32 {
+	key, _ := i.ParseGREKey()
+	return key
+}
+
+// ParseGREKey returns GREKey in uint32, or the error if the type of IE does not
+// match or the payload is malformed.
+func (i *IE) ParseGREKey() (uint32, error) {
 	switch i.Type {
 	case FullyQualifiedTEID:
-		return binary.BigEndian.Uint32(i.Payload[1:5])
-	case S103PDNDataForwardingInfo:
-		switch i.Payload[0] {
-		case 4:
-			return binary.BigEndian.Uint32(i.Payload[5:9])
-		case 16:
-			return binary.BigEndian.Uint32(i.Payload[17:21])
-		default:
-			return 0
+		if len(i.Payload) < 5 {
+			return 0, ErrTooShortToDecode
 		}
+		return binary.BigEndian.Uint32(i.Payload[1:5]), nil
+	case S103PDNDataForwardingInfo:
+		return i.teidAfterAddress()
 	default:
-		return 0
+		return 0, ErrInvalidType
 	}
 }
 
 // TEID returns TEID in uint32 if the type of IE matches.
 func (i *IE) TEID() uint32 {
+	teid, _ := i.ParseTEID()
+	return teid
+}
+
+// ParseTEID returns TEID in uint32, or the error if the type of IE does not match
+// or the payload is malformed.
+func (i *IE) ParseTEID() (uint32, error) {
 	switch i.Type {
 	case FullyQualifiedTEID:
-		return binary.BigEndian.Uint32(i.Payload[1:5])
-	case S1UDataForwarding:
-		switch i.Payload[0] {
-		case 4:
-			return binary.BigEndian.Uint32(i.Payload[5:9])
-		case 16:
-			return binary.BigEndian.Uint32(i.Payload[17:21])
-		default:
-			return 0
+		if len(i.Payload) < 5 {
+			return 0, ErrTooShortToDecode
 		}
+		return binary.BigEndian.Uint32(i.Payload[1:5]), nil
+	case S1UDataForwarding:
+		return i.teidAfterAddress()
 	default:
-		return 0
+		return 0, ErrInvalidType
 	}
+}
 
+// teidAfterAddress returns the TEID or GRE Key that follows the address
+// with its length in the first octet.
+func (i *IE) teidAfterAddress() (uint32, error) {
+	if len(i.Payload) < 1 {
+		return 0, ErrTooShortToDecode
+	}
+	switch i.Payload[0] {
+	case 4:
+		if len(i.Payload) < 9 {
+			return 0, ErrTooShortToDecode
+		}
+		return binary.BigEndian.Uint32(i.Payload[5:9]), nil
+	case 16:
+		if len(i.Payload) < 21 {
+			return 0, ErrTooShortToDecode
+		}
+		return binary.BigEndian.Uint32(i.Payload[17:21]), nil
+	default:
+		return 0, ErrMalformed
+	}
 }
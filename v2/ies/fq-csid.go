@@ -54,6 +54,9 @@ func NewFullyQualifiedCSID(nodeID string, csIDs ...uint16) *IE {
 func (i *IE) NodeIDType() uint8 {
 	switch i.Type {
 	case FullyQualifiedCSID:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return (i.Payload[0] >> 4) & 0x0f
 	default:
 		return 0
@@ -64,10 +67,19 @@ func (i *IE) NodeIDType() uint8 {
 func (i *IE) NodeID() []byte {
 	switch i.Type {
 	case FullyQualifiedCSID:
+		if len(i.Payload) < 1 {
+			return nil
+		}
 		switch (i.Payload[0] >> 4) & 0x0f {
 		case nodeIDIPv4, nodeIDOther:
+			if len(i.Payload) < 5 {
+				return nil
+			}
 			return i.Payload[1:5]
 		case nodeIDIPv6:
+			if len(i.Payload) < 17 {
+				return nil
+			}
 			return i.Payload[1:17]
 		default:
 			return nil
@@ -81,6 +93,9 @@ func (i *IE) NodeID() []byte {
 func (i *IE) CSIDs() []uint16 {
 	switch i.Type {
 	case FullyQualifiedCSID:
+		if len(i.Payload) < 1 {
+			return nil
+		}
 		offset := 0
 		switch (i.Payload[0] >> 4) & 0x0f {
 		case nodeIDIPv4, nodeIDOther:
@@ -92,11 +107,9 @@ func (i *IE) CSIDs() []uint16 {
 		}
 
 		var csids []uint16
-		for {
-			if offset >= len(i.Payload) {
-				break
-			}
+		for offset+2 <= len(i.Payload) {
 			csids = append(csids, binary.BigEndian.Uint16(i.Payload[offset:offset+2]))
+			offset += 2
 		}
 		return csids
 	default:
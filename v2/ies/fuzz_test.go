@@ -0,0 +1,74 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func FuzzDecode(f *testing.F) {
+	seeds := []*ies.IE{
+		ies.NewIMSI("123451234567890"),
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, ies.NewRecovery(0)),
+		ies.NewRecovery(0xff),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewAggregateMaximumBitRate(0x11111111, 0x22222222),
+		ies.NewEPSBearerID(0x05),
+		ies.NewIPAddress("2001::1"),
+		ies.NewMobileEquipmentIdentity("123450123456789"),
+		ies.NewMSISDN("123450123456789"),
+		ies.NewIndicationFromOctets(0xa1, 0x08, 0x15, 0x10, 0x88, 0x81, 0x40),
+		ies.NewPDNAddressAllocation("2001::1"),
+		ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewServingNetwork("123", "45"),
+		ies.NewUserLocationInformationLazy("123", "45", 0x1111, 0x2222, 0x3333, 0x44, 0x5555, 0x66666666, 0x77777, 0x88888),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "2001::1"),
+		ies.NewDelayValue(500 * time.Millisecond),
+		ies.NewBearerContext(ies.NewEPSBearerID(0x05), ies.NewBearerQoS(1, 2, 1, 0xff, 0, 0, 0, 0)),
+		ies.NewChargingID(0xffffffff),
+		ies.NewPDNType(v2.PDNTypeIPv4),
+		ies.NewUETimeZone(9*time.Hour, 0),
+		ies.NewFullyQualifiedCSID("1.1.1.1", 1, 2, 3),
+		ies.NewFullyQualifiedDomainName("some.fqdn.example"),
+		ies.NewPrivateExtension(10415, []byte{0xde, 0xad, 0xbe, 0xef}),
+	}
+	for _, ie := range seeds {
+		b, err := ie.Serialize()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		ie, err := ies.Decode(b)
+		if err != nil {
+			return
+		}
+		callGetters(ie)
+		children, _ := ie.Children()
+		for _, child := range children {
+			callGetters(child)
+		}
+		_, _ = ie.Serialize()
+	})
+}
+
+// callGetters calls all the methods of IE that take no arguments, so that
+// the getters are checked not to panic with the malformed payload.
+func callGetters(ie *ies.IE) {
+	v := reflect.ValueOf(ie)
+	for i := 0; i < v.NumMethod(); i++ {
+		if m := v.Method(i); m.Type().NumIn() == 0 {
+			m.Call(nil)
+		}
+	}
+}
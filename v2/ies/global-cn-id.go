@@ -28,6 +28,9 @@ func NewGlobalCNID(mcc, mnc string, cnid uint16) *IE {
 func (i *IE) CNID() uint16 {
 	switch i.Type {
 	case GlobalCNID:
+		if len(i.Payload) < 5 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[3:5])
 	default:
 		return 0
@@ -28,6 +28,9 @@ func NewGUTI(mcc, mnc string, groupID uint16, code uint8, mTMSI uint32) *IE {
 func (i *IE) MMEGroupID() uint16 {
 	switch i.Type {
 	case GUTI:
+		if len(i.Payload) < 5 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[3:5])
 	default:
 		return 0
@@ -38,6 +41,9 @@ func (i *IE) MMEGroupID() uint16 {
 func (i *IE) MMECode() uint8 {
 	switch i.Type {
 	case GUTI:
+		if len(i.Payload) < 6 {
+			return 0
+		}
 		return i.Payload[5]
 	default:
 		return 0
@@ -48,8 +54,65 @@ func (i *IE) MMECode() uint8 {
 func (i *IE) MTMSI() uint32 {
 	switch i.Type {
 	case GUTI:
+		if len(i.Payload) < 10 {
+			return 0
+		}
 		return binary.BigEndian.Uint32(i.Payload[6:10])
 	default:
 		return 0
 	}
 }
+
+// NewGUTIFromRAI creates a new GUTI IE mapped from the RAI, P-TMSI and P-TMSI
+// Signature, which is used by MME to identify the UE moved from SGSN in the
+// Identification and Context procedures. See utils.RAIToGUTI for the mapping.
+func NewGUTIFromRAI(mcc, mnc string, lac uint16, ptmsi, ptmsiSig uint32) *IE {
+	groupID, code, mTMSI := utils.RAIToGUTI(lac, ptmsi, ptmsiSig)
+	return NewGUTI(mcc, mnc, groupID, code, mTMSI)
+}
+
+// GUTIPayload is the identities in GUTI IE.
+type GUTIPayload struct {
+	MCC, MNC   string
+	MMEGroupID uint16
+	MMECode    uint8
+	MTMSI      uint32
+}
+
+// DecodeGUTIPayload decodes the payload of GUTI IE.
+func DecodeGUTIPayload(b []byte) (*GUTIPayload, error) {
+	if len(b) < 10 {
+		return nil, ErrTooShortToDecode
+	}
+	mcc, mnc, err := utils.DecodePLMN(b[0:3])
+	if err != nil {
+		return nil, err
+	}
+	return &GUTIPayload{
+		MCC:        mcc,
+		MNC:        mnc,
+		MMEGroupID: binary.BigEndian.Uint16(b[3:5]),
+		MMECode:    b[5],
+		MTMSI:      binary.BigEndian.Uint32(b[6:10]),
+	}, nil
+}
+
+// GUTI returns the identities in GUTI IE, or nil if the type is not GUTI or the
+// payload is malformed.
+func (i *IE) GUTI() *GUTIPayload {
+	if i.Type != GUTI {
+		return nil
+	}
+	p, err := DecodeGUTIPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// RAI returns the LAC and RAC in RAI, P-TMSI and P-TMSI Signature mapped from the
+// GUTI, which is used by SGSN to identify the UE moved from MME in the Identification
+// and Context procedures. See utils.GUTIToRAI for the mapping.
+func (p *GUTIPayload) RAI() (lac uint16, rac uint8, ptmsi, ptmsiSig uint32) {
+	return utils.GUTIToRAI(p.MMEGroupID, p.MMECode, p.MTMSI)
+}
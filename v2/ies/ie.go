@@ -10,6 +10,8 @@ package ies
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
+	"sync/atomic"
 )
 
 // IE definitions.
@@ -278,7 +280,39 @@ type IE struct {
 	Length   uint16
 	instance uint8
 	Payload  []byte
+
+	// ChildIEs is the IEs inside the grouped IE.
+	//
+	// With SetLazyDecoding enabled, the ChildIEs of the decoded IE are left nil
+	// until the IE is modified with Add or Remove. Use Children instead of
+	// accessing this directly to get them regardless of how the IE is created.
 	ChildIEs []*IE
+
+	// lazy holds the child IEs decoded on demand from the Payload, if the IE is
+	// decoded with the lazy decoding enabled and not modified after that.
+	lazy *lazyChildren
+}
+
+// lazyChildren is the child IEs of the grouped IE decoded at the first call of
+// Children, which may be made concurrently.
+type lazyChildren struct {
+	once     sync.Once
+	children []*IE
+	err      error
+}
+
+var lazyDecoding atomic.Bool
+
+// SetLazyDecoding enables or disables the lazy decoding of the grouped IEs. It is
+// disabled by default.
+//
+// When enabled, the child IEs of the grouped IEs are not decoded by Decode and the
+// other decoders of IEs and messages but at the first call of Children or the
+// methods that need them(e.g., FindByType, BearerContext), which reduces the cost
+// to decode the large messages when only a few of the IEs are looked into. The
+// malformed child IEs don't fail the decoding then, but Children returns the error.
+func SetLazyDecoding(enabled bool) {
+	lazyDecoding.Store(enabled)
 }
 
 // New creates new IE.
@@ -323,7 +357,7 @@ func (i *IE) SerializeTo(b []byte) error {
 	b[0] = i.Type
 	binary.BigEndian.PutUint16(b[1:3], i.Length)
 	b[3] = i.instance
-	if i.IsGrouped() {
+	if i.IsGrouped() && i.lazy == nil {
 		offset := 4
 		for _, ie := range i.ChildIEs {
 			if err := ie.SerializeTo(b[offset:]); err != nil {
@@ -362,7 +396,15 @@ func (i *IE) DecodeFromBytes(b []byte) error {
 	i.instance = b[3]
 	i.Payload = b[4 : 4+int(i.Length)]
 
+	i.ChildIEs = nil
+	i.lazy = nil
 	if i.IsGrouped() {
+		if lazyDecoding.Load() {
+			// the child IEs are decoded on demand in Children.
+			i.lazy = &lazyChildren{}
+			return nil
+		}
+
 		var err error
 		i.ChildIEs, err = DecodeMultiIEs(i.Payload)
 		if err != nil {
@@ -373,9 +415,30 @@ func (i *IE) DecodeFromBytes(b []byte) error {
 	return nil
 }
 
+// Children returns the IEs inside the grouped IE.
+//
+// The child IEs of the IE decoded with SetLazyDecoding enabled are decoded from
+// the Payload at the first call, and the ones decoded are returned afterwards.
+// It is safe to call it concurrently, but not with Add or Remove.
+//
+// This returns ErrInvalidType if the IE is not grouped type.
+func (i *IE) Children() ([]*IE, error) {
+	if !i.IsGrouped() {
+		return nil, ErrInvalidType
+	}
+
+	if l := i.lazy; l != nil {
+		l.once.Do(func() {
+			l.children, l.err = DecodeMultiIEs(i.Payload)
+		})
+		return l.children, l.err
+	}
+	return i.ChildIEs, nil
+}
+
 // Len returns field length in integer.
 func (i *IE) Len() int {
-	if i.IsGrouped() {
+	if i.IsGrouped() && i.lazy == nil {
 		l := 4
 		for _, ie := range i.ChildIEs {
 			l += ie.Len()
@@ -429,8 +492,12 @@ func (i *IE) Add(ies ...*IE) {
 		return
 	}
 
+	// the malformed child IEs are dropped, as they cannot be serialized anyway.
+	children, _ := i.Children()
+	i.lazy = nil
+
 	i.Payload = nil
-	i.ChildIEs = append(i.ChildIEs, ies...)
+	i.ChildIEs = append(children, ies...)
 	for _, ie := range i.ChildIEs {
 		serialized, err := ie.Serialize()
 		if err != nil {
@@ -447,9 +514,12 @@ func (i *IE) Remove(typ, instance uint8) {
 		return
 	}
 
+	children, _ := i.Children()
+	i.lazy = nil
+
 	i.Payload = nil
 	var newChildren []*IE
-	for _, ie := range i.ChildIEs {
+	for _, ie := range children {
 		if ie.Type == typ && ie.Instance() == instance {
 			continue
 		}
@@ -470,11 +540,12 @@ func (i *IE) Remove(typ, instance uint8) {
 // The program may be slower when calling this method multiple times
 // because this ranges over a ChildIEs each time it is called.
 func (i *IE) FindByType(typ, instance uint8) (*IE, error) {
-	if !i.IsGrouped() {
-		return nil, ErrInvalidType
+	children, err := i.Children()
+	if err != nil {
+		return nil, err
 	}
 
-	for _, ie := range i.ChildIEs {
+	for _, ie := range children {
 		if ie.Type == typ && ie.Instance() == instance {
 			return ie, nil
 		}
@@ -483,22 +554,32 @@ func (i *IE) FindByType(typ, instance uint8) (*IE, error) {
 }
 
 // DecodeMultiIEs decodes multiple IEs at a time.
-// This is easy and useful but slower than decoding one by one.
-// When you don't know the number of IEs, this is the only way to decode them.
-// See benchmarks in diameter_test.go for the detail.
+//
+// The IEs returned share one backing array allocated at once, which is faster
+// than decoding them one by one. See benchmark_test.go for the detail.
 func DecodeMultiIEs(b []byte) ([]*IE, error) {
-	var ies []*IE
-	for {
-		if len(b) == 0 {
-			break
-		}
+	// count the IEs first to allocate them all at once instead of one by one.
+	n := 0
+	for offset := 0; offset+4 <= len(b); n++ {
+		offset += 4 + int(binary.BigEndian.Uint16(b[offset+1:offset+3]))
+	}
 
-		i, err := Decode(b)
-		if err != nil {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	decoded := make([]IE, n)
+	ies := make([]*IE, n)
+	for k := range decoded {
+		i := &decoded[k]
+		if err := i.DecodeFromBytes(b); err != nil {
 			return nil, err
 		}
-		ies = append(ies, i)
-		b = b[i.Len():]
+		ies[k] = i
+		b = b[4+len(i.Payload):]
+	}
+	if len(b) > 0 {
+		return nil, ErrTooShortToDecode
 	}
 	return ies, nil
 }
@@ -5,6 +5,9 @@
 package ies_test
 
 import (
+	"errors"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +16,27 @@ import (
 	"github.com/wmnsk/go-gtp/v2/ies"
 )
 
+func TestQoSGetters(t *testing.T) {
+	arp := ies.NewAllocationRetensionPriority(0, 15, 0)
+	if got := arp.PriorityLevel(); got != 15 {
+		t.Errorf("got PriorityLevel %d, want 15", got)
+	}
+
+	bqos := ies.NewBearerQoS(0, 15, 0, 9, 0x1111111111, 0x2222222222, 0x3333333333, 0x4444444444)
+	fqos := ies.NewFlowQoS(9, 0x1111111111, 0x2222222222, 0x3333333333, 0x4444444444)
+	if got := bqos.PriorityLevel(); got != 15 {
+		t.Errorf("got PriorityLevel %d, want 15", got)
+	}
+	for _, ie := range []*ies.IE{bqos, fqos} {
+		if got := ie.MBRForUplink(); got != 0x1111111111 {
+			t.Errorf("%d: got MBRForUplink %#x, want 0x1111111111", ie.Type, got)
+		}
+		if got := ie.MBRForDownlink(); got != 0x2222222222 {
+			t.Errorf("%d: got MBRForDownlink %#x, want 0x2222222222", ie.Type, got)
+		}
+	}
+}
+
 func TestIEs(t *testing.T) {
 	cases := []struct {
 		description string
@@ -119,6 +143,14 @@ func TestIEs(t *testing.T) {
 			[]byte{0x4f, 0x00, 0x12, 0x00, 0x02, 0x00, 0x20, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
 		}, */
 		{
+			"PDNAddressAllocation/v6-prefix",
+			ies.NewPDNAddressAllocationIPv6("2001:db8:1:2::/64"),
+			[]byte{0x4f, 0x00, 0x12, 0x00, 0x02, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		}, {
+			"PDNAddressAllocation/v4v6",
+			ies.NewPDNAddressAllocationIPv4v6("1.1.1.1", "2001:db8:1:2::/64"),
+			[]byte{0x4f, 0x00, 0x16, 0x00, 0x03, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01},
+		}, {
 			"BearerQoS",
 			ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
 			[]byte{0x50, 0x00, 0x16, 0x00, 0x49, 0xff, 0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22, 0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22},
@@ -166,7 +198,7 @@ func TestIEs(t *testing.T) {
 				// TAI
 				0x21, 0xf3, 0x54, 0x55, 0x55,
 				// ECGI
-				0x21, 0xf3, 0x54, 0x00, 0x06, 0x66, 0x66,
+				0x21, 0xf3, 0x54, 0x00, 0x66, 0x66, 0x66,
 				// RAI
 				0x21, 0xf3, 0x54, 0x11, 0x11,
 				// Extended Macro eNB ID
@@ -191,7 +223,7 @@ func TestIEs(t *testing.T) {
 				// TAI
 				0x21, 0xf3, 0x54, 0x55, 0x55,
 				// ECGI
-				0x21, 0xf3, 0x54, 0x00, 0x06, 0x66, 0x66,
+				0x21, 0xf3, 0x54, 0x00, 0x66, 0x66, 0x66,
 				// RAI
 				0x21, 0xf3, 0x54, 0x11, 0x11,
 				// Macro eNB ID
@@ -218,7 +250,7 @@ func TestIEs(t *testing.T) {
 				// TAI
 				0x21, 0xf3, 0x54, 0x55, 0x55,
 				// ECGI
-				0x21, 0xf3, 0x54, 0x00, 0x06, 0x66, 0x66,
+				0x21, 0xf3, 0x54, 0x00, 0x66, 0x66, 0x66,
 				// RAI
 				0x21, 0xf3, 0x54, 0x11, 0x11,
 				// Macro eNB ID
@@ -266,6 +298,18 @@ func TestIEs(t *testing.T) {
 			"DelayValue",
 			ies.NewDelayValue(500 * time.Millisecond),
 			[]byte{0x5c, 0x00, 0x01, 0x00, 0x0a},
+		}, {
+			"Throttling",
+			ies.NewThrottling(10*time.Minute, 50),
+			[]byte{0x9a, 0x00, 0x02, 0x00, 0x2a, 0x32},
+		}, {
+			"EPCTimer",
+			ies.NewEPCTimer(90 * time.Minute),
+			[]byte{0x9c, 0x00, 0x01, 0x00, 0x49},
+		}, {
+			"SignallingPriorityIndication",
+			ies.NewSignallingPriorityIndication(1),
+			[]byte{0x9d, 0x00, 0x01, 0x00, 0x01},
 		}, {
 			"BearerContext",
 			ies.NewBearerContext(ies.NewDelayValue(500*time.Millisecond), ies.NewDelayValue(100*time.Millisecond)),
@@ -366,6 +410,10 @@ func TestIEs(t *testing.T) {
 			"UserCSGInformation",
 			ies.NewUserCSGInformation("123", "45", 0x00ffffff, v2.AccessModeHybrid, 0, v2.CMICSG),
 			[]byte{0x91, 0x00, 0x08, 0x00, 0x21, 0xf3, 0x54, 0x00, 0xff, 0xff, 0xff, 0x41},
+		}, {
+			"CSGInformationReportingAction",
+			ies.NewCSGInformationReportingAction(ies.CSGReportingFlagCSG | ies.CSGReportingFlagUnsubscribedHybrid),
+			[]byte{0x92, 0x00, 0x01, 0x00, 0x05},
 		}, {
 			"CSGID",
 			ies.NewCSGID(0x00ffffff),
@@ -390,14 +438,77 @@ func TestIEs(t *testing.T) {
 			"AllocationRetensionPriority",
 			ies.NewAllocationRetensionPriority(1, 2, 1),
 			[]byte{0x9b, 0x00, 0x01, 0x00, 0x49},
+		}, {
+			"AdditionalProtocolConfigurationOptions",
+			ies.NewAdditionalProtocolConfigurationOptions(
+				v2.ConfigProtocolPPPWithIP,
+				ies.NewConfigurationProtocolOption(v2.ContIDDNSServerIPv4AddressRequest, nil),
+				ies.NewConfigurationProtocolOption(v2.ContIDPCSCFIPv4AddressRequest, nil),
+			),
+			[]byte{
+				0xa3, 0x00, 0x07, 0x00,
+				// ConfigurationProtocol
+				0x80,
+				// DNS server request
+				0x00, 0x0d, 0x00,
+				// P-CSCF request
+				0x00, 0x0c, 0x00,
+			},
+		}, {
+			"TWANIdentifier",
+			ies.NewTWANIdentifier(&ies.TWANIdentifierPayload{
+				SSID:         []byte("go-gtp"),
+				BSSID:        net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				MCC:          "123",
+				MNC:          "45",
+				OperatorName: []byte("example"),
+			}),
+			[]byte{
+				0xa9, 0x00, 0x19, 0x00,
+				// Flags: OPNAI, PLMNI, BSSIDI
+				0x0d,
+				// SSID
+				0x06, 0x67, 0x6f, 0x2d, 0x67, 0x74, 0x70,
+				// BSSID
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				// PLMN ID
+				0x21, 0xf3, 0x54,
+				// Operator Name
+				0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+			},
 		}, {
 			"ULITimestamp",
 			ies.NewULITimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
 			[]byte{0xaa, 0x00, 0x04, 0x00, 0xdf, 0xd5, 0x2c, 0x00},
+		}, {
+			"TWANIdentifierTimestamp",
+			ies.NewTWANIdentifierTimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			[]byte{0xb3, 0x00, 0x04, 0x00, 0xdf, 0xd5, 0x2c, 0x00},
 		}, {
 			"MBMSFlags",
 			ies.NewMBMSFlags(1, 1),
 			[]byte{0xab, 0x00, 0x01, 0x00, 0x03},
+		}, {
+			"SecondaryRATUsageDataReport",
+			ies.NewSecondaryRATUsageDataReport(&ies.SecondaryRATUsageDataReportPayload{
+				IRPGW:     true,
+				RATType:   v2.SecondaryRATTypeNR,
+				EBI:       5,
+				StartTime: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2019, time.January, 1, 0, 1, 0, 0, time.UTC),
+				UsageDL:   1000,
+				UsageUL:   500,
+			}),
+			[]byte{
+				0xc9, 0x00, 0x1b, 0x00,
+				// Flags: IRPGW, Secondary RAT Type, EBI
+				0x01, 0x00, 0x05,
+				// Start and End Timestamp
+				0xdf, 0xd5, 0x2c, 0x00, 0xdf, 0xd5, 0x2c, 0x3c,
+				// Usage Data DL and UL
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0xf4,
+			},
 		}, {
 			"PrivateExtension",
 			ies.NewPrivateExtension(10415, []byte{0xde, 0xad, 0xbe, 0xef}),
@@ -430,3 +541,410 @@ func TestIEs(t *testing.T) {
 		})
 	}
 }
+
+func TestChildren(t *testing.T) {
+	// BearerContext with EBI and a truncated Charging ID.
+	malformed := []byte{0x5d, 0x00, 0x0b, 0x00, 0x49, 0x00, 0x01, 0x00, 0x05, 0x5e, 0x00, 0x04, 0x00, 0x12, 0x34}
+
+	t.Run("eager", func(t *testing.T) {
+		if _, err := ies.Decode(malformed); err == nil {
+			t.Error("malformed child IEs should fail Decode")
+		}
+
+		structured := ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(0x12345678))
+		b, err := structured.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ie, err := ies.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ie.ChildIEs) != 2 {
+			t.Errorf("ChildIEs are not decoded: %v", ie.ChildIEs)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		ies.SetLazyDecoding(true)
+		defer ies.SetLazyDecoding(false)
+
+		ie, err := ies.Decode(malformed)
+		if err != nil {
+			t.Fatalf("malformed child IEs should not fail Decode: %s", err)
+		}
+
+		// the first call decodes the child IEs, which can be made concurrently.
+		var wg sync.WaitGroup
+		for k := 0; k < 4; k++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := ie.Children(); err == nil {
+					t.Error("malformed child IEs should fail Children")
+				}
+			}()
+		}
+		wg.Wait()
+		if got := ie.BearerContext(); got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+
+		serialized, err := ie.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(serialized, malformed); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("modified-lazy", func(t *testing.T) {
+		ies.SetLazyDecoding(true)
+		defer ies.SetLazyDecoding(false)
+
+		structured := ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(0x12345678))
+		b, err := structured.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ie, err := ies.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ie.Remove(ies.ChargingID, 0)
+		ie.Add(ies.NewChargingID(0x87654321))
+
+		want, err := ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(0x87654321)).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ie.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Error(diff)
+		}
+
+		ebi, err := ie.FindByType(ies.EPSBearerID, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ebi.EPSBearerID(); got != 5 {
+			t.Errorf("got %d, want 5", got)
+		}
+	})
+}
+
+func TestTWANIdentifier(t *testing.T) {
+	want := &ies.TWANIdentifierPayload{
+		SSID:              []byte("go-gtp"),
+		BSSID:             net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		CivicAddress:      []byte{0x4a, 0x50, 0x00},
+		MCC:               "123",
+		MNC:               "456",
+		OperatorName:      []byte("example"),
+		RelayIdentityType: 1,
+		RelayIdentity:     []byte("relay-1"),
+		CircuitID:         []byte{0x01, 0x02},
+	}
+
+	got := ies.NewTWANIdentifier(want).TWANIdentifier()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if got := ies.NewIMSI("123451234567890").TWANIdentifier(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+	// BSSID flag is set, but BSSID is truncated.
+	if got := ies.New(ies.TWANIdentifier, 0, []byte{0x01, 0x00, 0x00, 0x11}).TWANIdentifier(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestUserLocationInformation(t *testing.T) {
+	want := &ies.UserLocationInformationPayload{
+		Flags: ies.ULIFlagTAI | ies.ULIFlagECGI | ies.ULIFlagEMeNBI,
+		MCC:   "123", MNC: "45",
+		TAC: 0x5555, ECI: 0x06666666, EMeNBI: 0x88888,
+	}
+
+	got := ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 0x5555, 0x06666666, -1, 0x88888).UserLocationInformation()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if got := ies.NewIMSI("123451234567890").UserLocationInformation(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+	// TAI flag is set, but TAI is truncated.
+	if got := ies.New(ies.UserLocationInformation, 0, []byte{0x08, 0x21, 0xf3}).UserLocationInformation(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestSelectionMode(t *testing.T) {
+	cases := []struct {
+		payload, want uint8
+	}{
+		{0x00, 0},
+		{0x01, 1},
+		{0xfe, 2},
+		{0x03, 2},
+	}
+	for _, c := range cases {
+		if got := ies.New(ies.SelectionMode, 0, []byte{c.payload}).SelectionMode(); got != c.want {
+			t.Errorf("%#02x: got %d, want %d", c.payload, got, c.want)
+		}
+	}
+}
+
+func TestGUTI(t *testing.T) {
+	want := &ies.GUTIPayload{MCC: "123", MNC: "45", MMEGroupID: 0x1111, MMECode: 0x22, MTMSI: 0xd2345678}
+
+	got := ies.NewGUTI("123", "45", 0x1111, 0x22, 0xd2345678).GUTI()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatal(diff)
+	}
+
+	lac, rac, ptmsi, sig := got.RAI()
+	if lac != 0x1111 || rac != 0x22 || ptmsi != 0xd2225678 || sig != 0x340000 {
+		t.Errorf("got %#x/%#x/%#x/%#x", lac, rac, ptmsi, sig)
+	}
+	if diff := cmp.Diff(ies.NewGUTIFromRAI("123", "45", lac, ptmsi, sig).GUTI(), want); diff != "" {
+		t.Error(diff)
+	}
+
+	if got := ies.NewIMSI("123451234567890").GUTI(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+	if got := ies.New(ies.GUTI, 0, []byte{0x21, 0xf3, 0x54}).GUTI(); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestThrottling(t *testing.T) {
+	cases := []struct {
+		delay, want time.Duration
+	}{
+		{0, 0},
+		{10 * time.Second, 10 * time.Second},
+		{10 * time.Minute, 10 * time.Minute},
+		{90 * time.Minute, 90 * time.Minute},
+		{5 * time.Hour, 5 * time.Hour},
+		{100 * time.Hour, 100 * time.Hour},
+		{1000 * time.Hour, 310 * time.Hour},
+	}
+	for _, c := range cases {
+		ie := ies.NewThrottling(c.delay, 20)
+		if got := ie.ThrottlingDelay(); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.delay, got, c.want)
+		}
+		if got := ie.ThrottlingFactor(); got != 20 {
+			t.Errorf("%s: got factor %d, want 20", c.delay, got)
+		}
+	}
+
+	// deactivated.
+	if got := ies.New(ies.Throttling, 0, []byte{0xff, 0x32}).ThrottlingDelay(); got != 0 {
+		t.Errorf("got %s, want 0", got)
+	}
+	if got := ies.New(ies.Throttling, 0, []byte{0x00, 0xff}).ThrottlingFactor(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+}
+
+func TestEPCTimer(t *testing.T) {
+	cases := []struct {
+		d, want time.Duration
+	}{
+		{0, 0},
+		{10 * time.Second, 10 * time.Second},
+		{90 * time.Minute, 90 * time.Minute},
+		{1000 * time.Hour, 310 * time.Hour},
+		{ies.EPCTimerInfinite, ies.EPCTimerInfinite},
+	}
+	for _, c := range cases {
+		if got := ies.NewEPCTimer(c.d).EPCTimer(); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.d, got, c.want)
+		}
+	}
+
+	// the unit not defined is interpreted as 1 minute.
+	if got := ies.New(ies.EPCTimer, 0, []byte{0xa3}).EPCTimer(); got != 3*time.Minute {
+		t.Errorf("got %s, want %s", got, 3*time.Minute)
+	}
+}
+
+func TestIMEISV(t *testing.T) {
+	cases := []struct {
+		description string
+		ie          *ies.IE
+		imei, svn   string
+		isIMEISV    bool
+	}{
+		{"IMEI", ies.NewMobileEquipmentIdentity("352099001761481"), "352099001761481", "", false},
+		{"IMEISV", ies.NewMobileEquipmentIdentity("3520990017614823"), "352099001761481", "23", true},
+		{"NewIMEISV", ies.NewIMEISV("352099001761481", "23"), "352099001761481", "23", true},
+		{"NewIMEISV/14digits", ies.NewIMEISV("35209900176148", "07"), "352099001761481", "07", true},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if got := c.ie.IMEI(); got != c.imei {
+				t.Errorf("got IMEI %s, want %s", got, c.imei)
+			}
+			if got := c.ie.SoftwareVersion(); got != c.svn {
+				t.Errorf("got SVN %s, want %s", got, c.svn)
+			}
+			if got := c.ie.IsIMEISV(); got != c.isIMEISV {
+				t.Errorf("got IsIMEISV %v, want %v", got, c.isIMEISV)
+			}
+
+			imei, svn, err := c.ie.ParseIMEI()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if imei != c.imei || svn != c.svn {
+				t.Errorf("got %s, %s, want %s, %s", imei, svn, c.imei, c.svn)
+			}
+		})
+	}
+
+	if _, _, err := ies.New(ies.MobileEquipmentIdentity, 0, []byte{0x21, 0x43}).ParseIMEI(); !errors.Is(err, ies.ErrMalformed) {
+		t.Errorf("got %v, want %v", err, ies.ErrMalformed)
+	}
+}
+
+func TestParseGetters(t *testing.T) {
+	cases := []struct {
+		description string
+		parse       func() (interface{}, error)
+		want        interface{}
+		wantErr     error
+	}{
+		{
+			"IMSI",
+			func() (interface{}, error) { return ies.NewIMSI("123451234567890").ParseIMSI() },
+			"123451234567890", nil,
+		}, {
+			"IMSI/even",
+			func() (interface{}, error) { return ies.NewIMSI("12345123456789").ParseIMSI() },
+			"12345123456789", nil,
+		}, {
+			"IMSI/non-digit",
+			func() (interface{}, error) { return ies.New(ies.IMSI, 0, []byte{0x21, 0xa3, 0xf4}).ParseIMSI() },
+			"123a4", ies.ErrMalformed,
+		}, {
+			"IMSI/empty",
+			func() (interface{}, error) { return ies.New(ies.IMSI, 0, nil).ParseIMSI() },
+			"", ies.ErrTooShortToDecode,
+		}, {
+			"IMSI/wrong-type",
+			func() (interface{}, error) { return ies.NewMSISDN("819012345678").ParseIMSI() },
+			"", ies.ErrInvalidType,
+		}, {
+			"MSISDN",
+			func() (interface{}, error) { return ies.NewMSISDN("819012345678").ParseMSISDN() },
+			"819012345678", nil,
+		}, {
+			"MobileEquipmentIdentity",
+			func() (interface{}, error) {
+				return ies.NewMobileEquipmentIdentity("123450123456789").ParseMobileEquipmentIdentity()
+			},
+			"123450123456789", nil,
+		}, {
+			"TEID",
+			func() (interface{}, error) {
+				return ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "").ParseTEID()
+			},
+			uint32(0xffffffff), nil,
+		}, {
+			"TEID/short",
+			func() (interface{}, error) { return ies.New(ies.FullyQualifiedTEID, 0, []byte{0x8a, 0x11}).ParseTEID() },
+			uint32(0), ies.ErrTooShortToDecode,
+		}, {
+			"TEID/S1UDataForwarding-bad-address-length",
+			func() (interface{}, error) {
+				return ies.New(ies.S1UDataForwarding, 0, []byte{0x05, 1, 1, 1, 1, 1, 0, 0, 0, 1}).ParseTEID()
+			},
+			uint32(0), ies.ErrMalformed,
+		}, {
+			"InterfaceType",
+			func() (interface{}, error) {
+				return ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "").ParseInterfaceType()
+			},
+			v2.IFTypeS11MMEGTPC, nil,
+		}, {
+			"IPAddress/F-TEID",
+			func() (interface{}, error) {
+				return ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "").ParseIPAddress()
+			},
+			"1.1.1.1", nil,
+		}, {
+			"IPAddress/F-TEID-truncated",
+			func() (interface{}, error) {
+				return ies.New(ies.FullyQualifiedTEID, 0, []byte{0x8a, 0, 0, 0, 1, 1, 1}).ParseIPAddress()
+			},
+			"", ies.ErrTooShortToDecode,
+		}, {
+			"IPAddress/F-TEID-no-address",
+			func() (interface{}, error) {
+				return ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 1, "", "").ParseIPAddress()
+			},
+			"", ies.ErrMalformed,
+		}, {
+			"IPAddress/PAA-unknown-PDN-type",
+			func() (interface{}, error) {
+				return ies.New(ies.PDNAddressAllocation, 0, []byte{0x07, 1, 1, 1, 1}).ParseIPAddress()
+			},
+			"", ies.ErrMalformed,
+		}, {
+			"EPSBearerID",
+			func() (interface{}, error) { return ies.NewEPSBearerID(5).ParseEPSBearerID() },
+			uint8(5), nil,
+		}, {
+			"Recovery/empty",
+			func() (interface{}, error) { return ies.New(ies.Recovery, 0, nil).ParseRecovery() },
+			uint8(0), ies.ErrTooShortToDecode,
+		}, {
+			"Cause",
+			func() (interface{}, error) { return ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil).ParseCause() },
+			v2.CauseRequestAccepted, nil,
+		}, {
+			"Cause/remote",
+			func() (interface{}, error) {
+				i := ies.NewCause(v2.CauseContextNotFound, 0, 0, 1, nil)
+				return []bool{i.IsRemoteCause(), i.IsBearerContextIEError(), i.IsPDNConnectionIEError()}, nil
+			},
+			[]bool{true, false, false}, nil,
+		}, {
+			"Cause/PDN-connection-IE-error",
+			func() (interface{}, error) {
+				i := ies.NewCause(v2.CauseMandatoryIEIncorrect, 1, 0, 0, nil)
+				return []bool{i.IsRemoteCause(), i.IsBearerContextIEError(), i.IsPDNConnectionIEError()}, nil
+			},
+			[]bool{false, false, true}, nil,
+		}, {
+			"AccessPointName/overrun",
+			func() (interface{}, error) {
+				return ies.New(ies.AccessPointName, 0, []byte{0x04, 's', 'o', 'm', 'e', 0x07, 'a', 'p'}).ParseAccessPointName()
+			},
+			"some", ies.ErrMalformed,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := c.parse()
+			if !errors.Is(err, c.wantErr) {
+				t.Errorf("got error %v, want %v", err, c.wantErr)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
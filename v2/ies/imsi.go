@@ -5,11 +5,18 @@
 package ies
 
 import (
+	"strings"
+
 	"github.com/wmnsk/go-gtp/utils"
 )
 
 // NewIMSI creates a new IMSI IE.
+//
+// imsi is validated with utils.CheckIMSI, and nil is returned if it fails.
 func NewIMSI(imsi string) *IE {
+	if err := utils.CheckIMSI(imsi); err != nil {
+		return nil
+	}
 	i, err := utils.StrToSwappedBytes(imsi, "f")
 	if err != nil {
 		return nil
@@ -19,10 +26,40 @@ func NewIMSI(imsi string) *IE {
 }
 
 // IMSI returns IMSI in string if the type of IE matches.
+//
+// The malformed payload is decoded as it is, use ParseIMSI to detect it.
 func (i *IE) IMSI() string {
 	if i.Type != IMSI {
 		return ""
 	}
 
-	return utils.SwappedBytesToStr(i.Payload, true)
+	imsi, _ := decodeTBCD(i.Payload)
+	return imsi
+}
+
+// ParseIMSI returns IMSI in string, or the error if the type of IE does not match
+// or the payload contains the values other than digits.
+func (i *IE) ParseIMSI() (string, error) {
+	if i.Type != IMSI {
+		return "", ErrInvalidType
+	}
+
+	return decodeTBCD(i.Payload)
+}
+
+// decodeTBCD decodes the digits in TBCD format, removing the filler in the last octet
+// if present. The digits are returned together with ErrMalformed if b contains the
+// values other than digits, so that the lenient getters can still use them.
+func decodeTBCD(b []byte) (string, error) {
+	if len(b) == 0 {
+		return "", ErrTooShortToDecode
+	}
+
+	s := strings.TrimSuffix(utils.SwappedBytesToStr(b, false), "f")
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return s, ErrMalformed
+		}
+	}
+	return s, nil
 }
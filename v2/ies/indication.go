@@ -129,3 +129,17 @@ func NewIndicationFromOctets(octs ...uint8) *IE {
 	ie.SetLength()
 	return ie
 }
+
+// OperationIndication reports whether the OI flag is set in Indication IE.
+//
+// On S11, the OI flag tells the S-GW to forward the Delete Session Request to the P-GW,
+// which is not set when the MME deletes the session on the old S-GW on relocation.
+func (i *IE) OperationIndication() bool {
+	if i.Type != Indication {
+		return false
+	}
+	if len(i.Payload) < 1 {
+		return false
+	}
+	return i.Payload[0]&0x08 != 0
+}
@@ -23,36 +23,58 @@ func NewIPAddress(addr string) *IE {
 
 // IPAddress returns IPAddress value if the type of IE matches.
 func (i *IE) IPAddress() string {
+	addr, _ := i.ParseIPAddress()
+	return addr
+}
+
+// ParseIPAddress returns IPAddress value, or the error if the type of IE does not
+// match or the payload is malformed, e.g., the address is too short for the PDN Type.
+func (i *IE) ParseIPAddress() (string, error) {
 	switch i.Type {
 	case IPAddress:
-		return net.IP(i.Payload).String()
+		if len(i.Payload) == 0 {
+			return "", ErrTooShortToDecode
+		}
+		return net.IP(i.Payload).String(), nil
 	case PDNAddressAllocation:
 		switch i.PDNType() {
 		case 0x01:
-			return net.IP(i.Payload[1:]).String()
+			return ipAddressAt(i.Payload, 1, 4)
 		case 0x02:
-			return net.IP(i.Payload[2:]).String()
+			return ipAddressAt(i.Payload, 2, 16)
+		case 0x03:
+			// IPv4 address follows the IPv6 prefix.
+			return ipAddressAt(i.Payload, 18, 4)
 		default:
-			return ""
+			return "", ErrMalformed
 		}
 	case S103PDNDataForwardingInfo, S1UDataForwarding:
+		if len(i.Payload) < 1 {
+			return "", ErrTooShortToDecode
+		}
 		switch i.Payload[0] {
-		case 4:
-			return net.IP(i.Payload[1:5]).String()
-		case 16:
-			return net.IP(i.Payload[1:17]).String()
+		case 4, 16:
+			return ipAddressAt(i.Payload, 1, int(i.Payload[0]))
 		default:
-			return ""
+			return "", ErrMalformed
 		}
 	case FullyQualifiedTEID:
 		if i.HasIPv4() {
-			return net.IP(i.Payload[5:9]).String()
+			return ipAddressAt(i.Payload, 5, 4)
 		} else if i.HasIPv6() {
-			return net.IP(i.Payload[5:21]).String()
+			return ipAddressAt(i.Payload, 5, 16)
 		} else {
-			return ""
+			return "", ErrMalformed
 		}
 	default:
-		return ""
+		return "", ErrInvalidType
+	}
+}
+
+// ipAddressAt returns the IP address of length l at offset in b in string.
+func ipAddressAt(b []byte, offset, l int) (string, error) {
+	if len(b) < offset+l {
+		return "", ErrTooShortToDecode
 	}
+	return net.IP(b[offset : offset+l]).String(), nil
 }
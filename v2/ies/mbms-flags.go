@@ -16,6 +16,9 @@ func (i *IE) MBMSFlags() uint8 {
 	if i.Type != MBMSFlags {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 
 	return i.Payload[0]
 }
@@ -25,7 +28,10 @@ func (i *IE) MBMSFlags() uint8 {
 func (i *IE) LocalMBMSBearerContextRelease() bool {
 	switch i.Type {
 	case MBMSFlags:
-		return i.Payload[0]&0x02 == 1
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return i.Payload[0]&0x02 != 0
 	default:
 		return false
 	}
@@ -36,6 +42,9 @@ func (i *IE) LocalMBMSBearerContextRelease() bool {
 func (i *IE) MBMSSessionReEstablishment() bool {
 	switch i.Type {
 	case MBMSFlags:
+		if len(i.Payload) < 1 {
+			return false
+		}
 		return i.Payload[0]&0x01 == 1
 	default:
 		return false
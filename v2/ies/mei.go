@@ -7,7 +7,12 @@ package ies
 import "github.com/wmnsk/go-gtp/utils"
 
 // NewMobileEquipmentIdentity creates a new MobileEquipmentIdentity IE.
+//
+// mei is validated with utils.CheckMEI, and nil is returned if it fails.
 func NewMobileEquipmentIdentity(mei string) *IE {
+	if err := utils.CheckMEI(mei); err != nil {
+		return nil
+	}
 	m, err := utils.StrToSwappedBytes(mei, "f")
 	if err != nil {
 		return nil
@@ -17,6 +22,92 @@ func NewMobileEquipmentIdentity(mei string) *IE {
 
 // MobileEquipmentIdentity returns MobileEquipmentIdentity in string if the
 // type of IE matches.
+//
+// The malformed payload is decoded as it is, use ParseMobileEquipmentIdentity
+// to detect it.
 func (i *IE) MobileEquipmentIdentity() string {
-	return utils.SwappedBytesToStr(i.Payload, true)
+	if i.Type != MobileEquipmentIdentity {
+		return ""
+	}
+
+	mei, _ := decodeTBCD(i.Payload)
+	return mei
+}
+
+// ParseMobileEquipmentIdentity returns MobileEquipmentIdentity in string, or the
+// error if the type of IE does not match or the payload contains the values other
+// than digits.
+func (i *IE) ParseMobileEquipmentIdentity() (string, error) {
+	if i.Type != MobileEquipmentIdentity {
+		return "", ErrInvalidType
+	}
+
+	return decodeTBCD(i.Payload)
+}
+
+// NewIMEISV creates a new MobileEquipmentIdentity IE with the IMEISV built from imei and
+// the 2-digit Software Version Number svn. imei can be the 15-digit IMEI, whose check
+// digit is replaced with svn, or the 14 digits without it.
+//
+// The IMEISV is validated with utils.CheckMEI, and nil is returned if it fails.
+func NewIMEISV(imei, svn string) *IE {
+	if len(imei) == 15 {
+		imei = imei[:14]
+	}
+	return NewMobileEquipmentIdentity(imei + svn)
+}
+
+// IMEI returns the 15-digit IMEI in MobileEquipmentIdentity if the type of IE matches.
+//
+// The IMEI is built from the IMEISV with the check digit computed in place of the
+// Software Version Number, which is retrieved with SoftwareVersion. The others are
+// returned as MobileEquipmentIdentity does.
+func (i *IE) IMEI() string {
+	mei := i.MobileEquipmentIdentity()
+	if len(mei) != 16 {
+		return mei
+	}
+	cd, err := utils.LuhnCheckDigit(mei[:14])
+	if err != nil {
+		return mei
+	}
+	return mei[:14] + string(cd)
+}
+
+// SoftwareVersion returns the 2-digit Software Version Number in MobileEquipmentIdentity
+// if the type of IE matches and it is the IMEISV, or empty string otherwise.
+func (i *IE) SoftwareVersion() string {
+	if mei := i.MobileEquipmentIdentity(); len(mei) == 16 {
+		return mei[14:]
+	}
+	return ""
+}
+
+// IsIMEISV reports whether the MobileEquipmentIdentity is the IMEISV, which has 16 digits
+// with the Software Version Number, instead of the IMEI.
+func (i *IE) IsIMEISV() bool {
+	return len(i.MobileEquipmentIdentity()) == 16
+}
+
+// ParseIMEI returns the 15-digit IMEI and the Software Version Number in
+// MobileEquipmentIdentity, or the error if the type of IE does not match or the payload
+// is neither the IMEI nor the IMEISV. svn is empty if it is the IMEI.
+func (i *IE) ParseIMEI() (imei, svn string, err error) {
+	mei, err := i.ParseMobileEquipmentIdentity()
+	if err != nil {
+		return "", "", err
+	}
+
+	switch len(mei) {
+	case 15:
+		return mei, "", nil
+	case 16:
+		cd, err := utils.LuhnCheckDigit(mei[:14])
+		if err != nil {
+			return "", "", err
+		}
+		return mei[:14] + string(cd), mei[14:], nil
+	default:
+		return "", "", ErrMalformed
+	}
 }
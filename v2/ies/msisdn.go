@@ -17,6 +17,23 @@ func NewMSISDN(mei string) *IE {
 
 // MSISDN returns MSISDN in string if the
 // type of IE matches.
+//
+// The malformed payload is decoded as it is, use ParseMSISDN to detect it.
 func (i *IE) MSISDN() string {
-	return utils.SwappedBytesToStr(i.Payload, true)
+	if i.Type != MSISDN {
+		return ""
+	}
+
+	msisdn, _ := decodeTBCD(i.Payload)
+	return msisdn
+}
+
+// ParseMSISDN returns MSISDN in string, or the error if the type of IE does not
+// match or the payload contains the values other than digits.
+func (i *IE) ParseMSISDN() (string, error) {
+	if i.Type != MSISDN {
+		return "", ErrInvalidType
+	}
+
+	return decodeTBCD(i.Payload)
 }
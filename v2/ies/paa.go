@@ -45,3 +45,39 @@ func NewPDNAddressAllocation(addr string) *IE {
 	// Non-IP
 	return New(PDNAddressAllocation, 0x00, []byte{pdnTypeNonIP})
 }
+
+// NewPDNAddressAllocationIPv6 creates a new PDNAddressAllocation IE with the IPv6
+// prefix delegated to the UE in CIDR notation, e.g., "2001:db8:1:2::/64".
+//
+// It returns nil if prefix is not a valid IPv6 prefix.
+func NewPDNAddressAllocationIPv6(prefix string) *IE {
+	ip, n, err := net.ParseCIDR(prefix)
+	if err != nil || ip.To4() != nil {
+		return nil
+	}
+	ones, _ := n.Mask.Size()
+
+	i := New(PDNAddressAllocation, 0x00, make([]byte, 18))
+	i.Payload[0] = pdnTypeIPv6
+	i.Payload[1] = uint8(ones)
+	copy(i.Payload[2:], n.IP)
+	return i
+}
+
+// NewPDNAddressAllocationIPv4v6 creates a new PDNAddressAllocation IE with the IPv4
+// address and the IPv6 prefix in CIDR notation assigned to the UE.
+//
+// It returns nil if either of them is not valid.
+func NewPDNAddressAllocationIPv4v6(v4addr, v6prefix string) *IE {
+	v4 := net.ParseIP(v4addr).To4()
+	v6 := NewPDNAddressAllocationIPv6(v6prefix)
+	if v4 == nil || v6 == nil {
+		return nil
+	}
+
+	i := New(PDNAddressAllocation, 0x00, make([]byte, 22))
+	i.Payload[0] = pdnTypeIPv4v6
+	copy(i.Payload[1:18], v6.Payload[1:])
+	copy(i.Payload[18:], v4)
+	return i
+}
@@ -13,6 +13,9 @@ func NewPDNType(pdn uint8) *IE {
 func (i *IE) PDNType() uint8 {
 	switch i.Type {
 	case PDNType, PDNAddressAllocation:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return i.Payload[0]
 	default:
 		return 0
@@ -15,6 +15,9 @@ func NewPortNumber(port uint16) *IE {
 func (i *IE) PortNumber() uint16 {
 	switch i.Type {
 	case PortNumber:
+		if len(i.Payload) < 2 {
+			return 0
+		}
 		return binary.BigEndian.Uint16(i.Payload[0:2])
 	default:
 		return 0
@@ -19,6 +19,9 @@ func (i *IE) EnterpriseID() uint16 {
 	if i.Type != PrivateExtension {
 		return 0
 	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
 	return binary.BigEndian.Uint16(i.Payload[0:2])
 
 }
@@ -28,5 +31,8 @@ func (i *IE) PrivateExtension() []byte {
 	if i.Type != PrivateExtension {
 		return nil
 	}
+	if len(i.Payload) < 2 {
+		return nil
+	}
 	return i.Payload[2:]
 }
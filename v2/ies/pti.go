@@ -14,6 +14,9 @@ func (i *IE) ProcedureTransactionID() uint8 {
 	if i.Type != ProcedureTransactionID {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 
 	return i.Payload[0]
 }
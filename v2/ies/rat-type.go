@@ -13,6 +13,9 @@ func NewRATType(rat uint8) *IE {
 func (i *IE) RATType() uint8 {
 	switch i.Type {
 	case RATType:
+		if len(i.Payload) < 1 {
+			return 0
+		}
 		return i.Payload[0]
 	default:
 		return 0
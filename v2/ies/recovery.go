@@ -11,8 +11,18 @@ func NewRecovery(recovery uint8) *IE {
 
 // Recovery returns Recovery value if the type of IE matches.
 func (i *IE) Recovery() uint8 {
+	recovery, _ := i.ParseRecovery()
+	return recovery
+}
+
+// ParseRecovery returns Recovery value, or the error if the type of IE does not
+// match or the payload is too short.
+func (i *IE) ParseRecovery() (uint8, error) {
 	if i.Type != Recovery {
-		return 0
+		return 0, ErrInvalidType
+	}
+	if len(i.Payload) < 1 {
+		return 0, ErrTooShortToDecode
 	}
-	return i.Payload[0]
+	return i.Payload[0], nil
 }
@@ -55,18 +55,30 @@ func (i *IE) EBIs() []uint8 {
 	if i.Type != S103PDNDataForwardingInfo {
 		return nil
 	}
+	if len(i.Payload) < 1 {
+		return nil
+	}
 
 	var n, offset int
 	switch i.Payload[0] {
 	case 4:
+		if len(i.Payload) < 10 {
+			return nil
+		}
 		n = int(i.Payload[9])
 		offset = 10
 	case 16:
+		if len(i.Payload) < 22 {
+			return nil
+		}
 		n = int(i.Payload[21])
 		offset = 22
 	default:
 		return nil
 	}
+	if len(i.Payload) < offset+n {
+		return nil
+	}
 
 	var ebis []uint8
 	for x := 0; x < n; x++ {
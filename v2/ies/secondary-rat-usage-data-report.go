@@ -0,0 +1,122 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// SecondaryRATUsageDataReportPayload is a Payload of SecondaryRATUsageDataReport IE.
+//
+// IRPGW and IRSGW tell whether the report is intended for P-GW and S-GW respectively.
+// The usage data is the volume in bytes in the period between StartTime and EndTime.
+type SecondaryRATUsageDataReportPayload struct {
+	IRPGW, IRSGW       bool
+	RATType            uint8
+	EBI                uint8
+	StartTime, EndTime time.Time
+	UsageDL, UsageUL   uint64
+}
+
+// Serialize serializes SecondaryRATUsageDataReportPayload.
+func (p *SecondaryRATUsageDataReportPayload) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SecondaryRATUsageDataReportPayload.
+func (p *SecondaryRATUsageDataReportPayload) SerializeTo(b []byte) error {
+	if len(b) < p.Len() {
+		return ErrTooShortToDecode
+	}
+
+	b[0] = 0
+	if p.IRPGW {
+		b[0] |= 0x01
+	}
+	if p.IRSGW {
+		b[0] |= 0x02
+	}
+	b[1] = p.RATType
+	b[2] = p.EBI & 0x0f
+	binary.BigEndian.PutUint32(b[3:7], toNTPSeconds(p.StartTime))
+	binary.BigEndian.PutUint32(b[7:11], toNTPSeconds(p.EndTime))
+	binary.BigEndian.PutUint64(b[11:19], p.UsageDL)
+	binary.BigEndian.PutUint64(b[19:27], p.UsageUL)
+
+	return nil
+}
+
+// DecodeSecondaryRATUsageDataReportPayload decodes SecondaryRATUsageDataReportPayload.
+func DecodeSecondaryRATUsageDataReportPayload(b []byte) (*SecondaryRATUsageDataReportPayload, error) {
+	p := &SecondaryRATUsageDataReportPayload{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into SecondaryRATUsageDataReportPayload.
+func (p *SecondaryRATUsageDataReportPayload) DecodeFromBytes(b []byte) error {
+	if len(b) < 27 {
+		return ErrTooShortToDecode
+	}
+
+	p.IRPGW = b[0]&0x01 != 0
+	p.IRSGW = b[0]&0x02 != 0
+	p.RATType = b[1]
+	p.EBI = b[2] & 0x0f
+	p.StartTime = fromNTPSeconds(binary.BigEndian.Uint32(b[3:7]))
+	p.EndTime = fromNTPSeconds(binary.BigEndian.Uint32(b[7:11]))
+	p.UsageDL = binary.BigEndian.Uint64(b[11:19])
+	p.UsageUL = binary.BigEndian.Uint64(b[19:27])
+
+	return nil
+}
+
+// Len returns the actual length of SecondaryRATUsageDataReportPayload in int.
+func (p *SecondaryRATUsageDataReportPayload) Len() int {
+	return 27
+}
+
+// NewSecondaryRATUsageDataReport creates a new SecondaryRATUsageDataReport IE.
+func NewSecondaryRATUsageDataReport(payload *SecondaryRATUsageDataReportPayload) *IE {
+	i := New(SecondaryRATUsageDataReport, 0x00, make([]byte, payload.Len()))
+	if err := payload.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// SecondaryRATUsageDataReport returns SecondaryRATUsageDataReport in
+// SecondaryRATUsageDataReportPayload type if the type of IE matches.
+func (i *IE) SecondaryRATUsageDataReport() *SecondaryRATUsageDataReportPayload {
+	if i.Type != SecondaryRATUsageDataReport {
+		return nil
+	}
+
+	p, err := DecodeSecondaryRATUsageDataReportPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// toNTPSeconds returns the seconds part of the 64-bit NTP timestamp of t.
+func toNTPSeconds(t time.Time) uint32 {
+	return uint32(t.Unix() + 2208988800)
+}
+
+// fromNTPSeconds returns the time of the seconds part of the 64-bit NTP timestamp.
+func fromNTPSeconds(s uint32) time.Time {
+	return time.Unix(int64(s)-2208988800, 0)
+}
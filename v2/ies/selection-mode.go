@@ -13,9 +13,15 @@ func NewSelectionMode(mode uint8) *IE {
 }
 
 // SelectionMode returns SelectionMode value if the type of IE matches.
+//
+// The spare bits are ignored, and the value 3, which is for future use, is returned
+// as 2 as defined in TS 29.274 8.58.
 func (i *IE) SelectionMode() uint8 {
 	if i.Type != SelectionMode {
 		return 0
 	}
-	return i.Payload[0]
+	if len(i.Payload) < 1 {
+		return 0
+	}
+	return min(i.Payload[0]&0x03, 2)
 }
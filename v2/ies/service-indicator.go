@@ -14,6 +14,9 @@ func (i *IE) ServiceIndicator() uint8 {
 	if i.Type != ServiceIndicator {
 		return 0
 	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
 
 	return i.Payload[0]
 }
@@ -42,6 +42,9 @@ func (i *IE) MCC() string {
 		}
 		return mcc
 	case GlobalCNID, TraceReference, GUTI, UserCSGInformation:
+		if len(i.Payload) < 3 {
+			return ""
+		}
 		mcc, _, err := utils.DecodePLMN(i.Payload[:3])
 		if err != nil {
 			return ""
@@ -62,6 +65,9 @@ func (i *IE) MNC() string {
 		}
 		return mnc
 	case GlobalCNID, TraceReference, GUTI, UserCSGInformation:
+		if len(i.Payload) < 3 {
+			return ""
+		}
 		_, mnc, err := utils.DecodePLMN(i.Payload[:3])
 		if err != nil {
 			return ""
@@ -0,0 +1,25 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewSignallingPriorityIndication creates a new SignallingPriorityIndication IE.
+// lapi should be 1 if the UE is configured for NAS signalling low priority.
+func NewSignallingPriorityIndication(lapi uint8) *IE {
+	return New(SignallingPriorityIndication, 0x00, []byte{lapi & 0x01})
+}
+
+// LowAccessPriorityIndicator reports whether the UE is configured for NAS signalling
+// low priority, i.e., the delay-tolerant devices whose signalling can be deprioritized.
+func (i *IE) LowAccessPriorityIndicator() bool {
+	switch i.Type {
+	case SignallingPriorityIndication:
+		if len(i.Payload) < 1 {
+			return false
+		}
+		return i.Payload[0]&0x01 != 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "time"
+
+// throttlingUnits are the units of Throttling Delay, indexed by the unit value.
+var throttlingUnits = []time.Duration{
+	2 * time.Second, 1 * time.Minute, 10 * time.Minute, 1 * time.Hour, 10 * time.Hour,
+}
+
+// NewThrottling creates a new Throttling IE.
+//
+// The delay is encoded with the smallest unit that can represent it, which is
+// truncated to the unit. The factor is the percentage of the Downlink Data
+// Notifications to be dropped, from 0 to 100.
+func NewThrottling(delay time.Duration, factor uint8) *IE {
+	var unit, value uint8
+	for u, d := range throttlingUnits {
+		unit, value = uint8(u), uint8(min(delay/d, 31))
+		if delay <= 31*d {
+			break
+		}
+	}
+	return New(Throttling, 0x00, []byte{unit<<5 | value, factor})
+}
+
+// ThrottlingDelay returns ThrottlingDelay in time.Duration if the type of IE matches.
+// The delay deactivated is returned as 0.
+func (i *IE) ThrottlingDelay() time.Duration {
+	if i.Type != Throttling {
+		return 0
+	}
+	if len(i.Payload) < 1 {
+		return 0
+	}
+
+	unit, value := int(i.Payload[0]>>5), time.Duration(i.Payload[0]&0x1f)
+	switch {
+	case unit == 7:
+		return 0
+	case unit < len(throttlingUnits):
+		return value * throttlingUnits[unit]
+	default:
+		// the other values shall be interpreted as 1 minute.
+		return value * time.Minute
+	}
+}
+
+// ThrottlingFactor returns ThrottlingFactor in uint8 if the type of IE matches.
+// The values above 100 are returned as 0, as they shall be interpreted so.
+func (i *IE) ThrottlingFactor() uint8 {
+	if i.Type != Throttling {
+		return 0
+	}
+	if len(i.Payload) < 2 {
+		return 0
+	}
+	if i.Payload[1] > 100 {
+		return 0
+	}
+	return i.Payload[1]
+}
@@ -15,6 +15,9 @@ func NewTMSI(tmsi uint32) *IE {
 func (i *IE) TMSI() uint32 {
 	switch i.Type {
 	case TMSI:
+		if len(i.Payload) < 4 {
+			return 0
+		}
 		return binary.BigEndian.Uint32(i.Payload)
 	default:
 		return 0
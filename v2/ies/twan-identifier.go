@@ -0,0 +1,236 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// TWANIdentifierPayload is a Payload of TWANIdentifier IE.
+//
+// The optional fields are included in the IE only when they are not empty, and
+// the corresponding flags are set accordingly.
+type TWANIdentifierPayload struct {
+	SSID         []byte
+	BSSID        net.HardwareAddr
+	CivicAddress []byte
+	// MCC and MNC are the PLMN of the TWAN operator.
+	MCC, MNC     string
+	OperatorName []byte
+	// RelayIdentityType, RelayIdentity and CircuitID are the Logical Access ID.
+	RelayIdentityType uint8
+	RelayIdentity     []byte
+	CircuitID         []byte
+}
+
+// NewTWANIdentifierPayload creates a new TWANIdentifierPayload with SSID and BSSID.
+func NewTWANIdentifierPayload(ssid string, bssid net.HardwareAddr) *TWANIdentifierPayload {
+	return &TWANIdentifierPayload{SSID: []byte(ssid), BSSID: bssid}
+}
+
+func (p *TWANIdentifierPayload) hasBSSID() bool {
+	return len(p.BSSID) != 0
+}
+
+func (p *TWANIdentifierPayload) hasCivicAddress() bool {
+	return len(p.CivicAddress) != 0
+}
+
+func (p *TWANIdentifierPayload) hasPLMN() bool {
+	return p.MCC != "" && p.MNC != ""
+}
+
+func (p *TWANIdentifierPayload) hasOperatorName() bool {
+	return len(p.OperatorName) != 0
+}
+
+func (p *TWANIdentifierPayload) hasLogicalAccessID() bool {
+	return len(p.RelayIdentity) != 0 || len(p.CircuitID) != 0
+}
+
+// Serialize serializes TWANIdentifierPayload.
+func (p *TWANIdentifierPayload) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TWANIdentifierPayload.
+func (p *TWANIdentifierPayload) SerializeTo(b []byte) error {
+	if len(b) < p.Len() {
+		return ErrTooShortToDecode
+	}
+
+	b[0] = 0
+	b[1] = uint8(len(p.SSID))
+	offset := 2 + copy(b[2:], p.SSID)
+
+	if p.hasBSSID() {
+		if len(p.BSSID) != 6 {
+			return ErrInvalidLength
+		}
+		b[0] |= 0x01
+		offset += copy(b[offset:], p.BSSID)
+	}
+	if p.hasCivicAddress() {
+		b[0] |= 0x02
+		b[offset] = uint8(len(p.CivicAddress))
+		offset += 1 + copy(b[offset+1:], p.CivicAddress)
+	}
+	if p.hasPLMN() {
+		plmn, err := utils.EncodePLMN(p.MCC, p.MNC)
+		if err != nil {
+			return err
+		}
+		b[0] |= 0x04
+		offset += copy(b[offset:], plmn)
+	}
+	if p.hasOperatorName() {
+		b[0] |= 0x08
+		b[offset] = uint8(len(p.OperatorName))
+		offset += 1 + copy(b[offset+1:], p.OperatorName)
+	}
+	if p.hasLogicalAccessID() {
+		b[0] |= 0x10
+		b[offset] = p.RelayIdentityType
+		b[offset+1] = uint8(len(p.RelayIdentity))
+		offset += 2 + copy(b[offset+2:], p.RelayIdentity)
+		b[offset] = uint8(len(p.CircuitID))
+		copy(b[offset+1:], p.CircuitID)
+	}
+
+	return nil
+}
+
+// DecodeTWANIdentifierPayload decodes TWANIdentifierPayload.
+func DecodeTWANIdentifierPayload(b []byte) (*TWANIdentifierPayload, error) {
+	p := &TWANIdentifierPayload{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into TWANIdentifierPayload.
+func (p *TWANIdentifierPayload) DecodeFromBytes(b []byte) error {
+	if len(b) < 2 {
+		return ErrTooShortToDecode
+	}
+	flags := b[0]
+
+	// readLV reads the value with the length in the first octet at offset.
+	offset := 1
+	readLV := func() ([]byte, error) {
+		if offset >= len(b) {
+			return nil, ErrTooShortToDecode
+		}
+		l := int(b[offset])
+		if offset+1+l > len(b) {
+			return nil, ErrInvalidLength
+		}
+		v := make([]byte, l)
+		copy(v, b[offset+1:offset+1+l])
+		offset += 1 + l
+		return v, nil
+	}
+
+	var err error
+	if p.SSID, err = readLV(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		if offset+6 > len(b) {
+			return ErrTooShortToDecode
+		}
+		p.BSSID = make(net.HardwareAddr, 6)
+		copy(p.BSSID, b[offset:offset+6])
+		offset += 6
+	}
+	if flags&0x02 != 0 {
+		if p.CivicAddress, err = readLV(); err != nil {
+			return err
+		}
+	}
+	if flags&0x04 != 0 {
+		if offset+3 > len(b) {
+			return ErrTooShortToDecode
+		}
+		if p.MCC, p.MNC, err = utils.DecodePLMN(b[offset : offset+3]); err != nil {
+			return err
+		}
+		offset += 3
+	}
+	if flags&0x08 != 0 {
+		if p.OperatorName, err = readLV(); err != nil {
+			return err
+		}
+	}
+	if flags&0x10 != 0 {
+		if offset >= len(b) {
+			return ErrTooShortToDecode
+		}
+		p.RelayIdentityType = b[offset]
+		offset++
+		if p.RelayIdentity, err = readLV(); err != nil {
+			return err
+		}
+		if p.CircuitID, err = readLV(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TWANIdentifierPayload in int.
+func (p *TWANIdentifierPayload) Len() int {
+	l := 2 + len(p.SSID)
+	if p.hasBSSID() {
+		l += 6
+	}
+	if p.hasCivicAddress() {
+		l += 1 + len(p.CivicAddress)
+	}
+	if p.hasPLMN() {
+		l += 3
+	}
+	if p.hasOperatorName() {
+		l += 1 + len(p.OperatorName)
+	}
+	if p.hasLogicalAccessID() {
+		l += 3 + len(p.RelayIdentity) + len(p.CircuitID)
+	}
+
+	return l
+}
+
+// NewTWANIdentifier creates a new TWANIdentifier IE.
+func NewTWANIdentifier(payload *TWANIdentifierPayload) *IE {
+	i := New(TWANIdentifier, 0x00, make([]byte, payload.Len()))
+	if err := payload.SerializeTo(i.Payload); err != nil {
+		return nil
+	}
+
+	return i
+}
+
+// TWANIdentifier returns TWANIdentifier in TWANIdentifierPayload type if the type of IE matches.
+func (i *IE) TWANIdentifier() *TWANIdentifierPayload {
+	if i.Type != TWANIdentifier {
+		return nil
+	}
+
+	p, err := DecodeTWANIdentifierPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return p
+}
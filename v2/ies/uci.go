@@ -27,8 +27,25 @@ func NewUserCSGInformation(mcc, mnc string, csgID uint32, mode, lcsg, cmi uint8)
 func (i *IE) AccessMode() uint8 {
 	switch i.Type {
 	case UserCSGInformation:
+		if len(i.Payload) < 8 {
+			return 0
+		}
 		return i.Payload[7] >> 6
 	default:
 		return 0
 	}
 }
+
+// LeaveCSG reports whether the UE leaves the CSG cell or the hybrid cell, i.e., LCSG
+// flag in UserCSGInformation is set.
+func (i *IE) LeaveCSG() bool {
+	switch i.Type {
+	case UserCSGInformation:
+		if len(i.Payload) < 8 {
+			return false
+		}
+		return i.Payload[7]&0x02 != 0
+	default:
+		return false
+	}
+}
@@ -107,7 +107,7 @@ func NewUserLocationInformation(
 	}
 	if flags>>4&0x01 == 1 {
 		copy(i.Payload[offset:offset+3], plmn)
-		eci &= 0xfffff
+		eci &= 0x0fffffff
 		binary.BigEndian.PutUint32(i.Payload[offset+3:offset+7], eci)
 		offset += ecgilen
 	}
@@ -157,4 +157,102 @@ func uliPayloadLen(flags uint8) int {
 	return l
 }
 
-// XXX - add getters.
+// UserLocationInformationPayload is the identities in UserLocationInformation IE.
+//
+// Flags has the bits of the identities present in the IE in the same order as the
+// IE, i.e., CGI in the LSB and Extended Macro eNodeB ID in the MSB. MCC and MNC are
+// the ones of the first identity present.
+type UserLocationInformationPayload struct {
+	Flags                  uint8
+	MCC, MNC               string
+	LAC, CI, SAC, RAC, TAC uint16
+	ECI, MeNBI, EMeNBI     uint32
+}
+
+// UserLocationInformation flags.
+const (
+	ULIFlagCGI uint8 = 1 << iota
+	ULIFlagSAI
+	ULIFlagRAI
+	ULIFlagTAI
+	ULIFlagECGI
+	ULIFlagLAI
+	ULIFlagMeNBI
+	ULIFlagEMeNBI
+)
+
+// Has reports whether the identity of the flag is present.
+func (p *UserLocationInformationPayload) Has(flag uint8) bool {
+	return p.Flags&flag != 0
+}
+
+// DecodeUserLocationInformationPayload decodes the payload of UserLocationInformation IE.
+func DecodeUserLocationInformationPayload(b []byte) (*UserLocationInformationPayload, error) {
+	if len(b) < 1 {
+		return nil, ErrTooShortToDecode
+	}
+	p := &UserLocationInformationPayload{Flags: b[0]}
+	if len(b) < uliPayloadLen(p.Flags) {
+		return nil, ErrTooShortToDecode
+	}
+
+	offset := 1
+	plmn := func() error {
+		if p.MCC != "" {
+			return nil
+		}
+		var err error
+		p.MCC, p.MNC, err = utils.DecodePLMN(b[offset : offset+3])
+		return err
+	}
+	for flag := ULIFlagCGI; flag != 0; flag <<= 1 {
+		if !p.Has(flag) {
+			continue
+		}
+		if err := plmn(); err != nil {
+			return nil, err
+		}
+
+		id := b[offset+3:]
+		switch flag {
+		case ULIFlagCGI:
+			p.LAC, p.CI = binary.BigEndian.Uint16(id[0:2]), binary.BigEndian.Uint16(id[2:4])
+			offset += cgilen
+		case ULIFlagSAI:
+			p.LAC, p.SAC = binary.BigEndian.Uint16(id[0:2]), binary.BigEndian.Uint16(id[2:4])
+			offset += sailen
+		case ULIFlagRAI:
+			p.LAC, p.RAC = binary.BigEndian.Uint16(id[0:2]), binary.BigEndian.Uint16(id[2:4])
+			offset += railen
+		case ULIFlagTAI:
+			p.TAC = binary.BigEndian.Uint16(id[0:2])
+			offset += tailen
+		case ULIFlagECGI:
+			p.ECI = binary.BigEndian.Uint32(id[0:4]) & 0x0fffffff
+			offset += ecgilen
+		case ULIFlagLAI:
+			p.LAC = binary.BigEndian.Uint16(id[0:2])
+			offset += lailen
+		case ULIFlagMeNBI:
+			p.MeNBI = utils.Uint24To32(id[0:3])
+			offset += menbilen
+		case ULIFlagEMeNBI:
+			p.EMeNBI = utils.Uint24To32(id[0:3])
+			offset += emenbilen
+		}
+	}
+	return p, nil
+}
+
+// UserLocationInformation returns the identities in UserLocationInformation IE, or nil
+// if the type is not UserLocationInformation or the payload is malformed.
+func (i *IE) UserLocationInformation() *UserLocationInformationPayload {
+	if i.Type != UserLocationInformation {
+		return nil
+	}
+	p, err := DecodeUserLocationInformationPayload(i.Payload)
+	if err != nil {
+		return nil
+	}
+	return p
+}
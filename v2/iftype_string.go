@@ -0,0 +1,135 @@
+// Code generated by "genconst -file constants.go -type IFType -block \"InterfaceType definitions.\" -trim IFType"; DO NOT EDIT.
+
+package v2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var namesIFType = map[IFType]string{
+	IFType(IFTypeS1UeNodeBGTPU):   "S1UeNodeBGTPU",
+	IFType(IFTypeS1USGWGTPU):      "S1USGWGTPU",
+	IFType(IFTypeS12RNCGTPU):      "S12RNCGTPU",
+	IFType(IFTypeS12SGWGTPU):      "S12SGWGTPU",
+	IFType(IFTypeS5S8SGWGTPU):     "S5S8SGWGTPU",
+	IFType(IFTypeS5S8PGWGTPU):     "S5S8PGWGTPU",
+	IFType(IFTypeS5S8SGWGTPC):     "S5S8SGWGTPC",
+	IFType(IFTypeS5S8PGWGTPC):     "S5S8PGWGTPC",
+	IFType(IFTypeS5S8SGWPMIPv6):   "S5S8SGWPMIPv6",
+	IFType(IFTypeS5S8PGWPMIPv6):   "S5S8PGWPMIPv6",
+	IFType(IFTypeS11MMEGTPC):      "S11MMEGTPC",
+	IFType(IFTypeS11S4SGWGTPC):    "S11S4SGWGTPC",
+	IFType(IFTypeS10MMEGTPC):      "S10MMEGTPC",
+	IFType(IFTypeS3MMEGTPC):       "S3MMEGTPC",
+	IFType(IFTypeS3SGSNGTPC):      "S3SGSNGTPC",
+	IFType(IFTypeS4SGSNGTPU):      "S4SGSNGTPU",
+	IFType(IFTypeS4SGWGTPU):       "S4SGWGTPU",
+	IFType(IFTypeS4SGSNGTPC):      "S4SGSNGTPC",
+	IFType(IFTypeS16SGSNGTPC):     "S16SGSNGTPC",
+	IFType(IFTypeeNodeBGTPUForDL): "eNodeBGTPUForDL",
+	IFType(IFTypeeNodeBGTPUForUL): "eNodeBGTPUForUL",
+	IFType(IFTypeRNCGTPUForData):  "RNCGTPUForData",
+	IFType(IFTypeSGSNGTPUForData): "SGSNGTPUForData",
+	IFType(IFTypeSGWUPFGTPUForDL): "SGWUPFGTPUForDL",
+	IFType(IFTypeSmMBMSGWGTPC):    "SmMBMSGWGTPC",
+	IFType(IFTypeSnMBMSGWGTPC):    "SnMBMSGWGTPC",
+	IFType(IFTypeSmMMEGTPC):       "SmMMEGTPC",
+	IFType(IFTypeSnSGSNGTPC):      "SnSGSNGTPC",
+	IFType(IFTypeSGWGTPUForUL):    "SGWGTPUForUL",
+	IFType(IFTypeSnSGSNGTPU):      "SnSGSNGTPU",
+	IFType(IFTypeS2bePDGGTPC):     "S2bePDGGTPC",
+	IFType(IFTypeS2bUePDGGTPU):    "S2bUePDGGTPU",
+	IFType(IFTypeS2bPGWGTPC):      "S2bPGWGTPC",
+	IFType(IFTypeS2bUPGWGTPU):     "S2bUPGWGTPU",
+	IFType(IFTypeS2aTWANGTPU):     "S2aTWANGTPU",
+	IFType(IFTypeS2aTWANGTPC):     "S2aTWANGTPC",
+	IFType(IFTypeS2aPGWGTPC):      "S2aPGWGTPC",
+	IFType(IFTypeS2aPGWGTPU):      "S2aPGWGTPU",
+	IFType(IFTypeS11MMEGTPU):      "S11MMEGTPU",
+	IFType(IFTypeS11SGWGTPU):      "S11SGWGTPU",
+}
+
+var valuesIFType = map[string]IFType{
+	"RNCGTPUForData":  IFType(IFTypeRNCGTPUForData),
+	"S10MMEGTPC":      IFType(IFTypeS10MMEGTPC),
+	"S11MMEGTPC":      IFType(IFTypeS11MMEGTPC),
+	"S11MMEGTPU":      IFType(IFTypeS11MMEGTPU),
+	"S11S4SGWGTPC":    IFType(IFTypeS11S4SGWGTPC),
+	"S11SGWGTPU":      IFType(IFTypeS11SGWGTPU),
+	"S12RNCGTPU":      IFType(IFTypeS12RNCGTPU),
+	"S12SGWGTPU":      IFType(IFTypeS12SGWGTPU),
+	"S16SGSNGTPC":     IFType(IFTypeS16SGSNGTPC),
+	"S1USGWGTPU":      IFType(IFTypeS1USGWGTPU),
+	"S1UeNodeBGTPU":   IFType(IFTypeS1UeNodeBGTPU),
+	"S2aPGWGTPC":      IFType(IFTypeS2aPGWGTPC),
+	"S2aPGWGTPU":      IFType(IFTypeS2aPGWGTPU),
+	"S2aTWANGTPC":     IFType(IFTypeS2aTWANGTPC),
+	"S2aTWANGTPU":     IFType(IFTypeS2aTWANGTPU),
+	"S2bPGWGTPC":      IFType(IFTypeS2bPGWGTPC),
+	"S2bUPGWGTPU":     IFType(IFTypeS2bUPGWGTPU),
+	"S2bUePDGGTPU":    IFType(IFTypeS2bUePDGGTPU),
+	"S2bePDGGTPC":     IFType(IFTypeS2bePDGGTPC),
+	"S3MMEGTPC":       IFType(IFTypeS3MMEGTPC),
+	"S3SGSNGTPC":      IFType(IFTypeS3SGSNGTPC),
+	"S4SGSNGTPC":      IFType(IFTypeS4SGSNGTPC),
+	"S4SGSNGTPU":      IFType(IFTypeS4SGSNGTPU),
+	"S4SGWGTPU":       IFType(IFTypeS4SGWGTPU),
+	"S5S8PGWGTPC":     IFType(IFTypeS5S8PGWGTPC),
+	"S5S8PGWGTPU":     IFType(IFTypeS5S8PGWGTPU),
+	"S5S8PGWPMIPv6":   IFType(IFTypeS5S8PGWPMIPv6),
+	"S5S8SGWGTPC":     IFType(IFTypeS5S8SGWGTPC),
+	"S5S8SGWGTPU":     IFType(IFTypeS5S8SGWGTPU),
+	"S5S8SGWPMIPv6":   IFType(IFTypeS5S8SGWPMIPv6),
+	"SGSNGTPUForData": IFType(IFTypeSGSNGTPUForData),
+	"SGWGTPUForUL":    IFType(IFTypeSGWGTPUForUL),
+	"SGWUPFGTPUForDL": IFType(IFTypeSGWUPFGTPUForDL),
+	"SmMBMSGWGTPC":    IFType(IFTypeSmMBMSGWGTPC),
+	"SmMMEGTPC":       IFType(IFTypeSmMMEGTPC),
+	"SnMBMSGWGTPC":    IFType(IFTypeSnMBMSGWGTPC),
+	"SnSGSNGTPC":      IFType(IFTypeSnSGSNGTPC),
+	"SnSGSNGTPU":      IFType(IFTypeSnSGSNGTPU),
+	"eNodeBGTPUForDL": IFType(IFTypeeNodeBGTPUForDL),
+	"eNodeBGTPUForUL": IFType(IFTypeeNodeBGTPUForUL),
+}
+
+// String returns the name of the IFType, or "IFType(N)" if the value is unknown.
+func (v IFType) String() string {
+	if name, ok := namesIFType[v]; ok {
+		return name
+	}
+	return "IFType(" + strconv.Itoa(int(v)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler to encode the IFType with its name.
+func (v IFType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler to decode the IFType from its name.
+func (v *IFType) UnmarshalText(b []byte) error {
+	parsed, err := IFTypeFromString(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// IFTypeFromString returns the IFType whose name is s.
+//
+// s can also be in the "IFType(N)" format that String() returns for the unknown values.
+func IFTypeFromString(s string) (IFType, error) {
+	if v, ok := valuesIFType[s]; ok {
+		return v, nil
+	}
+
+	if strings.HasPrefix(s, "IFType(") && strings.HasSuffix(s, ")") {
+		n, err := strconv.ParseUint(s[len("IFType("):len(s)-1], 10, 8)
+		if err == nil {
+			return IFType(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown IFType: %s", s)
+}
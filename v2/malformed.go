@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/sampler"
+)
+
+// MalformedHandlerFunc is a callback to be told the packet that cannot be decoded as
+// a message, with the error and the number of the ones suppressed since the last call.
+type MalformedHandlerFunc func(peer net.Addr, err error, suppressed uint64)
+
+// SetMalformedHandler sets the handler called with the packets received that cannot
+// be decoded, which are discarded silently otherwise, e.g., to log them.
+//
+// The handler is called only for the first burst packets in each interval, and the
+// others are just counted as suppressed, so that a flood of garbage on fuzzing or
+// attack does not produce the same number of logs and keep the Conn busy. The handler
+// is called synchronously and should return quickly. Giving nil removes it.
+func (c *Conn) SetMalformedHandler(fn MalformedHandlerFunc, burst int, interval time.Duration) {
+	if fn == nil {
+		c.malformed.Store(nil)
+		return
+	}
+	c.malformed.Store(&malformedReporter{fn: fn, sampler: sampler.New(burst, interval)})
+}
+
+type malformedReporter struct {
+	fn      MalformedHandlerFunc
+	sampler *sampler.Sampler
+}
+
+func (c *Conn) reportMalformed(peer net.Addr, err error) {
+	r := c.malformed.Load()
+	if r == nil {
+		return
+	}
+	if ok, suppressed := r.sampler.Allow(time.Now()); ok {
+		r.fn(peer, err, suppressed)
+	}
+}
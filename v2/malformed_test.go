@@ -0,0 +1,58 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+func TestMalformedHandler(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.23:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	peer, err := net.ListenPacket("udp", "127.0.0.24:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	rcvCh := make(chan net.Addr, 10)
+	conn.SetMalformedHandler(func(addr net.Addr, err error, suppressed uint64) {
+		rcvCh <- addr
+	}, 2, time.Hour)
+
+	for range 5 {
+		if _, err := peer.WriteTo([]byte{0x48, 0x01}, laddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range 2 {
+		select {
+		case addr := <-rcvCh:
+			if addr.String() != peer.LocalAddr().String() {
+				t.Errorf("got malformed packet from %s", addr)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("malformed packet not reported")
+		}
+	}
+	select {
+	case <-rcvCh:
+		t.Error("malformed packet beyond the burst reported")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
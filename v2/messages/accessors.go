@@ -0,0 +1,146 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// IMSI returns the IMSI in the message, or the error if the message cannot have it,
+// does not have it or it is malformed.
+//
+// ErrIEMissing is returned if the IMSI IE is not present, and the error from
+// (*ies.IE).ParseIMSI is returned wrapped if it is malformed.
+func IMSI(m Message) (string, error) {
+	var ie *ies.IE
+	switch msg := m.(type) {
+	case *CreateSessionRequest:
+		ie = msg.IMSI
+	case *ModifyBearerRequest:
+		ie = msg.IMSI
+	case *ContextRequest:
+		ie = msg.IMSI
+	case *ContextResponse:
+		ie = msg.IMSI
+	case *DownlinkDataNotification:
+		ie = msg.IMSI
+	case *DownlinkDataNotificationAcknowledge:
+		ie = msg.IMSI
+	case *CreateIndirectDataForwardingTunnelRequest:
+		ie = msg.IMSI
+	default:
+		return "", fmt.Errorf("%w: IMSI in %s", ErrIENotExpected, m.MessageTypeName())
+	}
+	if ie == nil {
+		return "", fmt.Errorf("%w: IMSI in %s", ErrIEMissing, m.MessageTypeName())
+	}
+
+	imsi, err := ie.ParseIMSI()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse IMSI in %s: %w", m.MessageTypeName(), err)
+	}
+	return imsi, nil
+}
+
+// SenderFTEID returns the TEID and IP address in the Sender F-TEID for Control Plane
+// in the message, or the error if the message cannot have it, does not have it or it
+// is malformed.
+//
+// ErrIEMissing is returned if the F-TEID IE is not present, and the error from
+// (*ies.IE).ParseTEID or ParseIPAddress is returned wrapped if it is malformed.
+func SenderFTEID(m Message) (teid uint32, addr string, err error) {
+	var ie *ies.IE
+	switch msg := m.(type) {
+	case *CreateSessionRequest:
+		ie = msg.SenderFTEIDC
+	case *CreateSessionResponse:
+		ie = msg.SenderFTEIDC
+	case *ModifyBearerRequest:
+		ie = msg.SenderFTEIDC
+	case *DeleteSessionRequest:
+		ie = msg.SenderFTEIDC
+	case *DownlinkDataNotification:
+		ie = msg.SenderFTEIDC
+	case *CreateIndirectDataForwardingTunnelRequest:
+		ie = msg.SenderFTEIDC
+	case *CreateIndirectDataForwardingTunnelResponse:
+		ie = msg.SenderFTEIDC
+	default:
+		return 0, "", fmt.Errorf("%w: Sender F-TEID in %s", ErrIENotExpected, m.MessageTypeName())
+	}
+	if ie == nil {
+		return 0, "", fmt.Errorf("%w: Sender F-TEID in %s", ErrIEMissing, m.MessageTypeName())
+	}
+
+	if teid, err = ie.ParseTEID(); err != nil {
+		return 0, "", fmt.Errorf("failed to parse Sender F-TEID in %s: %w", m.MessageTypeName(), err)
+	}
+	if addr, err = ie.ParseIPAddress(); err != nil {
+		return 0, "", fmt.Errorf("failed to parse Sender F-TEID in %s: %w", m.MessageTypeName(), err)
+	}
+	return teid, addr, nil
+}
+
+// Cause returns the Cause value in the message, or the error if the message cannot
+// have it, does not have it or it is malformed.
+//
+// ErrIEMissing is returned if the Cause IE is not present, and the error from
+// (*ies.IE).ParseCause is returned wrapped if it is malformed.
+func Cause(m Message) (uint8, error) {
+	ie, err := CauseIE(m)
+	if err != nil {
+		return 0, err
+	}
+
+	cause, err := ie.ParseCause()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse Cause in %s: %w", m.MessageTypeName(), err)
+	}
+	return cause, nil
+}
+
+// CauseIE returns the Cause IE in the message to see the flags in it, e.g., the Cause
+// Source, or the error if the message cannot have it or does not have it.
+func CauseIE(m Message) (*ies.IE, error) {
+	var ie *ies.IE
+	switch msg := m.(type) {
+	case *ContextAcknowledge:
+		ie = msg.Cause
+	case *ContextResponse:
+		ie = msg.Cause
+	case *CreateBearerResponse:
+		ie = msg.Cause
+	case *CreateIndirectDataForwardingTunnelResponse:
+		ie = msg.Cause
+	case *CreateSessionResponse:
+		ie = msg.Cause
+	case *DeleteBearerRequest:
+		ie = msg.Cause
+	case *DeleteBearerResponse:
+		ie = msg.Cause
+	case *DeleteIndirectDataForwardingTunnelResponse:
+		ie = msg.Cause
+	case *DeleteSessionRequest:
+		ie = msg.Cause
+	case *DeleteSessionResponse:
+		ie = msg.Cause
+	case *DownlinkDataNotification:
+		ie = msg.Cause
+	case *DownlinkDataNotificationAcknowledge:
+		ie = msg.Cause
+	case *ModifyBearerResponse:
+		ie = msg.Cause
+	case *ReleaseAccessBearersResponse:
+		ie = msg.Cause
+	default:
+		return nil, fmt.Errorf("%w: Cause in %s", ErrIENotExpected, m.MessageTypeName())
+	}
+	if ie == nil {
+		return nil, fmt.Errorf("%w: Cause in %s", ErrIEMissing, m.MessageTypeName())
+	}
+	return ie, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"errors"
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestIMSI(t *testing.T) {
+	csReq := messages.NewCreateSessionRequest(0, 0, ies.NewIMSI("123451234567890"))
+	if got, err := messages.IMSI(csReq); err != nil || got != "123451234567890" {
+		t.Errorf("got %s, %v", got, err)
+	}
+
+	csReq = messages.NewCreateSessionRequest(0, 0, ies.New(ies.IMSI, 0, []byte{0x21, 0xa3}))
+	if _, err := messages.IMSI(csReq); !errors.Is(err, ies.ErrMalformed) {
+		t.Errorf("got %v, want %v", err, ies.ErrMalformed)
+	}
+
+	csReq = messages.NewCreateSessionRequest(0, 0)
+	if _, err := messages.IMSI(csReq); !errors.Is(err, messages.ErrIEMissing) {
+		t.Errorf("got %v, want %v", err, messages.ErrIEMissing)
+	}
+
+	if _, err := messages.IMSI(messages.NewEchoRequest(0)); !errors.Is(err, messages.ErrIENotExpected) {
+		t.Errorf("got %v, want %v", err, messages.ErrIENotExpected)
+	}
+}
+
+func TestSenderFTEID(t *testing.T) {
+	csRsp := messages.NewCreateSessionResponse(0, 0,
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0x11223344, "127.0.0.1", ""),
+	)
+	teid, addr, err := messages.SenderFTEID(csRsp)
+	if err != nil || teid != 0x11223344 || addr != "127.0.0.1" {
+		t.Errorf("got %#x, %s, %v", teid, addr, err)
+	}
+
+	csRsp = messages.NewCreateSessionResponse(0, 0,
+		ies.New(ies.FullyQualifiedTEID, 0, []byte{0x8b, 0x11, 0x22, 0x33, 0x44, 0x7f}),
+	)
+	if _, _, err := messages.SenderFTEID(csRsp); !errors.Is(err, ies.ErrTooShortToDecode) {
+		t.Errorf("got %v, want %v", err, ies.ErrTooShortToDecode)
+	}
+}
+
+func TestCause(t *testing.T) {
+	dsRsp := messages.NewDeleteSessionResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	if got, err := messages.Cause(dsRsp); err != nil || got != v2.CauseRequestAccepted {
+		t.Errorf("got %d, %v", got, err)
+	}
+
+	dsRsp = messages.NewDeleteSessionResponse(0, 0)
+	if _, err := messages.Cause(dsRsp); !errors.Is(err, messages.ErrIEMissing) {
+		t.Errorf("got %v, want %v", err, messages.ErrIEMissing)
+	}
+}
+
+func TestCauseIE(t *testing.T) {
+	dsRsp := messages.NewDeleteSessionResponse(0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 1, nil))
+	ie, err := messages.CauseIE(dsRsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ie.IsRemoteCause() {
+		t.Error("Cause Source is not set")
+	}
+
+	if _, err := messages.CauseIE(messages.NewEchoRequest(0)); !errors.Is(err, messages.ErrIENotExpected) {
+		t.Errorf("got %v, want %v", err, messages.ErrIENotExpected)
+	}
+}
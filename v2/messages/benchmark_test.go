@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+var benchMessages = []struct {
+	description string
+	msg         messages.Message
+}{
+	{
+		"EchoRequest",
+		messages.NewEchoRequest(1, ies.NewRecovery(0x80)),
+	}, {
+		"CreateSessionRequest",
+		messages.NewCreateSessionRequest(
+			0, 1,
+			ies.NewIMSI("123451234567890"),
+			ies.NewMSISDN("819012345678"),
+			ies.NewMobileEquipmentIdentity("123450123456789"),
+			ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 0x0001, 0x00000101, -1, -1),
+			ies.NewServingNetwork("123", "45"),
+			ies.NewRATType(v2.RATTypeEUTRAN),
+			ies.NewIndicationFromOctets(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0, "1.1.1.2", "").WithInstance(1),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
+			ies.NewPDNType(v2.PDNTypeIPv4),
+			ies.NewPDNAddressAllocation("0.0.0.0"),
+			ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+			ies.NewAggregateMaximumBitRate(0x11111111, 0x22222222),
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(0x05),
+				ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
+			),
+			ies.NewRecovery(0x80),
+			ies.NewUETimeZone(9*time.Hour, 0),
+			ies.NewChargingCharacteristics(0x0800),
+		),
+	}, {
+		"CreateSessionResponse",
+		messages.NewCreateSessionResponse(
+			0xffffffff, 1,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0xffffffff, "1.1.1.3", ""),
+			ies.NewPDNAddressAllocation("2.2.2.2"),
+			ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
+			ies.NewBearerContext(
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewEPSBearerID(0x05),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0xffffffff, "1.1.1.4", ""),
+				ies.NewChargingID(0xffffffff),
+			),
+		),
+	},
+}
+
+func BenchmarkSerialize(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			buf := make([]byte, c.msg.Len())
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, c := range benchMessages {
+		b.Run(c.description, func(b *testing.B) {
+			serialized, err := messages.Serialize(c.msg)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := messages.Decode(serialized); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSerializeToAllocs(t *testing.T) {
+	for _, c := range benchMessages {
+		t.Run(c.description, func(t *testing.T) {
+			buf := make([]byte, c.msg.Len())
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := c.msg.SerializeTo(buf); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("got %v allocations, want 0", allocs)
+			}
+
+			if err := c.msg.SerializeTo(buf[:len(buf)-1]); err != messages.ErrTooShortToSerialize {
+				t.Errorf("got %v, want %v", err, messages.ErrTooShortToSerialize)
+			}
+		})
+	}
+}
+
+func TestDetachPayload(t *testing.T) {
+	msg := messages.NewEchoRequest(1, ies.NewRecovery(1))
+	buf := make([]byte, msg.Len())
+	if err := msg.SerializeTo(buf); err != nil {
+		t.Fatal(err)
+	}
+	messages.DetachPayload(msg)
+	want := bytes.Clone(msg.Payload)
+
+	// the buffer reused for another message.
+	clear(buf)
+	if !bytes.Equal(msg.Payload, want) {
+		t.Errorf("Payload overwritten with the buffer:\ngot:  %x\nwant: %x", msg.Payload, want)
+	}
+}
@@ -85,10 +85,9 @@ func (c *ContextAcknowledge) Serialize() ([]byte, error) {
 
 // SerializeTo serializes ContextAcknowledge into bytes.
 func (c *ContextAcknowledge) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.Cause; ie != nil {
@@ -118,10 +118,9 @@ func (c *ContextRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes ContextRequest into bytes.
 func (c *ContextRequest) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.IMSI; ie != nil {
@@ -117,10 +117,9 @@ func (c *CreateBearerRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes CreateBearerRequest into bytes.
 func (c *CreateBearerRequest) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.PTI; ie != nil {
@@ -148,10 +148,9 @@ func (c *CreateBearerResponse) Serialize() ([]byte, error) {
 
 // SerializeTo serializes CreateBearerResponse into bytes.
 func (c *CreateBearerResponse) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.Cause; ie != nil {
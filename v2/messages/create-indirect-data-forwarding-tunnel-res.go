@@ -0,0 +1,212 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// CreateIndirectDataForwardingTunnelResponse is a CreateIndirectDataForwardingTunnelResponse Header and its IEs above.
+type CreateIndirectDataForwardingTunnelResponse struct {
+	*Header
+	Cause            *ies.IE
+	SenderFTEIDC     *ies.IE
+	BearerContexts   []*ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewCreateIndirectDataForwardingTunnelResponse creates a new CreateIndirectDataForwardingTunnelResponse.
+func NewCreateIndirectDataForwardingTunnelResponse(teid, seq uint32, ie ...*ies.IE) *CreateIndirectDataForwardingTunnelResponse {
+	c := &CreateIndirectDataForwardingTunnelResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeCreateIndirectDataForwardingTunnelResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			c.Cause = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = append(c.BearerContexts, i)
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			c.PrivateExtension = i
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	c.SetLength()
+	return c
+}
+
+// Serialize serializes CreateIndirectDataForwardingTunnelResponse into bytes.
+func (c *CreateIndirectDataForwardingTunnelResponse) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeTo serializes CreateIndirectDataForwardingTunnelResponse into bytes.
+func (c *CreateIndirectDataForwardingTunnelResponse) SerializeTo(b []byte) error {
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := c.Cause; ie != nil {
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	for _, ie := range c.BearerContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := c.Recovery; ie != nil {
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(c.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	c.Header.SetLength()
+	return c.Header.SerializeTo(b)
+}
+
+// DecodeCreateIndirectDataForwardingTunnelResponse decodes given bytes as CreateIndirectDataForwardingTunnelResponse.
+func DecodeCreateIndirectDataForwardingTunnelResponse(b []byte) (*CreateIndirectDataForwardingTunnelResponse, error) {
+	c := &CreateIndirectDataForwardingTunnelResponse{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes as CreateIndirectDataForwardingTunnelResponse.
+func (c *CreateIndirectDataForwardingTunnelResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	c.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(c.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(c.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			c.Cause = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = append(c.BearerContexts, i)
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			c.PrivateExtension = i
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (c *CreateIndirectDataForwardingTunnelResponse) Len() int {
+	l := c.Header.Len() - len(c.Header.Payload)
+
+	if ie := c.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		l += ie.Len()
+	}
+	for _, ie := range c.BearerContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	if ie := c.Recovery; ie != nil {
+		l += ie.Len()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *CreateIndirectDataForwardingTunnelResponse) SetLength() {
+	c.Header.Length = uint16(c.Len() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (c *CreateIndirectDataForwardingTunnelResponse) MessageTypeName() string {
+	return "Create Indirect Data Forwarding Tunnel Response"
+}
+
+// TEID returns the TEID in uint32.
+func (c *CreateIndirectDataForwardingTunnelResponse) TEID() uint32 {
+	return c.Header.teid()
+}
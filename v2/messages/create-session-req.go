@@ -286,10 +286,9 @@ func (c *CreateSessionRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes CreateSessionRequest into bytes.
 func (c *CreateSessionRequest) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.IMSI; ie != nil {
@@ -680,6 +679,7 @@ func (c *CreateSessionRequest) SerializeTo(b []byte) error {
 		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
 			return err
 		}
+		offset += ie.Len()
 	}
 
 	for _, ie := range c.AdditionalIEs {
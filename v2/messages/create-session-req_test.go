@@ -98,3 +98,48 @@ func TestCreateSessionRequest(t *testing.T) {
 		return v, nil
 	})
 }
+
+func TestCreateSessionRequestSerializeAllIEs(t *testing.T) {
+	csReq := messages.NewCreateSessionRequest(
+		0, 1,
+		ies.NewIMSI("123451234567890"),
+		ies.NewBearerContext(ies.NewEPSBearerID(5)),
+		ies.NewBearerContext(ies.NewEPSBearerID(6)).WithInstance(1),
+		ies.NewPrivateExtension(10415, []byte{0xde, 0xad}),
+		// the Bearer Context without the field is kept in AdditionalIEs.
+		ies.NewBearerContext(ies.NewEPSBearerID(7)).WithInstance(2),
+	)
+	b, err := csReq.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := messages.DecodeCreateSessionRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ebi := func(ie *ies.IE) uint8 {
+		child, err := ie.FindByType(ies.EPSBearerID, 0)
+		if err != nil {
+			return 0
+		}
+		return child.EPSBearerID()
+	}
+	for _, c := range []struct {
+		ie  *ies.IE
+		ebi uint8
+	}{
+		{decoded.BearerContextsToBeCreated, 5},
+		{decoded.BearerContextsToBeRemoved, 6},
+	} {
+		if c.ie == nil || ebi(c.ie) != c.ebi {
+			t.Errorf("Bearer Context with EBI %d not decoded: %v", c.ebi, c.ie)
+		}
+	}
+	if decoded.PrivateExtension == nil {
+		t.Error("Private Extension not decoded")
+	}
+	if len(decoded.AdditionalIEs) != 1 || ebi(decoded.AdditionalIEs[0]) != 7 {
+		t.Errorf("got unexpected AdditionalIEs: %v", decoded.AdditionalIEs)
+	}
+}
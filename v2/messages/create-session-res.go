@@ -13,6 +13,7 @@ type CreateSessionResponse struct {
 	*Header
 	Cause                         *ies.IE
 	ChangeReportingAction         *ies.IE
+	CSGInformationReportingAction *ies.IE
 	HeNBInformationReporting      *ies.IE
 	SenderFTEIDC                  *ies.IE
 	PGWS5S8FTEIDC                 *ies.IE
@@ -64,6 +65,8 @@ func NewCreateSessionResponse(teid, seq uint32, ie ...*ies.IE) *CreateSessionRes
 			c.Cause = i
 		case ies.ChangeReportingAction:
 			c.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			c.CSGInformationReportingAction = i
 		case ies.HeNBInformationReporting:
 			c.HeNBInformationReporting = i
 		case ies.FullyQualifiedTEID:
@@ -176,10 +179,9 @@ func (c *CreateSessionResponse) Serialize() ([]byte, error) {
 
 // SerializeTo serializes CreateSessionResponse into bytes.
 func (c *CreateSessionResponse) SerializeTo(b []byte) error {
-	if c.Header.Payload != nil {
-		c.Header.Payload = nil
+	if err := c.Header.preparePayload(b, c.Len()); err != nil {
+		return err
 	}
-	c.Header.Payload = make([]byte, c.Len()-c.Header.Len())
 
 	offset := 0
 	if ie := c.Cause; ie != nil {
@@ -194,6 +196,12 @@ func (c *CreateSessionResponse) SerializeTo(b []byte) error {
 		}
 		offset += ie.Len()
 	}
+	if ie := c.CSGInformationReportingAction; ie != nil {
+		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
 	if ie := c.HeNBInformationReporting; ie != nil {
 		if err := ie.SerializeTo(c.Payload[offset:]); err != nil {
 			return err
@@ -429,6 +437,8 @@ func (c *CreateSessionResponse) DecodeFromBytes(b []byte) error {
 			c.Cause = i
 		case ies.ChangeReportingAction:
 			c.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			c.CSGInformationReportingAction = i
 		case ies.HeNBInformationReporting:
 			c.HeNBInformationReporting = i
 		case ies.FullyQualifiedTEID:
@@ -539,6 +549,9 @@ func (c *CreateSessionResponse) Len() int {
 	if ie := c.ChangeReportingAction; ie != nil {
 		l += ie.Len()
 	}
+	if ie := c.CSGInformationReportingAction; ie != nil {
+		l += ie.Len()
+	}
 	if ie := c.HeNBInformationReporting; ie != nil {
 		l += ie.Len()
 	}
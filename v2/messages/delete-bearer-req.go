@@ -122,10 +122,9 @@ func (d *DeleteBearerRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes DeleteBearerRequest into bytes.
 func (d *DeleteBearerRequest) SerializeTo(b []byte) error {
-	if d.Header.Payload != nil {
-		d.Header.Payload = nil
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 
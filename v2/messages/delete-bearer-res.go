@@ -133,6 +133,12 @@ func NewDeleteBearerResponse(teid, seq uint32, ie ...*ies.IE) *DeleteBearerRespo
 		case ies.FContainer:
 			d.NBIFOMContainer = i
 		case ies.SecondaryRATUsageDataReport:
+			// several reports can be included, which are kept in AdditionalIEs
+			// except the first one.
+			if d.SecondaryRATUsageDataReport != nil {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+				continue
+			}
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
 			d.PrivateExtension = i
@@ -156,10 +162,9 @@ func (d *DeleteBearerResponse) Serialize() ([]byte, error) {
 
 // SerializeTo serializes DeleteBearerResponse into bytes.
 func (d *DeleteBearerResponse) SerializeTo(b []byte) error {
-	if d.Header.Payload != nil {
-		d.Header.Payload = nil
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
@@ -442,6 +447,12 @@ func (d *DeleteBearerResponse) DecodeFromBytes(b []byte) error {
 		case ies.FContainer:
 			d.NBIFOMContainer = i
 		case ies.SecondaryRATUsageDataReport:
+			// several reports can be included, which are kept in AdditionalIEs
+			// except the first one.
+			if d.SecondaryRATUsageDataReport != nil {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+				continue
+			}
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
 			d.PrivateExtension = i
@@ -0,0 +1,150 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// DeleteIndirectDataForwardingTunnelRequest is a DeleteIndirectDataForwardingTunnelRequest Header and its IEs above.
+type DeleteIndirectDataForwardingTunnelRequest struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewDeleteIndirectDataForwardingTunnelRequest creates a new DeleteIndirectDataForwardingTunnelRequest.
+func NewDeleteIndirectDataForwardingTunnelRequest(teid, seq uint32, ie ...*ies.IE) *DeleteIndirectDataForwardingTunnelRequest {
+	d := &DeleteIndirectDataForwardingTunnelRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDeleteIndirectDataForwardingTunnelRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize serializes DeleteIndirectDataForwardingTunnelRequest into bytes.
+func (d *DeleteIndirectDataForwardingTunnelRequest) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeTo serializes DeleteIndirectDataForwardingTunnelRequest into bytes.
+func (d *DeleteIndirectDataForwardingTunnelRequest) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeDeleteIndirectDataForwardingTunnelRequest decodes given bytes as DeleteIndirectDataForwardingTunnelRequest.
+func DecodeDeleteIndirectDataForwardingTunnelRequest(b []byte) (*DeleteIndirectDataForwardingTunnelRequest, error) {
+	d := &DeleteIndirectDataForwardingTunnelRequest{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes as DeleteIndirectDataForwardingTunnelRequest.
+func (d *DeleteIndirectDataForwardingTunnelRequest) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (d *DeleteIndirectDataForwardingTunnelRequest) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DeleteIndirectDataForwardingTunnelRequest) SetLength() {
+	d.Header.Length = uint16(d.Len() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DeleteIndirectDataForwardingTunnelRequest) MessageTypeName() string {
+	return "Delete Indirect Data Forwarding Tunnel Request"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DeleteIndirectDataForwardingTunnelRequest) TEID() uint32 {
+	return d.Header.teid()
+}
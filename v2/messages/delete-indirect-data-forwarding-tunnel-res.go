@@ -0,0 +1,178 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// DeleteIndirectDataForwardingTunnelResponse is a DeleteIndirectDataForwardingTunnelResponse Header and its IEs above.
+type DeleteIndirectDataForwardingTunnelResponse struct {
+	*Header
+	Cause            *ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewDeleteIndirectDataForwardingTunnelResponse creates a new DeleteIndirectDataForwardingTunnelResponse.
+func NewDeleteIndirectDataForwardingTunnelResponse(teid, seq uint32, ie ...*ies.IE) *DeleteIndirectDataForwardingTunnelResponse {
+	d := &DeleteIndirectDataForwardingTunnelResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDeleteIndirectDataForwardingTunnelResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize serializes DeleteIndirectDataForwardingTunnelResponse into bytes.
+func (d *DeleteIndirectDataForwardingTunnelResponse) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeTo serializes DeleteIndirectDataForwardingTunnelResponse into bytes.
+func (d *DeleteIndirectDataForwardingTunnelResponse) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.Cause; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.Recovery; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeDeleteIndirectDataForwardingTunnelResponse decodes given bytes as DeleteIndirectDataForwardingTunnelResponse.
+func DecodeDeleteIndirectDataForwardingTunnelResponse(b []byte) (*DeleteIndirectDataForwardingTunnelResponse, error) {
+	d := &DeleteIndirectDataForwardingTunnelResponse{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes as DeleteIndirectDataForwardingTunnelResponse.
+func (d *DeleteIndirectDataForwardingTunnelResponse) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (d *DeleteIndirectDataForwardingTunnelResponse) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.Recovery; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DeleteIndirectDataForwardingTunnelResponse) SetLength() {
+	d.Header.Length = uint16(d.Len() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DeleteIndirectDataForwardingTunnelResponse) MessageTypeName() string {
+	return "Delete Indirect Data Forwarding Tunnel Response"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DeleteIndirectDataForwardingTunnelResponse) TEID() uint32 {
+	return d.Header.teid()
+}
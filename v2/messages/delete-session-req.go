@@ -114,6 +114,12 @@ func NewDeleteSessionRequest(teid, seq uint32, ie ...*ies.IE) *DeleteSessionRequ
 		case ies.ExtendedProtocolConfigurationOptions:
 			d.EPCO = i
 		case ies.SecondaryRATUsageDataReport:
+			// several reports can be included, which are kept in AdditionalIEs
+			// except the first one.
+			if d.SecondaryRATUsageDataReport != nil {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+				continue
+			}
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
 			d.PrivateExtension = i
@@ -137,10 +143,9 @@ func (d *DeleteSessionRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes DeleteSessionRequest into bytes.
 func (d *DeleteSessionRequest) SerializeTo(b []byte) error {
-	if d.Header.Payload != nil {
-		d.Header.Payload = nil
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
@@ -389,6 +394,12 @@ func (d *DeleteSessionRequest) DecodeFromBytes(b []byte) error {
 		case ies.ExtendedProtocolConfigurationOptions:
 			d.EPCO = i
 		case ies.SecondaryRATUsageDataReport:
+			// several reports can be included, which are kept in AdditionalIEs
+			// except the first one.
+			if d.SecondaryRATUsageDataReport != nil {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+				continue
+			}
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
 			d.PrivateExtension = i
@@ -94,10 +94,9 @@ func (d *DeleteSessionResponse) Serialize() ([]byte, error) {
 
 // SerializeTo serializes DeleteSessionResponse into bytes.
 func (d *DeleteSessionResponse) SerializeTo(b []byte) error {
-	if d.Header.Payload != nil {
-		d.Header.Payload = nil
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
 	}
-	d.Header.Payload = make([]byte, d.Len()-d.Header.Len())
 
 	offset := 0
 	if ie := d.Cause; ie != nil {
@@ -0,0 +1,79 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Dissect returns the message in human readable, multi-line format, which lists
+// the header fields and every IE with its name, instance, length and value.
+// The child IEs of grouped IEs are listed with deeper indentation.
+func Dissect(m Message) string {
+	b, err := Serialize(m)
+	if err != nil {
+		return fmt.Sprintf("GTPv2 %s\n  ! failed to serialize message: %s\n", m.MessageTypeName(), err)
+	}
+	return DissectBytes(b)
+}
+
+// DissectBytes returns the message in b in the same format as Dissect.
+//
+// Unlike Decode, this does not give up on the message which cannot be decoded,
+// but prints the error together with the IEs so that the problematic IE can be
+// found.
+func DissectBytes(b []byte) string {
+	s := &strings.Builder{}
+
+	h, err := DecodeHeader(b)
+	if err != nil {
+		fmt.Fprintf(s, "GTPv2 (malformed header: %s)\n", err)
+		return s.String()
+	}
+
+	name := fmt.Sprintf("Unknown(%d)", h.Type)
+	msg, decErr := Decode(b)
+	if decErr == nil {
+		name = msg.MessageTypeName()
+	}
+	fmt.Fprintf(s, "GTPv2 %s\n", name)
+	fmt.Fprintf(s, "  Header: Flags: %#02x, Type: %d, Length: %d", h.Flags, h.Type, h.Length)
+	if h.HasTEID() {
+		fmt.Fprintf(s, ", TEID: %#08x", h.TEID)
+	}
+	fmt.Fprintf(s, ", SequenceNumber: %d\n", h.SequenceNumber)
+	if decErr != nil {
+		fmt.Fprintf(s, "  ! failed to decode message: %s\n", decErr)
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		fmt.Fprintf(s, "  ! failed to decode IEs: %s\n", err)
+	}
+	dissectIEs(s, decodedIEs, 1)
+
+	return s.String()
+}
+
+func dissectIEs(s *strings.Builder, decodedIEs []*ies.IE, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, ie := range decodedIEs {
+		if ie.IsGrouped() {
+			fmt.Fprintf(s, "%s%s(%d) instance: %d, length: %d\n",
+				indent, ie.Name(), ie.Type, ie.Instance(), len(ie.Payload))
+			children, err := ie.Children()
+			if err != nil {
+				fmt.Fprintf(s, "%s  ! failed to decode IEs: %s\n", indent, err)
+			}
+			dissectIEs(s, children, depth+1)
+			continue
+		}
+		fmt.Fprintf(s, "%s%s(%d) instance: %d, length: %d, value: %s\n",
+			indent, ie.Name(), ie.Type, ie.Instance(), len(ie.Payload), ie.ValueString())
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestDissect(t *testing.T) {
+	msg := messages.NewCreateSessionResponse(
+		0x11223344, 0x00abcdef,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0xffffffff, "1.1.1.1", ""),
+		ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(0x12345678)),
+	)
+
+	want := `GTPv2 Create Session Response
+  Header: Flags: 0x48, Type: 33, Length: 44, TEID: 0x11223344, SequenceNumber: 11259375
+  Cause(2) instance: 0, length: 2, value: 16
+  FullyQualifiedTEID(87) instance: 0, length: 9, value: InterfaceType: 11, TEID: 0xffffffff, Address: 1.1.1.1
+  BearerContext(93) instance: 0, length: 13
+    EPSBearerID(73) instance: 0, length: 1, value: 5
+    ChargingID(94) instance: 0, length: 4, value: 0x12345678
+`
+	if got := messages.Dissect(msg); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDissectBytesMalformed(t *testing.T) {
+	// Cause IE says its length is 2, but the message ends in the middle.
+	b := []byte{
+		0x48, 0x21, 0x00, 0x0d, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+		0x02, 0x00, 0x05,
+	}
+
+	want := `GTPv2 Unknown(33)
+  Header: Flags: 0x48, Type: 33, Length: 13, TEID: 0x11223344, SequenceNumber: 1
+  ! failed to decode message: failed to decode GTPv2 Message: too short to decode as GTP
+  ! failed to decode IEs: too short to decode as GTP
+`
+	if got := messages.DissectBytes(b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
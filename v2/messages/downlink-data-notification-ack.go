@@ -0,0 +1,262 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// DownlinkDataNotificationAcknowledge is a DownlinkDataNotificationAcknowledge Header and its IEs above.
+type DownlinkDataNotificationAcknowledge struct {
+	*Header
+	Cause                               *ies.IE
+	DataNotificationDelay               *ies.IE
+	Recovery                            *ies.IE
+	DLLowPriorityTrafficThrottling      *ies.IE
+	IMSI                                *ies.IE
+	DLBufferingDuration                 *ies.IE
+	DLBufferingSuggestedPacketCount     *ies.IE
+	MMES4SGSNOverloadControlInformation *ies.IE
+	PrivateExtension                    *ies.IE
+	AdditionalIEs                       []*ies.IE
+}
+
+// NewDownlinkDataNotificationAcknowledge creates a new DownlinkDataNotificationAcknowledge.
+func NewDownlinkDataNotificationAcknowledge(teid, seq uint32, ie ...*ies.IE) *DownlinkDataNotificationAcknowledge {
+	d := &DownlinkDataNotificationAcknowledge{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDownlinkDataNotificationAcknowledge, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.DelayValue:
+			d.DataNotificationDelay = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.Throttling:
+			d.DLLowPriorityTrafficThrottling = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.EPCTimer:
+			d.DLBufferingDuration = i
+		case ies.IntegerNumber:
+			d.DLBufferingSuggestedPacketCount = i
+		case ies.OverloadControlInformation:
+			d.MMES4SGSNOverloadControlInformation = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize serializes DownlinkDataNotificationAcknowledge into bytes.
+func (d *DownlinkDataNotificationAcknowledge) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeTo serializes DownlinkDataNotificationAcknowledge into bytes.
+func (d *DownlinkDataNotificationAcknowledge) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.Cause; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.DataNotificationDelay; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.Recovery; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.DLLowPriorityTrafficThrottling; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.DLBufferingDuration; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.DLBufferingSuggestedPacketCount; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.MMES4SGSNOverloadControlInformation; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeDownlinkDataNotificationAcknowledge decodes given bytes as DownlinkDataNotificationAcknowledge.
+func DecodeDownlinkDataNotificationAcknowledge(b []byte) (*DownlinkDataNotificationAcknowledge, error) {
+	d := &DownlinkDataNotificationAcknowledge{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes as DownlinkDataNotificationAcknowledge.
+func (d *DownlinkDataNotificationAcknowledge) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.DelayValue:
+			d.DataNotificationDelay = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.Throttling:
+			d.DLLowPriorityTrafficThrottling = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.EPCTimer:
+			d.DLBufferingDuration = i
+		case ies.IntegerNumber:
+			d.DLBufferingSuggestedPacketCount = i
+		case ies.OverloadControlInformation:
+			d.MMES4SGSNOverloadControlInformation = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (d *DownlinkDataNotificationAcknowledge) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.DataNotificationDelay; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.Recovery; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.DLLowPriorityTrafficThrottling; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.DLBufferingDuration; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.DLBufferingSuggestedPacketCount; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.MMES4SGSNOverloadControlInformation; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DownlinkDataNotificationAcknowledge) SetLength() {
+	d.Header.Length = uint16(d.Len() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DownlinkDataNotificationAcknowledge) MessageTypeName() string {
+	return "Downlink Data Notification Acknowledge"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DownlinkDataNotificationAcknowledge) TEID() uint32 {
+	return d.Header.teid()
+}
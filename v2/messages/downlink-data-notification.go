@@ -0,0 +1,290 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// DownlinkDataNotification is a DownlinkDataNotification Header and its IEs above.
+type DownlinkDataNotification struct {
+	*Header
+	Cause                         *ies.IE
+	EPSBearerID                   *ies.IE
+	ARP                           *ies.IE
+	IMSI                          *ies.IE
+	SenderFTEIDC                  *ies.IE
+	IndicationFlags               *ies.IE
+	SGWNodeLoadControlInformation *ies.IE
+	SGWOverloadControlInformation *ies.IE
+	PagingAndServiceInformation   *ies.IE
+	DLDataPacketsSize             *ies.IE
+	PrivateExtension              *ies.IE
+	AdditionalIEs                 []*ies.IE
+}
+
+// NewDownlinkDataNotification creates a new DownlinkDataNotification.
+func NewDownlinkDataNotification(teid, seq uint32, ie ...*ies.IE) *DownlinkDataNotification {
+	d := &DownlinkDataNotification{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDownlinkDataNotification, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.EPSBearerID:
+			d.EPSBearerID = i
+		case ies.AllocationRetensionPriority:
+			d.ARP = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.FullyQualifiedTEID:
+			d.SenderFTEIDC = i
+		case ies.Indication:
+			d.IndicationFlags = i
+		case ies.LoadControlInformation:
+			d.SGWNodeLoadControlInformation = i
+		case ies.OverloadControlInformation:
+			d.SGWOverloadControlInformation = i
+		case ies.PagingAndServiceInformation:
+			d.PagingAndServiceInformation = i
+		case ies.IntegerNumber:
+			d.DLDataPacketsSize = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Serialize serializes DownlinkDataNotification into bytes.
+func (d *DownlinkDataNotification) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeTo serializes DownlinkDataNotification into bytes.
+func (d *DownlinkDataNotification) SerializeTo(b []byte) error {
+	if err := d.Header.preparePayload(b, d.Len()); err != nil {
+		return err
+	}
+
+	offset := 0
+	if ie := d.Cause; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.EPSBearerID; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.ARP; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.SenderFTEIDC; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.IndicationFlags; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.SGWNodeLoadControlInformation; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.SGWOverloadControlInformation; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PagingAndServiceInformation; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.DLDataPacketsSize; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.SerializeTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.SerializeTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+
+	d.Header.SetLength()
+	return d.Header.SerializeTo(b)
+}
+
+// DecodeDownlinkDataNotification decodes given bytes as DownlinkDataNotification.
+func DecodeDownlinkDataNotification(b []byte) (*DownlinkDataNotification, error) {
+	d := &DownlinkDataNotification{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes as DownlinkDataNotification.
+func (d *DownlinkDataNotification) DecodeFromBytes(b []byte) error {
+	var err error
+	d.Header, err = DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.DecodeMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.EPSBearerID:
+			d.EPSBearerID = i
+		case ies.AllocationRetensionPriority:
+			d.ARP = i
+		case ies.IMSI:
+			d.IMSI = i
+		case ies.FullyQualifiedTEID:
+			d.SenderFTEIDC = i
+		case ies.Indication:
+			d.IndicationFlags = i
+		case ies.LoadControlInformation:
+			d.SGWNodeLoadControlInformation = i
+		case ies.OverloadControlInformation:
+			d.SGWOverloadControlInformation = i
+		case ies.PagingAndServiceInformation:
+			d.PagingAndServiceInformation = i
+		case ies.IntegerNumber:
+			d.DLDataPacketsSize = i
+		case ies.PrivateExtension:
+			d.PrivateExtension = i
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (d *DownlinkDataNotification) Len() int {
+	l := d.Header.Len() - len(d.Header.Payload)
+
+	if ie := d.Cause; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.EPSBearerID; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.ARP; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.IMSI; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.SenderFTEIDC; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.IndicationFlags; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.SGWNodeLoadControlInformation; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.SGWOverloadControlInformation; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PagingAndServiceInformation; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.DLDataPacketsSize; ie != nil {
+		l += ie.Len()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.Len()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DownlinkDataNotification) SetLength() {
+	d.Header.Length = uint16(d.Len() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DownlinkDataNotification) MessageTypeName() string {
+	return "Downlink Data Notification"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DownlinkDataNotification) TEID() uint32 {
+	return d.Header.teid()
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestDownlinkDataNotification(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewDownlinkDataNotification(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewEPSBearerID(5),
+				ies.NewAllocationRetensionPriority(1, 2, 1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xb0, 0x00, 0x12, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				// ARP
+				0x9b, 0x00, 0x01, 0x00, 0x49,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializeable, error) {
+		v, err := messages.DecodeDownlinkDataNotification(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
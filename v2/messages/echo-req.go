@@ -55,10 +55,9 @@ func (e *EchoRequest) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (e *EchoRequest) SerializeTo(b []byte) error {
-	if e.Header.Payload != nil {
-		e.Header.Payload = nil
+	if err := e.Header.preparePayload(b, e.Len()); err != nil {
+		return err
 	}
-	e.Header.Payload = make([]byte, e.Len()-e.Header.Len())
 
 	offset := 0
 	if ie := e.Recovery; ie != nil {
@@ -68,7 +67,7 @@ func (e *EchoRequest) SerializeTo(b []byte) error {
 		offset += ie.Len()
 	}
 	if ie := e.PrivateExtension; ie != nil {
-		if err := ie.SerializeTo(e.Header.Payload); err != nil {
+		if err := ie.SerializeTo(e.Header.Payload[offset:]); err != nil {
 			return err
 		}
 		offset += ie.Len()
@@ -134,6 +133,9 @@ func (e *EchoRequest) Len() int {
 	if ie := e.Recovery; ie != nil {
 		l += ie.Len()
 	}
+	if ie := e.PrivateExtension; ie != nil {
+		l += ie.Len()
+	}
 
 	for _, ie := range e.AdditionalIEs {
 		l += ie.Len()
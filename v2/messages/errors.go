@@ -8,6 +8,11 @@ import "errors"
 
 // Error definitions.
 var (
-	ErrInvalidLength    = errors.New("length value is invalid")
-	ErrTooShortToDecode = errors.New("too short to decode as GTP")
+	ErrInvalidLength       = errors.New("length value is invalid")
+	ErrTooShortToDecode    = errors.New("too short to decode as GTP")
+	ErrTooShortToSerialize = errors.New("too short to serialize")
+
+	ErrIEMissing     = errors.New("IE is missing")
+	ErrIENotExpected = errors.New("IE is not expected in the message")
+	ErrIEOutOfOrder  = errors.New("IE is out of order")
 )
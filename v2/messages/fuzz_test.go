@@ -0,0 +1,67 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func FuzzDecode(f *testing.F) {
+	seeds := []messages.Message{
+		messages.NewEchoRequest(1, ies.NewRecovery(0x80)),
+		messages.NewCreateSessionRequest(
+			0, 1,
+			ies.NewIMSI("123451234567890"),
+			ies.NewMSISDN("123450123456789"),
+			ies.NewAccessPointName("some.apn.example"),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+			ies.NewPDNType(v2.PDNTypeIPv4),
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(0x05),
+				ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
+			),
+			ies.NewServingNetwork("123", "45"),
+			ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 0x0001, 0x00000101, -1, -1),
+		),
+		messages.NewCreateSessionResponse(
+			0xffffffff, 1,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0xffffffff, "1.1.1.3", ""),
+			ies.NewPDNAddressAllocation("2.2.2.2"),
+			ies.NewBearerContext(
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewEPSBearerID(0x05),
+			),
+		),
+		messages.NewModifyBearerRequest(
+			0xffffffff, 1,
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(0x05),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0xffffffff, "1.1.1.4", ""),
+			),
+		),
+		messages.NewDeleteSessionRequest(0xffffffff, 1, ies.NewEPSBearerID(0x05)),
+	}
+	for _, m := range seeds {
+		b, err := messages.Serialize(m)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		msg, err := messages.Decode(b)
+		if err != nil {
+			return
+		}
+		_ = msg.MessageTypeName()
+		_, _ = messages.Serialize(msg)
+	})
+}
@@ -55,6 +55,10 @@ func (h *Header) Serialize() ([]byte, error) {
 
 // SerializeTo puts the byte sequence in the byte array given as b.
 func (h *Header) SerializeTo(b []byte) error {
+	if len(b) < h.Len() {
+		return ErrTooShortToSerialize
+	}
+
 	b[0] = h.Flags
 	b[1] = h.Type
 	binary.BigEndian.PutUint16(b[2:4], h.Length)
@@ -130,6 +134,23 @@ func (h *Header) SetLength() {
 	}
 }
 
+// preparePayload points the Payload to the part of b after the header, so that
+// the message of length l can put its IEs into b directly without allocating
+// the Payload. The Payload refers to b after the message is serialized.
+func (h *Header) preparePayload(b []byte, l int) error {
+	if len(b) < l {
+		return ErrTooShortToSerialize
+	}
+	h.Payload = b[h.Len()-len(h.Payload) : l]
+	return nil
+}
+
+// detachPayload makes the Payload a copy of its own, not to refer to the buffer
+// given to preparePayload.
+func (h *Header) detachPayload() {
+	h.Payload = append([]byte(nil), h.Payload...)
+}
+
 // String returns the GTPv2 header values in human readable format.
 func (h *Header) String() string {
 	return fmt.Sprintf("{Flags: %#x, Type: %d, Length: %d, TEID: %#x, SequenceNumber: %#x, Spare: %d, Payload: %#v}",
@@ -190,17 +211,27 @@ func (h *Header) HasMessagePriority() bool {
 
 // SetMessagePriority sets the MessagePriorityFlag to 1 and puts the MessagePriority
 // given into MessagePriority field.
+//
+// The MessagePriority is the relative priority of the message from 0 to 15, where 0
+// is the highest, and the upper bits of the given value are ignored.
 func (h *Header) SetMessagePriority(mp uint8) {
 	h.Flags |= (1 << 2)
-	h.Spare = (mp & 0xf0)
+	h.Spare = (h.Spare & 0x0f) | (mp << 4)
+}
+
+// ClearMessagePriority sets the MessagePriorityFlag to 0 and clears the MessagePriority
+// field.
+func (h *Header) ClearMessagePriority() {
+	h.Flags &^= (1 << 2)
+	h.Spare &= 0x0f
 }
 
-// MessagePriority returns the value of MessagePriority.
+// MessagePriority returns the value of MessagePriority, from 0 to 15.
 //
 // Note that this returns the value set in the field even if the MessagePriorityFlag
 // is not set to 1.
 func (h *Header) MessagePriority() uint8 {
-	return h.Spare & 0xf0
+	return h.Spare >> 4
 }
 
 // Version returns the GTP version.
@@ -29,6 +29,26 @@ func TestHeader(t *testing.T) {
 				0xda, 0xda, 0xda, 0x00,
 				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
 			},
+		}, {
+			Description: "WithMessagePriority",
+			Structured: func() *messages.Header {
+				h := messages.NewHeader(
+					messages.NewHeaderFlags(2, 0, 1),
+					32,         // Message type
+					0xffffffff, // TEID
+					0xdadada,   // Sequence Number
+					[]byte{ // Payload: IMSI IE
+						0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+					},
+				)
+				h.SetMessagePriority(10)
+				return h
+			}(),
+			Serialized: []byte{
+				0x4c, 0x20, 0x00, 0x14, 0xff, 0xff, 0xff, 0xff,
+				0xda, 0xda, 0xda, 0xa0,
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+			},
 		},
 	}
 
@@ -40,3 +60,32 @@ func TestHeader(t *testing.T) {
 		return v, nil
 	})
 }
+
+func TestHeaderMessagePriority(t *testing.T) {
+	h := messages.NewHeader(messages.NewHeaderFlags(2, 0, 1), 32, 0x11223344, 1, nil)
+	if h.HasMessagePriority() {
+		t.Fatal("got MessagePriority on new Header")
+	}
+
+	h.SetMessagePriority(3)
+	if !h.HasMessagePriority() || h.MessagePriority() != 3 {
+		t.Errorf("got %v, %d, want true, 3", h.HasMessagePriority(), h.MessagePriority())
+	}
+
+	b, err := h.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := messages.DecodeHeader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.HasMessagePriority() || decoded.MessagePriority() != 3 {
+		t.Errorf("got %v, %d after decoding, want true, 3", decoded.HasMessagePriority(), decoded.MessagePriority())
+	}
+
+	h.ClearMessagePriority()
+	if h.HasMessagePriority() || h.MessagePriority() != 0 {
+		t.Errorf("got %v, %d after clearing, want false, 0", h.HasMessagePriority(), h.MessagePriority())
+	}
+}
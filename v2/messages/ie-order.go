@@ -0,0 +1,225 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+var (
+	ieType      = reflect.TypeOf((*ies.IE)(nil))
+	ieSliceType = reflect.TypeOf([]*ies.IE(nil))
+)
+
+// ieRank is the position of an IE in the canonical order of a message.
+type ieRank struct {
+	field, group, sub int
+}
+
+func (r ieRank) less(o ieRank) bool {
+	if r.field != o.field {
+		return r.field < o.field
+	}
+	if r.group != o.group {
+		return r.group < o.group
+	}
+	return r.sub < o.sub
+}
+
+// ieLayout is the canonical order of the IEs in a message. The fields of the messages
+// are defined in the order of the tables in TS 29.274, which is used as the canonical
+// order of the IEs in them.
+type ieLayout struct {
+	fields     [][]*ies.IE
+	additional []*ies.IE
+
+	// index is the field of the IE by type and instance, and last is the last field
+	// of the type, after which the IEs of the type with other instances are placed.
+	index map[uint16]int
+	last  map[uint8]int
+
+	// privExt is the field of the Private Extension, before which the IEs not known
+	// to the message are placed.
+	privExt int
+}
+
+func newIELayout(m Message) *ieLayout {
+	l := &ieLayout{
+		index: map[uint16]int{},
+		last:  map[uint8]int{},
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(m))
+	if v.Kind() != reflect.Struct {
+		return l
+	}
+	if g, ok := m.(*Generic); ok {
+		l.additional = g.IEs
+		return l
+	}
+
+	l.privExt = -1
+	for n := 0; n < v.NumField(); n++ {
+		f := v.Type().Field(n)
+		if f.Anonymous {
+			continue
+		}
+
+		var fieldIEs []*ies.IE
+		switch f.Type {
+		case ieType:
+			if ie := v.Field(n).Interface().(*ies.IE); ie != nil {
+				fieldIEs = []*ies.IE{ie}
+			}
+		case ieSliceType:
+			if f.Name == "AdditionalIEs" {
+				l.additional = v.Field(n).Interface().([]*ies.IE)
+				continue
+			}
+			fieldIEs = v.Field(n).Interface().([]*ies.IE)
+		default:
+			continue
+		}
+
+		k := len(l.fields)
+		if f.Name == "PrivateExtension" {
+			l.privExt = k
+		}
+		for _, ie := range fieldIEs {
+			if ie == nil {
+				continue
+			}
+			key := uint16(ie.Type)<<8 | uint16(ie.Instance())
+			if _, ok := l.index[key]; !ok {
+				l.index[key] = k
+			}
+			l.last[ie.Type] = k
+		}
+		l.fields = append(l.fields, fieldIEs)
+	}
+	if l.privExt < 0 {
+		l.privExt = len(l.fields)
+	}
+	return l
+}
+
+// rank returns the position of ie in the canonical order.
+//
+// The IEs in the fields are placed in the order of the fields. The IEs of the type in
+// the fields with the other instances are placed after the last field of the type in
+// the order of instance, and the IEs of unknown types are placed before the Private
+// Extension in the order of type and instance.
+func (l *ieLayout) rank(ie *ies.IE) ieRank {
+	if k, ok := l.index[uint16(ie.Type)<<8|uint16(ie.Instance())]; ok {
+		return ieRank{field: k}
+	}
+	if k, ok := l.last[ie.Type]; ok {
+		return ieRank{field: k, group: 1, sub: int(ie.Instance())}
+	}
+	return ieRank{field: l.privExt, group: -1, sub: int(ie.Type)<<8 | int(ie.Instance())}
+}
+
+// CanonicalIEs returns the IEs in m in the canonical order, which is the order of the
+// table of the message in TS 29.274. The repeated IEs, e.g., Bearer Contexts, keep the
+// order they are set in m.
+//
+// The IEs in AdditionalIEs are grouped with the ones of the same type, and the ones of
+// the type not known to m are placed before the Private Extension. The IEs in Generic
+// are sorted in the order of type and instance.
+func CanonicalIEs(m Message) []*ies.IE {
+	l := newIELayout(m)
+
+	var list []*ies.IE
+	for _, f := range l.fields {
+		for _, ie := range f {
+			if ie != nil {
+				list = append(list, ie)
+			}
+		}
+	}
+	for _, ie := range l.additional {
+		if ie != nil {
+			list = append(list, ie)
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return l.rank(list[i]).less(l.rank(list[j]))
+	})
+	return list
+}
+
+// SerializeCanonical returns the byte sequence generated from m, with the IEs in the
+// canonical order given by CanonicalIEs.
+//
+// The messages defined in this package serialize the IEs in the fields in the canonical
+// order, but the ones in AdditionalIEs are always placed at the end. Some equipment
+// rejects such messages, which can be sent with this instead.
+func SerializeCanonical(m Message) ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := SerializeCanonicalTo(m, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SerializeCanonicalTo serializes m into b with the IEs in the canonical order.
+// b should have the length of m.Len() or longer.
+func SerializeCanonicalTo(m Message, b []byte) error {
+	if err := m.SerializeTo(b); err != nil {
+		return err
+	}
+
+	list := CanonicalIEs(m)
+	var l int
+	for _, ie := range list {
+		l += ie.Len()
+	}
+	offset := m.Len() - l
+	if offset < 0 {
+		return ErrInvalidLength
+	}
+	for _, ie := range list {
+		if err := ie.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += ie.Len()
+	}
+	return nil
+}
+
+// VerifyIEOrder checks if the IEs in the message in b are in the canonical order given
+// by CanonicalIEs. The error wrapping ErrIEOutOfOrder is returned with the first IE out
+// of order if not.
+func VerifyIEOrder(b []byte) error {
+	m, err := Decode(b)
+	if err != nil {
+		return err
+	}
+	h, err := DecodeHeader(b)
+	if err != nil {
+		return err
+	}
+	list, err := ies.DecodeMultiIEs(h.Payload)
+	if err != nil {
+		return err
+	}
+
+	l := newIELayout(m)
+	for n := 1; n < len(list); n++ {
+		if l.rank(list[n]).less(l.rank(list[n-1])) {
+			return fmt.Errorf(
+				"%w: %s (instance %d) after %s (instance %d) in %s", ErrIEOutOfOrder,
+				list[n].Name(), list[n].Instance(), list[n-1].Name(), list[n-1].Instance(),
+				m.MessageTypeName(),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func ieKeys(list []*ies.IE) [][2]uint8 {
+	var keys [][2]uint8
+	for _, ie := range list {
+		keys = append(keys, [2]uint8{ie.Type, ie.Instance()})
+	}
+	return keys
+}
+
+func TestCanonicalIEs(t *testing.T) {
+	csReq := messages.NewCreateSessionRequest(
+		0, 1,
+		ies.NewPrivateExtension(10415, []byte{0x01}),
+		ies.New(200, 0, []byte{0x01}),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", "").WithInstance(5),
+		ies.NewBearerContext(ies.NewEPSBearerID(5)),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewBearerContext(ies.NewEPSBearerID(6)).WithInstance(1),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", ""),
+		ies.NewIMSI("123451234567890"),
+	)
+
+	want := [][2]uint8{
+		{ies.IMSI, 0},
+		{ies.FullyQualifiedTEID, 0},
+		{ies.FullyQualifiedTEID, 5},
+		{ies.AccessPointName, 0},
+		{ies.BearerContext, 0},
+		{ies.BearerContext, 1},
+		{200, 0},
+		{ies.PrivateExtension, 0},
+	}
+	if diff := cmp.Diff(ieKeys(messages.CanonicalIEs(csReq)), want); diff != "" {
+		t.Fatal(diff)
+	}
+
+	b, err := messages.Serialize(csReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := messages.VerifyIEOrder(b); !errors.Is(err, messages.ErrIEOutOfOrder) {
+		t.Errorf("got %v, want %v", err, messages.ErrIEOutOfOrder)
+	}
+
+	canonical, err := messages.SerializeCanonical(csReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(canonical) != len(b) {
+		t.Fatalf("got length %d, want %d", len(canonical), len(b))
+	}
+	if err := messages.VerifyIEOrder(canonical); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCanonicalIEsRepeated(t *testing.T) {
+	req := messages.NewCreateIndirectDataForwardingTunnelRequest(
+		0, 1,
+		ies.NewBearerContext(ies.NewEPSBearerID(5)),
+		ies.NewRecovery(1),
+		ies.NewBearerContext(ies.NewEPSBearerID(6)),
+		ies.NewIMSI("123451234567890"),
+	)
+	req.AdditionalIEs = append(req.AdditionalIEs, ies.NewIMSI("123451234567890").WithInstance(1))
+
+	got := messages.CanonicalIEs(req)
+	want := [][2]uint8{
+		{ies.IMSI, 0},
+		{ies.IMSI, 1},
+		{ies.BearerContext, 0},
+		{ies.BearerContext, 0},
+		{ies.Recovery, 0},
+	}
+	if diff := cmp.Diff(ieKeys(got), want); diff != "" {
+		t.Fatal(diff)
+	}
+	if ebi := got[3].BearerContext()[0].EPSBearerID(); ebi != 6 {
+		t.Errorf("got EBI %d, want 6", ebi)
+	}
+}
+
+func TestCanonicalIEsGeneric(t *testing.T) {
+	g := messages.NewGeneric(
+		messages.MsgTypeEchoRequest, 0, 1,
+		ies.NewPrivateExtension(10415, []byte{0x01}),
+		ies.NewRecovery(1),
+		ies.NewIMSI("123451234567890"),
+	)
+	want := [][2]uint8{{ies.IMSI, 0}, {ies.Recovery, 0}, {ies.PrivateExtension, 0}}
+	if diff := cmp.Diff(ieKeys(messages.CanonicalIEs(g)), want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestVerifyIEOrderInOrder(t *testing.T) {
+	for _, m := range []messages.Message{
+		messages.NewEchoRequest(1, ies.NewRecovery(0x80)),
+		messages.NewCreateSessionResponse(
+			0xffffffff, 1,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0xffffffff, "1.1.1.3", ""),
+			ies.NewPDNAddressAllocation("2.2.2.2"),
+			ies.NewBearerContext(
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewEPSBearerID(0x05),
+			),
+		),
+		messages.NewDeleteSessionRequest(0xffffffff, 1, ies.NewEPSBearerID(0x05)),
+	} {
+		b, err := messages.Serialize(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := messages.VerifyIEOrder(b); err != nil {
+			t.Errorf("%s: %v", m.MessageTypeName(), err)
+		}
+
+		canonical, err := messages.SerializeCanonical(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(canonical, b); diff != "" {
+			t.Errorf("%s: %s", m.MessageTypeName(), diff)
+		}
+	}
+}
@@ -7,7 +7,11 @@ Package messages provides encoding/decoding feature of GTPv2 protocol.
 */
 package messages
 
+//go:generate go run ../../internal/genconst -file $GOFILE -type MessageType -block "Message Type definitions." -trim MsgType
+
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 )
 
@@ -271,6 +275,25 @@ const (
 	_ // 248-255: Reserved for others
 )
 
+// MessageType is the type of the message type values, which gives the name with String().
+//
+// The MsgType* constants are kept uint8 to be given to the functions directly.
+// Convert them or the value retrieved from Message into MessageType to get the name.
+type MessageType uint8
+
+// ExpectsResponse reports whether the message of the type is answered by the peer
+// with the same sequence number, i.e., it is the Request, Notification or Command,
+// or the Context Response acknowledged with Context Acknowledge.
+func ExpectsResponse(msgType uint8) bool {
+	name := MessageType(msgType).String()
+	for _, suffix := range []string{"Request", "Notification", "Command"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return msgType == MsgTypeContextResponse
+}
+
 // Message is an interface that defines GTPv2 messages.
 type Message interface {
 	SerializeTo([]byte) error
@@ -283,6 +306,9 @@ type Message interface {
 	SetTEID(uint32)
 	Sequence() uint32
 	SetSequenceNumber(uint32)
+	HasMessagePriority() bool
+	MessagePriority() uint8
+	SetMessagePriority(uint8)
 }
 
 // Serialize returns the byte sequence generated from a Message instance.
@@ -296,8 +322,21 @@ func Serialize(m Message) ([]byte, error) {
 	return b, nil
 }
 
+// DetachPayload makes the Payload of the message a copy of its own after SerializeTo,
+// which otherwise refers to the buffer given, so that the buffer can be reused for
+// other messages while the message is kept.
+func DetachPayload(m Message) {
+	if h, ok := m.(interface{ detachPayload() }); ok {
+		h.detachPayload()
+	}
+}
+
 // Decode decodes the given bytes as Message.
 func Decode(b []byte) (Message, error) {
+	if len(b) < 2 {
+		return nil, ErrTooShortToDecode
+	}
+
 	var m Message
 
 	switch b[1] {
@@ -333,6 +372,22 @@ func Decode(b []byte) (Message, error) {
 		m = &ContextResponse{}
 	case MsgTypeContextAcknowledge:
 		m = &ContextAcknowledge{}
+	case MsgTypeCreateIndirectDataForwardingTunnelRequest:
+		m = &CreateIndirectDataForwardingTunnelRequest{}
+	case MsgTypeCreateIndirectDataForwardingTunnelResponse:
+		m = &CreateIndirectDataForwardingTunnelResponse{}
+	case MsgTypeDeleteIndirectDataForwardingTunnelRequest:
+		m = &DeleteIndirectDataForwardingTunnelRequest{}
+	case MsgTypeDeleteIndirectDataForwardingTunnelResponse:
+		m = &DeleteIndirectDataForwardingTunnelResponse{}
+	case MsgTypeReleaseAccessBearersRequest:
+		m = &ReleaseAccessBearersRequest{}
+	case MsgTypeReleaseAccessBearersResponse:
+		m = &ReleaseAccessBearersResponse{}
+	case MsgTypeDownlinkDataNotification:
+		m = &DownlinkDataNotification{}
+	case MsgTypeDownlinkDataNotificationAcknowledge:
+		m = &DownlinkDataNotificationAcknowledge{}
 	default:
 		m = &Generic{}
 	}
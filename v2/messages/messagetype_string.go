@@ -0,0 +1,257 @@
+// Code generated by "genconst -file message.go -type MessageType -block \"Message Type definitions.\" -trim MsgType"; DO NOT EDIT.
+
+package messages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var namesMessageType = map[MessageType]string{
+	MessageType(MsgTypeEchoRequest):                                "EchoRequest",
+	MessageType(MsgTypeEchoResponse):                               "EchoResponse",
+	MessageType(MsgTypeVersionNotSupportedIndication):              "VersionNotSupportedIndication",
+	MessageType(MsgTypeDirectTransferRequest):                      "DirectTransferRequest",
+	MessageType(MsgTypeDirectTransferResponse):                     "DirectTransferResponse",
+	MessageType(MsgTypeNotificationRequest):                        "NotificationRequest",
+	MessageType(MsgTypeNotificationResponse):                       "NotificationResponse",
+	MessageType(MsgTypeRIMInformationTransfer):                     "RIMInformationTransfer",
+	MessageType(MsgTypeSRVCCPsToCsRequest):                         "SRVCCPsToCsRequest",
+	MessageType(MsgTypeSRVCCPsToCsResponse):                        "SRVCCPsToCsResponse",
+	MessageType(MsgTypeSRVCCPsToCsCompleteNotification):            "SRVCCPsToCsCompleteNotification",
+	MessageType(MsgTypeSRVCCPsToCsCompleteAcknowledge):             "SRVCCPsToCsCompleteAcknowledge",
+	MessageType(MsgTypeSRVCCPsToCsCancelNotification):              "SRVCCPsToCsCancelNotification",
+	MessageType(MsgTypeSRVCCPsToCsCancelAcknowledge):               "SRVCCPsToCsCancelAcknowledge",
+	MessageType(MsgTypeSRVCCCsToPsRequest):                         "SRVCCCsToPsRequest",
+	MessageType(MsgTypeCreateSessionRequest):                       "CreateSessionRequest",
+	MessageType(MsgTypeCreateSessionResponse):                      "CreateSessionResponse",
+	MessageType(MsgTypeModifyBearerRequest):                        "ModifyBearerRequest",
+	MessageType(MsgTypeModifyBearerResponse):                       "ModifyBearerResponse",
+	MessageType(MsgTypeDeleteSessionRequest):                       "DeleteSessionRequest",
+	MessageType(MsgTypeDeleteSessionResponse):                      "DeleteSessionResponse",
+	MessageType(MsgTypeChangeNotificationRequest):                  "ChangeNotificationRequest",
+	MessageType(MsgTypeChangeNotificationResponse):                 "ChangeNotificationResponse",
+	MessageType(MsgTypeRemoteUEReportNotification):                 "RemoteUEReportNotification",
+	MessageType(MsgTypeRemoteUEReportAcknowledge):                  "RemoteUEReportAcknowledge",
+	MessageType(MsgTypeModifyBearerCommand):                        "ModifyBearerCommand",
+	MessageType(MsgTypeModifyBearerFailureIndication):              "ModifyBearerFailureIndication",
+	MessageType(MsgTypeDeleteBearerCommand):                        "DeleteBearerCommand",
+	MessageType(MsgTypeDeleteBearerFailureIndication):              "DeleteBearerFailureIndication",
+	MessageType(MsgTypeBearerResourceCommand):                      "BearerResourceCommand",
+	MessageType(MsgTypeBearerResourceFailureIndication):            "BearerResourceFailureIndication",
+	MessageType(MsgTypeDownlinkDataNotificationFailureIndication):  "DownlinkDataNotificationFailureIndication",
+	MessageType(MsgTypeTraceSessionActivation):                     "TraceSessionActivation",
+	MessageType(MsgTypeTraceSessionDeactivation):                   "TraceSessionDeactivation",
+	MessageType(MsgTypeStopPagingIndication):                       "StopPagingIndication",
+	MessageType(MsgTypeCreateBearerRequest):                        "CreateBearerRequest",
+	MessageType(MsgTypeCreateBearerResponse):                       "CreateBearerResponse",
+	MessageType(MsgTypeUpdateBearerRequest):                        "UpdateBearerRequest",
+	MessageType(MsgTypeUpdateBearerResponse):                       "UpdateBearerResponse",
+	MessageType(MsgTypeDeleteBearerRequest):                        "DeleteBearerRequest",
+	MessageType(MsgTypeDeleteBearerResponse):                       "DeleteBearerResponse",
+	MessageType(MsgTypeDeletePDNConnectionSetRequest):              "DeletePDNConnectionSetRequest",
+	MessageType(MsgTypeDeletePDNConnectionSetResponse):             "DeletePDNConnectionSetResponse",
+	MessageType(MsgTypePGWDownlinkTriggeringNotification):          "PGWDownlinkTriggeringNotification",
+	MessageType(MsgTypePGWDownlinkTriggeringAcknowledge):           "PGWDownlinkTriggeringAcknowledge",
+	MessageType(MsgTypeIdentificationRequest):                      "IdentificationRequest",
+	MessageType(MsgTypeIdentificationResponse):                     "IdentificationResponse",
+	MessageType(MsgTypeContextRequest):                             "ContextRequest",
+	MessageType(MsgTypeContextResponse):                            "ContextResponse",
+	MessageType(MsgTypeContextAcknowledge):                         "ContextAcknowledge",
+	MessageType(MsgTypeForwardRelocationRequest):                   "ForwardRelocationRequest",
+	MessageType(MsgTypeForwardRelocationResponse):                  "ForwardRelocationResponse",
+	MessageType(MsgTypeForwardRelocationCompleteNotification):      "ForwardRelocationCompleteNotification",
+	MessageType(MsgTypeForwardRelocationCompleteAcknowledge):       "ForwardRelocationCompleteAcknowledge",
+	MessageType(MsgTypeForwardAccessContextNotification):           "ForwardAccessContextNotification",
+	MessageType(MsgTypeForwardAccessContextAcknowledge):            "ForwardAccessContextAcknowledge",
+	MessageType(MsgTypeRelocationCancelRequest):                    "RelocationCancelRequest",
+	MessageType(MsgTypeRelocationCancelResponse):                   "RelocationCancelResponse",
+	MessageType(MsgTypeConfigurationTransferTunnel):                "ConfigurationTransferTunnel",
+	MessageType(MsgTypeDetachNotification):                         "DetachNotification",
+	MessageType(MsgTypeDetachAcknowledge):                          "DetachAcknowledge",
+	MessageType(MsgTypeCSPagingIndication):                         "CSPagingIndication",
+	MessageType(MsgTypeRANInformationRelay):                        "RANInformationRelay",
+	MessageType(MsgTypeAlertMMENotification):                       "AlertMMENotification",
+	MessageType(MsgTypeAlertMMEAcknowledge):                        "AlertMMEAcknowledge",
+	MessageType(MsgTypeUEActivityNotification):                     "UEActivityNotification",
+	MessageType(MsgTypeUEActivityAcknowledge):                      "UEActivityAcknowledge",
+	MessageType(MsgTypeISRStatusIndication):                        "ISRStatusIndication",
+	MessageType(MsgTypeUERegistrationQueryRequest):                 "UERegistrationQueryRequest",
+	MessageType(MsgTypeUERegistrationQueryResponse):                "UERegistrationQueryResponse",
+	MessageType(MsgTypeCreateForwardingTunnelRequest):              "CreateForwardingTunnelRequest",
+	MessageType(MsgTypeCreateForwardingTunnelResponse):             "CreateForwardingTunnelResponse",
+	MessageType(MsgTypeSuspendNotification):                        "SuspendNotification",
+	MessageType(MsgTypeSuspendAcknowledge):                         "SuspendAcknowledge",
+	MessageType(MsgTypeResumeNotification):                         "ResumeNotification",
+	MessageType(MsgTypeResumeAcknowledge):                          "ResumeAcknowledge",
+	MessageType(MsgTypeCreateIndirectDataForwardingTunnelRequest):  "CreateIndirectDataForwardingTunnelRequest",
+	MessageType(MsgTypeCreateIndirectDataForwardingTunnelResponse): "CreateIndirectDataForwardingTunnelResponse",
+	MessageType(MsgTypeDeleteIndirectDataForwardingTunnelRequest):  "DeleteIndirectDataForwardingTunnelRequest",
+	MessageType(MsgTypeDeleteIndirectDataForwardingTunnelResponse): "DeleteIndirectDataForwardingTunnelResponse",
+	MessageType(MsgTypeReleaseAccessBearersRequest):                "ReleaseAccessBearersRequest",
+	MessageType(MsgTypeReleaseAccessBearersResponse):               "ReleaseAccessBearersResponse",
+	MessageType(MsgTypeDownlinkDataNotification):                   "DownlinkDataNotification",
+	MessageType(MsgTypeDownlinkDataNotificationAcknowledge):        "DownlinkDataNotificationAcknowledge",
+	MessageType(MsgTypePGWRestartNotification):                     "PGWRestartNotification",
+	MessageType(MsgTypePGWRestartNotificationAcknowledge):          "PGWRestartNotificationAcknowledge",
+	MessageType(MsgTypeUpdatePDNConnectionSetRequest):              "UpdatePDNConnectionSetRequest",
+	MessageType(MsgTypeUpdatePDNConnectionSetResponse):             "UpdatePDNConnectionSetResponse",
+	MessageType(MsgTypeModifyAccessBearersRequest):                 "ModifyAccessBearersRequest",
+	MessageType(MsgTypeModifyAccessBearersResponse):                "ModifyAccessBearersResponse",
+	MessageType(MsgTypeMBMSSessionStartRequest):                    "MBMSSessionStartRequest",
+	MessageType(MsgTypeMBMSSessionStartResponse):                   "MBMSSessionStartResponse",
+	MessageType(MsgTypeMBMSSessionUpdateRequest):                   "MBMSSessionUpdateRequest",
+	MessageType(MsgTypeMBMSSessionUpdateResponse):                  "MBMSSessionUpdateResponse",
+	MessageType(MsgTypeMBMSSessionStopRequest):                     "MBMSSessionStopRequest",
+	MessageType(MsgTypeMBMSSessionStopResponse):                    "MBMSSessionStopResponse",
+	MessageType(MsgTypeSRVCCCsToPsResponse):                        "SRVCCCsToPsResponse",
+	MessageType(MsgTypeSRVCCCsToPsCompleteNotification):            "SRVCCCsToPsCompleteNotification",
+	MessageType(MsgTypeSRVCCCsToPsCompleteAcknowledge):             "SRVCCCsToPsCompleteAcknowledge",
+	MessageType(MsgTypeSRVCCCsToPsCancelNotification):              "SRVCCCsToPsCancelNotification",
+	MessageType(MsgTypeSRVCCCsToPsCancelAcknowledge):               "SRVCCCsToPsCancelAcknowledge",
+}
+
+var valuesMessageType = map[string]MessageType{
+	"AlertMMEAcknowledge":                        MessageType(MsgTypeAlertMMEAcknowledge),
+	"AlertMMENotification":                       MessageType(MsgTypeAlertMMENotification),
+	"BearerResourceCommand":                      MessageType(MsgTypeBearerResourceCommand),
+	"BearerResourceFailureIndication":            MessageType(MsgTypeBearerResourceFailureIndication),
+	"CSPagingIndication":                         MessageType(MsgTypeCSPagingIndication),
+	"ChangeNotificationRequest":                  MessageType(MsgTypeChangeNotificationRequest),
+	"ChangeNotificationResponse":                 MessageType(MsgTypeChangeNotificationResponse),
+	"ConfigurationTransferTunnel":                MessageType(MsgTypeConfigurationTransferTunnel),
+	"ContextAcknowledge":                         MessageType(MsgTypeContextAcknowledge),
+	"ContextRequest":                             MessageType(MsgTypeContextRequest),
+	"ContextResponse":                            MessageType(MsgTypeContextResponse),
+	"CreateBearerRequest":                        MessageType(MsgTypeCreateBearerRequest),
+	"CreateBearerResponse":                       MessageType(MsgTypeCreateBearerResponse),
+	"CreateForwardingTunnelRequest":              MessageType(MsgTypeCreateForwardingTunnelRequest),
+	"CreateForwardingTunnelResponse":             MessageType(MsgTypeCreateForwardingTunnelResponse),
+	"CreateIndirectDataForwardingTunnelRequest":  MessageType(MsgTypeCreateIndirectDataForwardingTunnelRequest),
+	"CreateIndirectDataForwardingTunnelResponse": MessageType(MsgTypeCreateIndirectDataForwardingTunnelResponse),
+	"CreateSessionRequest":                       MessageType(MsgTypeCreateSessionRequest),
+	"CreateSessionResponse":                      MessageType(MsgTypeCreateSessionResponse),
+	"DeleteBearerCommand":                        MessageType(MsgTypeDeleteBearerCommand),
+	"DeleteBearerFailureIndication":              MessageType(MsgTypeDeleteBearerFailureIndication),
+	"DeleteBearerRequest":                        MessageType(MsgTypeDeleteBearerRequest),
+	"DeleteBearerResponse":                       MessageType(MsgTypeDeleteBearerResponse),
+	"DeleteIndirectDataForwardingTunnelRequest":  MessageType(MsgTypeDeleteIndirectDataForwardingTunnelRequest),
+	"DeleteIndirectDataForwardingTunnelResponse": MessageType(MsgTypeDeleteIndirectDataForwardingTunnelResponse),
+	"DeletePDNConnectionSetRequest":              MessageType(MsgTypeDeletePDNConnectionSetRequest),
+	"DeletePDNConnectionSetResponse":             MessageType(MsgTypeDeletePDNConnectionSetResponse),
+	"DeleteSessionRequest":                       MessageType(MsgTypeDeleteSessionRequest),
+	"DeleteSessionResponse":                      MessageType(MsgTypeDeleteSessionResponse),
+	"DetachAcknowledge":                          MessageType(MsgTypeDetachAcknowledge),
+	"DetachNotification":                         MessageType(MsgTypeDetachNotification),
+	"DirectTransferRequest":                      MessageType(MsgTypeDirectTransferRequest),
+	"DirectTransferResponse":                     MessageType(MsgTypeDirectTransferResponse),
+	"DownlinkDataNotification":                   MessageType(MsgTypeDownlinkDataNotification),
+	"DownlinkDataNotificationAcknowledge":        MessageType(MsgTypeDownlinkDataNotificationAcknowledge),
+	"DownlinkDataNotificationFailureIndication":  MessageType(MsgTypeDownlinkDataNotificationFailureIndication),
+	"EchoRequest":                                MessageType(MsgTypeEchoRequest),
+	"EchoResponse":                               MessageType(MsgTypeEchoResponse),
+	"ForwardAccessContextAcknowledge":            MessageType(MsgTypeForwardAccessContextAcknowledge),
+	"ForwardAccessContextNotification":           MessageType(MsgTypeForwardAccessContextNotification),
+	"ForwardRelocationCompleteAcknowledge":       MessageType(MsgTypeForwardRelocationCompleteAcknowledge),
+	"ForwardRelocationCompleteNotification":      MessageType(MsgTypeForwardRelocationCompleteNotification),
+	"ForwardRelocationRequest":                   MessageType(MsgTypeForwardRelocationRequest),
+	"ForwardRelocationResponse":                  MessageType(MsgTypeForwardRelocationResponse),
+	"ISRStatusIndication":                        MessageType(MsgTypeISRStatusIndication),
+	"IdentificationRequest":                      MessageType(MsgTypeIdentificationRequest),
+	"IdentificationResponse":                     MessageType(MsgTypeIdentificationResponse),
+	"MBMSSessionStartRequest":                    MessageType(MsgTypeMBMSSessionStartRequest),
+	"MBMSSessionStartResponse":                   MessageType(MsgTypeMBMSSessionStartResponse),
+	"MBMSSessionStopRequest":                     MessageType(MsgTypeMBMSSessionStopRequest),
+	"MBMSSessionStopResponse":                    MessageType(MsgTypeMBMSSessionStopResponse),
+	"MBMSSessionUpdateRequest":                   MessageType(MsgTypeMBMSSessionUpdateRequest),
+	"MBMSSessionUpdateResponse":                  MessageType(MsgTypeMBMSSessionUpdateResponse),
+	"ModifyAccessBearersRequest":                 MessageType(MsgTypeModifyAccessBearersRequest),
+	"ModifyAccessBearersResponse":                MessageType(MsgTypeModifyAccessBearersResponse),
+	"ModifyBearerCommand":                        MessageType(MsgTypeModifyBearerCommand),
+	"ModifyBearerFailureIndication":              MessageType(MsgTypeModifyBearerFailureIndication),
+	"ModifyBearerRequest":                        MessageType(MsgTypeModifyBearerRequest),
+	"ModifyBearerResponse":                       MessageType(MsgTypeModifyBearerResponse),
+	"NotificationRequest":                        MessageType(MsgTypeNotificationRequest),
+	"NotificationResponse":                       MessageType(MsgTypeNotificationResponse),
+	"PGWDownlinkTriggeringAcknowledge":           MessageType(MsgTypePGWDownlinkTriggeringAcknowledge),
+	"PGWDownlinkTriggeringNotification":          MessageType(MsgTypePGWDownlinkTriggeringNotification),
+	"PGWRestartNotification":                     MessageType(MsgTypePGWRestartNotification),
+	"PGWRestartNotificationAcknowledge":          MessageType(MsgTypePGWRestartNotificationAcknowledge),
+	"RANInformationRelay":                        MessageType(MsgTypeRANInformationRelay),
+	"RIMInformationTransfer":                     MessageType(MsgTypeRIMInformationTransfer),
+	"ReleaseAccessBearersRequest":                MessageType(MsgTypeReleaseAccessBearersRequest),
+	"ReleaseAccessBearersResponse":               MessageType(MsgTypeReleaseAccessBearersResponse),
+	"RelocationCancelRequest":                    MessageType(MsgTypeRelocationCancelRequest),
+	"RelocationCancelResponse":                   MessageType(MsgTypeRelocationCancelResponse),
+	"RemoteUEReportAcknowledge":                  MessageType(MsgTypeRemoteUEReportAcknowledge),
+	"RemoteUEReportNotification":                 MessageType(MsgTypeRemoteUEReportNotification),
+	"ResumeAcknowledge":                          MessageType(MsgTypeResumeAcknowledge),
+	"ResumeNotification":                         MessageType(MsgTypeResumeNotification),
+	"SRVCCCsToPsCancelAcknowledge":               MessageType(MsgTypeSRVCCCsToPsCancelAcknowledge),
+	"SRVCCCsToPsCancelNotification":              MessageType(MsgTypeSRVCCCsToPsCancelNotification),
+	"SRVCCCsToPsCompleteAcknowledge":             MessageType(MsgTypeSRVCCCsToPsCompleteAcknowledge),
+	"SRVCCCsToPsCompleteNotification":            MessageType(MsgTypeSRVCCCsToPsCompleteNotification),
+	"SRVCCCsToPsRequest":                         MessageType(MsgTypeSRVCCCsToPsRequest),
+	"SRVCCCsToPsResponse":                        MessageType(MsgTypeSRVCCCsToPsResponse),
+	"SRVCCPsToCsCancelAcknowledge":               MessageType(MsgTypeSRVCCPsToCsCancelAcknowledge),
+	"SRVCCPsToCsCancelNotification":              MessageType(MsgTypeSRVCCPsToCsCancelNotification),
+	"SRVCCPsToCsCompleteAcknowledge":             MessageType(MsgTypeSRVCCPsToCsCompleteAcknowledge),
+	"SRVCCPsToCsCompleteNotification":            MessageType(MsgTypeSRVCCPsToCsCompleteNotification),
+	"SRVCCPsToCsRequest":                         MessageType(MsgTypeSRVCCPsToCsRequest),
+	"SRVCCPsToCsResponse":                        MessageType(MsgTypeSRVCCPsToCsResponse),
+	"StopPagingIndication":                       MessageType(MsgTypeStopPagingIndication),
+	"SuspendAcknowledge":                         MessageType(MsgTypeSuspendAcknowledge),
+	"SuspendNotification":                        MessageType(MsgTypeSuspendNotification),
+	"TraceSessionActivation":                     MessageType(MsgTypeTraceSessionActivation),
+	"TraceSessionDeactivation":                   MessageType(MsgTypeTraceSessionDeactivation),
+	"UEActivityAcknowledge":                      MessageType(MsgTypeUEActivityAcknowledge),
+	"UEActivityNotification":                     MessageType(MsgTypeUEActivityNotification),
+	"UERegistrationQueryRequest":                 MessageType(MsgTypeUERegistrationQueryRequest),
+	"UERegistrationQueryResponse":                MessageType(MsgTypeUERegistrationQueryResponse),
+	"UpdateBearerRequest":                        MessageType(MsgTypeUpdateBearerRequest),
+	"UpdateBearerResponse":                       MessageType(MsgTypeUpdateBearerResponse),
+	"UpdatePDNConnectionSetRequest":              MessageType(MsgTypeUpdatePDNConnectionSetRequest),
+	"UpdatePDNConnectionSetResponse":             MessageType(MsgTypeUpdatePDNConnectionSetResponse),
+	"VersionNotSupportedIndication":              MessageType(MsgTypeVersionNotSupportedIndication),
+}
+
+// String returns the name of the MessageType, or "MessageType(N)" if the value is unknown.
+func (v MessageType) String() string {
+	if name, ok := namesMessageType[v]; ok {
+		return name
+	}
+	return "MessageType(" + strconv.Itoa(int(v)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler to encode the MessageType with its name.
+func (v MessageType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler to decode the MessageType from its name.
+func (v *MessageType) UnmarshalText(b []byte) error {
+	parsed, err := MessageTypeFromString(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// MessageTypeFromString returns the MessageType whose name is s.
+//
+// s can also be in the "MessageType(N)" format that String() returns for the unknown values.
+func MessageTypeFromString(s string) (MessageType, error) {
+	if v, ok := valuesMessageType[s]; ok {
+		return v, nil
+	}
+
+	if strings.HasPrefix(s, "MessageType(") && strings.HasSuffix(s, ")") {
+		n, err := strconv.ParseUint(s[len("MessageType("):len(s)-1], 10, 8)
+		if err == nil {
+			return MessageType(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown MessageType: %s", s)
+}
@@ -188,10 +188,9 @@ func (m *ModifyBearerRequest) Serialize() ([]byte, error) {
 
 // SerializeTo serializes ModifyBearerRequest into bytes.
 func (m *ModifyBearerRequest) SerializeTo(b []byte) error {
-	if m.Header.Payload != nil {
-		m.Header.Payload = nil
+	if err := m.Header.preparePayload(b, m.Len()); err != nil {
+		return err
 	}
-	m.Header.Payload = make([]byte, m.Len()-m.Header.Len())
 
 	offset := 0
 	if ie := m.MEI; ie != nil {
@@ -151,10 +151,9 @@ func (m *ModifyBearerResponse) Serialize() ([]byte, error) {
 
 // SerializeTo serializes ModifyBearerResponse into bytes.
 func (m *ModifyBearerResponse) SerializeTo(b []byte) error {
-	if m.Header.Payload != nil {
-		m.Header.Payload = nil
+	if err := m.Header.preparePayload(b, m.Len()); err != nil {
+		return err
 	}
-	m.Header.Payload = make([]byte, m.Len()-m.Header.Len())
 
 	offset := 0
 	if ie := m.Cause; ie != nil {
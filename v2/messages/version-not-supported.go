@@ -45,10 +45,9 @@ func (v *VersionNotSupportedIndication) Serialize() ([]byte, error) {
 
 // SerializeTo serializes VersionNotSupportedIndication into bytes.
 func (v *VersionNotSupportedIndication) SerializeTo(b []byte) error {
-	if v.Header.Payload != nil {
-		v.Header.Payload = nil
+	if err := v.Header.preparePayload(b, v.Len()); err != nil {
+		return err
 	}
-	v.Header.Payload = make([]byte, v.Len()-v.Header.Len())
 
 	offset := 0
 	for _, ie := range v.AdditionalIEs {
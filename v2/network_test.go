@@ -5,6 +5,7 @@
 package v2_test
 
 import (
+	"bytes"
 	"net"
 	"testing"
 	"time"
@@ -126,3 +127,31 @@ func TestCreateSession(t *testing.T) {
 		t.Fatal("timed out while waiting for Create Session Response")
 	}
 }
+
+func TestSendMessageToKeepsPayload(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.62:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	peerAddr := &net.UDPAddr{IP: net.IP{127, 0, 0, 63}, Port: 2123}
+
+	first := messages.NewModifyBearerRequest(0x11111111, 1, ies.NewIMSI("123451234567890"))
+	if err := conn.SendMessageTo(first, peerAddr); err != nil {
+		t.Fatal(err)
+	}
+	want := bytes.Clone(first.Payload)
+
+	// serialized into the same buffer as the first one.
+	second := messages.NewModifyBearerRequest(0x22222222, 2, ies.NewMSISDN("8130900000000"))
+	if err := conn.SendMessageTo(second, peerAddr); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Payload, want) {
+		t.Errorf("Payload overwritten by the next message:\ngot:  %x\nwant: %x", first.Payload, want)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// nodeLevelMessages are the types of the messages sent with TEID 0 legitimately, as they
+// are not of any session or sent before the TEID of the peer is known. See TS 29.274 5.5.2.
+var nodeLevelMessages = map[uint8]bool{
+	messages.MsgTypeEchoRequest:                               true,
+	messages.MsgTypeEchoResponse:                              true,
+	messages.MsgTypeVersionNotSupportedIndication:             true,
+	messages.MsgTypeCreateSessionRequest:                      true,
+	messages.MsgTypeCreateIndirectDataForwardingTunnelRequest: true,
+	messages.MsgTypeIdentificationRequest:                     true,
+	messages.MsgTypeIdentificationResponse:                    true,
+	messages.MsgTypeContextRequest:                            true,
+	messages.MsgTypeForwardRelocationRequest:                  true,
+	messages.MsgTypeRelocationCancelRequest:                   true,
+	messages.MsgTypeDeletePDNConnectionSetRequest:             true,
+	messages.MsgTypeDeletePDNConnectionSetResponse:            true,
+	messages.MsgTypeConfigurationTransferTunnel:               true,
+	messages.MsgTypeRANInformationRelay:                       true,
+	messages.MsgTypePGWRestartNotification:                    true,
+	messages.MsgTypePGWRestartNotificationAcknowledge:         true,
+	messages.MsgTypePGWDownlinkTriggeringNotification:         true,
+	messages.MsgTypePGWDownlinkTriggeringAcknowledge:          true,
+	messages.MsgTypeMBMSSessionStartRequest:                   true,
+}
+
+// IsNodeLevel reports whether the message of msgType is the node-level one, which is sent
+// with TEID 0 legitimately, e.g., Echo Request, the initial Create Session Request and PGW
+// Restart Notification.
+func IsNodeLevel(msgType uint8) bool {
+	return nodeLevelMessages[msgType]
+}
+
+// AddNodeHandler adds a HandlerFunc for the node-level messages of msgType with TEID 0,
+// which is called instead of the one added with AddHandler. The messages of msgType with
+// TEID are still passed to the one added with AddHandler, e.g., Create Session Request on
+// the existing session, so that the ones not associated with any session are handled
+// separately.
+//
+// msgType should be the one IsNodeLevel reports true for, as the HandlerFunc for the
+// other types is never called.
+func (c *Conn) AddNodeHandler(msgType uint8, fn HandlerFunc) {
+	c.nodeHandlers.store(msgType, fn)
+}
+
+// AddNodeHandlers adds multiple node-level handler funcs at a time.
+//
+// See AddNodeHandler for detailed usage.
+func (c *Conn) AddNodeHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		c.nodeHandlers.store(msgType, fn)
+	}
+}
+
+// loadHandler returns the HandlerFunc for msg, which is the node-level one if msg is the
+// node-level message with TEID 0 and it is added.
+func (c *Conn) loadHandler(msg messages.Message) (HandlerFunc, bool) {
+	if msg.TEID() == 0 && IsNodeLevel(msg.MessageType()) {
+		if handle, ok := c.nodeHandlers.load(msg.MessageType()); ok {
+			return handle, true
+		}
+	}
+	return c.msgHandlerMap.load(msg.MessageType())
+}
+
+// EnableZeroTEIDValidation turns on the validation of the incoming requests with TEID 0,
+// in addition to the automatic validation. The session-level requests with TEID 0, i.e.,
+// the ones IsNodeLevel reports false for, are answered with CauseContextNotFound in the
+// response with TEID 0, and discarded with ErrZeroTEID sent to the error channel.
+//
+// This is disabled by default, as some peers send the session-level messages with TEID 0,
+// e.g., the ones which do not keep the TEID of this node.
+func (c *Conn) EnableZeroTEIDValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zeroTEIDValidationEnabled = true
+}
+
+// DisableZeroTEIDValidation turns off the validation of the incoming requests with TEID 0.
+func (c *Conn) DisableZeroTEIDValidation() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zeroTEIDValidationEnabled = false
+}
+
+// validateZeroTEID rejects msg if it is the session-level request with TEID 0.
+func (c *Conn) validateZeroTEID(senderAddr net.Addr, msg messages.Message) error {
+	c.mu.Lock()
+	enabled := c.zeroTEIDValidationEnabled
+	c.mu.Unlock()
+
+	msgType := msg.MessageType()
+	if !enabled || msg.TEID() != 0 || IsNodeLevel(msgType) || !messages.ExpectsResponse(msgType) {
+		return nil
+	}
+
+	// the response, acknowledge and failure indication have the type next to the request,
+	// notification and command respectively.
+	rsp := messages.NewGeneric(msgType+1, 0, 0, ies.NewCause(CauseContextNotFound, 0, 0, 0, nil))
+	if err := c.RespondTo(senderAddr, msg, rsp); err != nil {
+		return err
+	}
+	return ErrZeroTEID
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestNodeLevelHandler(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.46:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pktConn, err := net.ListenPacket("udp", srvAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pktConn.Close()
+	errCh := make(chan error, 10)
+	srvConn := v2.ServeConn(pktConn, 0, errCh)
+	defer srvConn.Close()
+	srvConn.DisableValidation()
+
+	nodeCh, sessCh := make(chan uint32, 1), make(chan uint32, 1)
+	for _, msgType := range []uint8{messages.MsgTypeCreateSessionRequest, messages.MsgTypeEchoRequest} {
+		srvConn.AddNodeHandler(msgType, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+			nodeCh <- msg.TEID()
+			return nil
+		})
+	}
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		sessCh <- msg.TEID()
+		return nil
+	})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.47:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	for _, tc := range []struct {
+		description string
+		msg         messages.Message
+		want        chan uint32
+	}{
+		{"node-level", messages.NewCreateSessionRequest(0, 1, ies.NewIMSI("123451234567890")), nodeCh},
+		{"session-level", messages.NewCreateSessionRequest(0x11111111, 2, ies.NewIMSI("123451234567890")), sessCh},
+		{"echo", messages.NewEchoRequest(3, ies.NewRecovery(0)), nodeCh},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			b, err := messages.Serialize(tc.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := peer.WriteTo(b, srvAddr); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case <-nodeCh:
+				if tc.want != nodeCh {
+					t.Error("node-level handler called")
+				}
+			case <-sessCh:
+				if tc.want != sessCh {
+					t.Error("session-level handler called")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("handler not called")
+			}
+		})
+	}
+}
+
+func TestZeroTEIDValidation(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.48:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pktConn, err := net.ListenPacket("udp", srvAddr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pktConn.Close()
+	errCh := make(chan error, 10)
+	srvConn := v2.ServeConn(pktConn, 0, errCh)
+	defer srvConn.Close()
+	srvConn.EnableZeroTEIDValidation()
+
+	rcvCh := make(chan uint8, 1)
+	for _, msgType := range []uint8{messages.MsgTypeCreateSessionRequest, messages.MsgTypeModifyBearerRequest} {
+		srvConn.AddHandler(msgType, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+			rcvCh <- msg.MessageType()
+			return nil
+		})
+	}
+
+	peer, err := net.ListenPacket("udp", "127.0.0.49:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	send := func(msg messages.Message) {
+		t.Helper()
+		b, err := messages.Serialize(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// node-level message is accepted with TEID 0.
+	send(messages.NewCreateSessionRequest(0, 1, ies.NewIMSI("123451234567890")))
+	select {
+	case got := <-rcvCh:
+		if got != messages.MsgTypeCreateSessionRequest {
+			t.Errorf("got %d", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Create Session Request with TEID 0 not handled")
+	}
+
+	// session-level message is rejected with TEID 0.
+	send(messages.NewModifyBearerRequest(0, 2, ies.NewEPSBearerID(5)))
+	if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	mbRsp, ok := msg.(*messages.ModifyBearerResponse)
+	if !ok {
+		t.Fatalf("got %s, want ModifyBearerResponse", msg.MessageTypeName())
+	}
+	if mbRsp.TEID() != 0 || mbRsp.Sequence() != 2 {
+		t.Errorf("got TEID %#x, sequence %d", mbRsp.TEID(), mbRsp.Sequence())
+	}
+	if got := mbRsp.Cause.Cause(); got != v2.CauseContextNotFound {
+		t.Errorf("got Cause %s, want %s", v2.Cause(got), v2.Cause(v2.CauseContextNotFound))
+	}
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, v2.ErrZeroTEID) {
+			t.Errorf("got %v, want %v", err, v2.ErrZeroTEID)
+		}
+	case <-time.After(time.Second):
+		t.Error("no error reported")
+	}
+	select {
+	case <-rcvCh:
+		t.Error("handler called for the request rejected")
+	default:
+	}
+}
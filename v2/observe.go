@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/gtppcap"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Direction definitions for the messages observed.
+const (
+	DirectionInbound  = gtppcap.DirectionInbound
+	DirectionOutbound = gtppcap.DirectionOutbound
+)
+
+// MessageObserverFunc is a callback to receive the messages sent and received on Conn,
+// with the direction and the address of the peer.
+//
+// MessageObserverFunc is called synchronously before the handler of the message received
+// is called, and after the message is sent. It should return quickly not to slow down the
+// signaling, and must not modify the message.
+type MessageObserverFunc func(c *Conn, direction uint8, peer net.Addr, msg messages.Message)
+
+// SetMessageObserver sets the observer of the messages sent with SendMessageTo and the
+// methods built on it, and received on Conn. Calling it again replaces the current one,
+// and nil removes it.
+func (c *Conn) SetMessageObserver(fn MessageObserverFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgObserver = fn
+}
+
+func (c *Conn) observe(direction uint8, peer net.Addr, msg messages.Message) {
+	c.mu.Lock()
+	fn := c.msgObserver
+	c.mu.Unlock()
+	if fn != nil {
+		fn(c, direction, peer, msg)
+	}
+}
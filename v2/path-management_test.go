@@ -0,0 +1,125 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestEchoUnderHandlerBacklog(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.30:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	blockCh := make(chan struct{})
+	defer close(blockCh)
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		<-blockCh
+		return nil
+	})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.31:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	for seq := range uint32(100) {
+		b, err := messages.NewCreateSessionRequest(0, seq, ies.NewIMSI("123451234567890")).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := messages.NewEchoRequest(100, ies.NewRecovery(0)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Echo Response not received: %v", err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageType() != messages.MsgTypeEchoResponse || msg.Sequence() != 100 {
+		t.Errorf("got %s with sequence %d, want EchoResponse with 100", msg.MessageTypeName(), msg.Sequence())
+	}
+}
+
+func TestEchoOfOtherVersion(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.65:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	peer, err := net.ListenPacket("udp", "127.0.0.66:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	b, err := messages.NewEchoRequest(1, ies.NewRecovery(0)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[0] = b[0]&0x1f | 3<<5 // version 3
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Version Not Supported Indication not received: %v", err)
+	}
+	msg, err := messages.Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageType() != messages.MsgTypeVersionNotSupportedIndication || msg.Sequence() != 1 {
+		t.Errorf("got %s with sequence %d, want VersionNotSupportedIndication with 1", msg.MessageTypeName(), msg.Sequence())
+	}
+
+	// the Echo Request is discarded without being answered.
+	if err := peer.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := peer.ReadFrom(buf); err == nil {
+		t.Error("got unexpected message")
+	}
+}
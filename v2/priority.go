@@ -0,0 +1,31 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+type messagePriorityKey struct{}
+
+// WithMessagePriority returns the copy of ctx with the Message Priority, which is set in
+// the header of the request sent by Exchange or CreateSessions with the ctx returned.
+//
+// The Message Priority is the relative priority of the signalling from 0 to 15, where 0
+// is the highest, used by the peer to deprioritize the requests in congestion, e.g., the
+// ones from the delay-tolerant devices. The requests sent with SendMessageTo can have it
+// with (*messages.Header).SetMessagePriority instead.
+func WithMessagePriority(ctx context.Context, mp uint8) context.Context {
+	return context.WithValue(ctx, messagePriorityKey{}, mp&0x0f)
+}
+
+// setMessagePriority sets the Message Priority in ctx to msg, if any.
+func setMessagePriority(ctx context.Context, msg messages.Message) {
+	if mp, ok := ctx.Value(messagePriorityKey{}).(uint8); ok {
+		msg.SetMessagePriority(mp)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// EnableRecoveryInclusion turns on the automatic inclusion of the Recovery IE with
+// the RestartCounter of the Conn, in the first message sent to each peer and in Echo
+// Request/Response, as TS 29.274 recommends to include it when contacting the peer for
+// the first time so that the peer detects the restart of this node.
+//
+// The Recovery IE is set to the message given to SendMessageTo or RespondTo only if
+// the message has the Recovery IE and it is not set yet, e.g., Create Session Request,
+// Create Session Response and Modify Bearer Request. The other messages sent first are
+// sent as they are, and the Recovery IE is included in the next one that can have it.
+func (c *Conn) EnableRecoveryInclusion() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recoveryInclusionEnabled = true
+}
+
+// DisableRecoveryInclusion turns off the automatic inclusion of the Recovery IE.
+// This is the default, and the Recovery IE is included only by the users except in
+// Echo Request/Response sent by Conn.
+func (c *Conn) DisableRecoveryInclusion() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recoveryInclusionEnabled = false
+}
+
+// Restarts returns the number of restarts in uint8, which is the RestartCounter
+// included in the Recovery IE.
+func (c *Conn) Restarts() uint8 {
+	return c.RestartCounter
+}
+
+// includeRecovery sets the Recovery IE to msg if enabled, msg can have it and it is not
+// set yet. Echo always gets it, and the others get it only when first is true.
+//
+// It reports whether msg carries the Recovery IE, so that the caller considers the peer
+// contacted only after the Recovery IE is sent to it.
+func (c *Conn) includeRecovery(msg messages.Message, first bool) bool {
+	var (
+		recovery **ies.IE
+		echo     bool
+	)
+	switch m := msg.(type) {
+	case *messages.EchoRequest:
+		recovery, echo = &m.Recovery, true
+	case *messages.EchoResponse:
+		recovery, echo = &m.Recovery, true
+	default:
+		recovery = recoveryField(msg)
+	}
+	if recovery == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	enabled := c.recoveryInclusionEnabled
+	c.mu.Unlock()
+	if enabled && *recovery == nil && (first || echo) {
+		*recovery = ies.NewRecovery(c.Restarts())
+	}
+	return *recovery != nil
+}
+
+// recoveryField returns the pointer to the Recovery IE field of msg, or nil if msg does
+// not have it.
+func recoveryField(msg messages.Message) **ies.IE {
+	switch m := msg.(type) {
+	case *messages.CreateSessionRequest:
+		return &m.Recovery
+	case *messages.CreateSessionResponse:
+		return &m.Recovery
+	case *messages.ModifyBearerRequest:
+		return &m.Recovery
+	case *messages.ModifyBearerResponse:
+		return &m.Recovery
+	case *messages.DeleteSessionResponse:
+		return &m.Recovery
+	case *messages.CreateBearerResponse:
+		return &m.Recovery
+	case *messages.DeleteBearerResponse:
+		return &m.Recovery
+	case *messages.ReleaseAccessBearersResponse:
+		return &m.Recovery
+	case *messages.DownlinkDataNotificationAcknowledge:
+		return &m.Recovery
+	case *messages.CreateIndirectDataForwardingTunnelRequest:
+		return &m.Recovery
+	case *messages.CreateIndirectDataForwardingTunnelResponse:
+		return &m.Recovery
+	case *messages.DeleteIndirectDataForwardingTunnelResponse:
+		return &m.Recovery
+	default:
+		return nil
+	}
+}
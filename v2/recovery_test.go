@@ -0,0 +1,85 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestRecoveryInclusion(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.38:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.39:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 3, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	// the Recovery IE received, or nil if not included.
+	recoveryCh := make(chan *ies.IE, 1)
+	handler := func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		switch m := msg.(type) {
+		case *messages.DeleteSessionRequest:
+			recoveryCh <- nil
+		case *messages.CreateSessionRequest:
+			recoveryCh <- m.Recovery
+		case *messages.ModifyBearerRequest:
+			recoveryCh <- m.Recovery
+		}
+		return nil
+	}
+	srvConn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeDeleteSessionRequest: handler,
+		messages.MsgTypeCreateSessionRequest: handler,
+		messages.MsgTypeModifyBearerRequest:  handler,
+	})
+	cliConn.EnableRecoveryInclusion()
+
+	cases := []struct {
+		description string
+		msg         messages.Message
+		want        bool
+	}{
+		// Delete Session Request cannot have Recovery, so the peer is not contacted yet.
+		{"CannotHave", messages.NewDeleteSessionRequest(0, 1, ies.NewEPSBearerID(5)), false},
+		{"First", messages.NewCreateSessionRequest(0, 2, ies.NewIMSI("123451234567890")), true},
+		{"Second", messages.NewModifyBearerRequest(0, 3, ies.NewEPSBearerID(5)), false},
+	}
+
+	for _, c := range cases {
+		if err := cliConn.SendMessageTo(c.msg, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-recoveryCh:
+			if (got != nil) != c.want {
+				t.Errorf("%s: got Recovery %v, want included: %v", c.description, got, c.want)
+			}
+			if got != nil && got.Recovery() != 3 {
+				t.Errorf("%s: got Restart Counter %d, want 3", c.description, got.Recovery())
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("%s: timed out while waiting for the message", c.description)
+		}
+	}
+}
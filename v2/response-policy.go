@@ -0,0 +1,90 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/sendbuf"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// gtpcPort is the well-known UDP port of GTP-C.
+const gtpcPort = 2123
+
+// ResponsePolicy represents where Conn sends the responses to the requests.
+type ResponsePolicy uint8
+
+// ResponsePolicy definitions.
+const (
+	// ResponseToSource sends the responses to the source IP:Port of the requests,
+	// as required by TS 29.274 even if the peer sends them from an ephemeral port.
+	ResponseToSource ResponsePolicy = iota
+
+	// ResponseToGTPCPort sends the responses to port 2123 of the source IP of the
+	// requests, for the peers that listen only on the well-known port.
+	ResponseToGTPCPort
+)
+
+// SetResponsePolicy sets where the responses sent with RespondTo, VersionNotSupportedIndication
+// and the default Echo handler go. ResponseToSource is used by default.
+func (c *Conn) SetResponsePolicy(policy ResponsePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responsePolicy = policy
+}
+
+// EnablePeerTracking turns on tracking the endpoint of the peers. The PeerAddr of the
+// Session is updated to the endpoint the response goes to by ResponsePolicy, when the
+// message with the TEID of the Session comes from the endpoint other than PeerAddr,
+// so that the requests sent afterwards go to where the peer actually is.
+//
+// This is disabled by default, as the requests should be sent to the well-known port
+// even if the peer sends its requests from an ephemeral port.
+func (c *Conn) EnablePeerTracking() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peerTrackingEnabled = true
+}
+
+// DisablePeerTracking turns off tracking the endpoint of the peers.
+func (c *Conn) DisablePeerTracking() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.peerTrackingEnabled = false
+}
+
+// responseAddr returns the address to send the response to the request from raddr.
+func (c *Conn) responseAddr(raddr net.Addr) net.Addr {
+	c.mu.Lock()
+	policy := c.responsePolicy
+	c.mu.Unlock()
+
+	u, ok := raddr.(*net.UDPAddr)
+	if policy != ResponseToGTPCPort || !ok || u.Port == gtpcPort {
+		return raddr
+	}
+	return &net.UDPAddr{IP: u.IP, Port: gtpcPort, Zone: u.Zone}
+}
+
+// trackPeer updates the PeerAddr of the Session with the TEID of the message if
+// the message comes from another endpoint.
+func (c *Conn) trackPeer(senderAddr net.Addr, msg messages.Message) {
+	c.mu.Lock()
+	enabled := c.peerTrackingEnabled
+	c.mu.Unlock()
+	if !enabled || msg.TEID() == 0 {
+		return
+	}
+
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return
+	}
+	peer := c.responseAddr(senderAddr)
+	if sess.PeerAddr == nil || sendbuf.KeyOf(sess.PeerAddr) != sendbuf.KeyOf(peer) {
+		sess.PeerAddr = peer
+	}
+}
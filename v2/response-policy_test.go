@@ -0,0 +1,146 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestResponsePolicy(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.18:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	// the peer sends the requests from an ephemeral port.
+	ephConn, err := net.ListenPacket("udp", "127.0.0.19:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ephConn.Close()
+	wkConn, err := net.ListenPacket("udp", "127.0.0.19:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wkConn.Close()
+
+	echo := func(seq uint32) {
+		t.Helper()
+		b, err := messages.NewEchoRequest(seq, ies.NewRecovery(0)).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ephConn.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	receive := func(conn net.PacketConn, seq uint32) {
+		t.Helper()
+		buf := make([]byte, 1500)
+		if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("response not received on %s: %v", conn.LocalAddr(), err)
+		}
+		msg, err := messages.Decode(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Sequence() != seq {
+			t.Errorf("got sequence %d, want %d", msg.Sequence(), seq)
+		}
+	}
+
+	echo(1)
+	receive(ephConn, 1)
+
+	srvConn.SetResponsePolicy(v2.ResponseToGTPCPort)
+	echo(2)
+	receive(wkConn, 2)
+}
+
+func TestPeerTracking(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.20:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerAddr, err := net.ResolveUDPAddr("udp", "127.0.0.21:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	ephConn, err := net.ListenPacket("udp", "127.0.0.21:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ephConn.Close()
+
+	sess := v2.NewSession(peerAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	teid := srvConn.NewTEID()
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
+	srvConn.AddSession(sess)
+
+	rcvCh := make(chan net.Addr, 1)
+	srvConn.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		s, err := c.GetSessionByTEID(msg.TEID())
+		if err != nil {
+			return err
+		}
+		rcvCh <- s.PeerAddr
+		return nil
+	})
+
+	send := func(seq uint32) net.Addr {
+		t.Helper()
+		b, err := messages.NewModifyBearerRequest(teid, seq).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ephConn.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case addr := <-rcvCh:
+			return addr
+		case <-time.After(time.Second):
+			t.Fatal("message not handled")
+		}
+		return nil
+	}
+
+	// not tracked by default.
+	if got := send(1); got.String() != peerAddr.String() {
+		t.Errorf("PeerAddr changed to %s", got)
+	}
+
+	srvConn.EnablePeerTracking()
+	if got := send(2); got.String() != ephConn.LocalAddr().String() {
+		t.Errorf("got PeerAddr %s, want %s", got, ephConn.LocalAddr())
+	}
+
+	// only the IP is tracked if the responses go to the well-known port.
+	srvConn.SetResponsePolicy(v2.ResponseToGTPCPort)
+	if got := send(3); got.String() != peerAddr.String() {
+		t.Errorf("got PeerAddr %s, want %s", got, peerAddr)
+	}
+}
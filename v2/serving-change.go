@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// ServingChange is the change of the Serving Network and RAT Type of a Session.
+type ServingChange struct {
+	// OldMCC, OldMNC and OldRATType are the ones known before the change.
+	OldMCC, OldMNC string
+	OldRATType     uint8
+
+	// MCC, MNC and RATType are the ones after the change. The ones not changed are
+	// the same as the old ones.
+	MCC, MNC string
+	RATType  uint8
+}
+
+// PLMNChanged reports whether the Serving Network is changed.
+func (c *ServingChange) PLMNChanged() bool {
+	return c.OldMCC != c.MCC || c.OldMNC != c.MNC
+}
+
+// RATTypeChanged reports whether the RAT Type is changed.
+func (c *ServingChange) RATTypeChanged() bool {
+	return c.OldRATType != c.RATType
+}
+
+// ServingChangeHandlerFunc is a handler called when the Serving Network or RAT Type of
+// a session is changed by Modify Bearer Request received on Conn.
+//
+// The handler is called synchronously before the HandlerFunc of Modify Bearer Request,
+// with the Session already updated. It should return quickly not to block the handlers
+// of the messages.
+type ServingChangeHandlerFunc func(c *Conn, sess *Session, change *ServingChange)
+
+// SetServingChangeHandler sets the handler called when the Serving Network or RAT Type of
+// a session is changed. Calling it again replaces the current one, and nil removes it.
+//
+// The Sessions are updated with the Serving Network and RAT Type in Modify Bearer Request
+// regardless of the handler.
+func (c *Conn) SetServingChangeHandler(fn ServingChangeHandlerFunc) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+	c.servingChangeHandler = fn
+}
+
+// UpdateServing updates the Serving Network and RAT Type in the Location of the Session
+// with the ServingNetwork and RATType IEs given, and returns the change, or nil if not
+// changed. The IEs can be nil if not present.
+//
+// The values not known yet, e.g., the Session created without them, are just recorded
+// and not reported as the change.
+func (s *Session) UpdateServing(servingNetwork, ratType *ies.IE) *ServingChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Subscriber == nil {
+		return nil
+	}
+	if s.Location == nil {
+		s.Location = &Location{}
+	}
+
+	change := &ServingChange{
+		OldMCC: s.MCC, OldMNC: s.MNC, OldRATType: s.RATType,
+		MCC: s.MCC, MNC: s.MNC, RATType: s.RATType,
+	}
+	var changed bool
+	if servingNetwork != nil && servingNetwork.Type == ies.ServingNetwork {
+		mcc, mnc := servingNetwork.MCC(), servingNetwork.MNC()
+		if s.MCC != "" && (mcc != s.MCC || mnc != s.MNC) {
+			changed = true
+		}
+		change.MCC, change.MNC = mcc, mnc
+		s.MCC, s.MNC = mcc, mnc
+	}
+	if ratType != nil && ratType.Type == ies.RATType {
+		rat := ratType.RATType()
+		if s.RATType != 0 && rat != s.RATType {
+			changed = true
+		}
+		change.RATType = rat
+		s.RATType = rat
+	}
+
+	if !changed {
+		return nil
+	}
+	return change
+}
+
+// updateServing updates the Session of the TEID in Modify Bearer Request with the Serving
+// Network and RAT Type in it, and calls the ServingChangeHandlerFunc if changed.
+func (c *Conn) updateServing(msg messages.Message) {
+	mbReq, ok := msg.(*messages.ModifyBearerRequest)
+	if !ok || (mbReq.ServingNetwork == nil && mbReq.RATType == nil) {
+		return
+	}
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return
+	}
+
+	change := sess.UpdateServing(mbReq.ServingNetwork, mbReq.RATType)
+	if change == nil {
+		return
+	}
+
+	c.sessMu.RLock()
+	fn := c.servingChangeHandler
+	c.sessMu.RUnlock()
+	if fn != nil {
+		fn(c, sess, change)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestUpdateServing(t *testing.T) {
+	sess := v2.NewSession(&net.UDPAddr{}, &v2.Subscriber{IMSI: "123451234567890"})
+
+	// unknown values are just recorded.
+	if got := sess.UpdateServing(ies.NewServingNetwork("123", "45"), ies.NewRATType(v2.RATTypeEUTRAN)); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+	if got := sess.UpdateServing(ies.NewServingNetwork("123", "45"), nil); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+
+	got := sess.UpdateServing(nil, ies.NewRATType(v2.RATTypeNR))
+	want := &v2.ServingChange{
+		OldMCC: "123", OldMNC: "45", OldRATType: v2.RATTypeEUTRAN,
+		MCC: "123", MNC: "45", RATType: v2.RATTypeNR,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatal(diff)
+	}
+	if got.PLMNChanged() || !got.RATTypeChanged() {
+		t.Errorf("got PLMNChanged=%v, RATTypeChanged=%v", got.PLMNChanged(), got.RATTypeChanged())
+	}
+	if sess.RATType != v2.RATTypeNR {
+		t.Errorf("RATType is not updated: %d", sess.RATType)
+	}
+}
+
+func TestServingChangeHandler(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.36:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.37:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	sess := v2.NewSession(cliAddr, &v2.Subscriber{
+		IMSI:     "123451234567890",
+		Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN},
+	})
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+	srvConn.AddSession(sess)
+
+	changeCh := make(chan *v2.ServingChange, 1)
+	srvConn.SetServingChangeHandler(func(c *v2.Conn, s *v2.Session, change *v2.ServingChange) {
+		changeCh <- change
+	})
+	handledCh := make(chan struct{}, 1)
+	srvConn.AddHandler(
+		messages.MsgTypeModifyBearerRequest,
+		func(c *v2.Conn, cliAddr net.Addr, msg messages.Message) error {
+			handledCh <- struct{}{}
+			return nil
+		},
+	)
+
+	mbReq := messages.NewModifyBearerRequest(
+		0x11111111, 1,
+		ies.NewServingNetwork("234", "56"),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+	)
+	if err := cliConn.SendMessageTo(mbReq, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &v2.ServingChange{
+		OldMCC: "123", OldMNC: "45", OldRATType: v2.RATTypeEUTRAN,
+		MCC: "234", MNC: "56", RATType: v2.RATTypeEUTRAN,
+	}
+	select {
+	case got := <-changeCh:
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for the change")
+	}
+	select {
+	case <-handledCh:
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for the handler")
+	}
+	if sess.MCC != "234" || sess.MNC != "56" {
+		t.Errorf("Serving Network is not updated: %s-%s", sess.MCC, sess.MNC)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+// SessionEvent is the type of change of the sessions on Conn.
+type SessionEvent uint8
+
+// SessionEvent definitions.
+const (
+	_ SessionEvent = iota
+	SessionEventAdded
+	SessionEventRemoved
+)
+
+// SessionEventHandlerFunc is a handler called when a session is added to or removed
+// from Conn by AddSession or RemoveSession.
+//
+// The handler is called synchronously after the session is added or removed. It should
+// return quickly not to block the handlers of the messages.
+type SessionEventHandlerFunc func(c *Conn, event SessionEvent, sess *Session)
+
+// SetSessionEventHandler sets the handler called when a session is added or removed.
+// Calling it again replaces the current one, and nil removes it.
+func (c *Conn) SetSessionEventHandler(fn SessionEventHandlerFunc) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+	c.sessEventHandler = fn
+}
+
+func (c *Conn) notifySessionEvent(fn SessionEventHandlerFunc, event SessionEvent, sess *Session) {
+	if fn != nil {
+		fn(c, event, sess)
+	}
+}
@@ -27,9 +27,23 @@ type Location struct {
 // Subscriber is a subscriber that belongs to a GTPv2 session.
 type Subscriber struct {
 	IMSI, MSISDN, IMEI string
+
+	// SVN is the 2-digit Software Version Number of the UE, which is set if the MEI is
+	// the IMEISV, while IMEI has the 15-digit IMEI with the check digit in any case.
+	SVN string
+
 	*Location
 }
 
+// IMEISV returns the 16-digit IMEISV built from the IMEI and SVN, or empty string if
+// either of them is not known.
+func (s *Subscriber) IMEISV() string {
+	if len(s.IMEI) < 14 || s.SVN == "" {
+		return ""
+	}
+	return s.IMEI[:14] + s.SVN
+}
+
 // Session is a GTPv2 Session.
 type Session struct {
 	mu       sync.Mutex
@@ -37,6 +51,12 @@ type Session struct {
 	*teidMap
 	*bearerMap
 	inflightCh chan messages.Message
+	removedCh  chan struct{}
+
+	usageReports       []*ies.SecondaryRATUsageDataReportPayload
+	uli                *ies.UserLocationInformationPayload
+	csgReportingAction CSGReportingAction
+	timers             map[string]*sessionTimer
 
 	// PeerAddr is a net.Addr of the peer of the Session.
 	PeerAddr net.Addr
@@ -61,13 +81,16 @@ func NewSession(peerAddr net.Addr, sub *Subscriber) *Session {
 		bearerMap:  newBearerMap("default", &Bearer{QoSProfile: &QoSProfile{}}),
 		Subscriber: sub,
 		inflightCh: make(chan messages.Message),
+		removedCh:  make(chan struct{}),
 	}
 
+	// the initial sequence number is drawn from crypto/rand not to be predicted, within
+	// the 24 bits in the header.
 	u32buf := make([]byte, 4)
 	if _, err := rand.Read(u32buf); err != nil {
 		u32buf = []byte{0x00, 0x00, 0x00, 0x00}
 	}
-	s.Sequence = binary.BigEndian.Uint32(u32buf)
+	s.Sequence = binary.BigEndian.Uint32(u32buf) & 0xffffff
 
 	return s
 }
@@ -156,8 +179,49 @@ func (s *Session) Deactivate() error {
 	return nil
 }
 
+// AddSecondaryRATUsageReports accumulates the reports in the Secondary RAT Usage Data
+// Report IEs given on the Session. The other IEs and the malformed ones are ignored.
+//
+// This is called by Conn for the reports in Delete Session Request and Delete Bearer
+// Response, so that they can be retrieved when the Session is removed.
+func (s *Session) AddSecondaryRATUsageReports(ie ...*ies.IE) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		if r := i.SecondaryRATUsageDataReport(); r != nil {
+			s.usageReports = append(s.usageReports, r)
+		}
+	}
+}
+
+// SecondaryRATUsageReports returns the Secondary RAT usage reports accumulated on the Session.
+func (s *Session) SecondaryRATUsageReports() []*ies.SecondaryRATUsageDataReportPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make([]*ies.SecondaryRATUsageDataReportPayload, len(s.usageReports))
+	copy(reports, s.usageReports)
+	return reports
+}
+
+// takeSecondaryRATUsageReports returns the reports accumulated and clears them.
+func (s *Session) takeSecondaryRATUsageReports() []*ies.SecondaryRATUsageDataReportPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := s.usageReports
+	s.usageReports = nil
+	return reports
+}
+
 // IsActive reports whether a Session is active or not.
 func (s *Session) IsActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.isActive
 }
 
@@ -166,6 +230,21 @@ func (s *Session) AddTEID(ifType uint8, teid uint32) {
 	s.teidMap.store(ifType, teid)
 }
 
+// addFTEID adds the TEID in the F-TEID IE with its InterfaceType, or returns the error
+// if the F-TEID is malformed.
+func (s *Session) addFTEID(fteid *ies.IE) error {
+	ifType, err := fteid.ParseInterfaceType()
+	if err != nil {
+		return err
+	}
+	teid, err := fteid.ParseTEID()
+	if err != nil {
+		return err
+	}
+	s.AddTEID(ifType, teid)
+	return nil
+}
+
 // GetTEID returns TEID associated with InterfaceType given.
 func (s *Session) GetTEID(ifType uint8) (uint32, error) {
 	if teid, ok := s.teidMap.load(ifType); ok {
@@ -174,12 +253,26 @@ func (s *Session) GetTEID(ifType uint8) (uint32, error) {
 	return 0, ErrTEIDNotFound
 }
 
+// TEIDs returns all the TEIDs associated with the Session by InterfaceType.
+func (s *Session) TEIDs() map[uint8]uint32 {
+	teids := map[uint8]uint32{}
+	s.teidMap.rangeWithFunc(func(i, t interface{}) bool {
+		teids[i.(uint8)] = t.(uint32)
+		return true
+	})
+	return teids
+}
+
 // PassMessageTo passes the message (typically "triggerred message") to the session
 // expecting to receive it.
+//
+// It fails with ErrSessionRemoved if the session is removed from the Conn meanwhile.
 func PassMessageTo(s *Session, msg messages.Message, timeout time.Duration) error {
 	select {
 	case s.inflightCh <- msg:
 		return nil
+	case <-s.removed():
+		return ErrSessionRemoved
 	case <-time.After(timeout):
 		return ErrTimeout
 	}
@@ -187,15 +280,35 @@ func PassMessageTo(s *Session, msg messages.Message, timeout time.Duration) erro
 
 // WaitMessage waits for a message to come.
 // Unless the user does not use PassMessage() func, this always fails with timeout.
+//
+// It fails with ErrSessionRemoved if the session is removed from the Conn meanwhile,
+// not to keep waiting for the message that never comes.
 func (s *Session) WaitMessage(timeout time.Duration) (messages.Message, error) {
 	select {
 	case msg := <-s.inflightCh:
 		return msg, nil
+	case <-s.removed():
+		return nil, ErrSessionRemoved
 	case <-time.After(timeout):
 		return nil, ErrTimeout
 	}
 }
 
+func (s *Session) removed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.removedCh
+}
+
+// abandonWaiters wakes up the callers of WaitMessage and PassMessageTo waiting now.
+// The ones called afterwards wait as usual, as the session may be added again.
+func (s *Session) abandonWaiters() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.removedCh)
+	s.removedCh = make(chan struct{})
+}
+
 // AddBearer adds a Bearer to Session with arbitrary name given.
 //
 // In the single-bearer environment it is not used, as a bearer named "default" is
@@ -235,6 +348,16 @@ func (s *Session) SetDefaultBearer(bearer *Bearer) {
 	s.bearerMap.store("default", bearer)
 }
 
+// Bearers returns all the Bearers registered in Session by name.
+func (s *Session) Bearers() map[string]*Bearer {
+	bearers := map[string]*Bearer{}
+	s.bearerMap.rangeWithFunc(func(name, bearer interface{}) bool {
+		bearers[name.(string)] = bearer.(*Bearer)
+		return true
+	})
+	return bearers
+}
+
 // LookupBearerByName looks up Bearer registered in Session by name.
 func (s *Session) LookupBearerByName(name string) (*Bearer, error) {
 	if br, ok := s.bearerMap.load(name); ok {
@@ -300,7 +423,7 @@ func (s *Session) LookupEBIByTEID(teid uint32) uint8 {
 	var ebi uint8
 	s.bearerMap.rangeWithFunc(func(name, bearer interface{}) bool {
 		br := bearer.(*Bearer)
-		if teid == br.teidIn || teid == br.teidOut {
+		if teid == br.IncomingTEID() || teid == br.OutgoingTEID() {
 			ebi = br.EBI
 			return false
 		}
@@ -0,0 +1,222 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// SnapshotVersion is the version of the format written by SnapshotSessions.
+//
+// RestoreSessions accepts the snapshots of this version only, and the version is
+// incremented when the format changes incompatibly.
+const SnapshotVersion = 1
+
+// snapshot is the format of the sessions written by SnapshotSessions in JSON.
+type snapshot struct {
+	Version        int                `json:"version"`
+	RestartCounter uint8              `json:"restart_counter"`
+	Sessions       []*sessionSnapshot `json:"sessions"`
+}
+
+type sessionSnapshot struct {
+	IMSI               string                                    `json:"imsi"`
+	MSISDN             string                                    `json:"msisdn,omitempty"`
+	IMEI               string                                    `json:"imei,omitempty"`
+	SVN                string                                    `json:"svn,omitempty"`
+	Location           *Location                                 `json:"location,omitempty"`
+	PeerAddr           string                                    `json:"peer_addr,omitempty"`
+	Sequence           uint32                                    `json:"sequence"`
+	Active             bool                                      `json:"active"`
+	TEIDs              map[uint8]uint32                          `json:"teids"`
+	Bearers            map[string]*bearerSnapshot                `json:"bearers"`
+	Timers             map[string]time.Time                      `json:"timers,omitempty"`
+	ULI                *ies.UserLocationInformationPayload       `json:"uli,omitempty"`
+	CSGReportingAction CSGReportingAction                        `json:"csg_reporting_action,omitempty"`
+	UsageReports       []*ies.SecondaryRATUsageDataReportPayload `json:"secondary_rat_usage_reports,omitempty"`
+}
+
+type bearerSnapshot struct {
+	EBI          uint8       `json:"ebi"`
+	APN          string      `json:"apn,omitempty"`
+	SubscriberIP string      `json:"subscriber_ip,omitempty"`
+	ChargingID   uint32      `json:"charging_id"`
+	QoSProfile   *QoSProfile `json:"qos,omitempty"`
+	RemoteAddr   string      `json:"remote_addr,omitempty"`
+	IncomingTEID uint32      `json:"incoming_teid"`
+	OutgoingTEID uint32      `json:"outgoing_teid"`
+}
+
+// SnapshotSessions writes the sessions on the Conn to w, with the TEIDs, the bearers,
+// the peer addresses and the sequence numbers, which can be restored with
+// RestoreSessions after restarting the process. The last known ULI, the CSG
+// Information Reporting Action and the Secondary RAT usage reports accumulated are
+// also written.
+//
+// RestartCounter of the Conn is also written, so that the peers do not take the
+// restart as the loss of the sessions. The format is JSON with SnapshotVersion.
+func (c *Conn) SnapshotSessions(w io.Writer) error {
+	c.sessMu.RLock()
+	snap := &snapshot{
+		Version:        SnapshotVersion,
+		RestartCounter: c.RestartCounter,
+		Sessions:       make([]*sessionSnapshot, 0, len(c.Sessions)),
+	}
+	for _, sess := range c.Sessions {
+		snap.Sessions = append(snap.Sessions, sess.snapshot())
+	}
+	c.sessMu.RUnlock()
+
+	sort.Slice(snap.Sessions, func(i, j int) bool {
+		return snap.Sessions[i].IMSI < snap.Sessions[j].IMSI
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snap)
+}
+
+// RestoreSessions reads the sessions written by SnapshotSessions from r, and adds
+// them to the Conn with AddSession. RestartCounter of the Conn is also restored.
+// The timers on the sessions are restarted with the TimerFunc set by SetTimerHandler.
+//
+// Nothing is added if the snapshot is malformed or the version is not supported.
+// The sessions with the same IMSI as the ones restored are replaced.
+func (c *Conn) RestoreSessions(r io.Reader) error {
+	snap := &snapshot{}
+	if err := json.NewDecoder(r).Decode(snap); err != nil {
+		return err
+	}
+	if snap.Version != SnapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSnapshot, snap.Version)
+	}
+
+	sessions := make([]*Session, 0, len(snap.Sessions))
+	for _, s := range snap.Sessions {
+		sess, err := s.restore()
+		if err != nil {
+			return fmt.Errorf("failed to restore session of %s: %w", s.IMSI, err)
+		}
+		sessions = append(sessions, sess)
+	}
+
+	c.mu.Lock()
+	c.RestartCounter = snap.RestartCounter
+	c.mu.Unlock()
+	for i, sess := range sessions {
+		c.AddSession(sess)
+		c.restartTimers(sess, snap.Sessions[i].Timers)
+	}
+	return nil
+}
+
+// MarshalSession returns the session in the format of the sessions in the snapshot
+// written by SnapshotSessions, to pass the sessions one by one to another process.
+//
+// The sessions marshaled are restored by RestoreSessions as the elements of
+// "sessions" in the snapshot.
+func MarshalSession(sess *Session) ([]byte, error) {
+	return json.Marshal(sess.snapshot())
+}
+
+func (s *Session) snapshot() *sessionSnapshot {
+	snap := &sessionSnapshot{
+		Sequence: s.Sequence,
+		Active:   s.IsActive(),
+		TEIDs:    s.TEIDs(),
+		Bearers:  map[string]*bearerSnapshot{},
+	}
+	if timers := s.Timers(); len(timers) != 0 {
+		snap.Timers = timers
+	}
+	if reports := s.SecondaryRATUsageReports(); len(reports) != 0 {
+		snap.UsageReports = reports
+	}
+	snap.ULI = s.ULI()
+	snap.CSGReportingAction = s.CSGReportingAction()
+	if s.Subscriber != nil {
+		snap.IMSI, snap.MSISDN, snap.IMEI, snap.SVN = s.IMSI, s.MSISDN, s.IMEI, s.SVN
+		snap.Location = s.Location
+	}
+	if s.PeerAddr != nil {
+		snap.PeerAddr = s.PeerAddr.String()
+	}
+
+	for name, br := range s.Bearers() {
+		bs := &bearerSnapshot{
+			EBI:          br.EBI,
+			APN:          br.APN,
+			SubscriberIP: br.SubscriberIP,
+			ChargingID:   br.ChargingID,
+			QoSProfile:   br.QoSProfile,
+			IncomingTEID: br.IncomingTEID(),
+			OutgoingTEID: br.OutgoingTEID(),
+		}
+		if raddr := br.RemoteAddress(); raddr != nil {
+			bs.RemoteAddr = raddr.String()
+		}
+		snap.Bearers[name] = bs
+	}
+	return snap
+}
+
+func (s *sessionSnapshot) restore() (*Session, error) {
+	peerAddr, err := resolveSnapshotAddr(s.PeerAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := NewSession(peerAddr, &Subscriber{
+		IMSI: s.IMSI, MSISDN: s.MSISDN, IMEI: s.IMEI, SVN: s.SVN, Location: s.Location,
+	})
+	sess.Sequence = s.Sequence
+	sess.uli = s.ULI
+	sess.csgReportingAction = s.CSGReportingAction
+	sess.usageReports = s.UsageReports
+	for ifType, teid := range s.TEIDs {
+		sess.AddTEID(ifType, teid)
+	}
+
+	for name, bs := range s.Bearers {
+		raddr, err := resolveSnapshotAddr(bs.RemoteAddr)
+		if err != nil {
+			return nil, err
+		}
+		br := NewBearer(bs.EBI, bs.APN, bs.QoSProfile)
+		if br.QoSProfile == nil {
+			br.QoSProfile = &QoSProfile{}
+		}
+		br.SubscriberIP = bs.SubscriberIP
+		br.ChargingID = bs.ChargingID
+		br.SetIncomingTEID(bs.IncomingTEID)
+		br.SetOutgoingTEID(bs.OutgoingTEID)
+		if raddr != nil {
+			br.SetRemoteAddress(raddr)
+		}
+		sess.AddBearer(name, br)
+	}
+
+	if s.Active {
+		if err := sess.Activate(); err != nil {
+			return nil, err
+		}
+	}
+	return sess, nil
+}
+
+// resolveSnapshotAddr returns the UDP address in the snapshot, or nil if empty.
+func resolveSnapshotAddr(addr string) (net.Addr, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	return net.ResolveUDPAddr("udp", addr)
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestSnapshotSessions(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.5:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerAddr, err := net.ResolveUDPAddr("udp", "127.0.0.6:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := v2.ListenAndServe(laddr, 3, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sess := v2.NewSession(peerAddr, &v2.Subscriber{
+		IMSI: "123451234567890", MSISDN: "8130900000000", IMEI: "123456780000000", SVN: "01",
+		Location: &v2.Location{MCC: "123", MNC: "45", RATType: v2.RATTypeEUTRAN, TAI: 1, ECI: 2},
+	})
+	sess.Sequence = 100
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x22222222)
+
+	bearer := sess.GetDefaultBearer()
+	bearer.EBI = 5
+	bearer.APN = "some.apn.example"
+	bearer.SubscriberIP = "10.0.0.1"
+	bearer.ChargingID = 1
+	bearer.QoSProfile = &v2.QoSProfile{PL: 2, QCI: 9, MBRUL: 1000, MBRDL: 2000}
+	bearer.SetIncomingTEID(0x33333333)
+	bearer.SetOutgoingTEID(0x44444444)
+	bearer.SetRemoteAddress(&net.UDPAddr{IP: net.IP{127, 0, 0, 7}, Port: 2152})
+	dedicated := v2.NewBearer(6, "some.apn.example", &v2.QoSProfile{QCI: 1})
+	sess.AddBearer("dedicated", dedicated)
+
+	uli := ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 1, 2, -1, -1)
+	if _, err := sess.UpdateULI(uli); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.SetCSGReportingAction(ies.NewCSGInformationReportingAction(ies.CSGReportingFlagCSG)); err != nil {
+		t.Fatal(err)
+	}
+	sess.AddSecondaryRATUsageReports(ies.NewSecondaryRATUsageDataReport(&ies.SecondaryRATUsageDataReportPayload{
+		IRPGW: true, EBI: 5,
+		StartTime: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2019, 1, 1, 1, 0, 0, 0, time.UTC),
+		UsageDL:   1000, UsageUL: 2000,
+	}))
+
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	conn.AddSession(sess)
+
+	buf := &bytes.Buffer{}
+	if err := conn.SnapshotSessions(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.RemoveSession(sess)
+	conn.RestartCounter = 0
+	if err := conn.RestoreSessions(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if conn.RestartCounter != 3 {
+		t.Errorf("RestartCounter not restored: %d", conn.RestartCounter)
+	}
+	restored, err := conn.GetSessionByTEID(0x11111111)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored.IsActive() {
+		t.Error("session not active")
+	}
+	if diff := cmp.Diff(restored.Subscriber, sess.Subscriber); diff != "" {
+		t.Error(diff)
+	}
+	if got := restored.IMEISV(); got != "1234567800000001" {
+		t.Errorf("got IMEISV %s, want 1234567800000001", got)
+	}
+	if diff := cmp.Diff(restored.TEIDs(), sess.TEIDs()); diff != "" {
+		t.Error(diff)
+	}
+	if restored.Sequence != 100 {
+		t.Errorf("Sequence not restored: %d", restored.Sequence)
+	}
+	if got := restored.PeerAddr.String(); got != peerAddr.String() {
+		t.Errorf("got %s, want %s", got, peerAddr)
+	}
+	if diff := cmp.Diff(restored.ULI(), sess.ULI()); diff != "" {
+		t.Error(diff)
+	}
+	if changed, err := restored.ChangedSince(uli); err != nil || changed != 0 {
+		t.Errorf("got ULI changed %s, %v", changed, err)
+	}
+	if got, want := restored.CSGReportingAction(), sess.CSGReportingAction(); got != want {
+		t.Errorf("got CSG Information Reporting Action %d, want %d", got, want)
+	}
+	if diff := cmp.Diff(restored.SecondaryRATUsageReports(), sess.SecondaryRATUsageReports()); diff != "" {
+		t.Error(diff)
+	}
+
+	for name, want := range sess.Bearers() {
+		got, err := restored.LookupBearerByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.IncomingTEID() != want.IncomingTEID() || got.OutgoingTEID() != want.OutgoingTEID() {
+			t.Errorf("TEIDs of %s not restored", name)
+		}
+		if (got.RemoteAddress() == nil) != (want.RemoteAddress() == nil) {
+			t.Errorf("remote address of %s not restored", name)
+		} else if got.RemoteAddress() != nil && got.RemoteAddress().String() != want.RemoteAddress().String() {
+			t.Errorf("got %s, want %s", got.RemoteAddress(), want.RemoteAddress())
+		}
+		if diff := cmp.Diff(
+			[]interface{}{got.EBI, got.APN, got.SubscriberIP, got.ChargingID, got.QoSProfile},
+			[]interface{}{want.EBI, want.APN, want.SubscriberIP, want.ChargingID, want.QoSProfile},
+		); diff != "" {
+			t.Errorf("bearer %s: %s", name, diff)
+		}
+	}
+}
+
+func TestRestoreSessionsVersion(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.8:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = conn.RestoreSessions(strings.NewReader(`{"version": 999, "sessions": [{"imsi": "123451234567890"}]}`))
+	if !errors.Is(err, v2.ErrUnsupportedSnapshot) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(conn.ListSessions()) != 0 {
+		t.Error("sessions restored from unsupported snapshot")
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package v2
+
+import (
+	"net"
+	"syscall"
+)
+
+// bindToDevice binds the socket to the network device or VRF specified by name,
+// so that the packets are sent and received only through the device. An empty
+// name removes the binding.
+func bindToDevice(conn net.PacketConn, name string) error {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return ErrNotSupported
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		serr = syscall.BindToDevice(int(fd), name)
+	}); err != nil {
+		return err
+	}
+	return serr
+}
@@ -0,0 +1,18 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package v2
+
+import "net"
+
+// bindToDevice is not supported on the platforms other than Linux.
+func bindToDevice(conn net.PacketConn, name string) error {
+	if name != "" {
+		return ErrNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// MaxStatsPeers is the maximum number of peers whose messages are counted separately
+// in ReceivedCauses and SentCauses of ConnStats. The messages from/to the peers beyond
+// it are counted with the empty Peer, not to let the garbage from random sources grow
+// the statistics unboundedly.
+const MaxStatsPeers = 4096
+
+// CauseKey is the key of the number of messages with Cause IE in ConnStats.
+type CauseKey struct {
+	// Peer is the address of the peer, or empty if the number of the peers exceeds
+	// MaxStatsPeers.
+	Peer        string
+	MessageType messages.MessageType
+	Cause       Cause
+}
+
+// ConnStats is the number of messages received and sent on a Conn, by message type.
+//
+// The messages that cannot be decoded are not counted as received.
+type ConnStats struct {
+	Received map[messages.MessageType]uint64
+	Sent     map[messages.MessageType]uint64
+
+	// ReceivedCauses and SentCauses are the number of messages with Cause IE, i.e.,
+	// the responses and a few requests such as Delete Bearer Request, by the peer, the
+	// message type and the value of the Cause, e.g., how many Create Session Requests
+	// are rejected with CauseAPNAccessDeniedNoSubscription by a P-GW.
+	ReceivedCauses map[CauseKey]uint64
+	SentCauses     map[CauseKey]uint64
+
+	// Transactions is the statistics of the transactions initiated by the requests sent.
+	Transactions TransactionStats
+}
+
+// Stats returns the number of messages received and sent on the Conn so far.
+func (c *Conn) Stats() *ConnStats {
+	return &ConnStats{
+		Received:       c.stats.received.snapshot(),
+		Sent:           c.stats.sent.snapshot(),
+		ReceivedCauses: c.stats.receivedCauses.snapshot(),
+		SentCauses:     c.stats.sentCauses.snapshot(),
+		Transactions:   c.txs.stats(),
+	}
+}
+
+type connStats struct {
+	received, sent             msgCounter
+	receivedCauses, sentCauses causeCounter
+}
+
+// msgCounter counts the messages by message type without locking.
+type msgCounter [256]atomic.Uint64
+
+func (m *msgCounter) add(msgType uint8) {
+	m[msgType].Add(1)
+}
+
+func (m *msgCounter) snapshot() map[messages.MessageType]uint64 {
+	counts := map[messages.MessageType]uint64{}
+	for i := range m {
+		if n := m[i].Load(); n > 0 {
+			counts[messages.MessageType(i)] = n
+		}
+	}
+	return counts
+}
+
+// causeCounter counts the messages with Cause IE by peer, message type and Cause.
+type causeCounter struct {
+	mu     sync.RWMutex
+	counts map[CauseKey]*atomic.Uint64
+	peers  map[string]struct{}
+}
+
+// add counts the serialized message b if it has Cause IE.
+func (c *causeCounter) add(peer net.Addr, b []byte) {
+	cause, ok := causeOf(b)
+	if !ok {
+		return
+	}
+	key := CauseKey{Peer: peer.String(), MessageType: messages.MessageType(b[1]), Cause: Cause(cause)}
+
+	c.mu.RLock()
+	n, ok := c.counts[key]
+	c.mu.RUnlock()
+	if !ok {
+		n = c.counter(key)
+	}
+	n.Add(1)
+}
+
+func (c *causeCounter) counter(key CauseKey) *atomic.Uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil {
+		c.counts = map[CauseKey]*atomic.Uint64{}
+		c.peers = map[string]struct{}{}
+	}
+	if _, ok := c.peers[key.Peer]; !ok {
+		if len(c.peers) >= MaxStatsPeers {
+			key.Peer = ""
+		} else {
+			c.peers[key.Peer] = struct{}{}
+		}
+	}
+	n, ok := c.counts[key]
+	if !ok {
+		n = &atomic.Uint64{}
+		c.counts[key] = n
+	}
+	return n
+}
+
+func (c *causeCounter) snapshot() map[CauseKey]uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counts := make(map[CauseKey]uint64, len(c.counts))
+	for key, n := range c.counts {
+		counts[key] = n.Load()
+	}
+	return counts
+}
+
+// causeOf returns the value of the Cause IE with instance 0 at the top level of the
+// serialized message b, without decoding the other IEs.
+func causeOf(b []byte) (uint8, bool) {
+	if len(b) < 8 {
+		return 0, false
+	}
+	offset := 8
+	if b[0]&0x08 != 0 {
+		offset = 12
+	}
+	end := min(4+int(binary.BigEndian.Uint16(b[2:4])), len(b))
+
+	for offset+4 < end {
+		typ, l := b[offset], int(binary.BigEndian.Uint16(b[offset+1:offset+3]))
+		if typ == ies.Cause && b[offset+3]&0x0f == 0 && l > 0 {
+			return b[offset+4], true
+		}
+		offset += 4 + l
+	}
+	return 0, false
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestCauseStats(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.25:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	peer, err := net.ListenPacket("udp", "127.0.0.26:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	if err := srvConn.SendMessageTo(
+		messages.NewDeleteSessionResponse(0, 1, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil)),
+		peer.LocalAddr(),
+	); err != nil {
+		t.Fatal(err)
+	}
+	sent := v2.CauseKey{
+		Peer:        peer.LocalAddr().String(),
+		MessageType: messages.MessageType(messages.MsgTypeDeleteSessionResponse),
+		Cause:       v2.Cause(v2.CauseContextNotFound),
+	}
+	if got := srvConn.Stats().SentCauses[sent]; got != 1 {
+		t.Errorf("got %d for %+v, want 1", got, sent)
+	}
+
+	for seq := uint32(1); seq <= 2; seq++ {
+		b, err := messages.NewCreateSessionResponse(
+			0, seq,
+			ies.NewCause(v2.CauseAPNAccessDeniedNoSubscription, 0, 0, 0, nil),
+		).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, srvAddr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	received := v2.CauseKey{
+		Peer:        peer.LocalAddr().String(),
+		MessageType: messages.MessageType(messages.MsgTypeCreateSessionResponse),
+		Cause:       v2.Cause(v2.CauseAPNAccessDeniedNoSubscription),
+	}
+	var got uint64
+	for range 100 {
+		if got = srvConn.Stats().ReceivedCauses[received]; got == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got != 2 {
+		t.Errorf("got %d for %+v, want 2", got, received)
+	}
+}
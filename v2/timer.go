@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"time"
+)
+
+// TimerDDNRetry is the name of the timer on the Session started by DownlinkDataNotifier
+// when Downlink Data Notification is sent, which suppresses the next notification
+// until it expires or is stopped.
+const TimerDDNRetry = "ddn-retry"
+
+// TimerFunc is called in its own goroutine with the Session and the name of the timer
+// when the timer expires.
+type TimerFunc func(sess *Session, name string)
+
+type sessionTimer struct {
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// StartTimer starts the timer with the name on the Session, which calls fn after d.
+// The timer running with the same name is stopped and replaced. fn can be nil to
+// just see whether the time has passed with TimerDeadline.
+//
+// The timers are stopped when the Session is removed from the Conn, and written in
+// the snapshot with the deadlines, to be restarted by RestoreSessions with the
+// TimerFunc set to the Conn with SetTimerHandler.
+func (s *Session) StartTimer(name string, d time.Duration, fn TimerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startTimer(name, time.Now().Add(d), fn)
+}
+
+// the caller must hold mu.
+func (s *Session) startTimer(name string, deadline time.Time, fn TimerFunc) {
+	if s.timers == nil {
+		s.timers = map[string]*sessionTimer{}
+	}
+	if old, ok := s.timers[name]; ok {
+		old.timer.Stop()
+	}
+
+	t := &sessionTimer{deadline: deadline}
+	t.timer = time.AfterFunc(time.Until(deadline), func() {
+		s.mu.Lock()
+		if s.timers[name] != t {
+			// stopped or replaced after expired.
+			s.mu.Unlock()
+			return
+		}
+		delete(s.timers, name)
+		s.mu.Unlock()
+
+		if fn != nil {
+			fn(s, name)
+		}
+	})
+	s.timers[name] = t
+}
+
+// StopTimer stops the timer with the name on the Session, and reports whether it
+// was running.
+func (s *Session) StopTimer(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.timers[name]
+	if !ok {
+		return false
+	}
+	t.timer.Stop()
+	delete(s.timers, name)
+	return true
+}
+
+// TimerDeadline returns the time when the timer with the name on the Session expires,
+// and false if it is not running.
+func (s *Session) TimerDeadline(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.timers[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	return t.deadline, true
+}
+
+// Timers returns the deadlines of the timers running on the Session by the names.
+func (s *Session) Timers() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timers := make(map[string]time.Time, len(s.timers))
+	for name, t := range s.timers {
+		timers[name] = t.deadline
+	}
+	return timers
+}
+
+// stopTimers stops all the timers on the Session.
+func (s *Session) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.timers {
+		t.timer.Stop()
+	}
+	s.timers = nil
+}
+
+// SetTimerHandler sets the TimerFunc called when the timer with the name on the
+// sessions restored by RestoreSessions expires, as the functions given to StartTimer
+// are not in the snapshot. The timers without the TimerFunc just expire.
+//
+// The timers expired while the process is down are called right after restored.
+func (c *Conn) SetTimerHandler(name string, fn TimerFunc) {
+	c.timerHandlers.Store(name, fn)
+}
+
+// restartTimers restarts the timers of the session in the snapshot.
+func (c *Conn) restartTimers(sess *Session, timers map[string]time.Time) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	for name, deadline := range timers {
+		var fn TimerFunc
+		if v, ok := c.timerHandlers.Load(name); ok {
+			fn = v.(TimerFunc)
+		}
+		sess.startTimer(name, deadline, fn)
+	}
+}
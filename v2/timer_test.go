@@ -0,0 +1,112 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+func TestSessionTimer(t *testing.T) {
+	sess := v2.NewSession(&net.UDPAddr{}, &v2.Subscriber{IMSI: "123451234567890"})
+
+	expired := make(chan string, 1)
+	sess.StartTimer("inactivity", 50*time.Millisecond, func(s *v2.Session, name string) {
+		if s != sess {
+			t.Error("unexpected session")
+		}
+		expired <- name
+	})
+	if _, ok := sess.TimerDeadline("inactivity"); !ok {
+		t.Error("timer not running")
+	}
+
+	select {
+	case name := <-expired:
+		if name != "inactivity" {
+			t.Errorf("got %s, want inactivity", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timer not expired")
+	}
+	if _, ok := sess.TimerDeadline("inactivity"); ok {
+		t.Error("timer still running after expired")
+	}
+
+	// replaced and stopped ones are never called.
+	sess.StartTimer("reporting", 50*time.Millisecond, func(*v2.Session, string) {
+		t.Error("replaced timer expired")
+	})
+	sess.StartTimer("reporting", time.Hour, nil)
+	if deadline, ok := sess.TimerDeadline("reporting"); !ok || time.Until(deadline) < 59*time.Minute {
+		t.Errorf("timer not replaced: %v, %v", deadline, ok)
+	}
+	if !sess.StopTimer("reporting") {
+		t.Error("timer not stopped")
+	}
+	if sess.StopTimer("reporting") {
+		t.Error("timer stopped twice")
+	}
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestSessionTimerSnapshot(t *testing.T) {
+	laddr, err := net.ResolveUDPAddr("udp", "127.0.0.58:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sess := v2.NewSession(&net.UDPAddr{IP: net.IP{127, 0, 0, 59}, Port: 2123}, &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS5S8PGWGTPC, 0x11111111)
+	conn.AddSession(sess)
+
+	sess.StartTimer("forwarding", time.Hour, nil)
+	sess.StartTimer("reporting", 50*time.Millisecond, func(*v2.Session, string) {
+		t.Error("timer of the removed session expired")
+	})
+	want := sess.Timers()
+
+	buf := &bytes.Buffer{}
+	if err := conn.SnapshotSessions(buf); err != nil {
+		t.Fatal(err)
+	}
+	conn.RemoveSession(sess)
+	if len(sess.Timers()) != 0 {
+		t.Error("timers not stopped on removal")
+	}
+
+	expired := make(chan *v2.Session, 1)
+	conn.SetTimerHandler("reporting", func(s *v2.Session, _ string) {
+		expired <- s
+	})
+	if err := conn.RestoreSessions(buf); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := conn.GetSessionByIMSI(sess.IMSI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deadline, ok := restored.TimerDeadline("forwarding"); !ok || !deadline.Equal(want["forwarding"]) {
+		t.Errorf("got %v, %v, want %v", deadline, ok, want["forwarding"])
+	}
+	select {
+	case s := <-expired:
+		if s != restored {
+			t.Error("unexpected session")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("restored timer not expired")
+	}
+}
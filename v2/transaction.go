@@ -0,0 +1,277 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"maps"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultTransactionTimeout is the default Timeout of TransactionLimits.
+const DefaultTransactionTimeout = 30 * time.Second
+
+// TransactionLimits is the limits of the transactions initiated by the requests sent
+// with SendMessageTo and the methods built on it, which are outstanding until the
+// message with the same sequence number comes from the peer.
+type TransactionLimits struct {
+	// MaxPerPeer is the maximum number of the outstanding transactions per peer, over
+	// which sending the request fails with ErrTooManyTransactions. 0 means unlimited.
+	MaxPerPeer int
+
+	// Timeout is how long to wait for the peer to answer, after which the transaction
+	// is abandoned. The abandoned transactions are collected every Timeout, so they may
+	// be outstanding for twice as long at most.
+	Timeout time.Duration
+
+	// Profiles is the ResponseProfile of the requests by message type, which is used
+	// instead of Timeout, e.g., the longer one for Create Session Request that may be
+	// relayed to P-GW, and the shorter one for Echo Request.
+	Profiles map[uint8]ResponseProfile
+}
+
+// ResponseProfile is how long to wait for the response to the requests of a message
+// type, which is the pair of T3-RESPONSE and N3-REQUESTS in TS 29.274 7.6.
+type ResponseProfile struct {
+	// Timeout is how long to wait for the response before retransmitting the request.
+	// The profile is ignored if it is not positive.
+	Timeout time.Duration
+	// Retries is the maximum number of the retransmissions by Exchange.
+	Retries int
+}
+
+// lifetime is how long the transaction is outstanding after the request is sent first,
+// i.e., until the Timeout after the last retransmission.
+func (p ResponseProfile) lifetime() time.Duration {
+	return p.Timeout * time.Duration(max(p.Retries, 0)+1)
+}
+
+// TransactionStats is the statistics of the transactions initiated on a Conn.
+type TransactionStats struct {
+	// Outstanding is the number of the transactions currently waiting for the answer.
+	Outstanding int
+	// Answered is the number of the transactions answered by the peer.
+	Answered uint64
+	// Abandoned is the number of the transactions not answered within the Timeout.
+	Abandoned uint64
+	// Rejected is the number of the requests not sent as MaxPerPeer is exceeded.
+	Rejected uint64
+}
+
+// SetTransactionLimits sets the limits of the transactions. The transactions already
+// outstanding are kept, and MaxPerPeer, Timeout and Profiles are applied to them
+// afterwards.
+func (c *Conn) SetTransactionLimits(limits TransactionLimits) {
+	limits.Profiles = maps.Clone(limits.Profiles)
+
+	c.txs.mu.Lock()
+	defer c.txs.mu.Unlock()
+	c.txs.limits = limits
+}
+
+// ResponseProfile returns the ResponseProfile of the requests of msgType set in
+// TransactionLimits, or false if not set.
+func (c *Conn) ResponseProfile(msgType uint8) (ResponseProfile, bool) {
+	c.txs.mu.Lock()
+	defer c.txs.mu.Unlock()
+	return c.txs.profile(msgType)
+}
+
+type txKey struct {
+	peer string
+	seq  uint32
+}
+
+// pendingTx is the outstanding transaction with the request last sent.
+type pendingTx struct {
+	peer  net.Addr
+	msg   messages.Message
+	first time.Time
+	sent  time.Time
+}
+
+// transactions is the table of the outstanding transactions.
+type transactions struct {
+	mu      sync.Mutex
+	limits  TransactionLimits
+	pending map[txKey]*pendingTx
+	perPeer map[string]int
+	gcOnce  sync.Once
+
+	answered, abandoned, rejected atomic.Uint64
+}
+
+func (t *transactions) timeout() time.Duration {
+	if t.limits.Timeout <= 0 {
+		return DefaultTransactionTimeout
+	}
+	return t.limits.Timeout
+}
+
+// profile returns the ResponseProfile of msgType. The caller must hold mu.
+func (t *transactions) profile(msgType uint8) (ResponseProfile, bool) {
+	p, ok := t.limits.Profiles[msgType]
+	if !ok || p.Timeout <= 0 {
+		return ResponseProfile{}, false
+	}
+	return p, true
+}
+
+// interval returns how often to collect the expired transactions, which is the shortest
+// one of Timeout and the ones in the Profiles. The caller must hold mu.
+func (t *transactions) interval() time.Duration {
+	interval := t.timeout()
+	for _, p := range t.limits.Profiles {
+		if p.Timeout > 0 {
+			interval = min(interval, p.Timeout)
+		}
+	}
+	return interval
+}
+
+// expired reports whether tx is expired at now. The caller must hold mu.
+func (t *transactions) expired(tx *pendingTx, now time.Time) bool {
+	if p, ok := t.profile(tx.msg.MessageType()); ok {
+		return now.Sub(tx.first) >= p.lifetime() && now.Sub(tx.sent) >= p.Timeout
+	}
+	return now.Sub(tx.sent) >= t.timeout()
+}
+
+// begin adds the transaction initiated by the request msg sent to peer. The retransmission
+// of the outstanding one extends its lifetime.
+func (t *transactions) begin(c *Conn, peer net.Addr, msg messages.Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := txKey{peer.String(), msg.Sequence()}
+	if t.pending == nil {
+		t.pending = map[txKey]*pendingTx{}
+		t.perPeer = map[string]int{}
+	}
+	if tx, ok := t.pending[key]; ok {
+		tx.msg, tx.sent = msg, time.Now()
+		return nil
+	}
+	if t.limits.MaxPerPeer > 0 && t.perPeer[key.peer] >= t.limits.MaxPerPeer {
+		t.rejected.Add(1)
+		return ErrTooManyTransactions
+	}
+
+	now := time.Now()
+	t.pending[key] = &pendingTx{peer: peer, msg: msg, first: now, sent: now}
+	t.perPeer[key.peer]++
+	t.gcOnce.Do(func() { go t.gc(c) })
+	return nil
+}
+
+// end removes the transaction answered by msg from peer, if any.
+//
+// The requests started by the peer never end the transaction even if they happen to have
+// the same sequence number, except for Context Response, which is the answer to Context
+// Request as well as the one to be acknowledged.
+func (t *transactions) end(peer net.Addr, msg messages.Message) {
+	msgType := msg.MessageType()
+	if messages.ExpectsResponse(msgType) && msgType != messages.MsgTypeContextResponse {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := txKey{peer.String(), msg.Sequence()}
+	if _, ok := t.pending[key]; !ok {
+		return
+	}
+	t.remove(key)
+	t.answered.Add(1)
+}
+
+// cancel removes the transaction of the request failed to be sent.
+func (t *transactions) cancel(peer net.Addr, seq uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := txKey{peer.String(), seq}
+	if _, ok := t.pending[key]; ok {
+		t.remove(key)
+	}
+}
+
+// remove removes the transaction. The caller must hold mu.
+func (t *transactions) remove(key txKey) {
+	delete(t.pending, key)
+	if t.perPeer[key.peer]--; t.perPeer[key.peer] <= 0 {
+		delete(t.perPeer, key.peer)
+	}
+}
+
+// gc abandons the expired transactions periodically until the Conn is closed.
+func (t *transactions) gc(c *Conn) {
+	for {
+		t.mu.Lock()
+		interval := t.interval()
+		t.mu.Unlock()
+
+		select {
+		case <-c.closed():
+			return
+		case now := <-time.After(interval):
+			for _, tx := range t.expire(now) {
+				c.notifyUnanswered(tx.peer, tx.msg)
+			}
+		}
+	}
+}
+
+// expire abandons the transactions expired at now, and returns them.
+func (t *transactions) expire(now time.Time) []*pendingTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var expired []*pendingTx
+	for key, tx := range t.pending {
+		if t.expired(tx, now) {
+			t.remove(key)
+			t.abandoned.Add(1)
+			expired = append(expired, tx)
+		}
+	}
+	return expired
+}
+
+// reset abandons all the outstanding transactions.
+func (t *transactions) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.abandoned.Add(uint64(len(t.pending)))
+	clear(t.pending)
+	clear(t.perPeer)
+}
+
+func (t *transactions) stats() TransactionStats {
+	t.mu.Lock()
+	outstanding := len(t.pending)
+	t.mu.Unlock()
+
+	return TransactionStats{
+		Outstanding: outstanding,
+		Answered:    t.answered.Load(),
+		Abandoned:   t.abandoned.Load(),
+		Rejected:    t.rejected.Load(),
+	}
+}
+
+// beginTransaction adds the transaction if msg is the request sent to addr.
+func (c *Conn) beginTransaction(addr net.Addr, msg messages.Message) error {
+	if !messages.ExpectsResponse(msg.MessageType()) {
+		return nil
+	}
+	return c.txs.begin(c, addr, msg)
+}
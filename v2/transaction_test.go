@@ -0,0 +1,220 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestTransactionLimits(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.27:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetTransactionLimits(v2.TransactionLimits{MaxPerPeer: 2, Timeout: 100 * time.Millisecond})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.28:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	send := func(seq uint32) error {
+		return srvConn.SendMessageTo(messages.NewEchoRequest(seq, ies.NewRecovery(0)), peer.LocalAddr())
+	}
+	for _, seq := range []uint32{1, 2, 1} {
+		if err := send(seq); err != nil {
+			t.Fatalf("seq %d: %v", seq, err)
+		}
+	}
+	if err := send(3); !errors.Is(err, v2.ErrTooManyTransactions) {
+		t.Errorf("got %v, want ErrTooManyTransactions", err)
+	}
+
+	b, err := messages.NewEchoResponse(1, ies.NewRecovery(0)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	want := v2.TransactionStats{Outstanding: 0, Answered: 1, Abandoned: 1, Rejected: 1}
+	var got v2.TransactionStats
+	for range 100 {
+		if got = srvConn.Stats().Transactions; got == want {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestWaitMessageOnRemovedSession(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.29:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	sess := v2.NewSession(srvAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	srvConn.AddSession(sess)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := sess.WaitMessage(10 * time.Second)
+		errCh <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	srvConn.RemoveSession(sess)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, v2.ErrSessionRemoved) {
+			t.Errorf("got %v, want ErrSessionRemoved", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitMessage not woken up on RemoveSession")
+	}
+
+	// the session can be added and waited again.
+	srvConn.AddSession(sess)
+	if _, err := sess.WaitMessage(10 * time.Millisecond); !errors.Is(err, v2.ErrTimeout) {
+		t.Errorf("got %v, want ErrTimeout", err)
+	}
+}
+
+func TestUnansweredHandler(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.52:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetTransactionLimits(v2.TransactionLimits{Timeout: 100 * time.Millisecond})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.53:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS5S8SGWGTPC, 0x11111111)
+	sess.AddTEID(v2.IFTypeS5S8PGWGTPC, srvConn.NewTEID())
+	srvConn.AddSession(sess)
+
+	type unanswered struct {
+		seq  uint32
+		sess *v2.Session
+	}
+	unansweredCh := make(chan unanswered, 2)
+	srvConn.SetUnansweredHandler(func(c *v2.Conn, peer net.Addr, msg messages.Message, sess *v2.Session) {
+		unansweredCh <- unanswered{msg.Sequence(), sess}
+	})
+
+	// answered one is not notified.
+	for _, seq := range []uint32{1, 2} {
+		cbReq := messages.NewCreateBearerRequest(0x11111111, seq, ies.NewEPSBearerID(5))
+		if err := srvConn.SendMessageTo(cbReq, peer.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := messages.NewCreateBearerResponse(0, 1, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-unansweredCh:
+		if got.seq != 2 {
+			t.Errorf("got sequence %d, want 2", got.seq)
+		}
+		if got.sess != sess {
+			t.Errorf("got Session %v, want %v", got.sess, sess)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unanswered request not notified")
+	}
+	select {
+	case got := <-unansweredCh:
+		t.Errorf("got unexpected notification of sequence %d", got.seq)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestTransactionNotEndedByPeerRequest(t *testing.T) {
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.63:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetTransactionLimits(v2.TransactionLimits{Timeout: 100 * time.Millisecond})
+
+	peer, err := net.ListenPacket("udp", "127.0.0.64:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	unansweredCh := make(chan uint32, 1)
+	srvConn.SetUnansweredHandler(func(c *v2.Conn, peer net.Addr, msg messages.Message, sess *v2.Session) {
+		unansweredCh <- msg.Sequence()
+	})
+
+	cbReq := messages.NewCreateBearerRequest(0x11111111, 1, ies.NewEPSBearerID(5))
+	if err := srvConn.SendMessageTo(cbReq, peer.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the request started by the peer with the same sequence number.
+	b, err := messages.NewEchoRequest(1, ies.NewRecovery(0)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case seq := <-unansweredCh:
+		if seq != 1 {
+			t.Errorf("got sequence %d, want 1", seq)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unanswered request not notified")
+	}
+	if got := srvConn.Stats().Transactions; got.Answered != 0 || got.Abandoned != 1 {
+		t.Errorf("got %+v, want no Answered and 1 Abandoned", got)
+	}
+}
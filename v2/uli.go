@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// ULIChange is the set of the identities changed in User Location Information, with
+// the same bits as the flags of the IE, e.g., ies.ULIFlagTAI.
+type ULIChange uint8
+
+// Has reports whether the identity of the flag, e.g., ies.ULIFlagECGI, is changed.
+func (c ULIChange) Has(flag uint8) bool {
+	return uint8(c)&flag != 0
+}
+
+var uliNames = []string{"CGI", "SAI", "RAI", "TAI", "ECGI", "LAI", "MeNBI", "EMeNBI"}
+
+// String returns the names of the identities changed joined with "|".
+func (c ULIChange) String() string {
+	var names []string
+	for i, name := range uliNames {
+		if c.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "None"
+	}
+	return strings.Join(names, "|")
+}
+
+// ULI returns the last known User Location Information of the Session updated with
+// UpdateULI, or nil if not known yet.
+func (s *Session) ULI() *ies.UserLocationInformationPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.uli
+}
+
+// ChangedSince returns the identities in the UserLocationInformation IE uli changed from
+// the last known one, without updating it. The identity is changed if it is present in
+// either of them and the PLMN or the value differs, so the ones added or removed are also
+// reported. All the identities present in uli are reported if no ULI is known yet.
+//
+// This helps to decide whether to send Change Notification, or to report the location
+// change for charging, e.g., only when TAI or ECGI is changed.
+func (s *Session) ChangedSince(uli *ies.IE) (ULIChange, error) {
+	p, err := ies.DecodeUserLocationInformationPayload(uli.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return diffULI(s.uli, p), nil
+}
+
+// UpdateULI updates the last known User Location Information of the Session with uli,
+// and returns the identities changed as ChangedSince does. The Location of the Subscriber
+// is also updated with the identities present.
+func (s *Session) UpdateULI(uli *ies.IE) (ULIChange, error) {
+	p, err := ies.DecodeUserLocationInformationPayload(uli.Payload)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := diffULI(s.uli, p)
+	s.uli = p
+
+	if s.Subscriber != nil {
+		if s.Location == nil {
+			s.Location = &Location{}
+		}
+		updateLocation(s.Location, p)
+	}
+	return changed, nil
+}
+
+func diffULI(old, cur *ies.UserLocationInformationPayload) ULIChange {
+	if old == nil {
+		return ULIChange(cur.Flags)
+	}
+
+	// the identities present in only one of them are changed.
+	changed := old.Flags ^ cur.Flags
+	samePLMN := old.MCC == cur.MCC && old.MNC == cur.MNC
+	for flag := ies.ULIFlagCGI; flag != 0; flag <<= 1 {
+		if !old.Has(flag) || !cur.Has(flag) {
+			continue
+		}
+		if !samePLMN || !sameULIValue(flag, old, cur) {
+			changed |= flag
+		}
+	}
+	return ULIChange(changed)
+}
+
+func sameULIValue(flag uint8, a, b *ies.UserLocationInformationPayload) bool {
+	switch flag {
+	case ies.ULIFlagCGI:
+		return a.LAC == b.LAC && a.CI == b.CI
+	case ies.ULIFlagSAI:
+		return a.LAC == b.LAC && a.SAC == b.SAC
+	case ies.ULIFlagRAI:
+		return a.LAC == b.LAC && a.RAC == b.RAC
+	case ies.ULIFlagTAI:
+		return a.TAC == b.TAC
+	case ies.ULIFlagECGI:
+		return a.ECI == b.ECI
+	case ies.ULIFlagLAI:
+		return a.LAC == b.LAC
+	case ies.ULIFlagMeNBI:
+		return a.MeNBI == b.MeNBI
+	case ies.ULIFlagEMeNBI:
+		return a.EMeNBI == b.EMeNBI
+	default:
+		return true
+	}
+}
+
+func updateLocation(l *Location, p *ies.UserLocationInformationPayload) {
+	l.MCC, l.MNC = p.MCC, p.MNC
+	if p.Has(ies.ULIFlagCGI) || p.Has(ies.ULIFlagSAI) || p.Has(ies.ULIFlagRAI) || p.Has(ies.ULIFlagLAI) {
+		l.LAC = p.LAC
+	}
+	if p.Has(ies.ULIFlagCGI) {
+		l.CI = p.CI
+	}
+	if p.Has(ies.ULIFlagSAI) {
+		l.SAI = p.SAC
+	}
+	if p.Has(ies.ULIFlagRAI) {
+		l.RAI = p.RAC
+	}
+	if p.Has(ies.ULIFlagTAI) {
+		l.TAI = p.TAC
+	}
+	if p.Has(ies.ULIFlagECGI) {
+		l.ECI = p.ECI
+	}
+	if p.Has(ies.ULIFlagMeNBI) {
+		l.MeNBI = p.MeNBI
+	}
+	if p.Has(ies.ULIFlagEMeNBI) {
+		l.EMeNBI = p.EMeNBI
+	}
+}
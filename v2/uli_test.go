@@ -0,0 +1,69 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestULIChange(t *testing.T) {
+	sess := v2.NewSession(nil, &v2.Subscriber{IMSI: "123451234567890"})
+
+	uli := func(mcc string, tac, eci int) *ies.IE {
+		return ies.NewUserLocationInformationLazy(mcc, "45", -1, -1, -1, -1, tac, eci, -1, -1)
+	}
+
+	changed, err := sess.UpdateULI(uli("123", 0x1111, 0x0aaaaaa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := changed.String(), "TAI|ECGI"; got != want {
+		t.Errorf("got %s on the first update, want %s", got, want)
+	}
+	if got := sess.Location; got == nil || got.TAI != 0x1111 || got.ECI != 0x0aaaaaa || got.MCC != "123" {
+		t.Errorf("Location not updated: %+v", got)
+	}
+
+	cases := []struct {
+		description string
+		uli         *ies.IE
+		want        string
+	}{
+		{"same", uli("123", 0x1111, 0x0aaaaaa), "None"},
+		{"ECGI changed", uli("123", 0x1111, 0x0bbbbbb), "ECGI"},
+		{"ECGI removed", uli("123", 0x1111, -1), "ECGI"},
+		{"CGI and LAI added, TAI changed", ies.NewUserLocationInformationLazy("123", "45", 1, 2, -1, -1, 0x2222, 0x0aaaaaa, -1, -1), "CGI|TAI|LAI"},
+		{"PLMN changed", uli("124", 0x1111, 0x0aaaaaa), "TAI|ECGI"},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := sess.ChangedSince(c.uli)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+
+	// ChangedSince does not update the last known ULI.
+	if got := sess.ULI(); got.ECI != 0x0aaaaaa {
+		t.Errorf("ULI updated by ChangedSince: %+v", got)
+	}
+	if _, err := sess.ChangedSince(ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 1, -1, -1, -1)); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = sess.UpdateULI(uli("123", 0x1111, 0x0bbbbbb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed.Has(ies.ULIFlagECGI) || changed.Has(ies.ULIFlagTAI) {
+		t.Errorf("got %s, want ECGI", changed)
+	}
+}
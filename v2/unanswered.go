@@ -0,0 +1,48 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// UnansweredHandlerFunc is a handler called with the request sent to peer on the Conn which
+// is not answered within the Timeout of TransactionLimits after it is sent last, i.e., the
+// one timed out after all the retransmissions, e.g., Create Bearer Request sent by P-GW.
+//
+// sess is the Session on the Conn with the TEID in the header of msg, or nil if not found,
+// so that the cleanup of the procedure can be centralized, e.g., rolling back the bearers
+// to be created.
+type UnansweredHandlerFunc func(c *Conn, peer net.Addr, msg messages.Message, sess *Session)
+
+// SetUnansweredHandler sets the handler called with the requests not answered by the peer.
+// Calling it again replaces the current one, and nil removes it.
+//
+// The handler is called one by one in the goroutine collecting the transactions expired,
+// so it should return quickly. It is not called for the ones outstanding when the Conn
+// is closed.
+func (c *Conn) SetUnansweredHandler(fn UnansweredHandlerFunc) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+	c.unansweredHandler = fn
+}
+
+// notifyUnanswered calls the UnansweredHandlerFunc with the request msg not answered.
+func (c *Conn) notifyUnanswered(peer net.Addr, msg messages.Message) {
+	c.sessMu.RLock()
+	fn := c.unansweredHandler
+	c.sessMu.RUnlock()
+	if fn == nil {
+		return
+	}
+
+	var sess *Session
+	if teid := msg.TEID(); teid != 0 {
+		sess, _ = c.GetSessionByTEID(teid)
+	}
+	fn(c, peer, msg, sess)
+}
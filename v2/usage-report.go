@@ -0,0 +1,78 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// UsageReportHandlerFunc is a handler called with the Secondary RAT usage reports
+// accumulated on the session, when the session is removed from Conn by RemoveSession.
+//
+// The reports are accumulated from Delete Session Request and Delete Bearer Response
+// received on the Conn, in the order of arrival. The handler is not called if the
+// session has no reports, and the same reports are not given twice.
+type UsageReportHandlerFunc func(c *Conn, sess *Session, reports []*ies.SecondaryRATUsageDataReportPayload)
+
+// SetUsageReportHandler sets the handler called with the Secondary RAT usage reports
+// at the teardown of the sessions. Calling it again replaces the current one, and nil
+// removes it.
+func (c *Conn) SetUsageReportHandler(fn UsageReportHandlerFunc) {
+	c.sessMu.Lock()
+	defer c.sessMu.Unlock()
+	c.usageReportHandler = fn
+}
+
+// SecondaryRATUsageDataReports returns all the Secondary RAT Usage Data Report IEs
+// in Delete Session Request or Delete Bearer Response, including the ones kept in
+// AdditionalIEs. It returns nil for the other messages.
+//
+// This is useful to relay the reports as they are, e.g., from MME to P-GW by S-GW.
+func SecondaryRATUsageDataReports(msg messages.Message) []*ies.IE {
+	var first *ies.IE
+	var additional []*ies.IE
+	switch m := msg.(type) {
+	case *messages.DeleteSessionRequest:
+		first, additional = m.SecondaryRATUsageDataReport, m.AdditionalIEs
+	case *messages.DeleteBearerResponse:
+		first, additional = m.SecondaryRATUsageDataReport, m.AdditionalIEs
+	default:
+		return nil
+	}
+	if first == nil {
+		return nil
+	}
+
+	reports := []*ies.IE{first}
+	for _, i := range additional {
+		if i != nil && i.Type == ies.SecondaryRATUsageDataReport {
+			reports = append(reports, i)
+		}
+	}
+	return reports
+}
+
+// accumulateUsageReports adds the reports in msg to the session of the TEID in it.
+func (c *Conn) accumulateUsageReports(msg messages.Message) {
+	reports := SecondaryRATUsageDataReports(msg)
+	if len(reports) == 0 {
+		return
+	}
+	sess, err := c.GetSessionByTEID(msg.TEID())
+	if err != nil {
+		return
+	}
+	sess.AddSecondaryRATUsageReports(reports...)
+}
+
+func (c *Conn) notifyUsageReports(fn UsageReportHandlerFunc, sess *Session) {
+	if fn == nil {
+		return
+	}
+	if reports := sess.takeSecondaryRATUsageReports(); len(reports) != 0 {
+		fn(c, sess, reports)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func newUsageReport(ebi uint8, dl, ul uint64) *ies.SecondaryRATUsageDataReportPayload {
+	return &ies.SecondaryRATUsageDataReportPayload{
+		IRPGW:     true,
+		RATType:   v2.SecondaryRATTypeNR,
+		EBI:       ebi,
+		StartTime: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2019, time.January, 1, 0, 1, 0, 0, time.UTC),
+		UsageDL:   dl,
+		UsageUL:   ul,
+	}
+}
+
+func TestSecondaryRATUsageDataReports(t *testing.T) {
+	reports := []*ies.SecondaryRATUsageDataReportPayload{
+		newUsageReport(5, 1000, 500),
+		newUsageReport(6, 2000, 600),
+	}
+	dsr := messages.NewDeleteSessionRequest(
+		0x11111111, 0,
+		ies.NewEPSBearerID(5),
+		ies.NewSecondaryRATUsageDataReport(reports[0]),
+		ies.NewSecondaryRATUsageDataReport(reports[1]),
+	)
+
+	b, err := dsr.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := messages.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*ies.SecondaryRATUsageDataReportPayload
+	for _, ie := range v2.SecondaryRATUsageDataReports(decoded) {
+		got = append(got, ie.SecondaryRATUsageDataReport())
+	}
+	if diff := cmp.Diff(got, reports); diff != "" {
+		t.Error(diff)
+	}
+
+	if got := v2.SecondaryRATUsageDataReports(messages.NewEchoRequest(0)); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+}
+
+func TestUsageReportHandler(t *testing.T) {
+	cliAddr, err := net.ResolveUDPAddr("udp", "127.0.0.3:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvAddr, err := net.ResolveUDPAddr("udp", "127.0.0.4:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	srvConn, err := v2.ListenAndServe(srvAddr, 0, make(chan error))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	sess := v2.NewSession(cliAddr, &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS5S8PGWGTPC, 0x11111111)
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	srvConn.AddSession(sess)
+
+	reportCh := make(chan []*ies.SecondaryRATUsageDataReportPayload, 1)
+	srvConn.SetUsageReportHandler(func(c *v2.Conn, s *v2.Session, reports []*ies.SecondaryRATUsageDataReportPayload) {
+		reportCh <- reports
+	})
+	srvConn.AddHandler(
+		messages.MsgTypeDeleteSessionRequest,
+		func(c *v2.Conn, cliAddr net.Addr, msg messages.Message) error {
+			s, err := c.GetSessionByTEID(msg.TEID())
+			if err != nil {
+				return err
+			}
+			c.RemoveSession(s)
+			return nil
+		},
+	)
+
+	want := []*ies.SecondaryRATUsageDataReportPayload{
+		newUsageReport(5, 1000, 500),
+		newUsageReport(5, 3000, 700),
+	}
+	dsr := messages.NewDeleteSessionRequest(
+		0x11111111, 1,
+		ies.NewSecondaryRATUsageDataReport(want[0]),
+		ies.NewSecondaryRATUsageDataReport(want[1]),
+	)
+	if err := cliConn.SendMessageTo(dsr, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-reportCh:
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for the usage reports")
+	}
+	if got := sess.SecondaryRATUsageReports(); len(got) != 0 {
+		t.Errorf("reports are left after the teardown: %v", got)
+	}
+}
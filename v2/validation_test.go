@@ -0,0 +1,88 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestPeerValidation(t *testing.T) {
+	var addrs []*net.UDPAddr
+	for _, s := range []string{"127.0.0.15:2123", "127.0.0.16:2123", "127.0.0.17:2123"} {
+		a, err := net.ResolveUDPAddr("udp", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, a)
+	}
+
+	var conns []*v2.Conn
+	for _, a := range addrs {
+		c, err := v2.ListenAndServe(a, 0, make(chan error))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		conns = append(conns, c)
+	}
+	srvConn, peerConn, otherConn := conns[0], conns[1], conns[2]
+
+	sess := v2.NewSession(addrs[1], &v2.Subscriber{IMSI: "123451234567890"})
+	teid := srvConn.NewTEID()
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
+	srvConn.AddSession(sess)
+	srvConn.EnablePeerValidation()
+
+	rcvCh := make(chan net.Addr, 2)
+	srvConn.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *v2.Conn, addr net.Addr, msg messages.Message) error {
+		rcvCh <- addr
+		return nil
+	})
+
+	if err := otherConn.SendMessageTo(messages.NewModifyBearerRequest(teid, 1), addrs[0]); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case addr := <-rcvCh:
+		t.Fatalf("message from %s not discarded", addr)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := peerConn.SendMessageTo(messages.NewModifyBearerRequest(teid, 2), addrs[0]); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case addr := <-rcvCh:
+		if addr.String() != addrs[1].String() {
+			t.Errorf("got message from %s", addr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message from the peer discarded")
+	}
+
+	// accepted from anyone if disabled.
+	srvConn.DisablePeerValidation()
+	if err := otherConn.SendMessageTo(messages.NewModifyBearerRequest(teid, 3), addrs[0]); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-rcvCh:
+	case <-time.After(time.Second):
+		t.Fatal("message discarded with peer validation disabled")
+	}
+}
+
+func TestNewSessionSequence(t *testing.T) {
+	for range 100 {
+		if seq := v2.NewSession(nil, &v2.Subscriber{}).Sequence; seq > 0xffffff {
+			t.Fatalf("sequence number exceeds 24 bits: %#x", seq)
+		}
+	}
+}